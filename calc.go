@@ -14,6 +14,7 @@ package excelize
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -99,7 +100,9 @@ type formulaArg struct {
 }
 
 // formulaFuncs is the type of the formula functions.
-type formulaFuncs struct{}
+type formulaFuncs struct {
+	f *File
+}
 
 // tokenPriority defined basic arithmetic operator priority.
 var tokenPriority = map[string]int{
@@ -118,7 +121,10 @@ var tokenPriority = map[string]int{
 
 // CalcCellValue provides a function to get calculated cell value. This
 // feature is currently in working processing. Array formula, table formula
-// and some other formulas are not supported currently.
+// and some other formulas are not supported currently. The structured
+// references of the tables such as Sales[Amount], Sales[[#Headers],[Amount]],
+// Sales[#Totals] and [@Amount] will be resolved by the current definitions of
+// the tables.
 //
 // Supported formulas:
 //
@@ -133,19 +139,41 @@ var tokenPriority = map[string]int{
 //    SUM, SUMIF, SUMSQ, TAN, TANH, TRUNC
 //
 func (f *File) CalcCellValue(sheet, cell string) (result string, err error) {
+	return f.CalcCellValueContext(context.Background(), sheet, cell)
+}
+
+// CalcCellValueContext provides a function to get calculated cell value like
+// CalcCellValue, the calculation will be stopped and the error of the context
+// will be returned if the context is canceled or its deadline is exceeded.
+// For example, calculate the cell value with a timeout:
+//
+//    ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//    defer cancel()
+//    result, err := f.CalcCellValueContext(ctx, "Sheet1", "A1")
+//
+func (f *File) CalcCellValueContext(ctx context.Context, sheet, cell string) (result string, err error) {
 	var (
 		formula string
 		token   efp.Token
 	)
+	if err = ctx.Err(); err != nil {
+		return
+	}
 	if formula, err = f.GetCellFormula(sheet, cell); err != nil {
 		return
 	}
+	resolved, err := f.resolveStructuredReferences(sheet, cell, formula)
+	if err != nil {
+		err = ErrFormula{Sheet: sheet, Cell: cell, Formula: formula, Err: err}
+		return
+	}
 	ps := efp.ExcelParser()
-	tokens := ps.Parse(formula)
+	tokens := ps.Parse(resolved)
 	if tokens == nil {
 		return
 	}
-	if token, err = f.evalInfixExp(sheet, tokens); err != nil {
+	if token, err = f.evalInfixExp(ctx, sheet, tokens); err != nil {
+		err = ErrFormula{Sheet: sheet, Cell: cell, Formula: formula, Err: err}
 		return
 	}
 	result = token.TValue
@@ -187,16 +215,19 @@ func getPriority(token efp.Token) (pri int) {
 //
 // TODO: handle subtypes: Nothing, Text, Logical, Error, Concatenation, Intersection, Union
 //
-func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error) {
+func (f *File) evalInfixExp(ctx context.Context, sheet string, tokens []efp.Token) (efp.Token, error) {
 	var err error
 	opdStack, optStack, opfStack, opfdStack, opftStack := NewStack(), NewStack(), NewStack(), NewStack(), NewStack()
 	argsList := list.New()
 	for i := 0; i < len(tokens); i++ {
+		if err = ctx.Err(); err != nil {
+			return efp.Token{}, err
+		}
 		token := tokens[i]
 
 		// out of function stack
 		if opfStack.Len() == 0 {
-			if err = f.parseToken(sheet, token, opdStack, optStack); err != nil {
+			if err = f.parseToken(ctx, sheet, token, opdStack, optStack); err != nil {
 				return efp.Token{}, err
 			}
 		}
@@ -218,7 +249,14 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 			if token.TSubType == efp.TokenSubTypeRange {
 				if !opftStack.Empty() {
 					// parse reference: must reference at here
-					result, err := f.parseReference(sheet, token.TValue)
+					refs, err := f.getReferences(token.TValue, sheet)
+					if err != nil {
+						return efp.Token{TValue: formulaErrorREF}, err
+					}
+					if len(refs) != 1 {
+						return efp.Token{}, errors.New(formulaErrorVALUE)
+					}
+					result, err := f.parseReference(ctx, sheet, refs[0])
 					if err != nil {
 						return efp.Token{TValue: formulaErrorNAME}, err
 					}
@@ -233,21 +271,27 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 					continue
 				}
 				if nextToken.TType == efp.TokenTypeArgument || nextToken.TType == efp.TokenTypeFunction {
-					// parse reference: reference or range at here
-					result, err := f.parseReference(sheet, token.TValue)
+					// parse reference: reference, range, defined name or 3D reference at here
+					refs, err := f.getReferences(token.TValue, sheet)
 					if err != nil {
-						return efp.Token{TValue: formulaErrorNAME}, err
+						return efp.Token{TValue: formulaErrorREF}, err
 					}
-					if result.Type == ArgUnknown {
-						return efp.Token{}, errors.New(formulaErrorVALUE)
+					for _, ref := range refs {
+						result, err := f.parseReference(ctx, sheet, ref)
+						if err != nil {
+							return efp.Token{TValue: formulaErrorNAME}, err
+						}
+						if result.Type == ArgUnknown {
+							return efp.Token{}, errors.New(formulaErrorVALUE)
+						}
+						argsList.PushBack(result)
 					}
-					argsList.PushBack(result)
 					continue
 				}
 			}
 
 			// check current token is opft
-			if err = f.parseToken(sheet, token, opfdStack, opftStack); err != nil {
+			if err = f.parseToken(ctx, sheet, token, opfdStack, opftStack); err != nil {
 				return efp.Token{}, err
 			}
 
@@ -301,7 +345,7 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 					})
 				}
 				// call formula function to evaluate
-				result, err := callFuncByName(&formulaFuncs{}, strings.NewReplacer(
+				result, err := callFuncByName(&formulaFuncs{f: f}, strings.NewReplacer(
 					"_xlfn", "", ".", "").Replace(opfStack.Peek().(efp.Token).TValue),
 					[]reflect.Value{reflect.ValueOf(argsList)})
 				if err != nil {
@@ -565,29 +609,62 @@ func isOperatorPrefixToken(token efp.Token) bool {
 	return false
 }
 
-func (f *File) getDefinedNameRefTo(definedNameName string, currentSheet string) (refTo string) {
-	for _, definedName := range f.GetDefinedName() {
-		if definedName.Name == definedNameName {
-			refTo = definedName.RefersTo
-			// worksheet scope takes precedence over scope workbook when both definedNames exist
-			if definedName.Scope == currentSheet {
-				break
+// getReferences resolve the defined name or the 3D reference to the
+// references which can be parsed by the parseReference, the worksheet scoped
+// name takes precedence over the workbook scoped name, and the 3D reference
+// will be expanded to the references on each worksheet of the span in the
+// workbook order. The given token will be returned if it's neither of them.
+func (f *File) getReferences(token, currentSheet string) ([]string, error) {
+	if idx := strings.Index(token, "!"); idx != -1 && idx == strings.LastIndex(token, "!") && strings.Contains(token[:idx], ":") {
+		firstSheet, lastSheet, cells, err := Split3DReference(token)
+		if err != nil {
+			return nil, errors.New(formulaErrorREF)
+		}
+		first, last, sheets := -1, -1, f.GetSheetList()
+		for i, name := range sheets {
+			if strings.EqualFold(name, firstSheet) {
+				first = i
+			}
+			if strings.EqualFold(name, lastSheet) {
+				last = i
 			}
 		}
+		if first == -1 || last == -1 {
+			return nil, errors.New(formulaErrorREF)
+		}
+		if first > last {
+			first, last = last, first
+		}
+		var refs []string
+		for _, name := range sheets[first : last+1] {
+			refs = append(refs, name+"!"+cells)
+		}
+		return refs, nil
+	}
+	ranges, err := f.ResolveDefinedName(token, currentSheet)
+	if err != nil {
+		return []string{token}, nil
+	}
+	refs := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		refs = append(refs, r.Sheet+"!"+r.Range)
 	}
-	return refTo
+	return refs, nil
 }
 
 // parseToken parse basic arithmetic operator priority and evaluate based on
 // operators and operands.
-func (f *File) parseToken(sheet string, token efp.Token, opdStack, optStack *Stack) error {
+func (f *File) parseToken(ctx context.Context, sheet string, token efp.Token, opdStack, optStack *Stack) error {
 	// parse reference: must reference at here
 	if token.TSubType == efp.TokenSubTypeRange {
-		refTo := f.getDefinedNameRefTo(token.TValue, sheet)
-		if refTo != "" {
-			token.TValue = refTo
+		refs, err := f.getReferences(token.TValue, sheet)
+		if err != nil {
+			return err
 		}
-		result, err := f.parseReference(sheet, token.TValue)
+		if len(refs) != 1 {
+			return errors.New(formulaErrorVALUE)
+		}
+		result, err := f.parseReference(ctx, sheet, refs[0])
 		if err != nil {
 			return errors.New(formulaErrorNAME)
 		}
@@ -625,7 +702,7 @@ func (f *File) parseToken(sheet string, token efp.Token, opdStack, optStack *Sta
 
 // parseReference parse reference and extract values by given reference
 // characters and default sheet name.
-func (f *File) parseReference(sheet, reference string) (arg formulaArg, err error) {
+func (f *File) parseReference(ctx context.Context, sheet, reference string) (arg formulaArg, err error) {
 	reference = strings.Replace(reference, "$", "", -1)
 	refs, cellRanges, cellRefs := list.New(), list.New(), list.New()
 	for _, ref := range strings.Split(reference, ":") {
@@ -664,7 +741,7 @@ func (f *File) parseReference(sheet, reference string) (arg formulaArg, err erro
 		cellRefs.PushBack(e.Value.(cellRef))
 		refs.Remove(e)
 	}
-	arg, err = f.rangeResolver(cellRefs, cellRanges)
+	arg, err = f.rangeResolver(ctx, cellRefs, cellRanges)
 	return
 }
 
@@ -703,7 +780,7 @@ func prepareValueRef(cr cellRef, valueRange []int) {
 // rangeResolver extract value as string from given reference and range list.
 // This function will not ignore the empty cell. For example, A1:A2:A2:B3 will
 // be reference A1:B3.
-func (f *File) rangeResolver(cellRefs, cellRanges *list.List) (arg formulaArg, err error) {
+func (f *File) rangeResolver(ctx context.Context, cellRefs, cellRanges *list.List) (arg formulaArg, err error) {
 	// value range order: from row, to row, from column, to column
 	valueRange := []int{0, 0, 0, 0}
 	var sheet string
@@ -732,6 +809,9 @@ func (f *File) rangeResolver(cellRefs, cellRanges *list.List) (arg formulaArg, e
 	if cellRanges.Len() > 0 {
 		arg.Type = ArgMatrix
 		for row := valueRange[0]; row <= valueRange[1]; row++ {
+			if err = ctx.Err(); err != nil {
+				return
+			}
 			var matrixRow = []formulaArg{}
 			for col := valueRange[2]; col <= valueRange[3]; col++ {
 				var cell, value string
@@ -3326,7 +3406,8 @@ func (fn *formulaFuncs) OR(argsList *list.List) (result string, err error) {
 
 // Date and Time Functions
 
-// DATE returns a date, from a user-supplied year, month and day.
+// DATE returns a date, from a user-supplied year, month and day. The date
+// before January 1, 1904 is invalid in the 1904 date system.
 func (fn *formulaFuncs) DATE(argsList *list.List) (result string, err error) {
 	if argsList.Len() != 3 {
 		err = errors.New("DATE requires 3 number arguments")
@@ -3346,7 +3427,14 @@ func (fn *formulaFuncs) DATE(argsList *list.List) (result string, err error) {
 		return
 	}
 	d := makeDate(year, time.Month(month), day)
-	result = timeFromExcelTime(daysBetween(excelMinTime1900.Unix(), d)+1, false).String()
+	date, date1904 := daysBetween(excelMinTime1900.Unix(), d)+1, fn.f != nil && fn.f.date1904()
+	if date1904 {
+		if date -= excelDate1904Offset; date < 0 {
+			err = errors.New(formulaErrorNUM)
+			return
+		}
+	}
+	result = timeFromExcelTime(date, date1904).String()
 	return
 }
 
@@ -2,6 +2,8 @@ package excelize
 
 import (
 	"container/list"
+	"context"
+	"fmt"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -904,6 +906,54 @@ func TestCalcCellValueWithDefinedName(t *testing.T) {
 	assert.NoError(t, err)
 	// DefinedName with scope WorkSheet takes precedence over DefinedName with scope Workbook, so we should get B1 value
 	assert.Equal(t, "B1 value", result, "=defined_name1")
+
+	// Test defined name with multiple ranges as function arguments
+	f = NewFile()
+	for r, value := range []int{1, 2, 3, 4} {
+		assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", r+1), value))
+	}
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1:$A$2,Sheet1!$A$4"}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=SUM(Amount)"))
+	result, err = f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "7", result)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=Amount"))
+	_, err = f.CalcCellValue("Sheet1", "B1")
+	assert.EqualError(t, err, formulaErrorVALUE)
+}
+
+func TestCalc3DReference(t *testing.T) {
+	f := NewFile()
+	for i, sheet := range []string{"Sheet1", "Jan 2021", "Feb 2021", "Sheet4"} {
+		if i > 0 {
+			f.NewSheet(sheet)
+		}
+		assert.NoError(t, f.SetCellValue(sheet, "B2", i+1))
+		assert.NoError(t, f.SetCellValue(sheet, "C2", 10))
+	}
+	for formula, expected := range map[string]string{
+		"=SUM(Sheet1:Sheet4!B2)":           "10",
+		"=SUM(Sheet4:Sheet1!B2)":           "10",
+		"=SUM('Jan 2021:Sheet4'!B2:C2)":    "39",
+		"=SUM('Jan 2021:Feb 2021'!B2)+1":   "6",
+		"=SUM(Sheet1:Sheet1!B2,Sheet4!B2)": "5",
+		"=Sheet1:Sheet1!B2":                "1",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A1", formula))
+		result, err := f.CalcCellValue("Sheet1", "A1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for formula, expected := range map[string]string{
+		"=SUM(Sheet1:SheetN!B2)": formulaErrorREF,
+		"=SUM(Sheet1:Sheet4!B)":  formulaErrorREF,
+		"=Sheet1:Sheet4!B2":      formulaErrorVALUE,
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A1", formula))
+		result, err := f.CalcCellValue("Sheet1", "A1")
+		assert.EqualError(t, err, expected, formula)
+		assert.Equal(t, "", result, formula)
+	}
 }
 
 func TestCalcPow(t *testing.T) {
@@ -971,3 +1021,138 @@ func TestDet(t *testing.T) {
 		{4, 5, 6, 7},
 	}), float64(0))
 }
+
+func TestCalcCellValueContext(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]int{1, 2, 3}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "=SUM(A1:C1)+A1"))
+	result, err := f.CalcCellValueContext(context.Background(), "Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "7", result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = f.CalcCellValueContext(ctx, "Sheet1", "D1")
+	assert.EqualError(t, err, context.Canceled.Error())
+	ps := efp.ExcelParser()
+	_, err = f.evalInfixExp(ctx, "Sheet1", ps.Parse("=1+2"))
+	assert.EqualError(t, err, context.Canceled.Error())
+	cellRanges := list.New()
+	cellRanges.PushBack(cellRange{From: cellRef{Col: 1, Row: 1}, To: cellRef{Col: 3, Row: 1}})
+	_, err = f.rangeResolver(ctx, list.New(), cellRanges)
+	assert.EqualError(t, err, context.Canceled.Error())
+}
+
+func TestCalcStructuredReferences(t *testing.T) {
+	f := NewFile()
+	f.SetSheetName("Sheet1", "Sales Data")
+	f.NewSheet("Sheet2")
+	for idx, row := range [][]interface{}{{"Region", "Amount", "Price"}, {"North", 10, 1}, {"South", 20, 2}, {"East", 30, 3}} {
+		assert.NoError(t, f.SetSheetRow("Sales Data", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	assert.NoError(t, f.AddTable("Sales Data", "A1", "C4", `{"table_name":"Sales","totals_row":[{"column":"A","label":"Total"},{"column":"B","function":"sum"}]}`))
+	assert.NoError(t, f.SetSheetRow("Sales Data", "E1", &[]interface{}{"Units"}))
+	for row := 2; row <= 3; row++ {
+		assert.NoError(t, f.SetCellValue("Sales Data", fmt.Sprintf("E%d", row), row-1))
+	}
+	assert.NoError(t, f.AddTable("Sales Data", "E1", "E3", `{"table_name":"Units"}`))
+
+	assert.NoError(t, f.SetCellFormula("Sales Data", "C3", "[@Amount]*2"))
+	assert.NoError(t, f.SetCellFormula("Sales Data", "D3", "Sales[@Amount]*Sales[@[Price]]"))
+	for cell, expected := range map[string]string{"C3": "Sales[[#This Row],[Amount]]*2", "D3": "Sales[[#This Row],[Amount]]*Sales[[#This Row],[Price]]"} {
+		formula, err := f.GetCellFormula("Sales Data", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	for cell, expected := range map[string]string{"C3": "40", "D3": "40"} {
+		result, err := f.CalcCellValue("Sales Data", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+
+	for formula, expected := range map[string]string{
+		"SUM(Sales[Amount])":                   "60",
+		"SUM(sales[amount])+1":                 "61",
+		"SUM(Sales[[#Data],[Amount]:[Price]])": "66",
+		"Sales[[#Headers],[Price]]":            "Price",
+		"Sales[[#Totals],[Region]]":            "Total",
+		"COUNTA(Sales[#All])":                  "13",
+		"COUNTA(Sales[[#Headers],[#Data]])":    "12",
+		"COUNTA(Sales[])":                      "9",
+		"SUM(Units[Units])":                    "3",
+		"ISBLANK(Sales[[#Totals],[Price]])":    "TRUE",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet2", "A1", formula))
+		result, err := f.CalcCellValue("Sheet2", "A1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+
+	// Test structured references are still valid after the table resized.
+	assert.NoError(t, f.SetCellValue("Sales Data", "E4", 3))
+	assert.NoError(t, f.ResizeTable("Units", "E1:E4"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "SUM(Units[Units])"))
+	result, err := f.CalcCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "6", result)
+
+	for formula, expected := range map[string]string{
+		"SUM(Missing[Amount])":        formulaErrorREF,
+		"SUM(Sales[Missing])":         formulaErrorREF,
+		"SUM(Sales[#Unknown])":        formulaErrorREF,
+		"SUM(Sales[[Amount]x])":       formulaErrorREF,
+		"SUM(Sales[[Amount])":         formulaErrorREF,
+		"Sales[[#This Row],[Amount]]": formulaErrorVALUE,
+		"[Amount]":                    formulaErrorREF,
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet2", "A1", formula))
+		_, err := f.CalcCellValue("Sheet2", "A1")
+		assert.EqualError(t, err, expected, formula)
+	}
+	// Test structured references to the headers of the table without header
+	// row and the totals of the table without totals row.
+	headerRowCount := 0
+	table, err := f.tableReader("xl/tables/table2.xml")
+	assert.NoError(t, err)
+	table.HeaderRowCount = &headerRowCount
+	for _, spec := range []string{"#Headers", "#Totals"} {
+		_, err = f.structuredReferenceToArea(table, &structuredReference{items: []string{strings.ToLower(spec)}}, "Sales Data", "Sheet2", "A1")
+		assert.EqualError(t, err, formulaErrorREF, spec)
+	}
+	table.Ref = "E1"
+	_, err = f.structuredReferenceToArea(table, &structuredReference{}, "Sales Data", "Sheet2", "A1")
+	assert.EqualError(t, err, formulaErrorREF)
+	_, err = f.structuredReferenceToArea(&xlsxTable{Ref: "A1:B1048576"}, &structuredReference{items: []string{"#all"}}, "Sales Data", "Sheet2", "A1")
+	assert.NoError(t, err)
+}
+
+func TestScanStructuredReferences(t *testing.T) {
+	var refs []string
+	result, err := scanStructuredReferences(`"a[b]"&'Sheet [1]'!A1&'It''s'!A1&[1]Sheet1!A1&Sales[Cost '[USD']]&[@Amount]`, func(table, spec string) (string, error) {
+		refs = append(refs, table+"|"+spec)
+		return "X", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `"a[b]"&'Sheet [1]'!A1&'It''s'!A1&[1]Sheet1!A1&X&X`, result)
+	assert.Equal(t, []string{"Sales|Cost '[USD']", "|@Amount"}, refs)
+	_, err = scanStructuredReferences(`Sales[Amount`, nil)
+	assert.EqualError(t, err, formulaErrorREF)
+	result, err = scanStructuredReferences(`'Sheet`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `'Sheet`, result)
+
+	ref, err := parseStructuredReference("@")
+	assert.NoError(t, err)
+	assert.Equal(t, &structuredReference{items: []string{"#this row"}}, ref)
+	ref, err = parseStructuredReference("[#Headers], [Cost '[USD']]:[Price]")
+	assert.NoError(t, err)
+	assert.Equal(t, &structuredReference{items: []string{"#headers"}, from: "Cost [USD]", to: "Price"}, ref)
+
+	f := NewFile()
+	assert.Equal(t, "[#This Row]*2", f.normalizeStructuredReferences("Sheet1", "A1", "[@]*2"))
+	assert.Equal(t, "T[#This Row]", f.normalizeStructuredReferences("Sheet1", "A1", "T[@]"))
+	assert.Equal(t, "T[[#This Row],[A]:[B]]", f.normalizeStructuredReferences("Sheet1", "A1", "T[@[A]:[B]]"))
+	assert.Equal(t, "[@A", f.normalizeStructuredReferences("Sheet1", "A1", "[@A"))
+	_, err = f.findTableByCell("Sheet1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
@@ -15,23 +15,20 @@ import (
 	"bytes"
 	"encoding/xml"
 	"io"
-	"log"
 )
 
 // calcChainReader provides a function to get the pointer to the structure
 // after deserialization of xl/calcChain.xml.
-func (f *File) calcChainReader() *xlsxCalcChain {
-	var err error
-
+func (f *File) calcChainReader() (*xlsxCalcChain, error) {
 	if f.CalcChain == nil {
 		f.CalcChain = new(xlsxCalcChain)
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/calcChain.xml")))).
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/calcChain.xml")))).
 			Decode(f.CalcChain); err != nil && err != io.EOF {
-			log.Printf("xml decode error: %s", err)
+			return f.CalcChain, f.newXMLDecodeError("xl/calcChain.xml", err)
 		}
 	}
 
-	return f.CalcChain
+	return f.CalcChain, nil
 }
 
 // calcChainWriter provides a function to save xl/calcChain.xml after
@@ -45,23 +42,28 @@ func (f *File) calcChainWriter() {
 
 // deleteCalcChain provides a function to remove cell reference on the
 // calculation chain.
-func (f *File) deleteCalcChain(index int, axis string) {
-	calc := f.calcChainReader()
-	if calc != nil {
-		calc.C = xlsxCalcChainCollection(calc.C).Filter(func(c xlsxCalcChainC) bool {
-			return !((c.I == index && c.R == axis) || (c.I == index && axis == ""))
-		})
+func (f *File) deleteCalcChain(index int, axis string) error {
+	calc, err := f.calcChainReader()
+	if err != nil {
+		return err
 	}
+	calc.C = xlsxCalcChainCollection(calc.C).Filter(func(c xlsxCalcChainC) bool {
+		return !((c.I == index && c.R == axis) || (c.I == index && axis == ""))
+	})
 	if len(calc.C) == 0 {
 		f.CalcChain = nil
 		delete(f.XLSX, "xl/calcChain.xml")
-		content := f.contentTypesReader()
+		content, err := f.contentTypesReader()
+		if err != nil {
+			return err
+		}
 		for k, v := range content.Overrides {
 			if v.PartName == "/xl/calcChain.xml" {
 				content.Overrides = append(content.Overrides[:k], content.Overrides[k+1:]...)
 			}
 		}
 	}
+	return nil
 }
 
 type xlsxCalcChainCollection []xlsxCalcChainC
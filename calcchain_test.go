@@ -1,12 +1,17 @@
 package excelize
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestCalcChainReader(t *testing.T) {
 	f := NewFile()
 	f.CalcChain = nil
 	f.XLSX["xl/calcChain.xml"] = MacintoshCyrillicCharset
-	f.calcChainReader()
+	_, err := f.calcChainReader()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestDeleteCalcChain(t *testing.T) {
@@ -15,5 +20,11 @@ func TestDeleteCalcChain(t *testing.T) {
 	f.ContentTypes.Overrides = append(f.ContentTypes.Overrides, xlsxOverride{
 		PartName: "/xl/calcChain.xml",
 	})
-	f.deleteCalcChain(1, "A1")
+	assert.NoError(t, f.deleteCalcChain(1, "A1"))
+
+	f.CalcChain = nil
+	f.XLSX["xl/calcChain.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.deleteCalcChain(1, "A1"), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	f.CalcChain = nil
+	assert.EqualError(t, f.SetCellFormula("Sheet1", "A1", ""), "xml decode error: XML syntax error on line 1: invalid UTF-8")
 }
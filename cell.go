@@ -19,6 +19,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -38,7 +40,11 @@ const (
 // along with the raw value of the cell.
 func (f *File) GetCellValue(sheet, axis string) (string, error) {
 	return f.getCellStringFunc(sheet, axis, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
-		val, err := c.getValueFrom(f, f.sharedStringsReader())
+		sst, err := f.sharedStringsReader()
+		if err != nil {
+			return "", true, err
+		}
+		val, err := c.getValueFrom(f, sst)
 		return val, true, err
 	})
 }
@@ -141,8 +147,12 @@ func (f *File) setCellTimeFunc(sheet, axis string, value time.Time) error {
 	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
 
-	var isNum bool
-	cellData.T, cellData.V, isNum, err = setCellTime(value)
+	var (
+		isNum bool
+		t, v  string
+	)
+	t, v, isNum, err = setCellTime(value, f.date1904())
+	cellData.setValue(t, v)
 	if err != nil {
 		return err
 	}
@@ -155,9 +165,9 @@ func (f *File) setCellTimeFunc(sheet, axis string, value time.Time) error {
 	return err
 }
 
-func setCellTime(value time.Time) (t string, b string, isNum bool, err error) {
+func setCellTime(value time.Time, date1904 bool) (t string, b string, isNum bool, err error) {
 	var excelTime float64
-	excelTime, err = timeToExcelTime(value)
+	excelTime, err = timeToExcelTime(value, date1904)
 	if err != nil {
 		return
 	}
@@ -187,7 +197,7 @@ func (f *File) SetCellInt(sheet, axis string, value int) error {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
-	cellData.T, cellData.V = setCellInt(value)
+	cellData.setInt(value)
 	return err
 }
 
@@ -208,7 +218,7 @@ func (f *File) SetCellBool(sheet, axis string, value bool) error {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
-	cellData.T, cellData.V = setCellBool(value)
+	cellData.setValue(setCellBool(value))
 	return err
 }
 
@@ -241,7 +251,11 @@ func (f *File) SetCellFloat(sheet, axis string, value float64, prec, bitSize int
 		return err
 	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
-	cellData.T, cellData.V = setCellFloat(value, prec, bitSize)
+	if prec == -1 && (bitSize == 32 || bitSize == 64) {
+		cellData.setFloat(value, bitSize)
+		return err
+	}
+	cellData.setValue(setCellFloat(value, prec, bitSize))
 	return err
 }
 
@@ -262,28 +276,44 @@ func (f *File) SetCellStr(sheet, axis, value string) error {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
-	cellData.T, cellData.V = f.setCellString(value)
+	t, v, ns, err := f.setCellString(value)
+	if err != nil {
+		return err
+	}
+	cellData.setValue(t, v)
+	cellData.XMLSpace = ns
 	return err
 }
 
 // setCellString provides a function to set string type to shared string
-// table.
-func (f *File) setCellString(value string) (t string, v string) {
+// table, or set the string as an inline string of the cell when the
+// InlineStrings option was specified.
+func (f *File) setCellString(value string) (t string, v string, ns xml.Attr, err error) {
+	if f.options != nil && f.options.InlineStrings {
+		t, v, ns = setCellStr(value)
+		return
+	}
 	if len(value) > TotalCellChars {
 		value = value[0:TotalCellChars]
 	}
-	t = "s"
-	v = strconv.Itoa(f.setSharedString(value))
+	var idx int
+	if idx, err = f.setSharedString(value); err != nil {
+		return
+	}
+	t, v = "s", strconv.Itoa(idx)
 	return
 }
 
 // setSharedString provides a function to add string to the share string table.
-func (f *File) setSharedString(val string) int {
-	sst := f.sharedStringsReader()
+func (f *File) setSharedString(val string) (int, error) {
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return 0, err
+	}
 	f.Lock()
 	defer f.Unlock()
-	if i, ok := f.sharedStringsMap[val]; ok {
-		return i
+	if i, ok := f.sharedStringsMap.get(sst, val); ok {
+		return i, nil
 	}
 	sst.Count++
 	sst.UniqueCount++
@@ -296,9 +326,9 @@ func (f *File) setSharedString(val string) int {
 		}
 		t.Space = ns
 	}
-	sst.SI = append(sst.SI, xlsxSI{T: &t})
-	f.sharedStringsMap[val] = sst.UniqueCount - 1
-	return sst.UniqueCount - 1
+	i := sst.add(xlsxSI{T: &t})
+	f.sharedStringsMap.add(sst, val, i)
+	return i, nil
 }
 
 // setCellStr provides a function to set string type to cell.
@@ -330,7 +360,7 @@ func (f *File) SetCellDefault(sheet, axis, value string) error {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
-	cellData.T, cellData.V = setCellDefault(value)
+	cellData.setValue(setCellDefault(value))
 	return err
 }
 
@@ -360,7 +390,13 @@ type FormulaOpts struct {
 }
 
 // SetCellFormula provides a function to set cell formula by given string and
-// worksheet name.
+// worksheet name. The structured references of the tables can be used in the
+// formula, the this row shorthand such as [@Amount] will be converted to the
+// form of Sales[[#This Row],[Amount]] which used in the file format. For
+// example, set the sum of the Amount column of the table Sales to Sheet1!F1:
+//
+//    err := f.SetCellFormula("Sheet1", "F1", "SUM(Sales[Amount])")
+//
 func (f *File) SetCellFormula(sheet, axis, formula string, opts ...FormulaOpts) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -372,10 +408,10 @@ func (f *File) SetCellFormula(sheet, axis, formula string, opts ...FormulaOpts)
 	}
 	if formula == "" {
 		cellData.F = nil
-		f.deleteCalcChain(f.getSheetID(sheet), axis)
-		return err
+		return f.deleteCalcChain(f.getSheetID(sheet), axis)
 	}
 
+	formula = f.normalizeStructuredReferences(sheet, axis, formula)
 	if cellData.F != nil {
 		cellData.F.Content = formula
 	} else {
@@ -445,7 +481,13 @@ func (f *File) GetCellHyperLink(sheet, axis string) (bool, string, error) {
 //
 //    err := f.SetCellHyperLink("Sheet1", "A3", "Sheet1!A40", "Location")
 //
-func (f *File) SetCellHyperLink(sheet, axis, link, linkType string) error {
+// Instead of setting the style of the cell manually, the built-in Hyperlink
+// cell style can be applied to the cell by the options, the number format,
+// fill, border and alignment of the cell will be kept:
+//
+//    err := f.SetCellHyperLink("Sheet1", "A3", "https://github.com/360EntSecGroup-Skylar/excelize", "External", excelize.HyperLinkOptions{ApplyStyle: true})
+//
+func (f *File) SetCellHyperLink(sheet, axis, link, linkType string, opts ...HyperLinkOptions) error {
 	// Check for correct cell name
 	if _, _, err := SplitCellName(axis); err != nil {
 		return err
@@ -490,9 +532,124 @@ func (f *File) SetCellHyperLink(sheet, axis, link, linkType string) error {
 	}
 
 	ws.Hyperlinks.Hyperlink = append(ws.Hyperlinks.Hyperlink, linkData)
+	for _, opt := range opts {
+		if opt.ApplyStyle {
+			return f.setCellHyperLinkStyle(sheet, axis)
+		}
+	}
 	return nil
 }
 
+// SetCellLocationHyperLink provides a function to set the internal hyperlink
+// of the cell which moves to a cell or range on a worksheet, or a defined
+// name in this workbook by given worksheet name, cell coordinates and the
+// destination. The worksheet name in the location will be quoted when
+// necessary. For example, set the hyperlink of Sheet1!A3 which moves to cell
+// A40 on the worksheet named "Sales Data":
+//
+//    err := f.SetCellLocationHyperLink("Sheet1", "A3", excelize.HyperLinkLocation{
+//        Sheet: "Sales Data",
+//        Cell:  "A40",
+//    })
+//
+// Set the hyperlink of Sheet1!A4 which moves to the defined name "Amount":
+//
+//    err := f.SetCellLocationHyperLink("Sheet1", "A4", excelize.HyperLinkLocation{
+//        DefinedName: "Amount",
+//    })
+//
+// The options are the same as SetCellHyperLink.
+func (f *File) SetCellLocationHyperLink(sheet, axis string, location HyperLinkLocation, opts ...HyperLinkOptions) error {
+	var link string
+	if location.DefinedName != "" {
+		if location.Sheet != "" || location.Cell != "" {
+			return errors.New("the defined name can't be used with the sheet and cell of the location")
+		}
+		found := false
+		for _, definedName := range f.GetDefinedName() {
+			if found = definedName.Name == location.DefinedName; found {
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("defined name %s is not exist", location.DefinedName)
+		}
+		link = location.DefinedName
+	} else {
+		if f.GetSheetIndex(location.Sheet) == -1 {
+			return ErrSheetNotExist{location.Sheet}
+		}
+		if _, err := rangeRefToCoordinates(location.Cell); err != nil {
+			return err
+		}
+		link = quoteSheetName(location.Sheet) + "!" + location.Cell
+	}
+	return f.SetCellHyperLink(sheet, axis, link, "Location", opts...)
+}
+
+// GetCellHyperLinkTarget provides a function to get the hyperlink of the cell
+// by given worksheet name and cell coordinates, and returns nil if the cell
+// has no hyperlink. The destination of the internal hyperlink is parsed from
+// its location, the location which only contains a cell reference refers to
+// the given worksheet. For example, get the hyperlink of Sheet1!H6:
+//
+//    link, err := f.GetCellHyperLinkTarget("Sheet1", "H6")
+//    if link != nil && !link.External {
+//        fmt.Println(link.Location.Sheet, link.Location.Cell)
+//    }
+//
+func (f *File) GetCellHyperLinkTarget(sheet, axis string) (*HyperLink, error) {
+	if _, _, err := SplitCellName(axis); err != nil {
+		return nil, err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if axis, err = f.mergeCellsParser(ws, axis); err != nil || ws.Hyperlinks == nil {
+		return nil, err
+	}
+	for _, link := range ws.Hyperlinks.Hyperlink {
+		if link.Ref != axis {
+			continue
+		}
+		if link.RID != "" {
+			return &HyperLink{External: true, Target: f.getSheetRelationshipsTargetByID(sheet, link.RID)}, err
+		}
+		return &HyperLink{Target: link.Location, Location: parseHyperLinkLocation(sheet, link.Location)}, err
+	}
+	return nil, err
+}
+
+// quoteSheetName provides a function to quote the worksheet name for the
+// reference when it contains the characters other than letters, digits,
+// underscores and periods.
+func quoteSheetName(name string) string {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			return "'" + strings.Replace(name, "'", "''", -1) + "'"
+		}
+	}
+	return name
+}
+
+// parseHyperLinkLocation provides a function to parse the location of the
+// internal hyperlink to the destination by given worksheet name of the cell.
+func parseHyperLinkLocation(sheet, location string) HyperLinkLocation {
+	location = strings.TrimPrefix(location, "#")
+	if idx := strings.LastIndex(location, "!"); idx != -1 {
+		name := location[:idx]
+		if len(name) > 1 && strings.HasPrefix(name, "'") && strings.HasSuffix(name, "'") {
+			name = strings.Replace(name[1:len(name)-1], "''", "'", -1)
+		}
+		return HyperLinkLocation{Sheet: name, Cell: strings.Replace(location[idx+1:], "$", "", -1)}
+	}
+	if _, err := rangeRefToCoordinates(location); err == nil {
+		return HyperLinkLocation{Sheet: sheet, Cell: strings.Replace(location, "$", "", -1)}
+	}
+	return HyperLinkLocation{DefinedName: location}
+}
+
 // SetCellRichText provides a function to set cell with rich text by given
 // worksheet. For example, set rich text on the A1 cell of the worksheet named
 // Sheet1:
@@ -608,7 +765,10 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 	}
 	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
 	si := xlsxSI{}
-	sst := f.sharedStringsReader()
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
 	textRuns := []xlsxR{}
 	for _, textRun := range runs {
 		run := xlsxR{T: &xlsxT{Val: textRun.Text}}
@@ -619,13 +779,13 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 		if fnt != nil {
 			rpr := xlsxRPr{}
 			if fnt.Bold {
-				rpr.B = " "
+				rpr.B = stringPtr("")
 			}
 			if fnt.Italic {
-				rpr.I = " "
+				rpr.I = stringPtr("")
 			}
 			if fnt.Strike {
-				rpr.Strike = " "
+				rpr.Strike = stringPtr("")
 			}
 			if fnt.Underline != "" {
 				rpr.U = &attrValString{Val: &fnt.Underline}
@@ -644,13 +804,107 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 		textRuns = append(textRuns, run)
 	}
 	si.R = textRuns
-	sst.SI = append(sst.SI, si)
+	idx := sst.add(si)
+	sst.Count++
+	sst.UniqueCount++
+	cellData.setValue("s", strconv.Itoa(idx))
+	return err
+}
+
+// SetCellPhonetic provides a function to set the phonetic hint (furigana) and
+// phonetic properties of the cell value by given worksheet name, cell
+// coordinates and phonetic settings. The phonetic hint covers the whole
+// value of the cell, and will be displayed above it when the Show field is
+// true. For example, set phonetic hint of the cell A1 on Sheet1:
+//
+//    err := f.SetCellStr("Sheet1", "A1", "東京")
+//    err = f.SetCellPhonetic("Sheet1", "A1", &excelize.Phonetic{
+//        Text:      "トウキョウ",
+//        Type:      "fullwidthKatakana",
+//        Alignment: "left",
+//        Show:      true,
+//    })
+//
+func (f *File) SetCellPhonetic(sheet, cell string, phonetic *Phonetic) error {
+	if phonetic == nil {
+		return errors.New("parameter is required")
+	}
+	if phonetic.Type != "" && inStrSlice([]string{"fullwidthKatakana", "halfwidthKatakana", "Hiragana", "noConversion"}, phonetic.Type) == -1 {
+		return fmt.Errorf("invalid phonetic type %q", phonetic.Type)
+	}
+	if phonetic.Alignment != "" && inStrSlice([]string{"noControl", "left", "center", "distributed"}, phonetic.Alignment) == -1 {
+		return fmt.Errorf("invalid phonetic alignment %q", phonetic.Alignment)
+	}
+	value, err := f.GetCellValue(sheet, cell)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	cellData, col, _, err := f.prepareCell(ws, sheet, cell)
+	if err != nil {
+		return err
+	}
+	cellData.S = f.prepareCellStyle(ws, col, cellData.S)
+	si := xlsxSI{
+		T:          &xlsxT{Val: value},
+		PhoneticPr: &xlsxPhoneticPr{Alignment: phonetic.Alignment, FontID: intPtr(0), Type: phonetic.Type},
+	}
+	if len(value) > 0 && (value[0] == 32 || value[len(value)-1] == 32) {
+		si.T.Space = xml.Attr{Name: xml.Name{Space: NameSpaceXML, Local: "space"}, Value: "preserve"}
+	}
+	if phonetic.Text != "" {
+		si.RPh = []*xlsxPhoneticRun{{Eb: uint32(utf8.RuneCountInString(value)), T: phonetic.Text}}
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	idx := sst.add(si)
 	sst.Count++
 	sst.UniqueCount++
-	cellData.T, cellData.V = "s", strconv.Itoa(len(sst.SI)-1)
+	cellData.setValue("s", strconv.Itoa(idx))
+	cellData.Ph = phonetic.Show
 	return err
 }
 
+// GetCellPhonetic provides a function to get the phonetic hint and phonetic
+// properties of the cell by given worksheet name and cell coordinates.
+func (f *File) GetCellPhonetic(sheet, cell string) (Phonetic, error) {
+	var phonetic Phonetic
+	_, err := f.getCellStringFunc(sheet, cell, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
+		phonetic.Show = c.Ph
+		if c.T != "s" {
+			return "", true, nil
+		}
+		idx, err := strconv.Atoi(c.value())
+		if err != nil {
+			return "", true, err
+		}
+		sst, err := f.sharedStringsReader()
+		if err != nil {
+			return "", true, err
+		}
+		if idx < 0 || idx >= sst.len() {
+			return "", true, nil
+		}
+		si, err := sst.item(idx)
+		if err != nil {
+			return "", true, err
+		}
+		for _, rPh := range si.RPh {
+			phonetic.Text += rPh.T
+		}
+		if si.PhoneticPr != nil {
+			phonetic.Type, phonetic.Alignment = si.PhoneticPr.Type, si.PhoneticPr.Alignment
+		}
+		return "", true, nil
+	})
+	return phonetic, err
+}
+
 // SetSheetRow writes an array to row by given worksheet name, starting
 // coordinate and a pointer to array type 'slice'. For example, writes an
 // array to row 6 start with the cell B6 on Sheet1:
@@ -689,7 +943,7 @@ func (f *File) prepareCell(ws *xlsxWorksheet, sheet, cell string) (*xlsxC, int,
 	ws.Lock()
 	defer ws.Unlock()
 	var err error
-	cell, err = f.mergeCellsParser(ws, cell)
+	cell, err = f.mergeCellsPolicy(ws, cell)
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -761,7 +1015,7 @@ func (f *File) formattedValue(s int, v string) string {
 	if s == 0 {
 		return v
 	}
-	styleSheet := f.stylesReader()
+	styleSheet, _ := f.stylesReader()
 
 	if s >= len(styleSheet.CellXfs.Xf) {
 		return v
@@ -771,18 +1025,18 @@ func (f *File) formattedValue(s int, v string) string {
 		numFmtID = *styleSheet.CellXfs.Xf[s].NumFmtID
 	}
 
+	date1904 := f.date1904()
 	ok := builtInNumFmtFunc[numFmtID]
 	if ok != nil {
-		return ok(v, builtInNumFmt[numFmtID])
+		return ok(v, builtInNumFmt[numFmtID], date1904)
 	}
 	if styleSheet == nil || styleSheet.NumFmts == nil {
 		return v
 	}
 	for _, xlsxFmt := range styleSheet.NumFmts.NumFmt {
 		if xlsxFmt.NumFmtID == numFmtID {
-			format := strings.ToLower(xlsxFmt.FormatCode)
-			if strings.Contains(format, "y") || strings.Contains(format, "m") || strings.Contains(strings.Replace(format, "red", "", -1), "d") || strings.Contains(format, "h") {
-				return parseTime(v, format)
+			if isDateNumFmtCode(xlsxFmt.FormatCode) {
+				return parseTime(v, strings.ToLower(xlsxFmt.FormatCode), date1904)
 			}
 			return v
 		}
@@ -790,6 +1044,34 @@ func (f *File) formattedValue(s int, v string) string {
 	return v
 }
 
+// isDateNumFmtCode provides a function to check if the custom number format
+// code is a date or time format.
+func isDateNumFmtCode(format string) bool {
+	format = strings.ToLower(format)
+	return strings.Contains(format, "y") || strings.Contains(format, "m") || strings.Contains(strings.Replace(format, "red", "", -1), "d") || strings.Contains(format, "h")
+}
+
+// isDateStyle provides a function to check if the number format of the cell
+// style is a date or time format by given style index.
+func (f *File) isDateStyle(s int) bool {
+	styleSheet, _ := f.stylesReader()
+	if s <= 0 || styleSheet.CellXfs == nil || s >= len(styleSheet.CellXfs.Xf) || styleSheet.CellXfs.Xf[s].NumFmtID == nil {
+		return false
+	}
+	numFmtID := *styleSheet.CellXfs.Xf[s].NumFmtID
+	if (numFmtID >= 14 && numFmtID <= 22) || (numFmtID >= 27 && numFmtID <= 36) || (numFmtID >= 45 && numFmtID <= 47) || (numFmtID >= 50 && numFmtID <= 58) {
+		return true
+	}
+	if styleSheet.NumFmts != nil {
+		for _, xlsxFmt := range styleSheet.NumFmts.NumFmt {
+			if xlsxFmt.NumFmtID == numFmtID {
+				return isDateNumFmtCode(xlsxFmt.FormatCode)
+			}
+		}
+	}
+	return false
+}
+
 // prepareCellStyle provides a function to prepare style index of cell in
 // worksheet by given column index and style index.
 func (f *File) prepareCellStyle(ws *xlsxWorksheet, col, style int) int {
@@ -821,6 +1103,85 @@ func (f *File) mergeCellsParser(ws *xlsxWorksheet, axis string) (string, error)
 	return axis, nil
 }
 
+// getMergeCellPolicy provides a function to get the merge cell policy of the
+// options.
+func (f *File) getMergeCellPolicy() MergeCellPolicy {
+	if f.options == nil {
+		return MergeCellPolicyRedirect
+	}
+	return f.options.MergeCellPolicy
+}
+
+// mergeCellsPolicy provides a function to apply the merge cell policy of the
+// options when writing into the cell covered by a merged cell, and returns
+// the cell which should be written.
+func (f *File) mergeCellsPolicy(ws *xlsxWorksheet, axis string) (string, error) {
+	policy := f.getMergeCellPolicy()
+	if policy == MergeCellPolicyRedirect || ws.MergeCells == nil {
+		return f.mergeCellsParser(ws, axis)
+	}
+	axis = strings.ToUpper(axis)
+	for i := 0; i < len(ws.MergeCells.Cells); i++ {
+		ref := ws.MergeCells.Cells[i].Ref
+		ok, err := f.checkCellInArea(axis, ref)
+		if err != nil {
+			return axis, err
+		}
+		if !ok || axis == strings.ToUpper(strings.Split(ref, ":")[0]) {
+			continue
+		}
+		if policy == MergeCellPolicyError {
+			return axis, ErrMergedCell{Cell: axis, Ref: ref}
+		}
+		ws.MergeCells.Cells = append(ws.MergeCells.Cells[:i], ws.MergeCells.Cells[i+1:]...)
+		ws.MergeCells.Count = len(ws.MergeCells.Cells)
+		i--
+	}
+	return axis, nil
+}
+
+// mergeCellsStylePolicy provides a function to apply the merge cell policy of
+// the options to the merged cells which partially covered by the coordinate
+// area when setting the style, and returns the area which should be styled.
+func (f *File) mergeCellsStylePolicy(ws *xlsxWorksheet, coordinates []int) ([]int, error) {
+	if ws.MergeCells == nil {
+		return coordinates, nil
+	}
+	policy := f.getMergeCellPolicy()
+	for i := 0; i < len(ws.MergeCells.Cells); i++ {
+		ref := ws.MergeCells.Cells[i].Ref
+		rect, err := rangeRefToCoordinates(ref)
+		if err != nil {
+			return coordinates, err
+		}
+		if rect[0] > coordinates[2] || coordinates[0] > rect[2] || rect[1] > coordinates[3] || coordinates[1] > rect[3] ||
+			(cellInRef(rect[:2], coordinates) && cellInRef(rect[2:], coordinates)) {
+			continue
+		}
+		switch policy {
+		case MergeCellPolicyError:
+			area, _ := coordinatesToRangeRef(coordinates)
+			return coordinates, ErrMergedCell{Cell: area, Ref: ref}
+		case MergeCellPolicyUnmerge:
+			ws.MergeCells.Cells = append(ws.MergeCells.Cells[:i], ws.MergeCells.Cells[i+1:]...)
+			ws.MergeCells.Count = len(ws.MergeCells.Cells)
+			i--
+		default:
+			for j := 0; j < 2; j++ {
+				if rect[j] < coordinates[j] {
+					coordinates[j] = rect[j]
+				}
+				if rect[j+2] > coordinates[j+2] {
+					coordinates[j+2] = rect[j+2]
+				}
+			}
+			// The expanded area may cover a part of another merged cell
+			i = -1
+		}
+	}
+	return coordinates, nil
+}
+
 // checkCellInArea provides a function to determine if a given coordinate is
 // within an area.
 func (f *File) checkCellInArea(cell, area string) (bool, error) {
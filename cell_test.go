@@ -2,6 +2,7 @@ package excelize
 
 import (
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strconv"
 	"sync"
@@ -341,3 +342,178 @@ func TestFormattedValue2(t *testing.T) {
 	v = f.formattedValue(1, "43528")
 	assert.Equal(t, "43528", v)
 }
+
+func TestSetCellStrInlineStrings(t *testing.T) {
+	f := NewFile(Options{InlineStrings: true})
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", " inline "))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "inline"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "str", ws.SheetData.Row[0].C[0].T)
+	assert.Equal(t, "preserve", ws.SheetData.Row[0].C[0].XMLSpace.Value)
+	assert.Equal(t, "str", ws.SheetData.Row[0].C[1].T)
+	sst, err := f.sharedStringsReader()
+	assert.NoError(t, err)
+	assert.Len(t, sst.SI, 0)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellStrInlineStrings.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestSetCellStrInlineStrings.xlsx"), Options{InlineStrings: true})
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, " inline ", val)
+	assert.NoError(t, f.SetCellStr("Sheet1", "C1", "inline"))
+	sst, err = f.sharedStringsReader()
+	assert.NoError(t, err)
+	assert.Len(t, sst.SI, 0)
+
+	// Test overwrite the inline string cell with shared string
+	f, err = OpenFile(filepath.Join("test", "TestSetCellStrInlineStrings.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "shared"))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "s", ws.SheetData.Row[0].C[0].T)
+	assert.Equal(t, "", ws.SheetData.Row[0].C[0].XMLSpace.Value)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "shared", val)
+}
+
+func TestSharedStringsIndex(t *testing.T) {
+	f := NewFile()
+	for i := 0; i < 3; i++ {
+		for _, val := range []string{"a", "b", "a"} {
+			assert.NoError(t, f.SetCellStr("Sheet1", fmt.Sprintf("A%d", i+1), val))
+		}
+	}
+	assert.Len(t, f.SharedStrings.SI, 2)
+	// Test add string with the colliding hash
+	sst := &xlsxSST{SI: []xlsxSI{{T: &xlsxT{Val: "a"}}, {T: &xlsxT{Val: "b"}}, {}}}
+	idx := newSharedStringsIndex()
+	idx.add(sst, "a", 0)
+	idx.hashes[hashSharedString("b")] = 0
+	idx.add(sst, "b", 1)
+	idx.add(sst, "b", 1)
+	i, ok := idx.get(sst, "b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, i)
+	i, ok = idx.get(sst, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 0, i)
+	idx.hashes[hashSharedString("c")] = 2
+	_, ok = idx.get(sst, "c")
+	assert.False(t, ok)
+	_, ok = idx.get(sst, "d")
+	assert.False(t, ok)
+	// Test get string with the index out of the shared string table
+	idx.hashes[hashSharedString("e")] = 5
+	idx.collisions["e"] = 6
+	_, ok = idx.get(sst, "e")
+	assert.False(t, ok)
+
+	// Test set string with the unique count which doesn't match the number
+	// of the shared strings
+	f = NewFile()
+	f.XLSX["xl/sharedStrings.xml"] = []byte(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="5" uniqueCount="5"><si><t>a</t></si></sst>`)
+	for i := 1; i <= 2; i++ {
+		assert.NoError(t, f.SetCellStr("Sheet1", fmt.Sprintf("A%d", i), "b"))
+		val, err := f.GetCellValue("Sheet1", fmt.Sprintf("A%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, "b", val)
+	}
+	assert.Len(t, f.SharedStrings.SI, 2)
+}
+
+func TestSetCellTypedValue(t *testing.T) {
+	f, x, y := NewFile(), 0.1, 0.2
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", -42))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", x+y))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", float32(1.325)))
+	assert.NoError(t, f.SetCellFloat("Sheet1", "D1", 1.325, 2, 64))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for i, numType := range []cellNumType{cellNumInt, cellNumFloat64, cellNumFloat32, cellNumNone} {
+		assert.Equal(t, numType, ws.SheetData.Row[0].C[i].numType)
+	}
+	assert.Equal(t, "", ws.SheetData.Row[0].C[0].V)
+	assert.Equal(t, "1.32", ws.SheetData.Row[0].C[3].V)
+	for cell, expected := range map[string]string{"A1": "-42", "B1": "0.3", "C1": "1.325", "D1": "1.32"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"-42", "0.3", "1.325", "1.32"}}, rows)
+
+	// Test overwrite the typed numeric value
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "text"))
+	assert.Equal(t, cellNumNone, ws.SheetData.Row[0].C[0].numType)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "text", val)
+	assert.NoError(t, f.SetCellInt("Sheet1", "A1", 1))
+
+	// Test copy worksheet with the typed numeric values
+	idx := f.NewSheet("Sheet2")
+	assert.NoError(t, f.CopySheet(0, idx))
+	val, err = f.GetCellValue("Sheet2", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.325", val)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellTypedValue.xlsx")))
+	content, err := ioutil.ReadFile(filepath.Join("test", "TestSetCellTypedValue.xlsx"))
+	assert.NoError(t, err)
+	assert.Contains(t, readZipPart(t, content, "xl/worksheets/sheet1.xml"), `<c r="A1"><v>1</v></c><c r="B1"><v>0.30000000000000004</v></c><c r="C1"><v>1.325</v></c>`)
+
+	f, err = OpenFile(filepath.Join("test", "TestSetCellTypedValue.xlsx"))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1", "0.3", "1.325", "1.32"}}, rows)
+}
+
+func TestSetCellPhonetic(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "東京"))
+	assert.NoError(t, f.SetCellPhonetic("Sheet1", "A1", &Phonetic{
+		Text:      "トウキョウ",
+		Type:      "fullwidthKatakana",
+		Alignment: "center",
+		Show:      true,
+	}))
+	phonetic, err := f.GetCellPhonetic("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, Phonetic{Text: "トウキョウ", Type: "fullwidthKatakana", Alignment: "center", Show: true}, phonetic)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "東京", val)
+	sst, err := f.sharedStringsReader()
+	assert.NoError(t, err)
+	assert.Equal(t, []*xlsxPhoneticRun{{Sb: 0, Eb: 2, T: "トウキョウ"}}, sst.SI[len(sst.SI)-1].RPh)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellPhonetic.xlsx")))
+
+	// Test get phonetic of the cell without phonetic settings
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 100))
+	phonetic, err = f.GetCellPhonetic("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, Phonetic{}, phonetic)
+	phonetic, err = f.GetCellPhonetic("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, Phonetic{}, phonetic)
+
+	// Test set cell phonetic with invalid parameters
+	assert.EqualError(t, f.SetCellPhonetic("Sheet1", "A1", nil), "parameter is required")
+	assert.EqualError(t, f.SetCellPhonetic("Sheet1", "A1", &Phonetic{Type: "katakana"}), `invalid phonetic type "katakana"`)
+	assert.EqualError(t, f.SetCellPhonetic("Sheet1", "A1", &Phonetic{Alignment: "right"}), `invalid phonetic alignment "right"`)
+	assert.EqualError(t, f.SetCellPhonetic("SheetN", "A1", &Phonetic{}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetCellPhonetic("Sheet1", "A", &Phonetic{}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = f.GetCellPhonetic("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get cell phonetic with invalid shared string index
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0].V = "x"
+	_, err = f.GetCellPhonetic("Sheet1", "A1")
+	assert.EqualError(t, err, `strconv.Atoi: parsing "x": invalid syntax`)
+}
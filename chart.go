@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // This section defines the currently supported chart types.
@@ -468,17 +469,35 @@ var (
 		Contour:          "none",
 		WireframeContour: "none",
 	}
+	chartTrendlineTypes = map[string]string{
+		"exponential":    "exp",
+		"linear":         "linear",
+		"logarithmic":    "log",
+		"moving_average": "movingAvg",
+		"polynomial":     "poly",
+		"power":          "power",
+	}
+	chartTrendlineSupported = map[string]bool{
+		Area:    true,
+		Bar:     true,
+		Col:     true,
+		Line:    true,
+		Scatter: true,
+		Bubble:  true,
+	}
 )
 
 // parseFormatChartSet provides a function to parse the format settings of the
-// chart with default value.
-func parseFormatChartSet(formatSet string) (*formatChart, error) {
-	format := formatChart{
-		Dimension: formatChartDimension{
+// chart by given JSON string or Chart structure pointer with default value.
+// The unknown fields in the JSON string will be returned as an error if the
+// strict is true, otherwise they will be ignored.
+func parseFormatChartSet(formatSet interface{}, strict bool) (*Chart, error) {
+	format := Chart{
+		Dimension: ChartDimension{
 			Width:  480,
 			Height: 290,
 		},
-		Format: formatPicture{
+		Format: PictureFormat{
 			FPrintsWithSheet: true,
 			FLocksWithSheet:  false,
 			NoChangeAspect:   false,
@@ -487,22 +506,65 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 			XScale:           1.0,
 			YScale:           1.0,
 		},
-		Legend: formatChartLegend{
+		Legend: ChartLegend{
 			Position:      "bottom",
 			ShowLegendKey: false,
 		},
-		Title: formatChartTitle{
+		Title: ChartTitle{
 			Name: " ",
 		},
 		ShowBlanksAs: "gap",
 	}
-	err := json.Unmarshal([]byte(formatSet), &format)
-	return &format, err
+	var err error
+	switch v := formatSet.(type) {
+	case string:
+		if !strict {
+			err = json.Unmarshal([]byte(v), &format)
+			break
+		}
+		decoder := json.NewDecoder(strings.NewReader(v))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&format)
+	case *Chart:
+		if v == nil {
+			return &format, errors.New("parameter is required")
+		}
+		format = *v
+		format.setDefaults()
+	default:
+		err = errors.New("invalid parameter type")
+	}
+	if err != nil {
+		return &format, err
+	}
+	return &format, format.Format.validate()
+}
+
+// setDefaults provides a function to set the default value of the format
+// settings of the chart which are omitted in the Chart structure.
+func (c *Chart) setDefaults() {
+	if c.Dimension.Width == 0 {
+		c.Dimension.Width = 480
+	}
+	if c.Dimension.Height == 0 {
+		c.Dimension.Height = 290
+	}
+	c.Format.setDefaults()
+	if c.Legend.Position == "" {
+		c.Legend.Position = "bottom"
+	}
+	if c.Title.Name == "" {
+		c.Title.Name = " "
+	}
+	if c.ShowBlanksAs == "" {
+		c.ShowBlanksAs = "gap"
+	}
 }
 
 // AddChart provides the method to add chart in a sheet by given chart format
 // set (such as offset, scale, aspect ratio setting and print settings) and
-// properties set. For example, create 3D clustered column chart with data
+// properties set, the format set and the combo charts can be the JSON string
+// or the Chart structure pointer. For example, create 3D clustered column chart with data
 // Sheet1!$E$1:$L$15:
 //
 //    package main
@@ -601,6 +663,7 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //    values
 //    line
 //    marker
+//    trendline
 //
 // name: Set the name for the series. The name is displayed in the chart legend and in the formula bar. The name property is optional and if it isn't supplied it will default to Series 1..n. The name can also be a formula such as Sheet1!$A$1
 //
@@ -625,6 +688,19 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //    x
 //    auto
 //
+// trendline: This sets the trendline of the series in the area, bar, column, line, scatter and bubble chart. The trendline property is optional and no trendline will be added if the optional field 'type' isn't supplied. The enumeration value of the field 'type' are:
+//
+//    exponential
+//    linear
+//    logarithmic
+//    moving_average
+//    polynomial
+//    power
+//
+// The optional field 'name' specifies the name of the trendline in the chart legend. The range of optional field 'order' of the polynomial trendline is 2-6 (default value is 2), and the range of optional field 'period' of the moving average trendline is 2-255 (default value is 2). The optional fields 'forward' and 'backward' specify the number of periods to forecast the trendline forward and backward. The optional fields 'display_r_squared' and 'display_equation' specify if display the R-squared value and the equation of the trendline on the chart. For example, add a polynomial trendline displaying the equation to the series:
+//
+//    {"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","trendline":{"type":"polynomial","order":3,"display_equation":true}}
+//
 // Set properties of the chart legend. The options that can be set are:
 //
 //    position
@@ -715,6 +791,25 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //
 // Set chart size by dimension property. The dimension property is optional. The default width is 480, and height is 290.
 //
+// The format set can also be specified by the Chart structure, the fields of
+// the structure are corresponding to the properties of the JSON format set.
+// The zero values of the dimension, x_scale, y_scale, the legend position,
+// the title name and show_blanks_as will be replaced by the default values.
+// Note that the print_obj of the format is false by default in the Chart
+// structure. For example, create a line chart by the Chart structure:
+//
+//    series := excelize.ChartSeries{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2"}
+//    series.Marker.Symbol = "none"
+//    chart := &excelize.Chart{Type: excelize.Line, Series: []excelize.ChartSeries{series}}
+//    chart.Title.Name = "Fruit Line Chart"
+//    chart.YAxis.MajorGridlines = true
+//    err := f.AddChart("Sheet1", "E1", chart)
+//
+// The values of the series is required and the categories of the series is
+// optional, which should be the reference of the cell range with the sheet
+// name such as Sheet1!$B$1:$D$1, the error will be returned if the chart type
+// is unsupported or the reference is invalid.
+//
 // combo: Specifies the create a chart that combines two or more chart types
 // in a single chart. For example, create a clustered column - line chart with
 // data Sheet1!$E$1:$L$15:
@@ -747,7 +842,7 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //        }
 //    }
 //
-func (f *File) AddChart(sheet, cell, format string, combo ...string) error {
+func (f *File) AddChart(sheet, cell string, format interface{}, combo ...interface{}) error {
 	// Read sheet data.
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -778,8 +873,9 @@ func (f *File) AddChart(sheet, cell, format string, combo ...string) error {
 // AddChartSheet provides the method to create a chartsheet by given chart
 // format set (such as offset, scale, aspect ratio setting and print settings)
 // and properties set. In Excel a chartsheet is a worksheet that only contains
-// a chart.
-func (f *File) AddChartSheet(sheet, format string, combo ...string) error {
+// a chart. The format set and the combo charts can be the JSON string or the
+// Chart structure pointer, see AddChart for details.
+func (f *File) AddChartSheet(sheet string, format interface{}, combo ...interface{}) error {
 	// Check if the worksheet already exists
 	if f.GetSheetIndex(sheet) != -1 {
 		return errors.New("the same name worksheet already exists")
@@ -794,7 +890,10 @@ func (f *File) AddChartSheet(sheet, format string, combo ...string) error {
 		},
 	}
 	f.SheetCount++
-	wb := f.workbookReader()
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
 	sheetID := 0
 	for _, v := range wb.Sheets.Sheet {
 		if v.SheetID > sheetID {
@@ -811,7 +910,9 @@ func (f *File) AddChartSheet(sheet, format string, combo ...string) error {
 	f.prepareChartSheetDrawing(&cs, drawingID, sheet)
 	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
 	drawingRID := f.addRels(drawingRels, SourceRelationshipChart, "../charts/chart"+strconv.Itoa(chartID)+".xml", "")
-	f.addSheetDrawingChart(drawingXML, drawingRID, &formatSet.Format)
+	if err = f.addSheetDrawingChart(drawingXML, drawingRID, &formatSet.Format); err != nil {
+		return err
+	}
 	f.addChart(formatSet, comboCharts)
 	f.addContentTypePart(chartID, "chart")
 	f.addContentTypePart(sheetID, "chartsheet")
@@ -827,27 +928,126 @@ func (f *File) AddChartSheet(sheet, format string, combo ...string) error {
 }
 
 // getFormatChart provides a function to check format set of the chart and
-// create chart format.
-func (f *File) getFormatChart(format string, combo []string) (*formatChart, []*formatChart, error) {
-	comboCharts := []*formatChart{}
-	formatSet, err := parseFormatChartSet(format)
+// create chart format. The series references of the Chart structure will be
+// checked, but the series references of the JSON string will be checked only
+// if the StrictChartFormat of the options is enabled for compatibility.
+func (f *File) getFormatChart(format interface{}, combo []interface{}) (*Chart, []*Chart, error) {
+	comboCharts := []*Chart{}
+	strict := f.options != nil && f.options.StrictChartFormat
+	formatSet, err := parseFormatChartSet(format, strict)
 	if err != nil {
 		return formatSet, comboCharts, err
 	}
 	for _, comboFormat := range combo {
-		comboChart, err := parseFormatChartSet(comboFormat)
+		comboChart, err := parseFormatChartSet(comboFormat, strict)
 		if err != nil {
 			return formatSet, comboCharts, err
 		}
 		if _, ok := chartValAxNumFmtFormatCode[comboChart.Type]; !ok {
 			return formatSet, comboCharts, errors.New("unsupported chart type " + comboChart.Type)
 		}
+		_, typed := comboFormat.(*Chart)
+		if err = checkChartSeries(comboChart, typed || strict); err != nil {
+			return formatSet, comboCharts, err
+		}
 		comboCharts = append(comboCharts, comboChart)
 	}
 	if _, ok := chartValAxNumFmtFormatCode[formatSet.Type]; !ok {
 		return formatSet, comboCharts, errors.New("unsupported chart type " + formatSet.Type)
 	}
-	return formatSet, comboCharts, err
+	_, typed := format.(*Chart)
+	return formatSet, comboCharts, checkChartSeries(formatSet, typed || strict)
+}
+
+// checkChartSeries provides a function to check the format settings of the
+// chart series, the references of the values and categories will be checked
+// only if the checkRef is true.
+func checkChartSeries(formatSet *Chart, checkRef bool) error {
+	for i, series := range formatSet.Series {
+		if checkRef {
+			if series.Values == "" {
+				return fmt.Errorf("parameter 'Series[%d].Values' is required", i)
+			}
+			if err := checkChartSeriesRef(series.Values); err != nil {
+				return fmt.Errorf("parameter 'Series[%d].Values' parsing error: %v", i, err)
+			}
+			if series.Categories != "" {
+				if err := checkChartSeriesRef(series.Categories); err != nil {
+					return fmt.Errorf("parameter 'Series[%d].Categories' parsing error: %v", i, err)
+				}
+			}
+		}
+		if err := checkChartTrendline(formatSet.Type, i, &series.Trendline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkChartTrendline provides a function to check the format settings of
+// the trendline of the chart series by given chart type and series index.
+func checkChartTrendline(chartType string, i int, trendline *ChartTrendline) error {
+	if trendline.Type == "" {
+		return nil
+	}
+	if _, ok := chartTrendlineTypes[trendline.Type]; !ok {
+		return fmt.Errorf("parameter 'Series[%d].Trendline.Type' must be 'exponential', 'linear', 'logarithmic', 'moving_average', 'polynomial' or 'power'", i)
+	}
+	if !chartTrendlineSupported[chartType] {
+		return fmt.Errorf("parameter 'Series[%d].Trendline' is not supported by the %s chart", i, chartType)
+	}
+	if trendline.Order != 0 && (trendline.Order < 2 || trendline.Order > 6) {
+		return fmt.Errorf("parameter 'Series[%d].Trendline.Order' must be between 2 and 6", i)
+	}
+	if trendline.Period != 0 && (trendline.Period < 2 || trendline.Period > 255) {
+		return fmt.Errorf("parameter 'Series[%d].Trendline.Period' must be between 2 and 255", i)
+	}
+	if trendline.Forward < 0 || trendline.Backward < 0 {
+		return fmt.Errorf("parameter 'Series[%d].Trendline.Forward' and 'Backward' must not be negative", i)
+	}
+	return nil
+}
+
+// checkChartSeriesRef provides a function to check the reference of the
+// chart series, which could be the cell range with the sheet name such as
+// Sheet1!$B$1:$D$1, the defined name such as Sales or Sheet1!Sales, or the
+// array constant such as {1,2,3}.
+func checkChartSeriesRef(ref string) error {
+	if strings.HasPrefix(ref, "{") && strings.HasSuffix(ref, "}") {
+		return nil
+	}
+	name := ref
+	if idx := strings.LastIndex(ref, "!"); idx != -1 {
+		if idx == 0 {
+			return fmt.Errorf("invalid reference %q", ref)
+		}
+		if _, err := rangeRefToCoordinates(ref[idx+1:]); err == nil {
+			return nil
+		}
+		name = ref[idx+1:]
+	}
+	if !isDefinedNameLike(name) {
+		return fmt.Errorf("invalid reference %q", ref)
+	}
+	return nil
+}
+
+// isDefinedNameLike provides a function to check if the given string could
+// be a defined name, which starts with a letter, an underscore or a
+// backslash, contains only letters, digits, underscores, periods and
+// backslashes, and is not a cell reference.
+func isDefinedNameLike(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if unicode.IsLetter(r) || r == '_' || r == '\\' || (i > 0 && (unicode.IsDigit(r) || r == '.')) {
+			continue
+		}
+		return false
+	}
+	_, _, err := CellNameToCoordinates(name)
+	return err != nil
 }
 
 // DeleteChart provides a function to delete chart in XLSX by given worksheet
@@ -889,5 +1089,5 @@ func (f *File) ptToEMUs(pt float64) int {
 	if 0.25 > pt || pt > 999 {
 		return 25400
 	}
-	return int(12700 * pt)
+	return PointsToEMUs(pt)
 }
@@ -208,6 +208,107 @@ func TestAddChart(t *testing.T) {
 	assert.EqualError(t, f.AddChart("Sheet2", "BD64", `{"type":"barOfPie","series":[{"name":"Sheet1!$A$30","categories":"Sheet1!$A$30:$D$37","values":"Sheet1!$B$30:$B$37"}],"format":{"x_scale":1.0,"y_scale":1.0,"x_offset":15,"y_offset":10,"print_obj":true,"lock_aspect_ratio":false,"locked":false},"legend":{"position":"left","show_legend_key":false},"title":{"name":"Bar of Pie Chart"},"plotarea":{"show_bubble_size":true,"show_cat_name":false,"show_leader_lines":false,"show_percent":true,"show_series_name":true,"show_val":true},"show_blanks_as":"zero","x_axis":{"major_grid_lines":true},"y_axis":{"major_grid_lines":true}}`, `{"type":"unknown","series":[{"name":"Sheet1!$A$30","categories":"Sheet1!$A$30:$D$37","values":"Sheet1!$B$30:$B$37"}],"format":{"x_scale":1.0,"y_scale":1.0,"x_offset":15,"y_offset":10,"print_obj":true,"lock_aspect_ratio":false,"locked":false},"legend":{"position":"left","show_legend_key":false},"title":{"name":"Bar of Pie Chart"},"plotarea":{"show_bubble_size":true,"show_cat_name":false,"show_leader_lines":false,"show_percent":true,"show_series_name":true,"show_val":true},"show_blanks_as":"zero","x_axis":{"major_grid_lines":true},"y_axis":{"major_grid_lines":true}}`), "unsupported chart type unknown")
 }
 
+func TestAddChartWithStructure(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{nil, "Apple", "Orange", "Pear"}, {"Small", 2, 3, 3}, {"Normal", 5, 2, 4}} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	series := ChartSeries{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2"}
+	series.Marker.Symbol = "none"
+	chart := &Chart{Type: Col, Series: []ChartSeries{series, {Name: "Sheet1!$A$3", Values: "Sheet1!$B$3:$D$3"}}}
+	chart.Title.Name = "Fruit Column Chart"
+	chart.YAxis.MajorGridlines = true
+	combo := &Chart{Type: Line, Series: []ChartSeries{series}}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", chart, combo))
+	assert.NoError(t, f.AddChartSheet("Chart1", chart))
+	// Test the default values of the chart structure.
+	formatSet, _, err := f.getFormatChart(&Chart{Type: Col}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ChartDimension{Width: 480, Height: 290}, formatSet.Dimension)
+	assert.Equal(t, 1.0, formatSet.Format.XScale)
+	assert.Equal(t, "bottom", formatSet.Legend.Position)
+	assert.Equal(t, " ", formatSet.Title.Name)
+	assert.Equal(t, "gap", formatSet.ShowBlanksAs)
+	assert.Equal(t, "", chart.ShowBlanksAs)
+
+	// Test add chart with invalid format set.
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", nil), "invalid parameter type")
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", (*Chart)(nil)), "parameter is required")
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", chart, 1), "invalid parameter type")
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", &Chart{Type: "column"}), "unsupported chart type column")
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", chart, &Chart{Type: "column"}), "unsupported chart type column")
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", &Chart{Type: Col, Series: []ChartSeries{{Name: "Sheet1!$A$2"}}}), "parameter 'Series[0].Values' is required")
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", &Chart{Type: Col, Series: []ChartSeries{{Values: "$B$2:$D$2"}}}), `parameter 'Series[0].Values' parsing error: invalid reference "$B$2:$D$2"`)
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", &Chart{Type: Col, Series: []ChartSeries{{Values: "Sheet1!$B$2:$D"}}}), `parameter 'Series[0].Values' parsing error: invalid reference "Sheet1!$B$2:$D"`)
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", chart, &Chart{Type: Line, Series: []ChartSeries{{Categories: "Sheet1!B1:D1:E1", Values: "Sheet1!B2:D2"}}}), `parameter 'Series[0].Categories' parsing error: invalid reference "Sheet1!B1:D1:E1"`)
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", &Chart{Type: Col, Series: []ChartSeries{{Values: "!Sales"}}}), `parameter 'Series[0].Values' parsing error: invalid reference "!Sales"`)
+	assert.EqualError(t, f.AddChart("Sheet1", "E20", &Chart{Type: Col, Series: []ChartSeries{{Values: "B2"}}}), `parameter 'Series[0].Values' parsing error: invalid reference "B2"`)
+
+	// Test add chart with the defined names and array constants as the series
+	// references.
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Sales", RefersTo: "Sheet1!$B$2:$D$2"}))
+	assert.NoError(t, f.AddChart("Sheet1", "E40", &Chart{Type: Col, Series: []ChartSeries{{Categories: "{1,2,3}", Values: "Sheet1!Sales"}, {Values: "Book1!Sales"}, {Values: "Sales"}}}))
+	assert.NoError(t, f.AddChart("Sheet1", "E60", `{"type":"col","series":[{"categories":"{1,2,3}","values":"Sheet1!Sales"},{"values":"Book1!Sales"}]}`))
+
+	// Test add chart with the JSON format settings, the unknown fields and the
+	// series references will be checked only with the strict chart format.
+	assert.NoError(t, f.AddChart("Sheet1", "E80", `{"type":"col","series":[{"value":"Sheet1!$B$2:$D$2"},{"values":"$B$2:$D$2"}]}`))
+	f.options = &Options{StrictChartFormat: true}
+	assert.EqualError(t, f.AddChart("Sheet1", "E100", `{"type":"col","series":[{"value":"Sheet1!$B$2:$D$2"}]}`), `json: unknown field "value"`)
+	assert.EqualError(t, f.AddChart("Sheet1", "E100", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}]}`, `{"type":"line","series":[{"values":"Sheet1!"}]}`), `parameter 'Series[0].Values' parsing error: invalid reference "Sheet1!"`)
+	assert.NoError(t, f.AddChart("Sheet1", "E100", `{"type":"col","series":[{"values":"Sheet1!Sales"}]}`))
+	f.options = nil
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartWithStructure.xlsx")))
+}
+
+func TestAddChartTrendline(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{nil, "Apple", "Orange", "Pear", "Plum"}, {"Small", 2, 3, 3, 5}, {"Normal", 5, 2, 4, 6}, {"Large", 6, 7, 8, 4}} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "G1", `{"type":"line","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$E$1","values":"Sheet1!$B$2:$E$2","trendline":{"type":"linear","name":"Linear","forward":1.5,"display_r_squared":true,"display_equation":true}},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$E$1","values":"Sheet1!$B$3:$E$3","trendline":{"type":"polynomial","order":3}},{"name":"Sheet1!$A$4","categories":"Sheet1!$B$1:$E$1","values":"Sheet1!$B$4:$E$4","trendline":{"type":"moving_average","backward":1}}]}`))
+	series := ChartSeries{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$E$1", Values: "Sheet1!$B$2:$E$2"}
+	series.Trendline = ChartTrendline{Type: "exponential", Backward: 0.5}
+	assert.NoError(t, f.AddChart("Sheet1", "G16", &Chart{Type: Scatter, Series: []ChartSeries{series}}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartTrendline.xlsx")))
+
+	trendlines := []*cTrendline{}
+	for _, chart := range []string{"xl/charts/chart1.xml", "xl/charts/chart2.xml"} {
+		content := new(xlsxChartSpace)
+		assert.NoError(t, xml.Unmarshal(f.readXML(chart), content))
+		for _, chartType := range []*cCharts{content.Chart.PlotArea.LineChart, content.Chart.PlotArea.ScatterChart} {
+			if chartType == nil {
+				continue
+			}
+			for _, ser := range *chartType.Ser {
+				trendlines = append(trendlines, ser.Trendline)
+			}
+		}
+	}
+	assert.Equal(t, []*cTrendline{
+		{Name: "Linear", TrendlineType: &attrValString{Val: stringPtr("linear")}, Forward: &attrValFloat{Val: float64Ptr(1.5)}, DispRSqr: &attrValBool{Val: boolPtr(true)}, DispEq: &attrValBool{Val: boolPtr(true)}},
+		{TrendlineType: &attrValString{Val: stringPtr("poly")}, Order: &attrValInt{Val: intPtr(3)}, DispRSqr: &attrValBool{Val: boolPtr(false)}, DispEq: &attrValBool{Val: boolPtr(false)}},
+		{TrendlineType: &attrValString{Val: stringPtr("movingAvg")}, Period: &attrValInt{Val: intPtr(2)}, DispRSqr: &attrValBool{Val: boolPtr(false)}, DispEq: &attrValBool{Val: boolPtr(false)}},
+		{TrendlineType: &attrValString{Val: stringPtr("exp")}, Backward: &attrValFloat{Val: float64Ptr(0.5)}, DispRSqr: &attrValBool{Val: boolPtr(false)}, DispEq: &attrValBool{Val: boolPtr(false)}},
+	}, trendlines)
+
+	// Test add chart with invalid trendline.
+	for _, c := range []struct {
+		chartType string
+		trendline ChartTrendline
+		err       string
+	}{
+		{Line, ChartTrendline{Type: "quadratic"}, "parameter 'Series[0].Trendline.Type' must be 'exponential', 'linear', 'logarithmic', 'moving_average', 'polynomial' or 'power'"},
+		{Pie, ChartTrendline{Type: "linear"}, "parameter 'Series[0].Trendline' is not supported by the pie chart"},
+		{Line, ChartTrendline{Type: "polynomial", Order: 7}, "parameter 'Series[0].Trendline.Order' must be between 2 and 6"},
+		{Line, ChartTrendline{Type: "moving_average", Period: 1}, "parameter 'Series[0].Trendline.Period' must be between 2 and 255"},
+		{Line, ChartTrendline{Type: "linear", Forward: -1}, "parameter 'Series[0].Trendline.Forward' and 'Backward' must not be negative"},
+	} {
+		series.Trendline = c.trendline
+		assert.EqualError(t, f.AddChart("Sheet1", "G31", &Chart{Type: c.chartType, Series: []ChartSeries{series}}), c.err)
+	}
+}
+
 func TestAddChartSheet(t *testing.T) {
 	categories := map[string]string{"A2": "Small", "A3": "Normal", "A4": "Large", "B1": "Apple", "C1": "Orange", "D1": "Pear"}
 	values := map[string]int{"B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4, "B4": 6, "C4": 7, "D4": 8}
@@ -90,7 +90,10 @@ func (cols *Cols) Rows() ([]string, error) {
 	if cols.stashCol >= cols.curCol {
 		return rows, err
 	}
-	d := cols.f.sharedStringsReader()
+	d, err := cols.f.sharedStringsReader()
+	if err != nil {
+		return rows, err
+	}
 	decoder := cols.f.xmlNewDecoder(bytes.NewReader(cols.sheetXML))
 	for {
 		token, _ := decoder.Token()
@@ -157,17 +160,13 @@ func (f *File) Cols(sheet string) (*Cols, error) {
 	if !ok {
 		return nil, ErrSheetNotExist{sheet}
 	}
-	if f.Sheet[name] != nil {
-		output, _ := xml.Marshal(f.Sheet[name])
-		f.saveFileList(name, f.replaceNameSpaceBytes(name, output))
-	}
 	var (
 		inElement            string
 		cols                 Cols
 		cellCol, curRow, row int
 		err                  error
 	)
-	cols.sheetXML = f.readXML(name)
+	cols.sheetXML = f.readSheetXML(name)
 	decoder := f.xmlNewDecoder(bytes.NewReader(cols.sheetXML))
 	for {
 		token, _ := decoder.Token()
@@ -0,0 +1,191 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType defined the type of the column inferred from the types and
+// number formats of the cells in the column.
+type ColumnType byte
+
+// Column types enumeration.
+const (
+	ColumnTypeString ColumnType = iota
+	ColumnTypeInt64
+	ColumnTypeFloat64
+	ColumnTypeBool
+	ColumnTypeTime
+)
+
+// ColumnField directly maps the name and the inferred type of the column.
+type ColumnField struct {
+	Name string
+	Type ColumnType
+}
+
+// RecordBatchWriter is the interface that wraps the methods for writing the
+// typed columns as the record batches, such as the Apache Arrow record
+// builder or the Apache Parquet file writer. The WriteSchema will be called
+// once with the fields of all columns before any record batch, and each
+// column of the record batch passed to the WriteRecordBatch contains the
+// values with the Go type of the column type: string, int64, float64, bool
+// and time.Time, the null values are nil.
+type RecordBatchWriter interface {
+	WriteSchema(fields []ColumnField) error
+	WriteRecordBatch(columns [][]interface{}) error
+}
+
+// ColumnarOptions directly maps the settings of exporting the worksheet as
+// the typed columns.
+//
+// Range and Table specifies the range or the table to be exported, with the
+// same rules of the JSONOptions.
+//
+// BatchSize specifies the maximum number of rows in each record batch, the
+// default value is 1024.
+type ColumnarOptions struct {
+	Range     string
+	Table     string
+	BatchSize int
+}
+
+// ExportColumns provides a function to write the rows of the worksheet to
+// the record batch writer as the typed columns by given worksheet name and
+// optional settings. The names of the columns are resolved from the header
+// row with the same rules of the ExportJSON, and the rows without any value
+// will be skipped. The type of each column is inferred from the values of
+// the cells: the column with only the integers is ColumnTypeInt64, the
+// column with only the numbers is ColumnTypeFloat64, the column with only
+// the boolean values is ColumnTypeBool, the column with only the values in
+// date or time number format is ColumnTypeTime, and the other columns are
+// ColumnTypeString with the formatted values of the cells. For example,
+// export the table named "Sales" on Sheet1 by the writer which builds the
+// Apache Arrow records:
+//
+//    err := f.ExportColumns("Sheet1", arrowWriter, excelize.ColumnarOptions{Table: "Sales"})
+//
+func (f *File) ExportColumns(sheet string, w RecordBatchWriter, opts ...ColumnarOptions) error {
+	var options ColumnarOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1024
+	}
+	cells, area, names, err := f.getExportRecords(sheet, options.Range, options.Table)
+	if err != nil {
+		return err
+	}
+	var rows [][]exportCell
+	if area == nil {
+		area = []int{1, 1, 0, 0}
+	}
+	for row := area[1]; row <= area[3]; row++ {
+		record, empty := make([]exportCell, len(names)), true
+		for i := range record {
+			if record[i] = cells[[2]int{area[0] + i, row}]; record[i].Value != "" {
+				empty = false
+			}
+		}
+		if !empty {
+			rows = append(rows, record)
+		}
+	}
+	fields := make([]ColumnField, len(names))
+	for i, name := range names {
+		fields[i] = ColumnField{Name: name, Type: f.getColumnType(rows, i)}
+	}
+	if err = w.WriteSchema(fields); err != nil {
+		return err
+	}
+	for start := 0; start < len(rows); start += options.BatchSize {
+		end := start + options.BatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		columns := make([][]interface{}, len(fields))
+		for i, field := range fields {
+			columns[i] = make([]interface{}, 0, end-start)
+			for _, record := range rows[start:end] {
+				columns[i] = append(columns[i], f.getColumnValue(record[i], field.Type))
+			}
+		}
+		if err = w.WriteRecordBatch(columns); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// getColumnType provides a function to infer the type of the column by given
+// rows and the index of the column.
+func (f *File) getColumnType(rows [][]exportCell, col int) ColumnType {
+	var columnType *ColumnType
+	for _, record := range rows {
+		var valueType ColumnType
+		switch f.getRecordValue(record[col]).(type) {
+		case nil:
+			continue
+		case int64:
+			valueType = ColumnTypeInt64
+		case float64:
+			valueType = ColumnTypeFloat64
+		case bool:
+			valueType = ColumnTypeBool
+		case time.Time:
+			valueType = ColumnTypeTime
+		default:
+			return ColumnTypeString
+		}
+		if columnType == nil {
+			columnType = &valueType
+			continue
+		}
+		if *columnType == valueType {
+			continue
+		}
+		if (*columnType == ColumnTypeInt64 || *columnType == ColumnTypeFloat64) && (valueType == ColumnTypeInt64 || valueType == ColumnTypeFloat64) {
+			*columnType = ColumnTypeFloat64
+			continue
+		}
+		return ColumnTypeString
+	}
+	if columnType == nil {
+		return ColumnTypeString
+	}
+	return *columnType
+}
+
+// getColumnValue provides a function to convert the cell to the value with
+// the Go type of the given column type.
+func (f *File) getColumnValue(cell exportCell, columnType ColumnType) interface{} {
+	value := f.getRecordValue(cell)
+	if value == nil {
+		return value
+	}
+	switch columnType {
+	case ColumnTypeString:
+		if b, ok := value.(bool); ok {
+			return strings.ToUpper(strconv.FormatBool(b))
+		}
+		return f.formattedValue(cell.StyleID, cell.Value)
+	case ColumnTypeFloat64:
+		if i, ok := value.(int64); ok {
+			return float64(i)
+		}
+	}
+	return value
+}
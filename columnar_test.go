@@ -0,0 +1,69 @@
+package excelize
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecordBatchWriter struct {
+	fields  []ColumnField
+	batches [][][]interface{}
+	err     error
+}
+
+func (w *testRecordBatchWriter) WriteSchema(fields []ColumnField) error {
+	w.fields = fields
+	return w.err
+}
+
+func (w *testRecordBatchWriter) WriteRecordBatch(columns [][]interface{}) error {
+	w.batches = append(w.batches, columns)
+	return nil
+}
+
+func TestExportColumns(t *testing.T) {
+	f := NewFile()
+	date := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for cell, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Count", "C1": "Amount", "D1": "Paid", "E1": "Date", "F1": "Mixed",
+		"A2": "Foo", "B2": 1, "C2": 1, "D2": true, "E2": date, "F2": 1,
+		"A3": "Bar", "B3": 2, "C3": 2.5, "D3": false, "F3": "x",
+		"A5": "Baz", "E5": date.AddDate(0, 0, 1), "F5": true,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	w := &testRecordBatchWriter{}
+	assert.NoError(t, f.ExportColumns("Sheet1", w, ColumnarOptions{BatchSize: 2}))
+	assert.Equal(t, []ColumnField{
+		{Name: "Name", Type: ColumnTypeString},
+		{Name: "Count", Type: ColumnTypeInt64},
+		{Name: "Amount", Type: ColumnTypeFloat64},
+		{Name: "Paid", Type: ColumnTypeBool},
+		{Name: "Date", Type: ColumnTypeTime},
+		{Name: "Mixed", Type: ColumnTypeString},
+	}, w.fields)
+	assert.Equal(t, [][][]interface{}{
+		{{"Foo", "Bar"}, {int64(1), int64(2)}, {float64(1), 2.5}, {true, false}, {date, nil}, {"1", "x"}},
+		{{"Baz"}, {nil}, {nil}, {nil}, {date.AddDate(0, 0, 1)}, {"TRUE"}},
+	}, w.batches)
+
+	// Test export the range and the empty worksheet
+	w = &testRecordBatchWriter{}
+	assert.NoError(t, f.ExportColumns("Sheet1", w, ColumnarOptions{Range: "G1:G3"}))
+	assert.Equal(t, []ColumnField{{Name: "G", Type: ColumnTypeString}}, w.fields)
+	assert.Nil(t, w.batches)
+	f.NewSheet("Sheet2")
+	w = &testRecordBatchWriter{}
+	assert.NoError(t, f.ExportColumns("Sheet2", w))
+	assert.Equal(t, []ColumnField{}, w.fields)
+	assert.Nil(t, w.batches)
+
+	// Test export with invalid options and writer error
+	assert.EqualError(t, f.ExportColumns("SheetN", w), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ExportColumns("Sheet1", w, ColumnarOptions{Table: "Sales"}), "table Sales is not exist")
+	w.err = errors.New("schema error")
+	assert.EqualError(t, f.ExportColumns("Sheet1", w), "schema error")
+}
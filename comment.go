@@ -15,9 +15,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -30,17 +30,36 @@ func parseFormatCommentsSet(formatSet string) (*formatComment, error) {
 		Author: "Author:",
 		Text:   " ",
 	}
-	err := json.Unmarshal([]byte(formatSet), &format)
-	return &format, err
+	if err := json.Unmarshal([]byte(formatSet), &format); err != nil {
+		return &format, err
+	}
+	if format.Width < 0 || format.Height < 0 {
+		return &format, errors.New("parameter 'width' and 'height' must be greater than or equal to 0")
+	}
+	if format.Anchor != "" {
+		anchor := strings.Split(format.Anchor, ",")
+		if len(anchor) != 8 {
+			return &format, errors.New("parameter 'anchor' is invalid")
+		}
+		for _, val := range anchor {
+			if _, err := strconv.Atoi(strings.TrimSpace(val)); err != nil {
+				return &format, errors.New("parameter 'anchor' is invalid")
+			}
+		}
+	}
+	return &format, nil
 }
 
 // GetComments retrieves all comments and returns a map of worksheet name to
-// the worksheet comments.
+// the worksheet comments. Each comment contains the author, the text and the
+// rich text runs with the formatting of the comment, the anchor and the
+// visibility of the note shape of the comment.
 func (f *File) GetComments() (comments map[string][]Comment) {
 	comments = map[string][]Comment{}
 	for n, path := range f.sheetMap {
-		if d := f.commentsReader("xl" + strings.TrimPrefix(f.getSheetComments(filepath.Base(path)), "..")); d != nil {
+		if d, _ := f.commentsReader("xl" + strings.TrimPrefix(f.getSheetComments(filepath.Base(path)), "..")); d != nil {
 			sheetComments := []Comment{}
+			notes := f.getCommentNotes("xl" + strings.TrimPrefix(f.getSheetDrawingVML(filepath.Base(path)), ".."))
 			for _, comment := range d.CommentList.Comment {
 				sheetComment := Comment{}
 				if comment.AuthorID < len(d.Authors) {
@@ -50,10 +69,17 @@ func (f *File) GetComments() (comments map[string][]Comment) {
 				sheetComment.AuthorID = comment.AuthorID
 				if comment.Text.T != nil {
 					sheetComment.Text += *comment.Text.T
+					sheetComment.Runs = append(sheetComment.Runs, RichTextRun{Text: *comment.Text.T})
 				}
 				for _, text := range comment.Text.R {
 					if text.T != nil {
 						sheetComment.Text += text.T.Val
+						sheetComment.Runs = append(sheetComment.Runs, RichTextRun{Font: getRichTextRunFont(text.RPr), Text: text.T.Val})
+					}
+				}
+				if col, row, err := CellNameToCoordinates(comment.Ref); err == nil {
+					if note, ok := notes[[2]int{col - 1, row - 1}]; ok {
+						sheetComment.Anchor, sheetComment.Visible = note.Anchor, note.Visible
 					}
 				}
 				sheetComments = append(sheetComments, sheetComment)
@@ -64,11 +90,80 @@ func (f *File) GetComments() (comments map[string][]Comment) {
 	return
 }
 
+// getRichTextRunFont provides a function to get the font settings of the
+// rich text run by given run properties.
+func getRichTextRunFont(rPr *xlsxRPr) *Font {
+	if rPr == nil {
+		return nil
+	}
+	fnt := &Font{Bold: rPr.B != nil, Italic: rPr.I != nil, Strike: rPr.Strike != nil}
+	if rPr.U != nil {
+		fnt.Underline = "single"
+		if rPr.U.Val != nil {
+			fnt.Underline = *rPr.U.Val
+		}
+	}
+	if rPr.RFont != nil && rPr.RFont.Val != nil {
+		fnt.Family = *rPr.RFont.Val
+	}
+	if rPr.Sz != nil && rPr.Sz.Val != nil {
+		fnt.Size = *rPr.Sz.Val
+	}
+	if rPr.Color != nil && len(rPr.Color.RGB) == 8 {
+		fnt.Color = "#" + rPr.Color.RGB[2:]
+	}
+	return fnt
+}
+
+// getCommentNotes provides a function to get the anchor and the visibility
+// of the note shapes by given VML drawing path, and returns a map of the
+// zero-based column and row number to the note shape.
+func (f *File) getCommentNotes(drawingVML string) map[[2]int]Comment {
+	notes := map[[2]int]Comment{}
+	var shapes []decodeShape
+	if vml := f.VMLDrawing[drawingVML]; vml != nil {
+		for _, shape := range vml.Shape {
+			shapes = append(shapes, decodeShape{Style: shape.Style, Val: shape.Val})
+		}
+	} else if d, _ := f.decodeVMLDrawingReader(drawingVML); d != nil {
+		shapes = d.Shape
+	}
+	for _, shape := range shapes {
+		shapeVal := new(decodeShapeVal)
+		if err := f.xmlNewDecoder(strings.NewReader("<shape>" + shape.Val + "</shape>")).
+			Decode(shapeVal); err != nil && err != io.EOF {
+			continue
+		}
+		if shapeVal.ClientData.ObjectType != "Note" {
+			continue
+		}
+		notes[[2]int{shapeVal.ClientData.Column, shapeVal.ClientData.Row}] = Comment{
+			Anchor:  strings.TrimSpace(shapeVal.ClientData.Anchor),
+			Visible: shapeVal.ClientData.Visible != nil || strings.Contains(strings.Replace(shape.Style, " ", "", -1), "visibility:visible"),
+		}
+	}
+	return notes
+}
+
+// getSheetDrawingVML provides the method to get the target VML drawing
+// reference by given worksheet file path.
+func (f *File) getSheetDrawingVML(sheetFile string) string {
+	var rels = "xl/worksheets/_rels/" + sheetFile + ".rels"
+	if sheetRels, _ := f.relsReader(rels); sheetRels != nil {
+		for _, v := range sheetRels.Relationships {
+			if v.Type == SourceRelationshipDrawingVML {
+				return v.Target
+			}
+		}
+	}
+	return ""
+}
+
 // getSheetComments provides the method to get the target comment reference by
 // given worksheet file path.
 func (f *File) getSheetComments(sheetFile string) string {
 	var rels = "xl/worksheets/_rels/" + sheetFile + ".rels"
-	if sheetRels := f.relsReader(rels); sheetRels != nil {
+	if sheetRels, _ := f.relsReader(rels); sheetRels != nil {
 		for _, v := range sheetRels.Relationships {
 			if v.Type == SourceRelationshipComments {
 				return v.Target
@@ -85,6 +180,23 @@ func (f *File) getSheetComments(sheetFile string) string {
 //
 //    err := f.AddComment("Sheet1", "A30", `{"author":"Excelize: ","text":"This is a comment."}`)
 //
+// The note box of the comment can be customized by the following optional
+// format settings:
+//
+//    width      | The width of the note box in pixels.
+//    height     | The height of the note box in pixels.
+//    anchor     | The anchor of the note box in the form of "LeftColumn,
+//               | LeftOffset, TopRow, TopOffset, RightColumn, RightOffset,
+//               | BottomRow, BottomOffset", such as the anchor returned by
+//               | GetComments, it has higher priority than width and height.
+//    visible    | Show the note box by default.
+//    fill_color | The fill color of the note box, such as #FFFFCC.
+//
+// For example, add a comment in Sheet1!$B2 which shown by default with a
+// 200 x 100 pixels light yellow note box:
+//
+//    err := f.AddComment("Sheet1", "B2", `{"author":"Excelize: ","text":"This is a comment.","width":200,"height":100,"visible":true,"fill_color":"#FFFFCC"}`)
+//
 func (f *File) AddComment(sheet, cell, format string) error {
 	formatSet, err := parseFormatCommentsSet(format)
 	if err != nil {
@@ -95,24 +207,37 @@ func (f *File) AddComment(sheet, cell, format string) error {
 	if err != nil {
 		return err
 	}
-	commentID := f.countComments() + 1
-	drawingVML := "xl/drawings/vmlDrawing" + strconv.Itoa(commentID) + ".vml"
+	if _, _, err = CellNameToCoordinates(cell); err != nil {
+		return err
+	}
+	commentID := f.getNextPartID("xl/comments", ".xml")
+	drawingID := f.getNextPartID("xl/drawings/vmlDrawing", ".vml")
+	drawingVML := "xl/drawings/vmlDrawing" + strconv.Itoa(drawingID) + ".vml"
 	sheetRelationshipsComments := "../comments" + strconv.Itoa(commentID) + ".xml"
-	sheetRelationshipsDrawingVML := "../drawings/vmlDrawing" + strconv.Itoa(commentID) + ".vml"
+	sheetRelationshipsDrawingVML := "../drawings/vmlDrawing" + strconv.Itoa(drawingID) + ".vml"
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+	var target string
 	if ws.LegacyDrawing != nil {
-		// The worksheet already has a comments relationships, use the relationships drawing ../drawings/vmlDrawing%d.vml.
-		sheetRelationshipsDrawingVML = f.getSheetRelationshipsTargetByID(sheet, ws.LegacyDrawing.RID)
-		commentID, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(sheetRelationshipsDrawingVML, "../drawings/vmlDrawing"), ".vml"))
+		target = f.getSheetRelationshipsTargetByID(sheet, ws.LegacyDrawing.RID)
+	}
+	if target != "" {
+		// The worksheet already has a legacy drawing, such as comments or
+		// form controls, add the comment into the existing VML drawing.
+		sheetRelationshipsDrawingVML = target
 		drawingVML = strings.Replace(sheetRelationshipsDrawingVML, "..", "xl", -1)
 	} else {
 		// Add first comment for given sheet.
-		sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
 		rID := f.addRels(sheetRels, SourceRelationshipDrawingVML, sheetRelationshipsDrawingVML, "")
-		f.addRels(sheetRels, SourceRelationshipComments, sheetRelationshipsComments, "")
 		f.addSheetNameSpace(sheet, SourceRelationship)
 		f.addSheetLegacyDrawing(sheet, rID)
 	}
-	commentsXML := "xl/comments" + strconv.Itoa(commentID) + ".xml"
+	if target := f.getSheetComments(filepath.Base(f.sheetMap[trimSheetName(sheet)])); target != "" {
+		sheetRelationshipsComments = target
+	} else {
+		f.addRels(sheetRels, SourceRelationshipComments, sheetRelationshipsComments, "")
+		f.addContentTypePart(commentID, "comments")
+	}
+	commentsXML := strings.Replace(sheetRelationshipsComments, "..", "xl", -1)
 	var colCount int
 	for i, l := range strings.Split(formatSet.Text, "\n") {
 		if ll := len(l); ll > colCount {
@@ -122,63 +247,140 @@ func (f *File) AddComment(sheet, cell, format string) error {
 			colCount = ll
 		}
 	}
-	err = f.addDrawingVML(commentID, drawingVML, cell, strings.Count(formatSet.Text, "\n")+1, colCount)
+	err = f.addDrawingVML(drawingID, drawingVML, sheet, cell, strings.Count(formatSet.Text, "\n")+1, colCount, formatSet)
 	if err != nil {
 		return err
 	}
-	f.addComment(commentsXML, cell, formatSet)
-	f.addContentTypePart(commentID, "comments")
-	return err
+	return f.addComment(commentsXML, cell, formatSet)
+}
+
+// SetComment provides the method to set comment in a sheet by given worksheet
+// name, cell and format set (such as author and text). The existing comment
+// of the cell will be replaced, and the comment will be added if the cell has
+// no comment. For example, change the comment in Sheet1!$A$30:
+//
+//    err := f.SetComment("Sheet1", "A30", `{"author":"Excelize: ","text":"This is a new comment."}`)
+//
+func (f *File) SetComment(sheet, cell, format string) error {
+	if _, err := parseFormatCommentsSet(format); err != nil {
+		return err
+	}
+	if err := f.DeleteComment(sheet, cell); err != nil {
+		return err
+	}
+	return f.AddComment(sheet, cell, format)
+}
+
+// DeleteComment provides the method to delete comment in a sheet by given
+// worksheet name and cell, the comment and the note shape of the cell will
+// be removed. For example, delete the comment in Sheet1!$A$30:
+//
+//    err := f.DeleteComment("Sheet1", "A30")
+//
+func (f *File) DeleteComment(sheet, cell string) error {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if target := f.getSheetComments(filepath.Base(f.sheetMap[trimSheetName(sheet)])); target != "" {
+		comments, err := f.commentsReader("xl" + strings.TrimPrefix(target, ".."))
+		if err != nil {
+			return err
+		}
+		if comments != nil {
+			commentList := comments.CommentList.Comment[:0]
+			for _, cmt := range comments.CommentList.Comment {
+				if c, r, err := CellNameToCoordinates(cmt.Ref); err == nil && c == col && r == row {
+					continue
+				}
+				commentList = append(commentList, cmt)
+			}
+			comments.CommentList.Comment = commentList
+		}
+	}
+	if ws.LegacyDrawing == nil {
+		return nil
+	}
+	sheetRelationshipsDrawingVML := f.getSheetRelationshipsTargetByID(sheet, ws.LegacyDrawing.RID)
+	drawingVML := strings.Replace(sheetRelationshipsDrawingVML, "..", "xl", -1)
+	if f.VMLDrawing[drawingVML] == nil {
+		d, err := f.decodeVMLDrawingReader(drawingVML)
+		if err != nil || d == nil {
+			return err
+		}
+	}
+	commentID, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(sheetRelationshipsDrawingVML, "../drawings/vmlDrawing"), ".vml"))
+	vml, err := f.vmlDrawingReader(drawingVML, commentID)
+	if err != nil {
+		return err
+	}
+	shapes := vml.Shape[:0]
+	for _, shape := range vml.Shape {
+		clientData := new(decodeShapeVal)
+		if err = f.xmlNewDecoder(strings.NewReader("<shape>" + shape.Val + "</shape>")).
+			Decode(clientData); err != nil && err != io.EOF {
+			return err
+		}
+		if clientData.ClientData.ObjectType == "Note" &&
+			clientData.ClientData.Column == col-1 && clientData.ClientData.Row == row-1 {
+			continue
+		}
+		shapes = append(shapes, shape)
+	}
+	vml.Shape = shapes
+	f.VMLDrawing[drawingVML] = vml
+	return nil
 }
 
 // addDrawingVML provides a function to create comment as
-// xl/drawings/vmlDrawing%d.vml by given commit ID and cell.
-func (f *File) addDrawingVML(commentID int, drawingVML, cell string, lineCount, colCount int) error {
+// xl/drawings/vmlDrawing%d.vml by given commit ID, cell and format sets.
+func (f *File) addDrawingVML(commentID int, drawingVML, sheet, cell string, lineCount, colCount int, formatSet *formatComment) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
 	}
 	yAxis := col - 1
 	xAxis := row - 1
-	vml := f.VMLDrawing[drawingVML]
-	if vml == nil {
-		vml = &vmlDrawing{
-			XMLNSv:  "urn:schemas-microsoft-com:vml",
-			XMLNSo:  "urn:schemas-microsoft-com:office:office",
-			XMLNSx:  "urn:schemas-microsoft-com:office:excel",
-			XMLNSmv: "http://macVmlSchemaUri",
-			Shapelayout: &xlsxShapelayout{
-				Ext: "edit",
-				IDmap: &xlsxIDmap{
-					Ext:  "edit",
-					Data: commentID,
-				},
-			},
-			Shapetype: &xlsxShapetype{
-				ID:        "_x0000_t202",
-				Coordsize: "21600,21600",
-				Spt:       202,
-				Path:      "m0,0l0,21600,21600,21600,21600,0xe",
-				Stroke: &xlsxStroke{
-					Joinstyle: "miter",
-				},
-				VPath: &vPath{
-					Gradientshapeok: "t",
-					Connecttype:     "rect",
-				},
-			},
+	vml, err := f.vmlDrawingReader(drawingVML, commentID)
+	if err != nil {
+		return err
+	}
+	anchor := fmt.Sprintf("%d, 23, %d, 0, %d, %d, %d, 5",
+		1+yAxis, 1+xAxis, 2+yAxis+lineCount, colCount+yAxis, 2+xAxis+lineCount)
+	width, height := 144, 79
+	if formatSet.Width > 0 || formatSet.Height > 0 {
+		if formatSet.Width > 0 {
+			width = formatSet.Width
 		}
+		if formatSet.Height > 0 {
+			height = formatSet.Height
+		}
+		colStart, rowStart, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, 1+yAxis, 1+xAxis, 23, 0, width, height)
+		anchor = fmt.Sprintf("%d, 23, %d, 0, %d, %d, %d, %d", colStart, rowStart, colEnd, x2, rowEnd, y2)
 	}
-	sp := encodeShape{
-		Fill: &vFill{
-			Color2: "#fbfe82",
-			Angle:  -180,
-			Type:   "gradient",
-			Fill: &oFill{
-				Ext:  "view",
-				Type: "gradientUnscaled",
-			},
+	if formatSet.Anchor != "" {
+		anchor = formatSet.Anchor
+	}
+	fill, fillColor := &vFill{
+		Color2: "#fbfe82",
+		Angle:  -180,
+		Type:   "gradient",
+		Fill: &oFill{
+			Ext:  "view",
+			Type: "gradientUnscaled",
 		},
+	}, "#fbf6d6"
+	if formatSet.FillColor != "" {
+		fillColor = formatSet.FillColor
+		fill = &vFill{Color2: formatSet.FillColor}
+	}
+	visibility := "hidden"
+	sp := encodeShape{
+		Fill: fill,
 		Shadow: &vShadow{
 			On:       "t",
 			Color:    "black",
@@ -195,45 +397,176 @@ func (f *File) addDrawingVML(commentID int, drawingVML, cell string, lineCount,
 		},
 		ClientData: &xClientData{
 			ObjectType: "Note",
-			Anchor: fmt.Sprintf(
-				"%d, 23, %d, 0, %d, %d, %d, 5",
-				1+yAxis, 1+xAxis, 2+yAxis+lineCount, colCount+yAxis, 2+xAxis+lineCount),
-			AutoFill: "True",
-			Row:      xAxis,
-			Column:   yAxis,
+			Anchor:     anchor,
+			AutoFill:   "True",
+			Row:        xAxis,
+			Column:     yAxis,
 		},
 	}
+	if formatSet.Visible {
+		visibility, sp.ClientData.Visible = "visible", stringPtr("")
+	}
 	s, _ := xml.Marshal(sp)
 	shape := xlsxShape{
-		ID:          "_x0000_s1025",
+		ID:          nextVMLShapeID(vml),
 		Type:        "#_x0000_t202",
-		Style:       "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden",
-		Fillcolor:   "#fbf6d6",
+		Style:       fmt.Sprintf("position:absolute;73.5pt;width:%gpt;height:%gpt;z-index:1;visibility:%s", float64(width)*0.75, float64(height)*0.75, visibility),
+		Fillcolor:   fillColor,
 		Strokecolor: "#edeaa1",
 		Val:         string(s[13 : len(s)-14]),
 	}
-	d := f.decodeVMLDrawingReader(drawingVML)
-	if d != nil {
-		for _, v := range d.Shape {
-			s := xlsxShape{
-				ID:          "_x0000_s1025",
-				Type:        "#_x0000_t202",
-				Style:       "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden",
-				Fillcolor:   "#fbf6d6",
-				Strokecolor: "#edeaa1",
-				Val:         v.Val,
-			}
-			vml.Shape = append(vml.Shape, s)
-		}
-	}
 	vml.Shape = append(vml.Shape, shape)
 	f.VMLDrawing[drawingVML] = vml
 	return err
 }
 
+// vmlDrawingReader provides a function to get the pointer to the structure
+// of the VML drawing by given VML drawing path and comment ID. The shapes in
+// the existing VML drawing part will be loaded only once, so that they
+// wouldn't be duplicated when adding multiple comments.
+func (f *File) vmlDrawingReader(drawingVML string, commentID int) (*vmlDrawing, error) {
+	if vml := f.VMLDrawing[drawingVML]; vml != nil {
+		return vml, nil
+	}
+	vml := &vmlDrawing{
+		XMLNSv:  "urn:schemas-microsoft-com:vml",
+		XMLNSo:  "urn:schemas-microsoft-com:office:office",
+		XMLNSx:  "urn:schemas-microsoft-com:office:excel",
+		XMLNSmv: "http://macVmlSchemaUri",
+		Shapelayout: &xlsxShapelayout{
+			Ext: "edit",
+			IDmap: &xlsxIDmap{
+				Ext:  "edit",
+				Data: commentID,
+			},
+		},
+		Shapetype: &xlsxShapetype{
+			ID:        "_x0000_t202",
+			Coordsize: "21600,21600",
+			Spt:       202,
+			Path:      "m0,0l0,21600,21600,21600,21600,0xe",
+			Stroke: &xlsxStroke{
+				Joinstyle: "miter",
+			},
+			VPath: &vPath{
+				Gradientshapeok: "t",
+				Connecttype:     "rect",
+			},
+		},
+	}
+	if content, ok := f.XLSX[drawingVML]; ok {
+		if err := parseVMLDrawing(vml, namespaceStrictToTransitional(content)); err != nil {
+			return vml, f.newXMLDecodeError(drawingVML, err)
+		}
+	}
+	return vml, nil
+}
+
+// parseVMLDrawing provides a function to parse the existing VML drawing part
+// by given content. The original root start tag, the elements except the
+// shapes, and each shape will be kept as they are, and the default shape
+// layout and comment shape type will be used only when they don't exist in
+// the VML drawing part.
+func parseVMLDrawing(vml *vmlDrawing, content []byte) error {
+	var (
+		head, elements bytes.Buffer
+		shapes         []xlsxShape
+		root           bool
+		d              = xml.NewDecoder(bytes.NewReader(content))
+	)
+	for {
+		offset := d.InputOffset()
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !root {
+			root = true
+			head.Write(content[:d.InputOffset()])
+			continue
+		}
+		if start.Name.Local == "shape" {
+			var shape decodeShape
+			if err = d.DecodeElement(&shape, &start); err != nil {
+				return err
+			}
+			shapes = append(shapes, xlsxShape{ID: shape.ID, Style: shape.Style, Val: shape.Val, Raw: string(content[offset:d.InputOffset()])})
+			continue
+		}
+		if err = d.Skip(); err != nil {
+			return err
+		}
+		elements.Write(content[offset:d.InputOffset()])
+	}
+	if !root {
+		return nil
+	}
+	vml.Head, vml.Content, vml.Shape = head.String(), elements.String(), shapes
+	if strings.Contains(vml.Content, "shapelayout") {
+		vml.Shapelayout = nil
+	}
+	if strings.Contains(vml.Content, vml.Shapetype.ID) {
+		vml.Shapetype = nil
+	}
+	return nil
+}
+
+// encodeVMLDrawing provides a function to serialize the VML drawing, the
+// original root start tag, elements and shapes of the existing VML drawing
+// part will be written as they are.
+func encodeVMLDrawing(vml *vmlDrawing) []byte {
+	if vml.Head == "" {
+		v, _ := xml.Marshal(vml)
+		return v
+	}
+	var buf bytes.Buffer
+	buf.WriteString(vml.Head)
+	e := xml.NewEncoder(&buf)
+	if vml.Shapelayout != nil {
+		_ = e.EncodeElement(vml.Shapelayout, xml.StartElement{Name: xml.Name{Local: "o:shapelayout"}})
+	}
+	_ = e.Flush()
+	buf.WriteString(vml.Content)
+	if vml.Shapetype != nil {
+		_ = e.EncodeElement(vml.Shapetype, xml.StartElement{Name: xml.Name{Local: "v:shapetype"}})
+	}
+	for _, shape := range vml.Shape {
+		if _ = e.Flush(); shape.Raw != "" {
+			buf.WriteString(shape.Raw)
+			continue
+		}
+		_ = e.Encode(shape)
+	}
+	_ = e.Flush()
+	buf.WriteString("</xml>")
+	return buf.Bytes()
+}
+
+// nextVMLShapeID provides a function to get the ID of the new shape in the
+// VML drawing, which is greater than the IDs of the existing shapes.
+func nextVMLShapeID(vml *vmlDrawing) string {
+	ID := 1025
+	if vml.Shapelayout != nil && vml.Shapelayout.IDmap != nil {
+		ID = vml.Shapelayout.IDmap.Data*1024 + 1
+	}
+	for _, shape := range vml.Shape {
+		if n, err := strconv.Atoi(strings.TrimPrefix(shape.ID, "_x0000_s")); err == nil && n >= ID {
+			ID = n + 1
+		}
+	}
+	return "_x0000_s" + strconv.Itoa(ID)
+}
+
 // addComment provides a function to create chart as xl/comments%d.xml by
 // given cell and format sets.
-func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
+func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) error {
 	a := formatSet.Author
 	t := formatSet.Text
 	if len(a) > 255 {
@@ -242,25 +575,33 @@ func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
 	if len(t) > 32512 {
 		t = t[0:32512]
 	}
-	comments := f.commentsReader(commentsXML)
+	comments, err := f.commentsReader(commentsXML)
+	if err != nil {
+		return err
+	}
 	if comments == nil {
-		comments = &xlsxComments{
-			Authors: []xlsxAuthor{
-				{
-					Author: formatSet.Author,
-				},
-			},
+		comments = &xlsxComments{}
+	}
+	authorID := -1
+	for idx, author := range comments.Authors {
+		if author.Author == formatSet.Author {
+			authorID = idx
+			break
 		}
 	}
+	if authorID == -1 {
+		comments.Authors = append(comments.Authors, xlsxAuthor{Author: formatSet.Author})
+		authorID = len(comments.Authors) - 1
+	}
 	defaultFont := f.GetDefaultFont()
 	cmt := xlsxComment{
 		Ref:      cell,
-		AuthorID: 0,
+		AuthorID: authorID,
 		Text: xlsxText{
 			R: []xlsxR{
 				{
 					RPr: &xlsxRPr{
-						B:  " ",
+						B:  stringPtr(""),
 						Sz: &attrValFloat{Val: float64Ptr(9)},
 						Color: &xlsxColor{
 							Indexed: 81,
@@ -286,6 +627,7 @@ func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
 	}
 	comments.CommentList.Comment = append(comments.CommentList.Comment, cmt)
 	f.Comments[commentsXML] = comments
+	return nil
 }
 
 // countComments provides a function to get comments files count storage in
@@ -310,20 +652,18 @@ func (f *File) countComments() int {
 
 // decodeVMLDrawingReader provides a function to get the pointer to the
 // structure after deserialization of xl/drawings/vmlDrawing%d.xml.
-func (f *File) decodeVMLDrawingReader(path string) *decodeVmlDrawing {
-	var err error
-
+func (f *File) decodeVMLDrawingReader(path string) (*decodeVmlDrawing, error) {
 	if f.DecodeVMLDrawing[path] == nil {
 		c, ok := f.XLSX[path]
 		if ok {
 			f.DecodeVMLDrawing[path] = new(decodeVmlDrawing)
-			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(c))).
+			if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(c))).
 				Decode(f.DecodeVMLDrawing[path]); err != nil && err != io.EOF {
-				log.Printf("xml decode error: %s", err)
+				return f.DecodeVMLDrawing[path], f.newXMLDecodeError(path, err)
 			}
 		}
 	}
-	return f.DecodeVMLDrawing[path]
+	return f.DecodeVMLDrawing[path], nil
 }
 
 // vmlDrawingWriter provides a function to save xl/drawings/vmlDrawing%d.xml
@@ -331,28 +671,25 @@ func (f *File) decodeVMLDrawingReader(path string) *decodeVmlDrawing {
 func (f *File) vmlDrawingWriter() {
 	for path, vml := range f.VMLDrawing {
 		if vml != nil {
-			v, _ := xml.Marshal(vml)
-			f.XLSX[path] = v
+			f.XLSX[path] = encodeVMLDrawing(vml)
 		}
 	}
 }
 
 // commentsReader provides a function to get the pointer to the structure
 // after deserialization of xl/comments%d.xml.
-func (f *File) commentsReader(path string) *xlsxComments {
-	var err error
-
+func (f *File) commentsReader(path string) (*xlsxComments, error) {
 	if f.Comments[path] == nil {
 		content, ok := f.XLSX[path]
 		if ok {
 			f.Comments[path] = new(xlsxComments)
-			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+			if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
 				Decode(f.Comments[path]); err != nil && err != io.EOF {
-				log.Printf("xml decode error: %s", err)
+				return f.Comments[path], f.newXMLDecodeError(path, err)
 			}
 		}
 	}
-	return f.Comments[path]
+	return f.Comments[path], nil
 }
 
 // commentsWriter provides a function to save xl/comments%d.xml after
@@ -61,3 +61,241 @@ func TestCountComments(t *testing.T) {
 	f.Comments["xl/comments1.xml"] = nil
 	assert.Equal(t, f.countComments(), 1)
 }
+
+func TestDeleteComment(t *testing.T) {
+	f, err := prepareTestBook1()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, f.AddComment("Sheet2", "A40", `{"author":"Excelize: ","text":"This is a comment1."}`))
+	assert.NoError(t, f.AddComment("Sheet2", "A41", `{"author":"Excelize: ","text":"This is a comment2."}`))
+	assert.NoError(t, f.AddComment("Sheet2", "C41", `{"author":"Excelize: ","text":"This is a comment3."}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteComment.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestDeleteComment.xlsx"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, f.DeleteComment("Sheet2", "A40"))
+	comments := f.GetComments()
+	assert.EqualValues(t, 2, len(comments["Sheet2"]))
+	assert.Len(t, f.VMLDrawing["xl/drawings/vmlDrawing2.vml"].Shape, 2)
+	assert.NoError(t, f.DeleteComment("Sheet2", "A41"))
+	assert.NoError(t, f.DeleteComment("Sheet2", "C41"))
+	assert.EqualValues(t, 0, len(f.GetComments()["Sheet2"]))
+	assert.Len(t, f.VMLDrawing["xl/drawings/vmlDrawing2.vml"].Shape, 0)
+	// Test delete comment on the cell without comment.
+	assert.NoError(t, f.DeleteComment("Sheet2", "A42"))
+	assert.NoError(t, f.DeleteComment("Sheet1", "A1"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteComment.xlsx")))
+
+	// Test delete comment on not exists worksheet.
+	assert.EqualError(t, f.DeleteComment("SheetN", "A1"), "sheet SheetN is not exist")
+	// Test delete comment with illegal cell coordinates.
+	assert.EqualError(t, f.DeleteComment("Sheet1", "A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test delete comment with invalid VML shape.
+	assert.NoError(t, f.AddComment("Sheet2", "A40", `{"author":"Excelize: ","text":"This is a comment1."}`))
+	f.VMLDrawing["xl/drawings/vmlDrawing2.vml"].Shape[0].Val = "<x:ClientData>"
+	assert.EqualError(t, f.DeleteComment("Sheet2", "A40"), "XML syntax error on line 1: element <ClientData> closed by </shape>")
+}
+
+func TestSetComment(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.SetComment("Sheet1", "A1", `{"author":"Author: ","text":"This is a new comment."}`))
+	assert.NoError(t, f.SetComment("Sheet1", "B2", `{"author":"Excelize: ","text":"This is a comment."}`))
+	comments := f.GetComments()["Sheet1"]
+	if assert.Len(t, comments, 2) {
+		assert.Equal(t, "A1", comments[0].Ref)
+		assert.Equal(t, "Author: ", comments[0].Author)
+		assert.Equal(t, "Author: This is a new comment.", comments[0].Text)
+		assert.Equal(t, "B2", comments[1].Ref)
+		assert.Equal(t, "Excelize: ", comments[1].Author)
+	}
+	assert.Len(t, f.VMLDrawing["xl/drawings/vmlDrawing1.vml"].Shape, 2)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetComment.xlsx")))
+
+	// Test set comment with invalid format set.
+	assert.EqualError(t, f.SetComment("Sheet1", "A1", "-"), "unexpected end of JSON input")
+	// Test set comment on not exists worksheet.
+	assert.EqualError(t, f.SetComment("SheetN", "A1", `{"author":"Excelize: ","text":"This is a comment."}`), "sheet SheetN is not exist")
+}
+
+func TestGetComments(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", "B2", `{"author":"Excelize: ","text":"This is a comment."}`))
+	comments := f.GetComments()["Sheet1"]
+	if assert.Len(t, comments, 1) {
+		assert.Equal(t, "Excelize: This is a comment.", comments[0].Text)
+		assert.Equal(t, []RichTextRun{
+			{Font: &Font{Bold: true, Family: "Calibri", Size: 9}, Text: "Excelize: "},
+			{Font: &Font{Family: "Calibri", Size: 9}, Text: "This is a comment."},
+		}, comments[0].Runs)
+		assert.Equal(t, "2, 23, 2, 0, 4, 29, 4, 5", comments[0].Anchor)
+		assert.False(t, comments[0].Visible)
+	}
+
+	// Test get comments with rich text and visible note shape in the file.
+	f.Comments["xl/comments1.xml"] = nil
+	f.XLSX["xl/comments1.xml"] = []byte(`<comments xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><authors><author>Excelize</author></authors><commentList><comment ref="B2" authorId="0"><text><r><rPr><b/><i/><strike/><u/><sz val="10"/><color rgb="FFFF0000"/><rFont val="Arial"/></rPr><t>Bold</t></r><r><t>Plain</t></r></text></comment><comment ref="C3" authorId="1"><text><t>Text</t></text></comment></commentList></comments>`)
+	f.VMLDrawing["xl/drawings/vmlDrawing1.vml"] = nil
+	f.XLSX["xl/drawings/vmlDrawing1.vml"] = []byte(`<xml xmlns:v="urn:schemas-microsoft-com:vml" xmlns:x="urn:schemas-microsoft-com:office:excel"><v:shape style="visibility:hidden"><x:ClientData ObjectType="Note"><x:Visible/><x:Anchor>
+    2, 15, 0, 2, 4, 15, 4, 16</x:Anchor><x:Row>1</x:Row><x:Column>1</x:Column></x:ClientData></v:shape><v:shape style="visibility: visible"><x:ClientData ObjectType="Note"><x:Row>2</x:Row><x:Column>2</x:Column></x:ClientData></v:shape><v:shape><x:ClientData ObjectType="Button"><x:Row>3</x:Row><x:Column>3</x:Column></x:ClientData></v:shape></xml>`)
+	comments = f.GetComments()["Sheet1"]
+	if assert.Len(t, comments, 2) {
+		assert.Equal(t, "BoldPlain", comments[0].Text)
+		assert.Equal(t, []RichTextRun{
+			{Font: &Font{Bold: true, Italic: true, Strike: true, Underline: "single", Family: "Arial", Size: 10, Color: "#FF0000"}, Text: "Bold"},
+			{Text: "Plain"},
+		}, comments[0].Runs)
+		assert.Equal(t, "2, 15, 0, 2, 4, 15, 4, 16", comments[0].Anchor)
+		assert.True(t, comments[0].Visible)
+		assert.Empty(t, comments[1].Author)
+		assert.Equal(t, []RichTextRun{{Text: "Text"}}, comments[1].Runs)
+		assert.True(t, comments[1].Visible)
+	}
+	// Test get comments with invalid note shape.
+	f.VMLDrawing["xl/drawings/vmlDrawing1.vml"] = &vmlDrawing{Shape: []xlsxShape{{Val: "<x:ClientData>"}}}
+	comments = f.GetComments()["Sheet1"]
+	if assert.Len(t, comments, 2) {
+		assert.Empty(t, comments[0].Anchor)
+		assert.False(t, comments[0].Visible)
+	}
+}
+
+func TestAddCommentNoteBox(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", "B2", `{"author":"Excelize: ","text":"This is a comment.","width":200,"height":100,"visible":true,"fill_color":"#FFFFCC"}`))
+	assert.NoError(t, f.AddComment("Sheet1", "D4", `{"author":"Excelize: ","text":"This is a comment.","width":50}`))
+	assert.NoError(t, f.AddComment("Sheet1", "F6", `{"author":"Excelize: ","text":"This is a comment.","anchor":"6, 15, 4, 2, 8, 15, 8, 16","width":50}`))
+	vml := f.VMLDrawing["xl/drawings/vmlDrawing1.vml"]
+	if assert.Len(t, vml.Shape, 3) {
+		assert.Equal(t, "position:absolute;73.5pt;width:150pt;height:75pt;z-index:1;visibility:visible", vml.Shape[0].Style)
+		assert.Equal(t, "#FFFFCC", vml.Shape[0].Fillcolor)
+		assert.Contains(t, vml.Shape[0].Val, `<v:fill color2="#FFFFCC"></v:fill>`)
+		assert.Equal(t, "position:absolute;73.5pt;width:37.5pt;height:59.25pt;z-index:1;visibility:hidden", vml.Shape[1].Style)
+		assert.Equal(t, "#fbf6d6", vml.Shape[1].Fillcolor)
+	}
+	comments := f.GetComments()["Sheet1"]
+	if assert.Len(t, comments, 3) {
+		assert.Equal(t, "2, 23, 2, 0, 5, 31, 7, 0", comments[0].Anchor)
+		assert.True(t, comments[0].Visible)
+		assert.Equal(t, "4, 23, 4, 0, 5, 9, 7, 19", comments[1].Anchor)
+		assert.False(t, comments[1].Visible)
+		assert.Equal(t, "6, 15, 4, 2, 8, 15, 8, 16", comments[2].Anchor)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddCommentNoteBox.xlsx")))
+
+	// Test add comment with invalid note box settings.
+	assert.EqualError(t, f.AddComment("Sheet1", "A1", `{"width":-1}`), "parameter 'width' and 'height' must be greater than or equal to 0")
+	assert.EqualError(t, f.AddComment("Sheet1", "A1", `{"anchor":"1, 2"}`), "parameter 'anchor' is invalid")
+	assert.EqualError(t, f.AddComment("Sheet1", "A1", `{"anchor":"1, 2, 3, 4, 5, 6, 7, A"}`), "parameter 'anchor' is invalid")
+}
+
+func TestAddCommentWithLegacyDrawing(t *testing.T) {
+	f := NewFile()
+	// Prepare a worksheet with a form control in the VML drawing part.
+	_, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	sheetRels := "xl/worksheets/_rels/sheet1.xml.rels"
+	rID := f.addRels(sheetRels, SourceRelationshipDrawingVML, "../drawings/vmlDrawing1.vml", "")
+	f.addSheetNameSpace("Sheet1", SourceRelationship)
+	f.addSheetLegacyDrawing("Sheet1", rID)
+	vml := `<xml xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office" xmlns:x="urn:schemas-microsoft-com:office:excel"><o:shapelayout v:ext="edit"><o:idmap v:ext="edit" data="1"/></o:shapelayout><v:shapetype id="_x0000_t201" coordsize="21600,21600" o:spt="201" path="m,l,21600r21600,l21600,xe"><v:stroke joinstyle="miter"/><v:path shadowok="f" o:extrusionok="f" strokeok="f" fillok="f" o:connecttype="rect"/><o:lock v:ext="edit" shapetype="t"/></v:shapetype><v:shape id="_x0000_s1026" type="#_x0000_t201" style="position:absolute;margin-left:48pt;margin-top:15pt;width:48pt;height:15pt;z-index:1" o:button="t" fillcolor="buttonFace [67]" strokecolor="windowText [64]" o:insetmode="auto"><v:fill color2="buttonFace [67]" o:detectmouseclick="t"/><o:lock v:ext="edit" rotation="t"/><x:ClientData ObjectType="Button"><x:Anchor>1, 0, 1, 0, 2, 0, 2, 0</x:Anchor><x:PrintObject>False</x:PrintObject><x:AutoFill>False</x:AutoFill><x:FmlaMacro>[0]!Macro1</x:FmlaMacro><x:TextHAlign>Center</x:TextHAlign><x:TextVAlign>Center</x:TextVAlign></x:ClientData></v:shape></xml>`
+	f.XLSX["xl/drawings/vmlDrawing1.vml"] = []byte(vml)
+	assert.NoError(t, f.AddComment("Sheet1", "C3", `{"author":"Excelize: ","text":"This is a comment.","visible":true}`))
+	assert.NoError(t, f.AddComment("Sheet1", "D4", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddCommentWithLegacyDrawing.xlsx")))
+
+	content := string(f.XLSX["xl/drawings/vmlDrawing1.vml"])
+	idx := strings.Index(vml, `<v:shape id="_x0000_s1026"`)
+	assert.True(t, strings.HasPrefix(content, vml[:idx]))
+	assert.Contains(t, content, vml[idx:len(vml)-len("</xml>")])
+	assert.Equal(t, 1, strings.Count(content, "shapelayout>"))
+	assert.Equal(t, 1, strings.Count(content, `<v:shapetype id="_x0000_t202"`))
+	assert.Contains(t, content, `<v:shape id="_x0000_s1027" type="#_x0000_t202"`)
+	assert.Contains(t, content, `<v:shape id="_x0000_s1028" type="#_x0000_t202"`)
+	rels, err := f.relsReader(sheetRels)
+	assert.NoError(t, err)
+	if assert.Len(t, rels.Relationships, 2) {
+		assert.Equal(t, SourceRelationshipComments, rels.Relationships[1].Type)
+		assert.Equal(t, "../comments1.xml", rels.Relationships[1].Target)
+	}
+
+	// Test add comment on the workbook which has existing comments.
+	f, err = OpenFile(filepath.Join("test", "TestAddCommentWithLegacyDrawing.xlsx"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, f.AddComment("Sheet1", "E5", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.AddComment("Sheet1", "F6", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddCommentWithLegacyDrawing.xlsx")))
+	content = string(f.XLSX["xl/drawings/vmlDrawing1.vml"])
+	assert.Equal(t, 1, strings.Count(content, `ObjectType="Button"`))
+	assert.Equal(t, 1, strings.Count(content, `<v:shapetype id="_x0000_t202"`))
+	assert.Contains(t, content, `<v:shape id="_x0000_s1030" type="#_x0000_t202"`)
+	rels, err = f.relsReader(sheetRels)
+	assert.NoError(t, err)
+	assert.Len(t, rels.Relationships, 2)
+	comments := f.GetComments()["Sheet1"]
+	if assert.Len(t, comments, 4) {
+		assert.True(t, comments[0].Visible)
+		assert.False(t, comments[1].Visible)
+		assert.Equal(t, "F6", comments[3].Ref)
+	}
+	assert.NoError(t, f.DeleteComment("Sheet1", "C3"))
+	assert.Len(t, f.VMLDrawing["xl/drawings/vmlDrawing1.vml"].Shape, 4)
+
+	// Test add comment with invalid VML drawing part.
+	for _, content := range []string{`<xml><v:shape>`, `<xml><o:shapelayout>`} {
+		f = NewFile()
+		_, err = f.workSheetReader("Sheet1")
+		assert.NoError(t, err)
+		f.addSheetLegacyDrawing("Sheet1", f.addRels(sheetRels, SourceRelationshipDrawingVML, "../drawings/vmlDrawing1.vml", ""))
+		f.XLSX["xl/drawings/vmlDrawing1.vml"] = []byte(content)
+		err = f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`)
+		decodeErr, ok := err.(ErrXMLDecode)
+		if assert.True(t, ok) {
+			assert.Equal(t, "xl/drawings/vmlDrawing1.vml", decodeErr.Part)
+		}
+		assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: unexpected EOF")
+	}
+	f.XLSX["xl/drawings/vmlDrawing2.vml"] = []byte(``)
+	vmlShapes, err := f.vmlDrawingReader("xl/drawings/vmlDrawing2.vml", 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, vmlShapes)
+}
+
+func TestAddCommentWithOtherLegacyDrawing(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	// Prepare a worksheet with the VML drawing part but without comments.
+	_, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	rID := f.addRels("xl/worksheets/_rels/sheet1.xml.rels", SourceRelationshipDrawingVML, "../drawings/vmlDrawing1.vml", "")
+	f.addSheetNameSpace("Sheet1", SourceRelationship)
+	f.addSheetLegacyDrawing("Sheet1", rID)
+	vml := []byte(`<xml xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office"><o:shapelayout v:ext="edit"><o:idmap v:ext="edit" data="1"/></o:shapelayout></xml>`)
+	f.XLSX["xl/drawings/vmlDrawing1.vml"] = vml
+	assert.NoError(t, f.AddComment("Sheet2", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.Equal(t, vml, f.XLSX["xl/drawings/vmlDrawing1.vml"])
+	assert.Nil(t, f.VMLDrawing["xl/drawings/vmlDrawing1.vml"])
+	assert.NotNil(t, f.VMLDrawing["xl/drawings/vmlDrawing2.vml"])
+	rels, err := f.relsReader("xl/worksheets/_rels/sheet2.xml.rels")
+	assert.NoError(t, err)
+	if assert.Len(t, rels.Relationships, 2) {
+		assert.Equal(t, "../drawings/vmlDrawing2.vml", rels.Relationships[0].Target)
+		assert.Equal(t, "../comments1.xml", rels.Relationships[1].Target)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddCommentWithOtherLegacyDrawing.xlsx")))
+
+	// Test add comment on the worksheet which legacy drawing relationship
+	// doesn't exist.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.LegacyDrawing.RID = "rId100"
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.Equal(t, vml, f.XLSX["xl/drawings/vmlDrawing1.vml"])
+	assert.NotNil(t, f.VMLDrawing["xl/drawings/vmlDrawing3.vml"])
+	assert.Equal(t, "../drawings/vmlDrawing3.vml", f.getSheetRelationshipsTargetByID("Sheet1", ws.LegacyDrawing.RID))
+}
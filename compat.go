@@ -0,0 +1,260 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// CompatibilityProfile defined the profile of the spreadsheet applications
+// which the saved spreadsheet targets. With the CompatibilityExcel, the parts
+// of the spreadsheet will be saved as is. With the CompatibilityGoogleSheets
+// or CompatibilityLibreOffice, the constructs those applications mishandle
+// will be rewritten when saving the spreadsheet: the missing r attributes of
+// the rows and cells in the worksheets will be filled, the markup
+// compatibility alternate content will be replaced with its fallback content,
+// the extension lists of the charts will be removed, and the extensions of
+// the worksheets and workbook which are not supported by the application,
+// such as slicers, timelines and web extensions will be removed. The
+// sparklines will be removed for the Google Sheets also.
+type CompatibilityProfile byte
+
+// Compatibility profiles enumeration.
+const (
+	CompatibilityExcel CompatibilityProfile = iota
+	CompatibilityGoogleSheets
+	CompatibilityLibreOffice
+)
+
+// unsupportedExtURIs defined the URIs of the extensions in the worksheets and
+// workbook which will be removed for each compatibility profile.
+var unsupportedExtURIs = map[CompatibilityProfile][]string{
+	CompatibilityGoogleSheets: {
+		ExtURISparklineGroups, ExtURISlicerListX14, ExtURISlicerListX15, ExtURISlicerCachesListX14,
+		ExtURITimelineRefs, ExtURITimelineCacheRefs, ExtURIWebExtensions,
+	},
+	CompatibilityLibreOffice: {
+		ExtURISlicerListX14, ExtURISlicerListX15, ExtURISlicerCachesListX14,
+		ExtURITimelineRefs, ExtURITimelineCacheRefs, ExtURIWebExtensions,
+	},
+}
+
+// checkCompatibilityProfile provides a function to check if the compatibility
+// profile of the options is valid.
+func checkCompatibilityProfile(profile CompatibilityProfile) error {
+	if profile > CompatibilityLibreOffice {
+		return fmt.Errorf("invalid compatibility profile %d", profile)
+	}
+	return nil
+}
+
+// compatibleXMLReader provides a function to get the reader of the part which
+// has been rewritten for the compatibility profile of the options by given
+// reader and part path. The returned function should be called to release the
+// reader after reading.
+func (f *File) compatibleXMLReader(r io.Reader, partPath string) (io.Reader, func()) {
+	if f.options == nil || f.options.Compatibility == CompatibilityExcel || !isFormattableXMLPart(partPath) {
+		return r, func() {}
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeCompatibleXML(pw, r, f.options.Compatibility, partPath))
+	}()
+	return pr, func() { pr.Close() }
+}
+
+// writeCompatibleXML provides a function to rewrite the XML document from the
+// reader for the compatibility profile by given part path, and write it to
+// the writer.
+func writeCompatibleXML(w io.Writer, r io.Reader, profile CompatibilityProfile, partPath string) error {
+	type element struct {
+		name          string
+		written, list bool
+	}
+	var (
+		buf            bytes.Buffer
+		pending        bool
+		skip, row, col int
+		stack          []element
+		deferred       *xml.StartElement
+		d              = xml.NewDecoder(r)
+		dir, base      = path.Dir(partPath), path.Base(partPath)
+		isSheet        = dir == "xl/worksheets" && path.Ext(base) == ".xml"
+		isChart        = dir == "xl/charts" && strings.HasPrefix(base, "chart")
+		hasExt         = isSheet || partPath == "xl/workbook.xml"
+		unsupported    = make(map[string]bool)
+	)
+	for _, uri := range unsupportedExtURIs[profile] {
+		unsupported[uri] = true
+	}
+	closeTag := func() {
+		if pending {
+			buf.WriteString(">")
+			pending = false
+		}
+	}
+	writeStart := func(t xml.StartElement) {
+		closeTag()
+		buf.WriteString("<" + canonicalName(t.Name))
+		for _, attr := range t.Attr {
+			buf.WriteString(" " + canonicalName(attr.Name) + `="` + escapeCanonicalAttr(attr.Value) + `"`)
+		}
+		pending = true
+	}
+	flushDeferred := func() {
+		if deferred != nil {
+			writeStart(*deferred)
+			stack[len(stack)-1].written, deferred = true, nil
+		}
+	}
+	getAttr := func(t xml.StartElement, name string) (string, bool) {
+		for _, attr := range t.Attr {
+			if attr.Name.Space == "" && attr.Name.Local == name {
+				return attr.Value, true
+			}
+		}
+		return "", false
+	}
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skip > 0 {
+				skip++
+				continue
+			}
+			var parent element
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			name := canonicalName(t.Name)
+			switch {
+			case name == "mc:AlternateContent":
+				stack = append(stack, element{name: name})
+				continue
+			case parent.name == "mc:AlternateContent":
+				if name == "mc:Fallback" {
+					stack = append(stack, element{name: name})
+					continue
+				}
+				skip = 1
+				continue
+			case isChart && t.Name.Local == "extLst":
+				skip = 1
+				continue
+			case hasExt && t.Name.Local == "ext" && parent.list:
+				if uri, _ := getAttr(t, "uri"); unsupported[uri] {
+					skip = 1
+					continue
+				}
+			case hasExt && t.Name.Local == "extLst":
+				flushDeferred()
+				start := t.Copy()
+				deferred, stack = &start, append(stack, element{name: name, list: true})
+				continue
+			case isSheet && t.Name.Local == "row" && strings.HasSuffix(parent.name, "sheetData"):
+				if r, ok := getAttr(t, "r"); ok {
+					row, _ = strconv.Atoi(r)
+				} else {
+					row++
+					t.Attr = append([]xml.Attr{{Name: xml.Name{Local: "r"}, Value: strconv.Itoa(row)}}, t.Attr...)
+				}
+				col = 0
+			case isSheet && t.Name.Local == "c" && strings.HasSuffix(parent.name, "row"):
+				if r, ok := getAttr(t, "r"); ok {
+					if c, _, err := CellNameToCoordinates(r); err == nil {
+						col = c
+					}
+				} else {
+					col++
+					cell, _ := CoordinatesToCellName(col, row)
+					t.Attr = append([]xml.Attr{{Name: xml.Name{Local: "r"}, Value: cell}}, t.Attr...)
+				}
+			}
+			flushDeferred()
+			writeStart(t)
+			stack = append(stack, element{name: name, written: true})
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if len(stack) == 0 {
+				return fmt.Errorf("unexpected end element %s", canonicalName(t.Name))
+			}
+			elem := stack[len(stack)-1]
+			if stack = stack[:len(stack)-1]; !elem.written {
+				if elem.list {
+					deferred = nil
+				}
+				continue
+			}
+			if pending {
+				buf.WriteString("/>")
+				pending = false
+				continue
+			}
+			buf.WriteString("</" + canonicalName(t.Name) + ">")
+		case xml.CharData:
+			if skip > 0 || (deferred != nil && len(bytes.TrimSpace(t)) == 0) {
+				continue
+			}
+			flushDeferred()
+			closeTag()
+			buf.WriteString(escapeCanonicalText(string(t)))
+		case xml.ProcInst:
+			if skip > 0 {
+				continue
+			}
+			closeTag()
+			buf.WriteString("<?" + t.Target)
+			if len(t.Inst) > 0 {
+				buf.WriteString(" " + string(t.Inst))
+			}
+			buf.WriteString("?>")
+		case xml.Comment:
+			if skip > 0 {
+				continue
+			}
+			closeTag()
+			buf.WriteString("<!--" + string(t) + "-->")
+		case xml.Directive:
+			if skip > 0 {
+				continue
+			}
+			closeTag()
+			buf.WriteString("<!" + string(t) + ">")
+		}
+		if buf.Len() >= 1<<16 {
+			if _, err := buf.WriteTo(w); err != nil {
+				return err
+			}
+		}
+	}
+	if len(stack) > 0 {
+		return io.ErrUnexpectedEOF
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
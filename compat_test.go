@@ -0,0 +1,81 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompatibilityProfile(t *testing.T) {
+	sheet := `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main" xmlns:xm="http://schemas.microsoft.com/office/excel/2006/main">` +
+		`<sheetData><row><c t="inlineStr"><is><t>A</t></is></c><c><v>1</v></c></row><row r="3"><c r="B3"><v>2</v></c><c><v>3</v></c></row><row><c><v>4</v></c></row></sheetData>` +
+		`<mc:AlternateContent><mc:Choice Requires="x14"><controls><control shapeId="1025" r:id="rId1" name="Check Box 1"/></controls></mc:Choice><mc:Fallback><drawing r:id="rId2"/></mc:Fallback></mc:AlternateContent>` +
+		`<extLst><ext uri="` + ExtURISparklineGroups + `"><x14:sparklineGroups><x14:sparklineGroup><x14:sparklines><x14:sparkline><xm:f>Sheet1!A1:B1</xm:f><xm:sqref>C1</xm:sqref></x14:sparkline></x14:sparklines></x14:sparklineGroup></x14:sparklineGroups></ext>` +
+		`<ext uri="` + ExtURISlicerListX14 + `"><x14:slicerList><x14:slicer r:id="rId3"/></x14:slicerList></ext>` +
+		`<ext uri="` + ExtURIDataValidations + `"><x14:dataValidations count="0"/></ext></extLst></worksheet>`
+	workbook := `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`<extLst><ext uri="` + ExtURISlicerCachesListX14 + `"><x14:slicerCaches xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"/></ext></extLst></workbook>`
+	chart := `<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" xmlns:c14="http://schemas.microsoft.com/office/drawing/2007/8/2/chart">` +
+		`<mc:AlternateContent><mc:Choice Requires="c14"><c14:style val="102"/></mc:Choice><mc:Fallback><c:style val="2"/></mc:Fallback></mc:AlternateContent>` +
+		`<c:chart><c:plotArea><c:layout/></c:plotArea></c:chart><c:extLst><c:ext uri="{C3380CC4-5D6E-409C-BE32-E72D297353CC}"><c16:uniqueId xmlns:c16="http://schemas.microsoft.com/office/drawing/2014/chart" val="{00000000-0001}"/></c:ext></c:extLst></c:chartSpace>`
+	newFile := func() *File {
+		f := NewFile()
+		delete(f.Sheet, "xl/worksheets/sheet1.xml")
+		f.WorkBook = nil
+		f.XLSX["xl/worksheets/sheet1.xml"] = []byte(XMLHeader + sheet)
+		f.XLSX["xl/workbook.xml"] = []byte(XMLHeader + workbook)
+		f.XLSX["xl/charts/chart1.xml"] = []byte(XMLHeader + chart)
+		return f
+	}
+	for name, profile := range map[string]CompatibilityProfile{
+		"excel": CompatibilityExcel, "googleSheets": CompatibilityGoogleSheets, "libreOffice": CompatibilityLibreOffice,
+	} {
+		f := newFile()
+		f.options = &Options{Compatibility: profile}
+		buf, err := f.WriteToBuffer()
+		assert.NoError(t, err)
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		parts := make(map[string][]byte)
+		for _, file := range zr.File {
+			rc, err := file.Open()
+			assert.NoError(t, err)
+			parts[file.Name], err = ioutil.ReadAll(rc)
+			assert.NoError(t, err)
+			assert.NoError(t, rc.Close())
+		}
+		for part, golden := range map[string]string{
+			"xl/worksheets/sheet1.xml": "sheet", "xl/workbook.xml": "workbook", "xl/charts/chart1.xml": "chart",
+		} {
+			expected, err := ioutil.ReadFile(filepath.Join("test", "golden", name+"_"+golden+".xml"))
+			assert.NoError(t, err)
+			assert.Equal(t, strings.TrimSpace(string(expected)), string(parts[part]), name+" "+part)
+		}
+	}
+
+	// Test save the spreadsheet with the compatibility profile and indented XML
+	f := newFile()
+	f.options = &Options{Compatibility: CompatibilityLibreOffice, PrettyXML: true}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Contains(t, readZipPart(t, buf.Bytes(), "xl/charts/chart1.xml"), "\n  <c:style val=\"2\"/>\n  <c:chart>")
+
+	// Test save the spreadsheet with invalid compatibility profile
+	f = NewFile()
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestCompatibilityProfile.xlsx"), Options{Compatibility: 3}), "invalid compatibility profile 3")
+
+	// Test rewrite the invalid XML
+	var out bytes.Buffer
+	assert.EqualError(t, writeCompatibleXML(&out, strings.NewReader("<worksheet>"), CompatibilityGoogleSheets, "xl/worksheets/sheet1.xml"), "unexpected EOF")
+	assert.EqualError(t, writeCompatibleXML(&out, strings.NewReader("</worksheet>"), CompatibilityGoogleSheets, "xl/worksheets/sheet1.xml"), "unexpected end element worksheet")
+	assert.EqualError(t, writeCompatibleXML(&out, strings.NewReader("<worksheet><"), CompatibilityGoogleSheets, "xl/worksheets/sheet1.xml"), "XML syntax error on line 1: unexpected EOF")
+	// Test rewrite the XML with the processing instruction, comment and directive
+	out.Reset()
+	assert.NoError(t, writeCompatibleXML(&out, strings.NewReader(`<?xml version="1.0"?><!DOCTYPE worksheet><worksheet><!--c--></worksheet>`), CompatibilityGoogleSheets, "xl/worksheets/sheet1.xml"))
+	assert.Equal(t, `<?xml version="1.0"?><!DOCTYPE worksheet><worksheet><!--c--></worksheet>`, out.String())
+}
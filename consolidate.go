@@ -0,0 +1,639 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mohae/deepcopy"
+)
+
+// MergeWorkbooks provides a function to create a new workbook containing the
+// worksheets of all given workbooks in order, with the same rules of the
+// AppendWorkbook. For example, consolidate the monthly reports:
+//
+//    var files []*excelize.File
+//    for _, name := range []string{"Jan.xlsx", "Feb.xlsx", "Mar.xlsx"} {
+//        f, err := excelize.OpenFile(name)
+//        if err != nil {
+//            fmt.Println(err)
+//            return
+//        }
+//        files = append(files, f)
+//    }
+//    f, err := excelize.MergeWorkbooks(files...)
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    err = f.SaveAs("Q1.xlsx")
+//
+func MergeWorkbooks(files ...*File) (*File, error) {
+	f := NewFile()
+	// Rename the default worksheet which will be removed after the worksheets
+	// are appended, to avoid the collision with the appended worksheets.
+	name := "Sheet1"
+	for i := 1; ; i++ {
+		exist := false
+		for _, file := range files {
+			for _, sheet := range file.GetSheetList() {
+				exist = exist || strings.EqualFold(sheet, name)
+			}
+		}
+		if !exist {
+			break
+		}
+		name = "Sheet" + strconv.Itoa(i+1)
+	}
+	f.SetSheetName("Sheet1", name)
+	for _, file := range files {
+		if _, err := f.AppendWorkbook(file); err != nil {
+			return f, err
+		}
+	}
+	if len(f.GetSheetList()) > 1 {
+		f.DeleteSheet(name)
+		f.SetActiveSheet(0)
+	}
+	return f, nil
+}
+
+// AppendWorkbook provides a function to append all worksheets of the given
+// workbook to the end of the workbook, and returns the names of the appended
+// worksheets. The styles, shared strings and conditional formats of the
+// cells are remapped into the workbook, and the tables, hyperlinks,
+// comments, pictures, charts and defined names of the worksheets are
+// appended. The worksheet which name already exists in the workbook will be
+// renamed with the number suffix, such as "Sheet1 (2)", and the references to
+// it in the formulas, hyperlinks, charts and defined names will be updated.
+// The workbook scoped defined names which already exist in the workbook will
+// be kept. The chart sheets and pivot tables will not be appended. For
+// example, append the worksheets of Book2.xlsx to Book1.xlsx:
+//
+//    names, err := f1.AppendWorkbook(f2)
+//
+func (f *File) AppendWorkbook(src *File) ([]string, error) {
+	var (
+		sheets, names []string
+		renamed       = map[string]string{}
+		styles        = map[int]int{}
+		dxfs          = map[int]int{}
+		sst           = map[int]int{}
+	)
+	for _, sheet := range src.GetSheetList() {
+		if !strings.HasPrefix(src.sheetMap[sheet], "xl/worksheets/") {
+			continue
+		}
+		name := f.getUniqueSheetName(sheet)
+		f.NewSheet(name)
+		if name != sheet {
+			renamed[sheet] = name
+		}
+		sheets, names = append(sheets, sheet), append(names, name)
+	}
+	replace := newSheetNameReplacer(renamed)
+	comments := src.GetComments()
+	for i, sheet := range sheets {
+		if err := f.appendWorksheet(src, sheet, names[i], replace, styles, dxfs, sst); err != nil {
+			return names, err
+		}
+		if err := f.appendComments(names[i], comments[sheet]); err != nil {
+			return names, err
+		}
+	}
+	for _, definedName := range src.GetDefinedName() {
+		definedName.RefersTo = replace(definedName.RefersTo)
+		if definedName.Scope == "Workbook" {
+			if f.getDefinedNameIndex(definedName.Name, nil) != -1 {
+				continue
+			}
+			definedName.Scope = ""
+		} else {
+			idx := inStrSlice(sheets, definedName.Scope)
+			if idx == -1 {
+				continue
+			}
+			definedName.Scope = names[idx]
+		}
+		if err := f.SetDefinedName(&definedName); err != nil {
+			return names, err
+		}
+	}
+	return names, nil
+}
+
+// ImportSheet provides a function to copy the worksheet of the given workbook
+// to the end of the workbook as a new worksheet by given source workbook,
+// worksheet name and the name of the new worksheet, the name of the source
+// worksheet will be used if the new name is empty. The cells with the styles,
+// merged cells, data validations, conditional formats, tables, hyperlinks,
+// comments, pictures, charts and the defined names scoped to the worksheet
+// are copied with the same rules of the AppendWorkbook, the other worksheets
+// and the workbook scoped defined names of the source workbook will not be
+// copied. For example, import the worksheet "Summary" of Book2.xlsx as
+// "Summary 2020":
+//
+//    err := f1.ImportSheet(f2, "Summary", "Summary 2020")
+//
+func (f *File) ImportSheet(src *File, sheet, newName string) error {
+	sheet = trimSheetName(sheet)
+	if !strings.HasPrefix(src.sheetMap[sheet], "xl/worksheets/") {
+		return ErrSheetNotExist{sheet}
+	}
+	if newName = trimSheetName(newName); newName == "" {
+		newName = sheet
+	}
+	if f.GetSheetIndex(newName) != -1 {
+		return errors.New("the same name worksheet already exists")
+	}
+	f.NewSheet(newName)
+	replace := newSheetNameReplacer(map[string]string{sheet: newName})
+	if err := f.appendWorksheet(src, sheet, newName, replace, map[int]int{}, map[int]int{}, map[int]int{}); err != nil {
+		return err
+	}
+	if err := f.appendComments(newName, src.GetComments()[sheet]); err != nil {
+		return err
+	}
+	for _, definedName := range src.GetDefinedName() {
+		if definedName.Scope != sheet {
+			continue
+		}
+		definedName.RefersTo, definedName.Scope = replace(definedName.RefersTo), newName
+		if err := f.SetDefinedName(&definedName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendComments provides a function to add the comments of the source
+// worksheet to the worksheet by given worksheet name and the comments.
+func (f *File) appendComments(sheet string, comments []Comment) error {
+	for _, comment := range comments {
+		format, _ := json.Marshal(formatComment{
+			Author:  comment.Author,
+			Text:    comment.Text,
+			Anchor:  comment.Anchor,
+			Visible: comment.Visible,
+		})
+		if err := f.AddComment(sheet, comment.Ref, string(format)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getUniqueSheetName provides a function to get the worksheet name which
+// doesn't exist in the workbook by given worksheet name, the number suffix
+// will be added to the name if it already exists.
+func (f *File) getUniqueSheetName(name string) string {
+	exist := func(name string) bool {
+		for _, sheet := range f.GetSheetList() {
+			if strings.EqualFold(sheet, name) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, unique := 2, name; ; i++ {
+		if !exist(unique) {
+			return unique
+		}
+		suffix := fmt.Sprintf(" (%d)", i)
+		for unique = name; utf8.RuneCountInString(unique)+len(suffix) > 31; {
+			_, size := utf8.DecodeLastRuneInString(unique)
+			unique = unique[:len(unique)-size]
+		}
+		unique += suffix
+	}
+}
+
+// appendWorksheet provides a function to copy the worksheet of the source
+// workbook to the created worksheet by given worksheet names, the function
+// to update the renamed worksheet names in the references, and the caches of
+// the remapped styles, conditional formats and shared strings.
+func (f *File) appendWorksheet(src *File, sheet, name string, replace func(string) string, styles, dxfs, sst map[int]int) error {
+	srcWs, err := src.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	ws := deepcopy.Copy(srcWs).(*xlsxWorksheet)
+	if ws.SheetViews != nil {
+		for i := range ws.SheetViews.SheetView {
+			ws.SheetViews.SheetView[i].TabSelected = false
+		}
+	}
+	ws.Drawing, ws.LegacyDrawing, ws.LegacyDrawingHF, ws.DrawingHF, ws.Picture = nil, nil, nil, nil, nil
+	ws.OleObjects, ws.Controls, ws.TableParts = nil, nil, nil
+	if ws.Cols != nil {
+		for i := range ws.Cols.Col {
+			ws.Cols.Col[i].Style = f.appendStyle(src, ws.Cols.Col[i].Style, styles)
+		}
+	}
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		row.S = f.appendStyle(src, row.S, styles)
+		for colIdx := range row.C {
+			c := &row.C[colIdx]
+			c.S = f.appendStyle(src, c.S, styles)
+			if c.T == "s" {
+				if c.V, err = f.appendSharedString(src, c.V, sst); err != nil {
+					return err
+				}
+			}
+			if c.F != nil {
+				c.F.Content = replace(c.F.Content)
+			}
+		}
+	}
+	for _, cf := range ws.ConditionalFormatting {
+		for _, rule := range cf.CfRule {
+			if rule.DxfID != nil {
+				dxfID := f.appendDxf(src, *rule.DxfID, dxfs)
+				rule.DxfID = &dxfID
+			}
+			for i := range rule.Formula {
+				rule.Formula[i] = replace(rule.Formula[i])
+			}
+		}
+	}
+	if ws.DataValidations != nil {
+		for _, dv := range ws.DataValidations.DataValidation {
+			dv.Formula1, dv.Formula2 = replace(dv.Formula1), replace(dv.Formula2)
+		}
+	}
+	path := f.sheetMap[trimSheetName(name)]
+	f.Sheet[path] = ws
+	f.xmlAttr[path] = src.xmlAttr[src.sheetMap[sheet]]
+	srcRels, err := src.relsReader("xl/worksheets/_rels/" + strings.TrimPrefix(src.sheetMap[sheet], "xl/worksheets/") + ".rels")
+	if err != nil {
+		return err
+	}
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(path, "xl/worksheets/") + ".rels"
+	getTarget := func(rID string) string {
+		if srcRels != nil {
+			for _, rel := range srcRels.Relationships {
+				if rel.ID == rID {
+					return rel.Target
+				}
+			}
+		}
+		return ""
+	}
+	if ws.Hyperlinks != nil {
+		hyperlinks := ws.Hyperlinks.Hyperlink[:0]
+		for _, link := range ws.Hyperlinks.Hyperlink {
+			if link.RID != "" {
+				target := getTarget(link.RID)
+				if target == "" {
+					continue
+				}
+				link.RID = "rId" + strconv.Itoa(f.addRels(sheetRels, SourceRelationshipHyperLink, target, "External"))
+				f.addSheetNameSpace(name, SourceRelationship)
+			}
+			link.Location = replace(link.Location)
+			hyperlinks = append(hyperlinks, link)
+		}
+		ws.Hyperlinks.Hyperlink = hyperlinks
+	}
+	if srcWs.Drawing != nil {
+		if target := getTarget(srcWs.Drawing.RID); target != "" {
+			drawingXML, err := f.appendDrawing(src, strings.Replace(target, "..", "xl", -1), replace)
+			if err != nil {
+				return err
+			}
+			f.addSheetDrawing(name, f.addRels(sheetRels, SourceRelationshipDrawingML, strings.Replace(drawingXML, "xl", "..", 1), ""))
+			f.addSheetNameSpace(name, SourceRelationship)
+		}
+	}
+	if srcWs.Picture != nil {
+		if target := getTarget(srcWs.Picture.RID); target != "" {
+			mediaXML := strings.Replace(target, "..", "xl", -1)
+			media := f.addMedia(src.readXML(mediaXML), filepath.Ext(mediaXML))
+			f.addSheetPicture(name, f.addRels(sheetRels, SourceRelationshipImage, strings.Replace(media, "xl", "..", 1), ""))
+			f.addSheetNameSpace(name, SourceRelationship)
+			f.setContentTypePartImageExtensions()
+		}
+	}
+	if srcWs.TableParts == nil {
+		return err
+	}
+	for _, tablePart := range srcWs.TableParts.TableParts {
+		target := getTarget(tablePart.RID)
+		if target == "" {
+			continue
+		}
+		t, err := src.tableReader(strings.Replace(target, "..", "xl", -1))
+		if err != nil {
+			return err
+		}
+		tableID := f.countTables() + 1
+		t.ID = tableID
+		if _, _, _, _, err = f.findTable(t.Name); err == nil {
+			t.Name = "Table" + strconv.Itoa(tableID)
+			t.DisplayName = t.Name
+		}
+		tableXML := "xl/tables/table" + strconv.Itoa(tableID) + ".xml"
+		table, _ := xml.Marshal(t)
+		f.saveFileList(tableXML, table)
+		f.addContentTypePart(tableID, "table")
+		rID := f.addRels(sheetRels, SourceRelationshipTable, strings.Replace(tableXML, "xl", "..", 1), "")
+		if err = f.addSheetTable(name, rID); err != nil {
+			return err
+		}
+		f.addSheetNameSpace(name, SourceRelationship)
+	}
+	return err
+}
+
+// appendDrawing provides a function to copy the drawing part of the source
+// workbook with the pictures and charts in it into the workbook by given
+// path of the drawing part and the function to update the renamed worksheet
+// names in the charts, and returns the path of the copied drawing part.
+func (f *File) appendDrawing(src *File, drawingXML string, replace func(string) string) (string, error) {
+	wsDr, _, err := src.drawingParser(drawingXML)
+	if err != nil {
+		return "", err
+	}
+	drawingID := f.countDrawings() + 1
+	path := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
+	f.Drawings[path] = deepcopy.Copy(wsDr).(*xlsxWsDr)
+	srcRels, err := src.relsReader("xl/drawings/_rels/" + filepath.Base(drawingXML) + ".rels")
+	if err != nil {
+		return "", err
+	}
+	if srcRels != nil {
+		rels := &xlsxRelationships{}
+		for _, rel := range srcRels.Relationships {
+			target := strings.Replace(rel.Target, "..", "xl", -1)
+			switch rel.Type {
+			case SourceRelationshipImage:
+				rel.Target = strings.Replace(f.addMedia(src.readXML(target), filepath.Ext(target)), "xl", "..", 1)
+			case SourceRelationshipChart:
+				chartID := f.countCharts() + 1
+				f.XLSX["xl/charts/chart"+strconv.Itoa(chartID)+".xml"] = []byte(replace(string(src.readXML(target))))
+				f.addContentTypePart(chartID, "chart")
+				rel.Target = "../charts/chart" + strconv.Itoa(chartID) + ".xml"
+			}
+			rels.Relationships = append(rels.Relationships, rel)
+		}
+		f.Relationships["xl/drawings/_rels/drawing"+strconv.Itoa(drawingID)+".xml.rels"] = rels
+	}
+	f.addContentTypePart(drawingID, "drawings")
+	return path, nil
+}
+
+// appendStyle provides a function to copy the cell style of the source
+// workbook with its number format, font, fill and border into the workbook
+// by given style ID of the source workbook, and returns the style ID in the
+// workbook. The existing parts with the same definitions will be reused.
+func (f *File) appendStyle(src *File, styleID int, styles map[int]int) int {
+	if ID, ok := styles[styleID]; ok {
+		return ID
+	}
+	srcStyles, _ := src.stylesReader()
+	s, _ := f.stylesReader()
+	if styleID <= 0 || srcStyles.CellXfs == nil || styleID >= len(srcStyles.CellXfs.Xf) {
+		return 0
+	}
+	xf := deepcopy.Copy(srcStyles.CellXfs.Xf[styleID]).(xlsxXf)
+	xf.XfID = intPtr(0)
+	if xf.NumFmtID != nil && *xf.NumFmtID >= 164 {
+		numFmtID := 0
+		if srcStyles.NumFmts != nil {
+			for _, numFmt := range srcStyles.NumFmts.NumFmt {
+				if numFmt.NumFmtID == *xf.NumFmtID {
+					numFmtID = appendNumFmt(s, numFmt.FormatCode)
+				}
+			}
+		}
+		xf.NumFmtID = &numFmtID
+	}
+	if xf.FontID != nil {
+		fontID := 0
+		if srcStyles.Fonts != nil && *xf.FontID < len(srcStyles.Fonts.Font) {
+			font := srcStyles.Fonts.Font[*xf.FontID]
+			if fontID = -1; s.Fonts != nil {
+				for i, ft := range s.Fonts.Font {
+					if equalStylePart(ft, font) {
+						fontID = i
+						break
+					}
+				}
+			}
+			if fontID == -1 {
+				if s.Fonts == nil {
+					s.Fonts = &xlsxFonts{}
+				}
+				s.Fonts.Font = append(s.Fonts.Font, deepcopy.Copy(font).(*xlsxFont))
+				s.Fonts.Count, fontID = len(s.Fonts.Font), len(s.Fonts.Font)-1
+			}
+		}
+		xf.FontID = &fontID
+	}
+	if xf.FillID != nil {
+		fillID := 0
+		if srcStyles.Fills != nil && *xf.FillID < len(srcStyles.Fills.Fill) {
+			fill := srcStyles.Fills.Fill[*xf.FillID]
+			if fillID = -1; s.Fills != nil {
+				for i, fl := range s.Fills.Fill {
+					if equalStylePart(fl, fill) {
+						fillID = i
+						break
+					}
+				}
+			}
+			if fillID == -1 {
+				if s.Fills == nil {
+					s.Fills = &xlsxFills{}
+				}
+				s.Fills.Fill = append(s.Fills.Fill, deepcopy.Copy(fill).(*xlsxFill))
+				s.Fills.Count, fillID = len(s.Fills.Fill), len(s.Fills.Fill)-1
+			}
+		}
+		xf.FillID = &fillID
+	}
+	if xf.BorderID != nil {
+		borderID := 0
+		if srcStyles.Borders != nil && *xf.BorderID < len(srcStyles.Borders.Border) {
+			border := srcStyles.Borders.Border[*xf.BorderID]
+			if borderID = -1; s.Borders != nil {
+				for i, bd := range s.Borders.Border {
+					if equalStylePart(bd, border) {
+						borderID = i
+						break
+					}
+				}
+			}
+			if borderID == -1 {
+				if s.Borders == nil {
+					s.Borders = &xlsxBorders{}
+				}
+				s.Borders.Border = append(s.Borders.Border, deepcopy.Copy(border).(*xlsxBorder))
+				s.Borders.Count, borderID = len(s.Borders.Border), len(s.Borders.Border)-1
+			}
+		}
+		xf.BorderID = &borderID
+	}
+	ID := -1
+	for i, x := range s.CellXfs.Xf {
+		if equalStylePart(x, xf) {
+			ID = i
+			break
+		}
+	}
+	if ID == -1 {
+		s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+		s.CellXfs.Count, ID = len(s.CellXfs.Xf), len(s.CellXfs.Xf)-1
+	}
+	styles[styleID] = ID
+	return ID
+}
+
+// appendNumFmt provides a function to get the ID of the custom number format
+// by given format code, the number format will be added if it doesn't exist.
+func appendNumFmt(s *xlsxStyleSheet, formatCode string) int {
+	numFmtID := 163
+	if s.NumFmts == nil {
+		s.NumFmts = &xlsxNumFmts{}
+	}
+	for _, numFmt := range s.NumFmts.NumFmt {
+		if numFmt.FormatCode == formatCode {
+			return numFmt.NumFmtID
+		}
+		if numFmt.NumFmtID > numFmtID {
+			numFmtID = numFmt.NumFmtID
+		}
+	}
+	numFmtID++
+	s.NumFmts.NumFmt = append(s.NumFmts.NumFmt, &xlsxNumFmt{NumFmtID: numFmtID, FormatCode: formatCode})
+	s.NumFmts.Count = len(s.NumFmts.NumFmt)
+	return numFmtID
+}
+
+// appendDxf provides a function to copy the differential format of the
+// conditional format of the source workbook into the workbook by given
+// format ID of the source workbook, and returns the format ID in the
+// workbook.
+func (f *File) appendDxf(src *File, dxfID int, dxfs map[int]int) int {
+	if ID, ok := dxfs[dxfID]; ok {
+		return ID
+	}
+	srcStyles, _ := src.stylesReader()
+	s, _ := f.stylesReader()
+	if srcStyles.Dxfs == nil || dxfID < 0 || dxfID >= len(srcStyles.Dxfs.Dxfs) {
+		return dxfID
+	}
+	if s.Dxfs == nil {
+		s.Dxfs = &xlsxDxfs{}
+	}
+	ID := -1
+	for i, d := range s.Dxfs.Dxfs {
+		if d.Dxf == srcStyles.Dxfs.Dxfs[dxfID].Dxf {
+			ID = i
+			break
+		}
+	}
+	if ID == -1 {
+		s.Dxfs.Dxfs = append(s.Dxfs.Dxfs, &xlsxDxf{Dxf: srcStyles.Dxfs.Dxfs[dxfID].Dxf})
+		s.Dxfs.Count, ID = len(s.Dxfs.Dxfs), len(s.Dxfs.Dxfs)-1
+	}
+	dxfs[dxfID] = ID
+	return ID
+}
+
+// appendSharedString provides a function to copy the shared string of the
+// source workbook into the workbook by given index of the source workbook,
+// and returns the index in the workbook. The rich text will be kept.
+func (f *File) appendSharedString(src *File, value string, sst map[int]int) (string, error) {
+	idx, err := strconv.Atoi(value)
+	if err != nil {
+		return value, nil
+	}
+	if ID, ok := sst[idx]; ok {
+		return strconv.Itoa(ID), nil
+	}
+	srcSST, err := src.sharedStringsReader()
+	if err != nil {
+		return value, err
+	}
+	if idx < 0 || idx >= srcSST.len() {
+		return value, nil
+	}
+	si, err := srcSST.item(idx)
+	if err != nil {
+		return value, err
+	}
+	if si.T != nil && len(si.R) == 0 {
+		if sst[idx], err = f.setSharedString(si.T.Val); err != nil {
+			return value, err
+		}
+		return strconv.Itoa(sst[idx]), nil
+	}
+	ss, err := f.sharedStringsReader()
+	if err != nil {
+		return value, err
+	}
+	f.Lock()
+	sst[idx] = ss.add(deepcopy.Copy(si).(xlsxSI))
+	ss.Count++
+	ss.UniqueCount++
+	f.Unlock()
+	return strconv.Itoa(sst[idx]), nil
+}
+
+// equalStylePart provides a function to check if the two parts of the
+// styles are serialized to the same XML.
+func equalStylePart(a, b interface{}) bool {
+	x, _ := xml.Marshal(a)
+	y, _ := xml.Marshal(b)
+	return bytes.Equal(x, y)
+}
+
+// newSheetNameReplacer provides a function to create the function which
+// replaces the worksheet names in the references of the formula by given
+// map of the old and new worksheet names.
+func newSheetNameReplacer(renamed map[string]string) func(string) string {
+	if len(renamed) == 0 {
+		return func(s string) string { return s }
+	}
+	var (
+		names   []string
+		mapping = map[string]string{}
+	)
+	for oldName, newName := range renamed {
+		for _, name := range []string{quoteSheetName(oldName), "'" + strings.Replace(oldName, "'", "''", -1) + "'"} {
+			if _, ok := mapping[strings.ToLower(name)]; !ok {
+				names = append(names, regexp.QuoteMeta(name))
+			}
+			mapping[strings.ToLower(name)] = quoteSheetName(newName)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	re := regexp.MustCompile(`(?i)(^|[^\w.'])(` + strings.Join(names, "|") + `)!`)
+	return func(s string) string {
+		return re.ReplaceAllStringFunc(s, func(match string) string {
+			submatch := re.FindStringSubmatch(match)
+			return submatch[1] + mapping[strings.ToLower(submatch[2])] + "!"
+		})
+	}
+}
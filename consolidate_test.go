@@ -0,0 +1,195 @@
+package excelize
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendWorkbook(t *testing.T) {
+	src := NewFile()
+	src.NewSheet("Sheet2")
+	src.NewSheet("Data")
+	numFmt := "0.000"
+	style, err := src.NewStyle(&Style{Font: &Font{Bold: true, Color: "#FF0000"}, CustomNumFmt: &numFmt})
+	assert.NoError(t, err)
+	for cell, value := range map[string]interface{}{"A1": 1.5, "B1": "Foo", "C1": "Bar"} {
+		assert.NoError(t, src.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, src.SetCellStyle("Sheet1", "A1", "A1", style))
+	assert.NoError(t, src.SetCellRichText("Sheet1", "D1", []RichTextRun{{Text: "Rich", Font: &Font{Bold: true}}, {Text: "Text"}}))
+	assert.NoError(t, src.SetCellValue("Sheet2", "A1", 10))
+	assert.NoError(t, src.SetCellValue("Data", "A1", 5))
+	assert.NoError(t, src.SetCellFormula("Sheet1", "E1", "Sheet2!A1*2+'Sheet2'!A1+Data!A1"))
+	assert.NoError(t, src.SetCellHyperLink("Sheet1", "B1", "https://github.com/360EntSecGroup-Skylar/excelize", "External"))
+	assert.NoError(t, src.SetCellHyperLink("Sheet1", "C1", "Sheet2!A1", "Location"))
+	assert.NoError(t, src.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"Comment"}`))
+	assert.NoError(t, src.AddTable("Sheet2", "A3", "B5", `{"table_name":"Sales"}`))
+	dxf, err := src.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, src.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"1"}]`, dxf)))
+	assert.NoError(t, src.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet2!$A$1"}))
+	assert.NoError(t, src.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1", Scope: "Sheet1"}))
+	assert.NoError(t, src.SetDefinedName(&DefinedName{Name: "Rate", RefersTo: "Sheet2!$B$1"}))
+
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Foo"))
+	assert.NoError(t, f.AddTable("Sheet1", "A3", "B5", `{"table_name":"Sales"}`))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$A$1"}))
+	names, err := f.AppendWorkbook(src)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1 (2)", "Sheet2 (2)", "Data"}, names)
+	assert.Equal(t, []string{"Sheet1", "Sheet2", "Sheet1 (2)", "Sheet2 (2)", "Data"}, f.GetSheetList())
+
+	rows, err := f.GetRows("Sheet1 (2)")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1.5", "Foo", "Bar", "RichText", ""}}, rows)
+	formula, err := f.GetCellFormula("Sheet1 (2)", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "'Sheet2 (2)'!A1*2+'Sheet2 (2)'!A1+Data!A1", formula)
+	result, err := f.CalcCellValue("Sheet1 (2)", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "35", result)
+	styleID, err := f.GetCellStyle("Sheet1 (2)", "A1")
+	assert.NoError(t, err)
+	s, err := f.stylesReader()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000", s.NumFmts.NumFmt[0].FormatCode)
+	assert.Equal(t, s.NumFmts.NumFmt[0].NumFmtID, *s.CellXfs.Xf[styleID].NumFmtID)
+	assert.True(t, *s.Fonts.Font[*s.CellXfs.Xf[styleID].FontID].B)
+	ws, err := f.workSheetReader("Sheet1 (2)")
+	assert.NoError(t, err)
+	idx, err := strconv.Atoi(ws.SheetData.Row[0].C[3].V)
+	assert.NoError(t, err)
+	sst, err := f.sharedStringsReader()
+	assert.NoError(t, err)
+	assert.Len(t, sst.SI[idx].R, 2)
+	link, target, err := f.GetCellHyperLink("Sheet1 (2)", "B1")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/360EntSecGroup-Skylar/excelize", target)
+	_, target, err = f.GetCellHyperLink("Sheet1 (2)", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "'Sheet2 (2)'!A1", target)
+	assert.Len(t, f.GetComments()["Sheet1 (2)"], 1)
+	tables, err := f.GetTables("Sheet2 (2)")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "Table2", tables[0].Name)
+	styles, err := src.stylesReader()
+	assert.NoError(t, err)
+	assert.Equal(t, styles.Dxfs.Dxfs[dxf].Dxf, s.Dxfs.Dxfs[*ws.ConditionalFormatting[0].CfRule[0].DxfID].Dxf)
+	assert.Equal(t, []DefinedName{
+		{Name: "Total", RefersTo: "Sheet1!$A$1", Scope: "Workbook"},
+		{Name: "Amount", RefersTo: "'Sheet1 (2)'!$A$1", Scope: "Sheet1 (2)"},
+		{Name: "Rate", RefersTo: "'Sheet2 (2)'!$B$1", Scope: "Workbook"},
+	}, f.GetDefinedName())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAppendWorkbook.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestAppendWorkbook.xlsx"))
+	assert.NoError(t, err)
+	result, err = f.CalcCellValue("Sheet1 (2)", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "35", result)
+
+	// Test append workbook with invalid worksheet
+	src.Sheet["xl/worksheets/sheet1.xml"] = nil
+	src.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="A"/></sheetData></worksheet>`)
+	delete(src.Sheet, "xl/worksheets/sheet1.xml")
+	_, err = f.AppendWorkbook(src)
+	assert.Error(t, err)
+}
+
+func TestMergeWorkbooks(t *testing.T) {
+	f1, f2 := NewFile(), NewFile()
+	assert.NoError(t, f1.SetCellValue("Sheet1", "A1", "Foo"))
+	assert.NoError(t, f2.SetCellValue("Sheet1", "A1", "Bar"))
+	f2.NewSheet("Sheet2")
+	f, err := MergeWorkbooks(f1, f2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet1 (2)", "Sheet2"}, f.GetSheetList())
+	for sheet, expected := range map[string]string{"Sheet1": "Foo", "Sheet1 (2)": "Bar"} {
+		value, err := f.GetCellValue(sheet, "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestMergeWorkbooks.xlsx")))
+
+	f, err = MergeWorkbooks()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1"}, f.GetSheetList())
+
+	// Test merge workbooks with invalid worksheet
+	f2.Sheet["xl/worksheets/sheet2.xml"] = nil
+	delete(f2.Sheet, "xl/worksheets/sheet2.xml")
+	f2.XLSX["xl/worksheets/sheet2.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="A"/></sheetData></worksheet>`)
+	_, err = MergeWorkbooks(f1, f2)
+	assert.Error(t, err)
+
+	// Test get unique sheet name with the long name
+	f = NewFile()
+	f.NewSheet("Sheet1234567890123456789012345")
+	assert.Equal(t, "sheet1234567890123456789012 (2)", f.getUniqueSheetName("sheet1234567890123456789012345"))
+}
+
+func TestImportSheet(t *testing.T) {
+	src := NewFile()
+	src.NewSheet("Report")
+	assert.NoError(t, src.SetSheetRow("Report", "A1", &[]interface{}{"Month", "Amount"}))
+	assert.NoError(t, src.SetSheetRow("Report", "A2", &[]interface{}{"Jan", 10}))
+	assert.NoError(t, src.SetSheetRow("Report", "A3", &[]interface{}{"Feb", 20}))
+	assert.NoError(t, src.SetCellFormula("Report", "B4", "SUM(Report!B2:B3)"))
+	assert.NoError(t, src.MergeCell("Report", "C1", "D2"))
+	dv := NewDataValidation(true)
+	dv.Sqref = "B2:B3"
+	assert.NoError(t, dv.SetRange(0, 100, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, src.AddDataValidation("Report", dv))
+	assert.NoError(t, src.AddPicture("Report", "F1", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, src.AddChart("Report", "F10", `{"type":"col","series":[{"name":"Report!$B$1","categories":"Report!$A$2:$A$3","values":"Report!$B$2:$B$3"}],"title":{"name":"Amount"}}`))
+	assert.NoError(t, src.SetSheetBackground("Report", filepath.Join("test", "images", "background.jpg")))
+	assert.NoError(t, src.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Report!$B$2:$B$3", Scope: "Report"}))
+	assert.NoError(t, src.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Report!$B$4"}))
+
+	f := NewFile()
+	assert.NoError(t, f.ImportSheet(src, "Report", "Summary"))
+	assert.Equal(t, []string{"Sheet1", "Summary"}, f.GetSheetList())
+	rows, err := f.GetRows("Summary")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Month", "Amount"}, {"Jan", "10"}, {"Feb", "20"}, {"", ""}}, rows)
+	formula, err := f.GetCellFormula("Summary", "B4")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(Summary!B2:B3)", formula)
+	mergeCells, err := f.GetMergeCells("Summary")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	dvs, err := f.GetDataValidations("Summary")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 1)
+	assert.Len(t, f.Drawings, 1)
+	assert.Contains(t, string(f.XLSX["xl/charts/chart1.xml"]), "Summary!$B$2:$B$3")
+	assert.NotContains(t, string(f.XLSX["xl/charts/chart1.xml"]), "Report!")
+	assert.Equal(t, []DefinedName{{Name: "Amount", RefersTo: "Summary!$B$2:$B$3", Scope: "Summary"}}, f.GetDefinedName())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestImportSheet.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestImportSheet.xlsx"))
+	assert.NoError(t, err)
+	file, raw, err := f.GetPicture("Summary", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "image1.png", file)
+	assert.NotEmpty(t, raw)
+	ws, err := f.workSheetReader("Summary")
+	assert.NoError(t, err)
+	assert.NotNil(t, ws.Picture)
+	result, err := f.CalcCellValue("Summary", "B4")
+	assert.NoError(t, err)
+	assert.Equal(t, "30", result)
+
+	// Test import the worksheet with the same name, existing name and not exists worksheet
+	assert.NoError(t, f.ImportSheet(src, "Report", ""))
+	assert.EqualError(t, f.ImportSheet(src, "Report", "Summary"), "the same name worksheet already exists")
+	assert.EqualError(t, f.ImportSheet(src, "SheetN", ""), "sheet SheetN is not exist")
+}
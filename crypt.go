@@ -15,6 +15,7 @@ import (
 	"crypto/cipher"
 	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rand"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
@@ -23,9 +24,10 @@ import (
 	"encoding/xml"
 	"errors"
 	"hash"
-	"math/rand"
 	"reflect"
+	"sort"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/richardlehane/mscfb"
 	"golang.org/x/crypto/md4"
@@ -52,12 +54,38 @@ var (
 	oleIdentifier = []byte{
 		0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1,
 	}
+	passwordHashAlgorithms = map[string]string{ // hash algorithms for the password of document protection
+		"MD4":     "md4",
+		"MD5":     "md5",
+		"SHA-1":   "sha1",
+		"SHA-256": "sha256",
+		"SHA-384": "sha384",
+		"SHA-512": "sha512",
+	}
+	agileEncryptionInfoHeader = []byte{ // [MS-OFFCRYPTO] - v20181211 2.3.4.10 EncryptionInfo Stream (Agile Encryption)
+		0x04, 0x00, 0x04, 0x00, 0x40, 0x00, 0x00, 0x00,
+	}
+)
+
+const (
+	cfbSectorSize       = 512
+	cfbMiniSectorSize   = 64
+	cfbMiniStreamCutoff = 4096
+	cfbDirEntrySize     = 128
+	cfbHeaderDIFATCount = 109
+	cfbFreeSect         = 0xFFFFFFFF
+	cfbEndOfChain       = 0xFFFFFFFE
+	cfbFATSect          = 0xFFFFFFFD
+	cfbDIFATSect        = 0xFFFFFFFC
+	cfbNoStream         = 0xFFFFFFFF
+
+	workbookProtectionSpinCount = 100000
 )
 
 // Encryption specifies the encryption structure, streams, and storages are
 // required when encrypting ECMA-376 documents.
 type Encryption struct {
-	XMLName       xml.Name      `xml:"encryption"`
+	XMLName       xml.Name      `xml:"http://schemas.microsoft.com/office/2006/encryption encryption"`
 	KeyData       KeyData       `xml:"keyData"`
 	DataIntegrity DataIntegrity `xml:"dataIntegrity"`
 	KeyEncryptors KeyEncryptors `xml:"keyEncryptors"`
@@ -158,7 +186,9 @@ func Decrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	return
 }
 
-// Encrypt API encrypt data with the password.
+// Encrypt API encrypt data with the password by ECMA-376 agile encryption,
+// and returns the CFB file format contains the EncryptionInfo and
+// EncryptedPackage streams.
 func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	// Generate a random key to use to encrypt the document. Excel uses 32 bytes. We'll use the password to encrypt this key.
 	packageKey, _ := randomBytes(32)
@@ -166,6 +196,7 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	keyEncryptors, _ := randomBytes(16)
 	encryptionInfo := Encryption{
 		KeyData: KeyData{
+			SaltSize:        16,
 			BlockSize:       16,
 			KeyBits:         len(packageKey) * 8,
 			HashSize:        64,
@@ -175,7 +206,9 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 			SaltValue:       base64.StdEncoding.EncodeToString(keyDataSaltValue),
 		},
 		KeyEncryptors: KeyEncryptors{KeyEncryptor: []KeyEncryptor{{
+			URI: "http://schemas.microsoft.com/office/2006/keyEncryptor/password",
 			EncryptedKey: EncryptedKey{SpinCount: 100000, KeyData: KeyData{
+				SaltSize:        16,
 				CipherAlgorithm: "AES",
 				CipherChaining:  "ChainingModeCBC",
 				HashAlgorithm:   "SHA512",
@@ -200,9 +233,6 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	// Create the data integrity fields used by clients for integrity checks.
 	// Generate a random array of bytes to use in HMAC. The docs say to use the same length as the key salt, but Excel seems to use 64.
 	hmacKey, _ := randomBytes(64)
-	if err != nil {
-		return
-	}
 	// Create an initialization vector using the package encryption info and the appropriate block key.
 	hmacKeyIV, err := createIV(blockKeyHmacKey, encryptionInfo)
 	if err != nil {
@@ -210,11 +240,12 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	}
 	// Use the package key and the IV to encrypt the HMAC key.
 	encryptedHmacKey, err := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, packageKey, hmacKeyIV, hmacKey)
-	// Create the HMAC.
-	h := hmac.New(sha512.New, append(hmacKey, encryptedPackage...))
-	for _, buf := range [][]byte{hmacKey, encryptedPackage} {
-		h.Write(buf)
+	if err != nil {
+		return
 	}
+	// Create the HMAC of the whole EncryptedPackage stream.
+	h := hmac.New(sha512.New, hmacKey)
+	_, _ = h.Write(encryptedPackage)
 	hmacValue := h.Sum(nil)
 	// Generate an initialization vector for encrypting the resulting HMAC value.
 	hmacValueIV, err := createIV(blockKeyHmacValue, encryptionInfo)
@@ -223,6 +254,9 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	}
 	// Encrypt the value.
 	encryptedHmacValue, err := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, packageKey, hmacValueIV, hmacValue)
+	if err != nil {
+		return
+	}
 	// Put the encrypted key and value on the encryption info.
 	encryptionInfo.DataIntegrity.EncryptedHmacKey = base64.StdEncoding.EncodeToString(encryptedHmacKey)
 	encryptionInfo.DataIntegrity.EncryptedHmacValue = base64.StdEncoding.EncodeToString(encryptedHmacValue)
@@ -236,6 +270,9 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	}
 	// Encrypt the package key with the encryption key.
 	encryptedKeyValue, err := crypt(true, encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.CipherAlgorithm, encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.CipherChaining, key, keyEncryptors, packageKey)
+	if err != nil {
+		return
+	}
 	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedKeyValue = base64.StdEncoding.EncodeToString(encryptedKeyValue)
 
 	// Verifier hash
@@ -271,9 +308,12 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	if err != nil {
 		return
 	}
-	// TODO: Create a new CFB.
-	_, _ = encryptedPackage, encryptionInfoBuffer
-	err = errors.New("not support encryption currently")
+	encryptionInfoBuffer = append(append(agileEncryptionInfoHeader, []byte(XMLHeader)...), encryptionInfoBuffer...)
+	// Create a new CFB contains the encryption info and encrypted package.
+	compoundFile := cfb{}
+	compoundFile.put("EncryptionInfo", encryptionInfoBuffer)
+	compoundFile.put("EncryptedPackage", encryptedPackage)
+	packageBuf = compoundFile.write()
 	return
 }
 
@@ -509,6 +549,38 @@ func hashing(hashAlgorithm string, buffer ...[]byte) (key []byte) {
 	return key
 }
 
+// genISOPasswdHash provides a function to generate the hash value of the
+// password for document protection by given password, hash algorithm name,
+// base64 encoded salt value and spin count, as defined in ISO/IEC 29500. A
+// random salt value will be generated if the salt value is empty. Returns the
+// base64 encoded hash value and salt value.
+func genISOPasswdHash(passwd, hashAlgorithm, salt string, spinCount int) (hashValue, saltValue string, err error) {
+	algorithm, ok := passwordHashAlgorithms[hashAlgorithm]
+	if !ok {
+		err = errors.New("unsupported hash algorithm")
+		return
+	}
+	var saltBuf []byte
+	if salt == "" {
+		if saltBuf, err = randomBytes(16); err != nil {
+			return
+		}
+	} else if saltBuf, err = base64.StdEncoding.DecodeString(salt); err != nil {
+		return
+	}
+	encoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	passwordBuffer, err := encoder.Bytes([]byte(passwd))
+	if err != nil {
+		return
+	}
+	key := hashing(algorithm, saltBuf, passwordBuffer)
+	for i := 0; i < spinCount; i++ {
+		key = hashing(algorithm, key, createUInt32LEBuffer(i, 4))
+	}
+	hashValue, saltValue = base64.StdEncoding.EncodeToString(key), base64.StdEncoding.EncodeToString(saltBuf)
+	return
+}
+
 // createUInt32LEBuffer create buffer with little endian 32-bit unsigned
 // integer.
 func createUInt32LEBuffer(value int, bufferSize int) []byte {
@@ -536,8 +608,9 @@ func crypt(encrypt bool, cipherAlgorithm, cipherChaining string, key, iv, input
 	} else {
 		stream = cipher.NewCBCDecrypter(block, iv)
 	}
-	stream.CryptBlocks(input, input)
-	return input, nil
+	output := make([]byte, len(input))
+	stream.CryptBlocks(output, input)
+	return output, nil
 }
 
 // cryptPackage encrypt / decrypt package by given packageKey and encryption
@@ -624,3 +697,212 @@ func randomBytes(n int) ([]byte, error) {
 	_, err := rand.Read(b)
 	return b, err
 }
+
+// Compound File Binary Implements
+
+// cfbStream specifies a stream object in the compound file binary.
+type cfbStream struct {
+	name  string
+	data  []byte
+	start uint32
+}
+
+// cfb structure is used for the compound file binary (CFB) file format
+// writer, which is specified in [MS-CFB]. The version 3 of the file format
+// with 512 bytes sector will be written, and only stream objects in the root
+// storage are supported.
+type cfb struct {
+	streams []*cfbStream
+}
+
+// put provides a function to add a stream object into the root storage of the
+// compound file by given stream name and content.
+func (c *cfb) put(name string, data []byte) {
+	c.streams = append(c.streams, &cfbStream{name: name, data: data})
+}
+
+// write provides a function to create the compound file binary by the stream
+// objects. The streams smaller than the mini stream cutoff size will be
+// stored in the mini stream.
+func (c *cfb) write() []byte {
+	var (
+		miniStream []byte
+		miniFAT    []uint32
+		large      []*cfbStream
+	)
+	for _, stream := range c.streams {
+		if len(stream.data) >= cfbMiniStreamCutoff {
+			large = append(large, stream)
+			continue
+		}
+		stream.start = cfbEndOfChain
+		if len(stream.data) == 0 {
+			continue
+		}
+		stream.start = uint32(len(miniFAT))
+		count := cfbSectorCount(len(stream.data), cfbMiniSectorSize)
+		for i := 1; i < count; i++ {
+			miniFAT = append(miniFAT, stream.start+uint32(i))
+		}
+		miniFAT = append(miniFAT, cfbEndOfChain)
+		miniStream = append(miniStream, stream.data...)
+		miniStream = append(miniStream, make([]byte, count*cfbMiniSectorSize-len(stream.data))...)
+	}
+	dirSectors := cfbSectorCount((len(c.streams)+1)*cfbDirEntrySize, cfbSectorSize)
+	miniFATSectors := cfbSectorCount(len(miniFAT)*4, cfbSectorSize)
+	miniStreamSectors := cfbSectorCount(len(miniStream), cfbSectorSize)
+	dataSectors := dirSectors + miniFATSectors + miniStreamSectors
+	for _, stream := range large {
+		dataSectors += cfbSectorCount(len(stream.data), cfbSectorSize)
+	}
+	// Calculate the count of the FAT and DIFAT sectors, the FAT sectors should
+	// be able to hold the sector chains of all the sectors include themselves.
+	var fatSectors, difatSectors int
+	for fatSectors*cfbSectorSize/4 < dataSectors+fatSectors+difatSectors {
+		fatSectors++
+		if fatSectors > cfbHeaderDIFATCount {
+			difatSectors = cfbSectorCount(fatSectors-cfbHeaderDIFATCount, cfbSectorSize/4-1)
+		}
+	}
+	fat := make([]uint32, fatSectors*cfbSectorSize/4)
+	for i := range fat {
+		fat[i] = cfbFreeSect
+	}
+	var next uint32
+	allocate := func(count int, mark uint32) (start uint32, sectors []uint32) {
+		start = cfbEndOfChain
+		if count > 0 {
+			start = next
+		}
+		for i := 0; i < count; i++ {
+			sectors = append(sectors, next)
+			if fat[next] = next + 1; mark != 0 {
+				fat[next] = mark
+			} else if i == count-1 {
+				fat[next] = cfbEndOfChain
+			}
+			next++
+		}
+		return
+	}
+	_, fatIDs := allocate(fatSectors, cfbFATSect)
+	difatStart, difatIDs := allocate(difatSectors, cfbDIFATSect)
+	dirStart, _ := allocate(dirSectors, 0)
+	miniFATStart, _ := allocate(miniFATSectors, 0)
+	miniStreamStart, _ := allocate(miniStreamSectors, 0)
+	for _, stream := range large {
+		stream.start, _ = allocate(cfbSectorCount(len(stream.data), cfbSectorSize), 0)
+	}
+
+	buf := make([]byte, cfbSectorSize*(int(next)+1))
+	sector := func(id uint32) []byte {
+		offset := cfbSectorSize * (int(id) + 1)
+		return buf[offset:]
+	}
+	putUint32s := func(b []byte, values []uint32) {
+		for i, v := range values {
+			binary.LittleEndian.PutUint32(b[i*4:], v)
+		}
+	}
+	// Header
+	copy(buf, oleIdentifier)
+	binary.LittleEndian.PutUint16(buf[24:], 0x003E)
+	binary.LittleEndian.PutUint16(buf[26:], 0x0003)
+	binary.LittleEndian.PutUint16(buf[28:], 0xFFFE)
+	binary.LittleEndian.PutUint16(buf[30:], 0x0009)
+	binary.LittleEndian.PutUint16(buf[32:], 0x0006)
+	putUint32s(buf[44:], []uint32{uint32(fatSectors), dirStart, 0, cfbMiniStreamCutoff, miniFATStart,
+		uint32(miniFATSectors), difatStart, uint32(difatSectors)})
+	difat := make([]uint32, cfbHeaderDIFATCount+difatSectors*(cfbSectorSize/4-1))
+	for i := range difat {
+		difat[i] = cfbFreeSect
+	}
+	copy(difat, fatIDs)
+	putUint32s(buf[76:], difat[:cfbHeaderDIFATCount])
+	// FAT and DIFAT sectors
+	for i, id := range fatIDs {
+		putUint32s(sector(id), fat[i*cfbSectorSize/4:(i+1)*cfbSectorSize/4])
+	}
+	for i, id := range difatIDs {
+		entries := cfbSectorSize/4 - 1
+		nextDIFAT := uint32(cfbEndOfChain)
+		if i < len(difatIDs)-1 {
+			nextDIFAT = difatIDs[i+1]
+		}
+		offset := cfbHeaderDIFATCount + i*entries
+		putUint32s(sector(id), append(append([]uint32{}, difat[offset:offset+entries]...), nextDIFAT))
+	}
+	// Directory sectors
+	dir := sector(dirStart)
+	for i := 0; i < dirSectors*cfbSectorSize/cfbDirEntrySize; i++ {
+		putUint32s(dir[i*cfbDirEntrySize+68:], []uint32{cfbNoStream, cfbNoStream, cfbNoStream})
+	}
+	rootStart := miniStreamStart
+	if len(miniStream) == 0 {
+		rootStart = cfbEndOfChain
+	}
+	child := c.writeDirEntries(dir)
+	writeCFBDirEntry(dir, "Root Entry", 5, cfbNoStream, cfbNoStream, child, rootStart, len(miniStream))
+	// Mini FAT, mini stream and stream sectors
+	if miniFATSectors > 0 {
+		miniFATBuf := sector(miniFATStart)[:miniFATSectors*cfbSectorSize]
+		for i := range miniFATBuf {
+			miniFATBuf[i] = 0xFF
+		}
+		putUint32s(miniFATBuf, miniFAT)
+	}
+	if miniStreamSectors > 0 {
+		copy(sector(miniStreamStart), miniStream)
+	}
+	for _, stream := range large {
+		copy(sector(stream.start), stream.data)
+	}
+	return buf
+}
+
+// writeDirEntries provides a function to write the directory entries of the
+// streams as a balanced binary tree with the entries sorted by the name, and
+// returns the directory entry ID of the tree root.
+func (c *cfb) writeDirEntries(dir []byte) uint32 {
+	sort.SliceStable(c.streams, func(i, j int) bool {
+		a, b := c.streams[i].name, c.streams[j].name
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return strings.ToUpper(a) < strings.ToUpper(b)
+	})
+	var build func(lo, hi int) uint32
+	build = func(lo, hi int) uint32 {
+		if lo > hi {
+			return cfbNoStream
+		}
+		mid := (lo + hi) / 2
+		stream := c.streams[mid-1]
+		writeCFBDirEntry(dir[mid*cfbDirEntrySize:], stream.name, 2, build(lo, mid-1), build(mid+1, hi), cfbNoStream, stream.start, len(stream.data))
+		return uint32(mid)
+	}
+	return build(1, len(c.streams))
+}
+
+// writeCFBDirEntry provides a function to write a directory entry in the
+// compound file binary by given name, object type, sibling and child IDs,
+// starting sector and stream size. All entries are colored black.
+func writeCFBDirEntry(entry []byte, name string, objectType byte, left, right, child, start uint32, size int) {
+	runes := utf16.Encode([]rune(name))
+	for i, r := range runes {
+		binary.LittleEndian.PutUint16(entry[i*2:], r)
+	}
+	binary.LittleEndian.PutUint16(entry[64:], uint16((len(runes)+1)*2))
+	entry[66], entry[67] = objectType, 1
+	binary.LittleEndian.PutUint32(entry[68:], left)
+	binary.LittleEndian.PutUint32(entry[72:], right)
+	binary.LittleEndian.PutUint32(entry[76:], child)
+	binary.LittleEndian.PutUint32(entry[116:], start)
+	binary.LittleEndian.PutUint64(entry[120:], uint64(size))
+}
+
+// cfbSectorCount provides a function to calculate the count of sectors
+// required to store the given size of data.
+func cfbSectorCount(size, sectorSize int) int {
+	return (size + sectorSize - 1) / sectorSize
+}
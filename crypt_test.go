@@ -10,16 +10,104 @@
 package excelize
 
 import (
+	"bytes"
+	"io/ioutil"
+	"log"
 	"path/filepath"
 	"testing"
 
+	"github.com/richardlehane/mscfb"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestEncrypt(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "encryptSHA1.xlsx"), Options{Password: "password"})
 	assert.NoError(t, err)
-	assert.EqualError(t, f.SaveAs(filepath.Join("test", "BadEncrypt.xlsx"), Options{Password: "password"}), "not support encryption currently")
+	cell, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "passwd"}))
+
+	_, err = OpenFile(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "password"})
+	assert.EqualError(t, err, "zip: not a valid zip file")
+	f, err = OpenFile(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "passwd"})
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, cell, val)
+}
+
+func TestSaveEncrypted(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "encryptAES.xlsx"), Options{Password: "password"})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "modified"))
+	f.Path = filepath.Join("test", "TestSaveEncrypted.xlsx")
+	assert.NoError(t, f.Save())
+	// Test re-encrypt with the same password
+	f, err = OpenFile(filepath.Join("test", "TestSaveEncrypted.xlsx"), Options{Password: "password"})
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "modified", val)
+	// Test change the password
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveEncrypted.xlsx"), Options{Password: "passwd"}))
+	f, err = OpenFile(filepath.Join("test", "TestSaveEncrypted.xlsx"), Options{Password: "passwd"})
+	assert.NoError(t, err)
+	// Test remove the password
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveEncrypted.xlsx"), Options{}))
+	f, err = OpenFile(filepath.Join("test", "TestSaveEncrypted.xlsx"))
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "modified", val)
+	// Test the options of saving only apply to that saving
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveEncrypted.xlsx"), Options{Password: "password", Strict: true}))
+	assert.Nil(t, f.options)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveEncryptedCopy.xlsx")))
+	f, err = OpenFile(filepath.Join("test", "TestSaveEncryptedCopy.xlsx"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(f.readXML("xl/workbook.xml")), `conformance="strict"`)
+	// Test the options of saving keep the options specified when opening
+	logger := log.New(ioutil.Discard, "", 0)
+	f.options = &Options{CompressionLevel: 10, StoreMedia: true, Logger: logger}
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestSaveEncryptedCopy.xlsx"), Options{Password: "password"}), "invalid compression level 10")
+	assert.Equal(t, &Options{CompressionLevel: 10, StoreMedia: true, Logger: logger}, f.options)
+	assert.Equal(t, &Options{Password: "password", CompressionLevel: 1, StoreMedia: true, Logger: logger, Strict: true},
+		f.saveOptions(Options{Password: "password", CompressionLevel: 1, Strict: true}))
+	f.options = &Options{Password: "password", PrettyXML: true}
+	assert.Equal(t, &Options{CompactXML: true}, f.saveOptions(Options{CompactXML: true}))
+	// Test the save-only options specified when opening the encrypted
+	// spreadsheet are not kept
+	f, err = OpenFile(filepath.Join("test", "encryptAES.xlsx"), Options{Password: "password", PrettyXML: true, Strict: true, CompressionLevel: 1, Logger: logger})
+	assert.NoError(t, err)
+	assert.Equal(t, &Options{Password: "password", Logger: logger}, f.options)
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"), Options{Password: "password", Strict: true, Compatibility: CompatibilityLibreOffice})
+	assert.NoError(t, err)
+	assert.Nil(t, f.options)
+}
+
+func TestCFB(t *testing.T) {
+	streams := map[string][]byte{
+		"Empty":  {},
+		"Mini":   bytes.Repeat([]byte{1}, 100),
+		"Stream": bytes.Repeat([]byte{2, 3}, 4096),
+		// The stream requires more than 109 FAT sectors.
+		"Large": bytes.Repeat([]byte{4, 5, 6, 7}, 2<<20),
+	}
+	compoundFile := cfb{}
+	for _, name := range []string{"Empty", "Mini", "Stream", "Large"} {
+		compoundFile.put(name, streams[name])
+	}
+	doc, err := mscfb.New(bytes.NewReader(compoundFile.write()))
+	assert.NoError(t, err)
+	var count int
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		buf, err := ioutil.ReadAll(doc)
+		assert.NoError(t, err)
+		assert.Equal(t, len(streams[entry.Name]), len(buf), entry.Name)
+		assert.True(t, bytes.Equal(streams[entry.Name], buf), entry.Name)
+		count++
+	}
+	assert.Equal(t, 4, count)
 }
 
 func TestEncryptionMechanism(t *testing.T) {
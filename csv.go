@@ -0,0 +1,231 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVOptions directly maps the settings of exporting the worksheet to CSV.
+//
+// Delimiter specifies the field delimiter, the comma will be used if it's
+// empty.
+//
+// UseCRLF specifies using \r\n as the line ending instead of \n.
+//
+// Range specifies the cell or range to be exported, such as "B2:D10". The
+// used range of the worksheet starting from the cell A1 will be exported if
+// it's empty.
+//
+// RawCellValue specifies writing the raw values of the cells instead of the
+// values formatted by the number format of the cells.
+//
+// FillMergedCells specifies writing the value of the top-left cell of the
+// merged cell to all cells covered by it, the values of the other cells of
+// the merged cell will be omitted if it's false.
+type CSVOptions struct {
+	Delimiter       rune
+	UseCRLF         bool
+	Range           string
+	RawCellValue    bool
+	FillMergedCells bool
+}
+
+// ExportCSV provides a function to write the values of the worksheet to the
+// writer in CSV format by given worksheet name and optional settings. For
+// example, export the formatted values of the range A1:D10 on Sheet1 to a
+// semicolon separated file:
+//
+//    file, err := os.Create("Book1.csv")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    defer file.Close()
+//    err = f.ExportCSV("Sheet1", file, excelize.CSVOptions{
+//        Delimiter: ';',
+//        Range:     "A1:D10",
+//    })
+//
+func (f *File) ExportCSV(sheet string, w io.Writer, opts ...CSVOptions) error {
+	var options CSVOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	cells, err := f.getExportCells(ws, options.RawCellValue)
+	if err != nil {
+		return err
+	}
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			coordinates, err := rangeRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return err
+			}
+			topLeft := cells[[2]int{coordinates[0], coordinates[1]}]
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				for row := coordinates[1]; row <= coordinates[3]; row++ {
+					if col == coordinates[0] && row == coordinates[1] {
+						continue
+					}
+					delete(cells, [2]int{col, row})
+					if options.FillMergedCells && topLeft.Value != "" {
+						cells[[2]int{col, row}] = topLeft
+					}
+				}
+			}
+		}
+	}
+	area, err := getExportArea(options.Range, cells, false)
+	if err != nil || area == nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if options.Delimiter != 0 {
+		writer.Comma = options.Delimiter
+	}
+	writer.UseCRLF = options.UseCRLF
+	for row := area[1]; row <= area[3]; row++ {
+		record := make([]string, 0, area[2]-area[0]+1)
+		for col := area[0]; col <= area[2]; col++ {
+			record = append(record, cells[[2]int{col, row}].Value)
+		}
+		if err = writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportCell directly maps the value, type and style ID of the cell to be
+// exported.
+type exportCell struct {
+	Value   string
+	Type    string
+	StyleID int
+}
+
+// getExportCells provides a function to get the values and style IDs of all
+// cells in the worksheet indexed by the column and row number. The number
+// format of the cells will be skipped when the raw is true.
+func (f *File) getExportCells(ws *xlsxWorksheet, raw bool) (map[[2]int]exportCell, error) {
+	cells := map[[2]int]exportCell{}
+	d, err := f.sharedStringsReader()
+	if err != nil {
+		return cells, err
+	}
+	for _, r := range ws.SheetData.Row {
+		for _, c := range r.C {
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return cells, err
+			}
+			val, err := c.getValueFrom(f, d, raw)
+			if err != nil {
+				return cells, err
+			}
+			cells[[2]int{col, row}] = exportCell{Value: val, Type: c.T, StyleID: c.S}
+		}
+	}
+	return cells, nil
+}
+
+// getExportArea provides a function to get the coordinates of the range to
+// be exported by given range reference. The used range starting from the
+// cell A1 will be returned if the reference is empty, the styled cells
+// without value are counted in the used range when the withStyle is true,
+// and nil will be returned if there are no such cells.
+func getExportArea(ref string, cells map[[2]int]exportCell, withStyle bool) ([]int, error) {
+	if ref != "" {
+		return rangeRefToCoordinates(ref)
+	}
+	var area []int
+	for cell, c := range cells {
+		if c.Value == "" && (!withStyle || c.StyleID == 0) {
+			continue
+		}
+		if area == nil {
+			area = []int{1, 1, 1, 1}
+		}
+		if cell[0] > area[2] {
+			area[2] = cell[0]
+		}
+		if cell[1] > area[3] {
+			area[3] = cell[1]
+		}
+	}
+	return area, nil
+}
+
+// getExportMergeCells provides a function to get the merged cells clipped by
+// the area to be exported. The area will be expanded to cover all merged
+// cells when the expand is true. The spans of the merged cells are indexed by
+// the first cell in the area, which will be given the value and style of the
+// top-left cell of the merged cell, and the other cells covered by the merged
+// cells are returned too.
+func getExportMergeCells(ws *xlsxWorksheet, cells map[[2]int]exportCell, area []int, expand bool) ([]int, map[[2]int][2]int, map[[2]int]bool, error) {
+	var merges [][]int
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			coordinates, err := rangeRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return area, nil, nil, err
+			}
+			merges = append(merges, coordinates)
+			if !expand {
+				continue
+			}
+			if area == nil {
+				area = []int{1, 1, 1, 1}
+			}
+			if coordinates[2] > area[2] {
+				area[2] = coordinates[2]
+			}
+			if coordinates[3] > area[3] {
+				area[3] = coordinates[3]
+			}
+		}
+	}
+	spans, covered := map[[2]int][2]int{}, map[[2]int]bool{}
+	if area == nil {
+		return area, spans, covered, nil
+	}
+	for _, coordinates := range merges {
+		topLeft := cells[[2]int{coordinates[0], coordinates[1]}]
+		for i := 0; i < 2; i++ {
+			if coordinates[i] < area[i] {
+				coordinates[i] = area[i]
+			}
+			if coordinates[i+2] > area[i+2] {
+				coordinates[i+2] = area[i+2]
+			}
+		}
+		if coordinates[0] > coordinates[2] || coordinates[1] > coordinates[3] {
+			continue
+		}
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			for row := coordinates[1]; row <= coordinates[3]; row++ {
+				covered[[2]int{col, row}] = true
+			}
+		}
+		start := [2]int{coordinates[0], coordinates[1]}
+		covered[start], cells[start] = false, topLeft
+		spans[start] = [2]int{coordinates[2] - coordinates[0] + 1, coordinates[3] - coordinates[1] + 1}
+	}
+	return area, spans, covered, nil
+}
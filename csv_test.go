@@ -0,0 +1,48 @@
+package excelize
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportCSV(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Date", "C1": "Note",
+		"A2": "Foo", "B2": 44197, "C2": "a,b",
+		"A3": "Bar", "C4": "line\nbreak",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	style, err := f.NewStyle(`{"number_format":14}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "A3"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf))
+	assert.Equal(t, "Name,Date,Note\nFoo,01-01-21,\"a,b\"\n,,\n,,\"line\nbreak\"\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{
+		Delimiter:       ';',
+		UseCRLF:         true,
+		Range:           "$B$3:A2",
+		RawCellValue:    true,
+		FillMergedCells: true,
+	}))
+	assert.Equal(t, "Foo;44197\r\nFoo;\r\n", buf.String())
+
+	// Test export the empty worksheet
+	f.NewSheet("Sheet2")
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet2", &buf))
+	assert.Equal(t, "", buf.String())
+
+	// Test export with invalid options
+	assert.EqualError(t, f.ExportCSV("SheetN", &buf), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Range: "A"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Delimiter: '"'}), "csv: invalid field or comment delimiter")
+}
@@ -0,0 +1,221 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// customXMLItemIDRegexp defined the pattern of the identifier of the custom
+// XML data.
+var customXMLItemIDRegexp = regexp.MustCompile(`^\{[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}\}$`)
+
+// customXMLItem defined the custom XML data part, the custom XML data
+// properties part and the relationship of the data part in the workbook.
+type customXMLItem struct {
+	rID       string
+	itemPath  string
+	propsPath string
+	props     *decodeDatastoreItem
+}
+
+// SetCustomXMLPart provides a function to add or replace the custom XML data
+// part of the workbook by given custom XML part. The custom XML part with
+// the same ID will be replaced, a new GUID will be generated as the ID if it
+// is empty. The ID of the custom XML part will be returned. For example, add
+// a custom XML data part with the schema reference:
+//
+//    id, err := f.SetCustomXMLPart(excelize.CustomXMLPart{
+//        SchemaRefs: []string{"http://example.com/metadata"},
+//        Content:    []byte(`<metadata xmlns="http://example.com/metadata"><owner>Finance</owner></metadata>`),
+//    })
+//
+func (f *File) SetCustomXMLPart(part CustomXMLPart) (string, error) {
+	if part.ID == "" {
+		b, err := randomBytes(16)
+		if err != nil {
+			return part.ID, err
+		}
+		b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+		part.ID = fmt.Sprintf("{%X-%X-%X-%X-%X}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	}
+	if !customXMLItemIDRegexp.MatchString(part.ID) {
+		return part.ID, fmt.Errorf("invalid custom XML part ID %s", part.ID)
+	}
+	if err := checkCustomXMLContent(part.Content); err != nil {
+		return part.ID, err
+	}
+	items, err := f.customXMLItems()
+	if err != nil {
+		return part.ID, err
+	}
+	for _, item := range items {
+		if strings.EqualFold(item.props.ItemID, part.ID) {
+			f.XLSX[item.itemPath] = part.Content
+			f.setCustomXMLProps(item.itemPath, item.propsPath, part)
+			return part.ID, nil
+		}
+	}
+	idx := 1
+	for ; ; idx++ {
+		if _, ok := f.XLSX["customXml/item"+strconv.Itoa(idx)+".xml"]; !ok {
+			break
+		}
+	}
+	itemPath := "customXml/item" + strconv.Itoa(idx) + ".xml"
+	f.XLSX[itemPath] = part.Content
+	f.setCustomXMLProps(itemPath, "", part)
+	wbDir := path.Dir(f.getWorkbookPath())
+	target := itemPath
+	if wbDir != "." {
+		target = strings.Repeat("../", len(strings.Split(wbDir, "/"))) + itemPath
+	}
+	f.addRels(f.getWorkbookRelsPath(), SourceRelationshipCustomXML, target, "")
+	return part.ID, nil
+}
+
+// GetCustomXMLParts provides a function to get all custom XML data parts of
+// the workbook with their ID, schema references and content.
+func (f *File) GetCustomXMLParts() ([]CustomXMLPart, error) {
+	var parts []CustomXMLPart
+	items, err := f.customXMLItems()
+	if err != nil {
+		return parts, err
+	}
+	for _, item := range items {
+		part := CustomXMLPart{ID: item.props.ItemID, Content: f.readXML(item.itemPath)}
+		for _, ref := range item.props.SchemaRefs {
+			part.SchemaRefs = append(part.SchemaRefs, ref.URI)
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// DeleteCustomXMLPart provides a function to delete the custom XML data part
+// of the workbook by given ID, the custom XML data properties part and the
+// relationships of the custom XML data part will be removed.
+func (f *File) DeleteCustomXMLPart(ID string) error {
+	items, err := f.customXMLItems()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if !strings.EqualFold(item.props.ItemID, ID) {
+			continue
+		}
+		rels, err := f.relsReader(f.getWorkbookRelsPath())
+		if err != nil {
+			return err
+		}
+		for k, rel := range rels.Relationships {
+			if rel.ID == item.rID {
+				rels.Relationships = append(rels.Relationships[:k], rels.Relationships[k+1:]...)
+				break
+			}
+		}
+		f.deletePartWithRels(item.itemPath)
+		return nil
+	}
+	return fmt.Errorf("custom XML part %s does not exist", ID)
+}
+
+// customXMLItems provides a function to get the custom XML data parts which
+// related to the workbook.
+func (f *File) customXMLItems() ([]customXMLItem, error) {
+	var items []customXMLItem
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return nil, err
+	}
+	if rels == nil {
+		return items, nil
+	}
+	for _, rel := range rels.Relationships {
+		if rel.Type != SourceRelationshipCustomXML {
+			continue
+		}
+		item := customXMLItem{
+			rID:      rel.ID,
+			itemPath: resolvePartPath(f.getWorkbookPath(), rel.Target),
+			props:    new(decodeDatastoreItem),
+		}
+		itemRels, err := f.relsReader(getPartRelsPath(item.itemPath))
+		if err != nil {
+			return nil, err
+		}
+		if itemRels != nil {
+			for _, r := range itemRels.Relationships {
+				if r.Type == SourceRelationshipCustomXMLProps {
+					item.propsPath = resolvePartPath(item.itemPath, r.Target)
+				}
+			}
+		}
+		if item.propsPath != "" {
+			if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(item.propsPath)))).
+				Decode(item.props); err != nil && err != io.EOF {
+				return items, f.newXMLDecodeError(item.propsPath, err)
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// setCustomXMLProps provides a function to write the custom XML data
+// properties part by given custom XML data part path, properties part path
+// and custom XML part. The properties part and its relationship will be
+// created if the properties part path is empty.
+func (f *File) setCustomXMLProps(itemPath, propsPath string, part CustomXMLPart) {
+	if propsPath == "" {
+		propsPath = path.Join(path.Dir(itemPath), strings.Replace(path.Base(itemPath), "item", "itemProps", 1))
+		f.addRels(getPartRelsPath(itemPath), SourceRelationshipCustomXMLProps, path.Base(propsPath), "")
+		f.setContentTypes("/"+propsPath, ContentTypeCustomXMLProperties)
+	}
+	props := xlsxDatastoreItem{ItemID: part.ID, DS: NameSpaceCustomXML}
+	for _, uri := range part.SchemaRefs {
+		props.SchemaRefs.SchemaRef = append(props.SchemaRefs.SchemaRef, xlsxDatastoreSchemaRef{URI: uri})
+	}
+	output, _ := xml.Marshal(props)
+	f.saveFileList(propsPath, output)
+}
+
+// checkCustomXMLContent provides a function to check if the content of the
+// custom XML data part is a well-formed XML document.
+func checkCustomXMLContent(content []byte) error {
+	var root bool
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("xml decode error: %s", err)
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			root = true
+		}
+	}
+	if !root {
+		return errors.New("custom XML part content is empty")
+	}
+	return nil
+}
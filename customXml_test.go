@@ -0,0 +1,73 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomXMLPart(t *testing.T) {
+	f := NewFile()
+	parts, err := f.GetCustomXMLParts()
+	assert.NoError(t, err)
+	assert.Empty(t, parts)
+
+	content := []byte(`<metadata xmlns="http://example.com/metadata"><owner>Finance</owner></metadata>`)
+	ID, err := f.SetCustomXMLPart(CustomXMLPart{SchemaRefs: []string{"http://example.com/metadata"}, Content: content})
+	assert.NoError(t, err)
+	assert.Regexp(t, customXMLItemIDRegexp, ID)
+	_, err = f.SetCustomXMLPart(CustomXMLPart{ID: "{6C8A1C2B-3F2D-4C1E-9B3A-2E4F5A6B7C8D}", Content: []byte(`<data/>`)})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCustomXMLPart.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestCustomXMLPart.xlsx"))
+	assert.NoError(t, err)
+	parts, err = f.GetCustomXMLParts()
+	assert.NoError(t, err)
+	assert.Equal(t, []CustomXMLPart{
+		{ID: ID, SchemaRefs: []string{"http://example.com/metadata"}, Content: content},
+		{ID: "{6C8A1C2B-3F2D-4C1E-9B3A-2E4F5A6B7C8D}", Content: []byte(`<data/>`)},
+	}, parts)
+	assert.Contains(t, f.XLSX, "customXml/itemProps2.xml")
+
+	// Test replace the custom XML part
+	_, err = f.SetCustomXMLPart(CustomXMLPart{ID: "{6c8a1c2b-3f2d-4c1e-9b3a-2e4f5a6b7c8d}", SchemaRefs: []string{"urn:data"}, Content: []byte(`<data>1</data>`)})
+	assert.NoError(t, err)
+	parts, err = f.GetCustomXMLParts()
+	assert.NoError(t, err)
+	assert.Equal(t, CustomXMLPart{ID: "{6c8a1c2b-3f2d-4c1e-9b3a-2e4f5a6b7c8d}", SchemaRefs: []string{"urn:data"}, Content: []byte(`<data>1</data>`)}, parts[1])
+
+	// Test delete the custom XML part
+	assert.NoError(t, f.DeleteCustomXMLPart(ID))
+	assert.EqualError(t, f.DeleteCustomXMLPart(ID), "custom XML part "+ID+" does not exist")
+	parts, err = f.GetCustomXMLParts()
+	assert.NoError(t, err)
+	assert.Len(t, parts, 1)
+	for _, part := range []string{"customXml/item1.xml", "customXml/itemProps1.xml", "customXml/_rels/item1.xml.rels"} {
+		assert.NotContains(t, f.XLSX, part)
+	}
+	assert.NotContains(t, f.ContentTypes.Overrides, xlsxOverride{PartName: "/customXml/itemProps1.xml", ContentType: ContentTypeCustomXMLProperties})
+
+	// Test add custom XML part reuse the deleted part name
+	_, err = f.SetCustomXMLPart(CustomXMLPart{Content: content})
+	assert.NoError(t, err)
+	assert.Equal(t, content, f.XLSX["customXml/item1.xml"])
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCustomXMLPart.xlsx")))
+
+	// Test set custom XML part with invalid ID and content
+	_, err = f.SetCustomXMLPart(CustomXMLPart{ID: "ID", Content: content})
+	assert.EqualError(t, err, "invalid custom XML part ID ID")
+	_, err = f.SetCustomXMLPart(CustomXMLPart{})
+	assert.EqualError(t, err, "custom XML part content is empty")
+	_, err = f.SetCustomXMLPart(CustomXMLPart{Content: []byte(`<data>`)})
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: unexpected EOF")
+
+	// Test custom XML part with unsupported charset properties
+	f.XLSX["customXml/itemProps1.xml"] = MacintoshCyrillicCharset
+	_, err = f.GetCustomXMLParts()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	_, err = f.SetCustomXMLPart(CustomXMLPart{Content: content})
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.DeleteCustomXMLPart(ID), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
@@ -12,7 +12,11 @@
 package excelize
 
 import (
+	"bytes"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -38,6 +42,9 @@ const (
 	dataValidationFormulaStrLen = 257
 	// dataValidationFormulaStrLenErr
 	dataValidationFormulaStrLenErr = "data validation must be 0-255 characters"
+	// dataValidationListSheet is the name of the hidden worksheet which
+	// stores the values of the drop list set by SetHiddenDropList
+	dataValidationListSheet = "DataValidationLists"
 )
 
 // DataValidationErrorStyle defined the style of data validation error alert.
@@ -58,6 +65,26 @@ const (
 	styleInformation = "information"
 )
 
+// DataValidationImeMode defined the input method editor (IME) mode enforced
+// by the data validation.
+type DataValidationImeMode int
+
+// Data validation IME modes.
+const (
+	_ DataValidationImeMode = iota
+	DataValidationImeModeNoControl
+	DataValidationImeModeOff
+	DataValidationImeModeOn
+	DataValidationImeModeDisabled
+	DataValidationImeModeHiragana
+	DataValidationImeModeFullKatakana
+	DataValidationImeModeHalfKatakana
+	DataValidationImeModeFullAlpha
+	DataValidationImeModeHalfAlpha
+	DataValidationImeModeFullHangul
+	DataValidationImeModeHalfHangul
+)
+
 // DataValidationOperator operator enum.
 type DataValidationOperator int
 
@@ -87,6 +114,18 @@ func NewDataValidation(allowBlank bool) *DataValidation {
 func (dd *DataValidation) SetError(style DataValidationErrorStyle, title, msg string) {
 	dd.Error = &msg
 	dd.ErrorTitle = &title
+	dd.ShowErrorMessage = true
+	dd.SetErrorStyle(style)
+}
+
+// SetErrorStyle provides a function to set the style of the error alert
+// which displayed when the invalid data was entered, without changing the
+// title and message of the error alert. For example, only warn the invalid
+// data and allow to enter it:
+//
+//     dvRange.SetErrorStyle(excelize.DataValidationErrorStyleWarning)
+//
+func (dd *DataValidation) SetErrorStyle(style DataValidationErrorStyle) {
 	strStyle := styleStop
 	switch style {
 	case DataValidationErrorStyleStop:
@@ -95,12 +134,33 @@ func (dd *DataValidation) SetError(style DataValidationErrorStyle, title, msg st
 		strStyle = styleWarning
 	case DataValidationErrorStyleInformation:
 		strStyle = styleInformation
-
 	}
-	dd.ShowErrorMessage = true
 	dd.ErrorStyle = &strStyle
 }
 
+// SetAllowBlank provides a function to set whether the blank cells are
+// treated as valid data.
+func (dd *DataValidation) SetAllowBlank(allowBlank bool) {
+	dd.AllowBlank = allowBlank
+}
+
+// SetHideDropDown provides a function to set whether to suppress the in-cell
+// dropdown arrow of the list data validation. Note that the dropdown arrow
+// is hidden when the showDropDown attribute of the data validation is true.
+func (dd *DataValidation) SetHideDropDown(hide bool) {
+	dd.ShowDropDown = hide
+}
+
+// SetImeMode provides a function to set the input method editor (IME) mode
+// enforced when the cells of the data validation are selected. For example,
+// turn off the IME when entering data:
+//
+//     dvRange.SetImeMode(excelize.DataValidationImeModeOff)
+//
+func (dd *DataValidation) SetImeMode(mode DataValidationImeMode) {
+	dd.ImeMode = convDataValidationImeMode(mode)
+}
+
 // SetInput set prompt notice.
 func (dd *DataValidation) SetInput(title, msg string) {
 	dd.ShowInputMessage = true
@@ -114,7 +174,31 @@ func (dd *DataValidation) SetDropList(keys []string) error {
 	if dataValidationFormulaStrLen < len(formula) {
 		return fmt.Errorf(dataValidationFormulaStrLenErr)
 	}
-	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", formula)
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(formula))
+	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", buf.String())
+	dd.Type = convDataValidationType(typeList)
+	return nil
+}
+
+// SetHiddenDropList provides a function to set data validation list without
+// the 255 characters limit of the SetDropList function. The values of the
+// list will be written into a column of the hidden worksheet named
+// DataValidationLists when the data validation is added by the
+// AddDataValidation function, and the data validation will reference to the
+// cell range of the values. For example, set data validation on Sheet1!A1:A10
+// with a long list:
+//
+//     dvRange := excelize.NewDataValidation(true)
+//     dvRange.Sqref = "A1:A10"
+//     dvRange.SetHiddenDropList(keys)
+//     f.AddDataValidation("Sheet1", dvRange)
+//
+func (dd *DataValidation) SetHiddenDropList(keys []string) error {
+	if len(keys) == 0 {
+		return errors.New("data validation list must not be empty")
+	}
+	dd.hiddenKeys = append([]string{}, keys...)
 	dd.Type = convDataValidationType(typeList)
 	return nil
 }
@@ -146,13 +230,24 @@ func (dd *DataValidation) SetRange(f1, f2 float64, t DataValidationType, o DataV
 //     dvRange.SetSqrefDropList("$E$1:$E$3", true)
 //     f.AddDataValidation("Sheet1", dvRange)
 //
+// The source reference range on other worksheet should be specified with the
+// worksheet name and set isCurrentSheet as false, such data validation will
+// be stored in the extension list of the worksheet, which supported by Excel
+// 2010 and later. For example, set data validation on Sheet1!A9:B10 with
+// validation criteria source Sheet2!A1:A3:
+//
+//     dvRange = excelize.NewDataValidation(true)
+//     dvRange.Sqref = "A9:B10"
+//     dvRange.SetSqrefDropList("Sheet2!$A$1:$A$3", false)
+//     f.AddDataValidation("Sheet1", dvRange)
+//
 func (dd *DataValidation) SetSqrefDropList(sqref string, isCurrentSheet bool) error {
-	if isCurrentSheet {
-		dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", sqref)
-		dd.Type = convDataValidationType(typeList)
-		return nil
-	}
-	return fmt.Errorf("cross-sheet sqref cell are not supported")
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(sqref))
+	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", buf.String())
+	dd.Type = convDataValidationType(typeList)
+	dd.crossSheet = !isCurrentSheet
+	return nil
 }
 
 // SetSqref provides function to set data validation range in drop list.
@@ -181,6 +276,26 @@ func convDataValidationType(t DataValidationType) string {
 
 }
 
+// convDataValidationImeMode get excel data validation IME mode.
+func convDataValidationImeMode(m DataValidationImeMode) string {
+	typeMap := map[DataValidationImeMode]string{
+		DataValidationImeModeNoControl:    "noControl",
+		DataValidationImeModeOff:          "off",
+		DataValidationImeModeOn:           "on",
+		DataValidationImeModeDisabled:     "disabled",
+		DataValidationImeModeHiragana:     "hiragana",
+		DataValidationImeModeFullKatakana: "fullKatakana",
+		DataValidationImeModeHalfKatakana: "halfKatakana",
+		DataValidationImeModeFullAlpha:    "fullAlpha",
+		DataValidationImeModeHalfAlpha:    "halfAlpha",
+		DataValidationImeModeFullHangul:   "fullHangul",
+		DataValidationImeModeHalfHangul:   "halfHangul",
+	}
+
+	return typeMap[m]
+
+}
+
 // convDataValidationOperatior get excel data validation operator.
 func convDataValidationOperatior(o DataValidationOperator) string {
 	typeMap := map[DataValidationOperator]string{
@@ -234,6 +349,22 @@ func (f *File) AddDataValidation(sheet string, dv *DataValidation) error {
 	if err != nil {
 		return err
 	}
+	if dv.hiddenKeys != nil {
+		if err = f.setDataValidationHiddenList(dv); err != nil {
+			return err
+		}
+	}
+	if dv.crossSheet {
+		dvs, err := f.getX14DataValidations(ws)
+		if err != nil {
+			return err
+		}
+		if err = f.setX14DataValidations(ws, append(dvs, dv)); err != nil {
+			return err
+		}
+		f.addSheetNameSpace(sheet, NameSpaceSpreadSheetX14)
+		return err
+	}
 	if nil == ws.DataValidations {
 		ws.DataValidations = new(xlsxDataValidations)
 	}
@@ -242,26 +373,351 @@ func (f *File) AddDataValidation(sheet string, dv *DataValidation) error {
 	return err
 }
 
+// GetDataValidations provides a function to get the data validations of the
+// worksheet by given worksheet name. Each data validation contains the cell
+// range, the type, the operator, the formulas, the input message and the
+// error alert settings, and can be added to other worksheets by the
+// AddDataValidation function. The formulas are in the form which set by the
+// SetRange, SetDropList and SetSqrefDropList functions, such as
+// <formula1>10</formula1>. For example, get the data validations on Sheet1:
+//
+//     dvs, err := f.GetDataValidations("Sheet1")
+//     if err != nil {
+//         fmt.Println(err)
+//         return
+//     }
+//     for _, dv := range dvs {
+//         fmt.Println(dv.Sqref, dv.Type, dv.Operator, dv.Formula1, dv.Formula2)
+//     }
+//
+func (f *File) GetDataValidations(sheet string) ([]*DataValidation, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var dvs []*DataValidation
+	if ws.DataValidations != nil {
+		for _, dv := range ws.DataValidations.DataValidation {
+			if dv == nil {
+				continue
+			}
+			item := *dv
+			decodeFormulas, err := f.getDataValidationFormulas(dv)
+			if err != nil {
+				return dvs, err
+			}
+			item.Formula1 = encodeDataValidationFormula("formula1", decodeFormulas.Formula1)
+			item.Formula2 = encodeDataValidationFormula("formula2", decodeFormulas.Formula2)
+			dvs = append(dvs, &item)
+		}
+	}
+	x14DataValidations, err := f.getX14DataValidations(ws)
+	return append(dvs, x14DataValidations...), err
+}
+
+// getDataValidationFormulas provides a function to get the formulas of the
+// data validation.
+func (f *File) getDataValidationFormulas(dv *DataValidation) (*decodeDataValidationFormulas, error) {
+	decodeFormulas := new(decodeDataValidationFormulas)
+	if err := f.xmlNewDecoder(strings.NewReader("<dataValidation>" + dv.Formula1 + dv.Formula2 + "</dataValidation>")).
+		Decode(decodeFormulas); err != nil && err != io.EOF {
+		return decodeFormulas, err
+	}
+	return decodeFormulas, nil
+}
+
+// encodeDataValidationFormula provides a function to convert the formula of
+// the data validation to the formula1 or formula2 element.
+func encodeDataValidationFormula(name string, formula *string) string {
+	if formula == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(*formula))
+	return fmt.Sprintf("<%s>%s</%s>", name, buf.String(), name)
+}
+
+// setDataValidationHiddenList provides a function to write the values of the
+// drop list into a new column of the hidden worksheet, and set the formula
+// of the data validation to reference the cell range of the values.
+func (f *File) setDataValidationHiddenList(dv *DataValidation) error {
+	if f.GetSheetIndex(dataValidationListSheet) == -1 {
+		f.NewSheet(dataValidationListSheet)
+		if err := f.SetSheetVisible(dataValidationListSheet, false); err != nil {
+			return err
+		}
+	}
+	rows, err := f.GetRows(dataValidationListSheet)
+	if err != nil {
+		return err
+	}
+	col := 1
+	for _, row := range rows {
+		if len(row) >= col {
+			col = len(row) + 1
+		}
+	}
+	for idx, key := range dv.hiddenKeys {
+		cell, err := CoordinatesToCellName(col, idx+1)
+		if err != nil {
+			return err
+		}
+		if err = f.SetCellStr(dataValidationListSheet, cell, key); err != nil {
+			return err
+		}
+	}
+	colName, err := ColumnNumberToName(col)
+	if err != nil {
+		return err
+	}
+	dv.Formula1 = fmt.Sprintf("<formula1>%s!$%s$1:$%s$%d</formula1>", dataValidationListSheet, colName, colName, len(dv.hiddenKeys))
+	dv.crossSheet, dv.hiddenKeys = true, nil
+	return err
+}
+
+// getX14DataValidations provides a function to get the data validations
+// with the cross-sheet references which stored in the extension list of the
+// worksheet.
+func (f *File) getX14DataValidations(ws *xlsxWorksheet) ([]*DataValidation, error) {
+	var dvs []*DataValidation
+	if ws.ExtLst == nil {
+		return dvs, nil
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return dvs, err
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if !strings.EqualFold(ext.URI, ExtURIDataValidations) {
+			continue
+		}
+		decodeDataValidations := new(decodeX14DataValidations)
+		if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(decodeDataValidations); err != nil && err != io.EOF {
+			return dvs, err
+		}
+		for _, v := range decodeDataValidations.DataValidation {
+			dv := &DataValidation{
+				AllowBlank:       v.AllowBlank,
+				Error:            v.Error,
+				ErrorStyle:       v.ErrorStyle,
+				ErrorTitle:       v.ErrorTitle,
+				ImeMode:          v.ImeMode,
+				Operator:         v.Operator,
+				Prompt:           v.Prompt,
+				PromptTitle:      v.PromptTitle,
+				ShowDropDown:     v.ShowDropDown,
+				ShowErrorMessage: v.ShowErrorMessage,
+				ShowInputMessage: v.ShowInputMessage,
+				Sqref:            v.Sqref,
+				Type:             v.Type,
+				crossSheet:       true,
+			}
+			if v.Formula1 != nil {
+				dv.Formula1 = encodeDataValidationFormula("formula1", &v.Formula1.F)
+			}
+			if v.Formula2 != nil {
+				dv.Formula2 = encodeDataValidationFormula("formula2", &v.Formula2.F)
+			}
+			dvs = append(dvs, dv)
+		}
+	}
+	return dvs, nil
+}
+
+// setX14DataValidations provides a function to write the data validations
+// with the cross-sheet references into the extension list of the worksheet,
+// the extension will be removed if no data validation given.
+func (f *File) setX14DataValidations(ws *xlsxWorksheet, dvs []*DataValidation) error {
+	var content string
+	if len(dvs) > 0 {
+		dataValidations := xlsxX14DataValidations{
+			XMLNSXM: NameSpaceSpreadSheetExcel2006Main.Value,
+			Count:   len(dvs),
+		}
+		for _, dv := range dvs {
+			decodeFormulas, err := f.getDataValidationFormulas(dv)
+			if err != nil {
+				return err
+			}
+			item := &xlsxX14DataValidation{
+				AllowBlank:       dv.AllowBlank,
+				Error:            dv.Error,
+				ErrorStyle:       dv.ErrorStyle,
+				ErrorTitle:       dv.ErrorTitle,
+				ImeMode:          dv.ImeMode,
+				Operator:         dv.Operator,
+				Prompt:           dv.Prompt,
+				PromptTitle:      dv.PromptTitle,
+				ShowDropDown:     dv.ShowDropDown,
+				ShowErrorMessage: dv.ShowErrorMessage,
+				ShowInputMessage: dv.ShowInputMessage,
+				Type:             dv.Type,
+				Sqref:            dv.Sqref,
+			}
+			if decodeFormulas.Formula1 != nil {
+				item.Formula1 = &xlsxX14Formula{F: *decodeFormulas.Formula1}
+			}
+			if decodeFormulas.Formula2 != nil {
+				item.Formula2 = &xlsxX14Formula{F: *decodeFormulas.Formula2}
+			}
+			dataValidations.DataValidation = append(dataValidations.DataValidation, item)
+		}
+		dataValidationsBytes, _ := xml.Marshal(dataValidations)
+		content = string(dataValidationsBytes)
+	}
+	return f.setWorksheetExt(ws, ExtURIDataValidations, content, ExtURIConditionalFormattings)
+}
+
 // DeleteDataValidation delete data validation by given worksheet name and
-// reference sequence.
-func (f *File) DeleteDataValidation(sheet, sqref string) error {
+// reference sequences. The cells in the reference sequence will be removed
+// from the ranges of the data validations, and the data validations which
+// don't contain any cell will be deleted. All data validations of the
+// worksheet will be deleted if the reference sequence isn't specified. For
+// example, delete the data validations on the cells Sheet1!A1:B2 and
+// Sheet1!D1:D5, and delete all data validations on Sheet2:
+//
+//     err := f.DeleteDataValidation("Sheet1", "A1:B2", "D1:D5")
+//     err = f.DeleteDataValidation("Sheet2")
+//
+func (f *File) DeleteDataValidation(sheet string, sqref ...string) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	if ws.DataValidations == nil {
-		return nil
+	if len(sqref) == 0 {
+		ws.DataValidations = nil
+		return f.setX14DataValidations(ws, nil)
+	}
+	delRef := strings.Join(sqref, " ")
+	delCoordinates, err := sqrefToCoordinates(delRef)
+	if err != nil {
+		return err
+	}
+	if ws.DataValidations != nil {
+		dv := ws.DataValidations
+		if dv.DataValidation, err = deleteDataValidations(dv.DataValidation, delRef, delCoordinates); err != nil {
+			return err
+		}
+		dv.Count = len(dv.DataValidation)
+		if dv.Count == 0 {
+			ws.DataValidations = nil
+		}
+	}
+	dvs, err := f.getX14DataValidations(ws)
+	if err != nil || len(dvs) == 0 {
+		return err
+	}
+	if dvs, err = deleteDataValidations(dvs, delRef, delCoordinates); err != nil {
+		return err
 	}
-	dv := ws.DataValidations
-	for i := 0; i < len(dv.DataValidation); i++ {
-		if dv.DataValidation[i].Sqref == sqref {
-			dv.DataValidation = append(dv.DataValidation[:i], dv.DataValidation[i+1:]...)
+	return f.setX14DataValidations(ws, dvs)
+}
+
+// deleteDataValidations provides a function to remove the cells from the
+// ranges of the data validations by given reference sequence and the
+// coordinates of it, and returns the data validations which still contain
+// cells.
+func deleteDataValidations(dvs []*DataValidation, delRef string, delCoordinates [][]int) ([]*DataValidation, error) {
+	for i := 0; i < len(dvs); i++ {
+		if dvs[i].Sqref == delRef {
+			dvs = append(dvs[:i], dvs[i+1:]...)
 			i--
+			continue
+		}
+		coordinates, err := sqrefToCoordinates(dvs[i].Sqref)
+		if err != nil {
+			return dvs, err
+		}
+		for _, del := range delCoordinates {
+			coordinates = subtractCoordinates(coordinates, del)
+		}
+		if len(coordinates) == 0 {
+			dvs = append(dvs[:i], dvs[i+1:]...)
+			i--
+			continue
+		}
+		if dvs[i].Sqref, err = coordinatesToSqref(coordinates); err != nil {
+			return dvs, err
 		}
 	}
-	dv.Count = len(dv.DataValidation)
-	if dv.Count == 0 {
-		ws.DataValidations = nil
+	return dvs, nil
+}
+
+// sqrefToCoordinates provides a function to convert the space-separated
+// reference sequence to the coordinates of the cell ranges.
+func sqrefToCoordinates(sqref string) ([][]int, error) {
+	var coordinates [][]int
+	for _, ref := range strings.Fields(sqref) {
+		cells := strings.Split(ref, ":")
+		if len(cells) == 1 {
+			cells = append(cells, cells[0])
+		}
+		if len(cells) != 2 {
+			return coordinates, fmt.Errorf("invalid range %q", ref)
+		}
+		rect, err := areaRangeToCoordinates(cells[0], cells[1])
+		if err != nil {
+			return coordinates, err
+		}
+		_ = sortCoordinates(rect)
+		coordinates = append(coordinates, rect)
 	}
-	return nil
+	return coordinates, nil
+}
+
+// coordinatesToSqref provides a function to convert the coordinates of the
+// cell ranges to the space-separated reference sequence, the single cell
+// range will be converted to the cell name.
+func coordinatesToSqref(coordinates [][]int) (string, error) {
+	var refs []string
+	for _, rect := range coordinates {
+		ref, err := CoordinatesToCellName(rect[0], rect[1])
+		if err != nil {
+			return "", err
+		}
+		if rect[0] != rect[2] || rect[1] != rect[3] {
+			lastCell, err := CoordinatesToCellName(rect[2], rect[3])
+			if err != nil {
+				return "", err
+			}
+			ref += ":" + lastCell
+		}
+		refs = append(refs, ref)
+	}
+	return strings.Join(refs, " "), nil
+}
+
+// subtractCoordinates provides a function to remove the cell range from the
+// cell ranges by given coordinates, each cell range which intersects with the
+// removed range will be split into up to four cell ranges.
+func subtractCoordinates(coordinates [][]int, del []int) [][]int {
+	var result [][]int
+	for _, rect := range coordinates {
+		if del[0] > rect[2] || del[2] < rect[0] || del[1] > rect[3] || del[3] < rect[1] {
+			result = append(result, rect)
+			continue
+		}
+		if rect[1] < del[1] {
+			result = append(result, []int{rect[0], rect[1], rect[2], del[1] - 1})
+		}
+		top, bottom := rect[1], rect[3]
+		if del[1] > top {
+			top = del[1]
+		}
+		if del[3] < bottom {
+			bottom = del[3]
+		}
+		if rect[0] < del[0] {
+			result = append(result, []int{rect[0], top, del[0] - 1, bottom})
+		}
+		if rect[2] > del[2] {
+			result = append(result, []int{del[2] + 1, top, rect[2], bottom})
+		}
+		if rect[3] > del[3] {
+			result = append(result, []int{rect[0], del[3] + 1, rect[2], rect[3]})
+		}
+	}
+	return result
 }
@@ -58,14 +58,11 @@ func TestDataValidationError(t *testing.T) {
 	dvRange.SetSqref("A7:B8")
 	assert.NoError(t, dvRange.SetSqrefDropList("$E$1:$E$3", true))
 
-	err := dvRange.SetSqrefDropList("$E$1:$E$3", false)
-	assert.EqualError(t, err, "cross-sheet sqref cell are not supported")
-
 	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
 	assert.NoError(t, f.SaveAs(resultFile))
 
 	dvRange = NewDataValidation(true)
-	err = dvRange.SetDropList(make([]string, 258))
+	err := dvRange.SetDropList(make([]string, 258))
 	if dvRange.Formula1 != "" {
 		t.Errorf("data validation error. Formula1 must be empty!")
 		return
@@ -86,6 +83,135 @@ func TestDataValidationError(t *testing.T) {
 	assert.EqualError(t, f.AddDataValidation("SheetN", nil), "sheet SheetN is not exist")
 }
 
+func TestDataValidationOptions(t *testing.T) {
+	f := NewFile()
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "A1:A5"
+	assert.NoError(t, dvRange.SetDropList([]string{"1", "2", "3"}))
+	dvRange.SetError(DataValidationErrorStyleStop, "error title", "error body")
+	dvRange.SetErrorStyle(DataValidationErrorStyleInformation)
+	dvRange.SetAllowBlank(false)
+	dvRange.SetHideDropDown(true)
+	dvRange.SetImeMode(DataValidationImeModeHiragana)
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "B1:B5"
+	assert.NoError(t, dvRange.SetSqrefDropList("Sheet2!$A$1:$A$3", false))
+	dvRange.SetErrorStyle(DataValidationErrorStyleWarning)
+	dvRange.SetImeMode(DataValidationImeModeOff)
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDataValidationOptions.xlsx")))
+
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, dvs, 2) {
+		assert.Equal(t, "information", *dvs[0].ErrorStyle)
+		assert.Equal(t, "error title", *dvs[0].ErrorTitle)
+		assert.False(t, dvs[0].AllowBlank)
+		assert.True(t, dvs[0].ShowDropDown)
+		assert.Equal(t, "hiragana", dvs[0].ImeMode)
+		assert.Equal(t, "warning", *dvs[1].ErrorStyle)
+		assert.Equal(t, "off", dvs[1].ImeMode)
+	}
+
+	// Test set data validation with invalid error style and IME mode.
+	dvRange.SetErrorStyle(DataValidationErrorStyle(0))
+	assert.Equal(t, "stop", *dvRange.ErrorStyle)
+	dvRange.SetImeMode(DataValidationImeMode(0))
+	assert.Empty(t, dvRange.ImeMode)
+}
+
+func TestCrossSheetDataValidation(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetSheetRow("Sheet2", "A1", &[]string{"A", "B", "C"}))
+
+	// Test add data validation with cross-sheet reference after sparklines.
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOption{
+		Location: []string{"F1"},
+		Range:    []string{"Sheet2!A1:C1"},
+	}))
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "A1:A5"
+	assert.NoError(t, dvRange.SetSqrefDropList("Sheet2!$A$1:$C$1", false))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	dvRange = NewDataValidation(false)
+	dvRange.Sqref = "B1:B5"
+	dvRange.SetInput("input title", "input & body")
+	assert.NoError(t, dvRange.SetSqrefDropList("'Sheet 2'!$A$1:$A$3", false))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+
+	// Test add long list data validation via hidden worksheet.
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = strings.Repeat("s", i+1)
+	}
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "C1:C5"
+	assert.EqualError(t, dvRange.SetDropList(keys), "data validation must be 0-255 characters")
+	assert.EqualError(t, dvRange.SetHiddenDropList(nil), "data validation list must not be empty")
+	assert.NoError(t, dvRange.SetHiddenDropList(keys))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.NoError(t, dvRange.SetHiddenDropList(keys[:2]))
+	assert.NoError(t, f.AddDataValidation("Sheet2", dvRange))
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Equal(t, "hidden", wb.Sheets.Sheet[2].State)
+	cell, err := f.GetCellValue("DataValidationLists", "A100")
+	assert.NoError(t, err)
+	assert.Equal(t, keys[99], cell)
+	cell, err = f.GetCellValue("DataValidationLists", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, keys[1], cell)
+
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, dvs, 3) {
+		assert.Equal(t, "A1:A5", dvs[0].Sqref)
+		assert.Equal(t, "<formula1>Sheet2!$A$1:$C$1</formula1>", dvs[0].Formula1)
+		assert.Equal(t, "input & body", *dvs[1].Prompt)
+		assert.Equal(t, "<formula1>&#39;Sheet 2&#39;!$A$1:$A$3</formula1>", dvs[1].Formula1)
+		assert.Equal(t, "<formula1>DataValidationLists!$A$1:$A$100</formula1>", dvs[2].Formula1)
+	}
+	dvs, err = f.GetDataValidations("Sheet2")
+	assert.NoError(t, err)
+	if assert.Len(t, dvs, 1) {
+		assert.Equal(t, "<formula1>DataValidationLists!$B$1:$B$2</formula1>", dvs[0].Formula1)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCrossSheetDataValidation.xlsx")))
+
+	// Test delete data validations with cross-sheet reference.
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:B3"))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, dvs, 3) {
+		assert.Equal(t, "A4:A5", dvs[0].Sqref)
+		assert.Equal(t, "B4:B5", dvs[1].Sqref)
+	}
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:C5"))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, dvs)
+	assert.NoError(t, f.DeleteDataValidation("Sheet2"))
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.ExtLst)
+
+	// Test get and delete data validations with invalid extension list.
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ExtLst.Ext = "<ext uri=\"" + ExtURIDataValidations + "\"><x14:dataValidations></ext>"
+	_, err = f.GetDataValidations("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: element <dataValidations> closed by </ext>")
+	assert.EqualError(t, f.DeleteDataValidation("Sheet1", "A1"), "XML syntax error on line 1: element <dataValidations> closed by </ext>")
+	assert.EqualError(t, f.AddDataValidation("Sheet1", dvRange), "XML syntax error on line 1: element <dataValidations> closed by </ext>")
+	ws.ExtLst.Ext = "<ext>"
+	_, err = f.GetDataValidations("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: element <ext> closed by </extLst>")
+	assert.EqualError(t, f.DeleteDataValidation("Sheet1"), "XML syntax error on line 1: element <ext> closed by </extLst>")
+}
+
 func TestDeleteDataValidation(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:B2"))
@@ -99,6 +225,101 @@ func TestDeleteDataValidation(t *testing.T) {
 	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:B2"))
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteDataValidation.xlsx")))
 
+	// Test delete part of the cell range of the data validations.
+	dvRange.Sqref = "A1:C3"
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	dvList := NewDataValidation(true)
+	dvList.Sqref = "E1 E3:E4"
+	assert.NoError(t, dvList.SetDropList([]string{"1", "2"}))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvList))
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "B2", "E3:E5"))
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, dvs, 2) {
+		assert.Equal(t, "A1:C1 A2 C2 A3:C3", dvs[0].Sqref)
+		assert.Equal(t, "E1", dvs[1].Sqref)
+	}
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "E1:E2"))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 1)
+
+	// Test delete all data validations of the worksheet.
+	assert.NoError(t, f.DeleteDataValidation("Sheet1"))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, dvs)
+
+	// Test delete data validation with invalid reference sequence.
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.EqualError(t, f.DeleteDataValidation("Sheet1", "A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.DeleteDataValidation("Sheet1", "A1:B2:C3"), `invalid range "A1:B2:C3"`)
+	ws, ok := f.Sheet["xl/worksheets/sheet1.xml"]
+	assert.True(t, ok)
+	ws.DataValidations.DataValidation[0].Sqref = "A"
+	assert.EqualError(t, f.DeleteDataValidation("Sheet1", "A1"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
 	// Test delete data validation on no exists worksheet.
 	assert.EqualError(t, f.DeleteDataValidation("SheetN", "A1:B2"), "sheet SheetN is not exist")
 }
+
+func TestGetDataValidations(t *testing.T) {
+	f := NewFile()
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, dvs)
+
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "A1:B2"
+	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	dvRange.SetError(DataValidationErrorStyleWarning, "error title", "error body")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	dvRange = NewDataValidation(false)
+	dvRange.Sqref = "C1:C5"
+	assert.NoError(t, dvRange.SetDropList([]string{"A&B", "C"}))
+	dvRange.SetInput("input title", "input body")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	path := filepath.Join("test", "TestGetDataValidations.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 2)
+	assert.Equal(t, "A1:B2", dvs[0].Sqref)
+	assert.Equal(t, "whole", dvs[0].Type)
+	assert.Equal(t, "between", dvs[0].Operator)
+	assert.Equal(t, "<formula1>10.000000</formula1>", dvs[0].Formula1)
+	assert.Equal(t, "<formula2>20.000000</formula2>", dvs[0].Formula2)
+	assert.Equal(t, "warning", *dvs[0].ErrorStyle)
+	assert.Equal(t, "error title", *dvs[0].ErrorTitle)
+	assert.Equal(t, "error body", *dvs[0].Error)
+	assert.True(t, dvs[0].AllowBlank)
+	assert.True(t, dvs[0].ShowErrorMessage)
+	assert.Equal(t, "C1:C5", dvs[1].Sqref)
+	assert.Equal(t, "list", dvs[1].Type)
+	assert.Equal(t, "<formula1>&#34;A&amp;B,C&#34;</formula1>", dvs[1].Formula1)
+	assert.Empty(t, dvs[1].Formula2)
+	assert.Equal(t, "input title", *dvs[1].PromptTitle)
+	assert.Equal(t, "input body", *dvs[1].Prompt)
+	assert.True(t, dvs[1].ShowInputMessage)
+
+	// Test migrate the data validations to another worksheet.
+	f.NewSheet("Sheet2")
+	for _, dv := range dvs {
+		assert.NoError(t, f.AddDataValidation("Sheet2", dv))
+	}
+	migrated, err := f.GetDataValidations("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, dvs, migrated)
+
+	// Test get data validations with invalid settings.
+	_, err = f.GetDataValidations("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.DataValidations.DataValidation[0].Formula1 = string(MacintoshCyrillicCharset)
+	_, err = f.GetDataValidations("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
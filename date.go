@@ -18,19 +18,21 @@ import (
 )
 
 const (
-	dayNanoseconds = 24 * time.Hour
-	maxDuration    = 290 * 364 * dayNanoseconds
+	dayNanoseconds      = 24 * time.Hour
+	maxDuration         = 290 * 364 * dayNanoseconds
+	excelDate1904Offset = 1462
 )
 
 var (
 	excelMinTime1900      = time.Date(1899, time.December, 31, 0, 0, 0, 0, time.UTC)
+	excelMinTime1904      = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
 	excelBuggyPeriodStart = time.Date(1900, time.March, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
 )
 
-// timeToExcelTime provides a function to convert time to Excel time.
-func timeToExcelTime(t time.Time) (float64, error) {
-	// TODO in future this should probably also handle date1904 and like TimeFromExcelTime
-
+// timeToExcelTime provides a function to convert time to Excel time by
+// given time and date system of the workbook. The time before the beginning
+// of the date system will be converted to 0.
+func timeToExcelTime(t time.Time, date1904 bool) (float64, error) {
 	// Force user to explicit convet passed value to UTC time.
 	// Because for example 1900-01-01 00:00:00 +0300 MSK converts to 1900-01-01 00:00:00 +0230 LMT
 	// probably due to daylight saving.
@@ -38,7 +40,7 @@ func timeToExcelTime(t time.Time) (float64, error) {
 		return 0.0, errors.New("only UTC time expected")
 	}
 
-	if t.Before(excelMinTime1900) {
+	if t.Before(excelMinTime1900) || (date1904 && t.Before(excelMinTime1904)) {
 		return 0.0, nil
 	}
 
@@ -63,6 +65,9 @@ func timeToExcelTime(t time.Time) (float64, error) {
 	if t.After(excelBuggyPeriodStart) {
 		result += 1.0
 	}
+	if date1904 {
+		result -= excelDate1904Offset
+	}
 	return result, nil
 }
 
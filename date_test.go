@@ -39,7 +39,7 @@ var excelTimeInputList = []dateTest{
 func TestTimeToExcelTime(t *testing.T) {
 	for i, test := range trueExpectedDateList {
 		t.Run(fmt.Sprintf("TestData%d", i+1), func(t *testing.T) {
-			excelTime, err := timeToExcelTime(test.GoValue)
+			excelTime, err := timeToExcelTime(test.GoValue, false)
 			assert.NoError(t, err)
 			assert.Equalf(t, test.ExcelValue, excelTime,
 				"Time: %s", test.GoValue.String())
@@ -47,6 +47,21 @@ func TestTimeToExcelTime(t *testing.T) {
 	}
 }
 
+func TestTimeToExcelTime_1904(t *testing.T) {
+	for _, test := range []dateTest{
+		{0, time.Date(1903, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{0, time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{39813.5, time.Date(2013, 1, 1, 12, 0, 0, 0, time.UTC)},
+	} {
+		excelTime, err := timeToExcelTime(test.GoValue, true)
+		assert.NoError(t, err)
+		assert.Equal(t, test.ExcelValue, excelTime)
+		if excelTime > 0 {
+			assert.Equal(t, test.GoValue, timeFromExcelTime(excelTime, true))
+		}
+	}
+}
+
 func TestTimeToExcelTime_Timezone(t *testing.T) {
 	location, err := time.LoadLocation("America/Los_Angeles")
 	if !assert.NoError(t, err) {
@@ -54,7 +69,7 @@ func TestTimeToExcelTime_Timezone(t *testing.T) {
 	}
 	for i, test := range trueExpectedDateList {
 		t.Run(fmt.Sprintf("TestData%d", i+1), func(t *testing.T) {
-			_, err := timeToExcelTime(test.GoValue.In(location))
+			_, err := timeToExcelTime(test.GoValue.In(location), false)
 			assert.EqualError(t, err, "only UTC time expected")
 		})
 	}
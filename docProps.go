@@ -14,9 +14,13 @@ package excelize
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // SetDocProps provides a function to set document core properties. The
@@ -38,6 +42,8 @@ import (
 //     LastModifiedBy | The user who performed the last modification. The identification is
 //                    | environment-specific.
 //                    |
+//     LastPrinted    | The date and time of the last printing, in W3CDTF format.
+//                    |
 //     Language       | The language of the intellectual content of the resource.
 //                    |
 //     Identifier     | An unambiguous reference to the resource within a given context.
@@ -62,6 +68,7 @@ import (
 //        Identifier:     "xlsx",
 //        Keywords:       "Spreadsheet",
 //        LastModifiedBy: "Go Author",
+//        LastPrinted:    "2019-06-04T22:00:10Z",
 //        Modified:       "2019-06-04T22:00:10Z",
 //        Revision:       "0",
 //        Subject:        "Test Subject",
@@ -83,7 +90,7 @@ func (f *File) SetDocProps(docProperties *DocProperties) (err error) {
 	core = new(decodeCoreProperties)
 	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("docProps/core.xml")))).
 		Decode(core); err != nil && err != io.EOF {
-		err = fmt.Errorf("xml decode error: %s", err)
+		err = f.newXMLDecodeError("docProps/core.xml", err)
 		return
 	}
 	newProps, err = &xlsxCoreProperties{
@@ -97,6 +104,7 @@ func (f *File) SetDocProps(docProperties *DocProperties) (err error) {
 		Keywords:       core.Keywords,
 		Description:    core.Description,
 		LastModifiedBy: core.LastModifiedBy,
+		LastPrinted:    core.LastPrinted,
 		Language:       core.Language,
 		Identifier:     core.Identifier,
 		Revision:       core.Revision,
@@ -108,7 +116,7 @@ func (f *File) SetDocProps(docProperties *DocProperties) (err error) {
 		core.Created.Text, core.Created.Type, core.Modified.Text, core.Modified.Type
 	fields = []string{
 		"Category", "ContentStatus", "Creator", "Description", "Identifier", "Keywords",
-		"LastModifiedBy", "Revision", "Subject", "Title", "Language", "Version",
+		"LastModifiedBy", "LastPrinted", "Revision", "Subject", "Title", "Language", "Version",
 	}
 	immutable, mutable = reflect.ValueOf(*docProperties), reflect.ValueOf(newProps).Elem()
 	for _, field = range fields {
@@ -134,7 +142,7 @@ func (f *File) GetDocProps() (ret *DocProperties, err error) {
 
 	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("docProps/core.xml")))).
 		Decode(core); err != nil && err != io.EOF {
-		err = fmt.Errorf("xml decode error: %s", err)
+		err = f.newXMLDecodeError("docProps/core.xml", err)
 		return
 	}
 	ret, err = &DocProperties{
@@ -146,6 +154,7 @@ func (f *File) GetDocProps() (ret *DocProperties, err error) {
 		Identifier:     core.Identifier,
 		Keywords:       core.Keywords,
 		LastModifiedBy: core.LastModifiedBy,
+		LastPrinted:    core.LastPrinted,
 		Modified:       core.Modified.Text,
 		Revision:       core.Revision,
 		Subject:        core.Subject,
@@ -156,3 +165,304 @@ func (f *File) GetDocProps() (ret *DocProperties, err error) {
 
 	return
 }
+
+// SetAppProps provides a function to set document application properties.
+// The application properties will be overwritten by the given properties,
+// other properties of docProps/app.xml such as the heading pairs and the
+// titles of parts will be kept. The properties that can be set are:
+//
+//     Property          | Description
+//    -------------------+--------------------------------------------------------------------------
+//     Application       | The name of the application that created this document.
+//                       |
+//     ScaleCrop         | Indicates the display mode of the document thumbnail. Set this element
+//                       | to true to enable scaling of the document thumbnail to the display. Set
+//                       | this element to false to enable cropping of the document thumbnail to
+//                       | show only sections that will fit the display.
+//                       |
+//     DocSecurity       | Security level of a document as a numeric value. Document security is
+//                       | defined as:
+//                       | 1 - Document is password protected.
+//                       | 2 - Document is recommended to be opened as read-only.
+//                       | 4 - Document is enforced to be opened as read-only.
+//                       | 8 - Document is locked for annotation.
+//                       |
+//     Company           | The name of a company associated with the document.
+//                       |
+//     LinksUpToDate     | Indicates whether hyperlinks in a document are up-to-date. Set this
+//                       | element to true to indicate that hyperlinks are updated. Set this
+//                       | element to false to indicate that hyperlinks are outdated.
+//                       |
+//     HyperlinksChanged | Specifies that one or more hyperlinks in this part were updated
+//                       | exclusively in this part by a producer. The next producer to open this
+//                       | document shall update the hyperlink relationships with the new
+//                       | hyperlinks specified in this part.
+//                       |
+//     AppVersion        | Specifies the version of the application which produced this document.
+//                       | The content of this element shall be of the form XX.YYYY where X and Y
+//                       | represent numerical values, or the document shall be considered
+//                       | non-conformant.
+//                       |
+//     Manager           | The name of a supervisor associated with the document.
+//                       |
+//     HyperlinkBase     | The base string used for evaluating relative hyperlinks in this
+//                       | document.
+//                       |
+//     SharedDoc         | Indicates if this document is currently shared between multiple
+//                       | producers.
+//                       |
+//     Template          | The name of an external document template containing format and style
+//                       | information used to create the current document.
+//
+// For example:
+//
+//    err := f.SetAppProps(&excelize.AppProperties{
+//        Application:       "Microsoft Excel",
+//        ScaleCrop:         true,
+//        DocSecurity:       3,
+//        Company:           "Company Name",
+//        LinksUpToDate:     true,
+//        HyperlinksChanged: true,
+//        AppVersion:        "16.0000",
+//        Manager:           "Manager Name",
+//        HyperlinkBase:     "https://github.com/360EntSecGroup-Skylar/excelize",
+//    })
+//
+func (f *File) SetAppProps(appProperties *AppProperties) error {
+	app, err := f.appPropsReader()
+	if err != nil {
+		return err
+	}
+	app.Vt = NameSpaceDocPropsVTypes
+	app.Application, app.ScaleCrop, app.DocSecurity = appProperties.Application, appProperties.ScaleCrop, appProperties.DocSecurity
+	app.Company, app.LinksUpToDate, app.HyperlinksChanged = appProperties.Company, appProperties.LinksUpToDate, appProperties.HyperlinksChanged
+	app.AppVersion, app.Manager, app.HyperlinkBase = appProperties.AppVersion, appProperties.Manager, appProperties.HyperlinkBase
+	app.SharedDoc, app.Template = appProperties.SharedDoc, appProperties.Template
+	output, err := xml.Marshal(app)
+	f.saveFileList("docProps/app.xml", output)
+	return err
+}
+
+// GetAppProps provides a function to get document application properties.
+func (f *File) GetAppProps() (*AppProperties, error) {
+	app, err := f.appPropsReader()
+	if err != nil {
+		return nil, err
+	}
+	return &AppProperties{
+		Application:       app.Application,
+		ScaleCrop:         app.ScaleCrop,
+		DocSecurity:       app.DocSecurity,
+		Company:           app.Company,
+		LinksUpToDate:     app.LinksUpToDate,
+		HyperlinksChanged: app.HyperlinksChanged,
+		AppVersion:        app.AppVersion,
+		Manager:           app.Manager,
+		HyperlinkBase:     app.HyperlinkBase,
+		SharedDoc:         app.SharedDoc,
+		Template:          app.Template,
+	}, err
+}
+
+// appPropsReader provides a function to get the pointer to the structure
+// after deserialization of docProps/app.xml.
+func (f *File) appPropsReader() (*xlsxProperties, error) {
+	app := new(xlsxProperties)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("docProps/app.xml")))).
+		Decode(app); err != nil && err != io.EOF {
+		return app, f.newXMLDecodeError("docProps/app.xml", err)
+	}
+	return app, nil
+}
+
+// SetCustomProps provides a function to set a custom property of the
+// document by given property name and value, the property with the same
+// name will be overwritten, and set the value to nil to delete the property.
+// The type of the value could be string, int, int32, int64, float32,
+// float64, bool and time.Time. For example, set the classification tag and
+// the review date of the document:
+//
+//    err := f.SetCustomProps(excelize.CustomProperty{
+//        Name:  "Classification",
+//        Value: "Internal",
+//    })
+//    err = f.SetCustomProps(excelize.CustomProperty{
+//        Name:  "ReviewDate",
+//        Value: time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+//    })
+//
+func (f *File) SetCustomProps(prop CustomProperty) error {
+	if prop.Name == "" {
+		return errors.New("parameter 'Name' is required")
+	}
+	custom, err := f.customPropsReader()
+	if err != nil {
+		return err
+	}
+	props := &xlsxCustomProperties{Vt: NameSpaceDocPropsVTypes}
+	pid := 1
+	for _, p := range custom.Property {
+		if p.PID > pid {
+			pid = p.PID
+		}
+		if p.Name == prop.Name {
+			continue
+		}
+		props.Property = append(props.Property, xlsxCustomProperty{
+			FmtID: p.FmtID, PID: p.PID, Name: p.Name, LinkTarget: p.LinkTarget, Content: p.content(),
+		})
+	}
+	if prop.Value != nil {
+		content, err := customPropValueContent(prop.Value)
+		if err != nil {
+			return err
+		}
+		props.Property = append(props.Property, xlsxCustomProperty{
+			FmtID:   "{D5CDD505-2E9C-101B-9397-08002B2CF9AE}",
+			PID:     pid + 1,
+			Name:    prop.Name,
+			Content: content,
+		})
+	}
+	output, err := xml.Marshal(props)
+	if err != nil {
+		return err
+	}
+	f.saveFileList("docProps/custom.xml", output)
+	f.addCustomPropsRels()
+	return err
+}
+
+// GetCustomProps provides a function to get the custom properties of the
+// document. The value of the property will be converted to the corresponding
+// Go type: string, int64 for the signed integers, uint64 for the unsigned
+// integers, float64, bool or time.Time, the value of the unsupported type
+// will be returned as string.
+func (f *File) GetCustomProps() ([]CustomProperty, error) {
+	var props []CustomProperty
+	custom, err := f.customPropsReader()
+	if err != nil {
+		return props, err
+	}
+	for _, p := range custom.Property {
+		prop := CustomProperty{Name: p.Name, Value: p.Value.Text}
+		if p.Value.XMLName.Space != NameSpaceDocPropsVTypes {
+			props = append(props, prop)
+			continue
+		}
+		switch p.Value.XMLName.Local {
+		case "i1", "i2", "i4", "i8", "int":
+			if val, err := strconv.ParseInt(p.Value.Text, 10, 64); err == nil {
+				prop.Value = val
+			}
+		case "ui1", "ui2", "ui4", "ui8", "uint":
+			if val, err := strconv.ParseUint(p.Value.Text, 10, 64); err == nil {
+				prop.Value = val
+			}
+		case "r4", "r8", "decimal":
+			if val, err := strconv.ParseFloat(p.Value.Text, 64); err == nil {
+				prop.Value = val
+			}
+		case "bool":
+			if val, err := strconv.ParseBool(p.Value.Text); err == nil {
+				prop.Value = val
+			}
+		case "filetime", "date":
+			if val, err := time.Parse(time.RFC3339, p.Value.Text); err == nil {
+				prop.Value = val
+			}
+		}
+		props = append(props, prop)
+	}
+	return props, err
+}
+
+// content provides a function to get the raw content of the custom property
+// for writing it back under the root element which binds the docPropsVTypes
+// namespace to the "vt" prefix. The value element in the docPropsVTypes
+// namespace is matched by the namespace URI, and the prefix of it and its
+// child elements will be replaced with "vt" if the original part bound the
+// namespace to another prefix.
+func (p decodeCustomProperty) content() string {
+	if p.Value.XMLName.Space != NameSpaceDocPropsVTypes {
+		return p.Content
+	}
+	content := strings.TrimSpace(p.Content)
+	if !strings.HasPrefix(content, "<") {
+		return p.Content
+	}
+	name := content[1:]
+	if idx := strings.IndexAny(name, " \t\r\n/>"); idx != -1 {
+		name = name[:idx]
+	}
+	idx := strings.Index(name, ":")
+	if idx == -1 || name[:idx] == "vt" {
+		return p.Content
+	}
+	prefix := name[:idx]
+	return strings.NewReplacer("<"+prefix+":", "<vt:", "</"+prefix+":", "</vt:").Replace(p.Content)
+}
+
+// customPropsReader provides a function to get the pointer to the structure
+// after deserialization of docProps/custom.xml.
+func (f *File) customPropsReader() (*decodeCustomProperties, error) {
+	custom := new(decodeCustomProperties)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("docProps/custom.xml")))).
+		Decode(custom); err != nil && err != io.EOF {
+		return custom, f.newXMLDecodeError("docProps/custom.xml", err)
+	}
+	return custom, nil
+}
+
+// addCustomPropsRels provides a function to add the relationship and content
+// type of the custom properties part if not exist.
+func (f *File) addCustomPropsRels() {
+	rels, _ := f.relsReader("_rels/.rels")
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipCustomProperties {
+				return
+			}
+		}
+	}
+	f.addRels("_rels/.rels", SourceRelationshipCustomProperties, "docProps/custom.xml", "")
+	content, _ := f.contentTypesReader()
+	for _, v := range content.Overrides {
+		if v.PartName == "/docProps/custom.xml" {
+			return
+		}
+	}
+	f.setContentTypes("/docProps/custom.xml", ContentTypeCustomProperties)
+}
+
+// customPropValueContent provides a function to build the typed value element
+// of the custom property by given value.
+func customPropValueContent(value interface{}) (string, error) {
+	var vt, text string
+	switch v := value.(type) {
+	case string:
+		vt, text = "lpwstr", v
+	case int:
+		vt, text = "i4", strconv.Itoa(v)
+		if int64(v) != int64(int32(v)) {
+			vt = "i8"
+		}
+	case int32:
+		vt, text = "i4", strconv.FormatInt(int64(v), 10)
+	case int64:
+		vt, text = "i8", strconv.FormatInt(v, 10)
+	case float32:
+		vt, text = "r8", strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		vt, text = "r8", strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		vt, text = "bool", strconv.FormatBool(v)
+	case time.Time:
+		vt, text = "filetime", v.UTC().Format("2006-01-02T15:04:05Z")
+	default:
+		return "", fmt.Errorf("unsupported custom property value type %T", value)
+	}
+	var buf strings.Builder
+	_ = xml.EscapeText(&buf, []byte(text))
+	return "<vt:" + vt + ">" + buf.String() + "</vt:" + vt + ">", nil
+}
@@ -12,6 +12,7 @@ package excelize
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -32,6 +33,7 @@ func TestSetDocProps(t *testing.T) {
 		Identifier:     "xlsx",
 		Keywords:       "Spreadsheet",
 		LastModifiedBy: "Go Author",
+		LastPrinted:    "2019-06-04T22:00:10Z",
 		Modified:       "2019-06-04T22:00:10Z",
 		Revision:       "0",
 		Subject:        "Test Subject",
@@ -67,3 +69,116 @@ func TestGetDocProps(t *testing.T) {
 	_, err = f.GetDocProps()
 	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
 }
+
+func TestCustomProps(t *testing.T) {
+	f := NewFile()
+	date := time.Date(2020, 12, 31, 8, 30, 0, 0, time.UTC)
+	for _, prop := range []CustomProperty{
+		{Name: "Text", Value: "a < b"},
+		{Name: "Integer", Value: 1},
+		{Name: "Large", Value: int64(1) << 40},
+		{Name: "Float", Value: 1.5},
+		{Name: "Boolean", Value: true},
+		{Name: "Date", Value: date},
+	} {
+		assert.NoError(t, f.SetCustomProps(prop))
+	}
+	// Test overwrite and delete the custom property
+	assert.NoError(t, f.SetCustomProps(CustomProperty{Name: "Integer", Value: int32(2)}))
+	assert.NoError(t, f.SetCustomProps(CustomProperty{Name: "Float", Value: nil}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCustomProps.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestCustomProps.xlsx"))
+	assert.NoError(t, err)
+	props, err := f.GetCustomProps()
+	assert.NoError(t, err)
+	assert.Equal(t, []CustomProperty{
+		{Name: "Text", Value: "a < b"},
+		{Name: "Large", Value: int64(1) << 40},
+		{Name: "Boolean", Value: true},
+		{Name: "Date", Value: date},
+		{Name: "Integer", Value: int64(2)},
+	}, props)
+	var count int
+	rels, err := f.relsReader("_rels/.rels")
+	assert.NoError(t, err)
+	for _, rel := range rels.Relationships {
+		if rel.Type == SourceRelationshipCustomProperties {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	assert.EqualError(t, f.SetCustomProps(CustomProperty{}), "parameter 'Name' is required")
+	assert.EqualError(t, f.SetCustomProps(CustomProperty{Name: "Complex", Value: complex64(1)}), "unsupported custom property value type complex64")
+
+	// Test get and set the custom properties with the 64 bits integers and
+	// another prefix of the docPropsVTypes namespace
+	f = NewFile()
+	f.XLSX["docProps/custom.xml"] = []byte(`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" xmlns:v="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes" xmlns:o="urn:other"><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="Unsigned"><v:ui8>18446744073709551615</v:ui8></property><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="3" name="Signed"><v:i8>-9223372036854775808</v:i8></property><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="4" name="Vector"><v:vector size="1" baseType="lpwstr"><v:lpwstr>a</v:lpwstr></v:vector></property><property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="5" name="Other"><o:i4>1</o:i4></property></Properties>`)
+	expected := []CustomProperty{
+		{Name: "Unsigned", Value: uint64(18446744073709551615)},
+		{Name: "Signed", Value: int64(-9223372036854775808)},
+		{Name: "Vector", Value: ""},
+		{Name: "Other", Value: "1"},
+	}
+	props, err = f.GetCustomProps()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, props)
+	assert.NoError(t, f.SetCustomProps(CustomProperty{Name: "Text", Value: "text"}))
+	assert.Contains(t, string(f.XLSX["docProps/custom.xml"]), `<vt:vector size="1" baseType="lpwstr"><vt:lpwstr>a</vt:lpwstr></vt:vector>`)
+	props, err = f.GetCustomProps()
+	assert.NoError(t, err)
+	assert.Equal(t, append(expected, CustomProperty{Name: "Text", Value: "text"}), props)
+
+	// Test unsupport charset
+	f = NewFile()
+	f.XLSX["docProps/custom.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.SetCustomProps(CustomProperty{Name: "Text", Value: "text"}), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetCustomProps()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestSetAppProps(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	appProps := &AppProperties{
+		Application:       "Microsoft Excel",
+		ScaleCrop:         true,
+		DocSecurity:       3,
+		Company:           "Company Name",
+		LinksUpToDate:     true,
+		HyperlinksChanged: true,
+		AppVersion:        "16.0000",
+		Manager:           "Manager Name",
+		HyperlinkBase:     "https://github.com/360EntSecGroup-Skylar/excelize",
+		SharedDoc:         true,
+		Template:          "Template.xltx",
+	}
+	assert.NoError(t, f.SetAppProps(appProps))
+	// Test application properties will be kept after add new worksheet
+	f.NewSheet("Sheet3")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetAppProps.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestSetAppProps.xlsx"))
+	assert.NoError(t, err)
+	props, err := f.GetAppProps()
+	assert.NoError(t, err)
+	assert.Equal(t, appProps, props)
+
+	// Test unsupport charset
+	f = NewFile()
+	f.XLSX["docProps/app.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.SetAppProps(&AppProperties{}), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetAppProps()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetAppProps(t *testing.T) {
+	f := NewFile()
+	props, err := f.GetAppProps()
+	assert.NoError(t, err)
+	assert.Equal(t, "Go Excelize", props.Application)
+}
@@ -14,9 +14,7 @@ package excelize
 import (
 	"bytes"
 	"encoding/xml"
-	"fmt"
 	"io"
-	"log"
 	"reflect"
 	"strconv"
 	"strings"
@@ -56,11 +54,11 @@ func (f *File) prepareChartSheetDrawing(cs *xlsxChartsheet, drawingID int, sheet
 
 // addChart provides a function to create chart as xl/charts/chart%d.xml by
 // given format sets.
-func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
+func (f *File) addChart(formatSet *Chart, comboCharts []*Chart) {
 	count := f.countCharts()
 	xlsxChartSpace := xlsxChartSpace{
 		XMLNSa:         NameSpaceDrawingML.Value,
-		Date1904:       &attrValBool{Val: boolPtr(false)},
+		Date1904:       &attrValBool{Val: boolPtr(f.date1904())},
 		Lang:           &attrValString{Val: stringPtr("en-US")},
 		RoundedCorners: &attrValBool{Val: boolPtr(false)},
 		Chart: cChart{
@@ -180,7 +178,7 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 			},
 		},
 	}
-	plotAreaFunc := map[string]func(*formatChart) *cPlotArea{
+	plotAreaFunc := map[string]func(*Chart) *cPlotArea{
 		Area:                        f.drawBaseChart,
 		AreaStacked:                 f.drawBaseChart,
 		AreaPercentStacked:          f.drawBaseChart,
@@ -260,7 +258,7 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 
 // drawBaseChart provides a function to draw the c:plotArea element for bar,
 // and column series charts by given format sets.
-func (f *File) drawBaseChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawBaseChart(formatSet *Chart) *cPlotArea {
 	c := cCharts{
 		BarDir: &attrValString{
 			Val: stringPtr("col"),
@@ -509,7 +507,7 @@ func (f *File) drawBaseChart(formatSet *formatChart) *cPlotArea {
 
 // drawDoughnutChart provides a function to draw the c:plotArea element for
 // doughnut chart by given format sets.
-func (f *File) drawDoughnutChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawDoughnutChart(formatSet *Chart) *cPlotArea {
 	return &cPlotArea{
 		DoughnutChart: &cCharts{
 			VaryColors: &attrValBool{
@@ -523,7 +521,7 @@ func (f *File) drawDoughnutChart(formatSet *formatChart) *cPlotArea {
 
 // drawLineChart provides a function to draw the c:plotArea element for line
 // chart by given format sets.
-func (f *File) drawLineChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawLineChart(formatSet *Chart) *cPlotArea {
 	return &cPlotArea{
 		LineChart: &cCharts{
 			Grouping: &attrValString{
@@ -549,7 +547,7 @@ func (f *File) drawLineChart(formatSet *formatChart) *cPlotArea {
 
 // drawPieChart provides a function to draw the c:plotArea element for pie
 // chart by given format sets.
-func (f *File) drawPieChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawPieChart(formatSet *Chart) *cPlotArea {
 	return &cPlotArea{
 		PieChart: &cCharts{
 			VaryColors: &attrValBool{
@@ -562,7 +560,7 @@ func (f *File) drawPieChart(formatSet *formatChart) *cPlotArea {
 
 // drawPie3DChart provides a function to draw the c:plotArea element for 3D
 // pie chart by given format sets.
-func (f *File) drawPie3DChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawPie3DChart(formatSet *Chart) *cPlotArea {
 	return &cPlotArea{
 		Pie3DChart: &cCharts{
 			VaryColors: &attrValBool{
@@ -575,7 +573,7 @@ func (f *File) drawPie3DChart(formatSet *formatChart) *cPlotArea {
 
 // drawPieOfPieChart provides a function to draw the c:plotArea element for
 // pie chart by given format sets.
-func (f *File) drawPieOfPieChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawPieOfPieChart(formatSet *Chart) *cPlotArea {
 	return &cPlotArea{
 		OfPieChart: &cCharts{
 			OfPieType: &attrValString{
@@ -592,7 +590,7 @@ func (f *File) drawPieOfPieChart(formatSet *formatChart) *cPlotArea {
 
 // drawBarOfPieChart provides a function to draw the c:plotArea element for
 // pie chart by given format sets.
-func (f *File) drawBarOfPieChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawBarOfPieChart(formatSet *Chart) *cPlotArea {
 	return &cPlotArea{
 		OfPieChart: &cCharts{
 			OfPieType: &attrValString{
@@ -609,7 +607,7 @@ func (f *File) drawBarOfPieChart(formatSet *formatChart) *cPlotArea {
 
 // drawRadarChart provides a function to draw the c:plotArea element for radar
 // chart by given format sets.
-func (f *File) drawRadarChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawRadarChart(formatSet *Chart) *cPlotArea {
 	return &cPlotArea{
 		RadarChart: &cCharts{
 			RadarStyle: &attrValString{
@@ -632,7 +630,7 @@ func (f *File) drawRadarChart(formatSet *formatChart) *cPlotArea {
 
 // drawScatterChart provides a function to draw the c:plotArea element for
 // scatter chart by given format sets.
-func (f *File) drawScatterChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawScatterChart(formatSet *Chart) *cPlotArea {
 	return &cPlotArea{
 		ScatterChart: &cCharts{
 			ScatterStyle: &attrValString{
@@ -655,7 +653,7 @@ func (f *File) drawScatterChart(formatSet *formatChart) *cPlotArea {
 
 // drawSurface3DChart provides a function to draw the c:surface3DChart element by
 // given format sets.
-func (f *File) drawSurface3DChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawSurface3DChart(formatSet *Chart) *cPlotArea {
 	plotArea := &cPlotArea{
 		Surface3DChart: &cCharts{
 			Ser: f.drawChartSeries(formatSet),
@@ -677,7 +675,7 @@ func (f *File) drawSurface3DChart(formatSet *formatChart) *cPlotArea {
 
 // drawSurfaceChart provides a function to draw the c:surfaceChart element by
 // given format sets.
-func (f *File) drawSurfaceChart(formatSet *formatChart) *cPlotArea {
+func (f *File) drawSurfaceChart(formatSet *Chart) *cPlotArea {
 	plotArea := &cPlotArea{
 		SurfaceChart: &cCharts{
 			Ser: f.drawChartSeries(formatSet),
@@ -699,7 +697,7 @@ func (f *File) drawSurfaceChart(formatSet *formatChart) *cPlotArea {
 
 // drawChartShape provides a function to draw the c:shape element by given
 // format sets.
-func (f *File) drawChartShape(formatSet *formatChart) *attrValString {
+func (f *File) drawChartShape(formatSet *Chart) *attrValString {
 	shapes := map[string]string{
 		Bar3DConeClustered:          "cone",
 		Bar3DConeStacked:            "cone",
@@ -731,7 +729,7 @@ func (f *File) drawChartShape(formatSet *formatChart) *attrValString {
 
 // drawChartSeries provides a function to draw the c:ser element by given
 // format sets.
-func (f *File) drawChartSeries(formatSet *formatChart) *[]cSer {
+func (f *File) drawChartSeries(formatSet *Chart) *[]cSer {
 	ser := []cSer{}
 	for k := range formatSet.Series {
 		ser = append(ser, cSer{
@@ -746,6 +744,7 @@ func (f *File) drawChartSeries(formatSet *formatChart) *[]cSer {
 			Marker:     f.drawChartSeriesMarker(k, formatSet),
 			DPt:        f.drawChartSeriesDPt(k, formatSet),
 			DLbls:      f.drawChartSeriesDLbls(formatSet),
+			Trendline:  f.drawChartSeriesTrendline(formatSet.Series[k].Trendline),
 			Cat:        f.drawChartSeriesCat(formatSet.Series[k], formatSet),
 			Val:        f.drawChartSeriesVal(formatSet.Series[k], formatSet),
 			XVal:       f.drawChartSeriesXVal(formatSet.Series[k], formatSet),
@@ -759,7 +758,7 @@ func (f *File) drawChartSeries(formatSet *formatChart) *[]cSer {
 
 // drawChartSeriesSpPr provides a function to draw the c:spPr element by given
 // format sets.
-func (f *File) drawChartSeriesSpPr(i int, formatSet *formatChart) *cSpPr {
+func (f *File) drawChartSeriesSpPr(i int, formatSet *Chart) *cSpPr {
 	spPrScatter := &cSpPr{
 		Ln: &aLn{
 			W:      25400,
@@ -781,7 +780,7 @@ func (f *File) drawChartSeriesSpPr(i int, formatSet *formatChart) *cSpPr {
 
 // drawChartSeriesDPt provides a function to draw the c:dPt element by given
 // data index and format sets.
-func (f *File) drawChartSeriesDPt(i int, formatSet *formatChart) []*cDPt {
+func (f *File) drawChartSeriesDPt(i int, formatSet *Chart) []*cDPt {
 	dpt := []*cDPt{{
 		IDx:      &attrValInt{Val: intPtr(i)},
 		Bubble3D: &attrValBool{Val: boolPtr(false)},
@@ -808,9 +807,44 @@ func (f *File) drawChartSeriesDPt(i int, formatSet *formatChart) []*cDPt {
 	return chartSeriesDPt[formatSet.Type]
 }
 
+// drawChartSeriesTrendline provides a function to draw the c:trendline
+// element by given format settings of the trendline.
+func (f *File) drawChartSeriesTrendline(trendline ChartTrendline) *cTrendline {
+	trendlineType, ok := chartTrendlineTypes[trendline.Type]
+	if !ok {
+		return nil
+	}
+	t := &cTrendline{
+		Name:          trendline.Name,
+		TrendlineType: &attrValString{Val: stringPtr(trendlineType)},
+		DispRSqr:      &attrValBool{Val: boolPtr(trendline.DisplayRSquared)},
+		DispEq:        &attrValBool{Val: boolPtr(trendline.DisplayEquation)},
+	}
+	switch trendlineType {
+	case "poly":
+		if trendline.Order == 0 {
+			trendline.Order = 2
+		}
+		t.Order = &attrValInt{Val: intPtr(trendline.Order)}
+	case "movingAvg":
+		if trendline.Period == 0 {
+			trendline.Period = 2
+		}
+		t.Period = &attrValInt{Val: intPtr(trendline.Period)}
+		return t
+	}
+	if trendline.Forward > 0 {
+		t.Forward = &attrValFloat{Val: float64Ptr(trendline.Forward)}
+	}
+	if trendline.Backward > 0 {
+		t.Backward = &attrValFloat{Val: float64Ptr(trendline.Backward)}
+	}
+	return t
+}
+
 // drawChartSeriesCat provides a function to draw the c:cat element by given
 // chart series and format sets.
-func (f *File) drawChartSeriesCat(v formatChartSeries, formatSet *formatChart) *cCat {
+func (f *File) drawChartSeriesCat(v ChartSeries, formatSet *Chart) *cCat {
 	cat := &cCat{
 		StrRef: &cStrRef{
 			F: v.Categories,
@@ -825,7 +859,7 @@ func (f *File) drawChartSeriesCat(v formatChartSeries, formatSet *formatChart) *
 
 // drawChartSeriesVal provides a function to draw the c:val element by given
 // chart series and format sets.
-func (f *File) drawChartSeriesVal(v formatChartSeries, formatSet *formatChart) *cVal {
+func (f *File) drawChartSeriesVal(v ChartSeries, formatSet *Chart) *cVal {
 	val := &cVal{
 		NumRef: &cNumRef{
 			F: v.Values,
@@ -840,7 +874,7 @@ func (f *File) drawChartSeriesVal(v formatChartSeries, formatSet *formatChart) *
 
 // drawChartSeriesMarker provides a function to draw the c:marker element by
 // given data index and format sets.
-func (f *File) drawChartSeriesMarker(i int, formatSet *formatChart) *cMarker {
+func (f *File) drawChartSeriesMarker(i int, formatSet *Chart) *cMarker {
 	defaultSymbol := map[string]*attrValString{Scatter: &attrValString{Val: stringPtr("circle")}}
 	marker := &cMarker{
 		Symbol: defaultSymbol[formatSet.Type],
@@ -875,7 +909,7 @@ func (f *File) drawChartSeriesMarker(i int, formatSet *formatChart) *cMarker {
 
 // drawChartSeriesXVal provides a function to draw the c:xVal element by given
 // chart series and format sets.
-func (f *File) drawChartSeriesXVal(v formatChartSeries, formatSet *formatChart) *cCat {
+func (f *File) drawChartSeriesXVal(v ChartSeries, formatSet *Chart) *cCat {
 	cat := &cCat{
 		StrRef: &cStrRef{
 			F: v.Categories,
@@ -887,7 +921,7 @@ func (f *File) drawChartSeriesXVal(v formatChartSeries, formatSet *formatChart)
 
 // drawChartSeriesYVal provides a function to draw the c:yVal element by given
 // chart series and format sets.
-func (f *File) drawChartSeriesYVal(v formatChartSeries, formatSet *formatChart) *cVal {
+func (f *File) drawChartSeriesYVal(v ChartSeries, formatSet *Chart) *cVal {
 	val := &cVal{
 		NumRef: &cNumRef{
 			F: v.Values,
@@ -899,7 +933,7 @@ func (f *File) drawChartSeriesYVal(v formatChartSeries, formatSet *formatChart)
 
 // drawCharSeriesBubbleSize provides a function to draw the c:bubbleSize
 // element by given chart series and format sets.
-func (f *File) drawCharSeriesBubbleSize(v formatChartSeries, formatSet *formatChart) *cVal {
+func (f *File) drawCharSeriesBubbleSize(v ChartSeries, formatSet *Chart) *cVal {
 	if _, ok := map[string]bool{Bubble: true, Bubble3D: true}[formatSet.Type]; !ok {
 		return nil
 	}
@@ -912,7 +946,7 @@ func (f *File) drawCharSeriesBubbleSize(v formatChartSeries, formatSet *formatCh
 
 // drawCharSeriesBubble3D provides a function to draw the c:bubble3D element
 // by given format sets.
-func (f *File) drawCharSeriesBubble3D(formatSet *formatChart) *attrValBool {
+func (f *File) drawCharSeriesBubble3D(formatSet *Chart) *attrValBool {
 	if _, ok := map[string]bool{Bubble3D: true}[formatSet.Type]; !ok {
 		return nil
 	}
@@ -921,7 +955,7 @@ func (f *File) drawCharSeriesBubble3D(formatSet *formatChart) *attrValBool {
 
 // drawChartDLbls provides a function to draw the c:dLbls element by given
 // format sets.
-func (f *File) drawChartDLbls(formatSet *formatChart) *cDLbls {
+func (f *File) drawChartDLbls(formatSet *Chart) *cDLbls {
 	return &cDLbls{
 		ShowLegendKey:   &attrValBool{Val: boolPtr(formatSet.Legend.ShowLegendKey)},
 		ShowVal:         &attrValBool{Val: boolPtr(formatSet.Plotarea.ShowVal)},
@@ -935,7 +969,7 @@ func (f *File) drawChartDLbls(formatSet *formatChart) *cDLbls {
 
 // drawChartSeriesDLbls provides a function to draw the c:dLbls element by
 // given format sets.
-func (f *File) drawChartSeriesDLbls(formatSet *formatChart) *cDLbls {
+func (f *File) drawChartSeriesDLbls(formatSet *Chart) *cDLbls {
 	dLbls := f.drawChartDLbls(formatSet)
 	chartSeriesDLbls := map[string]*cDLbls{Scatter: nil, Surface3D: nil, WireframeSurface3D: nil, Contour: nil, WireframeContour: nil, Bubble: nil, Bubble3D: nil}
 	if _, ok := chartSeriesDLbls[formatSet.Type]; ok {
@@ -945,7 +979,7 @@ func (f *File) drawChartSeriesDLbls(formatSet *formatChart) *cDLbls {
 }
 
 // drawPlotAreaCatAx provides a function to draw the c:catAx element.
-func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
+func (f *File) drawPlotAreaCatAx(formatSet *Chart) []*cAxs {
 	min := &attrValFloat{Val: float64Ptr(formatSet.XAxis.Minimum)}
 	max := &attrValFloat{Val: float64Ptr(formatSet.XAxis.Maximum)}
 	if formatSet.XAxis.Minimum == 0 {
@@ -994,7 +1028,7 @@ func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
 }
 
 // drawPlotAreaValAx provides a function to draw the c:valAx element.
-func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
+func (f *File) drawPlotAreaValAx(formatSet *Chart) []*cAxs {
 	min := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Minimum)}
 	max := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Maximum)}
 	if formatSet.YAxis.Minimum == 0 {
@@ -1048,7 +1082,7 @@ func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
 }
 
 // drawPlotAreaSerAx provides a function to draw the c:serAx element.
-func (f *File) drawPlotAreaSerAx(formatSet *formatChart) []*cAxs {
+func (f *File) drawPlotAreaSerAx(formatSet *Chart) []*cAxs {
 	min := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Minimum)}
 	max := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Maximum)}
 	if formatSet.YAxis.Minimum == 0 {
@@ -1137,7 +1171,7 @@ func (f *File) drawPlotAreaTxPr() *cTxPr {
 // the problem that the label structure is changed after serialization and
 // deserialization, two different structures: decodeWsDr and encodeWsDr are
 // defined.
-func (f *File) drawingParser(path string) (*xlsxWsDr, int) {
+func (f *File) drawingParser(path string) (*xlsxWsDr, int, error) {
 	var (
 		err error
 		ok  bool
@@ -1151,7 +1185,9 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int) {
 			decodeWsDr := decodeWsDr{}
 			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
 				Decode(&decodeWsDr); err != nil && err != io.EOF {
-				log.Printf("xml decode error: %s", err)
+				err = f.newXMLDecodeError(path, err)
+			} else {
+				err = nil
 			}
 			content.R = decodeWsDr.R
 			for _, v := range decodeWsDr.OneCellAnchor {
@@ -1170,12 +1206,12 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int) {
 		f.Drawings[path] = &content
 	}
 	wsDr := f.Drawings[path]
-	return wsDr, len(wsDr.OneCellAnchor) + len(wsDr.TwoCellAnchor) + 2
+	return wsDr, len(wsDr.OneCellAnchor) + len(wsDr.TwoCellAnchor) + 2, err
 }
 
 // addDrawingChart provides a function to add chart graphic frame by given
 // sheet, drawingXML, cell, width, height, relationship index and format sets.
-func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rID int, formatSet *formatPicture) error {
+func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rID int, formatSet *PictureFormat) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -1187,7 +1223,10 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 	height = int(float64(height) * formatSet.YScale)
 	colStart, rowStart, colEnd, rowEnd, x2, y2 :=
 		f.positionObjectPixels(sheet, colIdx, rowIdx, formatSet.OffsetX, formatSet.OffsetY, width, height)
-	content, cNvPrID := f.drawingParser(drawingXML)
+	content, cNvPrID, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return err
+	}
 	twoCellAnchor := xdrCellAnchor{}
 	twoCellAnchor.EditAs = formatSet.Positioning
 	from := xlsxFrom{}
@@ -1235,8 +1274,11 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 // addSheetDrawingChart provides a function to add chart graphic frame for
 // chartsheet by given sheet, drawingXML, width, height, relationship index
 // and format sets.
-func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *formatPicture) {
-	content, cNvPrID := f.drawingParser(drawingXML)
+func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *PictureFormat) error {
+	content, cNvPrID, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return err
+	}
 	absoluteAnchor := xdrCellAnchor{
 		EditAs: formatSet.Positioning,
 		Pos:    &xlsxPoint2D{},
@@ -1269,7 +1311,7 @@ func (f *File) addSheetDrawingChart(drawingXML string, rID int, formatSet *forma
 	}
 	content.AbsoluteAnchor = append(content.AbsoluteAnchor, &absoluteAnchor)
 	f.Drawings[drawingXML] = content
-	return
+	return err
 }
 
 // deleteDrawing provides a function to delete chart graphic frame by given by
@@ -1287,7 +1329,9 @@ func (f *File) deleteDrawing(col, row int, drawingXML, drawingType string) (err
 		"Chart": func(anchor *decodeTwoCellAnchor) bool { return anchor.Pic == nil },
 		"Pic":   func(anchor *decodeTwoCellAnchor) bool { return anchor.Pic != nil },
 	}
-	wsDr, _ = f.drawingParser(drawingXML)
+	if wsDr, _, err = f.drawingParser(drawingXML); err != nil {
+		return
+	}
 	for idx := 0; idx < len(wsDr.TwoCellAnchor); idx++ {
 		if err = nil; wsDr.TwoCellAnchor[idx].From != nil && xdrCellAnchorFuncs[drawingType](wsDr.TwoCellAnchor[idx]) {
 			if wsDr.TwoCellAnchor[idx].From.Col == col && wsDr.TwoCellAnchor[idx].From.Row == row {
@@ -1300,7 +1344,7 @@ func (f *File) deleteDrawing(col, row int, drawingXML, drawingType string) (err
 		deTwoCellAnchor = new(decodeTwoCellAnchor)
 		if err = f.xmlNewDecoder(strings.NewReader("<decodeTwoCellAnchor>" + wsDr.TwoCellAnchor[idx].GraphicFrame + "</decodeTwoCellAnchor>")).
 			Decode(deTwoCellAnchor); err != nil && err != io.EOF {
-			err = fmt.Errorf("xml decode error: %s", err)
+			err = f.newXMLDecodeError(drawingXML, err)
 			return
 		}
 		if err = nil; deTwoCellAnchor.From != nil && decodeTwoCellAnchorFuncs[drawingType](deTwoCellAnchor) {
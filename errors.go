@@ -13,18 +13,190 @@ package excelize
 
 import "fmt"
 
+// ErrSheetNotExist defines an error of sheet is not exist
+type ErrSheetNotExist struct {
+	SheetName string
+}
+
+func (err ErrSheetNotExist) Error() string {
+	return fmt.Sprintf("sheet %s is not exist", string(err.SheetName))
+}
+
+// ErrInvalidColumnName defines an error of the invalid column name.
+type ErrInvalidColumnName struct {
+	Column string
+}
+
+func (err ErrInvalidColumnName) Error() string {
+	return fmt.Sprintf("invalid column name %q", err.Column)
+}
+
+// ErrInvalidRowNumber defines an error of the invalid row number.
+type ErrInvalidRowNumber struct {
+	Row int
+}
+
+func (err ErrInvalidRowNumber) Error() string {
+	return fmt.Sprintf("invalid row number %d", err.Row)
+}
+
+// ErrInvalidCellRef defines an error of the invalid cell reference.
+type ErrInvalidCellRef struct {
+	Cell string
+}
+
+func (err ErrInvalidCellRef) Error() string {
+	return fmt.Sprintf("invalid cell name %q", err.Cell)
+}
+
+// ErrCellCoordinates defines an error of converting the cell name to the
+// coordinates, the Err is the underlying error of parsing the cell name.
+type ErrCellCoordinates struct {
+	Cell string
+	Err  error
+}
+
+func (err ErrCellCoordinates) Error() string {
+	return fmt.Sprintf("cannot convert cell %q to coordinates: %s", err.Cell, err.Err)
+}
+
+// Unwrap returns the underlying error of parsing the cell name.
+func (err ErrCellCoordinates) Unwrap() error {
+	return err.Err
+}
+
+// ErrInvalidExcelDate defines an error of the date value which can't be
+// converted to the Excel date.
+type ErrInvalidExcelDate struct {
+	Value float64
+}
+
+func (err ErrInvalidExcelDate) Error() string {
+	return fmt.Sprintf("invalid date value %f, negative values are not supported supported", err.Value)
+}
+
+// ErrFormula defines an error of calculating the formula of the cell, the Err
+// is the underlying error such as the formula error value #DIV/0!.
+type ErrFormula struct {
+	Sheet   string
+	Cell    string
+	Formula string
+	Err     error
+}
+
+func (err ErrFormula) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap returns the underlying error of calculating the formula.
+func (err ErrFormula) Unwrap() error {
+	return err.Err
+}
+
+// ErrXMLDecode defines an error of decoding the XML part of the spreadsheet,
+// the Err is the underlying error of the XML decoder.
+type ErrXMLDecode struct {
+	Part string
+	Err  error
+}
+
+func (err ErrXMLDecode) Error() string {
+	return fmt.Sprintf("xml decode error: %s", err.Err)
+}
+
+// Unwrap returns the underlying error of the XML decoder.
+func (err ErrXMLDecode) Unwrap() error {
+	return err.Err
+}
+
+// ErrReadPart defines an error of reading the part of the spreadsheet which
+// has not been loaded from the reader, the Err is the underlying error.
+type ErrReadPart struct {
+	Part string
+	Err  error
+}
+
+func (err ErrReadPart) Error() string {
+	return fmt.Sprintf("read file error: %s", err.Err)
+}
+
+// Unwrap returns the underlying error of reading the part.
+func (err ErrReadPart) Unwrap() error {
+	return err.Err
+}
+
+// ErrTableNotExist defines an error of table is not exist.
+type ErrTableNotExist struct {
+	Name string
+}
+
+func (err ErrTableNotExist) Error() string {
+	return fmt.Sprintf("table %s is not exist", err.Name)
+}
+
+// ErrPivotTableNotExist defines an error of pivot table is not exist.
+type ErrPivotTableNotExist struct {
+	Name string
+}
+
+func (err ErrPivotTableNotExist) Error() string {
+	return fmt.Sprintf("pivot table %s is not exist", err.Name)
+}
+
+// newXMLDecodeError provides a function to create the error of decoding the
+// XML part by given part path and the underlying error, and write it to the
+// logger, since the partially decoded part will be cached and the error may
+// be discarded by the functions which don't return error.
+func (f *File) newXMLDecodeError(part string, err error) error {
+	f.logf("%s: xml decode error: %s", part, err)
+	return ErrXMLDecode{Part: part, Err: err}
+}
+
 func newInvalidColumnNameError(col string) error {
-	return fmt.Errorf("invalid column name %q", col)
+	return ErrInvalidColumnName{Column: col}
 }
 
 func newInvalidRowNumberError(row int) error {
-	return fmt.Errorf("invalid row number %d", row)
+	return ErrInvalidRowNumber{Row: row}
 }
 
 func newInvalidCellNameError(cell string) error {
-	return fmt.Errorf("invalid cell name %q", cell)
+	return ErrInvalidCellRef{Cell: cell}
 }
 
 func newInvalidExcelDateError(dateValue float64) error {
-	return fmt.Errorf("invalid date value %f, negative values are not supported supported", dateValue)
+	return ErrInvalidExcelDate{Value: dateValue}
+}
+
+// ErrMaxMemoryExceeded defines an error of the memory required for opening
+// the spreadsheet exceeds the MaxMemory of the options.
+type ErrMaxMemoryExceeded struct {
+	MaxMemory int64
+	Size      int64
+}
+
+func (err ErrMaxMemoryExceeded) Error() string {
+	return fmt.Sprintf("opening the spreadsheet requires %d bytes of memory, exceeds the limit of %d bytes", err.Size, err.MaxMemory)
+}
+
+// ErrMergedCell defines an error of writing into the cell or area which
+// covers a part of the merged cell when the MergeCellPolicy of the options is
+// MergeCellPolicyError.
+type ErrMergedCell struct {
+	Cell string
+	Ref  string
+}
+
+func (err ErrMergedCell) Error() string {
+	return fmt.Sprintf("%s covers a part of the merged cell %s", err.Cell, err.Ref)
+}
+
+// ErrPartNotExist defines an error of the part of the spreadsheet is not
+// exist.
+type ErrPartNotExist struct {
+	Part string
+}
+
+func (err ErrPartNotExist) Error() string {
+	return fmt.Sprintf("part %s is not exist", err.Part)
 }
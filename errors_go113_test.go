@@ -0,0 +1,73 @@
+//go:build go1.13
+// +build go1.13
+
+package excelize
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedErrors(t *testing.T) {
+	f := NewFile()
+	_, err := f.GetCellValue("SheetN", "A1")
+	var sheetErr ErrSheetNotExist
+	if assert.True(t, errors.As(err, &sheetErr)) {
+		assert.Equal(t, "SheetN", sheetErr.SheetName)
+	}
+
+	err = f.SetCellValue("Sheet1", "A", 1)
+	var cellErr ErrInvalidCellRef
+	if assert.True(t, errors.As(err, &cellErr)) {
+		assert.Equal(t, "A", cellErr.Cell)
+	}
+	assert.True(t, errors.As(f.SetRowHeight("Sheet1", 0, 10), new(ErrInvalidRowNumber)))
+	assert.True(t, errors.As(f.SetColWidth("Sheet1", "*", "B", 10), new(ErrInvalidColumnName)))
+	_, err = ExcelDateToTime(-1, false)
+	assert.True(t, errors.As(err, new(ErrInvalidExcelDate)))
+
+	assert.True(t, errors.As(f.DeleteTable("Table1"), new(ErrTableNotExist)))
+	assert.True(t, errors.As(f.DeletePivotTable("Sheet1", "PivotTable1"), new(ErrPivotTableNotExist)))
+
+	// Test the formula error with the context of the cell.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "=UNKNOWN(1)"))
+	_, err = f.CalcCellValue("Sheet1", "A1")
+	var formulaErr ErrFormula
+	if assert.True(t, errors.As(err, &formulaErr)) {
+		assert.Equal(t, "Sheet1", formulaErr.Sheet)
+		assert.Equal(t, "A1", formulaErr.Cell)
+		assert.Equal(t, "=UNKNOWN(1)", formulaErr.Formula)
+	}
+	assert.EqualError(t, err, "not support UNKNOWN function")
+	assert.True(t, errors.Is(ErrFormula{Sheet: "Sheet1", Cell: "A1", Err: context.Canceled}, context.Canceled))
+
+	// Test open the workbook with the malformed part.
+	f = NewFile()
+	f.WorkBook = nil
+	f.XLSX["xl/workbook.xml"] = MacintoshCyrillicCharset
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	_, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	var decodeErr ErrXMLDecode
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, "xl/workbook.xml", decodeErr.Part)
+		assert.NotNil(t, errors.Unwrap(err))
+	}
+
+	// Test read the part which can't be loaded from the reader.
+	f = NewFile()
+	f.lazyParts["xl/worksheets/sheet1.xml"] = tempFilePart(filepath.Join("test", "nonexistent.xml"))
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	_, err = f.GetCellValue("Sheet1", "A1")
+	var readErr ErrReadPart
+	if assert.True(t, errors.As(err, &readErr)) {
+		assert.Equal(t, "xl/worksheets/sheet1.xml", readErr.Part)
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	}
+}
@@ -15,6 +15,7 @@ package excelize
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -22,14 +23,20 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/richardlehane/mscfb"
 	"golang.org/x/net/html/charset"
 )
 
-// File define a populated spreadsheet file struct.
+// File define a populated spreadsheet file struct. The functions for reading
+// the worksheets such as GetCellValue, GetCellStyle, GetRows, GetCols,
+// ReadRows and CalcCellValue are safe for concurrent use by multiple
+// goroutines on the same File, but the functions which modify the
+// spreadsheet should not be called concurrently with any other function.
 type File struct {
 	sync.Mutex
 	options          *Options
@@ -37,13 +44,17 @@ type File struct {
 	checked          map[string]bool
 	sheetMap         map[string]string
 	streams          map[string]*StreamWriter
+	signatures       []*signaturePart
+	lazyParts        map[string]lazyPart
+	tempFiles        []string
+	repairs          []ValidationIssue
 	CalcChain        *xlsxCalcChain
 	Comments         map[string]*xlsxComments
 	ContentTypes     *xlsxTypes
 	Drawings         map[string]*xlsxWsDr
 	Path             string
 	SharedStrings    *xlsxSST
-	sharedStringsMap map[string]int
+	sharedStringsMap *sharedStringsIndex
 	Sheet            map[string]*xlsxWorksheet
 	SheetCount       int
 	Styles           *xlsxStyleSheet
@@ -58,9 +69,132 @@ type File struct {
 
 type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, err error)
 
-// Options define the options for open spreadsheet.
+// Options define the options for open and save spreadsheet. The options
+// which are only used when saving the spreadsheet can also be given by
+// SaveAs.
 type Options struct {
+	// Password specifies the password of the spreadsheet in plain text.
 	Password string
+	// PrettyXML specifies if indent the XML parts when saving the
+	// spreadsheet, it can't be used with the CompactXML at the same time.
+	PrettyXML bool
+	// CompactXML specifies if remove all insignificant whitespace in the XML
+	// parts when saving the spreadsheet.
+	CompactXML bool
+	// Application specifies the name of the application which saved the
+	// spreadsheet in the document application properties.
+	Application string
+	// AppVersion specifies the version of the application which saved the
+	// spreadsheet in the document application properties.
+	AppVersion string
+	// CompressionLevel specifies the deflate compression level of the parts
+	// in the range of 1 (best speed) to 9 (best compression), the default
+	// compression level will be used if it is 0.
+	CompressionLevel int
+	// StoreMedia specifies if store the already-compressed media such as
+	// JPEG, PNG and GIF images without compression.
+	StoreMedia bool
+	// InlineStrings specifies if write the string cell values set by
+	// SetCellStr and SetCellValue as inline strings of the cells instead of
+	// adding them to the shared string table, which reduces the memory usage
+	// for write-once workloads with huge amounts of distinct strings.
+	InlineStrings bool
+	// UnzipXMLSizeLimit specifies the maximum uncompressed size in bytes of
+	// the worksheet and shared strings parts which will be unzipped into
+	// memory when opening the spreadsheet, the larger parts will be extracted
+	// to the temporary files and read from them on demand. The parts will be
+	// unzipped into memory regardless of their size if it is 0.
+	UnzipXMLSizeLimit int64
+	// OnProgress specifies the callback which will be called after each part
+	// of the spreadsheet has been read when opening or written when saving
+	// the spreadsheet.
+	OnProgress func(Progress)
+	// MaxMemory specifies the maximum size in bytes of the parts which will
+	// be read into memory when opening the spreadsheet. The worksheet and
+	// shared strings parts which don't fit in it will be extracted to the
+	// temporary files and read from them on demand, and the
+	// ErrMaxMemoryExceeded error will be returned if the other parts don't fit
+	// in it. There is no limit if it is 0.
+	MaxMemory int64
+	// SharedStringsOnDisk specifies if keep the string items of the shared
+	// string table in the temporary file when the shared strings part was
+	// extracted to it by the UnzipXMLSizeLimit or MaxMemory, only the index
+	// of them will be kept in memory. In this mode, the SI of the
+	// SharedStrings only holds the string items added after opening the
+	// spreadsheet, and the shared strings should be read by the functions
+	// such as GetCellValue and GetRows.
+	SharedStringsOnDisk bool
+	// MergeCellPolicy specifies how to handle writing the values and styles
+	// into the cells covered by the merged cells, the written cell will be
+	// redirected to the top-left cell of the merged cell by default.
+	MergeCellPolicy MergeCellPolicy
+	// Compatibility specifies the profile of the spreadsheet application
+	// which the saved spreadsheet targets, see CompatibilityProfile for the
+	// details.
+	Compatibility CompatibilityProfile
+	// Strict specifies if save the spreadsheet as the Strict Office Open XML
+	// conformant package.
+	Strict bool
+	// Repair specifies if repair the common corruption of the spreadsheet
+	// when opening it instead of failing or reading the wrong data: the
+	// malformed XML of the worksheets such as the unclosed rows, the
+	// duplicate and unordered rows and cells, the dimensions which don't
+	// match the cells, and the relationships of which targets are missing.
+	// All worksheets will be read into memory when opening, and the repaired
+	// issues can be got by GetRepairs.
+	Repair bool
+	// Logger specifies the logger which receives the warnings of the
+	// library, such as the errors of reading and decoding the parts which
+	// can't be returned by the functions without the error result. The
+	// warnings will be discarded if it is nil.
+	Logger Logger
+	// StrictChartFormat specifies if check the JSON format settings of the
+	// charts as strictly as the Chart structure, the unknown fields and the
+	// invalid series references will be returned as errors.
+	StrictChartFormat bool
+}
+
+// Logger defines the interface of the logger which receives the warnings of
+// the library, the *log.Logger of the standard library satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// MergeCellPolicy defined the policy of writing into the cells covered by the
+// merged cells. With the MergeCellPolicyRedirect, the values set by
+// SetCellValue will be written into the top-left cell of the merged cell, and
+// the style set by SetCellStyle will be applied to the whole merged cell if
+// the given area covers a part of it. With the MergeCellPolicyError, the
+// ErrMergedCell error will be returned instead. With the
+// MergeCellPolicyUnmerge, the merged cell will be unmerged before writing.
+type MergeCellPolicy byte
+
+// Merge cell policies enumeration.
+const (
+	MergeCellPolicyRedirect MergeCellPolicy = iota
+	MergeCellPolicyError
+	MergeCellPolicyUnmerge
+)
+
+// Progress directly maps the progress of opening or saving the spreadsheet
+// which reported by the OnProgress callback of the options. The Part is the
+// path of the part which has just been processed, the Parts and TotalParts
+// are the number of the processed parts and all parts of the spreadsheet, and
+// the Bytes is the total uncompressed size of the processed parts. The parts
+// which will be read on demand are reported without size when opening. For
+// example, print the progress of saving the spreadsheet:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{
+//        OnProgress: func(p excelize.Progress) {
+//            fmt.Printf("%d/%d parts, %d bytes\n", p.Parts, p.TotalParts, p.Bytes)
+//        },
+//    })
+//
+type Progress struct {
+	Part       string
+	Parts      int
+	TotalParts int
+	Bytes      int64
 }
 
 // OpenFile take the name of an spreadsheet file and returns a populated spreadsheet file struct
@@ -71,15 +205,41 @@ type Options struct {
 //        return
 //    }
 //
-// Note that the excelize just support decrypt and not support encrypt currently, the spreadsheet
-// saved by Save and SaveAs will be without password unprotected.
+// Note that the spreadsheet opened with password will be re-encrypted with the
+// same password when saved by Save and SaveAs, specify the options of SaveAs
+// to change or remove the password.
+//
+// Open the spreadsheet with huge worksheets, the worksheets larger than 16 MB
+// will be kept in the temporary files instead of memory, and the temporary
+// files will be removed by Close:
+//
+//    f, err := excelize.OpenFile("Book1.xlsx", excelize.Options{UnzipXMLSizeLimit: 16 << 20})
+//    if err != nil {
+//        return
+//    }
+//    defer f.Close()
+//
 func OpenFile(filename string, opt ...Options) (*File, error) {
+	return OpenFileContext(context.Background(), filename, opt...)
+}
+
+// OpenFileContext take the name of an spreadsheet file and returns a
+// populated spreadsheet file struct for it like OpenFile, the opening will be
+// stopped and the error of the context will be returned if the context is
+// canceled or its deadline is exceeded. For example, open the spreadsheet
+// with a timeout:
+//
+//    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//    defer cancel()
+//    f, err := excelize.OpenFileContext(ctx, "Book1.xlsx")
+//
+func OpenFileContext(ctx context.Context, filename string, opt ...Options) (*File, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
-	f, err := OpenReader(file, opt...)
+	f, err := openFile(ctx, file, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -87,15 +247,67 @@ func OpenFile(filename string, opt ...Options) (*File, error) {
 	return f, nil
 }
 
+// openFile provides a function to read the spreadsheet from the opened file.
+// The file will be read as a ZIP archive directly instead of being read into
+// memory entirely, if the UnzipXMLSizeLimit or MaxMemory of the options was
+// specified and the file is not encrypted.
+func openFile(ctx context.Context, file *os.File, opt ...Options) (*File, error) {
+	f := newFile()
+	f.setOpenOptions(opt...)
+	if f.options == nil || (f.options.UnzipXMLSizeLimit <= 0 && f.options.MaxMemory <= 0) {
+		return openReader(ctx, file, opt...)
+	}
+	header := make([]byte, len(oleIdentifier))
+	if _, err := file.ReadAt(header, 0); err == nil && bytes.Equal(header, oleIdentifier) {
+		return openReader(ctx, file, opt...)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	if err = f.readZipReader(ctx, zr); err != nil {
+		return nil, err
+	}
+	if err = f.readParts(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// readParts provides a function to read the calculation chain, workbook,
+// styles and theme parts of the opened spreadsheet, and repair the
+// spreadsheet if the Repair of the options was specified.
+func (f *File) readParts() (err error) {
+	if f.CalcChain, err = f.calcChainReader(); err != nil {
+		return
+	}
+	if f.sheetMap, err = f.getSheetMap(); err != nil {
+		return
+	}
+	if f.Styles, err = f.stylesReader(); err != nil {
+		return
+	}
+	if f.Theme, err = f.themeReader(); err != nil {
+		return
+	}
+	return f.repair()
+}
+
 // newFile is object builder
 func newFile() *File {
 	return &File{
 		xmlAttr:          make(map[string][]xml.Attr),
 		checked:          make(map[string]bool),
+		lazyParts:        make(map[string]lazyPart),
 		sheetMap:         make(map[string]string),
 		Comments:         make(map[string]*xlsxComments),
 		Drawings:         make(map[string]*xlsxWsDr),
-		sharedStringsMap: make(map[string]int),
+		sharedStringsMap: newSharedStringsIndex(),
 		Sheet:            make(map[string]*xlsxWorksheet),
 		DecodeVMLDrawing: make(map[string]*decodeVmlDrawing),
 		VMLDrawing:       make(map[string]*vmlDrawing),
@@ -105,17 +317,45 @@ func newFile() *File {
 }
 
 // OpenReader read data stream from io.Reader and return a populated
-// spreadsheet file.
+// spreadsheet file. The data stream which is larger than the MaxMemory of the
+// options will be copied to a temporary file and read from it like OpenFile,
+// except the spreadsheet encrypted with password, which must be read into
+// memory entirely.
 func OpenReader(r io.Reader, opt ...Options) (*File, error) {
-	b, err := ioutil.ReadAll(r)
+	return openReader(context.Background(), r, opt...)
+}
+
+// openReader read data stream from io.Reader with the context and return a
+// populated spreadsheet file. The data stream which is larger than the
+// MaxMemory of the options will be read by openTempFile, and the
+// ErrMaxMemoryExceeded error will be returned if it is encrypted.
+func openReader(ctx context.Context, r io.Reader, opt ...Options) (*File, error) {
+	var maxMemory int64
+	for _, o := range opt {
+		maxMemory = o.MaxMemory
+	}
+	rdr := r
+	if maxMemory > 0 {
+		rdr = io.LimitReader(r, maxMemory+1)
+	}
+	b, err := ioutil.ReadAll(rdr)
 	if err != nil {
 		return nil, err
 	}
+	if maxMemory > 0 && int64(len(b)) > maxMemory {
+		if bytes.HasPrefix(b, oleIdentifier) {
+			return nil, ErrMaxMemoryExceeded{MaxMemory: maxMemory, Size: int64(len(b))}
+		}
+		return openTempFile(ctx, io.MultiReader(bytes.NewReader(b), r), opt...)
+	}
 	f := newFile()
+	f.setOpenOptions(opt...)
 	if bytes.Contains(b, oleIdentifier) && len(opt) > 0 {
-		for _, o := range opt {
-			f.options = &o
+		// Keep the password to encrypt the spreadsheet with it when saving.
+		if f.options == nil {
+			f.options = &Options{}
 		}
+		f.options.Password = opt[len(opt)-1].Password
 		b, err = Decrypt(b, f.options)
 		if err != nil {
 			return nil, fmt.Errorf("decrypted file failed")
@@ -125,19 +365,209 @@ func OpenReader(r io.Reader, opt ...Options) (*File, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = f.readZipReader(ctx, zr); err != nil {
+		return nil, err
+	}
+	if err = f.readParts(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// openTempFile provides a function to copy the data stream to a temporary
+// file and read the spreadsheet from it like OpenFile, the temporary file
+// will be removed after the spreadsheet has been read.
+func openTempFile(ctx context.Context, r io.Reader, opt ...Options) (*File, error) {
+	tmp, err := ioutil.TempFile(os.TempDir(), "excelize-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err = io.Copy(tmp, r); err != nil {
+		return nil, err
+	}
+	return openFile(ctx, tmp, opt...)
+}
 
-	file, sheetCount, err := ReadZipReader(zr)
+// OpenReaderAt read data from io.ReaderAt with the given size and return a
+// populated spreadsheet file. Unlike OpenReader, the worksheet parts will not
+// be read at open time, they will be read and decoded from the reader on
+// demand when first accessed, so the reader must be kept readable until the
+// spreadsheet has been saved or no longer used. For example, open the
+// spreadsheet from a memory-mapped file or a HTTP range reader:
+//
+//    file, err := os.Open("Book1.xlsx")
+//    if err != nil {
+//        return
+//    }
+//    defer file.Close()
+//    info, err := file.Stat()
+//    if err != nil {
+//        return
+//    }
+//    f, err := excelize.OpenReaderAt(file, info.Size())
+//
+// The spreadsheet encrypted with password will be read into memory entirely.
+func OpenReaderAt(r io.ReaderAt, size int64, opt ...Options) (*File, error) {
+	header := make([]byte, len(oleIdentifier))
+	if _, err := r.ReadAt(header, 0); err == nil && bytes.Equal(header, oleIdentifier) {
+		return OpenReader(io.NewSectionReader(r, 0, size), opt...)
+	}
+	zr, err := zip.NewReader(r, size)
 	if err != nil {
 		return nil, err
 	}
-	f.SheetCount, f.XLSX = sheetCount, file
-	f.CalcChain = f.calcChainReader()
-	f.sheetMap = f.getSheetMap()
-	f.Styles = f.stylesReader()
-	f.Theme = f.themeReader()
+	f := newFile()
+	f.setOpenOptions(opt...)
+	f.XLSX = make(map[string][]byte, len(zr.File))
+	progress := Progress{TotalParts: len(zr.File)}
+	for _, v := range zr.File {
+		name := zipPartName(v.Name)
+		if strings.HasPrefix(name, "xl/worksheets/sheet") {
+			f.SheetCount++
+		}
+		if strings.HasPrefix(name, "xl/worksheets/") && path.Ext(name) == ".xml" {
+			f.XLSX[name], f.lazyParts[name] = nil, v
+			f.reportProgress(&progress, name, 0)
+			continue
+		}
+		if f.XLSX[name], err = readFile(v); err != nil {
+			return nil, err
+		}
+		f.reportProgress(&progress, name, int64(len(f.XLSX[name])))
+	}
+	if err = f.readParts(); err != nil {
+		return nil, err
+	}
 	return f, nil
 }
 
+// setOpenOptions provides a function to keep the options which affect the
+// reading and editing of the opened spreadsheet. The Password and the
+// save-only fields of the options are dropped, they only apply to the saving
+// by SaveAs.
+func (f *File) setOpenOptions(opt ...Options) {
+	if len(opt) == 0 {
+		return
+	}
+	o := opt[len(opt)-1]
+	o.Password = ""
+	o.setSaveOnly(saveOnlyOptions{})
+	if !reflect.DeepEqual(o, Options{}) {
+		f.options = &o
+	}
+}
+
+// logf provides a function to write the warning to the logger of the
+// options, the warning will be discarded if the logger wasn't specified.
+func (f *File) logf(format string, v ...interface{}) {
+	if f.options != nil && f.options.Logger != nil {
+		f.options.Logger.Printf(format, v...)
+	}
+}
+
+// readZipReader provides a function to read the parts of the spreadsheet from
+// the ZIP archive. The worksheet and shared strings parts which uncompressed
+// size exceeds the UnzipXMLSizeLimit of the options, or which don't fit in
+// the rest of the MaxMemory of the options will be extracted to the temporary
+// files.
+func (f *File) readZipReader(ctx context.Context, zr *zip.Reader) error {
+	var (
+		err                      error
+		limit, maxMemory, budget int64
+		progress                 = Progress{TotalParts: len(zr.File)}
+	)
+	if f.options != nil {
+		limit, maxMemory = f.options.UnzipXMLSizeLimit, f.options.MaxMemory
+	}
+	if maxMemory > 0 {
+		for _, v := range zr.File {
+			if !isExtractablePart(zipPartName(v.Name)) {
+				budget += int64(v.UncompressedSize64)
+			}
+		}
+		if budget > maxMemory {
+			return ErrMaxMemoryExceeded{MaxMemory: maxMemory, Size: budget}
+		}
+		budget = maxMemory - budget
+	}
+	f.XLSX = make(map[string][]byte, len(zr.File))
+	for _, v := range zr.File {
+		if err = ctx.Err(); err != nil {
+			_ = f.Close()
+			return err
+		}
+		name := zipPartName(v.Name)
+		if strings.HasPrefix(v.Name, "xl/worksheets/sheet") {
+			f.SheetCount++
+		}
+		size, extractable := int64(v.UncompressedSize64), isExtractablePart(name)
+		if extractable && ((limit > 0 && size > limit) || (maxMemory > 0 && size > budget)) {
+			var part tempFilePart
+			if part, err = unzipToTempFile(v); err != nil {
+				_ = f.Close()
+				return err
+			}
+			f.tempFiles = append(f.tempFiles, string(part))
+			f.XLSX[name], f.lazyParts[name] = nil, part
+			f.reportProgress(&progress, name, size)
+			continue
+		}
+		if extractable {
+			budget -= size
+		}
+		if f.XLSX[name], err = readFile(v); err != nil {
+			_ = f.Close()
+			return err
+		}
+		f.reportProgress(&progress, name, int64(len(f.XLSX[name])))
+	}
+	return nil
+}
+
+// isExtractablePart provides a function to check if the part of the
+// spreadsheet can be extracted to the temporary file instead of being read
+// into memory.
+func isExtractablePart(name string) bool {
+	return name == "xl/sharedStrings.xml" || strings.HasPrefix(name, "xl/worksheets/") && path.Ext(name) == ".xml"
+}
+
+// reportProgress provides a function to report the progress of opening or
+// saving the spreadsheet by the OnProgress callback of the options after the
+// part has been processed with the given uncompressed size.
+func (f *File) reportProgress(progress *Progress, part string, size int64) {
+	if f.options == nil || f.options.OnProgress == nil {
+		return
+	}
+	progress.Part, progress.Parts, progress.Bytes = part, progress.Parts+1, progress.Bytes+size
+	f.options.OnProgress(*progress)
+}
+
+// Close closes the spreadsheet and removes the temporary files which the
+// parts of the spreadsheet were extracted to. The spreadsheet should not be
+// used after it has been closed.
+func (f *File) Close() error {
+	var err error
+	if f.SharedStrings != nil {
+		err = f.SharedStrings.spill.close()
+	}
+	for _, name := range f.tempFiles {
+		if e := os.Remove(name); e != nil && !os.IsNotExist(e) && err == nil {
+			err = e
+		}
+	}
+	f.tempFiles = nil
+	for name, part := range f.lazyParts {
+		if _, ok := part.(tempFilePart); ok {
+			delete(f.lazyParts, name)
+		}
+	}
+	return err
+}
+
 // CharsetTranscoder Set user defined codepage transcoder function for open
 // XLSX from non UTF-8 encoding.
 func (f *File) CharsetTranscoder(fn charsetTranscoderFn) *File { f.CharsetReader = fn; return f }
@@ -175,7 +605,7 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 	)
 
 	if name, ok = f.sheetMap[trimSheetName(sheet)]; !ok {
-		err = fmt.Errorf("sheet %s is not exist", sheet)
+		err = ErrSheetNotExist{sheet}
 		return
 	}
 	if ws = f.Sheet[name]; f.Sheet[name] == nil {
@@ -183,14 +613,19 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 			err = fmt.Errorf("sheet %s is chart sheet", sheet)
 			return
 		}
+		var content []byte
+		if content, err = f.readBytes(name); err != nil {
+			return
+		}
+		content = namespaceStrictToTransitional(content)
 		ws = new(xlsxWorksheet)
 		if _, ok := f.xmlAttr[name]; !ok {
-			d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(name))))
+			d := f.xmlNewDecoder(bytes.NewReader(content))
 			f.xmlAttr[name] = append(f.xmlAttr[name], getRootElement(d)...)
 		}
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(name)))).
+		if err = f.xmlNewDecoder(bytes.NewReader(content)).
 			Decode(ws); err != nil && err != io.EOF {
-			err = fmt.Errorf("xml decode error: %s", err)
+			err = f.newXMLDecodeError(name, err)
 			return
 		}
 		err = nil
@@ -198,6 +633,9 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 			f.checked = make(map[string]bool)
 		}
 		if ok = f.checked[name]; !ok {
+			if f.options != nil && f.options.Repair {
+				f.repairWorksheet(sheet, name, ws)
+			}
 			checkSheet(ws)
 			if err = checkRow(ws); err != nil {
 				return
@@ -251,7 +689,7 @@ func (f *File) addRels(relPath, relType, target, targetMode string) int {
 	var uniqPart = map[string]string{
 		SourceRelationshipSharedStrings: "/xl/sharedStrings.xml",
 	}
-	rels := f.relsReader(relPath)
+	rels, _ := f.relsReader(relPath)
 	if rels == nil {
 		rels = &xlsxRelationships{}
 	}
@@ -308,7 +746,10 @@ func (f *File) addRels(relPath, relType, target, targetMode string) int {
 //    </row>
 //
 func (f *File) UpdateLinkedValue() error {
-	wb := f.workbookReader()
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
 	// recalculate formulas
 	wb.CalcPr = nil
 	for _, name := range f.GetSheetList() {
@@ -318,9 +759,8 @@ func (f *File) UpdateLinkedValue() error {
 		}
 		for indexR := range xlsx.SheetData.Row {
 			for indexC, col := range xlsx.SheetData.Row[indexR].C {
-				if col.F != nil && col.V != "" {
-					xlsx.SheetData.Row[indexR].C[indexC].V = ""
-					xlsx.SheetData.Row[indexR].C[indexC].T = ""
+				if col.F != nil && col.value() != "" {
+					xlsx.SheetData.Row[indexR].C[indexC].setValue("", "")
 				}
 			}
 		}
@@ -350,14 +790,47 @@ func (f *File) AddVBAProject(bin string) error {
 	if path.Ext(bin) != ".bin" {
 		return errors.New("unsupported VBA project extension")
 	}
+	file, _ := ioutil.ReadFile(bin)
+	return f.SetVBAProject(file)
+}
+
+// SetVBAProject provides the method to add or replace the VBA project of the
+// workbook by given content of the vbaProject.bin file, the content should be
+// an OLE compound file. The file extension should be .xlsm. For example,
+// copy the VBA project from a macro-enabled template:
+//
+//    tpl, err := excelize.OpenFile("Template.xlsm")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    bin, err := tpl.GetVBAProject()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := f.SetVBAProject(bin); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) SetVBAProject(bin []byte) error {
+	if _, err := mscfb.New(bytes.NewReader(bin)); err != nil {
+		return errors.New("invalid VBA project")
+	}
 	f.setContentTypePartVBAProjectExtensions()
-	wb := f.relsReader(f.getWorkbookRelsPath())
+	wb, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return err
+	}
+	if wb == nil {
+		wb = &xlsxRelationships{}
+		f.Relationships[f.getWorkbookRelsPath()] = wb
+	}
 	var rID int
-	var ok bool
 	for _, rel := range wb.Relationships {
-		if rel.Target == "vbaProject.bin" && rel.Type == SourceRelationshipVBAProject {
-			ok = true
-			continue
+		if rel.Type == SourceRelationshipVBAProject {
+			f.XLSX[f.getVBAProjectPath(rel.Target)] = bin
+			return nil
 		}
 		t, _ := strconv.Atoi(strings.TrimPrefix(rel.ID, "rId"))
 		if t > rID {
@@ -365,23 +838,114 @@ func (f *File) AddVBAProject(bin string) error {
 		}
 	}
 	rID++
-	if !ok {
-		wb.Relationships = append(wb.Relationships, xlsxRelationship{
-			ID:     "rId" + strconv.Itoa(rID),
-			Target: "vbaProject.bin",
-			Type:   SourceRelationshipVBAProject,
-		})
+	wb.Relationships = append(wb.Relationships, xlsxRelationship{
+		ID:     "rId" + strconv.Itoa(rID),
+		Target: "vbaProject.bin",
+		Type:   SourceRelationshipVBAProject,
+	})
+	f.XLSX[f.getVBAProjectPath("vbaProject.bin")] = bin
+	return nil
+}
+
+// GetVBAProject provides the method to get the content of the vbaProject.bin
+// file of the workbook, it will return an error if the workbook doesn't
+// contain the VBA project. For example, extract the VBA project to a file:
+//
+//    bin, err := f.GetVBAProject()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := ioutil.WriteFile("vbaProject.bin", bin, 0644); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) GetVBAProject() ([]byte, error) {
+	wb, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return nil, err
 	}
-	file, _ := ioutil.ReadFile(bin)
-	f.XLSX["xl/vbaProject.bin"] = file
-	return err
+	if wb != nil {
+		for _, rel := range wb.Relationships {
+			if rel.Type == SourceRelationshipVBAProject {
+				if bin, ok := f.XLSX[f.getVBAProjectPath(rel.Target)]; ok {
+					return bin, nil
+				}
+			}
+		}
+	}
+	return nil, errors.New("no VBA project in the workbook")
+}
+
+// GetVBAModules provides the method to get the modules of the VBA project of
+// the workbook. The modules are read from the PROJECT stream of the VBA
+// project, the type of the module could be one of the following:
+//
+//    Type     | Description
+//   ----------+----------------------------------------------------------
+//    Module   | Procedural module
+//    Class    | Class module
+//    Document | Document module of the workbook or worksheets
+//    Designer | Designer module such as user form
+//
+func (f *File) GetVBAModules() ([]VBAModule, error) {
+	var modules []VBAModule
+	bin, err := f.GetVBAProject()
+	if err != nil {
+		return modules, err
+	}
+	doc, err := mscfb.New(bytes.NewReader(bin))
+	if err != nil {
+		return modules, errors.New("invalid VBA project")
+	}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name != "PROJECT" || len(entry.Path) != 0 {
+			continue
+		}
+		buf := make([]byte, entry.Size)
+		if _, err = io.ReadFull(doc, buf); err != nil {
+			return modules, err
+		}
+		for _, line := range strings.Split(string(buf), "\n") {
+			kv := strings.SplitN(strings.TrimSpace(line), "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			module := VBAModule{Name: kv[1]}
+			switch kv[0] {
+			case "Module", "Class":
+				module.Type = kv[0]
+			case "Document":
+				module.Type = kv[0]
+				if idx := strings.Index(module.Name, "/"); idx != -1 {
+					module.Name = module.Name[:idx]
+				}
+			case "BaseClass":
+				module.Type = "Designer"
+			default:
+				continue
+			}
+			modules = append(modules, module)
+		}
+		return modules, nil
+	}
+	return modules, errors.New("invalid VBA project")
+}
+
+// getVBAProjectPath provides a function to get the path of the VBA project
+// part by given relationship target.
+func (f *File) getVBAProjectPath(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(f.getWorkbookPath()), target)
 }
 
 // setContentTypePartVBAProjectExtensions provides a function to set the
 // content type for relationship parts and the main document part.
 func (f *File) setContentTypePartVBAProjectExtensions() {
 	var ok bool
-	content := f.contentTypesReader()
+	content, _ := f.contentTypesReader()
 	for _, v := range content.Defaults {
 		if v.Extension == "bin" {
 			ok = true
@@ -390,6 +954,9 @@ func (f *File) setContentTypePartVBAProjectExtensions() {
 	for idx, o := range content.Overrides {
 		if o.PartName == "/xl/workbook.xml" {
 			content.Overrides[idx].ContentType = ContentTypeMacro
+			if o.ContentType == ContentTypeTemplate || o.ContentType == ContentTypeTemplateMacro {
+				content.Overrides[idx].ContentType = ContentTypeTemplateMacro
+			}
 		}
 	}
 	if !ok {
@@ -399,3 +966,179 @@ func (f *File) setContentTypePartVBAProjectExtensions() {
 		})
 	}
 }
+
+// ConvertToXLSM provides a function to convert the workbook to the
+// macro-enabled workbook by adding the required content types, the
+// template will be converted to the macro-enabled template. Then save the
+// workbook with .xlsm or .xltm extension. For example:
+//
+//    f.ConvertToXLSM()
+//    if err := f.AddVBAProject("vbaProject.bin"); err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := f.SaveAs("Book1.xlsm"); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) ConvertToXLSM() {
+	f.setContentTypePartVBAProjectExtensions()
+}
+
+// ConvertToXLSX provides a function to convert the macro-enabled workbook to
+// the macro-free workbook, the macro-enabled template will be converted to
+// the template. The VBA project, VBA project signature and their
+// relationships and content types will be removed. The form controls and
+// ActiveX controls of the worksheets will also be removed unless the
+// KeepControls of the options is true. Then save the workbook with .xlsx or
+// .xltx extension. For example:
+//
+//    f, err := excelize.OpenFile("Book1.xlsm")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := f.ConvertToXLSX(); err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := f.SaveAs("Book1.xlsx"); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) ConvertToXLSX(opt ...ConvertOptions) error {
+	var options ConvertOptions
+	for _, o := range opt {
+		options = o
+	}
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return err
+	}
+	wb, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return err
+	}
+	if wb != nil {
+		var rels []xlsxRelationship
+		for _, rel := range wb.Relationships {
+			if rel.Type == SourceRelationshipVBAProject {
+				f.deletePartWithRels(f.getVBAProjectPath(rel.Target))
+				continue
+			}
+			rels = append(rels, rel)
+		}
+		wb.Relationships = rels
+	}
+	if !options.KeepControls {
+		for sheet, sheetPath := range f.sheetMap {
+			if !strings.HasPrefix(sheetPath, "xl/worksheets/") {
+				continue
+			}
+			if err := f.deleteSheetControls(sheet); err != nil {
+				return err
+			}
+		}
+	}
+	for idx, o := range content.Overrides {
+		switch o.ContentType {
+		case ContentTypeMacro:
+			content.Overrides[idx].ContentType = ContentTypeSheetML
+		case ContentTypeTemplateMacro:
+			content.Overrides[idx].ContentType = ContentTypeTemplate
+		}
+	}
+	var defaults []xlsxDefault
+	for _, d := range content.Defaults {
+		if d.Extension == "bin" && d.ContentType == ContentTypeVBA {
+			continue
+		}
+		defaults = append(defaults, d)
+	}
+	content.Defaults = defaults
+	f.setContentTypePartBinaryOverrides()
+	return nil
+}
+
+// deleteSheetControls provides a function to remove the form controls and
+// ActiveX controls parts, relationships and the controls element of the
+// worksheet by given worksheet name.
+func (f *File) deleteSheetControls(sheet string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	sheetPath := f.sheetMap[trimSheetName(sheet)]
+	sheetRels, err := f.relsReader("xl/worksheets/_rels/" + strings.TrimPrefix(sheetPath, "xl/worksheets/") + ".rels")
+	if err != nil {
+		return err
+	}
+	if sheetRels != nil {
+		var rels []xlsxRelationship
+		for _, rel := range sheetRels.Relationships {
+			if rel.Type == SourceRelationshipCtrlProp || rel.Type == SourceRelationshipControl {
+				f.deletePartWithRels(path.Join(path.Dir(sheetPath), rel.Target))
+				continue
+			}
+			rels = append(rels, rel)
+		}
+		sheetRels.Relationships = rels
+	}
+	ws.Controls = nil
+	return nil
+}
+
+// deletePartWithRels provides a function to remove the part, the
+// relationships of the part, the targets of these relationships and the
+// content type overrides of them by given part path.
+func (f *File) deletePartWithRels(partPath string) {
+	partRels := getPartRelsPath(partPath)
+	if rels, _ := f.relsReader(partRels); rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode != "External" {
+				f.deletePartWithRels(path.Join(path.Dir(partPath), rel.Target))
+			}
+		}
+	}
+	delete(f.Relationships, partRels)
+	delete(f.XLSX, partRels)
+	delete(f.XLSX, partPath)
+	content, _ := f.contentTypesReader()
+	for k, v := range content.Overrides {
+		if v.PartName == "/"+partPath {
+			content.Overrides = append(content.Overrides[:k], content.Overrides[k+1:]...)
+			break
+		}
+	}
+}
+
+// setContentTypePartBinaryOverrides provides a function to set the content
+// type overrides for the binary parts of the ActiveX controls and printer
+// settings, which were covered by the default content type of the VBA
+// project.
+func (f *File) setContentTypePartBinaryOverrides() {
+	content, _ := f.contentTypesReader()
+	for _, d := range content.Defaults {
+		if d.Extension == "bin" {
+			return
+		}
+	}
+	overrides := make(map[string]bool)
+	for _, o := range content.Overrides {
+		overrides[o.PartName] = true
+	}
+	for partPath := range f.XLSX {
+		if path.Ext(partPath) != ".bin" || overrides["/"+partPath] {
+			continue
+		}
+		switch path.Base(path.Dir(partPath)) {
+		case "activeX":
+			content.Overrides = append(content.Overrides, xlsxOverride{
+				PartName: "/" + partPath, ContentType: ContentTypeActiveX,
+			})
+		case "printerSettings":
+			content.Overrides = append(content.Overrides, xlsxOverride{
+				PartName: "/" + partPath, ContentType: ContentTypePrinterSettings,
+			})
+		}
+	}
+}
@@ -1,21 +1,28 @@
 package excelize
 
 import (
+	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"image/color"
 	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
 	"io/ioutil"
+	"log"
 	"math"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -198,6 +205,23 @@ func TestCharsetTranscoder(t *testing.T) {
 	f.CharsetTranscoder(*new(charsetTranscoderFn))
 }
 
+// unsupportedZipBytes is a ZIP archive with a file compressed by the
+// unsupported compression algorithm.
+var unsupportedZipBytes = []byte{
+	0x50, 0x4b, 0x03, 0x04, 0x0a, 0x00, 0x09, 0x00, 0x63, 0x00, 0x47, 0xa3, 0xb6, 0x50, 0x00, 0x00,
+	0x00, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0b, 0x00, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x01, 0x99, 0x07, 0x00, 0x02, 0x00, 0x41, 0x45, 0x03, 0x00,
+	0x00, 0x21, 0x06, 0x59, 0xc0, 0x12, 0xf3, 0x19, 0xc7, 0x51, 0xd1, 0xc9, 0x31, 0xcb, 0xcc, 0x8a,
+	0xe1, 0x44, 0xe1, 0x56, 0x20, 0x24, 0x1f, 0xba, 0x09, 0xda, 0x53, 0xd5, 0xef, 0x50, 0x4b, 0x07,
+	0x08, 0x00, 0x00, 0x00, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x50, 0x4b, 0x01,
+	0x02, 0x1f, 0x00, 0x0a, 0x00, 0x09, 0x00, 0x63, 0x00, 0x47, 0xa3, 0xb6, 0x50, 0x00, 0x00, 0x00,
+	0x00, 0x1c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x70, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x01, 0x99, 0x07, 0x00, 0x02, 0x00, 0x41, 0x45, 0x03, 0x00, 0x00, 0x50, 0x4b,
+	0x05, 0x06, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x41, 0x00, 0x00, 0x00, 0x5d, 0x00,
+	0x00, 0x00, 0x00, 0x00,
+}
+
 func TestOpenReader(t *testing.T) {
 	_, err := OpenReader(strings.NewReader(""))
 	assert.EqualError(t, err, "zip: not a valid zip file")
@@ -230,21 +254,242 @@ func TestOpenReader(t *testing.T) {
 	_, err = OpenReader(r)
 	assert.EqualError(t, err, "unexpected EOF")
 
-	_, err = OpenReader(bytes.NewReader([]byte{
-		0x50, 0x4b, 0x03, 0x04, 0x0a, 0x00, 0x09, 0x00, 0x63, 0x00, 0x47, 0xa3, 0xb6, 0x50, 0x00, 0x00,
-		0x00, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0b, 0x00, 0x70, 0x61,
-		0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x01, 0x99, 0x07, 0x00, 0x02, 0x00, 0x41, 0x45, 0x03, 0x00,
-		0x00, 0x21, 0x06, 0x59, 0xc0, 0x12, 0xf3, 0x19, 0xc7, 0x51, 0xd1, 0xc9, 0x31, 0xcb, 0xcc, 0x8a,
-		0xe1, 0x44, 0xe1, 0x56, 0x20, 0x24, 0x1f, 0xba, 0x09, 0xda, 0x53, 0xd5, 0xef, 0x50, 0x4b, 0x07,
-		0x08, 0x00, 0x00, 0x00, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x50, 0x4b, 0x01,
-		0x02, 0x1f, 0x00, 0x0a, 0x00, 0x09, 0x00, 0x63, 0x00, 0x47, 0xa3, 0xb6, 0x50, 0x00, 0x00, 0x00,
-		0x00, 0x1c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x70, 0x61, 0x73, 0x73, 0x77,
-		0x6f, 0x72, 0x64, 0x01, 0x99, 0x07, 0x00, 0x02, 0x00, 0x41, 0x45, 0x03, 0x00, 0x00, 0x50, 0x4b,
-		0x05, 0x06, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x41, 0x00, 0x00, 0x00, 0x5d, 0x00,
-		0x00, 0x00, 0x00, 0x00,
-	}))
+	_, err = OpenReader(bytes.NewReader(unsupportedZipBytes))
+	assert.EqualError(t, err, "zip: unsupported compression algorithm")
+}
+
+func TestOpenReaderAt(t *testing.T) {
+	file, err := os.Open(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	defer file.Close()
+	info, err := file.Stat()
+	assert.NoError(t, err)
+	f, err := OpenReaderAt(file, info.Size())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.SheetCount)
+	assert.Len(t, f.lazyParts, 2)
+	assert.Nil(t, f.XLSX["xl/worksheets/sheet1.xml"])
+	val, err := f.GetCellValue("Sheet1", "A19")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total:", val)
+	assert.Len(t, f.lazyParts, 1)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "lazy"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestOpenReaderAt.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestOpenReaderAt.xlsx"))
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "lazy", val)
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rows)
+
+	// Test open password protected spreadsheet
+	file, err = os.Open(filepath.Join("test", "encryptAES.xlsx"))
+	assert.NoError(t, err)
+	defer file.Close()
+	info, err = file.Stat()
+	assert.NoError(t, err)
+	f, err = OpenReaderAt(file, info.Size(), Options{Password: "password"})
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SECRET", val)
+
+	// Test open invalid spreadsheet
+	_, err = OpenReaderAt(strings.NewReader(""), 0)
+	assert.EqualError(t, err, "zip: not a valid zip file")
+	zr, err := zip.NewReader(bytes.NewReader(unsupportedZipBytes), int64(len(unsupportedZipBytes)))
+	assert.NoError(t, err)
+	_, err = OpenReaderAt(bytes.NewReader(unsupportedZipBytes), int64(len(unsupportedZipBytes)))
+	assert.EqualError(t, err, "zip: unsupported compression algorithm")
+
+	// Test read and save the worksheet part with unsupported compression algorithm
+	f = NewFile()
+	f.lazyParts["xl/worksheets/sheet1.xml"] = zr.File[0]
+	assert.Empty(t, f.readXML("xl/worksheets/sheet1.xml"))
+	f.lazyParts["xl/worksheets/sheet1.xml"] = zr.File[0]
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	_, err = f.WriteToBuffer()
+	assert.EqualError(t, err, "zip: unsupported compression algorithm")
+}
+
+func TestOpenFileUnzipXMLSizeLimit(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.SheetCount)
+	assert.Len(t, f.tempFiles, 3)
+	for _, name := range []string{"xl/sharedStrings.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		assert.IsType(t, tempFilePart(""), f.lazyParts[name], name)
+		assert.Nil(t, f.XLSX[name], name)
+	}
+	val, err := f.GetCellValue("Sheet1", "A19")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total:", val)
+	assert.Nil(t, f.XLSX["xl/sharedStrings.xml"])
+	assert.NoError(t, f.ReadRows("Sheet2", func(row int, cells []Cell) error { return nil }))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "temp"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestOpenFileUnzipXMLSizeLimit.xlsx")))
+	tempFiles := f.tempFiles
+	assert.NoError(t, f.Close())
+	for _, name := range tempFiles {
+		_, err = os.Stat(name)
+		assert.True(t, os.IsNotExist(err))
+	}
+	assert.Empty(t, f.lazyParts)
+
+	f, err = OpenFile(filepath.Join("test", "TestOpenFileUnzipXMLSizeLimit.xlsx"))
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "temp", val)
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rows)
+	assert.NoError(t, f.Close())
+
+	// Test open spreadsheet from reader with the size limit
+	b, err := ioutil.ReadFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	f, err = OpenReader(bytes.NewReader(b), Options{UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	assert.Len(t, f.tempFiles, 3)
+	assert.NoError(t, f.Close())
+
+	// Test open password protected spreadsheet with the size limit
+	f, err = OpenFile(filepath.Join("test", "encryptAES.xlsx"), Options{Password: "password", UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SECRET", val)
+	assert.NoError(t, f.Close())
+
+	// Test open invalid spreadsheet with the size limit
+	_, err = OpenFile(filepath.Join("test", "images", "excel.png"), Options{UnzipXMLSizeLimit: 128})
+	assert.EqualError(t, err, "zip: not a valid zip file")
+	_, err = OpenReader(bytes.NewReader(unsupportedZipBytes), Options{UnzipXMLSizeLimit: 128})
+	assert.EqualError(t, err, "zip: unsupported compression algorithm")
+	zr, err := zip.NewReader(bytes.NewReader(unsupportedZipBytes), int64(len(unsupportedZipBytes)))
+	assert.NoError(t, err)
+	_, err = unzipToTempFile(zr.File[0])
 	assert.EqualError(t, err, "zip: unsupported compression algorithm")
+
+	// Test read the removed temporary file part
+	f = NewFile()
+	f.lazyParts["xl/worksheets/sheet1.xml"] = tempFilePart(filepath.Join("test", "nonexistent.xml"))
+	assert.Empty(t, f.readXML("xl/worksheets/sheet1.xml"))
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFile(Options{Logger: log.New(&buf, "", 0)})
+	// Test the decode error discarded by the function without error result.
+	f.WorkBook = nil
+	f.XLSX["xl/workbook.xml"] = MacintoshCyrillicCharset
+	assert.Empty(t, f.GetSheetList())
+	assert.Equal(t, "xl/workbook.xml: xml decode error: XML syntax error on line 1: invalid UTF-8\n", buf.String())
+
+	// Test the read error of the part which has not been loaded.
+	buf.Reset()
+	f.lazyParts["xl/worksheets/sheet1.xml"] = tempFilePart(filepath.Join("test", "nonexistent.xml"))
+	assert.Empty(t, f.readXML("xl/worksheets/sheet1.xml"))
+	assert.True(t, strings.HasPrefix(buf.String(), "xl/worksheets/sheet1.xml: read file error: "))
+
+	// Test keep the logger of the options when opening the spreadsheet.
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{Logger: log.New(&buf, "", 0)})
+	assert.NoError(t, err)
+	assert.NotNil(t, f.options.Logger)
+	f.XLSX["xl/comments1.xml"] = MacintoshCyrillicCharset
+	buf.Reset()
+	f.GetComments()
+	assert.Contains(t, buf.String(), "xl/comments1.xml: xml decode error: ")
+
+	// Test discard the warnings without the logger.
+	f = NewFile()
+	f.WorkBook = nil
+	f.XLSX["xl/workbook.xml"] = MacintoshCyrillicCharset
+	assert.Empty(t, f.GetSheetList())
+}
+
+func TestOpenFileMaxMemory(t *testing.T) {
+	b, err := ioutil.ReadFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(t, err)
+	var required int64
+	for _, v := range zr.File {
+		if !isExtractablePart(v.Name) {
+			required += int64(v.UncompressedSize64)
+		}
+	}
+	// Test open spreadsheet with the worksheets which don't fit in the memory
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{MaxMemory: required + 1331})
+	assert.NoError(t, err)
+	assert.Len(t, f.tempFiles, 2)
+	assert.NotNil(t, f.XLSX["xl/sharedStrings.xml"])
+	for _, name := range []string{"xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		assert.IsType(t, tempFilePart(""), f.lazyParts[name], name)
+	}
+	val, err := f.GetCellValue("Sheet1", "A19")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total:", val)
+	assert.NoError(t, f.Close())
+
+	// Test open spreadsheet with enough memory
+	f, err = OpenReader(bytes.NewReader(b), Options{MaxMemory: 1 << 20})
+	assert.NoError(t, err)
+	assert.Empty(t, f.tempFiles)
+	assert.NoError(t, f.Close())
+
+	// Test open spreadsheet which the other parts don't fit in the memory
+	_, err = OpenFile(filepath.Join("test", "Book1.xlsx"), Options{MaxMemory: required - 1})
+	assert.Equal(t, ErrMaxMemoryExceeded{MaxMemory: required - 1, Size: required}, err)
+	_, err = OpenReader(bytes.NewReader(b), Options{MaxMemory: int64(len(b)) - 1})
+	assert.Equal(t, ErrMaxMemoryExceeded{MaxMemory: int64(len(b)) - 1, Size: required}, err)
+	assert.EqualError(t, err, fmt.Sprintf("opening the spreadsheet requires %d bytes of memory, exceeds the limit of %d bytes", required, len(b)-1))
+
+	// Test open encrypted data stream which doesn't fit in the memory
+	b, err = ioutil.ReadFile(filepath.Join("test", "encryptAES.xlsx"))
+	assert.NoError(t, err)
+	_, err = OpenReader(bytes.NewReader(b), Options{Password: "password", MaxMemory: int64(len(b)) - 1})
+	assert.Equal(t, ErrMaxMemoryExceeded{MaxMemory: int64(len(b)) - 1, Size: int64(len(b))}, err)
+}
+
+func TestOpenReaderMaxMemory(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 2000; row++ {
+		for col := 1; col <= 10; col++ {
+			cell, _ := CoordinatesToCellName(col, row)
+			assert.NoError(t, f.SetCellValue("Sheet1", cell, rand.Int63()))
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	b := buf.Bytes()
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(t, err)
+	var required int64
+	for _, v := range zr.File {
+		if !isExtractablePart(v.Name) {
+			required += int64(v.UncompressedSize64)
+		}
+	}
+	assert.True(t, required < int64(len(b)))
+	// Test open data stream which doesn't fit in the memory from the
+	// temporary file
+	f, err = OpenReader(bytes.NewReader(b), Options{MaxMemory: required})
+	assert.NoError(t, err)
+	assert.Len(t, f.tempFiles, 1)
+	assert.IsType(t, tempFilePart(""), f.lazyParts["xl/worksheets/sheet1.xml"])
+	val, err := f.GetCellValue("Sheet1", "J2000")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, val)
+	assert.NoError(t, f.Close())
+
+	// Test open data stream with the read error
+	_, err = OpenReader(io.MultiReader(bytes.NewReader(b), iotest.TimeoutReader(bytes.NewReader(b))), Options{MaxMemory: required})
+	assert.Error(t, err)
 }
 
 func TestBrokenFile(t *testing.T) {
@@ -312,7 +557,7 @@ func TestNewFile(t *testing.T) {
 func TestAddDrawingVML(t *testing.T) {
 	// Test addDrawingVML with illegal cell coordinates.
 	f := NewFile()
-	assert.EqualError(t, f.addDrawingVML(0, "", "*", 0, 0), `cannot convert cell "*" to coordinates: invalid cell name "*"`)
+	assert.EqualError(t, f.addDrawingVML(0, "", "Sheet1", "*", 0, 0, &formatComment{}), `cannot convert cell "*" to coordinates: invalid cell name "*"`)
 }
 
 func TestSetCellHyperLink(t *testing.T) {
@@ -385,6 +630,112 @@ func TestGetCellHyperLink(t *testing.T) {
 
 }
 
+func TestCellLocationHyperLink(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sales Data")
+	f.NewSheet("Q1's")
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$2:$D$5"}))
+	assert.NoError(t, f.SetCellLocationHyperLink("Sheet1", "A1", HyperLinkLocation{Sheet: "Sales Data", Cell: "A40"}))
+	assert.NoError(t, f.SetCellLocationHyperLink("Sheet1", "A2", HyperLinkLocation{Sheet: "Q1's", Cell: "B2:C3"}))
+	assert.NoError(t, f.SetCellLocationHyperLink("Sheet1", "A3", HyperLinkLocation{Sheet: "Sheet1", Cell: "D8"}))
+	assert.NoError(t, f.SetCellLocationHyperLink("Sheet1", "A4", HyperLinkLocation{DefinedName: "Amount"}))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A5", "$B$2", "Location"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A6", "https://github.com/360EntSecGroup-Skylar/excelize", "External"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCellLocationHyperLink.xlsx")))
+
+	for cell, expected := range map[string]*HyperLink{
+		"A1": {Target: "'Sales Data'!A40", Location: HyperLinkLocation{Sheet: "Sales Data", Cell: "A40"}},
+		"A2": {Target: "'Q1''s'!B2:C3", Location: HyperLinkLocation{Sheet: "Q1's", Cell: "B2:C3"}},
+		"A3": {Target: "Sheet1!D8", Location: HyperLinkLocation{Sheet: "Sheet1", Cell: "D8"}},
+		"A4": {Target: "Amount", Location: HyperLinkLocation{DefinedName: "Amount"}},
+		"A5": {Target: "$B$2", Location: HyperLinkLocation{Sheet: "Sheet1", Cell: "B2"}},
+		"A6": {External: true, Target: "https://github.com/360EntSecGroup-Skylar/excelize"},
+		"A7": nil,
+	} {
+		link, err := f.GetCellHyperLinkTarget("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, link, cell)
+	}
+
+	// Test set cell location hyperlink with invalid destination
+	assert.EqualError(t, f.SetCellLocationHyperLink("Sheet1", "B1", HyperLinkLocation{Sheet: "SheetN", Cell: "A1"}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetCellLocationHyperLink("Sheet1", "B1", HyperLinkLocation{Sheet: "Sheet1", Cell: "A"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetCellLocationHyperLink("Sheet1", "B1", HyperLinkLocation{DefinedName: "Total"}), "defined name Total is not exist")
+	assert.EqualError(t, f.SetCellLocationHyperLink("Sheet1", "B1", HyperLinkLocation{Sheet: "Sheet1", DefinedName: "Amount"}), "the defined name can't be used with the sheet and cell of the location")
+	assert.EqualError(t, f.SetCellLocationHyperLink("Sheet1", "", HyperLinkLocation{Sheet: "Sheet1", Cell: "A1"}), `invalid cell name ""`)
+	// Test get cell hyperlink target with invalid parameters
+	_, err := f.GetCellHyperLinkTarget("Sheet1", "")
+	assert.EqualError(t, err, `invalid cell name ""`)
+	_, err = f.GetCellHyperLinkTarget("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestSetCellHyperLinkStyle(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{NumFmt: 14, Fill: Fill{Type: "pattern", Color: []string{"#FFFF00"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "A2", style))
+	opts := HyperLinkOptions{ApplyStyle: true}
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "https://github.com/360EntSecGroup-Skylar/excelize", "External", opts))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A2", "Sheet1!D8", "Location", opts))
+	assert.NoError(t, f.SetCellLocationHyperLink("Sheet1", "A3", HyperLinkLocation{Sheet: "Sheet1", Cell: "D9"}, opts))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A4", "Sheet1!D10", "Location", HyperLinkOptions{}))
+
+	s, err := f.stylesReader()
+	assert.NoError(t, err)
+	assert.Len(t, s.CellStyles.CellStyle, 3)
+	assert.Equal(t, &xlsxCellStyle{Name: "Followed Hyperlink", XfID: 1, BuiltInID: intPtr(9)}, s.CellStyles.CellStyle[1])
+	assert.Equal(t, &xlsxCellStyle{Name: "Hyperlink", XfID: 2, BuiltInID: intPtr(8)}, s.CellStyles.CellStyle[2])
+	assert.Equal(t, 3, s.CellStyleXfs.Count)
+	font := s.Fonts.Font[*s.CellStyleXfs.Xf[2].FontID]
+	assert.Equal(t, "single", *font.U.Val)
+	assert.Equal(t, 10, *font.Color.Theme)
+	assert.Equal(t, "Calibri", *font.Name.Val)
+	assert.Equal(t, 11, *s.Fonts.Font[*s.CellStyleXfs.Xf[1].FontID].Color.Theme)
+
+	// Test the hyperlink cells without the style share the same cell style.
+	styleA1, err := f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	styleA3, err := f.GetCellStyle("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, styleA1, styleA3)
+	assert.Equal(t, 2, *s.CellXfs.Xf[styleA1].XfID)
+	// Test the number format and fill of the cell are kept.
+	styleA2, err := f.GetCellStyle("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *s.CellXfs.Xf[styleA2].XfID)
+	assert.Equal(t, 14, *s.CellXfs.Xf[styleA2].NumFmtID)
+	assert.Equal(t, *s.CellXfs.Xf[style].FillID, *s.CellXfs.Xf[styleA2].FillID)
+	assert.Equal(t, *s.CellStyleXfs.Xf[2].FontID, *s.CellXfs.Xf[styleA2].FontID)
+	styleA4, err := f.GetCellStyle("Sheet1", "A4")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleA4)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellHyperLinkStyle.xlsx")))
+
+	// Test apply the hyperlink style with the existing named cell styles.
+	f, err = OpenFile(filepath.Join("test", "TestSetCellHyperLinkStyle.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "B1", "Sheet1!D8", "Location", opts))
+	s, err = f.stylesReader()
+	assert.NoError(t, err)
+	assert.Len(t, s.CellStyles.CellStyle, 3)
+	styleB1, err := f.GetCellStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, styleA1, styleB1)
+
+	// Test apply the hyperlink style without the styles.
+	f = NewFile()
+	f.Styles = &xlsxStyleSheet{}
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "Sheet1!D8", "Location", opts))
+	assert.Equal(t, 2, f.Styles.Fonts.Count)
+	assert.Equal(t, 1, f.Styles.CellXfs.Count)
+	// Test apply the hyperlink style with the invalid styles.
+	f = NewFile()
+	f.Styles = nil
+	f.XLSX["xl/styles.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.SetCellHyperLink("Sheet1", "A1", "Sheet1!D8", "Location", opts), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestSetCellFormula(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	if !assert.NoError(t, err) {
@@ -935,6 +1286,97 @@ func TestCopySheet(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCopySheet.xlsx")))
 }
 
+func TestCopySheetWithDependentParts(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{{"", "Apple", "Orange"}, {"Small", 2, 3}, {"Normal", 5, 2}} {
+		cell, _ := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$C$1","values":"Sheet1!$B$2:$C$2"}],"title":{"name":"Fruit"}}`))
+	assert.NoError(t, f.AddPicture("Sheet1", "E20", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "C3", `{"table_name":"Fruit"}`))
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "B2:C3"
+	assert.NoError(t, dvRange.SetRange(0, 10, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+
+	idx := f.NewSheet("Sheet2")
+	assert.NoError(t, f.CopySheet(0, idx))
+	assert.NoError(t, f.AddComment("Sheet2", "B1", `{"author":"Excelize: ","text":"This is another comment."}`))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCopySheetWithDependentParts.xlsx")))
+
+	for _, part := range []string{"xl/drawings/drawing2.xml", "xl/drawings/_rels/drawing2.xml.rels", "xl/charts/chart2.xml", "xl/comments2.xml", "xl/drawings/vmlDrawing2.vml", "xl/tables/table2.xml"} {
+		_, ok := f.XLSX[part]
+		assert.True(t, ok, part)
+	}
+	rels, err := f.relsReader("xl/worksheets/_rels/sheet2.xml.rels")
+	assert.NoError(t, err)
+	assert.NotNil(t, rels)
+	targets := map[string]string{}
+	for _, rel := range rels.Relationships {
+		targets[rel.Type] = rel.Target
+	}
+	assert.Equal(t, "../drawings/drawing2.xml", targets[SourceRelationshipDrawingML])
+	assert.Equal(t, "../drawings/vmlDrawing2.vml", targets[SourceRelationshipDrawingVML])
+	assert.Equal(t, "../comments2.xml", targets[SourceRelationshipComments])
+	assert.Equal(t, "../tables/table2.xml", targets[SourceRelationshipTable])
+	rels, err = f.relsReader("xl/drawings/_rels/drawing2.xml.rels")
+	assert.NoError(t, err)
+	assert.Equal(t, "../charts/chart2.xml", rels.Relationships[0].Target)
+	assert.Len(t, f.GetComments()["Sheet1"], 1)
+	assert.Len(t, f.GetComments()["Sheet2"], 2)
+	assert.Contains(t, string(f.XLSX["xl/tables/table2.xml"]), `name="Table2"`)
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:C3", ws.DataValidations.DataValidation[0].Sqref)
+}
+
+func TestCopySheetWithOtherParts(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet3")
+	// Prepare a worksheet with the VML drawing part but without comments, and
+	// the table named as the default name of the copied table.
+	_, err := f.workSheetReader("Sheet3")
+	assert.NoError(t, err)
+	rID := f.addRels("xl/worksheets/_rels/sheet2.xml.rels", SourceRelationshipDrawingVML, "../drawings/vmlDrawing1.vml", "")
+	f.addSheetNameSpace("Sheet3", SourceRelationship)
+	f.addSheetLegacyDrawing("Sheet3", rID)
+	vml := []byte(`<xml xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office"><o:shapelayout v:ext="edit"><o:idmap v:ext="edit" data="1"/></o:shapelayout></xml>`)
+	f.XLSX["xl/drawings/vmlDrawing1.vml"] = vml
+	assert.NoError(t, f.AddTable("Sheet3", "A1", "B2", `{"table_name":"Table3"}`))
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B2", `{"table_name":"Fruit"}`))
+
+	idx := f.NewSheet("Sheet2")
+	assert.NoError(t, f.CopySheet(0, idx))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCopySheetWithOtherParts.xlsx")))
+	assert.Equal(t, vml, f.XLSX["xl/drawings/vmlDrawing1.vml"])
+	rels, err := f.relsReader("xl/worksheets/_rels/sheet3.xml.rels")
+	assert.NoError(t, err)
+	targets := map[string]string{}
+	for _, rel := range rels.Relationships {
+		targets[rel.Type] = rel.Target
+	}
+	assert.Equal(t, "../drawings/vmlDrawing3.vml", targets[SourceRelationshipDrawingVML])
+	assert.Equal(t, "../comments2.xml", targets[SourceRelationshipComments])
+	assert.Equal(t, "../tables/table3.xml", targets[SourceRelationshipTable])
+	tables, err := f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	if assert.Len(t, tables, 1) {
+		assert.Equal(t, "Table4", tables[0].Name)
+	}
+	names, maxID, err := f.getTableNames()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, maxID)
+	assert.Len(t, names, 3)
+
+	// Test copy worksheet with the invalid table part
+	f.XLSX["xl/tables/table9.xml"] = MacintoshCyrillicCharset
+	idx = f.NewSheet("Sheet4")
+	assert.EqualError(t, f.CopySheet(0, idx), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestCopySheetError(t *testing.T) {
 	f, err := prepareTestBook1()
 	if !assert.NoError(t, err) {
@@ -1187,12 +1629,153 @@ func TestAddVBAProject(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddVBAProject.xlsm")))
 }
 
+func TestVBAProject(t *testing.T) {
+	f := NewFile()
+	_, err := f.GetVBAProject()
+	assert.EqualError(t, err, "no VBA project in the workbook")
+	_, err = f.GetVBAModules()
+	assert.EqualError(t, err, "no VBA project in the workbook")
+	assert.EqualError(t, f.SetVBAProject([]byte("VBA")), "invalid VBA project")
+
+	bin, err := ioutil.ReadFile(filepath.Join("test", "vbaProject.bin"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetVBAProject(bin))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestVBAProject.xlsm")))
+
+	f, err = OpenFile(filepath.Join("test", "TestVBAProject.xlsm"))
+	assert.NoError(t, err)
+	data, err := f.GetVBAProject()
+	assert.NoError(t, err)
+	assert.Equal(t, bin, data)
+	modules, err := f.GetVBAModules()
+	assert.NoError(t, err)
+	assert.Equal(t, []VBAModule{
+		{Name: "ThisWorkbook", Type: "Document"},
+		{Name: "Sheet1", Type: "Document"},
+		{Name: "Sheet2", Type: "Document"},
+		{Name: "Sheet3", Type: "Document"},
+	}, modules)
+
+	// Test replace the VBA project
+	compoundFile := cfb{}
+	compoundFile.put("PROJECT", []byte("ID=\"{00000000-0000-0000-0000-000000000000}\"\r\nDocument=ThisWorkbook/&H00000000\r\nModule=Module1\r\nClass=Class1\r\nBaseClass=UserForm1\r\nName=\"VBAProject\"\r\n"))
+	assert.NoError(t, f.SetVBAProject(compoundFile.write()))
+	modules, err = f.GetVBAModules()
+	assert.NoError(t, err)
+	assert.Equal(t, []VBAModule{
+		{Name: "ThisWorkbook", Type: "Document"},
+		{Name: "Module1", Type: "Module"},
+		{Name: "Class1", Type: "Class"},
+		{Name: "UserForm1", Type: "Designer"},
+	}, modules)
+	var count int
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	assert.NoError(t, err)
+	for _, rel := range rels.Relationships {
+		if rel.Type == SourceRelationshipVBAProject {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	// Test get modules from the VBA project without PROJECT stream
+	compoundFile = cfb{}
+	compoundFile.put("VBA", []byte{})
+	assert.NoError(t, f.SetVBAProject(compoundFile.write()))
+	_, err = f.GetVBAModules()
+	assert.EqualError(t, err, "invalid VBA project")
+	f.XLSX["xl/vbaProject.bin"] = []byte("VBA")
+	_, err = f.GetVBAModules()
+	assert.EqualError(t, err, "invalid VBA project")
+}
+
+func TestConvertToXLSX(t *testing.T) {
+	bin, err := ioutil.ReadFile(filepath.Join("test", "vbaProject.bin"))
+	assert.NoError(t, err)
+	prepareFile := func() *File {
+		f := NewFile()
+		assert.NoError(t, f.SetVBAProject(bin))
+		f.XLSX["xl/_rels/vbaProject.bin.rels"] = []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.microsoft.com/office/2006/relationships/vbaProjectSignature" Target="vbaProjectSignature.bin"/></Relationships>`)
+		f.XLSX["xl/vbaProjectSignature.bin"] = []byte{}
+		f.XLSX["xl/ctrlProps/ctrlProp1.xml"] = []byte(`<formControlPr xmlns="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main" objectType="Button"/>`)
+		f.XLSX["xl/activeX/activeX1.xml"] = []byte(`<ax:ocx xmlns:ax="http://schemas.microsoft.com/office/2006/activeX" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" ax:classid="{D7053240-CE69-11CD-A777-00DD01143C57}" ax:persistence="persistStreamInit" r:id="rId1"/>`)
+		f.XLSX["xl/activeX/_rels/activeX1.xml.rels"] = []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.microsoft.com/office/2006/relationships/activeXControlBinary" Target="activeX1.bin"/></Relationships>`)
+		f.XLSX["xl/activeX/activeX1.bin"] = []byte{}
+		f.setContentTypes("/xl/vbaProjectSignature.bin", "application/vnd.ms-office.vbaProjectSignature")
+		f.setContentTypes("/xl/ctrlProps/ctrlProp1.xml", "application/vnd.ms-excel.controlproperties+xml")
+		f.setContentTypes("/xl/activeX/activeX1.xml", "application/vnd.ms-office.activeX+xml")
+		f.Relationships["xl/worksheets/_rels/sheet1.xml.rels"] = &xlsxRelationships{Relationships: []xlsxRelationship{
+			{ID: "rId1", Type: SourceRelationshipCtrlProp, Target: "../ctrlProps/ctrlProp1.xml"},
+			{ID: "rId2", Type: SourceRelationshipControl, Target: "../activeX/activeX1.xml"},
+			{ID: "rId3", Type: SourceRelationshipHyperLink, Target: "https://github.com", TargetMode: "External"},
+		}}
+		ws, err := f.workSheetReader("Sheet1")
+		assert.NoError(t, err)
+		ws.Controls = &xlsxInnerXML{Content: `<control shapeId="1025" r:id="rId1" name="Button 1"/>`}
+		return f
+	}
+
+	// Test convert to XLSX with keep controls
+	f := prepareFile()
+	assert.NoError(t, f.ConvertToXLSX(ConvertOptions{KeepControls: true}))
+	_, err = f.GetVBAProject()
+	assert.EqualError(t, err, "no VBA project in the workbook")
+	assert.Contains(t, f.XLSX, "xl/ctrlProps/ctrlProp1.xml")
+	assert.Contains(t, f.XLSX, "xl/activeX/activeX1.bin")
+	assert.Contains(t, f.ContentTypes.Overrides, xlsxOverride{PartName: "/xl/activeX/activeX1.bin", ContentType: ContentTypeActiveX})
+	assert.NotNil(t, f.Sheet["xl/worksheets/sheet1.xml"].Controls)
+
+	f = prepareFile()
+	assert.NoError(t, f.ConvertToXLSX())
+	for _, part := range []string{"xl/vbaProject.bin", "xl/_rels/vbaProject.bin.rels", "xl/vbaProjectSignature.bin", "xl/ctrlProps/ctrlProp1.xml", "xl/activeX/activeX1.xml", "xl/activeX/_rels/activeX1.xml.rels", "xl/activeX/activeX1.bin"} {
+		assert.NotContains(t, f.XLSX, part)
+	}
+	for _, o := range f.ContentTypes.Overrides {
+		assert.NotContains(t, []string{ContentTypeMacro, "application/vnd.ms-office.vbaProjectSignature", "application/vnd.ms-excel.controlproperties+xml", "application/vnd.ms-office.activeX+xml"}, o.ContentType)
+	}
+	for _, d := range f.ContentTypes.Defaults {
+		assert.NotEqual(t, "bin", d.Extension)
+	}
+	assert.Equal(t, []xlsxRelationship{{ID: "rId3", Type: SourceRelationshipHyperLink, Target: "https://github.com", TargetMode: "External"}}, f.Relationships["xl/worksheets/_rels/sheet1.xml.rels"].Relationships)
+	assert.Nil(t, f.Sheet["xl/worksheets/sheet1.xml"].Controls)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestConvertToXLSX.xlsx")))
+
+	// Test convert to XLSX with unsupported charset worksheet
+	f = NewFile()
+	f.sheetMap["Chart1"] = "xl/chartsheets/sheet1.xml"
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	f.XLSX["xl/worksheets/sheet1.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.ConvertToXLSX(), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestConvertToXLSM(t *testing.T) {
+	f := NewFile()
+	f.ConvertToXLSM()
+	assert.Contains(t, f.ContentTypes.Overrides, xlsxOverride{PartName: "/xl/workbook.xml", ContentType: ContentTypeMacro})
+	assert.Contains(t, f.ContentTypes.Defaults, xlsxDefault{Extension: "bin", ContentType: ContentTypeVBA})
+	assert.NoError(t, f.ConvertToXLSX())
+	assert.Contains(t, f.ContentTypes.Overrides, xlsxOverride{PartName: "/xl/workbook.xml", ContentType: ContentTypeSheetML})
+
+	// Test convert template
+	f = NewFile()
+	for idx, o := range f.ContentTypes.Overrides {
+		if o.PartName == "/xl/workbook.xml" {
+			f.ContentTypes.Overrides[idx].ContentType = ContentTypeTemplate
+		}
+	}
+	f.ConvertToXLSM()
+	assert.Contains(t, f.ContentTypes.Overrides, xlsxOverride{PartName: "/xl/workbook.xml", ContentType: ContentTypeTemplateMacro})
+	assert.NoError(t, f.ConvertToXLSX())
+	assert.Contains(t, f.ContentTypes.Overrides, xlsxOverride{PartName: "/xl/workbook.xml", ContentType: ContentTypeTemplate})
+}
+
 func TestContentTypesReader(t *testing.T) {
 	// Test unsupport charset.
 	f := NewFile()
 	f.ContentTypes = nil
 	f.XLSX["[Content_Types].xml"] = MacintoshCyrillicCharset
-	f.contentTypesReader()
+	_, err := f.contentTypesReader()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestWorkbookReader(t *testing.T) {
@@ -1200,7 +1783,8 @@ func TestWorkbookReader(t *testing.T) {
 	f := NewFile()
 	f.WorkBook = nil
 	f.XLSX["xl/workbook.xml"] = MacintoshCyrillicCharset
-	f.workbookReader()
+	_, err := f.workbookReader()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestWorkSheetReader(t *testing.T) {
@@ -1226,7 +1810,8 @@ func TestRelsReader(t *testing.T) {
 	rels := "xl/_rels/workbook.xml.rels"
 	f.Relationships[rels] = nil
 	f.XLSX[rels] = MacintoshCyrillicCharset
-	f.relsReader(rels)
+	_, err := f.relsReader(rels)
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestDeleteSheetFromWorkbookRels(t *testing.T) {
@@ -1337,3 +1922,97 @@ func BenchmarkOpenFile(b *testing.B) {
 		}
 	}
 }
+
+func TestConcurrentReads(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	expected := make(map[string][][]string)
+	for _, sheet := range []string{"Sheet1", "Sheet2"} {
+		_, err = f.GetCellStyle(sheet, "A19")
+		assert.NoError(t, err)
+		expected[sheet], err = f.GetRows(sheet)
+		assert.NoError(t, err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, sheet := range []string{"Sheet1", "Sheet2"} {
+				_, err := f.GetCellValue(sheet, "A19")
+				assert.NoError(t, err)
+				rows, err := f.GetRows(sheet)
+				assert.NoError(t, err)
+				assert.Equal(t, expected[sheet], rows)
+				_, err = f.GetCols(sheet)
+				assert.NoError(t, err)
+				_, err = f.GetCellFormula(sheet, "B19")
+				assert.NoError(t, err)
+				_, err = f.GetCellStyle(sheet, "A19")
+				assert.NoError(t, err)
+				_, err = f.CalcCellValue(sheet, "B19")
+				assert.NoError(t, err)
+				assert.NoError(t, f.ReadRows(sheet, func(int, []Cell) error { return nil }))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestProgress(t *testing.T) {
+	var progress []Progress
+	onProgress := func(p Progress) { progress = append(progress, p) }
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{OnProgress: onProgress})
+	assert.NoError(t, err)
+	assert.Len(t, progress, len(f.XLSX))
+	var size int64
+	for i, p := range progress {
+		assert.Equal(t, i+1, p.Parts)
+		assert.Equal(t, len(f.XLSX), p.TotalParts)
+		size += int64(len(f.XLSX[p.Part]))
+		assert.Equal(t, size, p.Bytes)
+	}
+
+	progress = nil
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"stream"}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestProgress.xlsx"), Options{OnProgress: onProgress}))
+	assert.Len(t, progress, len(f.XLSX)+1)
+	last := progress[len(progress)-1]
+	assert.Equal(t, last.TotalParts, last.Parts)
+	assert.True(t, last.Bytes > progress[len(progress)-2].Bytes)
+
+	// Test open spreadsheet with lazy loaded parts
+	progress = nil
+	file, err := os.Open(filepath.Join("test", "TestProgress.xlsx"))
+	assert.NoError(t, err)
+	defer file.Close()
+	info, err := file.Stat()
+	assert.NoError(t, err)
+	f, err = OpenReaderAt(file, info.Size(), Options{OnProgress: onProgress})
+	assert.NoError(t, err)
+	assert.Len(t, progress, len(f.XLSX))
+	for _, p := range progress {
+		if _, ok := f.lazyParts[p.Part]; ok {
+			assert.Nil(t, f.XLSX[p.Part])
+		}
+	}
+	assert.Equal(t, progress[len(progress)-1].TotalParts, progress[len(progress)-1].Parts)
+}
+
+func TestOpenFileContext(t *testing.T) {
+	f, err := OpenFileContext(context.Background(), filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("test", "Book1.xlsx"), f.Path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = OpenFileContext(ctx, filepath.Join("test", "Book1.xlsx"))
+	assert.EqualError(t, err, context.Canceled.Error())
+	_, err = OpenFileContext(ctx, filepath.Join("test", "Book1.xlsx"), Options{UnzipXMLSizeLimit: 128})
+	assert.EqualError(t, err, context.Canceled.Error())
+	_, err = OpenFileContext(ctx, filepath.Join("test", "Book1.xlsx.nonexistent"))
+	assert.Error(t, err)
+}
@@ -14,18 +14,27 @@ package excelize
 import (
 	"archive/zip"
 	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 )
 
 // NewFile provides a function to create new file by default template. For
-// example:
+// example, create a spreadsheet which writes the strings as inline strings:
 //
-//    f := NewFile()
+//    f := NewFile(excelize.Options{InlineStrings: true})
 //
-func NewFile() *File {
+func NewFile(opt ...Options) *File {
 	file := make(map[string][]byte)
 	file["_rels/.rels"] = []byte(XMLHeader + templateRels)
 	file["docProps/app.xml"] = []byte(XMLHeader + templateDocpropsApp)
@@ -38,33 +47,113 @@ func NewFile() *File {
 	file["[Content_Types].xml"] = []byte(XMLHeader + templateContentTypes)
 	f := newFile()
 	f.SheetCount, f.XLSX = 1, file
-	f.CalcChain = f.calcChainReader()
+	f.CalcChain, _ = f.calcChainReader()
 	f.Comments = make(map[string]*xlsxComments)
-	f.ContentTypes = f.contentTypesReader()
+	f.ContentTypes, _ = f.contentTypesReader()
 	f.Drawings = make(map[string]*xlsxWsDr)
-	f.Styles = f.stylesReader()
+	f.Styles, _ = f.stylesReader()
 	f.DecodeVMLDrawing = make(map[string]*decodeVmlDrawing)
 	f.VMLDrawing = make(map[string]*vmlDrawing)
-	f.WorkBook = f.workbookReader()
+	f.WorkBook, _ = f.workbookReader()
 	f.Relationships = make(map[string]*xlsxRelationships)
-	f.Relationships["xl/_rels/workbook.xml.rels"] = f.relsReader("xl/_rels/workbook.xml.rels")
+	f.Relationships["xl/_rels/workbook.xml.rels"], _ = f.relsReader("xl/_rels/workbook.xml.rels")
 	f.Sheet["xl/worksheets/sheet1.xml"], _ = f.workSheetReader("Sheet1")
 	f.sheetMap["Sheet1"] = "xl/worksheets/sheet1.xml"
-	f.Theme = f.themeReader()
+	f.Theme, _ = f.themeReader()
+	for _, o := range opt {
+		f.options = &o
+	}
 	return f
 }
 
 // Save provides a function to override the spreadsheet with origin path.
 func (f *File) Save() error {
+	return f.SaveContext(context.Background())
+}
+
+// SaveContext provides a function to override the spreadsheet with origin
+// path like Save, the saving will be stopped and the error of the context
+// will be returned if the context is canceled or its deadline is exceeded.
+func (f *File) SaveContext(ctx context.Context) error {
 	if f.Path == "" {
 		return fmt.Errorf("no path defined for file, consider File.WriteTo or File.Write")
 	}
-	return f.SaveAs(f.Path)
+	return f.SaveAsContext(ctx, f.Path)
 }
 
 // SaveAs provides a function to create or update to an spreadsheet at the
-// provided path.
+// provided path. Specify the password by options to save the spreadsheet
+// encrypted with ECMA-376 agile encryption. For example:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{Password: "password"})
+//
+// The options of SaveAs only apply to this saving, and the options specified
+// when opening or creating the spreadsheet will be used by the next saving.
+// The fields which are not specified in the options of SaveAs keep the values
+// specified when creating the spreadsheet, except the Password. The fields
+// which only affect the saving, such as PrettyXML, CompressionLevel and
+// Strict, are not kept from the options specified when opening the
+// spreadsheet.
+// The spreadsheet opened with password will be re-encrypted with the same
+// password by Save, WriteTo and SaveAs if not specified options, specify the
+// options with a new password to change the password, or with an empty
+// password to save the spreadsheet without password protection:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{})
+//
+// Specify the PrettyXML or CompactXML of the options to indent or compact the
+// XML parts of the spreadsheet, and specify the Application and AppVersion of
+// the options to set the name and version of the application which saved the
+// spreadsheet. For example:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{
+//        PrettyXML:   true,
+//        Application: "Report Generator",
+//        AppVersion:  "1.0000",
+//    })
+//
+// Specify the CompressionLevel and StoreMedia of the options to control the
+// compression of the parts, for example, faster saves of the spreadsheet with
+// lots of images:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{
+//        CompressionLevel: 1,
+//        StoreMedia:       true,
+//    })
+//
+// Specify the Compatibility of the options to avoid the constructs which the
+// target spreadsheet application mishandles, for example, save the
+// spreadsheet which will be opened by Google Sheets:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{
+//        Compatibility: excelize.CompatibilityGoogleSheets,
+//    })
+//
+// Specify the Strict of the options to save the spreadsheet as the Strict
+// Office Open XML (ISO/IEC 29500 Strict) conformant package, the spreadsheet
+// will be saved as the Transitional conformant package otherwise, including
+// the spreadsheet opened from the Strict conformant package:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{Strict: true})
+//
+// The workbook will be saved in the OpenDocument Spreadsheet format if the
+// extension of the file name is ".ods", see WriteODS for the details.
+//
 func (f *File) SaveAs(name string, opt ...Options) error {
+	return f.SaveAsContext(context.Background(), name, opt...)
+}
+
+// SaveAsContext provides a function to create or update to an spreadsheet at
+// the provided path like SaveAs, the saving will be stopped and the error of
+// the context will be returned if the context is canceled or its deadline is
+// exceeded, the incomplete spreadsheet may be left at the path in that case.
+// For example, save the spreadsheet with a timeout:
+//
+//    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//    defer cancel()
+//    err := f.SaveAsContext(ctx, "Book1.xlsx")
+//
+func (f *File) SaveAsContext(ctx context.Context, name string, opt ...Options) error {
 	if len(name) > MaxFileNameLength {
 		return errors.New("file name length exceeds maximum limit")
 	}
@@ -73,11 +162,85 @@ func (f *File) SaveAs(name string, opt ...Options) error {
 		return err
 	}
 	defer file.Close()
-	f.options = nil
-	for _, o := range opt {
-		f.options = &o
+	if len(opt) > 0 {
+		defer func(options *Options) { f.options = options }(f.options)
+		f.options = f.saveOptions(opt[len(opt)-1])
+	}
+	if strings.EqualFold(filepath.Ext(name), ".ods") {
+		return f.writeODS(ctx, file)
+	}
+	_, err = f.writeTo(ctx, file)
+	return err
+}
+
+// saveOnlyOptions is the fields of the options which only affect the saving
+// of the spreadsheet. These fields are dropped from the options specified
+// when opening the spreadsheet, and the non-zero fields of them specified by
+// SaveAs override the options of the spreadsheet for that saving.
+type saveOnlyOptions struct {
+	PrettyXML        bool
+	CompactXML       bool
+	Application      string
+	AppVersion       string
+	CompressionLevel int
+	StoreMedia       bool
+	Compatibility    CompatibilityProfile
+	Strict           bool
+}
+
+// saveOnly returns the save-only fields of the options.
+func (o *Options) saveOnly() saveOnlyOptions {
+	return saveOnlyOptions{
+		PrettyXML:        o.PrettyXML,
+		CompactXML:       o.CompactXML,
+		Application:      o.Application,
+		AppVersion:       o.AppVersion,
+		CompressionLevel: o.CompressionLevel,
+		StoreMedia:       o.StoreMedia,
+		Compatibility:    o.Compatibility,
+		Strict:           o.Strict,
+	}
+}
+
+// setSaveOnly provides a function to set the save-only fields of the
+// options.
+func (o *Options) setSaveOnly(s saveOnlyOptions) {
+	o.PrettyXML, o.CompactXML = s.PrettyXML, s.CompactXML
+	o.Application, o.AppVersion = s.Application, s.AppVersion
+	o.CompressionLevel, o.StoreMedia = s.CompressionLevel, s.StoreMedia
+	o.Compatibility, o.Strict = s.Compatibility, s.Strict
+}
+
+// saveOptions provides a function to get the options of saving the
+// spreadsheet by given options of SaveAs. The Password of the given options
+// is always used, the non-zero save-only fields and callbacks of the given
+// options override the options of the spreadsheet, and the PrettyXML and
+// CompactXML override each other. The other fields of the given options
+// don't affect the saving and are ignored.
+func (f *File) saveOptions(opt Options) *Options {
+	var o Options
+	if f.options != nil {
+		o = *f.options
+	}
+	s, src := o.saveOnly(), opt.saveOnly()
+	if src.PrettyXML || src.CompactXML {
+		s.PrettyXML, s.CompactXML = false, false
+	}
+	dst, val := reflect.ValueOf(&s).Elem(), reflect.ValueOf(src)
+	for i := 0; i < val.NumField(); i++ {
+		if field := val.Field(i); field.Interface() != reflect.Zero(field.Type()).Interface() {
+			dst.Field(i).Set(field)
+		}
+	}
+	o.setSaveOnly(s)
+	o.Password = opt.Password
+	if opt.OnProgress != nil {
+		o.OnProgress = opt.OnProgress
+	}
+	if opt.Logger != nil {
+		o.Logger = opt.Logger
 	}
-	return f.Write(file)
+	return &o
 }
 
 // Write provides a function to write to an io.Writer.
@@ -86,74 +249,643 @@ func (f *File) Write(w io.Writer) error {
 	return err
 }
 
-// WriteTo implements io.WriterTo to write the file.
+// WriteTo implements io.WriterTo to write the file. The parts of the
+// spreadsheet will be written to the writer incrementally without buffering
+// the whole package in memory, unless the spreadsheet will be encrypted with
+// the password.
 func (f *File) WriteTo(w io.Writer) (int64, error) {
-	buf, err := f.WriteToBuffer()
-	if err != nil {
-		return 0, err
+	return f.writeTo(context.Background(), w)
+}
+
+// writeTo provides a function to write the file to the writer with the
+// context.
+func (f *File) writeTo(ctx context.Context, w io.Writer) (int64, error) {
+	if f.options != nil && f.options.Password != "" {
+		buf, err := f.writeToBuffer(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return buf.WriteTo(w)
 	}
-	return buf.WriteTo(w)
+	cw := &countWriter{w: w}
+	err := f.writeToZip(ctx, cw)
+	return cw.n, err
 }
 
 // WriteToBuffer provides a function to get bytes.Buffer from the saved file.
 func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
+	return f.writeToBuffer(context.Background())
+}
+
+// writeToBuffer provides a function to get bytes.Buffer from the saved file
+// with the context.
+func (f *File) writeToBuffer(ctx context.Context) (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
-	zw := zip.NewWriter(buf)
+	if err := f.writeToZip(ctx, buf); err != nil {
+		return buf, err
+	}
+	if f.options != nil && f.options.Password != "" {
+		b, err := Encrypt(buf.Bytes(), f.options)
+		if err != nil {
+			return buf, err
+		}
+		buf.Reset()
+		buf.Write(b)
+	}
+	return buf, nil
+}
+
+// writeToZip provides a function to serialize the parts of the spreadsheet
+// and write them to the writer as a ZIP archive one by one. The ZIP64
+// extensions will be used for the parts and the archive which exceed the
+// limits of the ZIP format.
+func (f *File) writeToZip(ctx context.Context, w io.Writer) error {
+	lw := &zip64Writer{w: &contextWriter{ctx: ctx, w: w}}
+	zw := zip.NewWriter(lw)
+	if f.options != nil && f.options.CompressionLevel != 0 {
+		level := f.options.CompressionLevel
+		if level < flate.BestSpeed || level > flate.BestCompression {
+			return fmt.Errorf("invalid compression level %d", level)
+		}
+		zw.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		})
+	}
 	f.calcChainWriter()
 	f.commentsWriter()
 	f.contentTypesWriter()
 	f.drawingsWriter()
 	f.vmlDrawingWriter()
 	f.workBookWriter()
-	f.workSheetWriter()
+	// The worksheets and the shared strings will be serialized into the ZIP
+	// archive directly, unless all parts should be serialized for signing.
+	buffered := len(f.signatures) > 0
+	if buffered {
+		f.workSheetWriter()
+		f.sharedStringsWriter()
+	}
 	f.relsWriter()
-	f.sharedStringsWriter()
 	f.styleSheetWriter()
+	if err := f.setSaveOptions(); err != nil {
+		return err
+	}
+	if err := f.signWorkbook(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
+	parts := make(map[string]serializedPart)
+	if !buffered {
+		parts = f.serializedParts()
+	}
+	progress := Progress{TotalParts: len(f.streams) + len(f.XLSX)}
+	for path := range parts {
+		if _, ok := f.XLSX[path]; !ok {
+			progress.TotalParts++
+		}
+	}
 	for path, stream := range f.streams {
-		fi, err := zw.Create(path)
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			zw.Close()
-			return buf, err
+			return err
 		}
-		var from io.Reader
-		from, err = stream.rawData.Reader()
+		from, err := stream.rawData.Reader()
 		if err != nil {
 			stream.rawData.Close()
-			return buf, err
+			return err
+		}
+		var size uint64
+		if r, ok := from.(interface{ Size() int64 }); ok {
+			size = uint64(r.Size())
 		}
-		_, err = io.Copy(fi, from)
+		fi, err := f.createZipPart(zw, lw, path, size)
 		if err != nil {
 			zw.Close()
-			return buf, err
+			return err
+		}
+		cw := &countWriter{w: fi}
+		err = f.copyXMLPart(cw, from, path)
+		if err != nil {
+			zw.Close()
+			return err
 		}
 		stream.rawData.Close()
+		f.reportProgress(&progress, path, cw.n)
+	}
+
+	for path, part := range parts {
+		if err := ctx.Err(); err != nil {
+			zw.Close()
+			return err
+		}
+		fi, err := f.createZipPart(zw, lw, path, part.size)
+		if err != nil {
+			zw.Close()
+			return err
+		}
+		cw := &countWriter{w: fi}
+		if err = f.copySerializedPart(cw, part.serialize, path); err != nil {
+			zw.Close()
+			return err
+		}
+		f.reportProgress(&progress, path, cw.n)
 	}
 
 	for path, content := range f.XLSX {
-		fi, err := zw.Create(path)
+		if _, ok := parts[path]; ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			zw.Close()
+			return err
+		}
+		part, lazy := f.lazyParts[path]
+		size := uint64(len(content))
+		if lazy {
+			size = lazyPartSize(part)
+		}
+		fi, err := f.createZipPart(zw, lw, path, size)
 		if err != nil {
 			zw.Close()
-			return buf, err
+			return err
+		}
+		cw := &countWriter{w: fi}
+		switch {
+		case lazy:
+			err = f.copyZipPart(cw, part, path)
+		case buffered:
+			_, err = cw.Write(content)
+		default:
+			err = f.copyXMLPart(cw, bytes.NewReader(content), path)
 		}
-		_, err = fi.Write(content)
 		if err != nil {
 			zw.Close()
-			return buf, err
+			return err
 		}
+		f.reportProgress(&progress, path, cw.n)
 	}
+	return zw.Close()
+}
 
-	if f.options != nil && f.options.Password != "" {
-		if err := zw.Close(); err != nil {
-			return buf, err
+// serializedPart is the part which will be serialized into the ZIP archive
+// directly, the size is the upper bound of the size of the serialized part,
+// which decides if the part will be written in the ZIP64 format, since the
+// actual size is unknown before serializing it.
+type serializedPart struct {
+	size      uint64
+	serialize func(io.Writer) error
+}
+
+// serializedParts provides a function to get the worksheets and the shared
+// strings which have been loaded into memory, and the functions to serialize
+// them to the writer by given part path.
+func (f *File) serializedParts() map[string]serializedPart {
+	parts := make(map[string]serializedPart)
+	for p, ws := range f.Sheet {
+		if _, ok := f.streams[p]; ws == nil || ok {
+			continue
 		}
-		b, err := Encrypt(buf.Bytes(), f.options)
+		p, ws := p, ws
+		parts[p] = serializedPart{size: worksheetSizeLimit(ws), serialize: func(w io.Writer) error {
+			return f.writeSheet(w, p, ws)
+		}}
+	}
+	if sst := f.SharedStrings; sst != nil {
+		parts["xl/sharedStrings.xml"] = serializedPart{size: sharedStringsSizeLimit(sst), serialize: func(w io.Writer) error {
+			return writeSharedStrings(w, sst, func(output []byte) []byte {
+				return f.replaceNameSpaceBytes("xl/sharedStrings.xml", output)
+			})
+		}}
+	}
+	return parts
+}
+
+// copySerializedPart provides a function to serialize the part by the given
+// function and copy it to the writer like copyXMLPart. The part will be
+// serialized and copied in chunks through a pipe, so that the whole part
+// will not be kept in memory.
+func (f *File) copySerializedPart(w io.Writer, serialize func(io.Writer) error, partPath string) error {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := io.WriteString(pw, XMLHeader)
+		if err == nil {
+			err = serialize(pw)
+		}
+		pw.CloseWithError(err)
+	}()
+	err := f.copyXMLPart(w, pr, partPath)
+	if err == nil {
+		_, err = io.Copy(ioutil.Discard, pr)
+	}
+	pr.CloseWithError(err)
+	<-done
+	return err
+}
+
+// copyZipPart provides a function to copy the part which has not been read
+// from the reader or the temporary file of the spreadsheet to the writer.
+func (f *File) copyZipPart(w io.Writer, part lazyPart, partPath string) error {
+	rc, err := part.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return f.copyXMLPart(w, rc, partPath)
+}
+
+// contextWriter is a writer which stops writing to the underlying writer
+// when the context is done.
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+// Write implements io.Writer to write to the underlying writer, the error of
+// the context will be returned if the context is done.
+func (cw *contextWriter) Write(p []byte) (int, error) {
+	if err := cw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cw.w.Write(p)
+}
+
+// countWriter is a writer which counts the number of bytes written to the
+// underlying writer.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer to write to the underlying writer and count
+// the written bytes.
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// createZipPart provides a function to add a part to the ZIP archive by
+// given part path and the uncompressed size of the part. The
+// already-compressed media will be stored without compression if the
+// StoreMedia of the options is true.
+func (f *File) createZipPart(zw *zip.Writer, lw *zip64Writer, partPath string, size uint64) (io.Writer, error) {
+	method := zip.Deflate
+	if f.options != nil && f.options.StoreMedia {
+		switch strings.ToLower(filepath.Ext(partPath)) {
+		case ".gif", ".jpeg", ".jpg", ".png":
+			method = zip.Store
+		}
+	}
+	fh := &zip.FileHeader{Name: partPath, Method: method}
+	if size < zip64Threshold {
+		return zw.CreateHeader(fh)
+	}
+	lw.holding = true
+	fi, err := zw.CreateHeader(fh)
+	if err == nil {
+		err = zw.Flush()
+	}
+	if err != nil {
+		lw.held, lw.holding = nil, false
+		return fi, err
+	}
+	return fi, lw.release(fh)
+}
+
+// zip64Threshold is the size of the part from which the local file header of
+// the part will be marked for the ZIP64 extensions.
+var zip64Threshold uint64 = math.MaxUint32
+
+// fileHeaderLen is the length of the fixed fields of the local file header in
+// the ZIP archive.
+const fileHeaderLen = 30
+
+// zip64Version is the version needed to extract the part in the ZIP64 format.
+const zip64Version = 45
+
+// zip64Writer is the writer under the ZIP writer, which sets the version
+// needed to extract in the local file headers of the parts larger than 4 GB
+// to 4.5. The ZIP specification requires that version for the entries which
+// use the ZIP64 extensions, but the ZIP writer only sets it in the central
+// directory. The bytes written to it will be held while creating the local
+// file header of the ZIP64 part.
+type zip64Writer struct {
+	w       io.Writer
+	held    []byte
+	holding bool
+}
+
+// Write implements io.Writer to write to the underlying writer or hold the
+// bytes.
+func (lw *zip64Writer) Write(p []byte) (int, error) {
+	if lw.holding {
+		lw.held = append(lw.held, p...)
+		return len(p), nil
+	}
+	return lw.w.Write(p)
+}
+
+// release provides a function to set the version needed to extract in the
+// local file header at the end of the held bytes by given file header which
+// has been written, and write the held bytes to the underlying writer. The
+// position of the local file header will be calculated by the length of the
+// name and the extra field of the file header, and an error will be returned
+// if the local file header was not found at that position.
+func (lw *zip64Writer) release(fh *zip.FileHeader) error {
+	held := lw.held
+	lw.held, lw.holding = nil, false
+	pos := len(held) - (fileHeaderLen + len(fh.Name) + len(fh.Extra))
+	if pos < 0 || !bytes.HasPrefix(held[pos:], []byte("PK\x03\x04")) ||
+		int(binary.LittleEndian.Uint16(held[pos+26:])) != len(fh.Name) ||
+		int(binary.LittleEndian.Uint16(held[pos+28:])) != len(fh.Extra) {
+		return fmt.Errorf("local file header of the ZIP64 part %s not found", fh.Name)
+	}
+	binary.LittleEndian.PutUint16(held[pos+4:], zip64Version)
+	_, err := lw.w.Write(held)
+	return err
+}
+
+// lazyPartSize returns the uncompressed size of the part which has not been
+// read into memory.
+func lazyPartSize(part lazyPart) uint64 {
+	switch p := part.(type) {
+	case *zip.File:
+		return p.UncompressedSize64
+	case tempFilePart:
+		if info, err := os.Stat(string(p)); err == nil {
+			return uint64(info.Size())
+		}
+	}
+	return 0
+}
+
+// setSaveOptions provides a function to apply the options of saving the
+// spreadsheet, set the conformance class of the workbook and the application
+// properties. The XML parts of the spreadsheet will be formatted here only if
+// the workbook will be signed, otherwise they will be formatted when writing
+// them to the ZIP archive.
+func (f *File) setSaveOptions() error {
+	var conformance string
+	if f.options != nil && f.options.Strict {
+		conformance = "strict"
+	}
+	f.setConformance(conformance)
+	if f.options == nil {
+		return nil
+	}
+	if f.options.PrettyXML && f.options.CompactXML {
+		return errors.New("PrettyXML and CompactXML options can't be used at the same time")
+	}
+	if err := checkCompatibilityProfile(f.options.Compatibility); err != nil {
+		return err
+	}
+	if f.options.Application != "" || f.options.AppVersion != "" {
+		app, err := f.appPropsReader()
 		if err != nil {
-			return buf, err
+			return err
 		}
-		buf.Reset()
-		buf.Write(b)
-		return buf, nil
+		if f.options.Application != "" {
+			app.Application = f.options.Application
+		}
+		if f.options.AppVersion != "" {
+			app.AppVersion = f.options.AppVersion
+		}
+		app.Vt = NameSpaceDocPropsVTypes
+		output, _ := xml.Marshal(app)
+		f.saveFileList("docProps/app.xml", output)
+	}
+	if len(f.signatures) == 0 {
+		return nil
+	}
+	for path := range f.XLSX {
+		var buf bytes.Buffer
+		if err := f.copyXMLPart(&buf, bytes.NewReader(f.readXML(path)), path); err == nil {
+			f.XLSX[path] = buf.Bytes()
+		}
+	}
+	return nil
+}
+
+// setConformance provides a function to set the conformance class attribute
+// of the workbook by given conformance class, the attribute will be removed
+// if the conformance class is empty.
+func (f *File) setConformance(conformance string) {
+	wbPath := f.getWorkbookPath()
+	if f.WorkBook == nil && conformance == "" && !bytes.Contains(f.readXML(wbPath), []byte("conformance=")) {
+		return
 	}
-	return buf, zw.Close()
+	f.workbookReader()
+	if len(f.xmlAttr[wbPath]) == 0 {
+		return
+	}
+	var (
+		attrs []xml.Attr
+		value string
+	)
+	for _, attr := range f.xmlAttr[wbPath] {
+		if attr.Name.Space == "" && attr.Name.Local == "conformance" {
+			value = attr.Value
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	if value == conformance {
+		return
+	}
+	if conformance != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "conformance"}, Value: conformance})
+	}
+	f.xmlAttr[wbPath] = attrs
+	f.workBookWriter()
+}
+
+// copyXMLPart provides a function to copy the part by given part path, the
+// XML part will be rewritten for the compatibility profile, indented or
+// compacted, and converted to the Strict or Transitional namespaces depending
+// on the options of saving the spreadsheet. The parts which are not modelled
+// by the library will be copied as is.
+func (f *File) copyXMLPart(w io.Writer, r io.Reader, partPath string) error {
+	if !isFormattableXMLPart(partPath) || f.isUnknownPart(partPath) {
+		_, err := io.Copy(w, r)
+		return err
+	}
+	r, release := f.compatibleXMLReader(r, partPath)
+	defer release()
+	nw := &namespaceWriter{w: w, convert: namespaceStrictToTransitional}
+	if f.options != nil && f.options.Strict {
+		nw.convert = namespaceTransitionalToStrict
+	}
+	var err error
+	if f.options == nil || !(f.options.PrettyXML || f.options.CompactXML) {
+		_, err = io.Copy(nw, r)
+	} else {
+		err = formatXML(nw, r, f.options.PrettyXML)
+	}
+	if err != nil {
+		return err
+	}
+	return nw.Flush()
+}
+
+// namespaceWriter is a writer which converts the namespaces in the XML
+// document before writing to the underlying writer. The bytes after the end
+// of the last tag will be held until the next writing or flushing, so that
+// the namespaces will not be split.
+type namespaceWriter struct {
+	w       io.Writer
+	convert func([]byte) []byte
+	held    []byte
+}
+
+// Write implements io.Writer to convert and write the bytes before the end
+// of the last tag to the underlying writer.
+func (nw *namespaceWriter) Write(p []byte) (int, error) {
+	nw.held = append(nw.held, p...)
+	if i := bytes.LastIndexByte(nw.held, '>'); i != -1 {
+		if _, err := nw.w.Write(nw.convert(nw.held[:i+1])); err != nil {
+			return 0, err
+		}
+		nw.held = append(nw.held[:0], nw.held[i+1:]...)
+	}
+	return len(p), nil
+}
+
+// Flush provides a function to convert and write the held bytes to the
+// underlying writer.
+func (nw *namespaceWriter) Flush() error {
+	if len(nw.held) == 0 {
+		return nil
+	}
+	_, err := nw.w.Write(nw.convert(nw.held))
+	nw.held = nil
+	return err
+}
+
+// isFormattableXMLPart provides a function to check if the part can be
+// formatted by given part path. The custom XML data parts and the digital
+// signature parts will be kept as is.
+func isFormattableXMLPart(partPath string) bool {
+	switch strings.ToLower(filepath.Ext(partPath)) {
+	case ".xml", ".rels", ".vml":
+	default:
+		return false
+	}
+	if strings.HasPrefix(partPath, "_xmlsignatures/") {
+		return false
+	}
+	return !strings.HasPrefix(partPath, "customXml/item") || strings.HasPrefix(partPath, "customXml/itemProps")
+}
+
+// formatXML provides a function to remove the insignificant whitespace of
+// the XML document from the reader and write it to the writer, the elements
+// will be indented with two spaces if indent is true. The whitespace in the
+// element with xml:space="preserve" and the element which has text content
+// will be kept as is.
+func formatXML(w io.Writer, r io.Reader, indent bool) error {
+	type element struct {
+		preserve, child, text bool
+	}
+	var (
+		buf              bytes.Buffer
+		pending, written bool
+		stack   []element
+		d       = xml.NewDecoder(r)
+	)
+	top := func() element {
+		if len(stack) == 0 {
+			return element{}
+		}
+		return stack[len(stack)-1]
+	}
+	closeTag := func() {
+		if pending {
+			buf.WriteString(">")
+			pending = false
+		}
+	}
+	newLine := func() {
+		if parent := top(); indent && written && !parent.preserve && !parent.text {
+			buf.WriteString("\n" + strings.Repeat("  ", len(stack)))
+		}
+	}
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			closeTag()
+			newLine()
+			parent := top()
+			if len(stack) > 0 {
+				stack[len(stack)-1].child = true
+			}
+			elem := element{preserve: parent.preserve}
+			buf.WriteString("<" + canonicalName(t.Name))
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xml" && attr.Name.Local == "space" {
+					elem.preserve = attr.Value == "preserve"
+				}
+				buf.WriteString(" " + canonicalName(attr.Name) + `="` + escapeCanonicalAttr(attr.Value) + `"`)
+			}
+			stack, pending = append(stack, elem), true
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return fmt.Errorf("unexpected end element %s", canonicalName(t.Name))
+			}
+			elem := top()
+			if stack = stack[:len(stack)-1]; pending {
+				buf.WriteString("/>")
+				pending = false
+				continue
+			}
+			if elem.child && !elem.text {
+				newLine()
+			}
+			buf.WriteString("</" + canonicalName(t.Name) + ">")
+		case xml.CharData:
+			if elem := top(); !elem.preserve && len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+			closeTag()
+			if len(stack) > 0 {
+				stack[len(stack)-1].text = true
+			}
+			buf.WriteString(escapeCanonicalText(string(t)))
+		case xml.ProcInst:
+			closeTag()
+			newLine()
+			buf.WriteString("<?" + t.Target)
+			if len(t.Inst) > 0 {
+				buf.WriteString(" " + string(t.Inst))
+			}
+			buf.WriteString("?>")
+		case xml.Comment:
+			closeTag()
+			newLine()
+			buf.WriteString("<!--" + string(t) + "-->")
+		case xml.Directive:
+			closeTag()
+			newLine()
+			buf.WriteString("<!" + string(t) + ">")
+		}
+		if written = true; buf.Len() >= 1<<16 {
+			if _, err := buf.WriteTo(w); err != nil {
+				return err
+			}
+		}
+	}
+	if len(stack) > 0 {
+		return errors.New("unexpected EOF")
+	}
+	_, err := buf.WriteTo(w)
+	return err
 }
@@ -1,8 +1,18 @@
 package excelize
 
 import (
+	"archive/zip"
 	"bufio"
 	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -46,3 +56,393 @@ func TestWriteTo(t *testing.T) {
 	_, err = f.WriteTo(bufio.NewWriter(&buf))
 	assert.EqualError(t, err, "zip: FileHeader.Name too long")
 }
+
+// readZipPart provides a function to read the part of the ZIP archive by
+// given archive content and part path.
+func readZipPart(t *testing.T, content []byte, part string) string {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	for _, file := range zr.File {
+		if file.Name != part {
+			continue
+		}
+		rc, err := file.Open()
+		assert.NoError(t, err)
+		defer rc.Close()
+		data, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		return string(data)
+	}
+	return ""
+}
+
+func TestSaveOptions(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", " text "))
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"stream"}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveOptions.xlsx"), Options{PrettyXML: true, Application: "Report Generator", AppVersion: "1.0000"}))
+	content, err := ioutil.ReadFile(filepath.Join("test", "TestSaveOptions.xlsx"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(readZipPart(t, content, "_rels/.rels"), "<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"yes\"?>\n<Relationships xmlns=\"http://schemas.openxmlformats.org/package/2006/relationships\">\n  <Relationship Id=\"rId3\" Target=\"docProps/app.xml\""))
+
+	f, err = OpenFile(filepath.Join("test", "TestSaveOptions.xlsx"))
+	assert.NoError(t, err)
+	app, err := f.GetAppProps()
+	assert.NoError(t, err)
+	assert.Equal(t, "Report Generator", app.Application)
+	assert.Equal(t, "1.0000", app.AppVersion)
+	assert.Contains(t, string(f.XLSX["xl/worksheets/sheet1.xml"]), "\n    <row r=\"1\">\n      <c r=\"A1\" t=\"str\">\n        <v>stream</v>\n      </c>\n    </row>\n")
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", " text "))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveOptions.xlsx"), Options{CompactXML: true}))
+	content, err = ioutil.ReadFile(filepath.Join("test", "TestSaveOptions.xlsx"))
+	assert.NoError(t, err)
+	assert.NotContains(t, readZipPart(t, content, "_rels/.rels"), "\n")
+	assert.Contains(t, readZipPart(t, content, "xl/sharedStrings.xml"), `<t xml:space="preserve"> text </t>`)
+
+	f, err = OpenFile(filepath.Join("test", "TestSaveOptions.xlsx"))
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, " text ", val)
+
+	// Test save spreadsheet with conflicting options
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestSaveOptions.xlsx"), Options{PrettyXML: true, CompactXML: true}), "PrettyXML and CompactXML options can't be used at the same time")
+	// Test save spreadsheet with unsupported charset application properties
+	f.XLSX["docProps/app.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestSaveOptions.xlsx"), Options{Application: "Report Generator"}), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestSaveStrict(t *testing.T) {
+	readParts := func(name string) map[string]string {
+		zr, err := zip.OpenReader(name)
+		assert.NoError(t, err)
+		defer zr.Close()
+		parts := make(map[string]string)
+		for _, file := range zr.File {
+			rc, err := file.Open()
+			assert.NoError(t, err)
+			content, err := ioutil.ReadAll(rc)
+			assert.NoError(t, err)
+			assert.NoError(t, rc.Close())
+			parts[file.Name] = string(content)
+		}
+		return parts
+	}
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Strict"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 1))
+	assert.NoError(t, f.AddChart("Sheet1", "D1", `{"type":"col","series":[{"name":"Sheet1!$A$1","values":"Sheet1!$B$1"}]}`))
+	f.NewSheet("Sheet2")
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"stream"}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveStrict.xlsx"), Options{Strict: true}))
+	parts := readParts(filepath.Join("test", "TestSaveStrict.xlsx"))
+	assert.Contains(t, parts["xl/workbook.xml"], `<workbook xmlns="`+StrictNameSpaceSpreadSheet+`"`)
+	assert.Contains(t, parts["xl/workbook.xml"], ` conformance="strict">`)
+	assert.Contains(t, parts["xl/worksheets/sheet2.xml"], `xmlns="`+StrictNameSpaceSpreadSheet+`"`)
+	assert.Contains(t, parts["xl/charts/chart1.xml"], `"http://purl.oclc.org/ooxml/drawingml/chart"`)
+	assert.Contains(t, parts["_rels/.rels"], StrictSourceRelationship+"/extendedProperties")
+	assert.Contains(t, parts["docProps/app.xml"], `"http://purl.oclc.org/ooxml/officeDocument/extendedProperties"`)
+	for name, content := range parts {
+		for _, ns := range strictNamespaces {
+			assert.NotContains(t, content, `"`+ns[1], name)
+		}
+	}
+
+	// Test open the Strict conformant spreadsheet and save it as the
+	// Transitional conformant package
+	f, err = OpenFile(filepath.Join("test", "TestSaveStrict.xlsx"), Options{UnzipXMLSizeLimit: 1})
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"stream"}}, rows)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Strict", val)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveStrict.xlsx")))
+	assert.NoError(t, f.Close())
+	for name, content := range readParts(filepath.Join("test", "TestSaveStrict.xlsx")) {
+		assert.NotContains(t, content, "http://purl.oclc.org/ooxml/", name)
+		assert.NotContains(t, content, "conformance=", name)
+	}
+
+	// Test convert the namespaces with the writer error
+	nw := &namespaceWriter{w: errWriter{}, convert: namespaceTransitionalToStrict}
+	_, err = nw.Write([]byte("<a>"))
+	assert.Error(t, err)
+	nw = &namespaceWriter{w: errWriter{}, convert: namespaceTransitionalToStrict}
+	_, err = nw.Write([]byte("a"))
+	assert.NoError(t, err)
+	assert.Error(t, nw.Flush())
+	assert.Equal(t, `xmlns:c="http://purl.oclc.org/ooxml/drawingml/chartDrawing" xmlns:r="`+StrictSourceRelationship+`"`,
+		string(namespaceTransitionalToStrict([]byte(`xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chartDrawing" xmlns:r="`+SourceRelationship.Value+`"`))))
+}
+
+func TestFormatXML(t *testing.T) {
+	for _, c := range []struct {
+		doc, pretty, compact string
+	}{
+		{
+			doc:     "<?xml version=\"1.0\"?>\n<!DOCTYPE a><a xmlns:x=\"urn:x\">\n <!-- c --> <x:b x:c=\"&quot;\"> </x:b><p>t<i>x</i> &amp;</p>\n<t xml:space=\"preserve\"> <s/> </t></a>",
+			pretty:  "<?xml version=\"1.0\"?>\n<!DOCTYPE a>\n<a xmlns:x=\"urn:x\">\n  <!-- c -->\n  <x:b x:c=\"&quot;\"/>\n  <p>t<i>x</i> &amp;</p>\n  <t xml:space=\"preserve\"> <s/> </t>\n</a>",
+			compact: "<?xml version=\"1.0\"?><!DOCTYPE a><a xmlns:x=\"urn:x\"><!-- c --><x:b x:c=\"&quot;\"/><p>t<i>x</i> &amp;</p><t xml:space=\"preserve\"> <s/> </t></a>",
+		},
+	} {
+		var buf bytes.Buffer
+		assert.NoError(t, formatXML(&buf, strings.NewReader(c.doc), true))
+		assert.Equal(t, c.pretty, buf.String())
+		buf.Reset()
+		assert.NoError(t, formatXML(&buf, strings.NewReader(c.doc), false))
+		assert.Equal(t, c.compact, buf.String())
+	}
+	assert.EqualError(t, formatXML(&bytes.Buffer{}, strings.NewReader("<a>"), true), "unexpected EOF")
+	assert.EqualError(t, formatXML(&bytes.Buffer{}, strings.NewReader("</a>"), true), "unexpected end element a")
+	assert.EqualError(t, formatXML(&bytes.Buffer{}, strings.NewReader("<a"), true), "XML syntax error on line 1: unexpected EOF")
+	assert.False(t, isFormattableXMLPart("xl/media/image1.png"))
+	assert.False(t, isFormattableXMLPart("customXml/item1.xml"))
+	assert.True(t, isFormattableXMLPart("customXml/itemProps1.xml"))
+}
+
+func TestSaveCompressionOptions(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), ""))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	size := buf.Len()
+	f.options = &Options{CompressionLevel: flate.BestCompression, StoreMedia: true}
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	for _, file := range zr.File {
+		if strings.HasPrefix(file.Name, "xl/media/") {
+			assert.Equal(t, zip.Store, file.Method, file.Name)
+			continue
+		}
+		assert.Equal(t, zip.Deflate, file.Method, file.Name)
+	}
+	assert.NotEqual(t, size, buf.Len())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSaveCompressionOptions.xlsx"), Options{CompressionLevel: flate.BestSpeed, StoreMedia: true}))
+	_, err = OpenFile(filepath.Join("test", "TestSaveCompressionOptions.xlsx"))
+	assert.NoError(t, err)
+
+	// Test save spreadsheet with invalid compression level
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestSaveCompressionOptions.xlsx"), Options{CompressionLevel: 10}), "invalid compression level 10")
+	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestSaveCompressionOptions.xlsx"), Options{CompressionLevel: -1}), "invalid compression level -1")
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+func TestWriteToStream(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "stream"))
+	var buf bytes.Buffer
+	n, err := f.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	f, err = OpenReader(&buf)
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "stream", val)
+	// Test write spreadsheet with password
+	f.options = &Options{Password: "password"}
+	buf.Reset()
+	n, err = f.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	_, err = OpenReader(&buf, Options{Password: "password"})
+	assert.NoError(t, err)
+	// Test write spreadsheet with writer error
+	f.options = nil
+	_, err = f.WriteTo(errWriter{})
+	assert.EqualError(t, err, "write error")
+	_, err = f.WriteTo(bufio.NewWriterSize(errWriter{}, 16))
+	assert.EqualError(t, err, "write error")
+	// Test write spreadsheet with invalid compression level
+	f.options = &Options{CompressionLevel: 10}
+	_, err = f.WriteTo(&buf)
+	assert.EqualError(t, err, "invalid compression level 10")
+}
+
+func TestWriteToSerializedParts(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 2000; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{row, fmt.Sprintf("text%d", row)}))
+	}
+	sheet := f.XLSX["xl/worksheets/sheet1.xml"]
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	// Test the worksheet and the shared strings are serialized into the ZIP
+	// archive directly without keeping them in the parts of the spreadsheet
+	assert.Equal(t, sheet, f.XLSX["xl/worksheets/sheet1.xml"])
+	assert.NotContains(t, f.XLSX, "xl/sharedStrings.xml")
+	assert.Contains(t, readZipPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml"), `<c r="A2000"><v>2000</v></c><c r="B2000" t="s"><v>1999</v></c>`)
+	assert.Contains(t, readZipPart(t, buf.Bytes(), "xl/sharedStrings.xml"), `<si><t>text2000</t></si></sst>`)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "B2000")
+	assert.NoError(t, err)
+	assert.Equal(t, "text2000", val)
+
+	// Test serialize the part with error
+	assert.EqualError(t, f.copySerializedPart(ioutil.Discard, func(w io.Writer) error {
+		return errors.New("serialize error")
+	}, "xl/worksheets/sheet1.xml"), "serialize error")
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, f.copySerializedPart(errWriter{}, func(w io.Writer) error {
+		return writeWorksheet(w, ws, nil)
+	}, "xl/worksheets/sheet1.xml"), "write error")
+}
+
+func TestWriteZip64Part(t *testing.T) {
+	f := NewFile()
+	var buf bytes.Buffer
+	lw := &zip64Writer{w: &buf}
+	zw := zip.NewWriter(lw)
+	for _, part := range []struct {
+		name string
+		size uint64
+	}{
+		{"docProps/app.xml", 5},
+		{"xl/worksheets/sheet1.xml", math.MaxUint32},
+	} {
+		fi, err := f.createZipPart(zw, lw, part.name, part.size)
+		assert.NoError(t, err)
+		_, err = fi.Write([]byte("<a/>"))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	for i, version := range []uint16{20, 45} {
+		offset, err := zr.File[i].DataOffset()
+		assert.NoError(t, err)
+		header := buf.Bytes()[offset-int64(fileHeaderLen+len(zr.File[i].Name)):]
+		assert.Equal(t, "PK\x03\x04", string(header[:4]))
+		assert.Equal(t, version, binary.LittleEndian.Uint16(header[4:]))
+		content, err := readFile(zr.File[i])
+		assert.NoError(t, err)
+		assert.Equal(t, "<a/>", string(content))
+	}
+	assert.Equal(t, uint64(0), lazyPartSize(tempFilePart(filepath.Join("test", "nonexistent.xml"))))
+	// Test create part with writer error
+	lw = &zip64Writer{w: errWriter{}}
+	_, err = f.createZipPart(zip.NewWriter(lw), lw, "xl/worksheets/sheet1.xml", math.MaxUint32)
+	assert.EqualError(t, err, "write error")
+
+	// Test set the version in the local file header with the extra field and
+	// the non-ASCII name
+	buf.Reset()
+	lw = &zip64Writer{w: &buf}
+	zw = zip.NewWriter(lw)
+	lw.holding = true
+	fh := &zip.FileHeader{Name: "xl/media/画像1.png", Method: zip.Store, Extra: []byte{0xfe, 0xca, 2, 0, 1, 2}}
+	_, err = zw.CreateHeader(fh)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Flush())
+	assert.NoError(t, lw.release(fh))
+	assert.NoError(t, zw.Close())
+	assert.Equal(t, "PK\x03\x04", string(buf.Bytes()[:4]))
+	assert.Equal(t, uint16(zip64Version), binary.LittleEndian.Uint16(buf.Bytes()[4:]))
+	assert.Equal(t, fh.Name, string(buf.Bytes()[fileHeaderLen:fileHeaderLen+len(fh.Name)]))
+
+	// Test set the version without the local file header
+	lw = &zip64Writer{w: &buf, holding: true}
+	_, err = lw.Write([]byte("PK\x03\x04"))
+	assert.NoError(t, err)
+	assert.EqualError(t, lw.release(fh), "local file header of the ZIP64 part xl/media/画像1.png not found")
+	assert.False(t, lw.holding)
+	assert.Nil(t, lw.held)
+}
+
+func TestWriteZip64SerializedPart(t *testing.T) {
+	defer func(threshold uint64) { zip64Threshold = threshold }(zip64Threshold)
+	zip64Threshold = 256 << 10
+	f := NewFile()
+	for row := 1; row <= 2000; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{row, fmt.Sprintf("text%d", row)}))
+	}
+	f.NewSheet("Sheet2")
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	for row := 1; row <= 10000; row++ {
+		assert.NoError(t, sw.SetRow(fmt.Sprintf("A%d", row), []interface{}{row, "streamed text"}))
+	}
+	assert.NoError(t, sw.Flush())
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, worksheetSizeLimit(ws) > uint64(len(marshalWorksheet(ws))))
+	sst, err := f.sharedStringsReader()
+	assert.NoError(t, err)
+	assert.True(t, sharedStringsSizeLimit(sst) > uint64(len(marshalSharedStrings(sst))))
+
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	versions := make(map[string]uint16)
+	content := buf.Bytes()
+	for pos := bytes.Index(content, []byte("PK\x03\x04")); pos != -1 && pos+fileHeaderLen < len(content); {
+		nameLen := int(binary.LittleEndian.Uint16(content[pos+26:]))
+		if end := pos + fileHeaderLen + nameLen; end <= len(content) {
+			versions[string(content[pos+fileHeaderLen:end])] = binary.LittleEndian.Uint16(content[pos+4:])
+		}
+		next := bytes.Index(content[pos+1:], []byte("PK\x03\x04"))
+		if next == -1 {
+			break
+		}
+		pos += next + 1
+	}
+	for part, version := range map[string]uint16{
+		"xl/worksheets/sheet1.xml": zip64Version,
+		"xl/worksheets/sheet2.xml": zip64Version,
+		"xl/sharedStrings.xml":     zip64Version,
+		"xl/styles.xml":            20,
+		"[Content_Types].xml":      20,
+	} {
+		assert.Equal(t, version, versions[part], part)
+	}
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "B2000")
+	assert.NoError(t, err)
+	assert.Equal(t, "text2000", val)
+	val, err = f.GetCellValue("Sheet2", "B10000")
+	assert.NoError(t, err)
+	assert.Equal(t, "streamed text", val)
+
+	// Test calculate the size of the cells and the string items which will be
+	// serialized by encoding/xml
+	ws.SheetData.Row[0].C[0].IS = &xlsxSI{T: &xlsxT{Val: "inline"}}
+	ws.SheetData.Row[0].C[0].F = &xlsxF{Content: "=1+1"}
+	assert.True(t, worksheetSizeLimit(ws) > uint64(len(marshalWorksheet(ws))))
+	sst = &xlsxSST{SI: []xlsxSI{{R: []xlsxR{{T: &xlsxT{Val: "rich"}}}}}}
+	assert.True(t, sharedStringsSizeLimit(sst) > uint64(len(marshalSharedStrings(sst))))
+}
+
+func TestSaveContext(t *testing.T) {
+	f := NewFile()
+	assert.EqualError(t, f.SaveContext(context.Background()), "no path defined for file, consider File.WriteTo or File.Write")
+	assert.NoError(t, f.SaveAsContext(context.Background(), filepath.Join("test", "TestSaveContext.xlsx")))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "context"))
+	f.Path = filepath.Join("test", "TestSaveContext.xlsx")
+	assert.NoError(t, f.SaveContext(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.EqualError(t, f.SaveContext(ctx), context.Canceled.Error())
+	assert.EqualError(t, f.SaveAsContext(ctx, filepath.Join("test", "TestSaveContext.xlsx"), Options{Password: "password"}), context.Canceled.Error())
+	assert.EqualError(t, f.writeToZip(ctx, &bytes.Buffer{}), context.Canceled.Error())
+	_, err := (&contextWriter{ctx: ctx, w: &bytes.Buffer{}}).Write([]byte("a"))
+	assert.EqualError(t, err, context.Canceled.Error())
+}
@@ -0,0 +1,59 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+//go:build go1.16
+// +build go1.16
+
+package excelize
+
+import (
+	"context"
+	"io/fs"
+)
+
+// OpenFS take the file system and the name of an spreadsheet file in it and
+// returns a populated spreadsheet file struct for it, such as the template
+// workbooks embedded in the binary by embed.FS, without writing them to the
+// temporary files. The path of the opened spreadsheet is not set, so the
+// spreadsheet should be saved by SaveAs or WriteTo. This function requires Go
+// version 1.16 or later. For example, open the template embedded in the
+// binary:
+//
+//    //go:embed templates/*.xlsx
+//    var templates embed.FS
+//
+//    f, err := excelize.OpenFS(templates, "templates/Report.xlsx")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err = f.SetCellValue("Sheet1", "B2", "Monthly Report"); err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    err = f.SaveAs("Report.xlsx")
+//
+func OpenFS(fsys fs.FS, name string, opt ...Options) (*File, error) {
+	return OpenFSContext(context.Background(), fsys, name, opt...)
+}
+
+// OpenFSContext take the file system and the name of an spreadsheet file in
+// it and returns a populated spreadsheet file struct for it like OpenFS, the
+// opening will be stopped and the error of the context will be returned if
+// the context is canceled or its deadline is exceeded.
+func OpenFSContext(ctx context.Context, fsys fs.FS, name string, opt ...Options) (*File, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return openReader(ctx, file, opt...)
+}
@@ -0,0 +1,47 @@
+//go:build go1.16
+// +build go1.16
+
+package excelize
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenFS(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Template"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	fsys := fstest.MapFS{
+		"templates/Book1.xlsx": &fstest.MapFile{Data: buf.Bytes()},
+		"templates/Book2.xlsx": &fstest.MapFile{Data: []byte("invalid")},
+	}
+
+	f, err = OpenFS(fsys, "templates/Book1.xlsx")
+	assert.NoError(t, err)
+	assert.Empty(t, f.Path)
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Template", value)
+	assert.EqualError(t, f.Save(), "no path defined for file, consider File.WriteTo or File.Write")
+
+	// Test open the spreadsheet with the options, invalid file and not exists file
+	_, err = OpenFS(fsys, "templates/Book1.xlsx", Options{MaxMemory: 1})
+	assert.IsType(t, ErrMaxMemoryExceeded{}, err)
+	_, err = OpenFS(fsys, "templates/Book2.xlsx")
+	assert.EqualError(t, err, "zip: not a valid zip file")
+	_, err = OpenFS(fsys, "templates/Book3.xlsx")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	// Test open the spreadsheet with canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = OpenFSContext(ctx, fsys, "templates/Book1.xlsx")
+	assert.EqualError(t, err, context.Canceled.Error())
+}
@@ -0,0 +1,321 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// indexedColors defined the legacy indexed color palette used by the indexed
+// attribute of the colors in the cell styles.
+var indexedColors = []string{
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"800000", "008000", "000080", "808000", "800080", "008080", "C0C0C0", "808080",
+	"9999FF", "993366", "FFFFCC", "CCFFFF", "660066", "FF8080", "0066CC", "CCCCFF",
+	"000080", "FF00FF", "FFFF00", "00FFFF", "800080", "800000", "008080", "0000FF",
+	"00CCFF", "CCFFFF", "CCFFCC", "FFFF99", "99CCFF", "FF99CC", "CC99FF", "FFCC99",
+	"3366FF", "33CCCC", "99CC00", "FFCC00", "FF9900", "FF6600", "666699", "969696",
+	"003366", "339966", "003300", "333300", "993300", "993366", "333399", "333333",
+}
+
+// htmlBorderStyles defined the CSS border styles of the cell border styles.
+var htmlBorderStyles = map[string]string{
+	"thin":             "1px solid",
+	"medium":           "2px solid",
+	"thick":            "3px solid",
+	"dashed":           "1px dashed",
+	"dotted":           "1px dotted",
+	"double":           "3px double",
+	"hair":             "1px dotted",
+	"mediumDashed":     "2px dashed",
+	"dashDot":          "1px dashed",
+	"mediumDashDot":    "2px dashed",
+	"dashDotDot":       "1px dotted",
+	"mediumDashDotDot": "2px dotted",
+	"slantDashDot":     "2px dashed",
+}
+
+// HTMLOptions directly maps the settings of exporting the worksheet to HTML.
+//
+// Range specifies the cell or range to be exported, such as "B2:D10". The
+// used range of the worksheet starting from the cell A1 will be exported if
+// it's empty.
+//
+// RawCellValue specifies writing the raw values of the cells instead of the
+// values formatted by the number format of the cells.
+type HTMLOptions struct {
+	Range        string
+	RawCellValue bool
+}
+
+// ExportHTML provides a function to write the worksheet to the writer as a
+// HTML table by given worksheet name and optional settings. The fills,
+// fonts, borders and alignment of the cell styles are written as the inline
+// styles of the table cells, and the merged cells are written as the cells
+// with colspan and rowspan. For example, export the range A1:D10 on Sheet1
+// for embedding into an email:
+//
+//    var buf bytes.Buffer
+//    if err := f.ExportHTML("Sheet1", &buf, excelize.HTMLOptions{Range: "A1:D10"}); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) ExportHTML(sheet string, w io.Writer, opts ...HTMLOptions) error {
+	var options HTMLOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	cells, err := f.getExportCells(ws, options.RawCellValue)
+	if err != nil {
+		return err
+	}
+	area, err := getExportArea(options.Range, cells, true)
+	if err != nil {
+		return err
+	}
+	area, spans, covered, err := getExportMergeCells(ws, cells, area, options.Range == "")
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(w)
+	styles := map[int]string{}
+	getStyle := func(styleID int) string {
+		style, ok := styles[styleID]
+		if !ok {
+			style = f.getHTMLStyle(styleID)
+			styles[styleID] = style
+		}
+		return style
+	}
+	fmt.Fprintf(buf, `<table style="border-collapse:collapse;%s">`, getStyle(0))
+	if area == nil {
+		area = []int{1, 1, 0, 0}
+	}
+	for row := area[1]; row <= area[3]; row++ {
+		buf.WriteString("<tr>")
+		for col := area[0]; col <= area[2]; col++ {
+			cell := [2]int{col, row}
+			if covered[cell] {
+				continue
+			}
+			buf.WriteString("<td")
+			if span, ok := spans[cell]; ok {
+				if span[0] > 1 {
+					fmt.Fprintf(buf, ` colspan="%d"`, span[0])
+				}
+				if span[1] > 1 {
+					fmt.Fprintf(buf, ` rowspan="%d"`, span[1])
+				}
+			}
+			if styleID := cells[cell].StyleID; styleID != 0 {
+				if style := getStyle(styleID); style != "" {
+					fmt.Fprintf(buf, ` style="%s"`, style)
+				}
+			}
+			buf.WriteString(">")
+			buf.WriteString(strings.Replace(html.EscapeString(cells[cell].Value), "\n", "<br>", -1))
+			buf.WriteString("</td>")
+		}
+		buf.WriteString("</tr>")
+	}
+	buf.WriteString("</table>")
+	return buf.Flush()
+}
+
+// ExportClipboardHTML provides a function to write the worksheet to the
+// writer in the HTML clipboard format by given worksheet name and optional
+// settings, which could be placed on the clipboard as the "HTML Format" data
+// to paste the cells with the styles into the spreadsheet, email or document
+// applications. The HTML table is generated with the same rules of the
+// ExportHTML, and wrapped by the description header with the byte offsets of
+// the HTML and the fragment. For example, export the range A1:D10 on Sheet1
+// for the clipboard:
+//
+//    var buf bytes.Buffer
+//    if err := f.ExportClipboardHTML("Sheet1", &buf, excelize.HTMLOptions{Range: "A1:D10"}); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) ExportClipboardHTML(sheet string, w io.Writer, opts ...HTMLOptions) error {
+	var fragment bytes.Buffer
+	if err := f.ExportHTML(sheet, &fragment, opts...); err != nil {
+		return err
+	}
+	const (
+		header = "Version:0.9\r\nStartHTML:%010d\r\nEndHTML:%010d\r\nStartFragment:%010d\r\nEndFragment:%010d\r\n"
+		prefix = `<html><head><meta http-equiv="Content-Type" content="text/html; charset=utf-8"></head><body><!--StartFragment-->`
+		suffix = "<!--EndFragment--></body></html>"
+	)
+	startHTML := len(fmt.Sprintf(header, 0, 0, 0, 0))
+	startFragment := startHTML + len(prefix)
+	endFragment := startFragment + fragment.Len()
+	endHTML := endFragment + len(suffix)
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, header, startHTML, endHTML, startFragment, endFragment)
+	buf.WriteString(prefix)
+	buf.Write(fragment.Bytes())
+	buf.WriteString(suffix)
+	return buf.Flush()
+}
+
+// getHTMLStyle provides a function to convert the fill, font, border and
+// alignment of the cell style to the CSS declarations by given style ID.
+func (f *File) getHTMLStyle(styleID int) string {
+	s, _ := f.stylesReader()
+	if s.CellXfs == nil || styleID < 0 || styleID >= len(s.CellXfs.Xf) {
+		return ""
+	}
+	var (
+		xf    = s.CellXfs.Xf[styleID]
+		style []string
+	)
+	if xf.FontID != nil && s.Fonts != nil && *xf.FontID < len(s.Fonts.Font) {
+		style = append(style, f.getHTMLFontStyle(s.Fonts.Font[*xf.FontID])...)
+	}
+	if xf.FillID != nil && s.Fills != nil && *xf.FillID < len(s.Fills.Fill) {
+		fill := s.Fills.Fill[*xf.FillID]
+		var color string
+		if fill.PatternFill != nil && fill.PatternFill.PatternType != "" && fill.PatternFill.PatternType != "none" {
+			color = f.getHexColor(&fill.PatternFill.FgColor)
+		}
+		if fill.GradientFill != nil && len(fill.GradientFill.Stop) > 0 {
+			color = f.getHexColor(&fill.GradientFill.Stop[0].Color)
+		}
+		if color != "" {
+			style = append(style, "background-color:"+color)
+		}
+	}
+	if xf.BorderID != nil && s.Borders != nil && *xf.BorderID < len(s.Borders.Border) {
+		border := s.Borders.Border[*xf.BorderID]
+		for _, line := range []struct {
+			side string
+			line xlsxLine
+		}{
+			{"left", border.Left}, {"right", border.Right}, {"top", border.Top}, {"bottom", border.Bottom},
+		} {
+			css, ok := htmlBorderStyles[line.line.Style]
+			if !ok {
+				continue
+			}
+			color := f.getHexColor(line.line.Color)
+			if color == "" {
+				color = "#000000"
+			}
+			style = append(style, fmt.Sprintf("border-%s:%s %s", line.side, css, color))
+		}
+	}
+	if xf.Alignment != nil {
+		style = append(style, getHTMLAlignmentStyle(xf.Alignment)...)
+	}
+	return strings.Join(style, ";")
+}
+
+// getHTMLFontStyle provides a function to convert the font of the cell style
+// to the CSS declarations.
+func (f *File) getHTMLFontStyle(font *xlsxFont) []string {
+	var style, decoration []string
+	if font.Name != nil && font.Name.Val != nil {
+		style = append(style, fmt.Sprintf("font-family:'%s'", strings.Replace(*font.Name.Val, "'", "\\'", -1)))
+	}
+	if font.Sz != nil && font.Sz.Val != nil {
+		style = append(style, "font-size:"+strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64)+"pt")
+	}
+	if font.B != nil && *font.B {
+		style = append(style, "font-weight:bold")
+	}
+	if font.I != nil && *font.I {
+		style = append(style, "font-style:italic")
+	}
+	if font.U != nil && (font.U.Val == nil || *font.U.Val != "none") {
+		decoration = append(decoration, "underline")
+	}
+	if font.Strike != nil && *font.Strike {
+		decoration = append(decoration, "line-through")
+	}
+	if len(decoration) > 0 {
+		style = append(style, "text-decoration:"+strings.Join(decoration, " "))
+	}
+	if color := f.getHexColor(font.Color); color != "" {
+		style = append(style, "color:"+color)
+	}
+	return style
+}
+
+// getHTMLAlignmentStyle provides a function to convert the alignment of the
+// cell style to the CSS declarations.
+func getHTMLAlignmentStyle(alignment *xlsxAlignment) []string {
+	var style []string
+	if align, ok := map[string]string{
+		"left":             "left",
+		"center":           "center",
+		"right":            "right",
+		"fill":             "left",
+		"justify":          "justify",
+		"centerContinuous": "center",
+		"distributed":      "justify",
+	}[alignment.Horizontal]; ok {
+		style = append(style, "text-align:"+align)
+	}
+	if align, ok := map[string]string{
+		"top":         "top",
+		"center":      "middle",
+		"bottom":      "bottom",
+		"justify":     "middle",
+		"distributed": "middle",
+	}[alignment.Vertical]; ok {
+		style = append(style, "vertical-align:"+align)
+	}
+	if alignment.Indent > 0 {
+		style = append(style, fmt.Sprintf("padding-left:%dpx", alignment.Indent*9))
+	}
+	if alignment.WrapText {
+		style = append(style, "white-space:pre-wrap")
+	}
+	return style
+}
+
+// getHexColor provides a function to convert the color of the cell style to
+// the #RRGGBB format, the RGB, theme and indexed colors are supported.
+func (f *File) getHexColor(color *xlsxColor) string {
+	if color == nil || color.Auto {
+		return ""
+	}
+	var rgb string
+	switch {
+	case len(color.RGB) == 8:
+		rgb = color.RGB[2:]
+	case len(color.RGB) == 6:
+		rgb = color.RGB
+	case color.Theme != nil:
+		themeColor, err := f.GetThemeColor(*color.Theme, color.Tint)
+		if err != nil {
+			return ""
+		}
+		return "#" + themeColor[2:]
+	case color.Indexed > 0 && color.Indexed < len(indexedColors):
+		rgb = indexedColors[color.Indexed]
+	default:
+		return ""
+	}
+	return "#" + ThemeColor(strings.ToUpper(rgb), color.Tint)[2:]
+}
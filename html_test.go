@@ -0,0 +1,87 @@
+package excelize
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportHTML(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Note", "A2": "<Foo>", "B2": "line\nbreak", "A4": 1.5,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	style, err := f.NewStyle(`{"fill":{"type":"pattern","color":["#FFFF00"],"pattern":1},"font":{"bold":true,"italic":true,"underline":"single","strike":true,"color":"#FF0000","family":"Arial","size":12},"border":[{"type":"left","color":"0000FF","style":1},{"type":"bottom","style":6}],"alignment":{"horizontal":"center","vertical":"center","indent":1,"wrap_text":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B1", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "A3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "B3", "C4"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportHTML("Sheet1", &buf))
+	cellStyle := "font-family:'Arial';font-size:12pt;font-weight:bold;font-style:italic;text-decoration:underline line-through;color:#FF0000;background-color:#FFFF00;border-left:1px solid #0000FF;border-bottom:3px double #000000;text-align:center;vertical-align:middle;padding-left:9px;white-space:pre-wrap"
+	assert.Equal(t, `<table style="border-collapse:collapse;font-family:'Calibri';font-size:11pt;color:#000000">`+
+		`<tr><td style="`+cellStyle+`">Name</td><td style="`+cellStyle+`">Note</td><td></td></tr>`+
+		`<tr><td rowspan="2">&lt;Foo&gt;</td><td>line<br>break</td><td></td></tr>`+
+		`<tr><td colspan="2" rowspan="2"></td></tr>`+
+		`<tr><td>1.5</td></tr></table>`, buf.String())
+
+	// Test export the range which partially covers the merged cells
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet1", &buf, HTMLOptions{Range: "B4:A3"}))
+	assert.Equal(t, `<table style="border-collapse:collapse;font-family:'Calibri';font-size:11pt;color:#000000">`+
+		`<tr><td>&lt;Foo&gt;</td><td rowspan="2"></td></tr><tr><td>1.5</td></tr></table>`, buf.String())
+
+	// Test export the empty worksheet
+	f.NewSheet("Sheet2")
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet2", &buf))
+	assert.Equal(t, `<table style="border-collapse:collapse;font-family:'Calibri';font-size:11pt;color:#000000"></table>`, buf.String())
+
+	// Test export with invalid options
+	assert.EqualError(t, f.ExportHTML("SheetN", &buf), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ExportHTML("Sheet1", &buf, HTMLOptions{Range: "A"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
+func TestExportClipboardHTML(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Name"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "Value"))
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportClipboardHTML("Sheet1", &buf, HTMLOptions{Range: "A1:B1"}))
+	var startHTML, endHTML, startFragment, endFragment int
+	_, err := fmt.Sscanf(buf.String(), "Version:0.9\r\nStartHTML:%d\r\nEndHTML:%d\r\nStartFragment:%d\r\nEndFragment:%d\r\n",
+		&startHTML, &endHTML, &startFragment, &endFragment)
+	assert.NoError(t, err)
+	data := buf.Bytes()
+	assert.Equal(t, len(data), endHTML)
+	assert.Equal(t, "<html>", string(data[startHTML:startHTML+6]))
+	assert.Equal(t, `<table style="border-collapse:collapse;font-family:'Calibri';font-size:11pt;color:#000000">`+
+		`<tr><td>Name</td><td>Value</td></tr></table>`, string(data[startFragment:endFragment]))
+	assert.Equal(t, "<!--StartFragment-->", string(data[startFragment-20:startFragment]))
+	assert.Equal(t, "<!--EndFragment-->", string(data[endFragment:endFragment+18]))
+
+	// Test export the clipboard HTML with not exist worksheet
+	assert.EqualError(t, f.ExportClipboardHTML("SheetN", &buf), "sheet SheetN is not exist")
+}
+
+func TestGetHexColor(t *testing.T) {
+	f := NewFile()
+	assert.Equal(t, "", f.getHexColor(nil))
+	assert.Equal(t, "", f.getHexColor(&xlsxColor{Auto: true}))
+	assert.Equal(t, "", f.getHexColor(&xlsxColor{}))
+	assert.Equal(t, "#00FF00", f.getHexColor(&xlsxColor{RGB: "FF00FF00"}))
+	assert.Equal(t, "#00FF00", f.getHexColor(&xlsxColor{RGB: "00ff00"}))
+	assert.Equal(t, "#5B9BD5", f.getHexColor(&xlsxColor{Theme: intPtr(4)}))
+	assert.Equal(t, "", f.getHexColor(&xlsxColor{Theme: intPtr(12)}))
+	assert.Equal(t, "#800000", f.getHexColor(&xlsxColor{Indexed: 16}))
+	assert.Equal(t, "", f.getHexColor(&xlsxColor{Indexed: 64}))
+	assert.Equal(t, 64, len(indexedColors))
+	styles, err := f.stylesReader()
+	assert.NoError(t, err)
+	assert.Equal(t, "", f.getHTMLStyle(len(styles.CellXfs.Xf)))
+}
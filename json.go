@@ -0,0 +1,418 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONOptions directly maps the settings of exporting the worksheet to JSON.
+//
+// Range specifies the range to be exported, such as "A1:D10", the first row
+// of the range is used as the header row. The used range of the worksheet
+// starting from the cell A1 will be exported if it's empty.
+//
+// Table specifies the name of the table on the worksheet to be exported, the
+// names of the table columns will be used as the keys and the totals row of
+// the table will be skipped. The Range will be ignored if it's specified.
+//
+// TimeLayout specifies the layout for formatting the values of the cells
+// with date or time number format, time.RFC3339 will be used if it's empty.
+type JSONOptions struct {
+	Range      string
+	Table      string
+	TimeLayout string
+}
+
+// ExportJSON provides a function to write the rows of the worksheet to the
+// writer as a JSON array of objects keyed by the header row by given
+// worksheet name and optional settings. The numeric, boolean and string
+// cell values are written as the JSON number, boolean and string, the
+// values of the cells with date or time number format are written as the
+// formatted time string, and the empty cells are written as null. The
+// column name will be used as the key if the header cell is empty, and the
+// rows without any value will be skipped. For example, export the table
+// named "Sales" on Sheet1:
+//
+//    var buf bytes.Buffer
+//    if err := f.ExportJSON("Sheet1", &buf, excelize.JSONOptions{Table: "Sales"}); err != nil {
+//        fmt.Println(err)
+//    }
+//
+// Data rows will be exported as:
+//
+//    [{"Region":"East","Date":"2021-01-01T00:00:00Z","Amount":1200.5,"Paid":true}]
+//
+func (f *File) ExportJSON(sheet string, w io.Writer, opts ...JSONOptions) error {
+	var options JSONOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if options.TimeLayout == "" {
+		options.TimeLayout = time.RFC3339
+	}
+	cells, area, names, err := f.getExportRecords(sheet, options.Range, options.Table)
+	if err != nil {
+		return err
+	}
+	if area == nil {
+		_, err = io.WriteString(w, "[]")
+		return err
+	}
+	keys := make([][]byte, len(names))
+	for i, name := range names {
+		if keys[i], err = json.Marshal(name); err != nil {
+			return err
+		}
+	}
+	buf := bufio.NewWriter(w)
+	buf.WriteString("[")
+	var written bool
+	for row := area[1]; row <= area[3]; row++ {
+		values, empty := make([]interface{}, len(keys)), true
+		for i := range keys {
+			if values[i] = f.getJSONValue(cells[[2]int{area[0] + i, row}], options.TimeLayout); values[i] != nil {
+				empty = false
+			}
+		}
+		if empty {
+			continue
+		}
+		if written {
+			buf.WriteString(",")
+		}
+		buf.WriteString("{")
+		for i, value := range values {
+			val, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.Write(keys[i])
+			buf.WriteString(":")
+			buf.Write(val)
+		}
+		buf.WriteString("}")
+		written = true
+	}
+	buf.WriteString("]")
+	return buf.Flush()
+}
+
+// getExportRecords provides a function to get the cells, the coordinates of
+// the data rows and the unique names of the columns to be exported as the
+// records by given worksheet name, range reference or table name. The first
+// row of the range is used as the header row, the column name will be used
+// if the header cell is empty, and the duplicate names will be suffixed with
+// the sequence number. The coordinates will be nil if there are no cells in
+// the used range of the worksheet.
+func (f *File) getExportRecords(sheet, ref, table string) (map[[2]int]exportCell, []int, []string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cells, err := f.getExportCells(ws, true)
+	if err != nil {
+		return cells, nil, nil, err
+	}
+	var (
+		area   []int
+		header []string
+	)
+	if table != "" {
+		tableSheet, _, _, t, err := f.findTable(table)
+		if err != nil {
+			return cells, nil, nil, err
+		}
+		if !strings.EqualFold(tableSheet, sheet) {
+			return cells, nil, nil, fmt.Errorf("table %s is not on the worksheet %s", table, sheet)
+		}
+		if area, err = rangeRefToCoordinates(t.Ref); err != nil {
+			return cells, nil, nil, err
+		}
+		if t.TableColumns != nil {
+			for _, col := range t.TableColumns.TableColumn {
+				header = append(header, col.Name)
+			}
+		}
+		if t.HeaderRowCount == nil || *t.HeaderRowCount > 0 {
+			area[1]++
+		}
+		area[3] -= t.TotalsRowCount
+	} else {
+		if area, err = getExportArea(ref, cells, false); err != nil || area == nil {
+			return cells, nil, nil, err
+		}
+		for col := area[0]; col <= area[2]; col++ {
+			cell := cells[[2]int{col, area[1]}]
+			header = append(header, f.formattedValue(cell.StyleID, cell.Value))
+		}
+		area[1]++
+	}
+	names, seen := make([]string, area[2]-area[0]+1), map[string]int{}
+	for i := range names {
+		name, _ := ColumnNumberToName(area[0] + i)
+		if i < len(header) && header[i] != "" {
+			name = header[i]
+		}
+		if seen[name]++; seen[name] > 1 {
+			name += "_" + strconv.Itoa(seen[name])
+		}
+		names[i] = name
+	}
+	return cells, area, names, err
+}
+
+// getRecordValue provides a function to convert the raw value of the cell to
+// the typed value, the value will be one of the bool, string, int64, float64
+// and time.Time, and nil will be returned for the empty cell.
+func (f *File) getRecordValue(cell exportCell) interface{} {
+	if cell.Value == "" {
+		return nil
+	}
+	switch cell.Type {
+	case "b":
+		return cell.Value == "1"
+	case "s", "str", "inlineStr", "e":
+		return cell.Value
+	}
+	num, err := strconv.ParseFloat(cell.Value, 64)
+	if err != nil {
+		return cell.Value
+	}
+	if f.isDateStyle(cell.StyleID) {
+		if t, err := ExcelDateToTime(num, f.date1904()); err == nil {
+			return t
+		}
+	}
+	if i, err := strconv.ParseInt(cell.Value, 10, 64); err == nil {
+		return i
+	}
+	return num
+}
+
+// getJSONValue provides a function to convert the raw value of the cell to
+// the typed value for the JSON by given layout of the time values.
+func (f *File) getJSONValue(cell exportCell, layout string) interface{} {
+	value := f.getRecordValue(cell)
+	if t, ok := value.(time.Time); ok {
+		return t.Format(layout)
+	}
+	return value
+}
+
+// ImportJSONOptions directly maps the settings of importing the JSON data to
+// the worksheet.
+//
+// Columns specifies the keys of the objects to be imported and the order of
+// the columns. The keys will be imported in the order of first appearance
+// in the JSON array if it's empty, and the keys of each map will be sorted
+// for the []map[string]interface{} data.
+//
+// TimeLayout specifies the layout for parsing the string values as the date
+// and time values, the string values will be kept as is if it's empty.
+//
+// Table specifies creating a table over the imported data with the format
+// settings given by the TableFormat, the format is same as the AddTable.
+type ImportJSONOptions struct {
+	Columns     []string
+	TimeLayout  string
+	Table       bool
+	TableFormat string
+}
+
+// ImportJSON provides a function to write the JSON array of objects or the
+// slice of maps to the worksheet by given worksheet name, the top-left cell
+// of the data, the data and optional settings. The keys of the objects are
+// written as the header row, and each object is written as a data row. The
+// numeric, boolean and string values are written as the cell values with the
+// same type, the date and time values are written with the date or date
+// time number format, the nested objects and arrays are written as the JSON
+// text, and the null values are skipped. For example, import the JSON array
+// to the cell A1 on Sheet1 and create a table over the result:
+//
+//    data := json.RawMessage(`[{"Region":"East","Date":"2021-01-01","Amount":1200.5}]`)
+//    err := f.ImportJSON("Sheet1", "A1", data, excelize.ImportJSONOptions{
+//        TimeLayout:  "2006-01-02",
+//        Table:       true,
+//        TableFormat: `{"table_name":"Sales","table_style":"TableStyleMedium2"}`,
+//    })
+//
+func (f *File) ImportJSON(sheet, axis string, data interface{}, opts ...ImportJSONOptions) error {
+	var options ImportJSONOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	col, row, err := CellNameToCoordinates(axis)
+	if err != nil {
+		return err
+	}
+	var (
+		keys []string
+		rows []map[string]interface{}
+	)
+	switch v := data.(type) {
+	case json.RawMessage:
+		if keys, rows, err = parseJSONRows(v); err != nil {
+			return err
+		}
+	case []map[string]interface{}:
+		seen := map[string]bool{}
+		for _, r := range v {
+			var rowKeys []string
+			for key := range r {
+				rowKeys = append(rowKeys, key)
+			}
+			sort.Strings(rowKeys)
+			for _, key := range rowKeys {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+		rows = v
+	default:
+		return fmt.Errorf("unsupported data type %T", data)
+	}
+	if len(options.Columns) > 0 {
+		keys = options.Columns
+	}
+	if len(keys) == 0 {
+		return err
+	}
+	for i, key := range keys {
+		cell, err := CoordinatesToCellName(col+i, row)
+		if err != nil {
+			return err
+		}
+		if err = f.SetCellStr(sheet, cell, key); err != nil {
+			return err
+		}
+	}
+	for r, values := range rows {
+		for i, key := range keys {
+			cell, err := CoordinatesToCellName(col+i, row+r+1)
+			if err != nil {
+				return err
+			}
+			if err = f.setJSONValue(sheet, cell, values[key], options.TimeLayout); err != nil {
+				return err
+			}
+		}
+	}
+	if !options.Table {
+		return err
+	}
+	vcell, err := CoordinatesToCellName(col+len(keys)-1, row+len(rows))
+	if err != nil {
+		return err
+	}
+	return f.AddTable(sheet, axis, vcell, options.TableFormat)
+}
+
+// parseJSONRows provides a function to parse the JSON array of objects to
+// the keys in the order of first appearance and the rows.
+func parseJSONRows(data []byte) ([]string, []map[string]interface{}, error) {
+	var (
+		keys []string
+		rows []map[string]interface{}
+		seen = map[string]bool{}
+		dec  = json.NewDecoder(bytes.NewReader(data))
+		err  = errors.New("JSON array of objects expected")
+	)
+	dec.UseNumber()
+	if token, _ := dec.Token(); token != json.Delim('[') {
+		return keys, rows, err
+	}
+	for dec.More() {
+		if token, _ := dec.Token(); token != json.Delim('{') {
+			return keys, rows, err
+		}
+		row := map[string]interface{}{}
+		for dec.More() {
+			token, e := dec.Token()
+			if e != nil {
+				return keys, rows, e
+			}
+			key, _ := token.(string)
+			var value interface{}
+			if e = dec.Decode(&value); e != nil {
+				return keys, rows, e
+			}
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+			row[key] = value
+		}
+		if _, e := dec.Token(); e != nil {
+			return keys, rows, e
+		}
+		rows = append(rows, row)
+	}
+	if token, _ := dec.Token(); token != json.Delim(']') {
+		return keys, rows, err
+	}
+	return keys, rows, nil
+}
+
+// setJSONValue provides a function to set the value of the cell by given
+// value of the JSON object and the layout for parsing the time values. The
+// date values without time will be set with the date number format.
+func (f *File) setJSONValue(sheet, cell string, value interface{}, layout string) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return f.SetCellValue(sheet, cell, i)
+		}
+		num, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		return f.SetCellValue(sheet, cell, num)
+	case string:
+		if layout == "" {
+			return f.SetCellStr(sheet, cell, v)
+		}
+		t, err := time.Parse(layout, v)
+		if err != nil {
+			return f.SetCellStr(sheet, cell, v)
+		}
+		value = t
+	case map[string]interface{}, []interface{}:
+		text, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return f.SetCellStr(sheet, cell, string(text))
+	}
+	if t, ok := value.(time.Time); ok && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		if err := f.setDefaultTimeStyle(sheet, cell, 14); err != nil {
+			return err
+		}
+	}
+	return f.SetCellValue(sheet, cell, value)
+}
@@ -0,0 +1,108 @@
+package excelize
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportJSON(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Region", "B1": "Date", "C1": "Amount", "D1": "Paid", "F1": "Region",
+		"A2": "East", "B2": time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "C2": 1200.5, "D2": true,
+		"A3": "West", "C3": 30, "D3": false, "E3": "x",
+		"A5": "North", "F5": "South",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf))
+	assert.Equal(t, `[{"Region":"East","Date":"2021-01-01T00:00:00Z","Amount":1200.5,"Paid":true,"E":null,"Region_2":null},`+
+		`{"Region":"West","Date":null,"Amount":30,"Paid":false,"E":"x","Region_2":null},`+
+		`{"Region":"North","Date":null,"Amount":null,"Paid":null,"E":null,"Region_2":"South"}]`, buf.String())
+
+	// Test export the range with custom time layout
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "B1:C3", TimeLayout: "2006-01-02"}))
+	assert.Equal(t, `[{"Date":"2021-01-01","Amount":1200.5},{"Date":null,"Amount":30}]`, buf.String())
+
+	// Test export the table with totals row
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "D4", `{"table_name":"Sales","totals_row":[{"column":"C","function":"sum"}]}`))
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Table: "sales"}))
+	assert.Equal(t, `[{"Region":"East","Date":"2021-01-01T00:00:00Z","Amount":1200.5,"Paid":true},`+
+		`{"Region":"West","Date":null,"Amount":30,"Paid":false}]`, buf.String())
+
+	// Test export the empty worksheet
+	f.NewSheet("Sheet2")
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet2", &buf))
+	assert.Equal(t, "[]", buf.String())
+
+	// Test export with invalid options
+	assert.EqualError(t, f.ExportJSON("SheetN", &buf), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "A"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Table: "Orders"}), "table Orders is not exist")
+	assert.EqualError(t, f.ExportJSON("Sheet2", &buf, JSONOptions{Table: "Sales"}), "table Sales is not on the worksheet Sheet2")
+}
+
+func TestImportJSON(t *testing.T) {
+	f := NewFile()
+	data := json.RawMessage(`[
+		{"Region":"East","Date":"2021-01-01","Amount":1200.5,"Paid":true,"Tags":["a","b"]},
+		{"Region":"West","Amount":30,"Note":null,"Date":"n/a"},
+		{"Count":12345678901}
+	]`)
+	assert.NoError(t, f.ImportJSON("Sheet1", "B2", data, ImportJSONOptions{
+		TimeLayout:  "2006-01-02",
+		Table:       true,
+		TableFormat: `{"table_name":"Sales"}`,
+	}))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		nil,
+		{"", "Region", "Date", "Amount", "Paid", "Tags", "Note", "Count"},
+		{"", "East", "01-01-21", "1200.5", "1", `["a","b"]`},
+		{"", "West", "n/a", "30"},
+		{"", "", "", "", "", "", "", "12345678901"},
+	}, rows)
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:H5", tables[0].Range)
+
+	// Test import the maps with the time value and specified columns
+	f = NewFile()
+	assert.NoError(t, f.ImportJSON("Sheet1", "A1", []map[string]interface{}{
+		{"Name": "Foo", "Time": time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC), "Age": 30},
+		{"Name": "Bar", "Nested": map[string]interface{}{"k": "v"}},
+	}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Age", "Name", "Time", "Nested"},
+		{"30", "Foo", "1/1/21 12:00"},
+		{"", "Bar", "", `{"k":"v"}`},
+	}, rows)
+	assert.NoError(t, f.ImportJSON("Sheet1", "F1", json.RawMessage(`[{"a":1,"b":2}]`), ImportJSONOptions{Columns: []string{"b", "c"}}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, rows[0][5:])
+	assert.Equal(t, []string{"2"}, rows[1][5:])
+	assert.NoError(t, f.ImportJSON("Sheet1", "A10", json.RawMessage(`[]`)))
+
+	// Test import with invalid data
+	expected := "JSON array of objects expected"
+	for _, data := range []string{`{}`, `[1]`, `[{"a":1}`, `[{"a":1},2]`} {
+		assert.EqualError(t, f.ImportJSON("Sheet1", "A1", json.RawMessage(data)), expected, data)
+	}
+	assert.EqualError(t, f.ImportJSON("Sheet1", "A1", json.RawMessage(`[{"a":}]`)), "invalid character '}' looking for beginning of value")
+	assert.EqualError(t, f.ImportJSON("Sheet1", "A1", []string{}), "unsupported data type []string")
+	assert.EqualError(t, f.ImportJSON("Sheet1", "A", []map[string]interface{}{}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.ImportJSON("SheetN", "A1", []map[string]interface{}{{"a": 1}}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ImportJSON("Sheet1", "XFD1", []map[string]interface{}{{"a": 1, "b": 2}}), "column number exceeds maximum limit")
+}
@@ -16,8 +16,12 @@ import (
 	"bytes"
 	"container/list"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path"
 	"strconv"
 	"strings"
 )
@@ -26,17 +30,10 @@ import (
 // filesystem.
 func ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 	var err error
-	var docPart = map[string]string{
-		"[content_types].xml":  "[Content_Types].xml",
-		"xl/sharedstrings.xml": "xl/sharedStrings.xml",
-	}
 	fileList := make(map[string][]byte, len(r.File))
 	worksheets := 0
 	for _, v := range r.File {
-		fileName := v.Name
-		if partName, ok := docPart[strings.ToLower(v.Name)]; ok {
-			fileName = partName
-		}
+		fileName := zipPartName(v.Name)
 		if fileList[fileName], err = readFile(v); err != nil {
 			return nil, 0, err
 		}
@@ -47,12 +44,66 @@ func ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 	return fileList, worksheets, nil
 }
 
-// readXML provides a function to read XML content as string.
+// zipPartName provides a function to get the part name by given file name in
+// the ZIP archive, the case of the part names of the content types and the
+// shared strings will be normalized.
+func zipPartName(name string) string {
+	switch strings.ToLower(name) {
+	case "[content_types].xml":
+		return "[Content_Types].xml"
+	case "xl/sharedstrings.xml":
+		return "xl/sharedStrings.xml"
+	}
+	return name
+}
+
+// lazyPart is the part of the spreadsheet which has not been read into
+// memory, such as the entry in the ZIP archive or the temporary file.
+type lazyPart interface {
+	Open() (io.ReadCloser, error)
+}
+
+// tempFilePart is the part of the spreadsheet which has been extracted to a
+// temporary file by given file path.
+type tempFilePart string
+
+// Open provides a function to open the temporary file of the part for
+// reading.
+func (p tempFilePart) Open() (io.ReadCloser, error) {
+	return os.Open(string(p))
+}
+
+// readXML provides a function to read XML content as string. The part which
+// can't be loaded will be read as empty content, use readBytes to get the
+// error of loading the part.
 func (f *File) readXML(name string) []byte {
+	content, _ := f.readBytes(name)
+	return content
+}
+
+// readBytes provides a function to read the content of the part by given part
+// name. The part which has not been read from the reader will be loaded on
+// demand, and the part which has been extracted to a temporary file will be
+// read from the file each time without keeping it in memory.
+func (f *File) readBytes(name string) ([]byte, error) {
+	if part, ok := f.lazyParts[name]; ok {
+		if _, ok = f.XLSX[name]; ok {
+			content, err := readPart(part)
+			if err != nil {
+				f.logf("%s: read file error: %s", name, err)
+				return []byte{}, ErrReadPart{Part: name, Err: err}
+			}
+			if _, ok = part.(tempFilePart); ok {
+				return content, nil
+			}
+			f.XLSX[name] = content
+		}
+		delete(f.lazyParts, name)
+	}
 	if content, ok := f.XLSX[name]; ok {
-		return content
+		return content, nil
 	}
-	return []byte{}
+	return []byte{}, nil
 }
 
 // saveFileList provides a function to update given file content in file list
@@ -62,6 +113,91 @@ func (f *File) saveFileList(name string, content []byte) {
 	newContent = append(newContent, []byte(XMLHeader)...)
 	newContent = append(newContent, content...)
 	f.XLSX[name] = newContent
+	delete(f.lazyParts, name)
+}
+
+// getNextPartID provides a function to get the next free index of the part
+// by given prefix and suffix of the part name, such as
+// "xl/drawings/vmlDrawing" and ".vml". The part names in the file list, the
+// lazy parts and the parsed parts are all taken into account, so the part
+// which is owned by the other worksheet won't be reused.
+func (f *File) getNextPartID(prefix, suffix string) int {
+	var id int
+	check := func(name string) {
+		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix) {
+			if i, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix)); err == nil && i > id {
+				id = i
+			}
+		}
+	}
+	for name := range f.XLSX {
+		check(name)
+	}
+	for name := range f.lazyParts {
+		check(name)
+	}
+	for name := range f.Comments {
+		check(name)
+	}
+	for name := range f.Drawings {
+		check(name)
+	}
+	for name := range f.VMLDrawing {
+		check(name)
+	}
+	for name := range f.DecodeVMLDrawing {
+		check(name)
+	}
+	return id + 1
+}
+
+// getPartRelsPath provides a function to get the relationships part path of
+// the package part by given part path.
+func getPartRelsPath(partPath string) string {
+	return path.Join(path.Dir(partPath), "_rels", path.Base(partPath)+".rels")
+}
+
+// resolvePartPath provides a function to get the path of the relationship
+// target by given source part path and target.
+func resolvePartPath(source, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(source), target)
+}
+
+// readPart provides a function to read the content of the part which has not
+// been read into memory.
+func readPart(part lazyPart) ([]byte, error) {
+	if file, ok := part.(*zip.File); ok {
+		return readFile(file)
+	}
+	rc, err := part.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+// unzipToTempFile provides a function to extract the file in the archive to
+// a temporary file.
+func unzipToTempFile(file *zip.File) (tempFilePart, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	tmp, err := ioutil.TempFile(os.TempDir(), "excelize-")
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(tmp, rc); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tempFilePart(tmp.Name()), tmp.Close()
 }
 
 // Read file content as string in a archive file.
@@ -182,11 +318,9 @@ func ColumnNumberToName(num int) (string, error) {
 //    excelize.CellNameToCoordinates("Z3") // returns 26, 3, nil
 //
 func CellNameToCoordinates(cell string) (int, int, error) {
-	const msg = "cannot convert cell %q to coordinates: %v"
-
 	colname, row, err := SplitCellName(cell)
 	if err != nil {
-		return -1, -1, fmt.Errorf(msg, cell, err)
+		return -1, -1, ErrCellCoordinates{Cell: cell, Err: err}
 	}
 	if row > TotalRows {
 		return -1, -1, fmt.Errorf("row number exceeds maximum limit")
@@ -210,6 +344,271 @@ func CoordinatesToCellName(col, row int) (string, error) {
 	return colname + strconv.Itoa(row), err
 }
 
+// rangeRefToCoordinates provides a function to convert the cell or range
+// reference, such as "A1", "$A$1:$B$2" or "B2:A1", to the sorted coordinates
+// of the top-left and bottom-right cells.
+func rangeRefToCoordinates(ref string) ([]int, error) {
+	cells := strings.Split(strings.Replace(ref, "$", "", -1), ":")
+	if len(cells) == 1 {
+		cells = append(cells, cells[0])
+	}
+	if len(cells) != 2 {
+		return nil, fmt.Errorf("invalid range %q", ref)
+	}
+	coordinates, err := areaRangeToCoordinates(cells[0], cells[1])
+	if err != nil {
+		return coordinates, err
+	}
+	return coordinates, sortCoordinates(coordinates)
+}
+
+// coordinatesToRangeRef provides a function to convert the coordinates of
+// the top-left and bottom-right cells to the range reference, the single
+// cell range will be converted to the cell name.
+func coordinatesToRangeRef(coordinates []int) (string, error) {
+	if coordinates[1] > TotalRows || coordinates[3] > TotalRows {
+		return "", fmt.Errorf("row number exceeds maximum limit")
+	}
+	firstCell, err := CoordinatesToCellName(coordinates[0], coordinates[1])
+	if err != nil {
+		return "", err
+	}
+	lastCell, err := CoordinatesToCellName(coordinates[2], coordinates[3])
+	if err != nil || firstCell == lastCell {
+		return firstCell, err
+	}
+	return firstCell + ":" + lastCell, err
+}
+
+// RangeUnion provides a function to get the smallest range which contains
+// all the given cells or ranges.
+//
+// Example:
+//
+//    excelize.RangeUnion("A1:B2", "D4") // returns "A1:D4", nil
+//
+func RangeUnion(refs ...string) (string, error) {
+	var union []int
+	for _, ref := range refs {
+		coordinates, err := rangeRefToCoordinates(ref)
+		if err != nil {
+			return "", err
+		}
+		if union == nil {
+			union = coordinates
+			continue
+		}
+		for i := 0; i < 2; i++ {
+			if coordinates[i] < union[i] {
+				union[i] = coordinates[i]
+			}
+			if coordinates[i+2] > union[i+2] {
+				union[i+2] = coordinates[i+2]
+			}
+		}
+	}
+	if union == nil {
+		return "", errors.New("parameter is required")
+	}
+	return coordinatesToRangeRef(union)
+}
+
+// RangeIntersect provides a function to get the overlapping range of two
+// given cells or ranges, and returns an empty string if they don't overlap.
+//
+// Example:
+//
+//    excelize.RangeIntersect("A1:C3", "B2:D4") // returns "B2:C3", nil
+//
+func RangeIntersect(refA, refB string) (string, error) {
+	a, err := rangeRefToCoordinates(refA)
+	if err != nil {
+		return "", err
+	}
+	b, err := rangeRefToCoordinates(refB)
+	if err != nil {
+		return "", err
+	}
+	intersect := a
+	for i := 0; i < 2; i++ {
+		if b[i] > intersect[i] {
+			intersect[i] = b[i]
+		}
+		if b[i+2] < intersect[i+2] {
+			intersect[i+2] = b[i+2]
+		}
+	}
+	if intersect[0] > intersect[2] || intersect[1] > intersect[3] {
+		return "", nil
+	}
+	return coordinatesToRangeRef(intersect)
+}
+
+// RangeOffset provides a function to move the cell or range by the given
+// number of rows and columns, the negative number moves it up or left. The
+// function returns an error if the result is out of the worksheet.
+//
+// Example:
+//
+//    excelize.RangeOffset("A1:B2", 2, 1) // returns "B3:C4", nil
+//
+func RangeOffset(ref string, rows, cols int) (string, error) {
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return "", err
+	}
+	return coordinatesToRangeRef([]int{coordinates[0] + cols, coordinates[1] + rows, coordinates[2] + cols, coordinates[3] + rows})
+}
+
+// RangeExpand provides a function to resize the cell or range by the given
+// number of rows and columns, the top-left cell of the range is kept and the
+// negative number shrinks it. The function returns an error if the result is
+// out of the worksheet or has no cells.
+//
+// Example:
+//
+//    excelize.RangeExpand("A1:B2", 3, 1) // returns "A1:C5", nil
+//
+func RangeExpand(ref string, rows, cols int) (string, error) {
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return "", err
+	}
+	coordinates[2], coordinates[3] = coordinates[2]+cols, coordinates[3]+rows
+	if coordinates[2] < coordinates[0] || coordinates[3] < coordinates[1] {
+		return "", fmt.Errorf("invalid range %q resized by %d rows and %d columns", ref, rows, cols)
+	}
+	return coordinatesToRangeRef(coordinates)
+}
+
+// CellInRange provides a function to check if the cell is within the given
+// cell or range.
+//
+// Example:
+//
+//    excelize.CellInRange("B2", "A1:C3") // returns true, nil
+//
+func CellInRange(cell, ref string) (bool, error) {
+	col, row, err := CellNameToCoordinates(strings.Replace(cell, "$", "", -1))
+	if err != nil {
+		return false, err
+	}
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return false, err
+	}
+	return cellInRef([]int{col, row}, coordinates), err
+}
+
+// CellNameToR1C1 provides a function to convert the cell or range reference
+// in A1 notation to the absolute R1C1 notation.
+//
+// Example:
+//
+//    excelize.CellNameToR1C1("B3")    // returns "R3C2", nil
+//    excelize.CellNameToR1C1("A1:B3") // returns "R1C1:R3C2", nil
+//
+func CellNameToR1C1(ref string) (string, error) {
+	var cells []string
+	refs := strings.Split(strings.Replace(ref, "$", "", -1), ":")
+	if len(refs) > 2 {
+		return "", fmt.Errorf("invalid range %q", ref)
+	}
+	for _, cell := range refs {
+		col, row, err := CellNameToCoordinates(cell)
+		if err != nil {
+			return "", err
+		}
+		cells = append(cells, fmt.Sprintf("R%dC%d", row, col))
+	}
+	return strings.Join(cells, ":"), nil
+}
+
+// R1C1ToCellName provides a function to convert the cell or range reference
+// in absolute R1C1 notation to the A1 notation.
+//
+// Example:
+//
+//    excelize.R1C1ToCellName("R3C2")      // returns "B3", nil
+//    excelize.R1C1ToCellName("R1C1:R3C2") // returns "A1:B3", nil
+//
+func R1C1ToCellName(ref string) (string, error) {
+	var cells []string
+	refs := strings.Split(strings.ToUpper(ref), ":")
+	if len(refs) > 2 {
+		return "", fmt.Errorf("invalid R1C1 reference %q", ref)
+	}
+	for _, cell := range refs {
+		idx := strings.Index(cell, "C")
+		if !strings.HasPrefix(cell, "R") || idx == -1 {
+			return "", fmt.Errorf("invalid R1C1 reference %q", ref)
+		}
+		row, err := strconv.Atoi(cell[1:idx])
+		if err != nil || row > TotalRows {
+			return "", fmt.Errorf("invalid R1C1 reference %q", ref)
+		}
+		col, err := strconv.Atoi(cell[idx+1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid R1C1 reference %q", ref)
+		}
+		name, err := CoordinatesToCellName(col, row)
+		if err != nil {
+			return "", err
+		}
+		cells = append(cells, name)
+	}
+	return strings.Join(cells, ":"), nil
+}
+
+// Split3DReference provides a function to split the 3D reference, which
+// refers to the same cell or range on a span of worksheets, to the first and
+// last worksheet name and the cell or range reference.
+//
+// Example:
+//
+//    excelize.Split3DReference("'Jan 2021:Dec 2021'!B2") // returns "Jan 2021", "Dec 2021", "B2", nil
+//
+func Split3DReference(ref string) (string, string, string, error) {
+	idx := strings.LastIndex(ref, "!")
+	if idx == -1 {
+		return "", "", "", fmt.Errorf("invalid 3D reference %q", ref)
+	}
+	span, cells := ref[:idx], ref[idx+1:]
+	if len(span) > 1 && strings.HasPrefix(span, "'") && strings.HasSuffix(span, "'") {
+		span = strings.Replace(span[1:len(span)-1], "''", "'", -1)
+	}
+	sheets := strings.Split(span, ":")
+	if len(sheets) != 2 || sheets[0] == "" || sheets[1] == "" {
+		return "", "", "", fmt.Errorf("invalid 3D reference %q", ref)
+	}
+	if _, err := rangeRefToCoordinates(cells); err != nil {
+		return "", "", "", err
+	}
+	return sheets[0], sheets[1], cells, nil
+}
+
+// Join3DReference provides a function to join the first and last worksheet
+// name and the cell or range reference to the 3D reference, the worksheet
+// span will be quoted if any of the worksheet names needs it.
+//
+// Example:
+//
+//    excelize.Join3DReference("Sheet1", "Sheet5", "B2") // returns "Sheet1:Sheet5!B2", nil
+//
+func Join3DReference(firstSheet, lastSheet, ref string) (string, error) {
+	if firstSheet == "" || lastSheet == "" {
+		return "", fmt.Errorf("invalid worksheet span %q", firstSheet+":"+lastSheet)
+	}
+	if _, err := rangeRefToCoordinates(ref); err != nil {
+		return "", err
+	}
+	span := firstSheet + ":" + lastSheet
+	if quoteSheetName(firstSheet) != firstSheet || quoteSheetName(lastSheet) != lastSheet {
+		span = "'" + strings.Replace(span, "'", "''", -1) + "'"
+	}
+	return span + "!" + ref, nil
+}
+
 // boolPtr returns a pointer to a bool with the given value.
 func boolPtr(b bool) *bool { return &b }
 
@@ -239,19 +638,50 @@ func parseFormatSet(formatSet string) []byte {
 	return []byte("{}")
 }
 
+// strictNamespaces defined the Strict namespaces and relationship types with
+// the corresponding Transitional ones. The namespaces which are the prefix of
+// another one should be placed after it.
+var strictNamespaces = [][2]string{
+	{StrictSourceRelationship + "/extendedProperties", SourceRelationshipExtendProperties},
+	{StrictSourceRelationship + "/customProperties", SourceRelationshipCustomProperties},
+	{StrictSourceRelationship, SourceRelationship.Value},
+	{StrictNameSpaceSpreadSheet, NameSpaceSpreadSheet.Value},
+	{"http://purl.oclc.org/ooxml/drawingml/main", NameSpaceDrawingML.Value},
+	{"http://purl.oclc.org/ooxml/drawingml/chartDrawing", "http://schemas.openxmlformats.org/drawingml/2006/chartDrawing"},
+	{"http://purl.oclc.org/ooxml/drawingml/chart", NameSpaceDrawingMLChart.Value},
+	{"http://purl.oclc.org/ooxml/drawingml/spreadsheetDrawing", NameSpaceDrawingMLSpreadSheet.Value},
+	{"http://purl.oclc.org/ooxml/drawingml/picture", "http://schemas.openxmlformats.org/drawingml/2006/picture"},
+	{"http://purl.oclc.org/ooxml/drawingml/diagram", "http://schemas.openxmlformats.org/drawingml/2006/diagram"},
+	{"http://purl.oclc.org/ooxml/drawingml/lockedCanvas", "http://schemas.openxmlformats.org/drawingml/2006/lockedCanvas"},
+	{"http://purl.oclc.org/ooxml/drawingml/compatibility", "http://schemas.openxmlformats.org/drawingml/2006/compatibility"},
+	{"http://purl.oclc.org/ooxml/officeDocument/extendedProperties", NameSpaceExtendedProperties},
+	{"http://purl.oclc.org/ooxml/officeDocument/customProperties", NameSpaceCustomProperties},
+	{"http://purl.oclc.org/ooxml/officeDocument/docPropsVTypes", NameSpaceDocPropsVTypes},
+	{"http://purl.oclc.org/ooxml/officeDocument/customXml", NameSpaceCustomXML},
+	{"http://purl.oclc.org/ooxml/officeDocument/sharedTypes", "http://schemas.openxmlformats.org/officeDocument/2006/sharedTypes"},
+	{"http://purl.oclc.org/ooxml/officeDocument/math", "http://schemas.openxmlformats.org/officeDocument/2006/math"},
+	{"http://purl.oclc.org/ooxml/officeDocument/bibliography", "http://schemas.openxmlformats.org/officeDocument/2006/bibliography"},
+	{"http://purl.oclc.org/ooxml/schemaLibrary/main", "http://schemas.openxmlformats.org/schemaLibrary/2006/main"},
+}
+
 // namespaceStrictToTransitional provides a method to convert Strict and
 // Transitional namespaces.
 func namespaceStrictToTransitional(content []byte) []byte {
-	var namespaceTranslationDic = map[string]string{
-		StrictSourceRelationship:               SourceRelationship.Value,
-		StrictSourceRelationshipOfficeDocument: SourceRelationshipOfficeDocument,
-		StrictSourceRelationshipChart:          SourceRelationshipChart,
-		StrictSourceRelationshipComments:       SourceRelationshipComments,
-		StrictSourceRelationshipImage:          SourceRelationshipImage,
-		StrictNameSpaceSpreadSheet:             NameSpaceSpreadSheet.Value,
-	}
-	for s, n := range namespaceTranslationDic {
-		content = bytesReplace(content, []byte(s), []byte(n), -1)
+	if !bytes.Contains(content, []byte("http://purl.oclc.org/ooxml/")) {
+		return content
+	}
+	for _, ns := range strictNamespaces {
+		content = bytesReplace(content, []byte(ns[0]), []byte(ns[1]), -1)
+	}
+	return content
+}
+
+// namespaceTransitionalToStrict provides a method to convert Transitional
+// namespaces and relationship types in the attribute values to the Strict
+// ones.
+func namespaceTransitionalToStrict(content []byte) []byte {
+	for _, ns := range strictNamespaces {
+		content = bytesReplace(content, []byte(`"`+ns[1]), []byte(`"`+ns[0]), -1)
 	}
 	return content
 }
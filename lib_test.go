@@ -211,6 +211,90 @@ func TestCoordinatesToCellName_Error(t *testing.T) {
 	}
 }
 
+func TestRangeArithmetic(t *testing.T) {
+	ref, err := RangeUnion("A1:B2", "D4", "$C$1:$C$3")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:D4", ref)
+	ref, err = RangeUnion("B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2", ref)
+	_, err = RangeUnion()
+	assert.EqualError(t, err, "parameter is required")
+	_, err = RangeUnion("A1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
+	ref, err = RangeIntersect("A1:C3", "D4:B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:C3", ref)
+	ref, err = RangeIntersect("A1:B2", "C3:D4")
+	assert.NoError(t, err)
+	assert.Equal(t, "", ref)
+	_, err = RangeIntersect("A1:B2:C3", "A1")
+	assert.EqualError(t, err, `invalid range "A1:B2:C3"`)
+	_, err = RangeIntersect("A1", "A0")
+	assert.EqualError(t, err, `cannot convert cell "A0" to coordinates: invalid cell name "A0"`)
+
+	ref, err = RangeOffset("A1:B2", 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "B3:C4", ref)
+	_, err = RangeOffset("A1:B2", -1, 0)
+	assert.EqualError(t, err, "invalid cell coordinates [1, 0]")
+	_, err = RangeOffset("A1048576", 1, 0)
+	assert.EqualError(t, err, "row number exceeds maximum limit")
+	_, err = RangeOffset("A", 1, 0)
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
+	ref, err = RangeExpand("A1:B2", 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:C5", ref)
+	ref, err = RangeExpand("A1:B2", -1, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", ref)
+	_, err = RangeExpand("A1:B2", -2, 0)
+	assert.EqualError(t, err, `invalid range "A1:B2" resized by -2 rows and 0 columns`)
+	_, err = RangeExpand("XFD1", 0, 1)
+	assert.EqualError(t, err, "column number exceeds maximum limit")
+	_, err = RangeExpand("A", 1, 0)
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
+	ok, err := CellInRange("B2", "A1:C3")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = CellInRange("$D$4", "C3:A1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, err = CellInRange("A", "A1:C3")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = CellInRange("A1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
+func TestR1C1Conversion(t *testing.T) {
+	ref, err := CellNameToR1C1("B3")
+	assert.NoError(t, err)
+	assert.Equal(t, "R3C2", ref)
+	ref, err = CellNameToR1C1("$A$1:$AK$74")
+	assert.NoError(t, err)
+	assert.Equal(t, "R1C1:R74C37", ref)
+	_, err = CellNameToR1C1("A1:B2:C3")
+	assert.EqualError(t, err, `invalid range "A1:B2:C3"`)
+	_, err = CellNameToR1C1("A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
+	ref, err = R1C1ToCellName("R3C2")
+	assert.NoError(t, err)
+	assert.Equal(t, "B3", ref)
+	ref, err = R1C1ToCellName("r1c1:R74C37")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:AK74", ref)
+	for _, ref := range []string{"R1C1:R2C2:R3C3", "A1", "R1", "RC1", "R1C", "R1048577C1"} {
+		_, err = R1C1ToCellName(ref)
+		assert.EqualError(t, err, fmt.Sprintf("invalid R1C1 reference %q", ref))
+	}
+	_, err = R1C1ToCellName("R1C0")
+	assert.EqualError(t, err, "invalid cell coordinates [0, 1]")
+}
+
 func TestBytesReplace(t *testing.T) {
 	s := []byte{0x01}
 	assert.EqualValues(t, s, bytesReplace(s, []byte{}, []byte{}, 0))
@@ -228,3 +312,29 @@ func TestStack(t *testing.T) {
 	assert.Equal(t, s.Peek(), nil)
 	assert.Equal(t, s.Pop(), nil)
 }
+
+func Test3DReference(t *testing.T) {
+	first, last, ref, err := Split3DReference("Sheet1:Sheet5!$B$2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet5", "$B$2"}, []string{first, last, ref})
+	first, last, ref, err = Split3DReference("'Jan 2021:O''Brien'!B2:C3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Jan 2021", "O'Brien", "B2:C3"}, []string{first, last, ref})
+	_, _, _, err = Split3DReference("B2")
+	assert.EqualError(t, err, `invalid 3D reference "B2"`)
+	_, _, _, err = Split3DReference("Sheet1!B2")
+	assert.EqualError(t, err, `invalid 3D reference "Sheet1!B2"`)
+	_, _, _, err = Split3DReference("Sheet1:Sheet5!B")
+	assert.EqualError(t, err, `cannot convert cell "B" to coordinates: invalid cell name "B"`)
+
+	ref, err = Join3DReference("Sheet1", "Sheet5", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet1:Sheet5!B2", ref)
+	ref, err = Join3DReference("Jan 2021", "O'Brien", "$B$2:$C$3")
+	assert.NoError(t, err)
+	assert.Equal(t, "'Jan 2021:O''Brien'!$B$2:$C$3", ref)
+	_, err = Join3DReference("", "Sheet5", "B2")
+	assert.EqualError(t, err, `invalid worksheet span ":Sheet5"`)
+	_, err = Join3DReference("Sheet1", "Sheet5", "B")
+	assert.EqualError(t, err, `cannot convert cell "B" to coordinates: invalid cell name "B"`)
+}
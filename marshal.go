@@ -0,0 +1,469 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// xmlWriter is a writer which serializes the frequently used elements of the
+// worksheets, shared strings and styles without reflection, the output of it
+// is the same as encoding/xml. The elements which are rarely used will be
+// serialized by the encoding/xml encoder on the same buffer. If the
+// destination writer is set, the buffer will be flushed to it in chunks.
+type xmlWriter struct {
+	buf bytes.Buffer
+	enc *xml.Encoder
+	dst io.Writer
+	err error
+}
+
+// xmlWriterChunkSize is the size of the buffer of the writer which will be
+// flushed to the destination writer.
+const xmlWriterChunkSize = 64 << 10
+
+// newXMLWriter provides a function to create a writer with the given initial
+// capacity of the buffer.
+func newXMLWriter(size int) *xmlWriter {
+	w := &xmlWriter{}
+	w.buf.Grow(size)
+	w.enc = xml.NewEncoder(&w.buf)
+	return w
+}
+
+// flush provides a function to write the buffer to the destination writer if
+// it's full or force is true.
+func (w *xmlWriter) flush(force bool) {
+	if w.dst == nil || w.err != nil || (!force && w.buf.Len() < xmlWriterChunkSize) {
+		return
+	}
+	_, w.err = w.dst.Write(w.buf.Bytes())
+	w.buf.Reset()
+}
+
+// encodeElement provides a function to serialize the element by the
+// encoding/xml encoder.
+func (w *xmlWriter) encodeElement(v interface{}, name string) {
+	_ = w.enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// text provides a function to write the escaped text, the text which doesn't
+// need to be escaped will be written directly.
+func (w *xmlWriter) text(s string) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= utf8.RuneSelf || c == '&' || c == '<' || c == '>' || c == '"' || c == '\'' {
+			_ = xml.EscapeText(&w.buf, []byte(s))
+			return
+		}
+	}
+	w.buf.WriteString(s)
+}
+
+// attr provides a function to write the attribute by given name and value.
+func (w *xmlWriter) attr(name, value string) {
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(name)
+	w.buf.WriteString(`="`)
+	w.text(value)
+	w.buf.WriteByte('"')
+}
+
+// attrInt provides a function to write the integer attribute by given name
+// and value.
+func (w *xmlWriter) attrInt(name string, value int64) {
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(name)
+	w.buf.WriteString(`="`)
+	var b [20]byte
+	w.buf.Write(strconv.AppendInt(b[:0], value, 10))
+	w.buf.WriteByte('"')
+}
+
+// attrBool provides a function to write the boolean attribute by given name
+// and value.
+func (w *xmlWriter) attrBool(name string, value bool) {
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(name)
+	w.buf.WriteString(`="`)
+	w.buf.WriteString(strconv.FormatBool(value))
+	w.buf.WriteByte('"')
+}
+
+// xmlAttrName returns the qualified name of the attribute in the XML
+// namespace or without namespace.
+func xmlAttrName(name xml.Name) string {
+	if name.Space == NameSpaceXML {
+		return "xml:" + name.Local
+	}
+	return name.Local
+}
+
+// row provides a function to write the row element of the worksheet.
+func (w *xmlWriter) row(r *xlsxRow) {
+	w.buf.WriteString(`<row`)
+	if r.R != 0 {
+		w.attrInt("r", int64(r.R))
+	}
+	if r.Spans != "" {
+		w.attr("spans", r.Spans)
+	}
+	if r.S != 0 {
+		w.attrInt("s", int64(r.S))
+	}
+	if r.CustomFormat {
+		w.attrBool("customFormat", true)
+	}
+	if r.Ht != 0 {
+		w.attr("ht", strconv.FormatFloat(r.Ht, 'g', -1, 64))
+	}
+	if r.Hidden {
+		w.attrBool("hidden", true)
+	}
+	if r.CustomHeight {
+		w.attrBool("customHeight", true)
+	}
+	if r.OutlineLevel != 0 {
+		w.attrInt("outlineLevel", int64(r.OutlineLevel))
+	}
+	if r.Collapsed {
+		w.attrBool("collapsed", true)
+	}
+	if r.ThickTop {
+		w.attrBool("thickTop", true)
+	}
+	if r.ThickBot {
+		w.attrBool("thickBot", true)
+	}
+	if r.Ph {
+		w.attrBool("ph", true)
+	}
+	w.buf.WriteByte('>')
+	for i := range r.C {
+		w.cell(&r.C[i])
+	}
+	w.buf.WriteString(`</row>`)
+}
+
+// cell provides a function to write the cell element of the worksheet.
+func (w *xmlWriter) cell(c *xlsxC) {
+	space := c.XMLSpace.Name
+	if c.IS != nil || (space.Local != "" && space.Space != NameSpaceXML) {
+		w.encodeElement(c, "c")
+		return
+	}
+	w.buf.WriteString(`<c`)
+	if space.Local != "" {
+		w.attr(xmlAttrName(space), c.XMLSpace.Value)
+	}
+	if c.R != "" {
+		w.attr("r", c.R)
+	}
+	if c.S != 0 {
+		w.attrInt("s", int64(c.S))
+	}
+	if c.T != "" {
+		w.attr("t", c.T)
+	}
+	if c.Ph {
+		w.attrBool("ph", true)
+	}
+	w.buf.WriteByte('>')
+	if c.F != nil {
+		w.buf.WriteString(`<f`)
+		if c.F.T != "" {
+			w.attr("t", c.F.T)
+		}
+		if c.F.Ref != "" {
+			w.attr("ref", c.F.Ref)
+		}
+		if c.F.Si != "" {
+			w.attr("si", c.F.Si)
+		}
+		w.buf.WriteByte('>')
+		w.text(c.F.Content)
+		w.buf.WriteString(`</f>`)
+	}
+	if v := c.value(); v != "" {
+		w.buf.WriteString(`<v>`)
+		w.text(v)
+		w.buf.WriteString(`</v>`)
+	}
+	w.buf.WriteString(`</c>`)
+}
+
+// si provides a function to write the string item of the shared string
+// table.
+func (w *xmlWriter) si(si *xlsxSI) {
+	if si.T == nil || len(si.R) > 0 || len(si.RPh) > 0 || si.PhoneticPr != nil ||
+		(si.T.Space.Name.Local != "" && si.T.Space.Name.Space != NameSpaceXML) {
+		w.encodeElement(si, "si")
+		return
+	}
+	w.buf.WriteString(`<si><t`)
+	if si.T.Space.Name.Local != "" {
+		w.attr(xmlAttrName(si.T.Space.Name), si.T.Space.Value)
+	}
+	w.buf.WriteByte('>')
+	w.text(si.T.Val)
+	w.buf.WriteString(`</t></si>`)
+}
+
+// xf provides a function to write the cell formatting record of the styles.
+func (w *xmlWriter) xf(xf *xlsxXf) {
+	w.buf.WriteString(`<xf`)
+	for _, attr := range []struct {
+		name  string
+		value *int
+	}{
+		{"numFmtId", xf.NumFmtID}, {"fontId", xf.FontID}, {"fillId", xf.FillID},
+		{"borderId", xf.BorderID}, {"xfId", xf.XfID},
+	} {
+		if attr.value != nil {
+			w.attrInt(attr.name, int64(*attr.value))
+		}
+	}
+	for _, attr := range []struct {
+		name  string
+		value *bool
+	}{
+		{"quotePrefix", xf.QuotePrefix}, {"pivotButton", xf.PivotButton},
+		{"applyNumberFormat", xf.ApplyNumberFormat}, {"applyFont", xf.ApplyFont},
+		{"applyFill", xf.ApplyFill}, {"applyBorder", xf.ApplyBorder},
+		{"applyAlignment", xf.ApplyAlignment}, {"applyProtection", xf.ApplyProtection},
+	} {
+		if attr.value != nil {
+			w.attrBool(attr.name, *attr.value)
+		}
+	}
+	w.buf.WriteByte('>')
+	if a := xf.Alignment; a != nil {
+		w.buf.WriteString(`<alignment`)
+		if a.Horizontal != "" {
+			w.attr("horizontal", a.Horizontal)
+		}
+		if a.Indent != 0 {
+			w.attrInt("indent", int64(a.Indent))
+		}
+		if a.JustifyLastLine {
+			w.attrBool("justifyLastLine", true)
+		}
+		if a.ReadingOrder != 0 {
+			w.attr("readingOrder", strconv.FormatUint(a.ReadingOrder, 10))
+		}
+		if a.RelativeIndent != 0 {
+			w.attrInt("relativeIndent", int64(a.RelativeIndent))
+		}
+		if a.ShrinkToFit {
+			w.attrBool("shrinkToFit", true)
+		}
+		if a.TextRotation != 0 {
+			w.attrInt("textRotation", int64(a.TextRotation))
+		}
+		if a.Vertical != "" {
+			w.attr("vertical", a.Vertical)
+		}
+		if a.WrapText {
+			w.attrBool("wrapText", true)
+		}
+		w.buf.WriteString(`></alignment>`)
+	}
+	if p := xf.Protection; p != nil {
+		w.buf.WriteString(`<protection`)
+		w.attrBool("hidden", p.Hidden)
+		w.attrBool("locked", p.Locked)
+		w.buf.WriteString(`></protection>`)
+	}
+	w.buf.WriteString(`</xf>`)
+}
+
+// splice provides a function to write the output of encoding/xml with the
+// hand-written elements inserted after the given position of the output.
+func (w *xmlWriter) splice(output []byte, pos int, fn func()) []byte {
+	w.buf.Write(output[:pos])
+	fn()
+	w.buf.Write(output[pos:])
+	return w.buf.Bytes()
+}
+
+// marshalWorksheet provides a function to serialize the worksheet, the rows
+// of the worksheet will be serialized by the hand-written writer.
+func marshalWorksheet(ws *xlsxWorksheet) []byte {
+	var buf bytes.Buffer
+	_ = writeWorksheet(&buf, ws, nil)
+	return buf.Bytes()
+}
+
+// writeWorksheet provides a function to serialize the worksheet to the
+// writer. The output of encoding/xml without the rows will be rewritten by
+// the replace function if it isn't nil, and the rows will be serialized by
+// the hand-written writer and written to the writer in chunks, so that the
+// whole worksheet will not be kept in memory.
+func writeWorksheet(dst io.Writer, ws *xlsxWorksheet, replace func([]byte) []byte) error {
+	rows := ws.SheetData.Row
+	ws.SheetData.Row = nil
+	output, _ := xml.Marshal(ws)
+	ws.SheetData.Row = rows
+	if replace != nil {
+		output = replace(output)
+	}
+	pos := bytes.Index(output, []byte(`<sheetData></sheetData>`))
+	if pos == -1 || len(rows) == 0 {
+		_, err := dst.Write(output)
+		return err
+	}
+	w := &xmlWriter{dst: dst}
+	w.enc = xml.NewEncoder(&w.buf)
+	w.splice(output, pos+len(`<sheetData>`), func() {
+		for i := range rows {
+			w.row(&rows[i])
+			w.flush(false)
+		}
+	})
+	w.flush(true)
+	return w.err
+}
+
+// The constants for calculating the upper bound of the size of the serialized
+// worksheet and shared string table. The text may grow up to 6 times after
+// escaping, such as the tab character escaped to "&#x9;", and the XML output
+// may grow in a similar ratio after indenting. The overheads are the upper
+// bound of the length of the tags, the attributes except the text and the
+// indentation of the elements.
+const (
+	xmlGrowthRatio  = 6
+	xmlRowOverhead  = 256
+	xmlCellOverhead = 160
+	xmlSIOverhead   = 64
+	xmlRootOverhead = 64 << 10
+)
+
+// worksheetSizeLimit provides a function to calculate the upper bound of the
+// size of the worksheet serialized by writeWorksheet, without serializing
+// the rows.
+func worksheetSizeLimit(ws *xlsxWorksheet) uint64 {
+	rows := ws.SheetData.Row
+	ws.SheetData.Row = nil
+	output, _ := xml.Marshal(ws)
+	ws.SheetData.Row = rows
+	size := xmlRootOverhead + uint64(len(output))*xmlGrowthRatio
+	for i := range rows {
+		size += xmlRowOverhead + uint64(len(rows[i].Spans))*xmlGrowthRatio
+		for j := range rows[i].C {
+			c := &rows[i].C[j]
+			if space := c.XMLSpace.Name; c.IS != nil || (space.Local != "" && space.Space != NameSpaceXML) {
+				output, _ = xml.Marshal(c)
+				size += xmlCellOverhead + uint64(len(output))*xmlGrowthRatio
+				continue
+			}
+			n := len(c.XMLSpace.Value) + len(c.R) + len(c.T) + len(c.value())
+			if c.F != nil {
+				n += len(c.F.T) + len(c.F.Ref) + len(c.F.Si) + len(c.F.Content)
+			}
+			size += xmlCellOverhead + uint64(n)*xmlGrowthRatio
+		}
+	}
+	return size
+}
+
+// sharedStringsSizeLimit provides a function to calculate the upper bound of
+// the size of the shared string table serialized by writeSharedStrings.
+func sharedStringsSizeLimit(sst *xlsxSST) uint64 {
+	items := sst.SI
+	sst.SI = nil
+	output, _ := xml.Marshal(sst)
+	sst.SI = items
+	size := xmlRootOverhead + uint64(len(output))*xmlGrowthRatio
+	for i := 0; i < sst.spill.len(); i++ {
+		size += xmlSIOverhead + uint64(sst.spill.size(i))*xmlGrowthRatio
+	}
+	for i := range items {
+		si := &items[i]
+		if si.T == nil || len(si.R) > 0 || len(si.RPh) > 0 || si.PhoneticPr != nil ||
+			(si.T.Space.Name.Local != "" && si.T.Space.Name.Space != NameSpaceXML) {
+			output, _ = xml.Marshal(si)
+			size += xmlSIOverhead + uint64(len(output))*xmlGrowthRatio
+			continue
+		}
+		size += xmlSIOverhead + uint64(len(si.T.Val)+len(si.T.Space.Value))*xmlGrowthRatio
+	}
+	return size
+}
+
+// marshalSharedStrings provides a function to serialize the shared string
+// table, the string items will be serialized by the hand-written writer.
+func marshalSharedStrings(sst *xlsxSST) []byte {
+	var buf bytes.Buffer
+	_ = writeSharedStrings(&buf, sst, nil)
+	return buf.Bytes()
+}
+
+// writeSharedStrings provides a function to serialize the shared string table
+// to the writer. The output of encoding/xml without the string items will be
+// rewritten by the replace function if it isn't nil, and the string items
+// will be serialized by the hand-written writer and written to the writer in
+// chunks.
+func writeSharedStrings(dst io.Writer, sst *xlsxSST, replace func([]byte) []byte) error {
+	items := sst.SI
+	sst.SI = nil
+	output, _ := xml.Marshal(sst)
+	sst.SI = items
+	if replace != nil {
+		output = replace(output)
+	}
+	if sst.len() == 0 {
+		_, err := dst.Write(output)
+		return err
+	}
+	w := &xmlWriter{dst: dst}
+	w.enc = xml.NewEncoder(&w.buf)
+	w.splice(output, len(output)-len(`</sst>`), func() {
+		for i := 0; i < sst.spill.len(); i++ {
+			si, err := sst.spill.item(i)
+			if err != nil {
+				w.err = err
+				return
+			}
+			w.si(&si)
+			w.flush(false)
+		}
+		for i := range items {
+			w.si(&items[i])
+			w.flush(false)
+		}
+	})
+	w.flush(true)
+	return w.err
+}
+
+// marshalStyleSheet provides a function to serialize the styles, the cell
+// formatting records will be serialized by the hand-written writer.
+func marshalStyleSheet(styles *xlsxStyleSheet) []byte {
+	if styles.CellXfs == nil || len(styles.CellXfs.Xf) == 0 {
+		output, _ := xml.Marshal(styles)
+		return output
+	}
+	xfs := styles.CellXfs.Xf
+	styles.CellXfs.Xf = nil
+	output, _ := xml.Marshal(styles)
+	styles.CellXfs.Xf = xfs
+	pos := bytes.Index(output, []byte(`</cellXfs>`))
+	w := newXMLWriter(len(output) + len(xfs)*128)
+	return w.splice(output, pos, func() {
+		for i := range xfs {
+			w.xf(&xfs[i])
+		}
+	})
+}
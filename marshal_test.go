@@ -0,0 +1,162 @@
+package excelize
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalWorksheet(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	for _, sheet := range f.GetSheetList() {
+		ws, err := f.workSheetReader(sheet)
+		assert.NoError(t, err)
+		expected, err := xml.Marshal(ws)
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), string(marshalWorksheet(ws)), sheet)
+	}
+	preserve := xml.Attr{Name: xml.Name{Space: NameSpaceXML, Local: "space"}, Value: "preserve"}
+	ws := &xlsxWorksheet{SheetData: xlsxSheetData{Row: []xlsxRow{
+		{R: 1, Spans: "1:3", S: 2, CustomFormat: true, Ht: 12.75, Hidden: true, CustomHeight: true, OutlineLevel: 7, Collapsed: true, ThickTop: true, ThickBot: true, Ph: true, C: []xlsxC{
+			{R: "A1", S: 1, T: "str", XMLSpace: preserve, V: " <a> & \"b\" 'c'\t\n"},
+			{R: "B1", F: &xlsxF{Content: "SUM(A1:A2)>0", T: "shared", Ref: "B1:B2", Si: "0"}, V: "1"},
+			{R: "C1", T: "inlineStr", IS: &xlsxSI{T: &xlsxT{Val: "inline"}}},
+			{R: "D1", XMLSpace: xml.Attr{Name: xml.Name{Space: "urn:x", Local: "space"}, Value: "preserve"}},
+			{R: "E1", XMLSpace: xml.Attr{Name: xml.Name{Local: "space"}, Value: "default"}, V: "é\x01"},
+			{F: &xlsxF{}},
+		}},
+		{Ht: 1e21},
+	}}}
+	ws.SheetData.Row[0].C[1].setFloat(-1.5, 64)
+	expected, err := xml.Marshal(ws)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(marshalWorksheet(ws)))
+	ws.SheetData.Row = nil
+	expected, err = xml.Marshal(ws)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(marshalWorksheet(ws)))
+}
+
+func TestMarshalSharedStrings(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "SharedStrings.xlsx"))
+	assert.NoError(t, err)
+	sst, err := f.sharedStringsReader()
+	assert.NoError(t, err)
+	sst.SI = append(sst.SI,
+		xlsxSI{T: &xlsxT{Val: " a & b ", Space: xml.Attr{Name: xml.Name{Space: NameSpaceXML, Local: "space"}, Value: "preserve"}}},
+		xlsxSI{T: &xlsxT{Val: "x", Space: xml.Attr{Name: xml.Name{Space: "urn:x", Local: "space"}, Value: "preserve"}}},
+		xlsxSI{R: []xlsxR{{T: &xlsxT{Val: "rich"}}}},
+		xlsxSI{},
+	)
+	expected, err := xml.Marshal(sst)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(marshalSharedStrings(sst)))
+	sst = &xlsxSST{}
+	expected, err = xml.Marshal(sst)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(marshalSharedStrings(sst)))
+}
+
+func TestMarshalStyleSheet(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	styles, err := f.stylesReader()
+	assert.NoError(t, err)
+	for _, format := range []string{
+		`{"alignment":{"horizontal":"center","indent":1,"justify_last_line":true,"reading_order":1,"relative_indent":1,"shrink_to_fit":true,"text_rotation":45,"vertical":"top","wrap_text":true}}`,
+		`{"protection":{"hidden":true,"locked":false},"number_format":14,"font":{"bold":true},"fill":{"type":"pattern","color":["#FF0000"],"pattern":1}}`,
+	} {
+		_, err = f.NewStyle(format)
+		assert.NoError(t, err)
+	}
+	expected, err := xml.Marshal(styles)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(marshalStyleSheet(styles)))
+	styles.CellXfs = nil
+	expected, err = xml.Marshal(styles)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(marshalStyleSheet(styles)))
+}
+
+// benchmarkWorksheet returns a worksheet with the given number of rows for
+// the serialization benchmarks.
+func benchmarkWorksheet(b *testing.B, rows int) *xlsxWorksheet {
+	f := NewFile()
+	for row := 1; row <= rows; row++ {
+		if err := f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{row, float64(row) / 3, "text", true, fmt.Sprint(row)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return ws
+}
+
+func BenchmarkMarshalWorksheet(b *testing.B) {
+	ws := benchmarkWorksheet(b, 10000)
+	b.Run("encoding/xml", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = xml.Marshal(ws)
+		}
+	})
+	b.Run("marshalWorksheet", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = marshalWorksheet(ws)
+		}
+	})
+}
+
+func BenchmarkMarshalSharedStrings(b *testing.B) {
+	sst := &xlsxSST{}
+	for i := 0; i < 100000; i++ {
+		sst.SI = append(sst.SI, xlsxSI{T: &xlsxT{Val: fmt.Sprintf("shared string %d", i)}})
+	}
+	b.Run("encoding/xml", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = xml.Marshal(sst)
+		}
+	})
+	b.Run("marshalSharedStrings", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = marshalSharedStrings(sst)
+		}
+	})
+}
+
+func BenchmarkMarshalStyleSheet(b *testing.B) {
+	f := NewFile()
+	for i := 0; i < 1000; i++ {
+		if _, err := f.NewStyle(fmt.Sprintf(`{"alignment":{"horizontal":"center","indent":%d},"protection":{"locked":true}}`, i%16)); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := f.NewStyle(fmt.Sprintf(`{"number_format":%d,"font":{"size":%d}}`, i%50, i%40+1)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	styles, err := f.stylesReader()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("encoding/xml", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = xml.Marshal(styles)
+		}
+	})
+	b.Run("marshalStyleSheet", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = marshalStyleSheet(styles)
+		}
+	})
+}
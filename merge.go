@@ -13,6 +13,7 @@ package excelize
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -106,13 +107,23 @@ func (f *File) MergeCell(sheet, hcell, vcell string) error {
 //
 //    err := f.UnmergeCell("Sheet1", "D3", "E9")
 //
+// The vcell parameter is optional, the merged cell which covers the hcell
+// will be unmerged if it is omitted, so any cell inside the merged cell can
+// be used. For example unmerge the merged cell which covers E5 on Sheet1:
+//
+//    err := f.UnmergeCell("Sheet1", "E5")
+//
 // Attention: overlapped areas will also be unmerged.
-func (f *File) UnmergeCell(sheet string, hcell, vcell string) error {
+func (f *File) UnmergeCell(sheet string, hcell string, vcell ...string) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	rect1, err := f.areaRefToCoordinates(hcell + ":" + vcell)
+	lastCell := hcell
+	if len(vcell) > 0 {
+		lastCell = vcell[0]
+	}
+	rect1, err := f.areaRefToCoordinates(hcell + ":" + lastCell)
 	if err != nil {
 		return err
 	}
@@ -152,8 +163,16 @@ func (f *File) UnmergeCell(sheet string, hcell, vcell string) error {
 }
 
 // GetMergeCells provides a function to get all merged cells from a worksheet
-// currently.
-func (f *File) GetMergeCells(sheet string) ([]MergeCell, error) {
+// currently. The value of the top-left cell is returned with each merged
+// cell, and the style ID of the top-left cell will be returned too when the
+// optional withStyle parameter is true. For example:
+//
+//    mergeCells, err := f.GetMergeCells("Sheet1", true)
+//    for _, mergeCell := range mergeCells {
+//        fmt.Println(mergeCell.GetCellValue(), mergeCell.GetCellStyle())
+//    }
+//
+func (f *File) GetMergeCells(sheet string, withStyle ...bool) ([]MergeCell, error) {
 	var mergeCells []MergeCell
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -166,7 +185,12 @@ func (f *File) GetMergeCells(sheet string) ([]MergeCell, error) {
 			ref := ws.MergeCells.Cells[i].Ref
 			axis := strings.Split(ref, ":")[0]
 			val, _ := f.GetCellValue(sheet, axis)
-			mergeCells = append(mergeCells, []string{ref, val})
+			mergeCell := MergeCell{ref, val}
+			if len(withStyle) > 0 && withStyle[0] {
+				styleID, _ := f.GetCellStyle(sheet, axis)
+				mergeCell = append(mergeCell, strconv.Itoa(styleID))
+			}
+			mergeCells = append(mergeCells, mergeCell)
 		}
 	}
 
@@ -174,8 +198,9 @@ func (f *File) GetMergeCells(sheet string) ([]MergeCell, error) {
 }
 
 // MergeCell define a merged cell data.
-// It consists of the following structure.
-// example: []string{"D4:E10", "cell value"}
+// It consists of the following structure, the style ID of the top-left cell
+// is only included when it was requested by GetMergeCells.
+// example: []string{"D4:E10", "cell value", "1"}
 type MergeCell []string
 
 // GetCellValue returns merged cell value.
@@ -183,6 +208,16 @@ func (m *MergeCell) GetCellValue() string {
 	return (*m)[1]
 }
 
+// GetCellStyle returns the style ID of the top-left cell of the merged cell,
+// and returns 0 if the style ID wasn't requested by GetMergeCells.
+func (m *MergeCell) GetCellStyle() int {
+	if len(*m) < 3 {
+		return 0
+	}
+	styleID, _ := strconv.Atoi((*m)[2])
+	return styleID
+}
+
 // GetStartAxis returns the merge start axis.
 // example: "C2"
 func (m *MergeCell) GetStartAxis() string {
@@ -2,6 +2,7 @@ package excelize
 
 import (
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -122,6 +123,18 @@ func TestGetMergeCells(t *testing.T) {
 		assert.Equal(t, wants[i].end, m.GetEndAxis())
 	}
 
+	// Test get merged cells with the style of the top-left cells
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle(sheet1, "A4", "A4", style))
+	mergeCells, err = f.GetMergeCells(sheet1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, MergeCell{"A4:B5", "A4", strconv.Itoa(style)}, mergeCells[2])
+	assert.Equal(t, style, mergeCells[2].GetCellStyle())
+	mergeCells, err = f.GetMergeCells(sheet1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, mergeCells[2].GetCellStyle())
+
 	// Test get merged cells on not exists worksheet.
 	_, err = f.GetMergeCells("SheetN")
 	assert.EqualError(t, err, "sheet SheetN is not exist")
@@ -149,6 +162,16 @@ func TestUnmergeCell(t *testing.T) {
 
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnmergeCell.xlsx")))
 
+	// Test unmerge the merged cell by any cell inside it
+	assert.NoError(t, f.UnmergeCell(sheet1, "B9"))
+	xlsx, err = f.workSheetReader(sheet1)
+	assert.NoError(t, err)
+	assert.Len(t, xlsx.MergeCells.Cells, mergeCellNum-2)
+	for _, mergeCell := range xlsx.MergeCells.Cells {
+		assert.NotEqual(t, "A7:C10", mergeCell.Ref)
+	}
+	assert.EqualError(t, f.UnmergeCell(sheet1, "A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
 	f = NewFile()
 	assert.NoError(t, f.MergeCell("Sheet1", "A2", "B3"))
 	// Test unmerged area on not exists worksheet.
@@ -167,3 +190,53 @@ func TestUnmergeCell(t *testing.T) {
 	assert.EqualError(t, f.UnmergeCell("Sheet1", "A2", "B3"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 
 }
+
+func TestMergeCellPolicy(t *testing.T) {
+	// Test write into the merged cell with the default redirect policy
+	f := NewFile()
+	assert.NoError(t, f.MergeCell("Sheet1", "B2", "C3"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "value"))
+	val, err := f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", val)
+	style, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"#E0EBF5"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B2", style))
+	for _, cell := range []string{"A1", "B2", "C2", "B3", "C3"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, style, styleID, cell)
+	}
+
+	// Test write into the merged cell with the error policy
+	f = NewFile(Options{MergeCellPolicy: MergeCellPolicyError})
+	assert.NoError(t, f.MergeCell("Sheet1", "B2", "C3"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "value"))
+	assert.Equal(t, ErrMergedCell{Cell: "C3", Ref: "B2:C3"}, f.SetCellValue("Sheet1", "C3", "value"))
+	assert.EqualError(t, f.SetCellStyle("Sheet1", "A1", "B2", style), "A1:B2 covers a part of the merged cell B2:C3")
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "C3", style))
+	styleID, err := f.GetCellStyle("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+
+	// Test write into the merged cell with the unmerge policy
+	f = NewFile(Options{MergeCellPolicy: MergeCellPolicyUnmerge})
+	assert.NoError(t, f.MergeCell("Sheet1", "B2", "C3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "E2", "F3"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "value"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "F3", "G4", style))
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 0)
+	val, err = f.GetCellValue("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", val)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestMergeCellPolicy.xlsx")))
+
+	// Test write into the merged cell with invalid merged cell reference
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.MergeCells = &xlsxMergeCells{Cells: []*xlsxMergeCell{{Ref: "A:A"}}}
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A1", "value"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetCellStyle("Sheet1", "A1", "A1", style), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
@@ -0,0 +1,315 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Source namespaces and media type of the OpenDocument Spreadsheet.
+const (
+	MediaTypeODS     = "application/vnd.oasis.opendocument.spreadsheet"
+	NameSpaceODSFo   = "urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"
+	NameSpaceODSMani = "urn:oasis:names:tc:opendocument:xmlns:manifest:1.0"
+	NameSpaceODSOff  = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	NameSpaceODSSty  = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	NameSpaceODSTab  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	NameSpaceODSText = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+// WriteODS provides a function to write the workbook to the writer in the
+// OpenDocument Spreadsheet (.ods) format. The values of the cells, the
+// fills, fonts, borders and alignment of the cell styles and the merged
+// cells of all worksheets are written, the values of the formulas are
+// written as the cached values without the formulas, and the other parts of
+// the workbook such as charts and pictures are not supported. The workbook
+// will be written in this format by SaveAs too if the extension of the file
+// name is ".ods". For example:
+//
+//    var buf bytes.Buffer
+//    if err := f.WriteODS(&buf); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) WriteODS(w io.Writer) error {
+	return f.writeODS(context.Background(), w)
+}
+
+// writeODS provides a function to write the workbook to the writer in the
+// OpenDocument Spreadsheet format with the context.
+func (f *File) writeODS(ctx context.Context, w io.Writer) error {
+	var (
+		body   bytes.Buffer
+		styles = map[int]string{}
+	)
+	for _, sheet := range f.GetSheetList() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := f.writeODSTable(&body, sheet, styles); err != nil {
+			return err
+		}
+	}
+	zw := zip.NewWriter(w)
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(mimetype, MediaTypeODS); err != nil {
+		return err
+	}
+	for _, part := range []struct {
+		name    string
+		content []byte
+	}{
+		{"META-INF/manifest.xml", []byte(XMLHeader + `<manifest:manifest xmlns:manifest="` + NameSpaceODSMani + `" manifest:version="1.2">` +
+			`<manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="` + MediaTypeODS + `"/>` +
+			`<manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>` +
+			`<manifest:file-entry manifest:full-path="styles.xml" manifest:media-type="text/xml"/>` +
+			`</manifest:manifest>`)},
+		{"styles.xml", []byte(XMLHeader + `<office:document-styles xmlns:office="` + NameSpaceODSOff + `" xmlns:style="` + NameSpaceODSSty +
+			`" xmlns:fo="` + NameSpaceODSFo + `" office:version="1.2"><office:styles><style:default-style style:family="table-cell">` +
+			f.getODSStyleProperties(0) + `</style:default-style></office:styles></office:document-styles>`)},
+		{"content.xml", f.getODSContent(body.Bytes(), styles)},
+	} {
+		fw, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err = fw.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// getODSContent provides a function to get the content.xml part of the
+// OpenDocument Spreadsheet by given tables and the names of the automatic
+// cell styles used by them.
+func (f *File) getODSContent(body []byte, styles map[int]string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(XMLHeader + `<office:document-content xmlns:office="` + NameSpaceODSOff + `" xmlns:style="` + NameSpaceODSSty +
+		`" xmlns:text="` + NameSpaceODSText + `" xmlns:table="` + NameSpaceODSTab + `" xmlns:fo="` + NameSpaceODSFo +
+		`" office:version="1.2"><office:automatic-styles>`)
+	styleIDs := make([]int, 0, len(styles))
+	for styleID := range styles {
+		styleIDs = append(styleIDs, styleID)
+	}
+	sort.Ints(styleIDs)
+	for _, styleID := range styleIDs {
+		fmt.Fprintf(&buf, `<style:style style:name="%s" style:family="table-cell">%s</style:style>`, styles[styleID], f.getODSStyleProperties(styleID))
+	}
+	buf.WriteString(`</office:automatic-styles><office:body><office:spreadsheet>`)
+	buf.Write(body)
+	buf.WriteString(`</office:spreadsheet></office:body></office:document-content>`)
+	return buf.Bytes()
+}
+
+// writeODSTable provides a function to write the worksheet to the buffer as
+// the table of the OpenDocument Spreadsheet by given worksheet name and the
+// names of the automatic cell styles, the names of the new styles used by
+// the worksheet will be added to the styles.
+func (f *File) writeODSTable(buf *bytes.Buffer, sheet string, styles map[int]string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	cells, err := f.getExportCells(ws, true)
+	if err != nil {
+		return err
+	}
+	area, _ := getExportArea("", cells, true)
+	area, spans, covered, err := getExportMergeCells(ws, cells, area, true)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(buf, `<table:table table:name="%s">`, escapeODSText(sheet))
+	if area == nil {
+		buf.WriteString(`<table:table-row><table:table-cell/></table:table-row></table:table>`)
+		return err
+	}
+	for row := area[1]; row <= area[3]; row++ {
+		buf.WriteString("<table:table-row>")
+		for col := area[0]; col <= area[2]; col++ {
+			cell := [2]int{col, row}
+			if covered[cell] {
+				buf.WriteString("<table:covered-table-cell/>")
+				continue
+			}
+			buf.WriteString("<table:table-cell")
+			if c := cells[cell]; c.StyleID != 0 {
+				if _, ok := styles[c.StyleID]; !ok {
+					styles[c.StyleID] = "ce" + strconv.Itoa(len(styles)+1)
+				}
+				fmt.Fprintf(buf, ` table:style-name="%s"`, styles[c.StyleID])
+			}
+			if span, ok := spans[cell]; ok {
+				fmt.Fprintf(buf, ` table:number-columns-spanned="%d" table:number-rows-spanned="%d"`, span[0], span[1])
+			}
+			buf.WriteString(f.getODSCellValue(cells[cell]))
+		}
+		buf.WriteString("</table:table-row>")
+	}
+	buf.WriteString("</table:table>")
+	return err
+}
+
+// getODSCellValue provides a function to get the value attributes and the
+// paragraphs of the formatted value of the table cell by given raw cell.
+func (f *File) getODSCellValue(cell exportCell) string {
+	if cell.Value == "" {
+		return "/>"
+	}
+	attrs, text := "", f.formattedValue(cell.StyleID, cell.Value)
+	switch cell.Type {
+	case "b":
+		attrs = fmt.Sprintf(` office:value-type="boolean" office:boolean-value="%t"`, cell.Value == "1")
+		text = strings.ToUpper(strconv.FormatBool(cell.Value == "1"))
+	case "s", "str", "inlineStr", "e":
+		attrs = ` office:value-type="string"`
+	default:
+		attrs = fmt.Sprintf(` office:value-type="float" office:value="%s"`, escapeODSText(cell.Value))
+		if num, err := strconv.ParseFloat(cell.Value, 64); err == nil && f.isDateStyle(cell.StyleID) {
+			if t, err := ExcelDateToTime(num, f.date1904()); err == nil {
+				attrs = fmt.Sprintf(` office:value-type="date" office:date-value="%s"`, t.Format("2006-01-02T15:04:05"))
+			}
+		}
+	}
+	var paragraphs string
+	for _, line := range strings.Split(text, "\n") {
+		paragraphs += "<text:p>" + escapeODSText(line) + "</text:p>"
+	}
+	return attrs + ">" + paragraphs + "</table:table-cell>"
+}
+
+// getODSStyleProperties provides a function to convert the fill, font,
+// border and alignment of the cell style to the properties of the cell style
+// of the OpenDocument Spreadsheet by given style ID.
+func (f *File) getODSStyleProperties(styleID int) string {
+	s, _ := f.stylesReader()
+	if s.CellXfs == nil || styleID < 0 || styleID >= len(s.CellXfs.Xf) {
+		return ""
+	}
+	var (
+		xf                    = s.CellXfs.Xf[styleID]
+		cell, paragraph, text []string
+	)
+	if xf.FillID != nil && s.Fills != nil && *xf.FillID < len(s.Fills.Fill) {
+		fill := s.Fills.Fill[*xf.FillID]
+		var color string
+		if fill.PatternFill != nil && fill.PatternFill.PatternType != "" && fill.PatternFill.PatternType != "none" {
+			color = f.getHexColor(&fill.PatternFill.FgColor)
+		}
+		if fill.GradientFill != nil && len(fill.GradientFill.Stop) > 0 {
+			color = f.getHexColor(&fill.GradientFill.Stop[0].Color)
+		}
+		if color != "" {
+			cell = append(cell, `fo:background-color="`+color+`"`)
+		}
+	}
+	if xf.BorderID != nil && s.Borders != nil && *xf.BorderID < len(s.Borders.Border) {
+		border := s.Borders.Border[*xf.BorderID]
+		for _, line := range []struct {
+			side string
+			line xlsxLine
+		}{
+			{"left", border.Left}, {"right", border.Right}, {"top", border.Top}, {"bottom", border.Bottom},
+		} {
+			style, ok := htmlBorderStyles[line.line.Style]
+			if !ok {
+				continue
+			}
+			color := f.getHexColor(line.line.Color)
+			if color == "" {
+				color = "#000000"
+			}
+			cell = append(cell, fmt.Sprintf(`fo:border-%s="%s %s"`, line.side, style, color))
+		}
+	}
+	if xf.Alignment != nil {
+		if align, ok := map[string]string{
+			"left":             "start",
+			"center":           "center",
+			"right":            "end",
+			"fill":             "start",
+			"justify":          "justify",
+			"centerContinuous": "center",
+			"distributed":      "justify",
+		}[xf.Alignment.Horizontal]; ok {
+			paragraph = append(paragraph, `fo:text-align="`+align+`"`)
+		}
+		if align, ok := map[string]string{
+			"top":         "top",
+			"center":      "middle",
+			"bottom":      "bottom",
+			"justify":     "middle",
+			"distributed": "middle",
+		}[xf.Alignment.Vertical]; ok {
+			cell = append(cell, `style:vertical-align="`+align+`"`)
+		}
+		if xf.Alignment.WrapText {
+			cell = append(cell, `fo:wrap-option="wrap"`)
+		}
+	}
+	if xf.FontID != nil && s.Fonts != nil && *xf.FontID < len(s.Fonts.Font) {
+		font := s.Fonts.Font[*xf.FontID]
+		if font.Name != nil && font.Name.Val != nil {
+			text = append(text, `fo:font-family="`+escapeODSText(*font.Name.Val)+`"`)
+		}
+		if font.Sz != nil && font.Sz.Val != nil {
+			text = append(text, `fo:font-size="`+strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64)+`pt"`)
+		}
+		if font.B != nil && *font.B {
+			text = append(text, `fo:font-weight="bold"`)
+		}
+		if font.I != nil && *font.I {
+			text = append(text, `fo:font-style="italic"`)
+		}
+		if font.U != nil && (font.U.Val == nil || *font.U.Val != "none") {
+			text = append(text, `style:text-underline-style="solid" style:text-underline-width="auto" style:text-underline-color="font-color"`)
+		}
+		if font.Strike != nil && *font.Strike {
+			text = append(text, `style:text-line-through-style="solid"`)
+		}
+		if color := f.getHexColor(font.Color); color != "" {
+			text = append(text, `fo:color="`+color+`"`)
+		}
+	}
+	var properties string
+	for _, props := range []struct {
+		element string
+		attrs   []string
+	}{
+		{"style:table-cell-properties", cell}, {"style:paragraph-properties", paragraph}, {"style:text-properties", text},
+	} {
+		if len(props.attrs) > 0 {
+			properties += "<" + props.element + " " + strings.Join(props.attrs, " ") + "/>"
+		}
+	}
+	return properties
+}
+
+// escapeODSText provides a function to escape the text for the content and
+// attribute values of the OpenDocument Spreadsheet.
+func escapeODSText(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
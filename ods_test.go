@@ -0,0 +1,77 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteODS(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Name & <Note>", "B1": 1.5, "C1": true, "A2": "line\nbreak",
+		"B2": time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "B3": "merged",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	style, err := f.NewStyle(`{"fill":{"type":"pattern","color":["#FFFF00"],"pattern":1},"font":{"bold":true,"italic":true,"underline":"single","strike":true,"color":"#FF0000","family":"Arial","size":12},"border":[{"type":"left","color":"0000FF","style":1}],"alignment":{"horizontal":"right","vertical":"center","wrap_text":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "B3", "C4"))
+	f.NewSheet("Sheet 2")
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.WriteODS(&buf))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	parts := map[string]string{}
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		assert.NoError(t, err)
+		content, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.NoError(t, rc.Close())
+		parts[file.Name] = string(content)
+	}
+	assert.Equal(t, "mimetype", zr.File[0].Name)
+	assert.Equal(t, zip.Store, zr.File[0].Method)
+	assert.Equal(t, MediaTypeODS, parts["mimetype"])
+	assert.Contains(t, parts["META-INF/manifest.xml"], `manifest:full-path="content.xml"`)
+	assert.Contains(t, parts["styles.xml"], `<style:default-style style:family="table-cell"><style:text-properties fo:font-family="Calibri" fo:font-size="11pt" fo:color="#000000"/></style:default-style>`)
+	content := parts["content.xml"]
+	for _, expected := range []string{
+		`<style:style style:name="ce1" style:family="table-cell"><style:table-cell-properties fo:background-color="#FFFF00" fo:border-left="1px solid #0000FF" style:vertical-align="middle" fo:wrap-option="wrap"/><style:paragraph-properties fo:text-align="end"/>`,
+		`fo:font-family="Arial" fo:font-size="12pt" fo:font-weight="bold" fo:font-style="italic" style:text-underline-style="solid"`,
+		`<table:table table:name="Sheet1"><table:table-row>` +
+			`<table:table-cell table:style-name="ce1" office:value-type="string"><text:p>Name &amp; &lt;Note&gt;</text:p></table:table-cell>` +
+			`<table:table-cell office:value-type="float" office:value="1.5"><text:p>1.5</text:p></table:table-cell>` +
+			`<table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell></table:table-row>`,
+		`<table:table-cell office:value-type="string"><text:p>line</text:p><text:p>break</text:p></table:table-cell>`,
+		`office:value-type="date" office:date-value="2021-01-01T00:00:00"><text:p>1/1/21 12:00</text:p>`,
+		`<table:table-row><table:table-cell/><table:table-cell table:number-columns-spanned="2" table:number-rows-spanned="2" office:value-type="string"><text:p>merged</text:p></table:table-cell><table:covered-table-cell/></table:table-row>` +
+			`<table:table-row><table:table-cell/><table:covered-table-cell/><table:covered-table-cell/></table:table-row></table:table>`,
+		`<table:table table:name="Sheet 2"><table:table-row><table:table-cell/></table:table-row></table:table>`,
+	} {
+		assert.Contains(t, content, expected)
+	}
+
+	// Test save as the OpenDocument Spreadsheet by the extension
+	path := filepath.Join("test", "TestWriteODS.ods")
+	assert.NoError(t, f.SaveAs(path))
+	file, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(file[30:]), "mimetype"+MediaTypeODS))
+
+	// Test write with canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.EqualError(t, f.writeODS(ctx, &buf), context.Canceled.Error())
+	assert.Equal(t, "", f.getODSStyleProperties(-1))
+}
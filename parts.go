@@ -0,0 +1,133 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// knownContentTypes defined the content types of the parts which are modelled
+// by the library, the other parts will be kept byte-for-byte when saving the
+// spreadsheet.
+var knownContentTypes = map[string]bool{
+	ContentTypeActiveX:                               true,
+	ContentTypeCustomProperties:                      true,
+	ContentTypeCustomXMLProperties:                   true,
+	ContentTypeDigitalSignatureOrigin:                true,
+	ContentTypeDigitalSignatureXML:                   true,
+	ContentTypeDrawing:                               true,
+	ContentTypeDrawingML:                             true,
+	ContentTypeMacro:                                 true,
+	ContentTypePrinterSettings:                       true,
+	ContentTypeRelationships:                         true,
+	ContentTypeSheetML:                               true,
+	ContentTypeSpreadSheetMLChartsheet:               true,
+	ContentTypeSpreadSheetMLComments:                 true,
+	ContentTypeSpreadSheetMLPivotCacheDefinition:     true,
+	ContentTypeSpreadSheetMLPivotCacheRecords:        true,
+	ContentTypeSpreadSheetMLPivotTable:               true,
+	ContentTypeSpreadSheetMLSharedStrings:            true,
+	ContentTypeSpreadSheetMLTable:                    true,
+	ContentTypeSpreadSheetMLWorksheet:                true,
+	ContentTypeTemplate:                              true,
+	ContentTypeTemplateMacro:                         true,
+	ContentTypeTimeline:                              true,
+	ContentTypeTimelineCache:                         true,
+	ContentTypeVBA:                                   true,
+	ContentTypeVML:                                   true,
+	"application/vnd.ms-excel.controlproperties+xml": true,
+	"application/vnd.ms-office.activeX+xml":          true,
+	"application/vnd.ms-office.vbaProjectSignature":  true,
+	"application/vnd.openxmlformats-officedocument.extended-properties+xml":       true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.calcChain+xml":   true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.connections+xml": true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml":      true,
+	"application/vnd.openxmlformats-officedocument.theme+xml":                     true,
+	"application/vnd.openxmlformats-package.core-properties+xml":                  true,
+}
+
+// ListUnknownParts provides a function to get the paths of the parts which
+// are not modelled by the library in the spreadsheet, such as the add-in
+// data, ink and 3D models. The paths will be sorted, and these parts will be
+// kept byte-for-byte when saving the spreadsheet. For example, print the
+// size of the unknown parts:
+//
+//    for _, part := range f.ListUnknownParts() {
+//        content, err := f.GetPartBytes(part)
+//        if err != nil {
+//            fmt.Println(err)
+//            return
+//        }
+//        fmt.Println(part, len(content))
+//    }
+//
+func (f *File) ListUnknownParts() []string {
+	parts := []string{}
+	for partPath := range f.XLSX {
+		if f.isUnknownPart(partPath) {
+			parts = append(parts, partPath)
+		}
+	}
+	sort.Strings(parts)
+	return parts
+}
+
+// GetPartBytes provides a function to get the raw content of the part by
+// given part path, such as "xl/model3d/model3d1.glb". The content of the
+// parts which are modelled by the library will not include the changes which
+// have not been saved.
+func (f *File) GetPartBytes(partPath string) ([]byte, error) {
+	partPath = strings.TrimPrefix(partPath, "/")
+	if _, ok := f.XLSX[partPath]; !ok {
+		return nil, ErrPartNotExist{Part: partPath}
+	}
+	content, err := f.readBytes(partPath)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{}, content...), nil
+}
+
+// SetPartBytes provides a function to replace the raw content of the unknown
+// part by given part path and content, the parts which are modelled by the
+// library can't be replaced by this function. The content will be written to
+// the spreadsheet as is when saving it.
+func (f *File) SetPartBytes(partPath string, content []byte) error {
+	partPath = strings.TrimPrefix(partPath, "/")
+	if _, ok := f.XLSX[partPath]; !ok {
+		return ErrPartNotExist{Part: partPath}
+	}
+	if !f.isUnknownPart(partPath) {
+		return fmt.Errorf("part %s is modelled by the library and can't be replaced", partPath)
+	}
+	f.XLSX[partPath] = append([]byte{}, content...)
+	delete(f.lazyParts, partPath)
+	return nil
+}
+
+// isUnknownPart provides a function to check if the part is not modelled by
+// the library by given part path. The parts without the content type or with
+// the generic XML content type can't be identified, and will be treated as
+// the known parts.
+func (f *File) isUnknownPart(partPath string) bool {
+	if partPath == "[Content_Types].xml" || strings.HasPrefix(partPath, "customXml/item") {
+		return false
+	}
+	switch contentType := f.getPartContentType(partPath); {
+	case contentType == "", contentType == "application/xml", strings.HasPrefix(contentType, "image/"):
+		return false
+	default:
+		return !knownContentTypes[contentType]
+	}
+}
@@ -0,0 +1,69 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownParts(t *testing.T) {
+	f := NewFile(Options{PrettyXML: true, Strict: true})
+	assert.Equal(t, []string{}, f.ListUnknownParts())
+
+	ink := []byte(`<inkml:ink xmlns:inkml="http://www.w3.org/2003/InkML"><inkml:trace xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">0 0, 10 10</inkml:trace></inkml:ink>`)
+	model := []byte{0x67, 0x6c, 0x54, 0x46, 0x02, 0x00, 0x00, 0x00}
+	content, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	content.Overrides = append(content.Overrides, xlsxOverride{PartName: "/xl/ink/ink1.xml", ContentType: "application/inkml+xml"})
+	content.Defaults = append(content.Defaults, xlsxDefault{Extension: "glb", ContentType: "model/gltf-binary"})
+	f.XLSX["xl/ink/ink1.xml"], f.XLSX["xl/model3d/model3d1.glb"] = ink, model
+	assert.Equal(t, []string{"xl/ink/ink1.xml", "xl/model3d/model3d1.glb"}, f.ListUnknownParts())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnknownParts.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestUnknownParts.xlsx"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"xl/ink/ink1.xml", "xl/model3d/model3d1.glb"}, f.ListUnknownParts())
+	for partPath, expected := range map[string][]byte{"xl/ink/ink1.xml": ink, "/xl/model3d/model3d1.glb": model} {
+		part, err := f.GetPartBytes(partPath)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, part, partPath)
+	}
+
+	// Test replace the unknown part
+	model = append(model, 0x01)
+	assert.NoError(t, f.SetPartBytes("xl/model3d/model3d1.glb", model))
+	model[8] = 0x02
+	part, err := f.GetPartBytes("xl/model3d/model3d1.glb")
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0x01), part[8])
+	part, err = f.GetPartBytes("xl/workbook.xml")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, part)
+
+	// Test get and set the parts with invalid paths
+	_, err = f.GetPartBytes("xl/model3d/model3d2.glb")
+	assert.EqualError(t, err, "part xl/model3d/model3d2.glb is not exist")
+	assert.EqualError(t, f.SetPartBytes("xl/model3d/model3d2.glb", model), "part xl/model3d/model3d2.glb is not exist")
+	assert.EqualError(t, f.SetPartBytes("xl/workbook.xml", model), "part xl/workbook.xml is modelled by the library and can't be replaced")
+	assert.NoError(t, f.Close())
+
+	// Test round-trip the unknown part with the Strict namespaces
+	f = NewFile()
+	ink = []byte(`<inkml:ink xmlns:inkml="http://www.w3.org/2003/InkML"><inkml:trace xmlns:r="http://purl.oclc.org/ooxml/officeDocument/relationships" xmlns:x="http://purl.oclc.org/ooxml/spreadsheetml/main">0 0, 10 10</inkml:trace></inkml:ink>`)
+	content, err = f.contentTypesReader()
+	assert.NoError(t, err)
+	content.Overrides = append(content.Overrides, xlsxOverride{PartName: "/xl/ink/ink1.xml", ContentType: "application/inkml+xml"})
+	f.XLSX["xl/ink/ink1.xml"] = ink
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Equal(t, string(ink), readZipPart(t, buf.Bytes(), "xl/ink/ink1.xml"))
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	part, err = f.GetPartBytes("xl/ink/ink1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, ink, part)
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Equal(t, string(ink), readZipPart(t, buf.Bytes(), "xl/ink/ink1.xml"))
+}
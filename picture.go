@@ -16,7 +16,6 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"errors"
-	"fmt"
 	"image"
 	"io"
 	"io/ioutil"
@@ -28,9 +27,10 @@ import (
 )
 
 // parseFormatPictureSet provides a function to parse the format settings of
-// the picture with default value.
-func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
-	format := formatPicture{
+// the picture by given JSON string or PictureFormat structure pointer with
+// default value.
+func parseFormatPictureSet(formatSet interface{}) (*PictureFormat, error) {
+	format := PictureFormat{
 		FPrintsWithSheet: true,
 		FLocksWithSheet:  false,
 		NoChangeAspect:   false,
@@ -40,13 +40,58 @@ func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
 		XScale:           1.0,
 		YScale:           1.0,
 	}
-	err := json.Unmarshal(parseFormatSet(formatSet), &format)
-	return &format, err
+	var err error
+	switch v := formatSet.(type) {
+	case nil:
+	case string:
+		err = json.Unmarshal(parseFormatSet(v), &format)
+	case *PictureFormat:
+		if v != nil {
+			format = *v
+			format.setDefaults()
+		}
+	default:
+		err = errors.New("invalid parameter type")
+	}
+	if err != nil {
+		return &format, err
+	}
+	return &format, format.validate()
+}
+
+// setDefaults provides a function to set the default value of the format
+// settings of the picture which are omitted in the PictureFormat structure.
+func (p *PictureFormat) setDefaults() {
+	if p.XScale == 0 {
+		p.XScale = 1.0
+	}
+	if p.YScale == 0 {
+		p.YScale = 1.0
+	}
+}
+
+// validate provides a function to check the format settings of the picture.
+func (p *PictureFormat) validate() error {
+	if p.XScale <= 0 || p.YScale <= 0 {
+		return errors.New("parameter 'XScale' and 'YScale' must be greater than 0")
+	}
+	switch p.HyperlinkType {
+	case "", "External", "Location":
+	default:
+		return errors.New("parameter 'HyperlinkType' must be 'External' or 'Location'")
+	}
+	switch p.Positioning {
+	case "", "oneCell", "twoCell", "absolute":
+	default:
+		return errors.New("parameter 'Positioning' must be 'oneCell', 'twoCell' or 'absolute'")
+	}
+	return nil
 }
 
 // AddPicture provides the method to add picture in a sheet by given picture
 // format set (such as offset, scale, aspect ratio setting and print settings)
-// and file path. For example:
+// and file path, the format set can be the JSON string or the PictureFormat
+// structure pointer. For example:
 //
 //    package main
 //
@@ -85,7 +130,21 @@ func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
 // spreadsheet, "oneCell" (Move but don't size with cells) or "absolute"
 // (Don't move or size with cells). If you don't set this parameter, default
 // positioning is move and size with cells.
-func (f *File) AddPicture(sheet, cell, picture, format string) error {
+//
+// The format set can also be specified by the PictureFormat structure, the
+// zero values of the XScale and YScale will be replaced by 1, and the nil
+// format set is the same as the empty JSON string. Note that the
+// FPrintsWithSheet is false by default in the PictureFormat structure. For
+// example, insert a picture scaling in the cell with location hyperlink:
+//
+//    err := f.AddPicture("Sheet1", "D2", "image.png", &excelize.PictureFormat{
+//        XScale:        0.5,
+//        YScale:        0.5,
+//        Hyperlink:     "#Sheet2!D8",
+//        HyperlinkType: "Location",
+//    })
+//
+func (f *File) AddPicture(sheet, cell, picture string, format interface{}) error {
 	var err error
 	// Check picture exists first.
 	if _, err = os.Stat(picture); os.IsNotExist(err) {
@@ -102,7 +161,9 @@ func (f *File) AddPicture(sheet, cell, picture, format string) error {
 
 // AddPictureFromBytes provides the method to add picture in a sheet by given
 // picture format set (such as offset, scale, aspect ratio setting and print
-// settings), file base name, extension name and file bytes. For example:
+// settings), file base name, extension name and file bytes. The format set
+// can be the JSON string or the PictureFormat structure pointer, see
+// AddPicture for details. For example:
 //
 //    package main
 //
@@ -129,7 +190,7 @@ func (f *File) AddPicture(sheet, cell, picture, format string) error {
 //        }
 //    }
 //
-func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string, file []byte) error {
+func (f *File) AddPictureFromBytes(sheet, cell string, format interface{}, name, extension string, file []byte) error {
 	var drawingHyperlinkRID int
 	var hyperlinkType string
 	ext, ok := supportImageTypes[extension]
@@ -181,7 +242,7 @@ func (f *File) deleteSheetRelationships(sheet, rID string) {
 		name = strings.ToLower(sheet) + ".xml"
 	}
 	var rels = "xl/worksheets/_rels/" + strings.TrimPrefix(name, "xl/worksheets/") + ".rels"
-	sheetRels := f.relsReader(rels)
+	sheetRels, _ := f.relsReader(rels)
 	if sheetRels == nil {
 		sheetRels = &xlsxRelationships{}
 	}
@@ -243,7 +304,7 @@ func (f *File) countDrawings() int {
 // addDrawingPicture provides a function to add picture by given sheet,
 // drawingXML, cell, file name, width, height relationship index and format
 // sets.
-func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, height, rID, hyperlinkRID int, formatSet *formatPicture) error {
+func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, height, rID, hyperlinkRID int, formatSet *PictureFormat) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -261,7 +322,10 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, he
 	row--
 	colStart, rowStart, colEnd, rowEnd, x2, y2 :=
 		f.positionObjectPixels(sheet, col, row, formatSet.OffsetX, formatSet.OffsetY, width, height)
-	content, cNvPrID := f.drawingParser(drawingXML)
+	content, cNvPrID, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return err
+	}
 	twoCellAnchor := xdrCellAnchor{}
 	twoCellAnchor.EditAs = formatSet.Positioning
 	from := xlsxFrom{}
@@ -335,7 +399,7 @@ func (f *File) addMedia(file []byte, ext string) string {
 // type for relationship parts and the Main Document part.
 func (f *File) setContentTypePartImageExtensions() {
 	var imageTypes = map[string]bool{"jpeg": false, "png": false, "gif": false, "tiff": false}
-	content := f.contentTypesReader()
+	content, _ := f.contentTypesReader()
 	for _, v := range content.Defaults {
 		_, ok := imageTypes[v.Extension]
 		if ok {
@@ -356,7 +420,7 @@ func (f *File) setContentTypePartImageExtensions() {
 // for relationship parts and the Main Document part.
 func (f *File) setContentTypePartVMLExtensions() {
 	vml := false
-	content := f.contentTypesReader()
+	content, _ := f.contentTypesReader()
 	for _, v := range content.Defaults {
 		if v.Extension == "vml" {
 			vml = true
@@ -378,30 +442,36 @@ func (f *File) addContentTypePart(index int, contentType string) {
 		"drawings": f.setContentTypePartImageExtensions,
 	}
 	partNames := map[string]string{
-		"chart":         "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
-		"chartsheet":    "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
-		"comments":      "/xl/comments" + strconv.Itoa(index) + ".xml",
-		"drawings":      "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
-		"table":         "/xl/tables/table" + strconv.Itoa(index) + ".xml",
-		"pivotTable":    "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
-		"pivotCache":    "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
-		"sharedStrings": "/xl/sharedStrings.xml",
+		"chart":             "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
+		"chartsheet":        "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
+		"comments":          "/xl/comments" + strconv.Itoa(index) + ".xml",
+		"drawings":          "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
+		"table":             "/xl/tables/table" + strconv.Itoa(index) + ".xml",
+		"pivotTable":        "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
+		"pivotCache":        "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
+		"pivotCacheRecords": "/xl/pivotCache/pivotCacheRecords" + strconv.Itoa(index) + ".xml",
+		"timeline":          "/xl/timelines/timeline" + strconv.Itoa(index) + ".xml",
+		"timelineCache":     "/xl/timelineCaches/timelineCache" + strconv.Itoa(index) + ".xml",
+		"sharedStrings":     "/xl/sharedStrings.xml",
 	}
 	contentTypes := map[string]string{
-		"chart":         ContentTypeDrawingML,
-		"chartsheet":    ContentTypeSpreadSheetMLChartsheet,
-		"comments":      ContentTypeSpreadSheetMLComments,
-		"drawings":      ContentTypeDrawing,
-		"table":         ContentTypeSpreadSheetMLTable,
-		"pivotTable":    ContentTypeSpreadSheetMLPivotTable,
-		"pivotCache":    ContentTypeSpreadSheetMLPivotCacheDefinition,
-		"sharedStrings": ContentTypeSpreadSheetMLSharedStrings,
+		"chart":             ContentTypeDrawingML,
+		"chartsheet":        ContentTypeSpreadSheetMLChartsheet,
+		"comments":          ContentTypeSpreadSheetMLComments,
+		"drawings":          ContentTypeDrawing,
+		"table":             ContentTypeSpreadSheetMLTable,
+		"pivotTable":        ContentTypeSpreadSheetMLPivotTable,
+		"pivotCache":        ContentTypeSpreadSheetMLPivotCacheDefinition,
+		"pivotCacheRecords": ContentTypeSpreadSheetMLPivotCacheRecords,
+		"timeline":          ContentTypeTimeline,
+		"timelineCache":     ContentTypeTimelineCache,
+		"sharedStrings":     ContentTypeSpreadSheetMLSharedStrings,
 	}
 	s, ok := setContentType[contentType]
 	if ok {
 		s()
 	}
-	content := f.contentTypesReader()
+	content, _ := f.contentTypesReader()
 	for _, v := range content.Overrides {
 		if v.PartName == partNames[contentType] {
 			return
@@ -422,7 +492,7 @@ func (f *File) getSheetRelationshipsTargetByID(sheet, rID string) string {
 		name = strings.ToLower(sheet) + ".xml"
 	}
 	var rels = "xl/worksheets/_rels/" + strings.TrimPrefix(name, "xl/worksheets/") + ".rels"
-	sheetRels := f.relsReader(rels)
+	sheetRels, _ := f.relsReader(rels)
 	if sheetRels == nil {
 		sheetRels = &xlsxRelationships{}
 	}
@@ -510,14 +580,16 @@ func (f *File) getPicture(row, col int, drawingXML, drawingRelationships string)
 		deTwoCellAnchor *decodeTwoCellAnchor
 	)
 
-	wsDr, _ = f.drawingParser(drawingXML)
+	if wsDr, _, err = f.drawingParser(drawingXML); err != nil {
+		return
+	}
 	if ret, buf = f.getPictureFromWsDr(row, col, drawingRelationships, wsDr); len(buf) > 0 {
 		return
 	}
 	deWsDr = new(decodeWsDr)
 	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(drawingXML)))).
 		Decode(deWsDr); err != nil && err != io.EOF {
-		err = fmt.Errorf("xml decode error: %s", err)
+		err = f.newXMLDecodeError(drawingXML, err)
 		return
 	}
 	err = nil
@@ -525,7 +597,7 @@ func (f *File) getPicture(row, col int, drawingXML, drawingRelationships string)
 		deTwoCellAnchor = new(decodeTwoCellAnchor)
 		if err = f.xmlNewDecoder(strings.NewReader("<decodeTwoCellAnchor>" + anchor.Content + "</decodeTwoCellAnchor>")).
 			Decode(deTwoCellAnchor); err != nil && err != io.EOF {
-			err = fmt.Errorf("xml decode error: %s", err)
+			err = f.newXMLDecodeError(drawingXML, err)
 			return
 		}
 		if err = nil; deTwoCellAnchor.From != nil && deTwoCellAnchor.Pic != nil {
@@ -570,7 +642,7 @@ func (f *File) getPictureFromWsDr(row, col int, drawingRelationships string, wsD
 // from xl/drawings/_rels/drawing%s.xml.rels by given file name and
 // relationship ID.
 func (f *File) getDrawingRelationships(rels, rID string) *xlsxRelationship {
-	if drawingRels := f.relsReader(rels); drawingRels != nil {
+	if drawingRels, _ := f.relsReader(rels); drawingRels != nil {
 		for _, v := range drawingRels.Relationships {
 			if v.ID == rID {
 				return &v
@@ -592,7 +664,7 @@ func (f *File) drawingsWriter() {
 }
 
 // drawingResize calculate the height and width after resizing.
-func (f *File) drawingResize(sheet string, cell string, width, height float64, formatSet *formatPicture) (w, h, c, r int, err error) {
+func (f *File) drawingResize(sheet string, cell string, width, height float64, formatSet *PictureFormat) (w, h, c, r int, err error) {
 	var mergeCells []MergeCell
 	mergeCells, err = f.GetMergeCells(sheet)
 	if err != nil {
@@ -182,6 +182,33 @@ func TestAddPictureFromBytes(t *testing.T) {
 	assert.EqualError(t, f.AddPictureFromBytes("SheetN", fmt.Sprint("A", 1), "", "logo", ".png", imgFile), "sheet SheetN is not exist")
 }
 
+func TestAddPictureWithStructure(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), nil))
+	assert.NoError(t, f.AddPicture("Sheet1", "F21", filepath.Join("test", "images", "excel.jpg"), &PictureFormat{
+		XScale: 0.5, OffsetX: 10, Hyperlink: "#Sheet2!D8", HyperlinkType: "Location", Positioning: "oneCell",
+	}))
+	// Test the default values of the picture format structure.
+	formatSet, err := parseFormatPictureSet(&PictureFormat{})
+	assert.NoError(t, err)
+	assert.Equal(t, PictureFormat{XScale: 1, YScale: 1}, *formatSet)
+	formatSet, err = parseFormatPictureSet(nil)
+	assert.NoError(t, err)
+	assert.True(t, formatSet.FPrintsWithSheet)
+	formatSet, err = parseFormatPictureSet((*PictureFormat)(nil))
+	assert.NoError(t, err)
+	assert.True(t, formatSet.FPrintsWithSheet)
+
+	// Test add picture with invalid format set.
+	img := filepath.Join("test", "images", "excel.png")
+	assert.EqualError(t, f.AddPicture("Sheet1", "A1", img, 1), "invalid parameter type")
+	assert.EqualError(t, f.AddPicture("Sheet1", "A1", img, &PictureFormat{XScale: -1}), "parameter 'XScale' and 'YScale' must be greater than 0")
+	assert.EqualError(t, f.AddPicture("Sheet1", "A1", img, `{"y_scale":0}`), "parameter 'XScale' and 'YScale' must be greater than 0")
+	assert.EqualError(t, f.AddPicture("Sheet1", "A1", img, &PictureFormat{HyperlinkType: "external"}), "parameter 'HyperlinkType' must be 'External' or 'Location'")
+	assert.EqualError(t, f.AddPicture("Sheet1", "A1", img, `{"positioning":"oneCel"}`), "parameter 'Positioning' must be 'oneCell', 'twoCell' or 'absolute'")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPictureWithStructure.xlsx")))
+}
+
 func TestDeletePicture(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	assert.NoError(t, err)
@@ -12,15 +12,32 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // PivotTableOption directly maps the format settings of the pivot table.
+// Name specifies the name of the pivot table, the name such as "Pivot
+// Table1" will be used if it is empty. CalculatedFields and CalculatedItems
+// specify the formula-based fields and items of the pivot table, the
+// calculated fields can be used in the data fields by name.
+//
+// ReportLayout specifies the layout of the pivot table report, the possible
+// values are compact, outline and tabular, the default value is tabular.
+// SubtotalsAtBottom specifies the subtotals of the row fields will be shown
+// at the bottom of each group instead of the top in the compact and outline
+// layout. InsertBlankRow specifies a blank row will be inserted after each
+// item of the row fields. RepeatItemLabels specifies the item labels of the
+// row fields will be repeated in each row.
 type PivotTableOption struct {
+	Name                string
 	DataRange           string
 	PivotTableRange     string
 	Rows                []PivotTableField
@@ -40,6 +57,13 @@ type PivotTableOption struct {
 	ShowColStripes      bool
 	ShowLastColumn      bool
 	PivotTableStyleName string
+	ReportLayout        string
+	SubtotalsAtBottom   bool
+	InsertBlankRow      bool
+	RepeatItemLabels    bool
+	CalculatedFields    []PivotTableCalculatedField
+	CalculatedItems     []PivotTableCalculatedItem
+	Groups              []PivotTableFieldGroup
 }
 
 // PivotTableField directly maps the field settings of the pivot table.
@@ -61,13 +85,113 @@ type PivotTableOption struct {
 //
 // Name specifies the name of the data field. Maximum 255 characters
 // are allowed in data field name, excess characters will be truncated.
+//
+// NumFmt and CustomNumFmt specify the built-in number format ID and the
+// custom number format code of the data field. ShowDataAs specifies how the
+// values of the data field are displayed, the possible values are:
+//
+//     Normal
+//     Difference
+//     Percent
+//     PercentDiff
+//     RunTotal
+//     PercentOfRow
+//     PercentOfCol
+//     PercentOfTotal
+//     Index
+//
+// BaseField specifies the name of the base field for the Difference,
+// Percent, PercentDiff and RunTotal calculations. BaseItem specifies the
+// name of the base item in the base field for the Difference, Percent and
+// PercentDiff calculations, the value (previous) and (next) could be used to
+// compare with the previous item and the next item.
 type PivotTableField struct {
 	Data            string
 	Name            string
 	Subtotal        string
 	DefaultSubtotal bool
+	NumFmt          int
+	CustomNumFmt    string
+	ShowDataAs      string
+	BaseField       string
+	BaseItem        string
+}
+
+// pivotTableShowDataAs defined the show values as types of the data field
+// which require the base field or the base item.
+var pivotTableShowDataAs = map[string]struct{ baseField, baseItem bool }{
+	"normal":         {false, false},
+	"difference":     {true, true},
+	"percent":        {true, true},
+	"percentDiff":    {true, true},
+	"runTotal":       {true, false},
+	"percentOfRow":   {false, false},
+	"percentOfCol":   {false, false},
+	"percentOfTotal": {false, false},
+	"index":          {false, false},
+}
+
+// Base item indexes of the data field for the previous item and the next
+// item of the base field.
+const (
+	pivotTableBaseItemPrevious int64 = 1048828
+	pivotTableBaseItemNext     int64 = 1048829
+)
+
+// PivotTableCalculatedField directly maps the calculated field settings of
+// the pivot table. Formula specifies the formula of the field which
+// references the other fields by name, such as Sales*0.1, the field name
+// should be enclosed in single quotes if it contains spaces.
+type PivotTableCalculatedField struct {
+	Name    string
+	Formula string
+}
+
+// PivotTableCalculatedItem directly maps the calculated item settings of the
+// pivot table. Field specifies the name of the field which the item will be
+// added to, Formula specifies the formula of the item which references the
+// other items of the field by name, such as Meat+Dairy.
+type PivotTableCalculatedItem struct {
+	Field   string
+	Name    string
+	Formula string
+}
+
+// PivotTableFieldGroup directly maps the grouping settings of the pivot
+// table field. Field specifies the name of the field in the data region.
+// GroupBy specifies the grouping type of the field, the default value is
+// range which groups the numeric values by ranges. The possible values for
+// this attribute are:
+//
+//     Range
+//     Seconds
+//     Minutes
+//     Hours
+//     Days
+//     Months
+//     Quarters
+//     Years
+//
+// StartNum and EndNum specify the start and end values of the groups, the
+// minimum and maximum values of the field will be used if both are zero, the
+// date time values should be specified by the serial number. Interval
+// specifies the size of each range for the range grouping and the number of
+// days of each group for the days grouping, the default value is 1.
+type PivotTableFieldGroup struct {
+	Field    string
+	GroupBy  string
+	StartNum float64
+	EndNum   float64
+	Interval float64
 }
 
+// pivotTableGroupBy defined the grouping types of the pivot table field.
+var pivotTableGroupBy = []string{"range", "seconds", "minutes", "hours", "days", "months", "quarters", "years"}
+
+// pivotCacheDateLayout defined the layout of the date time values in the
+// pivot cache.
+const pivotCacheDateLayout = "2006-01-02T15:04:05"
+
 // AddPivotTable provides the method to add pivot table by given pivot table
 // options.
 //
@@ -176,7 +300,43 @@ func (f *File) parseFormatPivotTableSet(opt *PivotTableOption) (*xlsxWorksheet,
 	}
 	pivotTableSheetPath, ok := f.sheetMap[trimSheetName(pivotTableSheetName)]
 	if !ok {
-		return dataSheet, pivotTableSheetPath, fmt.Errorf("sheet %s is not exist", pivotTableSheetName)
+		return dataSheet, pivotTableSheetPath, ErrSheetNotExist{pivotTableSheetName}
+	}
+	order, err := f.getPivotFieldsOrder(opt.DataRange)
+	if err != nil {
+		return dataSheet, pivotTableSheetPath, err
+	}
+	if inStrSlice([]string{"", "compact", "outline", "tabular"}, strings.ToLower(opt.ReportLayout)) == -1 {
+		return dataSheet, pivotTableSheetPath, errors.New("parameter 'ReportLayout' is invalid")
+	}
+	for _, field := range opt.CalculatedFields {
+		if field.Name == "" || field.Formula == "" {
+			return dataSheet, pivotTableSheetPath, errors.New("parameter 'CalculatedFields' is invalid")
+		}
+	}
+	for _, field := range opt.Data {
+		if _, ok := builtInNumFmt[field.NumFmt]; !ok && field.NumFmt != 0 {
+			return dataSheet, pivotTableSheetPath, errors.New("parameter 'NumFmt' is invalid")
+		}
+		if _, _, _, err = f.getPivotDataFieldShowDataAs(opt, &field); err != nil {
+			return dataSheet, pivotTableSheetPath, err
+		}
+	}
+	for _, item := range opt.CalculatedItems {
+		if item.Name == "" || item.Formula == "" {
+			return dataSheet, pivotTableSheetPath, errors.New("parameter 'CalculatedItems' is invalid")
+		}
+		if inStrSlice(order, item.Field) == -1 {
+			return dataSheet, pivotTableSheetPath, fmt.Errorf("parameter 'CalculatedItems' parsing error: field %s is not exist", item.Field)
+		}
+		if getPivotTableFieldGroup(opt, item.Field) != nil {
+			return dataSheet, pivotTableSheetPath, fmt.Errorf("parameter 'CalculatedItems' parsing error: field %s is grouped", item.Field)
+		}
+	}
+	for idx := range opt.Groups {
+		if _, _, err = f.getPivotFieldGroup(opt, &opt.Groups[idx]); err != nil {
+			return dataSheet, pivotTableSheetPath, err
+		}
 	}
 	return dataSheet, pivotTableSheetPath, err
 }
@@ -230,6 +390,281 @@ func (f *File) getPivotFieldsOrder(dataRange string) ([]string, error) {
 	return order, nil
 }
 
+// getPivotTableFieldsOrder provides a function to get order list of pivot
+// table fields, the calculated fields will be placed after the fields of the
+// data region.
+func (f *File) getPivotTableFieldsOrder(opt *PivotTableOption) ([]string, error) {
+	order, err := f.getPivotFieldsOrder(opt.DataRange)
+	if err != nil {
+		return order, err
+	}
+	for _, field := range opt.CalculatedFields {
+		order = append(order, field.Name)
+	}
+	return order, nil
+}
+
+// getPivotFieldItems provides a function to get the unique values of the
+// field in the data region with the calculated items of the field appended
+// by given field name, and returns the number of the calculated items. It
+// returns the names of the groups if the field is grouped, and returns nil if
+// the field has no calculated items and the items of the field aren't
+// referenced by the base item of the data fields.
+func (f *File) getPivotFieldItems(opt *PivotTableOption, name string) ([]string, int, error) {
+	var items, calculated []string
+	if group := getPivotTableFieldGroup(opt, name); group != nil {
+		_, fieldGroup, err := f.getPivotFieldGroup(opt, group)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, item := range fieldGroup.GroupItems.S {
+			items = append(items, item.V)
+		}
+		return items, 0, nil
+	}
+	for _, item := range opt.CalculatedItems {
+		if item.Field == name {
+			calculated = append(calculated, item.Name)
+		}
+	}
+	var baseField bool
+	for _, field := range opt.Data {
+		if field.BaseField == name && field.BaseItem != "" {
+			baseField = true
+		}
+	}
+	if len(calculated) == 0 && !baseField {
+		return nil, 0, nil
+	}
+	order, err := f.getPivotFieldsOrder(opt.DataRange)
+	if err != nil {
+		return nil, 0, err
+	}
+	if inStrSlice(order, name) == -1 {
+		return calculated, len(calculated), nil
+	}
+	dataSheet, coordinates, _ := f.adjustRange(opt.DataRange)
+	col := coordinates[0] + inStrSlice(order, name)
+	for row := coordinates[1] + 1; row <= coordinates[3]; row++ {
+		cell, _ := CoordinatesToCellName(col, row)
+		value, err := f.GetCellValue(dataSheet, cell)
+		if err != nil {
+			return nil, 0, err
+		}
+		if inStrSlice(items, value) == -1 {
+			items = append(items, value)
+		}
+	}
+	return append(items, calculated...), len(calculated), nil
+}
+
+// getSharedItemsValues provides a function to get the values of the string
+// items in the shared items of the cache field.
+func getSharedItemsValues(sharedItems *xlsxSharedItems) []string {
+	var values []string
+	if sharedItems != nil {
+		for _, s := range sharedItems.S {
+			values = append(values, s.V)
+		}
+	}
+	return values
+}
+
+// getPivotTableFieldGroup provides a function to get the grouping settings of
+// the field by given field name, and returns nil if the field isn't grouped.
+func getPivotTableFieldGroup(opt *PivotTableOption, name string) *PivotTableFieldGroup {
+	for idx := range opt.Groups {
+		if opt.Groups[idx].Field == name {
+			return &opt.Groups[idx]
+		}
+	}
+	return nil
+}
+
+// getPivotFieldGroup provides a function to build the shared items and the
+// field group of the pivot cache field by given grouping settings.
+func (f *File) getPivotFieldGroup(opt *PivotTableOption, group *PivotTableFieldGroup) (*xlsxSharedItems, *xlsxFieldGroup, error) {
+	order, err := f.getPivotFieldsOrder(opt.DataRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	field := inStrSlice(order, group.Field)
+	if field == -1 {
+		return nil, nil, fmt.Errorf("parameter 'Groups' parsing error: field %s is not exist", group.Field)
+	}
+	groupBy := strings.ToLower(group.GroupBy)
+	if groupBy == "" {
+		groupBy = "range"
+	}
+	if inStrSlice(pivotTableGroupBy, groupBy) == -1 {
+		return nil, nil, errors.New("parameter 'GroupBy' is invalid")
+	}
+	interval := group.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	if interval < 0 || (interval != 1 && groupBy != "range" && groupBy != "days") ||
+		(groupBy == "days" && interval != math.Trunc(interval)) {
+		return nil, nil, errors.New("parameter 'Interval' is invalid")
+	}
+	if group.EndNum < group.StartNum {
+		return nil, nil, errors.New("parameter 'EndNum' is invalid")
+	}
+	dataSheet, coordinates, _ := f.adjustRange(opt.DataRange)
+	ws, err := f.workSheetReader(dataSheet)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := f.getPivotCacheSourceValues(ws, coordinates)
+	if err != nil {
+		return nil, nil, err
+	}
+	var values []sortValue
+	for _, row := range rows[1:] {
+		values = append(values, row[field])
+	}
+	sharedItems, numeric := getPivotCacheNumericItems(values)
+	if !numeric || !sharedItems.ContainsNumber {
+		return nil, nil, fmt.Errorf("parameter 'Groups' parsing error: field %s contains non-numeric values", group.Field)
+	}
+	start, end, auto := *sharedItems.MinValue, *sharedItems.MaxValue, group.StartNum == 0 && group.EndNum == 0
+	if !auto {
+		start, end = group.StartNum, group.EndNum
+	}
+	if groupBy == "range" && (end-start)/interval >= TotalRows {
+		return nil, nil, errors.New("parameter 'Interval' is invalid")
+	}
+	fieldGroup := &xlsxFieldGroup{Base: intPtr(field), RangePr: &xlsxRangePr{}, GroupItems: &xlsxGroupItems{}}
+	if !auto {
+		fieldGroup.RangePr.AutoStart, fieldGroup.RangePr.AutoEnd = boolPtr(false), boolPtr(false)
+	}
+	if interval != 1 {
+		fieldGroup.RangePr.GroupInterval = interval
+	}
+	var items []string
+	if groupBy == "range" {
+		fieldGroup.RangePr.StartNum, fieldGroup.RangePr.EndNum = start, end
+		items = getPivotRangeGroupItems(start, end, interval, sharedItems.ContainsInteger)
+	} else {
+		date1904 := f.date1904()
+		setPivotCacheDateItems(sharedItems, date1904)
+		startDate, endDate := timeFromExcelTime(start, date1904), timeFromExcelTime(end, date1904)
+		fieldGroup.RangePr.GroupBy = groupBy
+		fieldGroup.RangePr.StartDate = startDate.Format(pivotCacheDateLayout)
+		fieldGroup.RangePr.EndDate = endDate.Format(pivotCacheDateLayout)
+		items = append([]string{"<" + startDate.Format("1/2/2006")}, getPivotDateGroupItems(groupBy, startDate, endDate, int(interval))...)
+		items = append(items, ">"+endDate.Format("1/2/2006"))
+	}
+	for _, item := range items {
+		fieldGroup.GroupItems.S = append(fieldGroup.GroupItems.S, &xlsxString{V: item})
+	}
+	fieldGroup.GroupItems.Count = len(fieldGroup.GroupItems.S)
+	return sharedItems, fieldGroup, nil
+}
+
+// getPivotRangeGroupItems provides a function to get the names of the groups
+// of the numeric values by given start value, end value and the size of each
+// range.
+func getPivotRangeGroupItems(start, end, interval float64, integer bool) []string {
+	formatNum := func(num float64) string {
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	}
+	items := []string{"<" + formatNum(start)}
+	integer = integer && start == math.Trunc(start) && interval == math.Trunc(interval)
+	for idx := 0; start+float64(idx)*interval <= end; idx++ {
+		from := start + float64(idx)*interval
+		to := from + interval
+		if integer {
+			to--
+		}
+		items = append(items, formatNum(from)+"-"+formatNum(to))
+	}
+	return append(items, ">"+formatNum(end))
+}
+
+// getPivotDateGroupItems provides a function to get the names of the groups
+// of the date time values by given grouping type, start date, end date and
+// the number of days of each group for the days grouping.
+func getPivotDateGroupItems(groupBy string, start, end time.Time, days int) []string {
+	var items []string
+	switch groupBy {
+	case "seconds", "minutes":
+		for idx := 0; idx < 60; idx++ {
+			items = append(items, fmt.Sprintf(":%02d", idx))
+		}
+	case "hours":
+		for idx := 0; idx < 24; idx++ {
+			items = append(items, time.Date(2000, 1, 1, idx, 0, 0, 0, time.UTC).Format("3 PM"))
+		}
+	case "days":
+		if days == 1 {
+			for date := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC); date.Year() == 2000; date = date.AddDate(0, 0, 1) {
+				items = append(items, date.Format("2-Jan"))
+			}
+			break
+		}
+		for date := start; !date.After(end); date = date.AddDate(0, 0, days) {
+			items = append(items, date.Format("1/2/2006")+" - "+date.AddDate(0, 0, days-1).Format("1/2/2006"))
+		}
+	case "months":
+		for month := time.January; month <= time.December; month++ {
+			items = append(items, month.String()[:3])
+		}
+	case "quarters":
+		for quarter := 1; quarter <= 4; quarter++ {
+			items = append(items, "Qtr"+strconv.Itoa(quarter))
+		}
+	case "years":
+		for year := start.Year(); year <= end.Year(); year++ {
+			items = append(items, strconv.Itoa(year))
+		}
+	}
+	return items
+}
+
+// getPivotCacheNumericItems provides a function to build the shared items of
+// the pivot cache field which contains numeric values by given values of the
+// field, and returns false if the field contains non-numeric values.
+func getPivotCacheNumericItems(values []sortValue) (*xlsxSharedItems, bool) {
+	sharedItems, numeric := &xlsxSharedItems{
+		ContainsSemiMixedTypes: boolPtr(false),
+		ContainsString:         boolPtr(false),
+		ContainsInteger:        true,
+	}, true
+	for _, value := range values {
+		switch value.kind {
+		case sortValueBlank:
+			sharedItems.ContainsBlank = true
+		case sortValueNumber:
+			if !sharedItems.ContainsNumber || value.num < *sharedItems.MinValue {
+				sharedItems.MinValue = float64Ptr(value.num)
+			}
+			if !sharedItems.ContainsNumber || value.num > *sharedItems.MaxValue {
+				sharedItems.MaxValue = float64Ptr(value.num)
+			}
+			sharedItems.ContainsNumber = true
+			sharedItems.ContainsInteger = sharedItems.ContainsInteger && value.num == math.Trunc(value.num)
+		default:
+			numeric = false
+		}
+	}
+	sharedItems.ContainsInteger = sharedItems.ContainsNumber && sharedItems.ContainsInteger
+	return sharedItems, numeric
+}
+
+// setPivotCacheDateItems provides a function to convert the shared items of
+// the pivot cache field which contains numeric values to the shared items of
+// date time values.
+func setPivotCacheDateItems(sharedItems *xlsxSharedItems, date1904 bool) {
+	sharedItems.ContainsNonDate, sharedItems.ContainsDate = boolPtr(false), true
+	sharedItems.ContainsNumber, sharedItems.ContainsInteger = false, false
+	if sharedItems.MinValue != nil && sharedItems.MaxValue != nil {
+		sharedItems.MinDate = timeFromExcelTime(*sharedItems.MinValue, date1904).Format(pivotCacheDateLayout)
+		sharedItems.MaxDate = timeFromExcelTime(*sharedItems.MaxValue, date1904).Format(pivotCacheDateLayout)
+	}
+	sharedItems.MinValue, sharedItems.MaxValue = nil, nil
+}
+
 // addPivotCache provides a function to create a pivot cache by given properties.
 func (f *File) addPivotCache(pivotCacheID int, pivotCacheXML string, opt *PivotTableOption, ws *xlsxWorksheet) error {
 	// validate data range
@@ -238,7 +673,7 @@ func (f *File) addPivotCache(pivotCacheID int, pivotCacheXML string, opt *PivotT
 		return fmt.Errorf("parameter 'DataRange' parsing error: %s", err.Error())
 	}
 	// data range has been checked
-	order, _ := f.getPivotFieldsOrder(opt.DataRange)
+	order, _ := f.getPivotTableFieldsOrder(opt)
 	hcell, _ := CoordinatesToCellName(coordinates[0], coordinates[1])
 	vcell, _ := CoordinatesToCellName(coordinates[2], coordinates[3])
 	pc := xlsxPivotCacheDefinition{
@@ -254,20 +689,43 @@ func (f *File) addPivotCache(pivotCacheID int, pivotCacheXML string, opt *PivotT
 		CacheFields: &xlsxCacheFields{},
 	}
 
-	for _, name := range order {
+	for idx, name := range order {
+		if idx := idx - len(order) + len(opt.CalculatedFields); idx >= 0 {
+			pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
+				Name:          name,
+				Formula:       strings.TrimPrefix(opt.CalculatedFields[idx].Formula, "="),
+				DatabaseField: boolPtr(false),
+			})
+			continue
+		}
+		if group := getPivotTableFieldGroup(opt, name); group != nil {
+			sharedItems, fieldGroup, err := f.getPivotFieldGroup(opt, group)
+			if err != nil {
+				return err
+			}
+			pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
+				Name:        name,
+				SharedItems: sharedItems,
+				FieldGroup:  fieldGroup,
+			})
+			continue
+		}
 		defaultRowsSubtotal, rowOk := f.getPivotTableFieldNameDefaultSubtotal(name, opt.Rows)
 		defaultColumnsSubtotal, colOk := f.getPivotTableFieldNameDefaultSubtotal(name, opt.Columns)
 		sharedItems := xlsxSharedItems{
 			Count: 0,
 		}
-		s := xlsxString{}
-		if (rowOk && !defaultRowsSubtotal) || (colOk && !defaultColumnsSubtotal) {
-			s = xlsxString{
-				V: "",
-			}
-			sharedItems.Count++
-			sharedItems.S = &s
+		items, _, err := f.getPivotFieldItems(opt, name)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			sharedItems.S = append(sharedItems.S, &xlsxString{V: item})
 		}
+		if len(items) == 0 && ((rowOk && !defaultRowsSubtotal) || (colOk && !defaultColumnsSubtotal)) {
+			sharedItems.S = append(sharedItems.S, &xlsxString{V: ""})
+		}
+		sharedItems.Count = len(sharedItems.S)
 
 		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
 			Name:        name,
@@ -275,6 +733,30 @@ func (f *File) addPivotCache(pivotCacheID int, pivotCacheXML string, opt *PivotT
 		})
 	}
 	pc.CacheFields.Count = len(pc.CacheFields.CacheField)
+	for _, item := range opt.CalculatedItems {
+		if pc.CalculatedItems == nil {
+			pc.CalculatedItems = &xlsxCalculatedItems{}
+		}
+		field := inStrSlice(order, item.Field)
+		x := inStrSlice(getSharedItemsValues(pc.CacheFields.CacheField[field].SharedItems), item.Name)
+		pc.CalculatedItems.CalculatedItem = append(pc.CalculatedItems.CalculatedItem, &xlsxCalculatedItem{
+			Formula: strings.TrimPrefix(item.Formula, "="),
+			PivotArea: &xlsxPivotArea{
+				CacheIndex:    true,
+				Outline:       boolPtr(false),
+				FieldPosition: intPtr(0),
+				References: &xlsxPivotAreaReferences{
+					Count: 1,
+					Reference: []*xlsxPivotAreaReference{
+						{Field: intPtr(field), Count: 1, X: []*xlsxX{{V: x}}},
+					},
+				},
+			},
+		})
+	}
+	if pc.CalculatedItems != nil {
+		pc.CalculatedItems.Count = len(pc.CalculatedItems.CalculatedItem)
+	}
 	pivotCache, err := xml.Marshal(pc)
 	f.saveFileList(pivotCacheXML, pivotCache)
 	return err
@@ -298,8 +780,12 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 		}
 		return opt.PivotTableStyleName
 	}
+	name := opt.Name
+	if name == "" {
+		name = fmt.Sprintf("Pivot Table%d", pivotTableID)
+	}
 	pt := xlsxPivotTableDefinition{
-		Name:              fmt.Sprintf("Pivot Table%d", pivotTableID),
+		Name:              name,
 		CacheID:           cacheID,
 		RowGrandTotals:    &opt.RowGrandTotals,
 		ColGrandTotals:    &opt.ColGrandTotals,
@@ -340,6 +826,9 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 
 	// pivot fields
 	_ = f.addPivotFields(&pt, opt)
+	if err = f.setPivotTableLayout(&pt, opt); err != nil {
+		return err
+	}
 
 	// count pivot fields
 	pt.PivotFields.Count = len(pt.PivotFields.PivotField)
@@ -355,6 +844,37 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 	return err
 }
 
+// setPivotTableLayout provides a method to set the report layout of the
+// pivot table and the layout settings of the row fields by given pivot table
+// options.
+func (f *File) setPivotTableLayout(pt *xlsxPivotTableDefinition, opt *PivotTableOption) error {
+	layout := strings.ToLower(opt.ReportLayout)
+	compact, outline := layout == "compact", layout == "compact" || layout == "outline"
+	pt.Compact, pt.Outline, pt.OutlineData = boolPtr(compact), boolPtr(outline), outline
+	for _, field := range pt.PivotFields.PivotField {
+		field.Compact, field.Outline = boolPtr(compact), boolPtr(outline)
+		if field.Axis != "axisRow" {
+			continue
+		}
+		field.InsertBlankRow = opt.InsertBlankRow
+		if opt.SubtotalsAtBottom {
+			field.SubtotalTop = boolPtr(false)
+		}
+		if opt.RepeatItemLabels {
+			ext, err := xml.Marshal(&xlsxPivotFieldExt{
+				URI:        ExtURIPivotField,
+				XMLNSX14:   NameSpaceSpreadSheetX14.Value,
+				PivotField: &xlsxX14PivotField{FillDownLabels: true},
+			})
+			if err != nil {
+				return err
+			}
+			field.ExtLst = &xlsxExtLst{Ext: string(ext)}
+		}
+	}
+	return nil
+}
+
 // addPivotRowFields provides a method to add row fields for pivot table by
 // given pivot table options.
 func (f *File) addPivotRowFields(pt *xlsxPivotTableDefinition, opt *PivotTableOption) error {
@@ -419,11 +939,29 @@ func (f *File) addPivotDataFields(pt *xlsxPivotTableDefinition, opt *PivotTableO
 		if pt.DataFields == nil {
 			pt.DataFields = &xlsxDataFields{}
 		}
-		pt.DataFields.DataField = append(pt.DataFields.DataField, &xlsxDataField{
+		field := &xlsxDataField{
 			Name:     dataFieldsName[idx],
 			Fld:      dataField,
 			Subtotal: dataFieldsSubtotals[idx],
-		})
+		}
+		if opt.Data[idx].CustomNumFmt != "" {
+			styleSheet, err := f.stylesReader()
+			if err != nil {
+				return err
+			}
+			style := &Style{CustomNumFmt: &opt.Data[idx].CustomNumFmt}
+			numFmtID := getCustomNumFmtID(styleSheet, style)
+			if numFmtID == -1 {
+				numFmtID = setCustomNumFmt(styleSheet, style)
+			}
+			field.NumFmtID = strconv.Itoa(numFmtID)
+		} else if opt.Data[idx].NumFmt != 0 {
+			field.NumFmtID = strconv.Itoa(opt.Data[idx].NumFmt)
+		}
+		if field.ShowDataAs, field.BaseField, field.BaseItem, err = f.getPivotDataFieldShowDataAs(opt, &opt.Data[idx]); err != nil {
+			return err
+		}
+		pt.DataFields.DataField = append(pt.DataFields.DataField, field)
 	}
 
 	// count data fields
@@ -433,6 +971,52 @@ func (f *File) addPivotDataFields(pt *xlsxPivotTableDefinition, opt *PivotTableO
 	return err
 }
 
+// getPivotDataFieldShowDataAs provides a function to get the show values as
+// type, the base field index and the base item index of the data field by
+// given pivot table options and data field settings.
+func (f *File) getPivotDataFieldShowDataAs(opt *PivotTableOption, field *PivotTableField) (string, *int, *int64, error) {
+	if field.ShowDataAs == "" {
+		return "", nil, nil, nil
+	}
+	showDataAs := strings.ToLower(field.ShowDataAs[:1]) + field.ShowDataAs[1:]
+	base, ok := pivotTableShowDataAs[showDataAs]
+	if !ok {
+		return "", nil, nil, errors.New("parameter 'ShowDataAs' is invalid")
+	}
+	if showDataAs == "normal" {
+		return "", nil, nil, nil
+	}
+	baseField, baseItem := 0, int64(0)
+	if base.baseField {
+		order, err := f.getPivotTableFieldsOrder(opt)
+		if err != nil {
+			return showDataAs, nil, nil, err
+		}
+		if baseField = inStrSlice(order, field.BaseField); baseField == -1 {
+			return showDataAs, nil, nil, fmt.Errorf("parameter 'BaseField' parsing error: field %s is not exist", field.BaseField)
+		}
+	}
+	if base.baseItem {
+		switch field.BaseItem {
+		case "(previous)":
+			baseItem = pivotTableBaseItemPrevious
+		case "(next)":
+			baseItem = pivotTableBaseItemNext
+		default:
+			items, _, err := f.getPivotFieldItems(opt, field.BaseField)
+			if err != nil {
+				return showDataAs, nil, nil, err
+			}
+			idx := inStrSlice(items, field.BaseItem)
+			if idx == -1 {
+				return showDataAs, nil, nil, fmt.Errorf("parameter 'BaseItem' parsing error: item %s is not exist", field.BaseItem)
+			}
+			baseItem = int64(idx)
+		}
+	}
+	return showDataAs, &baseField, &baseItem, nil
+}
+
 // inStrSlice provides a method to check if an element is present in an array,
 // and return the index of its location, otherwise return -1.
 func inStrSlice(a []string, x string) int {
@@ -500,7 +1084,7 @@ func (f *File) addPivotColFields(pt *xlsxPivotTableDefinition, opt *PivotTableOp
 // addPivotFields create pivot fields based on the column order of the first
 // row in the data region by given pivot table definition and option.
 func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opt *PivotTableOption) error {
-	order, err := f.getPivotFieldsOrder(opt.DataRange)
+	order, err := f.getPivotTableFieldsOrder(opt)
 	if err != nil {
 		return err
 	}
@@ -566,6 +1150,25 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opt *PivotTableOptio
 		}
 		pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, &xlsxPivotField{})
 	}
+	for idx, name := range order {
+		items, calculated, err := f.getPivotFieldItems(opt, name)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			continue
+		}
+		field := pt.PivotFields.PivotField[idx]
+		field.Items = &xlsxItems{}
+		for i := range items {
+			x := i
+			field.Items.Item = append(field.Items.Item, &xlsxItem{X: &x, F: i >= len(items)-calculated})
+		}
+		if defaultTrue(field.DefaultSubtotal) {
+			field.Items.Item = append(field.Items.Item, &xlsxItem{T: "default"})
+		}
+		field.Items.Count = len(field.Items.Item)
+	}
 	return err
 }
 
@@ -593,11 +1196,23 @@ func (f *File) countPivotCache() int {
 	return count
 }
 
+// countPivotCacheRecords provides a function to get pivot cache records files
+// count storage in the folder xl/pivotCache.
+func (f *File) countPivotCacheRecords() int {
+	count := 0
+	for k := range f.XLSX {
+		if strings.Contains(k, "xl/pivotCache/pivotCacheRecords") {
+			count++
+		}
+	}
+	return count
+}
+
 // getPivotFieldsIndex convert the column of the first row in the data region
 // to a sequential index by given fields and pivot option.
 func (f *File) getPivotFieldsIndex(fields []PivotTableField, opt *PivotTableOption) ([]int, error) {
 	pivotFieldsIndex := []int{}
-	orders, err := f.getPivotFieldsOrder(opt.DataRange)
+	orders, err := f.getPivotTableFieldsOrder(opt)
 	if err != nil {
 		return pivotFieldsIndex, err
 	}
@@ -663,7 +1278,7 @@ func (f *File) getPivotTableFieldNameDefaultSubtotal(name string, fields []Pivot
 
 // addWorkbookPivotCache add the association ID of the pivot cache in workbook.xml.
 func (f *File) addWorkbookPivotCache(RID int) int {
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	if wb.PivotCaches == nil {
 		wb.PivotCaches = &xlsxPivotCaches{}
 	}
@@ -680,3 +1295,779 @@ func (f *File) addWorkbookPivotCache(RID int) int {
 	})
 	return cacheID
 }
+
+// GetPivotTables provides the method to get all pivot tables on the worksheet
+// by given worksheet name, the pivot table options are parsed from the pivot
+// table and pivot cache parts in the form of the options of AddPivotTable.
+// For example, get the pivot tables on Sheet1:
+//
+//    pivotTables, err := f.GetPivotTables("Sheet1")
+//
+func (f *File) GetPivotTables(sheet string) ([]PivotTableOption, error) {
+	var pivotTables []PivotTableOption
+	parts, err := f.getPivotTableParts(sheet)
+	if err != nil {
+		return pivotTables, err
+	}
+	for _, part := range parts {
+		pivotTables = append(pivotTables, f.getPivotTableOption(sheet, part.pt, part.pc))
+	}
+	return pivotTables, nil
+}
+
+// pivotTablePart holds the part paths and the definitions of a pivot table
+// and the pivot cache used by the pivot table.
+type pivotTablePart struct {
+	sheet         string
+	pivotTableXML string
+	pivotCacheXML string
+	pt            *xlsxPivotTableDefinition
+	pc            *xlsxPivotCacheDefinition
+}
+
+// getPivotTableParts provides a function to get the parts of all pivot tables
+// on the worksheet by given worksheet name.
+func (f *File) getPivotTableParts(sheet string) ([]pivotTablePart, error) {
+	var parts []pivotTablePart
+	sheetPath, ok := f.sheetMap[trimSheetName(sheet)]
+	if !ok {
+		return parts, ErrSheetNotExist{sheet}
+	}
+	sheetRels, err := f.relsReader(getPartRelsPath(sheetPath))
+	if err != nil {
+		return nil, err
+	}
+	if sheetRels == nil {
+		return parts, nil
+	}
+	for _, rel := range sheetRels.Relationships {
+		if rel.Type != SourceRelationshipPivotTable {
+			continue
+		}
+		part := pivotTablePart{sheet: sheet, pivotTableXML: resolvePartPath(sheetPath, rel.Target), pc: &xlsxPivotCacheDefinition{}}
+		var err error
+		if part.pt, err = f.pivotTableReader(part.pivotTableXML); err != nil {
+			return parts, err
+		}
+		pivotTableRels, err := f.relsReader(getPartRelsPath(part.pivotTableXML))
+		if err != nil {
+			return nil, err
+		}
+		if pivotTableRels != nil {
+			for _, rel := range pivotTableRels.Relationships {
+				if rel.Type == SourceRelationshipPivotCache {
+					part.pivotCacheXML = resolvePartPath(part.pivotTableXML, rel.Target)
+					if part.pc, err = f.pivotCacheReader(part.pivotCacheXML); err != nil {
+						return parts, err
+					}
+					break
+				}
+			}
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// getWorkbookPivotTableParts provides a function to get the parts of all
+// pivot tables in the workbook.
+func (f *File) getWorkbookPivotTableParts() ([]pivotTablePart, error) {
+	var parts []pivotTablePart
+	for _, sheet := range f.GetSheetList() {
+		sheetParts, err := f.getPivotTableParts(sheet)
+		if err != nil {
+			return parts, err
+		}
+		parts = append(parts, sheetParts...)
+	}
+	return parts, nil
+}
+
+// findPivotTablePart provides a function to get the parts of all pivot tables
+// in the workbook and the parts of the pivot table by given pivot table name,
+// the pivot table name is case-insensitive.
+func (f *File) findPivotTablePart(name string) ([]pivotTablePart, *pivotTablePart, error) {
+	parts, err := f.getWorkbookPivotTableParts()
+	if err != nil {
+		return parts, nil, err
+	}
+	for idx := range parts {
+		if strings.EqualFold(parts[idx].pt.Name, name) {
+			return parts, &parts[idx], nil
+		}
+	}
+	return parts, nil, ErrPivotTableNotExist{name}
+}
+
+// getPivotTableOption provides a function to convert the pivot table
+// definition and pivot cache definition to the pivot table options by given
+// worksheet name of the pivot table.
+func (f *File) getPivotTableOption(sheet string, pt *xlsxPivotTableDefinition, pc *xlsxPivotCacheDefinition) PivotTableOption {
+	opt := PivotTableOption{
+		Name:              pt.Name,
+		RowGrandTotals:    defaultTrue(pt.RowGrandTotals),
+		ColGrandTotals:    defaultTrue(pt.ColGrandTotals),
+		ShowDrill:         defaultTrue(pt.ShowDrill),
+		UseAutoFormatting: pt.UseAutoFormatting != nil && *pt.UseAutoFormatting,
+		PageOverThenDown:  pt.PageOverThenDown != nil && *pt.PageOverThenDown,
+		MergeItem:         pt.MergeItem != nil && *pt.MergeItem,
+		CompactData:       defaultTrue(pt.CompactData),
+	}
+	if pt.Location != nil {
+		opt.PivotTableRange = sheet + "!" + pt.Location.Ref
+	}
+	if pc.CacheSource != nil && pc.CacheSource.WorksheetSource != nil {
+		if source := pc.CacheSource.WorksheetSource; source.Ref != "" {
+			opt.DataRange = source.Sheet + "!" + source.Ref
+		} else {
+			opt.DataRange = source.Name
+		}
+	}
+	var cacheFields []*xlsxCacheField
+	if pc.CacheFields != nil {
+		cacheFields = pc.CacheFields.CacheField
+	}
+	var pivotFields []*xlsxPivotField
+	if pt.PivotFields != nil {
+		pivotFields = pt.PivotFields.PivotField
+	}
+	getAxisField := func(x int) (PivotTableField, bool) {
+		if x < 0 || x >= len(cacheFields) {
+			return PivotTableField{}, false
+		}
+		field := PivotTableField{Data: cacheFields[x].Name, DefaultSubtotal: true}
+		if x < len(pivotFields) {
+			field.Name = pivotFields[x].Name
+			field.DefaultSubtotal = defaultTrue(pivotFields[x].DefaultSubtotal)
+		}
+		return field, true
+	}
+	if pt.RowFields != nil {
+		for _, rowField := range pt.RowFields.Field {
+			if field, ok := getAxisField(rowField.X); ok {
+				opt.Rows = append(opt.Rows, field)
+			}
+		}
+	}
+	if pt.ColFields != nil {
+		for _, colField := range pt.ColFields.Field {
+			if field, ok := getAxisField(colField.X); ok {
+				opt.Columns = append(opt.Columns, field)
+			}
+		}
+	}
+	if pt.PageFields != nil {
+		for _, pageField := range pt.PageFields.PageField {
+			if pageField.Fld >= 0 && pageField.Fld < len(cacheFields) {
+				opt.Filter = append(opt.Filter, PivotTableField{Data: cacheFields[pageField.Fld].Name, Name: pageField.Name})
+			}
+		}
+	}
+	if pt.DataFields != nil {
+		for _, dataField := range pt.DataFields.DataField {
+			if dataField.Fld < 0 || dataField.Fld >= len(cacheFields) {
+				continue
+			}
+			subtotal := "Sum"
+			if dataField.Subtotal != "" {
+				subtotal = strings.ToUpper(dataField.Subtotal[:1]) + dataField.Subtotal[1:]
+			}
+			field := PivotTableField{Data: cacheFields[dataField.Fld].Name, Name: dataField.Name, Subtotal: subtotal}
+			f.getPivotDataFieldFormat(pt, dataField, cacheFields, &field)
+			opt.Data = append(opt.Data, field)
+		}
+	}
+	for _, cacheField := range cacheFields {
+		if cacheField.Formula != "" {
+			opt.CalculatedFields = append(opt.CalculatedFields, PivotTableCalculatedField{Name: cacheField.Name, Formula: cacheField.Formula})
+		}
+	}
+	if pc.CalculatedItems != nil {
+		for _, item := range pc.CalculatedItems.CalculatedItem {
+			if item.PivotArea == nil || item.PivotArea.References == nil || len(item.PivotArea.References.Reference) == 0 {
+				continue
+			}
+			ref := item.PivotArea.References.Reference[0]
+			if ref.Field == nil || *ref.Field < 0 || *ref.Field >= len(cacheFields) || len(ref.X) == 0 {
+				continue
+			}
+			if values := getSharedItemsValues(cacheFields[*ref.Field].SharedItems); ref.X[0].V < len(values) {
+				opt.CalculatedItems = append(opt.CalculatedItems, PivotTableCalculatedItem{
+					Field: cacheFields[*ref.Field].Name, Name: values[ref.X[0].V], Formula: item.Formula,
+				})
+			}
+		}
+	}
+	for idx, cacheField := range cacheFields {
+		if group, ok := f.getPivotCacheFieldGroup(idx, cacheField); ok {
+			opt.Groups = append(opt.Groups, group)
+		}
+	}
+	opt.ReportLayout = "tabular"
+	if pt.Outline != nil && *pt.Outline {
+		opt.ReportLayout = "outline"
+		if defaultTrue(pt.Compact) {
+			opt.ReportLayout = "compact"
+		}
+	}
+	for _, field := range pivotFields {
+		if field.Axis != "axisRow" {
+			continue
+		}
+		opt.InsertBlankRow = opt.InsertBlankRow || field.InsertBlankRow
+		opt.SubtotalsAtBottom = opt.SubtotalsAtBottom || !defaultTrue(field.SubtotalTop)
+		opt.RepeatItemLabels = opt.RepeatItemLabels || getPivotFieldFillDownLabels(field)
+	}
+	if pt.PivotTableStyleInfo != nil {
+		opt.PivotTableStyleName = pt.PivotTableStyleInfo.Name
+		opt.ShowRowHeaders = pt.PivotTableStyleInfo.ShowRowHeaders
+		opt.ShowColHeaders = pt.PivotTableStyleInfo.ShowColHeaders
+		opt.ShowRowStripes = pt.PivotTableStyleInfo.ShowRowStripes
+		opt.ShowColStripes = pt.PivotTableStyleInfo.ShowColStripes
+		opt.ShowLastColumn = pt.PivotTableStyleInfo.ShowLastColumn
+	}
+	return opt
+}
+
+// getPivotCacheFieldGroup provides a function to get the grouping settings
+// of the pivot cache field by given field index and cache field, and returns
+// false if the field isn't grouped by ranges.
+func (f *File) getPivotCacheFieldGroup(idx int, cacheField *xlsxCacheField) (PivotTableFieldGroup, bool) {
+	group := PivotTableFieldGroup{Field: cacheField.Name, GroupBy: "Range", Interval: 1}
+	if cacheField.FieldGroup == nil || cacheField.FieldGroup.RangePr == nil ||
+		(cacheField.FieldGroup.Base != nil && *cacheField.FieldGroup.Base != idx) {
+		return group, false
+	}
+	rangePr := cacheField.FieldGroup.RangePr
+	if rangePr.GroupBy != "" {
+		group.GroupBy = strings.ToUpper(rangePr.GroupBy[:1]) + rangePr.GroupBy[1:]
+	}
+	if rangePr.GroupInterval != 0 {
+		group.Interval = rangePr.GroupInterval
+	}
+	if defaultTrue(rangePr.AutoStart) && defaultTrue(rangePr.AutoEnd) {
+		return group, true
+	}
+	group.StartNum, group.EndNum = rangePr.StartNum, rangePr.EndNum
+	if group.GroupBy != "Range" {
+		date1904 := f.date1904()
+		if startDate, err := time.Parse(pivotCacheDateLayout, rangePr.StartDate); err == nil {
+			group.StartNum, _ = timeToExcelTime(startDate, date1904)
+		}
+		if endDate, err := time.Parse(pivotCacheDateLayout, rangePr.EndDate); err == nil {
+			group.EndNum, _ = timeToExcelTime(endDate, date1904)
+		}
+	}
+	return group, true
+}
+
+// getPivotDataFieldFormat provides a function to get the number format and
+// the show values as settings of the data field by given pivot table
+// definition, data field and the cache fields of the pivot cache.
+func (f *File) getPivotDataFieldFormat(pt *xlsxPivotTableDefinition, dataField *xlsxDataField, cacheFields []*xlsxCacheField, field *PivotTableField) {
+	if numFmtID, err := strconv.Atoi(dataField.NumFmtID); err == nil {
+		if _, ok := builtInNumFmt[numFmtID]; ok {
+			field.NumFmt = numFmtID
+		} else if styleSheet, _ := f.stylesReader(); styleSheet.NumFmts != nil {
+			for _, numFmt := range styleSheet.NumFmts.NumFmt {
+				if numFmt.NumFmtID == numFmtID {
+					field.CustomNumFmt = numFmt.FormatCode
+					break
+				}
+			}
+		}
+	}
+	base, ok := pivotTableShowDataAs[dataField.ShowDataAs]
+	if !ok || dataField.ShowDataAs == "normal" {
+		return
+	}
+	field.ShowDataAs = strings.ToUpper(dataField.ShowDataAs[:1]) + dataField.ShowDataAs[1:]
+	if !base.baseField || dataField.BaseField == nil || *dataField.BaseField < 0 || *dataField.BaseField >= len(cacheFields) {
+		return
+	}
+	field.BaseField = cacheFields[*dataField.BaseField].Name
+	if !base.baseItem || dataField.BaseItem == nil {
+		return
+	}
+	switch *dataField.BaseItem {
+	case pivotTableBaseItemPrevious:
+		field.BaseItem = "(previous)"
+	case pivotTableBaseItemNext:
+		field.BaseItem = "(next)"
+	default:
+		if pt.PivotFields == nil || *dataField.BaseField >= len(pt.PivotFields.PivotField) {
+			return
+		}
+		pivotField := pt.PivotFields.PivotField[*dataField.BaseField]
+		if pivotField.Items == nil || *dataField.BaseItem < 0 || *dataField.BaseItem >= int64(len(pivotField.Items.Item)) {
+			return
+		}
+		values := getSharedItemsValues(cacheFields[*dataField.BaseField].SharedItems)
+		if x := pivotField.Items.Item[*dataField.BaseItem].X; x != nil && *x < len(values) {
+			field.BaseItem = values[*x]
+		}
+	}
+}
+
+// getPivotFieldFillDownLabels provides a function to check if the item labels
+// of the pivot field are repeated.
+func getPivotFieldFillDownLabels(field *xlsxPivotField) bool {
+	if field.ExtLst == nil {
+		return false
+	}
+	decodeExtLst := decodePivotFieldExtLst{}
+	if err := xml.Unmarshal([]byte("<extLst>"+field.ExtLst.Ext+"</extLst>"), &decodeExtLst); err != nil {
+		return false
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI == ExtURIPivotField && ext.PivotField != nil {
+			return ext.PivotField.FillDownLabels
+		}
+	}
+	return false
+}
+
+// RefreshPivotCache provides the method to rebuild the pivot cache of the
+// pivot table by given pivot table name from the current data of the source
+// range, the pivot table name is case-insensitive. The shared items and the
+// records of the pivot cache will be rebuilt, the items of the pivot fields in
+// all pivot tables which use the same pivot cache will be updated with the new
+// shared items, and the pivot cache will be marked to be refreshed on load, so
+// that the spreadsheet application recalculates the pivot tables when opening
+// the workbook. Only the pivot cache created from a worksheet range or a table
+// is supported. For example, refresh the pivot cache of the pivot table named
+// "PivotTable1" after the source data has been changed:
+//
+//    err := f.RefreshPivotCache("PivotTable1")
+//
+func (f *File) RefreshPivotCache(name string) error {
+	parts, target, err := f.findPivotTablePart(name)
+	if err != nil {
+		return err
+	}
+	pc := target.pc
+	if target.pivotCacheXML == "" || pc.CacheFields == nil {
+		return fmt.Errorf("pivot cache of the pivot table %s is not exist", name)
+	}
+	dataSheet, coordinates, err := f.getPivotCacheSource(pc)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(dataSheet)
+	if err != nil {
+		return err
+	}
+	var fields []int
+	for idx, field := range pc.CacheFields.CacheField {
+		if defaultTrue(field.DatabaseField) {
+			fields = append(fields, idx)
+		}
+	}
+	if len(fields) != coordinates[2]-coordinates[0]+1 {
+		return errors.New("the fields of the pivot cache source are changed")
+	}
+	var sharedParts []*pivotTablePart
+	for idx := range parts {
+		if parts[idx].pivotCacheXML == target.pivotCacheXML {
+			sharedParts = append(sharedParts, &parts[idx])
+		}
+	}
+	values, err := f.getPivotCacheSourceValues(ws, coordinates)
+	if err != nil {
+		return err
+	}
+	records := make([]*xlsxPivotCacheRecord, len(values)-1)
+	for idx := range records {
+		records[idx] = &xlsxPivotCacheRecord{}
+	}
+	for col, field := range fields {
+		if header := values[0][col]; header.kind != sortValueBlank {
+			pc.CacheFields.CacheField[field].Name = getPivotCacheItemName(header)
+		}
+		column := make([]sortValue, len(records))
+		for row := range records {
+			column[row] = values[row+1][col]
+		}
+		for row, content := range refreshPivotCacheField(pc, field, column, sharedParts, f.date1904()) {
+			records[row].Content += content
+		}
+	}
+	recordsXML, err := xml.Marshal(xlsxPivotCacheRecords{Count: len(records), R: records})
+	if err != nil {
+		return err
+	}
+	f.saveFileList(f.getPivotCacheRecordsPath(target.pivotCacheXML, pc), recordsXML)
+	pc.SaveData, pc.RefreshOnLoad, pc.RecordCount = true, true, len(records)
+	pivotCache, err := xml.Marshal(pc)
+	if err != nil {
+		return err
+	}
+	f.saveFileList(target.pivotCacheXML, pivotCache)
+	for _, part := range sharedParts {
+		pivotTable, err := xml.Marshal(part.pt)
+		if err != nil {
+			return err
+		}
+		f.saveFileList(part.pivotTableXML, pivotTable)
+	}
+	return nil
+}
+
+// DeletePivotTable provides the method to delete the pivot table by given
+// worksheet name and pivot table name, the pivot table name is
+// case-insensitive. The cells in the range of the pivot table will be
+// cleared, and the pivot cache of the pivot table will be deleted if it isn't
+// used by other pivot tables. For example, delete the pivot table named
+// "PivotTable1" on Sheet1:
+//
+//    err := f.DeletePivotTable("Sheet1", "PivotTable1")
+//
+func (f *File) DeletePivotTable(sheet, name string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	parts, err := f.getWorkbookPivotTableParts()
+	if err != nil {
+		return err
+	}
+	sheetPath := f.sheetMap[trimSheetName(sheet)]
+	var target *pivotTablePart
+	for idx := range parts {
+		if f.sheetMap[trimSheetName(parts[idx].sheet)] == sheetPath && strings.EqualFold(parts[idx].pt.Name, name) {
+			target = &parts[idx]
+			break
+		}
+	}
+	if target == nil {
+		return ErrPivotTableNotExist{name}
+	}
+	if target.pt.Location != nil {
+		if err = f.clearPivotTableRange(ws, sheet, target.pt.Location.Ref); err != nil {
+			return err
+		}
+	}
+	rels, err := f.relsReader(getPartRelsPath(sheetPath))
+	if err != nil {
+		return err
+	}
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipPivotTable && resolvePartPath(sheetPath, rel.Target) == target.pivotTableXML {
+				f.deleteSheetRelationships(sheet, rel.ID)
+				break
+			}
+		}
+	}
+	// the pivot cache will be deleted separately if it isn't shared
+	if rels, err = f.relsReader(getPartRelsPath(target.pivotTableXML)); err != nil {
+		return err
+	}
+	if rels != nil {
+		rels.Relationships = nil
+	}
+	f.deletePartWithRels(target.pivotTableXML)
+	if target.pivotCacheXML == "" {
+		return err
+	}
+	for _, part := range parts {
+		if part.pivotTableXML != target.pivotTableXML && part.pivotCacheXML == target.pivotCacheXML {
+			return err
+		}
+	}
+	f.deleteWorkbookPivotCache(target.pivotCacheXML)
+	f.deletePartWithRels(target.pivotCacheXML)
+	return err
+}
+
+// clearPivotTableRange provides a function to clear the values and formulas
+// of the cells in the range of the pivot table by given worksheet name and
+// range reference, the styles of the cells will be kept.
+func (f *File) clearPivotTableRange(ws *xlsxWorksheet, sheet, ref string) error {
+	coordinates, err := f.areaRefToCoordinates(ref)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	sheetID := f.getSheetID(sheet)
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		if row.R < coordinates[1] || row.R > coordinates[3] {
+			continue
+		}
+		for colIdx := range row.C {
+			c := &row.C[colIdx]
+			col, _, err := CellNameToCoordinates(c.R)
+			if err != nil || col < coordinates[0] || col > coordinates[2] {
+				continue
+			}
+			if c.F != nil {
+				if err = f.deleteCalcChain(sheetID, c.R); err != nil {
+					return err
+				}
+			}
+			*c = xlsxC{R: c.R, S: c.S}
+		}
+	}
+	return err
+}
+
+// deleteWorkbookPivotCache provides a function to remove the pivot cache and
+// the relationship of the pivot cache from the workbook by given pivot cache
+// definition part path.
+func (f *File) deleteWorkbookPivotCache(pivotCacheXML string) {
+	wbPath := f.getWorkbookPath()
+	rels, _ := f.relsReader(f.getWorkbookRelsPath())
+	if rels == nil {
+		return
+	}
+	for idx, rel := range rels.Relationships {
+		if rel.Type != SourceRelationshipPivotCache || resolvePartPath(wbPath, rel.Target) != pivotCacheXML {
+			continue
+		}
+		rels.Relationships = append(rels.Relationships[:idx], rels.Relationships[idx+1:]...)
+		wb, _ := f.workbookReader()
+		if wb.PivotCaches == nil {
+			return
+		}
+		for i, pivotCache := range wb.PivotCaches.PivotCache {
+			if pivotCache.RID == rel.ID {
+				wb.PivotCaches.PivotCache = append(wb.PivotCaches.PivotCache[:i], wb.PivotCaches.PivotCache[i+1:]...)
+				break
+			}
+		}
+		if len(wb.PivotCaches.PivotCache) == 0 {
+			wb.PivotCaches = nil
+		}
+		return
+	}
+}
+
+// getPivotCacheSource provides a function to get the worksheet name and the
+// coordinates of the source range of the pivot cache.
+func (f *File) getPivotCacheSource(pc *xlsxPivotCacheDefinition) (string, []int, error) {
+	if pc.CacheSource == nil || pc.CacheSource.WorksheetSource == nil {
+		return "", nil, errors.New("unsupported pivot cache source")
+	}
+	source := pc.CacheSource.WorksheetSource
+	if source.Ref == "" {
+		sheet, _, _, t, err := f.findTable(source.Name)
+		if err != nil {
+			return "", nil, err
+		}
+		coordinates, err := f.areaRefToCoordinates(t.Ref)
+		return sheet, coordinates, err
+	}
+	coordinates, err := f.areaRefToCoordinates(strings.Replace(source.Ref, "$", "", -1))
+	if err != nil {
+		return "", nil, err
+	}
+	_ = sortCoordinates(coordinates)
+	return source.Sheet, coordinates, nil
+}
+
+// getPivotCacheSourceValues provides a function to get the values of the
+// cells in the source range of the pivot cache by given coordinates, the
+// first row of the values is the header row.
+func (f *File) getPivotCacheSourceValues(ws *xlsxWorksheet, coordinates []int) ([][]sortValue, error) {
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]sortValue, coordinates[3]-coordinates[1]+1)
+	for row := range values {
+		values[row] = make([]sortValue, coordinates[2]-coordinates[0]+1)
+		for col := range values[row] {
+			values[row][col].kind = sortValueBlank
+		}
+	}
+	for _, r := range ws.SheetData.Row {
+		if r.R < coordinates[1] || r.R > coordinates[3] {
+			continue
+		}
+		for idx := range r.C {
+			col, _, err := CellNameToCoordinates(r.C[idx].R)
+			if err != nil || col < coordinates[0] || col > coordinates[2] {
+				continue
+			}
+			values[r.R-coordinates[1]][col-coordinates[0]] = getSortValue(&r.C[idx], sst)
+		}
+	}
+	return values, nil
+}
+
+// getPivotCacheItemName provides a function to get the name of the shared
+// item in the pivot cache by given cell value.
+func getPivotCacheItemName(value sortValue) string {
+	if value.kind == sortValueBool {
+		if value.str == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return value.str
+}
+
+// refreshPivotCacheField provides a function to rebuild the shared items of
+// the pivot cache field by given field index and the values of the field in
+// the source data, update the items of the pivot fields in the pivot tables
+// which use the pivot cache, and returns the values of the field in the
+// pivot cache records. The grouping of the field will be kept if the field
+// still contains numeric values only.
+func refreshPivotCacheField(pc *xlsxPivotCacheDefinition, field int, values []sortValue, parts []*pivotTablePart, date1904 bool) []string {
+	cacheField, oldItems := pc.CacheFields.CacheField[field], []string{}
+	if cacheField.SharedItems != nil && cacheField.SharedItems.M != nil {
+		oldItems = append(oldItems, "")
+	}
+	oldItems = append(oldItems, getSharedItemsValues(cacheField.SharedItems)...)
+	var calculatedItems []*xlsxPivotAreaReference
+	if pc.CalculatedItems != nil {
+		for _, item := range pc.CalculatedItems.CalculatedItem {
+			if item.PivotArea == nil || item.PivotArea.References == nil {
+				continue
+			}
+			for _, ref := range item.PivotArea.References.Reference {
+				if ref.Field != nil && *ref.Field == field && len(ref.X) > 0 && ref.X[0].V < len(oldItems) {
+					calculatedItems = append(calculatedItems, ref)
+				}
+			}
+		}
+	}
+	sharedItems, numeric := getPivotCacheNumericItems(values)
+	grouped := cacheField.FieldGroup != nil && cacheField.FieldGroup.RangePr != nil && numeric && sharedItems.ContainsNumber
+	hasItems, blank := !grouped && (len(calculatedItems) > 0 || !numeric), sharedItems.ContainsBlank
+	for _, part := range parts {
+		if !grouped && part.pt.PivotFields != nil && field < len(part.pt.PivotFields.PivotField) &&
+			part.pt.PivotFields.PivotField[field].Axis != "" {
+			hasItems = true
+		}
+	}
+	contents := make([]string, len(values))
+	if !hasItems {
+		dateGroup := grouped && cacheField.FieldGroup.RangePr.GroupBy != "" && cacheField.FieldGroup.RangePr.GroupBy != "range"
+		if dateGroup {
+			setPivotCacheDateItems(sharedItems, date1904)
+		}
+		cacheField.SharedItems = sharedItems
+		for row, value := range values {
+			contents[row] = "<m/>"
+			if value.kind == sortValueNumber {
+				contents[row] = fmt.Sprintf(`<n v="%s"/>`, value.str)
+				if dateGroup {
+					contents[row] = fmt.Sprintf(`<d v="%s"/>`, timeFromExcelTime(value.num, date1904).Format(pivotCacheDateLayout))
+				}
+			}
+		}
+		return contents
+	}
+	cacheField.FieldGroup = nil
+	var items []string
+	itemsIndex := make(map[string]int)
+	if blank {
+		items, itemsIndex[""] = append(items, ""), 0
+	}
+	for row, value := range values {
+		name := getPivotCacheItemName(value)
+		if value.kind == sortValueBlank {
+			name = ""
+		}
+		idx, ok := itemsIndex[name]
+		if !ok {
+			idx = len(items)
+			items, itemsIndex[name] = append(items, name), idx
+		}
+		contents[row] = fmt.Sprintf(`<x v="%d"/>`, idx)
+	}
+	dataItems := len(items)
+	for _, ref := range calculatedItems {
+		name := oldItems[ref.X[0].V]
+		idx, ok := itemsIndex[name]
+		if !ok {
+			idx = len(items)
+			items, itemsIndex[name] = append(items, name), idx
+		}
+		ref.X[0].V = idx
+	}
+	cacheField.SharedItems = &xlsxSharedItems{ContainsBlank: blank, Count: len(items)}
+	for idx, item := range items {
+		if blank && idx == 0 {
+			cacheField.SharedItems.M = &xlsxMissing{}
+			continue
+		}
+		cacheField.SharedItems.S = append(cacheField.SharedItems.S, &xlsxString{V: item})
+	}
+	for _, part := range parts {
+		if part.pt.PivotFields == nil || field >= len(part.pt.PivotFields.PivotField) ||
+			part.pt.PivotFields.PivotField[field].Items == nil {
+			continue
+		}
+		pivotField := part.pt.PivotFields.PivotField[field]
+		var fieldItems, typeItems []*xlsxItem
+		used := make(map[int]bool)
+		for _, item := range pivotField.Items.Item {
+			if item.X == nil {
+				typeItems = append(typeItems, item)
+				continue
+			}
+			if *item.X >= len(oldItems) {
+				continue
+			}
+			if idx, ok := itemsIndex[oldItems[*item.X]]; ok && !used[idx] {
+				item.X, item.F, used[idx] = intPtr(idx), idx >= dataItems, true
+				fieldItems = append(fieldItems, item)
+			}
+		}
+		for idx := range items {
+			if !used[idx] {
+				fieldItems = append(fieldItems, &xlsxItem{X: intPtr(idx), F: idx >= dataItems})
+			}
+		}
+		pivotField.Items.Item = append(fieldItems, typeItems...)
+		pivotField.Items.Count = len(pivotField.Items.Item)
+	}
+	return contents
+}
+
+// getPivotCacheRecordsPath provides a function to get the path of the pivot
+// cache records part by given pivot cache definition part path, the pivot
+// cache records part will be created if it doesn't exist.
+func (f *File) getPivotCacheRecordsPath(pivotCacheXML string, pc *xlsxPivotCacheDefinition) string {
+	relsPath := getPartRelsPath(pivotCacheXML)
+	if pc.RID != "" {
+		if rels, _ := f.relsReader(relsPath); rels != nil {
+			for _, rel := range rels.Relationships {
+				if rel.ID == pc.RID {
+					return resolvePartPath(pivotCacheXML, rel.Target)
+				}
+			}
+		}
+	}
+	recordsID := f.countPivotCacheRecords() + 1
+	rID := f.addRels(relsPath, SourceRelationshipPivotCacheRecords, fmt.Sprintf("pivotCacheRecords%d.xml", recordsID), "")
+	pc.RID = "rId" + strconv.Itoa(rID)
+	f.addContentTypePart(recordsID, "pivotCacheRecords")
+	return fmt.Sprintf("xl/pivotCache/pivotCacheRecords%d.xml", recordsID)
+}
+
+// pivotTableReader provides a function to get the pointer to the structure
+// after deserialization of the pivot table part by given part path.
+func (f *File) pivotTableReader(path string) (*xlsxPivotTableDefinition, error) {
+	pt := xlsxPivotTableDefinition{}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(&pt); err != nil && err != io.EOF {
+		return &pt, err
+	}
+	return &pt, nil
+}
+
+// pivotCacheReader provides a function to get the pointer to the structure
+// after deserialization of the pivot cache definition part by given part
+// path.
+func (f *File) pivotCacheReader(path string) (*xlsxPivotCacheDefinition, error) {
+	pc := xlsxPivotCacheDefinition{}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(&pc); err != nil && err != io.EOF {
+		return &pc, err
+	}
+	return &pc, nil
+}
@@ -1,11 +1,13 @@
 package excelize
 
 import (
+	"encoding/xml"
 	"fmt"
 	"math/rand"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -299,3 +301,532 @@ func TestGetPivotTableFieldName(t *testing.T) {
 	f := NewFile()
 	f.getPivotTableFieldName("-", []PivotTableField{})
 }
+
+func TestGetPivotTables(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{"Jan", 2020, "Meat", i * 100, "East"}))
+	}
+	expected := []PivotTableOption{{
+		Name:                "Sales Summary",
+		DataRange:           "Sheet1!A1:E11",
+		PivotTableRange:     "Sheet1!G2:M34",
+		Rows:                []PivotTableField{{Data: "Month", Name: "Months", DefaultSubtotal: true}, {Data: "Year"}},
+		Columns:             []PivotTableField{{Data: "Type", DefaultSubtotal: true}},
+		Data:                []PivotTableField{{Data: "Sales", Name: "Summarize by Max", Subtotal: "Max"}},
+		Filter:              []PivotTableField{{Data: "Region", Name: "Region Filter"}},
+		RowGrandTotals:      true,
+		ColGrandTotals:      true,
+		ShowDrill:           true,
+		CompactData:         true,
+		ShowRowHeaders:      true,
+		ShowColHeaders:      true,
+		ShowLastColumn:      true,
+		PivotTableStyleName: "PivotStyleLight16",
+		ReportLayout:        "tabular",
+	}, {
+		Name:                "Pivot Table2",
+		DataRange:           "Sheet1!A1:E11",
+		PivotTableRange:     "Sheet1!O2:U34",
+		Rows:                []PivotTableField{{Data: "Month"}},
+		Data:                []PivotTableField{{Data: "Sales", Subtotal: "Sum"}, {Data: "Sales", Subtotal: "CountNums"}},
+		UseAutoFormatting:   true,
+		PageOverThenDown:    true,
+		MergeItem:           true,
+		ShowRowStripes:      true,
+		ShowColStripes:      true,
+		PivotTableStyleName: "PivotStyleLight19",
+		ReportLayout:        "tabular",
+	}}
+	for _, opt := range expected {
+		opt := opt
+		opt.DataRange, opt.PivotTableRange = strings.Replace(opt.DataRange, "!", "!$", 1), strings.Replace(opt.PivotTableRange, "!", "!$", 1)
+		assert.NoError(t, f.AddPivotTable(&opt))
+	}
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, pivotTables)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetPivotTables.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestGetPivotTables.xlsx"))
+	assert.NoError(t, err)
+	pivotTables, err = f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, pivotTables)
+	f.NewSheet("Sheet2")
+	pivotTables, err = f.GetPivotTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Nil(t, pivotTables)
+
+	// Test get pivot tables on not exist worksheet.
+	_, err = f.GetPivotTables("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get pivot tables with unsupported charset.
+	f.XLSX["xl/pivotCache/pivotCacheDefinition1.xml"] = MacintoshCyrillicCharset
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	f.XLSX["xl/pivotTables/pivotTable1.xml"] = MacintoshCyrillicCharset
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestAddPivotTableCalculated(t *testing.T) {
+	f := NewFile()
+	types := []string{"Meat", "Dairy", "Beverages"}
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Type", "Sales", "Cost"}))
+	for i := 0; i < 9; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{"Jan", types[i%3], i * 100, i * 10}))
+	}
+	opt := PivotTableOption{
+		DataRange:        "Sheet1!$A$1:$D$10",
+		PivotTableRange:  "Sheet1!$G$2:$M$20",
+		Rows:             []PivotTableField{{Data: "Type", DefaultSubtotal: true}},
+		Data:             []PivotTableField{{Data: "Sales"}, {Data: "Profit", Name: "Sum of Profit"}},
+		CalculatedFields: []PivotTableCalculatedField{{Name: "Profit", Formula: "=Sales-Cost"}},
+		CalculatedItems:  []PivotTableCalculatedItem{{Field: "Type", Name: "Food", Formula: "Meat+Dairy"}},
+		RowGrandTotals:   true,
+		ColGrandTotals:   true,
+		ShowDrill:        true,
+		CompactData:      true,
+	}
+	assert.NoError(t, f.AddPivotTable(&opt))
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, pc.CacheFields.CacheField, 5)
+	assert.Equal(t, "Sales-Cost", pc.CacheFields.CacheField[4].Formula)
+	assert.False(t, *pc.CacheFields.CacheField[4].DatabaseField)
+	assert.Equal(t, []string{"Meat", "Dairy", "Beverages", "Food"}, getSharedItemsValues(pc.CacheFields.CacheField[1].SharedItems))
+	assert.Equal(t, 1, pc.CalculatedItems.Count)
+	assert.Equal(t, "Meat+Dairy", pc.CalculatedItems.CalculatedItem[0].Formula)
+	assert.Equal(t, 1, *pc.CalculatedItems.CalculatedItem[0].PivotArea.References.Reference[0].Field)
+	assert.Equal(t, 3, pc.CalculatedItems.CalculatedItem[0].PivotArea.References.Reference[0].X[0].V)
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, pt.PivotFields.PivotField, 5)
+	assert.True(t, pt.PivotFields.PivotField[4].DataField)
+	assert.Equal(t, 5, pt.PivotFields.PivotField[1].Items.Count)
+	assert.True(t, pt.PivotFields.PivotField[1].Items.Item[3].F)
+	assert.Equal(t, "default", pt.PivotFields.PivotField[1].Items.Item[4].T)
+	assert.Equal(t, 4, pt.DataFields.DataField[1].Fld)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPivotTableCalculated.xlsx")))
+
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []PivotTableCalculatedField{{Name: "Profit", Formula: "Sales-Cost"}}, pivotTables[0].CalculatedFields)
+	assert.Equal(t, opt.CalculatedItems, pivotTables[0].CalculatedItems)
+	assert.Equal(t, []PivotTableField{{Data: "Sales", Subtotal: "Sum"}, {Data: "Profit", Name: "Sum of Profit", Subtotal: "Sum"}}, pivotTables[0].Data)
+
+	// Test add pivot table with invalid calculated fields and items.
+	opt.CalculatedFields = []PivotTableCalculatedField{{Name: "Profit"}}
+	assert.EqualError(t, f.AddPivotTable(&opt), "parameter 'CalculatedFields' is invalid")
+	opt.CalculatedFields = nil
+	opt.CalculatedItems = []PivotTableCalculatedItem{{Field: "Type", Name: "Food"}}
+	assert.EqualError(t, f.AddPivotTable(&opt), "parameter 'CalculatedItems' is invalid")
+	opt.CalculatedItems = []PivotTableCalculatedItem{{Field: "Region", Name: "Food", Formula: "Meat+Dairy"}}
+	assert.EqualError(t, f.AddPivotTable(&opt), "parameter 'CalculatedItems' parsing error: field Region is not exist")
+	// Test get pivot field items with invalid data range.
+	_, _, err = f.getPivotFieldItems(&PivotTableOption{DataRange: "SheetN!$A$1:$D$10", CalculatedItems: []PivotTableCalculatedItem{{Field: "Type"}}}, "Type")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestAddPivotTableLayout(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Type", "Sales"}))
+	for i := 0; i < 6; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{"Jan", "Meat", i * 100}))
+	}
+	for i, opt := range []PivotTableOption{
+		{ReportLayout: "compact", SubtotalsAtBottom: true},
+		{ReportLayout: "Outline", InsertBlankRow: true, RepeatItemLabels: true},
+		{ReportLayout: "tabular", ColGrandTotals: true, ShowRowStripes: true, PivotTableStyleName: "PivotStyleMedium2"},
+	} {
+		opt.DataRange = "Sheet1!$A$1:$C$7"
+		opt.PivotTableRange = fmt.Sprintf("Sheet1!$E$%d:$H$%d", i*10+1, i*10+8)
+		opt.Rows = []PivotTableField{{Data: "Month", DefaultSubtotal: true}, {Data: "Type"}}
+		opt.Data = []PivotTableField{{Data: "Sales"}}
+		assert.NoError(t, f.AddPivotTable(&opt))
+	}
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	assert.True(t, *pt.Compact)
+	assert.True(t, *pt.Outline)
+	assert.True(t, *pt.PivotFields.PivotField[0].Compact)
+	assert.False(t, *pt.PivotFields.PivotField[0].SubtotalTop)
+	pt, err = f.pivotTableReader("xl/pivotTables/pivotTable2.xml")
+	assert.NoError(t, err)
+	assert.False(t, *pt.Compact)
+	assert.True(t, *pt.PivotFields.PivotField[0].Outline)
+	assert.True(t, pt.PivotFields.PivotField[0].InsertBlankRow)
+	assert.True(t, getPivotFieldFillDownLabels(pt.PivotFields.PivotField[0]))
+	assert.False(t, getPivotFieldFillDownLabels(pt.PivotFields.PivotField[2]))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPivotTableLayout.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestAddPivotTableLayout.xlsx"))
+	assert.NoError(t, err)
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 3)
+	assert.Equal(t, []interface{}{"compact", true, false, false}, []interface{}{pivotTables[0].ReportLayout, pivotTables[0].SubtotalsAtBottom, pivotTables[0].InsertBlankRow, pivotTables[0].RepeatItemLabels})
+	assert.Equal(t, []interface{}{"outline", false, true, true}, []interface{}{pivotTables[1].ReportLayout, pivotTables[1].SubtotalsAtBottom, pivotTables[1].InsertBlankRow, pivotTables[1].RepeatItemLabels})
+	assert.Equal(t, []interface{}{"tabular", false, true, false, true, "PivotStyleMedium2"}, []interface{}{pivotTables[2].ReportLayout, pivotTables[2].RowGrandTotals, pivotTables[2].ColGrandTotals, pivotTables[2].InsertBlankRow, pivotTables[2].ShowRowStripes, pivotTables[2].PivotTableStyleName})
+
+	// Test add pivot table with invalid report layout.
+	assert.EqualError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$C$7",
+		PivotTableRange: "Sheet1!$E$1:$H$8",
+		ReportLayout:    "unknown",
+	}), "parameter 'ReportLayout' is invalid")
+	// Test get fill down labels with invalid extension.
+	assert.False(t, getPivotFieldFillDownLabels(&xlsxPivotField{ExtLst: &xlsxExtLst{Ext: "<ext"}}))
+}
+
+func TestRefreshPivotCache(t *testing.T) {
+	f := NewFile()
+	types := []string{"Meat", "Dairy", "Beverages"}
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Type", "Sales"}))
+	for i := 0; i < 9; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{"Jan", types[i%3], i * 100}))
+	}
+	opt := PivotTableOption{
+		Name:            "PivotTable1",
+		DataRange:       "Sheet1!$A$1:$C$10",
+		PivotTableRange: "Sheet1!$G$2:$M$20",
+		Rows:            []PivotTableField{{Data: "Type", DefaultSubtotal: true}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+		CalculatedItems: []PivotTableCalculatedItem{{Field: "Type", Name: "Food", Formula: "Meat+Dairy"}},
+	}
+	assert.NoError(t, f.AddPivotTable(&opt))
+	// Update the source data and refresh the pivot cache.
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", "Amount"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B3", "Fish"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B6", nil))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C10", 2.5))
+	assert.NoError(t, f.RefreshPivotCache("pivottable1"))
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.True(t, pc.RefreshOnLoad)
+	assert.True(t, pc.SaveData)
+	assert.Equal(t, 9, pc.RecordCount)
+	assert.Equal(t, "Amount", pc.CacheFields.CacheField[2].Name)
+	assert.Equal(t, []string{"Jan"}, getSharedItemsValues(pc.CacheFields.CacheField[0].SharedItems))
+	sharedItems := pc.CacheFields.CacheField[1].SharedItems
+	assert.NotNil(t, sharedItems.M)
+	assert.True(t, sharedItems.ContainsBlank)
+	assert.Equal(t, 6, sharedItems.Count)
+	assert.Equal(t, []string{"Meat", "Fish", "Beverages", "Dairy", "Food"}, getSharedItemsValues(sharedItems))
+	assert.Equal(t, 5, pc.CalculatedItems.CalculatedItem[0].PivotArea.References.Reference[0].X[0].V)
+	sharedItems = pc.CacheFields.CacheField[2].SharedItems
+	assert.Nil(t, sharedItems.S)
+	assert.True(t, sharedItems.ContainsNumber)
+	assert.False(t, sharedItems.ContainsInteger)
+	assert.Equal(t, 0.0, *sharedItems.MinValue)
+	assert.Equal(t, 700.0, *sharedItems.MaxValue)
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	var items []int
+	for _, item := range pt.PivotFields.PivotField[1].Items.Item {
+		if item.X != nil {
+			items = append(items, *item.X)
+		}
+	}
+	assert.Equal(t, []int{1, 4, 3, 5, 0, 2}, items)
+	assert.True(t, pt.PivotFields.PivotField[1].Items.Item[3].F)
+	assert.Equal(t, "default", pt.PivotFields.PivotField[1].Items.Item[6].T)
+	records := xlsxPivotCacheRecords{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/pivotCache/pivotCacheRecords1.xml"), &records))
+	assert.Equal(t, 9, records.Count)
+	assert.Equal(t, `<x v="0"/><x v="2"/><n v="100"/>`, records.R[1].Content)
+	assert.Equal(t, `<x v="0"/><x v="0"/><n v="400"/>`, records.R[4].Content)
+	// Test refresh the pivot cache again with the existing records part.
+	assert.NoError(t, f.SetCellValue("Sheet1", "C10", "N/A"))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	assert.Equal(t, 1, f.countPivotCacheRecords())
+	pc, err = f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "100", "200", "300", "400", "500", "600", "700", "N/A"}, getSharedItemsValues(pc.CacheFields.CacheField[2].SharedItems))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRefreshPivotCache.xlsx")))
+
+	// Test refresh the pivot cache created from a table.
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "C10", `{"table_name":"Sales"}`))
+	pc.CacheSource.WorksheetSource = &xlsxWorksheetSource{Name: "Sales"}
+	pivotCache, err := xml.Marshal(pc)
+	assert.NoError(t, err)
+	f.saveFileList("xl/pivotCache/pivotCacheDefinition1.xml", pivotCache)
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+
+	// Test refresh the pivot cache with invalid settings.
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable2"), "pivot table PivotTable2 is not exist")
+	pc.CacheSource.WorksheetSource = &xlsxWorksheetSource{Name: "Table1"}
+	pivotCache, err = xml.Marshal(pc)
+	assert.NoError(t, err)
+	f.saveFileList("xl/pivotCache/pivotCacheDefinition1.xml", pivotCache)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "table Table1 is not exist")
+	pc.CacheSource.WorksheetSource = &xlsxWorksheetSource{Ref: "A1:B10", Sheet: "Sheet1"}
+	pivotCache, err = xml.Marshal(pc)
+	assert.NoError(t, err)
+	f.saveFileList("xl/pivotCache/pivotCacheDefinition1.xml", pivotCache)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "the fields of the pivot cache source are changed")
+	pc.CacheSource.WorksheetSource.Ref = "A:B10"
+	pivotCache, err = xml.Marshal(pc)
+	assert.NoError(t, err)
+	f.saveFileList("xl/pivotCache/pivotCacheDefinition1.xml", pivotCache)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	pc.CacheSource.WorksheetSource = &xlsxWorksheetSource{Ref: "A1:C10", Sheet: "SheetN"}
+	pivotCache, err = xml.Marshal(pc)
+	assert.NoError(t, err)
+	f.saveFileList("xl/pivotCache/pivotCacheDefinition1.xml", pivotCache)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "sheet SheetN is not exist")
+	pc.CacheSource.WorksheetSource = nil
+	pivotCache, err = xml.Marshal(pc)
+	assert.NoError(t, err)
+	f.saveFileList("xl/pivotCache/pivotCacheDefinition1.xml", pivotCache)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "unsupported pivot cache source")
+	f.Relationships["xl/pivotTables/_rels/pivotTable1.xml.rels"].Relationships = nil
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "pivot cache of the pivot table PivotTable1 is not exist")
+	f.XLSX["xl/pivotTables/pivotTable1.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestDeletePivotTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Type", "Sales"}))
+	for i := 0; i < 9; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{"Jan", "Meat", i * 100}))
+	}
+	for _, opt := range []PivotTableOption{
+		{Name: "PivotTable1", DataRange: "Sheet1!$A$1:$C$10", PivotTableRange: "Sheet1!$E$2:$G$10", Rows: []PivotTableField{{Data: "Type"}}, Data: []PivotTableField{{Data: "Sales"}}},
+		{Name: "PivotTable2", DataRange: "Sheet1!$A$1:$C$10", PivotTableRange: "Sheet1!$I$2:$K$10", Rows: []PivotTableField{{Data: "Month"}}, Data: []PivotTableField{{Data: "Sales"}}},
+	} {
+		opt := opt
+		assert.NoError(t, f.AddPivotTable(&opt))
+	}
+	// Share the pivot cache of the first pivot table with the second pivot table.
+	f.Relationships["xl/pivotTables/_rels/pivotTable2.xml.rels"].Relationships[0].Target = "../pivotCache/pivotCacheDefinition1.xml"
+	style, err := f.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "E2", "Row Labels"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "E2", "E2", style))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F3", "SUM(C2:C10)"))
+
+	assert.NoError(t, f.DeletePivotTable("Sheet1", "pivottable1"))
+	for cell, expected := range map[string]string{"E2": "", "F3": "", "C10": "800"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	formula, err := f.GetCellFormula("Sheet1", "F3")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+	styleID, err := f.GetCellStyle("Sheet1", "E2")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	_, ok := f.XLSX["xl/pivotTables/pivotTable1.xml"]
+	assert.False(t, ok)
+	_, ok = f.XLSX["xl/pivotCache/pivotCacheDefinition1.xml"]
+	assert.True(t, ok)
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Len(t, wb.PivotCaches.PivotCache, 2)
+
+	assert.NoError(t, f.DeletePivotTable("Sheet1", "PivotTable2"))
+	_, ok = f.XLSX["xl/pivotCache/pivotCacheDefinition1.xml"]
+	assert.False(t, ok)
+	wb, err = f.workbookReader()
+	assert.NoError(t, err)
+	assert.Len(t, wb.PivotCaches.PivotCache, 1)
+	pivotTables, err = f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, pivotTables)
+	contentTypes, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	for _, override := range contentTypes.Overrides {
+		assert.NotContains(t, []string{"/xl/pivotTables/pivotTable1.xml", "/xl/pivotTables/pivotTable2.xml", "/xl/pivotCache/pivotCacheDefinition1.xml"}, override.PartName)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeletePivotTable.xlsx")))
+
+	// Test delete the pivot table with invalid settings.
+	assert.EqualError(t, f.DeletePivotTable("SheetN", "PivotTable1"), "sheet SheetN is not exist")
+	assert.EqualError(t, f.DeletePivotTable("Sheet1", "PivotTable1"), "pivot table PivotTable1 is not exist")
+	assert.EqualError(t, f.clearPivotTableRange(nil, "Sheet1", "A:B"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	f = NewFile()
+	f.Relationships["xl/worksheets/_rels/sheet1.xml.rels"] = &xlsxRelationships{Relationships: []xlsxRelationship{{Type: SourceRelationshipPivotTable, Target: "../pivotTables/pivotTable1.xml"}}}
+	f.XLSX["xl/pivotTables/pivotTable1.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.DeletePivotTable("Sheet1", "PivotTable1"), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestAddPivotTableDataFieldFormat(t *testing.T) {
+	f := NewFile()
+	months := []string{"Jan", "Feb", "Mar"}
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Type", "Sales"}))
+	for i := 0; i < 9; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{months[i%3], "Meat", i * 100}))
+	}
+	opt := PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$C$10",
+		PivotTableRange: "Sheet1!$E$2:$K$20",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Columns:         []PivotTableField{{Data: "Type"}},
+		Data: []PivotTableField{
+			{Data: "Sales", Name: "Total", Subtotal: "Sum", NumFmt: 3},
+			{Data: "Sales", Name: "Percent", Subtotal: "Sum", NumFmt: 10, ShowDataAs: "PercentOfTotal"},
+			{Data: "Sales", Name: "Difference", Subtotal: "Sum", CustomNumFmt: "#,##0.0;[Red]-#,##0.0", ShowDataAs: "Difference", BaseField: "Month", BaseItem: "Feb"},
+			{Data: "Sales", Name: "Running", Subtotal: "Sum", ShowDataAs: "RunTotal", BaseField: "Month"},
+			{Data: "Sales", Name: "Previous", Subtotal: "Sum", ShowDataAs: "PercentDiff", BaseField: "Month", BaseItem: "(previous)"},
+		},
+	}
+	assert.NoError(t, f.AddPivotTable(&opt))
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	dataFields := pt.DataFields.DataField
+	assert.Equal(t, "3", dataFields[0].NumFmtID)
+	assert.Empty(t, dataFields[0].ShowDataAs)
+	assert.Nil(t, dataFields[0].BaseField)
+	assert.Equal(t, "percentOfTotal", dataFields[1].ShowDataAs)
+	assert.Equal(t, "164", dataFields[2].NumFmtID)
+	assert.Equal(t, "difference", dataFields[2].ShowDataAs)
+	assert.Equal(t, 0, *dataFields[2].BaseField)
+	assert.Equal(t, int64(1), *dataFields[2].BaseItem)
+	assert.Equal(t, "runTotal", dataFields[3].ShowDataAs)
+	assert.Equal(t, int64(0), *dataFields[3].BaseItem)
+	assert.Equal(t, int64(1048828), *dataFields[4].BaseItem)
+	assert.Equal(t, 3, pt.PivotFields.PivotField[0].Items.Count)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPivotTableDataFieldFormat.xlsx")))
+
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, opt.Data, pivotTables[0].Data)
+
+	// Test add pivot table with invalid data field settings.
+	opt.PivotTableRange = "Sheet1!$E$22:$K$40"
+	for _, field := range []struct {
+		field PivotTableField
+		err   string
+	}{
+		{PivotTableField{Data: "Sales", NumFmt: 200}, "parameter 'NumFmt' is invalid"},
+		{PivotTableField{Data: "Sales", ShowDataAs: "Rank"}, "parameter 'ShowDataAs' is invalid"},
+		{PivotTableField{Data: "Sales", ShowDataAs: "Percent", BaseField: "Region"}, "parameter 'BaseField' parsing error: field Region is not exist"},
+		{PivotTableField{Data: "Sales", ShowDataAs: "Percent", BaseField: "Month", BaseItem: "Apr"}, "parameter 'BaseItem' parsing error: item Apr is not exist"},
+	} {
+		opt.Data = []PivotTableField{field.field}
+		assert.EqualError(t, f.AddPivotTable(&opt), field.err)
+	}
+	opt.Data = []PivotTableField{{Data: "Sales", ShowDataAs: "Normal"}}
+	assert.NoError(t, f.AddPivotTable(&opt))
+	_, _, _, err = f.getPivotDataFieldShowDataAs(&PivotTableOption{DataRange: "SheetN!$A$1:$C$10"}, &PivotTableField{ShowDataAs: "RunTotal"})
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestAddPivotTableGroups(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Date", "Sales", "Type"}))
+	for i := 0; i < 9; i++ {
+		date := time.Date(2019, time.Month(i+1), 15, 0, 0, 0, 0, time.UTC)
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{date, i * 100, "Meat"}))
+	}
+	opt := PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$C$10",
+		PivotTableRange: "Sheet1!$E$2:$K$20",
+		Rows:            []PivotTableField{{Data: "Date"}},
+		Columns:         []PivotTableField{{Data: "Sales"}},
+		Data:            []PivotTableField{{Data: "Type", Subtotal: "Count"}},
+		Groups: []PivotTableFieldGroup{
+			{Field: "Date", GroupBy: "Months"},
+			{Field: "Sales", StartNum: 0, EndNum: 800, Interval: 250},
+		},
+	}
+	assert.NoError(t, f.AddPivotTable(&opt))
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	date := pc.CacheFields.CacheField[0]
+	assert.True(t, date.SharedItems.ContainsDate)
+	assert.Equal(t, "2019-01-15T00:00:00", date.SharedItems.MinDate)
+	assert.Equal(t, "2019-09-15T00:00:00", date.SharedItems.MaxDate)
+	assert.Equal(t, "months", date.FieldGroup.RangePr.GroupBy)
+	assert.Equal(t, "2019-01-15T00:00:00", date.FieldGroup.RangePr.StartDate)
+	assert.Equal(t, 14, date.FieldGroup.GroupItems.Count)
+	assert.Equal(t, "<1/15/2019", date.FieldGroup.GroupItems.S[0].V)
+	assert.Equal(t, "Jan", date.FieldGroup.GroupItems.S[1].V)
+	assert.Equal(t, ">9/15/2019", date.FieldGroup.GroupItems.S[13].V)
+	sales := pc.CacheFields.CacheField[1]
+	assert.True(t, sales.SharedItems.ContainsInteger)
+	assert.Equal(t, float64(800), *sales.SharedItems.MaxValue)
+	assert.Equal(t, float64(250), sales.FieldGroup.RangePr.GroupInterval)
+	assert.False(t, *sales.FieldGroup.RangePr.AutoStart)
+	var items []string
+	for _, item := range sales.FieldGroup.GroupItems.S {
+		items = append(items, item.V)
+	}
+	assert.Equal(t, []string{"<0", "0-249", "250-499", "500-749", "750-999", ">800"}, items)
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 14, pt.PivotFields.PivotField[0].Items.Count)
+	assert.Equal(t, 6, pt.PivotFields.PivotField[1].Items.Count)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPivotTableGroups.xlsx")))
+
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []PivotTableFieldGroup{
+		{Field: "Date", GroupBy: "Months", Interval: 1},
+		{Field: "Sales", GroupBy: "Range", EndNum: 800, Interval: 250},
+	}, pivotTables[0].Groups)
+
+	// Test refresh the pivot cache of the pivot table with grouped fields.
+	assert.NoError(t, f.RefreshPivotCache(pt.Name))
+	pc, err = f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.NotNil(t, pc.CacheFields.CacheField[0].FieldGroup)
+	assert.Equal(t, "2019-01-15T00:00:00", pc.CacheFields.CacheField[0].SharedItems.MinDate)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "N/A"))
+	assert.NoError(t, f.RefreshPivotCache(pt.Name))
+	pc, err = f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Nil(t, pc.CacheFields.CacheField[1].FieldGroup)
+
+	// Test add pivot table with invalid grouping settings.
+	opt.PivotTableRange = "Sheet1!$E$22:$K$40"
+	for _, group := range []struct {
+		group PivotTableFieldGroup
+		err   string
+	}{
+		{PivotTableFieldGroup{Field: "Region"}, "parameter 'Groups' parsing error: field Region is not exist"},
+		{PivotTableFieldGroup{Field: "Date", GroupBy: "Weeks"}, "parameter 'GroupBy' is invalid"},
+		{PivotTableFieldGroup{Field: "Date", GroupBy: "Months", Interval: 2}, "parameter 'Interval' is invalid"},
+		{PivotTableFieldGroup{Field: "Date", GroupBy: "Days", Interval: 1.5}, "parameter 'Interval' is invalid"},
+		{PivotTableFieldGroup{Field: "Date", Interval: 0.000001}, "parameter 'Interval' is invalid"},
+		{PivotTableFieldGroup{Field: "Date", StartNum: 1, EndNum: 0}, "parameter 'EndNum' is invalid"},
+		{PivotTableFieldGroup{Field: "Sales"}, "parameter 'Groups' parsing error: field Sales contains non-numeric values"},
+	} {
+		opt.Groups = []PivotTableFieldGroup{group.group}
+		assert.EqualError(t, f.AddPivotTable(&opt), group.err)
+	}
+	opt.Groups = []PivotTableFieldGroup{{Field: "Date", GroupBy: "Years"}}
+	opt.CalculatedItems = []PivotTableCalculatedItem{{Field: "Date", Name: "Total", Formula: "1"}}
+	assert.EqualError(t, f.AddPivotTable(&opt), "parameter 'CalculatedItems' parsing error: field Date is grouped")
+	opt.CalculatedItems = nil
+	assert.NoError(t, f.AddPivotTable(&opt))
+
+	// Test get the names of the groups of the date time values.
+	start, end := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 10, 0, 0, 0, 0, time.UTC)
+	assert.Len(t, getPivotDateGroupItems("seconds", start, end, 1), 60)
+	assert.Equal(t, "11 PM", getPivotDateGroupItems("hours", start, end, 1)[23])
+	assert.Equal(t, "29-Feb", getPivotDateGroupItems("days", start, end, 1)[59])
+	assert.Equal(t, []string{"1/1/2019 - 1/7/2019", "1/8/2019 - 1/14/2019"}, getPivotDateGroupItems("days", start, end, 7))
+	assert.Equal(t, []string{"Qtr1", "Qtr2", "Qtr3", "Qtr4"}, getPivotDateGroupItems("quarters", start, end, 1))
+	assert.Equal(t, []string{"<0.5", "0.5-1.5", "1.5-2.5", ">2"}, getPivotRangeGroupItems(0.5, 2, 1, false))
+}
+
+func TestPivotCacheDataModelParts(t *testing.T) {
+	pc := new(xlsxPivotCacheDefinition)
+	source := `<pivotCacheDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cacheSource type="external" connectionId="1"/><cacheFields count="0"></cacheFields><cacheHierarchies count="1"><cacheHierarchy uniqueName="[Sales].[Region]" caption="Region" attribute="1" defaultMemberUniqueName="[Sales].[Region].[All]" allUniqueName="[Sales].[Region].[All]" dimensionUniqueName="[Sales]" count="0" memberValueDatatype="130" unbalanced="0"/></cacheHierarchies><dimensions count="1"><dimension name="Sales" uniqueName="[Sales]" caption="Sales"/></dimensions><measureGroups count="1"><measureGroup name="Sales" caption="Sales"/></measureGroups><maps count="1"><map measureGroup="0" dimension="0"/></maps></pivotCacheDefinition>`
+	assert.NoError(t, xml.Unmarshal([]byte(source), pc))
+	assert.Equal(t, 1, pc.CacheSource.ConnectionID)
+	output, err := xml.Marshal(pc)
+	assert.NoError(t, err)
+	for _, part := range []string{`<cacheHierarchies count="1"><cacheHierarchy uniqueName="[Sales].[Region]"`, `<dimensions count="1"><dimension name="Sales"`, `<measureGroups count="1">`, `<maps count="1"><map measureGroup="0" dimension="0"/></maps>`} {
+		assert.Contains(t, string(output), part)
+	}
+}
@@ -0,0 +1,502 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// GetRepairs provides a function to get the issues which have been repaired
+// when opening the spreadsheet with the Repair of the options, the issues
+// will be returned in the order they were repaired. For example:
+//
+//    f, err := excelize.OpenFile("Book1.xlsx", excelize.Options{Repair: true})
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, issue := range f.GetRepairs() {
+//        fmt.Println(issue.Part, issue.Sheet, issue.Cell, issue.Message)
+//    }
+//
+func (f *File) GetRepairs() []ValidationIssue {
+	return f.repairs
+}
+
+// repair provides a function to repair the opened spreadsheet if the Repair
+// of the options was specified. The malformed XML of the worksheets, the
+// relationships and content type overrides of the missing parts, and the
+// worksheets which parts are missing will be repaired first, and then all
+// worksheets will be read into memory and repaired by workSheetReader.
+func (f *File) repair() error {
+	if f.options == nil || !f.options.Repair {
+		return nil
+	}
+	sheets := make(map[string]string, len(f.sheetMap))
+	for sheet, name := range f.sheetMap {
+		sheets[name] = sheet
+	}
+	parts := make([]string, 0, len(f.XLSX))
+	for part := range f.XLSX {
+		parts = append(parts, part)
+	}
+	sort.Strings(parts)
+	for _, part := range parts {
+		if path.Dir(part) != "xl/worksheets" || path.Ext(part) != ".xml" {
+			continue
+		}
+		content, issues := f.repairWorksheetXML(f.readXML(part))
+		if len(issues) == 0 {
+			continue
+		}
+		f.XLSX[part] = content
+		delete(f.lazyParts, part)
+		for _, issue := range issues {
+			issue.Part, issue.Sheet = part, sheets[part]
+			f.repairs = append(f.repairs, issue)
+		}
+	}
+	f.repairRelationships()
+	f.sheetMap, _ = f.getSheetMap()
+	for _, sheet := range f.GetSheetList() {
+		if !strings.HasPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") {
+			continue
+		}
+		if _, err := f.workSheetReader(sheet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repairRelationships provides a function to remove the worksheets which
+// parts are missing from the workbook, and remove the internal relationships
+// and content type overrides of the missing parts.
+func (f *File) repairRelationships() {
+	var (
+		parts  = f.getValidationParts()
+		exist  = make(map[string]bool, len(parts))
+		wbPath = f.getWorkbookPath()
+		wb, _  = f.workbookReader()
+	)
+	for _, part := range parts {
+		exist[part] = true
+	}
+	if wbRels, _ := f.relsReader(f.getWorkbookRelsPath()); wbRels != nil {
+		targets := make(map[string]string)
+		for _, rel := range wbRels.Relationships {
+			if rel.TargetMode != "External" {
+				targets[rel.ID] = resolveRelationshipTarget(exist, wbPath, rel.Target)
+			}
+		}
+		sheets := wb.Sheets.Sheet[:0]
+		for idx, sheet := range wb.Sheets.Sheet {
+			if target, ok := targets[sheet.ID]; (ok && exist[target]) || len(sheets)+len(wb.Sheets.Sheet)-idx == 1 {
+				sheets = append(sheets, sheet)
+				continue
+			}
+			f.repairs = append(f.repairs, ValidationIssue{
+				Type: ValidationRelationship, Part: wbPath, Sheet: sheet.Name,
+				Message: fmt.Sprintf("removed the sheet %s which part doesn't exist", sheet.Name),
+			})
+			f.deleteCalcChain(sheet.SheetID, "")
+			if wb.BookViews == nil {
+				continue
+			}
+			for i := range wb.BookViews.WorkBookView {
+				view := &wb.BookViews.WorkBookView[i]
+				if view.ActiveTab > len(sheets) {
+					view.ActiveTab--
+				}
+				if view.FirstSheet > len(sheets) {
+					view.FirstSheet--
+				}
+			}
+		}
+		wb.Sheets.Sheet = sheets
+		if wb.BookViews != nil {
+			for i := range wb.BookViews.WorkBookView {
+				view := &wb.BookViews.WorkBookView[i]
+				if view.ActiveTab >= len(sheets) {
+					view.ActiveTab = len(sheets) - 1
+				}
+				if view.FirstSheet >= len(sheets) {
+					view.FirstSheet = len(sheets) - 1
+				}
+			}
+		}
+	}
+	for _, part := range parts {
+		if path.Ext(part) != ".rels" {
+			continue
+		}
+		rels, _ := f.relsReader(part)
+		if rels == nil {
+			continue
+		}
+		source := strings.TrimSuffix(strings.Replace(part, "_rels/", "", 1), ".rels")
+		relationships := rels.Relationships[:0]
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode != "External" {
+				if target := resolveRelationshipTarget(exist, source, rel.Target); !exist[target] {
+					f.repairs = append(f.repairs, ValidationIssue{
+						Type: ValidationRelationship, Part: part,
+						Message: fmt.Sprintf("removed the relationship %s which target %s doesn't exist", rel.ID, target),
+					})
+					continue
+				}
+			}
+			relationships = append(relationships, rel)
+		}
+		rels.Relationships = relationships
+	}
+	content, _ := f.contentTypesReader()
+	overrides := content.Overrides[:0]
+	for _, override := range content.Overrides {
+		if part := strings.TrimPrefix(override.PartName, "/"); !exist[part] {
+			f.repairs = append(f.repairs, ValidationIssue{
+				Type: ValidationContentType, Part: "[Content_Types].xml",
+				Message: fmt.Sprintf("removed the content type override for part %s which doesn't exist", part),
+			})
+			continue
+		}
+		overrides = append(overrides, override)
+	}
+	content.Overrides = overrides
+}
+
+// repairWorksheetXML provides a function to repair the malformed XML of the
+// worksheet by given content. The rows and cells which are not closed before
+// the next row or cell, and the elements which are not closed before the end
+// element of their parent or the end of the content will be closed, the
+// unexpected end elements and the duplicate attributes will be removed, and
+// the content after the XML syntax error will be dropped. The repaired
+// content and the issues which have been repaired will be returned.
+func (f *File) repairWorksheetXML(content []byte) ([]byte, []ValidationIssue) {
+	type element struct {
+		name, local, ref string
+	}
+	var (
+		buf     bytes.Buffer
+		pending bool
+		stack   []element
+		issues  []ValidationIssue
+		d       = f.xmlNewDecoder(bytes.NewReader(content))
+	)
+	addIssue := func(cell, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Type: ValidationMalformedXML, Cell: cell, Message: fmt.Sprintf(format, args...)})
+	}
+	closeTag := func() {
+		if pending {
+			buf.WriteString(">")
+			pending = false
+		}
+	}
+	// closeElements closes the open elements from the top of the stack down
+	// to the given depth, the unclosed elements will be reported.
+	closeElements := func(depth int) {
+		for i := len(stack) - 1; i >= depth; i-- {
+			switch elem := stack[i]; elem.local {
+			case "row":
+				addIssue("", "closed the unclosed row %s", elem.ref)
+			case "c":
+				addIssue(elem.ref, "closed the unclosed cell %s", elem.ref)
+			default:
+				addIssue("", "closed the unclosed element %s", elem.name)
+			}
+			if pending {
+				buf.WriteString("/>")
+				pending = false
+			} else {
+				buf.WriteString("</" + stack[i].name + ">")
+			}
+		}
+		stack = stack[:depth]
+	}
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			addIssue("", "removed the content after the XML syntax error: %s", err)
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "row" || t.Name.Local == "c" {
+				open := -1
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i].local == "sheetData" {
+						if open != -1 {
+							closeElements(open)
+						}
+						break
+					}
+					if open == -1 && stack[i].local == t.Name.Local {
+						open = i
+					}
+				}
+			}
+			elem := element{name: canonicalName(t.Name), local: t.Name.Local}
+			closeTag()
+			buf.WriteString("<" + elem.name)
+			attrs := make(map[string]bool, len(t.Attr))
+			for _, attr := range t.Attr {
+				name := canonicalName(attr.Name)
+				if attrs[name] {
+					addIssue("", "removed the duplicate attribute %s of the %s element", name, elem.name)
+					continue
+				}
+				if attrs[name] = true; name == "r" {
+					elem.ref = attr.Value
+				}
+				buf.WriteString(" " + name + `="` + escapeCanonicalAttr(attr.Value) + `"`)
+			}
+			pending, stack = true, append(stack, elem)
+		case xml.EndElement:
+			name, open := canonicalName(t.Name), -1
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].name == name {
+					open = i
+					break
+				}
+			}
+			if open == -1 {
+				addIssue("", "removed the unexpected end element %s", name)
+				continue
+			}
+			closeElements(open + 1)
+			if pending {
+				buf.WriteString("/>")
+				pending = false
+			} else {
+				buf.WriteString("</" + name + ">")
+			}
+			stack = stack[:open]
+		case xml.CharData:
+			closeTag()
+			buf.WriteString(escapeCanonicalText(string(t)))
+		case xml.ProcInst:
+			closeTag()
+			if t.Target == "xml" {
+				buf.WriteString(strings.TrimSpace(XMLHeader))
+				continue
+			}
+			buf.WriteString("<?" + t.Target)
+			if len(t.Inst) > 0 {
+				buf.WriteString(" " + string(t.Inst))
+			}
+			buf.WriteString("?>")
+		case xml.Comment:
+			closeTag()
+			buf.WriteString("<!--" + string(t) + "-->")
+		case xml.Directive:
+			closeTag()
+			buf.WriteString("<!" + string(t) + ">")
+		}
+	}
+	closeElements(0)
+	return buf.Bytes(), issues
+}
+
+// repairWorksheet provides a function to repair the worksheet by given
+// worksheet name, part path and the decoded worksheet. The references of the
+// missing relationships will be removed, the rows will be sorted and the
+// duplicate rows will be merged, the cells will be sorted and the duplicate
+// cells will be removed with the last one kept, the invalid and out of range
+// rows and cells will be removed, and the dimension which doesn't match the
+// cells will be updated.
+func (f *File) repairWorksheet(sheet, name string, ws *xlsxWorksheet) {
+	var (
+		IDs    = make(map[string]bool)
+		rows   = make([]xlsxRow, 0, len(ws.SheetData.Row))
+		maxRow int
+	)
+	addIssue := func(typ ValidationIssueType, cell, format string, args ...interface{}) {
+		f.repairs = append(f.repairs, ValidationIssue{Type: typ, Part: name, Sheet: sheet, Cell: cell, Message: fmt.Sprintf(format, args...)})
+	}
+	if rels, _ := f.relsReader(getPartRelsPath(name)); rels != nil {
+		for _, rel := range rels.Relationships {
+			IDs[rel.ID] = true
+		}
+	}
+	missing := func(element, ID string) bool {
+		if ID == "" || IDs[ID] {
+			return false
+		}
+		addIssue(ValidationRelationship, "", "removed the %s with missing relationship %s", element, ID)
+		return true
+	}
+	if ws.Drawing != nil && missing("drawing", ws.Drawing.RID) {
+		ws.Drawing = nil
+	}
+	if ws.LegacyDrawing != nil && missing("legacyDrawing", ws.LegacyDrawing.RID) {
+		ws.LegacyDrawing = nil
+	}
+	if ws.LegacyDrawingHF != nil && missing("legacyDrawingHF", ws.LegacyDrawingHF.RID) {
+		ws.LegacyDrawingHF = nil
+	}
+	if ws.Picture != nil && missing("picture", ws.Picture.RID) {
+		ws.Picture = nil
+	}
+	if ws.TableParts != nil {
+		tableParts := ws.TableParts.TableParts[:0]
+		for _, tablePart := range ws.TableParts.TableParts {
+			if !missing("tablePart", tablePart.RID) {
+				tableParts = append(tableParts, tablePart)
+			}
+		}
+		if ws.TableParts.TableParts, ws.TableParts.Count = tableParts, len(tableParts); len(tableParts) == 0 {
+			ws.TableParts = nil
+		}
+	}
+	if ws.Hyperlinks != nil {
+		hyperlinks := ws.Hyperlinks.Hyperlink[:0]
+		for _, link := range ws.Hyperlinks.Hyperlink {
+			if !missing("hyperlink", link.RID) {
+				hyperlinks = append(hyperlinks, link)
+			}
+		}
+		if ws.Hyperlinks.Hyperlink = hyperlinks; len(hyperlinks) == 0 {
+			ws.Hyperlinks = nil
+		}
+	}
+	for _, row := range ws.SheetData.Row {
+		if row.R == 0 {
+			row.R = maxRow + 1
+		}
+		if row.R < 1 || row.R > TotalRows {
+			addIssue(ValidationCellReference, "", "removed the row %d out of range", row.R)
+			continue
+		}
+		if row.R < maxRow {
+			addIssue(ValidationCellReference, "", "moved the row %d into ascending order", row.R)
+		}
+		if row.R > maxRow {
+			maxRow = row.R
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].R < rows[j].R })
+	ws.SheetData.Row = rows[:0]
+	for _, row := range rows {
+		if last := len(ws.SheetData.Row) - 1; last >= 0 && ws.SheetData.Row[last].R == row.R {
+			addIssue(ValidationCellReference, "", "merged the duplicate row %d", row.R)
+			ws.SheetData.Row[last].C = append(ws.SheetData.Row[last].C, row.C...)
+			continue
+		}
+		ws.SheetData.Row = append(ws.SheetData.Row, row)
+	}
+	for idx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[idx]
+		row.C = repairCells(row.R, row.C, func(cell, format string, args ...interface{}) {
+			addIssue(ValidationCellReference, cell, format, args...)
+		})
+	}
+	if ws.Dimension == nil {
+		return
+	}
+	hCell, vCell, _ := getUsedRange(ws, func(c *xlsxC) bool { return true })
+	if hCell == "" {
+		hCell, vCell = "A1", "A1"
+	}
+	if ref := joinUsedRange(hCell, vCell); !isSameRange(ws.Dimension.Ref, ref) {
+		addIssue(ValidationCellReference, "", "updated the dimension %s to %s", ws.Dimension.Ref, ref)
+		ws.Dimension.Ref = ref
+	}
+}
+
+// repairCells provides a function to repair the cells of the row by given row
+// number, cells and the function to report the repaired issues, and get the
+// sorted cells without duplicate, invalid and out of range cell references.
+func repairCells(row int, cells []xlsxC, addIssue func(cell, format string, args ...interface{})) []xlsxC {
+	type column struct {
+		col int
+		c   xlsxC
+	}
+	var (
+		maxCol  int
+		columns = make([]column, 0, len(cells))
+	)
+	for _, c := range cells {
+		col := maxCol + 1
+		if c.R != "" {
+			cellCol, cellRow, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				addIssue(c.R, "removed the cell with invalid reference %s", c.R)
+				continue
+			}
+			if col = cellCol; cellRow != row && cellCol <= TotalColumns {
+				cell, _ := CoordinatesToCellName(col, row)
+				addIssue(cell, "moved the cell %s into row %d", c.R, row)
+			}
+		}
+		if col > TotalColumns {
+			addIssue(c.R, "removed the cell in column %d out of range", col)
+			continue
+		}
+		if c.R, _ = CoordinatesToCellName(col, row); col < maxCol {
+			addIssue(c.R, "moved the cell %s into ascending order", c.R)
+		}
+		if col > maxCol {
+			maxCol = col
+		}
+		columns = append(columns, column{col: col, c: c})
+	}
+	sort.SliceStable(columns, func(i, j int) bool { return columns[i].col < columns[j].col })
+	cells = cells[:0]
+	for idx, column := range columns {
+		if idx > 0 && columns[idx-1].col == column.col {
+			addIssue(column.c.R, "removed the duplicate cell %s", column.c.R)
+			cells[len(cells)-1] = column.c
+			continue
+		}
+		cells = append(cells, column.c)
+	}
+	return cells
+}
+
+// isSameRange provides a function to check if the given range reference is
+// the same as the given used range reference.
+func isSameRange(ref, usedRange string) bool {
+	coordinates := func(ref string) ([]int, error) {
+		cells := strings.Split(ref, ":")
+		if len(cells) == 1 {
+			cells = append(cells, cells[0])
+		}
+		if len(cells) != 2 {
+			return nil, newInvalidCellNameError(ref)
+		}
+		var result []int
+		for _, cell := range cells {
+			col, row, err := CellNameToCoordinates(cell)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, col, row)
+		}
+		return result, nil
+	}
+	a, err := coordinates(ref)
+	if err != nil {
+		return false
+	}
+	b, _ := coordinates(usedRange)
+	return a[0] == b[0] && a[1] == b[1] && a[2] == b[2] && a[3] == b[3]
+}
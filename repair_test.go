@@ -0,0 +1,91 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepair(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	f.NewSheet("Sheet3")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	header := `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`
+	parts := map[string]string{
+		"xl/worksheets/sheet1.xml": header + `<dimension ref="A1:Z99"/><sheetData>` +
+			`<row r="2"><c r="B2" r="C2"><v>1</v></c><c r="A2"><v>2</v></c>` +
+			`<row r="1"><c r="A1"><v>3</v></c><c r="A1"><v>4</v></c></row>` +
+			`<row r="2"><c r="D2"><v>5</v></c></row></sheetData></row><drawing r:id="rId9"/></worksheet>`,
+		"xl/worksheets/sheet2.xml": "",
+		"xl/worksheets/sheet3.xml": header + `<sheetData><row r="1"><c r="A1"><v>6</v></c></c`,
+	}
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, file := range zr.File {
+		content, ok := parts[file.Name]
+		if ok && content == "" {
+			continue
+		}
+		if !ok {
+			rc, err := file.Open()
+			assert.NoError(t, err)
+			data, err := ioutil.ReadAll(rc)
+			assert.NoError(t, err)
+			assert.NoError(t, rc.Close())
+			content = string(data)
+		}
+		fw, err := zw.Create(file.Name)
+		assert.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+
+	f, err = OpenReader(bytes.NewReader(b.Bytes()), Options{Repair: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationIssue{
+		{Type: ValidationMalformedXML, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Message: "removed the duplicate attribute r of the c element"},
+		{Type: ValidationMalformedXML, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Message: "closed the unclosed row 2"},
+		{Type: ValidationMalformedXML, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Message: "removed the unexpected end element row"},
+		{Type: ValidationMalformedXML, Part: "xl/worksheets/sheet3.xml", Sheet: "Sheet3", Message: "removed the content after the XML syntax error: XML syntax error on line 1: unexpected EOF"},
+		{Type: ValidationMalformedXML, Part: "xl/worksheets/sheet3.xml", Sheet: "Sheet3", Message: "closed the unclosed row 1"},
+		{Type: ValidationMalformedXML, Part: "xl/worksheets/sheet3.xml", Sheet: "Sheet3", Message: "closed the unclosed element sheetData"},
+		{Type: ValidationMalformedXML, Part: "xl/worksheets/sheet3.xml", Sheet: "Sheet3", Message: "closed the unclosed element worksheet"},
+		{Type: ValidationRelationship, Part: "xl/workbook.xml", Sheet: "Sheet2", Message: "removed the sheet Sheet2 which part doesn't exist"},
+		{Type: ValidationRelationship, Part: "xl/_rels/workbook.xml.rels", Message: "removed the relationship rId4 which target xl/worksheets/sheet2.xml doesn't exist"},
+		{Type: ValidationContentType, Part: "[Content_Types].xml", Message: "removed the content type override for part xl/worksheets/sheet2.xml which doesn't exist"},
+		{Type: ValidationRelationship, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Message: "removed the drawing with missing relationship rId9"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Message: "moved the row 1 into ascending order"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Message: "merged the duplicate row 2"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Cell: "A1", Message: "removed the duplicate cell A1"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Cell: "A2", Message: "moved the cell A2 into ascending order"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet1.xml", Sheet: "Sheet1", Message: "updated the dimension A1:Z99 to A1:D2"},
+	}, f.GetRepairs())
+	assert.Equal(t, []string{"Sheet1", "Sheet3"}, f.GetSheetList())
+	for cell, expected := range map[string]string{"A1": "4", "A2": "2", "B2": "1", "C2": "", "D2": "5"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	val, err := f.GetCellValue("Sheet3", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "6", val)
+	issues, err := f.Validate()
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRepair.xlsx")))
+
+	// Test open the repaired spreadsheet with nothing to repair
+	f, err = OpenFile(filepath.Join("test", "TestRepair.xlsx"), Options{Repair: true, UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	assert.Empty(t, f.GetRepairs())
+	assert.NoError(t, f.Close())
+}
@@ -17,8 +17,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"math"
+	"os"
 	"strconv"
 
 	"github.com/mohae/deepcopy"
@@ -89,7 +89,10 @@ func (rows *Rows) Columns() ([]string, error) {
 		return columns, err
 	}
 
-	d := rows.f.sharedStringsReader()
+	d, err := rows.f.sharedStringsReader()
+	if err != nil {
+		return columns, err
+	}
 	for {
 		token, _ := rows.decoder.Token()
 		if token == nil {
@@ -142,15 +145,6 @@ func appendSpace(l int, s []string) []string {
 	return s
 }
 
-// ErrSheetNotExist defines an error of sheet is not exist
-type ErrSheetNotExist struct {
-	SheetName string
-}
-
-func (err ErrSheetNotExist) Error() string {
-	return fmt.Sprintf("sheet %s is not exist", string(err.SheetName))
-}
-
 // Rows returns a rows iterator, used for streaming reading data for a
 // worksheet with a large data. For example:
 //
@@ -175,18 +169,14 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 	if !ok {
 		return nil, ErrSheetNotExist{sheet}
 	}
-	if f.Sheet[name] != nil {
-		// flush data
-		output, _ := xml.Marshal(f.Sheet[name])
-		f.saveFileList(name, f.replaceNameSpaceBytes(name, output))
-	}
 	var (
 		err       error
 		inElement string
 		row       int
 		rows      Rows
+		sheetXML  = f.readSheetXML(name)
 	)
-	decoder := f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
+	decoder := f.xmlNewDecoder(bytes.NewReader(sheetXML))
 	for {
 		token, _ := decoder.Token()
 		if token == nil {
@@ -212,10 +202,102 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 	}
 	rows.f = f
 	rows.sheet = name
-	rows.decoder = f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
+	rows.decoder = f.xmlNewDecoder(bytes.NewReader(sheetXML))
 	return &rows, nil
 }
 
+// ReadRows provides an event-driven reader to read the rows of the worksheet
+// by given worksheet name and the callback function, the callback function
+// will be called for each row in the worksheet with the row number and the
+// cells of the row, the worksheet will be read as a stream without
+// deserializing the whole worksheet. The cells of the row are indexed by the
+// column number, and each cell contains the style index and the formatted
+// value, the value of the blank cell will be nil. Reading will be stopped if
+// the callback function returns an error, and the error will be returned. For
+// example, print the values of the cells in Sheet1:
+//
+//    err := f.ReadRows("Sheet1", func(row int, cells []excelize.Cell) error {
+//        for _, cell := range cells {
+//            fmt.Print(cell.Value, "\t")
+//        }
+//        fmt.Println()
+//        return nil
+//    })
+//
+func (f *File) ReadRows(sheet string, fn func(row int, cells []Cell) error) error {
+	name, ok := f.sheetMap[trimSheetName(sheet)]
+	if !ok {
+		return ErrSheetNotExist{sheet}
+	}
+	f.Lock()
+	file, ok := f.lazyParts[name]
+	f.Unlock()
+	var r io.Reader
+	if ok {
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		r = rc
+	} else {
+		r = bytes.NewReader(f.readSheetXML(name))
+	}
+	d, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	var (
+		row     int
+		cells   []Cell
+		decoder = f.xmlNewDecoder(r)
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if element.Name.Local == "row" {
+				row++
+				if attrR, _ := attrValToInt("r", element.Attr); attrR != 0 {
+					row = attrR
+				}
+				cells = nil
+			}
+			if element.Name.Local == "c" {
+				colCell := xlsxC{}
+				if err = decoder.DecodeElement(&colCell, &element); err != nil {
+					return err
+				}
+				col := len(cells) + 1
+				if colCell.R != "" {
+					if col, _, err = CellNameToCoordinates(colCell.R); err != nil {
+						return err
+					}
+				}
+				for len(cells) < col {
+					cells = append(cells, Cell{})
+				}
+				cells[col-1].StyleID = colCell.S
+				if val, _ := colCell.getValueFrom(f, d); val != "" || colCell.T != "" || colCell.V != "" {
+					cells[col-1].Value = val
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Local == "row" {
+				if err = fn(row, cells); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
 // SetRowHeight provides a function to set the height of a single row. For
 // example, set the height of the first row in Sheet1:
 //
@@ -286,75 +368,295 @@ func (f *File) GetRowHeight(sheet string, row int) (float64, error) {
 
 // sharedStringsReader provides a function to get the pointer to the structure
 // after deserialization of xl/sharedStrings.xml.
-func (f *File) sharedStringsReader() *xlsxSST {
+func (f *File) sharedStringsReader() (*xlsxSST, error) {
 	var err error
 	f.Lock()
 	defer f.Unlock()
 	relPath := f.getWorkbookRelsPath()
 	if f.SharedStrings == nil {
 		var sharedStrings xlsxSST
-		ss := f.readXML("xl/sharedStrings.xml")
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(ss))).
-			Decode(&sharedStrings); err != nil && err != io.EOF {
-			log.Printf("xml decode error: %s", err)
+		if part, ok := f.lazyParts["xl/sharedStrings.xml"].(tempFilePart); ok && f.options != nil && f.options.SharedStringsOnDisk {
+			if sst, idx, e := spillSharedStrings(part); e == nil {
+				sharedStrings, f.sharedStringsMap = *sst, idx
+			}
+		}
+		if sharedStrings.spill == nil {
+			ss := f.readXML("xl/sharedStrings.xml")
+			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(ss))).
+				Decode(&sharedStrings); err != nil && err != io.EOF {
+				err = f.newXMLDecodeError("xl/sharedStrings.xml", err)
+			} else {
+				err = nil
+			}
+			for i := range sharedStrings.SI {
+				if sharedStrings.SI[i].T != nil {
+					f.sharedStringsMap.add(&sharedStrings, sharedStrings.SI[i].T.Val, i)
+				}
+			}
 		}
 		if sharedStrings.UniqueCount == 0 {
 			sharedStrings.UniqueCount = sharedStrings.Count
 		}
 		f.SharedStrings = &sharedStrings
-		for i := range sharedStrings.SI {
-			if sharedStrings.SI[i].T != nil {
-				f.sharedStringsMap[sharedStrings.SI[i].T.Val] = i
-			}
-		}
 		f.addContentTypePart(0, "sharedStrings")
-		rels := f.relsReader(relPath)
+		rels, _ := f.relsReader(relPath)
 		for _, rel := range rels.Relationships {
 			if rel.Target == "/xl/sharedStrings.xml" {
-				return f.SharedStrings
+				return f.SharedStrings, err
 			}
 		}
 		// Update workbook.xml.rels
 		f.addRels(relPath, SourceRelationshipSharedStrings, "/xl/sharedStrings.xml", "")
 	}
 
-	return f.SharedStrings
+	return f.SharedStrings, err
+}
+
+// sharedStringsSpill is the part of the shared string table which has been
+// kept in the temporary file the part was extracted to. Only the offsets of
+// the string items are kept in memory, and the string items will be decoded
+// from the file on demand.
+type sharedStringsSpill struct {
+	file    *os.File
+	offsets []int64
+}
+
+// spillSharedStrings provides a function to build the shared string table by
+// given temporary file of the part without reading the string items into
+// memory. The index of the plain text strings will be built at the same time.
+// The shared string table should be read into memory if an error is
+// returned, such as the part is not encoded in UTF-8.
+func spillSharedStrings(part tempFilePart) (*xlsxSST, *sharedStringsIndex, error) {
+	file, err := os.Open(string(part))
+	if err != nil {
+		return nil, nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	sst, idx := &xlsxSST{spill: &sharedStringsSpill{file: file}}, newSharedStringsIndex()
+	d := xml.NewDecoder(io.NewSectionReader(file, 0, stat.Size()))
+	for {
+		offset := d.InputOffset()
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch se.Name.Local {
+		case "sst":
+			for _, attr := range se.Attr {
+				switch attr.Name.Local {
+				case "count":
+					sst.Count, _ = strconv.Atoi(attr.Value)
+				case "uniqueCount":
+					sst.UniqueCount, _ = strconv.Atoi(attr.Value)
+				}
+			}
+		case "si":
+			var si xlsxSI
+			if err = d.DecodeElement(&si, &se); err != nil {
+				_ = file.Close()
+				return nil, nil, err
+			}
+			sst.spill.offsets = append(sst.spill.offsets, offset, d.InputOffset())
+			if si.T != nil {
+				idx.add(sst, si.T.Val, sst.len()-1)
+			}
+		default:
+			if err = d.Skip(); err != nil {
+				_ = file.Close()
+				return nil, nil, err
+			}
+		}
+	}
+	return sst, idx, nil
+}
+
+// len returns the number of the string items which have been kept in the
+// temporary file.
+func (s *sharedStringsSpill) len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.offsets) / 2
+}
+
+// item provides a function to decode the string item by given index from the
+// temporary file.
+func (s *sharedStringsSpill) item(i int) (xlsxSI, error) {
+	var si xlsxSI
+	start, end := s.offsets[2*i], s.offsets[2*i+1]
+	err := xml.NewDecoder(io.NewSectionReader(s.file, start, end-start)).Decode(&si)
+	return si, err
+}
+
+// size returns the size in bytes of the string item by given index in the
+// temporary file.
+func (s *sharedStringsSpill) size(i int) int64 {
+	return s.offsets[2*i+1] - s.offsets[2*i]
+}
+
+// close provides a function to close the temporary file of the shared string
+// table.
+func (s *sharedStringsSpill) close() error {
+	if s == nil || s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// len returns the number of the string items in the shared string table,
+// including the items which have been kept in the temporary file.
+func (sst *xlsxSST) len() int {
+	return sst.spill.len() + len(sst.SI)
+}
+
+// item provides a function to get the string item by given index of the
+// shared string table, the index should be checked by len before calling it.
+func (sst *xlsxSST) item(i int) (xlsxSI, error) {
+	if n := sst.spill.len(); i >= n {
+		return sst.SI[i-n], nil
+	}
+	return sst.spill.item(i)
+}
+
+// add provides a function to append the string item to the shared string
+// table and returns the index of it.
+func (sst *xlsxSST) add(si xlsxSI) int {
+	sst.SI = append(sst.SI, si)
+	return sst.len() - 1
+}
+
+// sharedStringsIndex is an index of the plain text strings in the shared
+// string table. It keeps the 64-bit FNV-1a hash of each string rather than
+// the string itself as the key, to reduce the memory footprint of the index
+// for the tables with millions of distinct strings, the rare strings which
+// collide with the hash of another string are kept in a separate map.
+type sharedStringsIndex struct {
+	hashes     map[uint64]int
+	collisions map[string]int
+}
+
+// newSharedStringsIndex provides a function to create an empty shared string
+// table index.
+func newSharedStringsIndex() *sharedStringsIndex {
+	return &sharedStringsIndex{hashes: make(map[uint64]int)}
+}
+
+// hashSharedString returns the 64-bit FNV-1a hash of the given string.
+func hashSharedString(val string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(val); i++ {
+		h ^= uint64(val[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// get provides a function to find the index of the given plain text string
+// in the shared string table.
+func (idx *sharedStringsIndex) get(sst *xlsxSST, val string) (int, bool) {
+	i, ok := idx.hashes[hashSharedString(val)]
+	if !ok {
+		return 0, false
+	}
+	if sharedStringEqual(sst, i, val) {
+		return i, true
+	}
+	if i, ok = idx.collisions[val]; ok && sharedStringEqual(sst, i, val) {
+		return i, true
+	}
+	return 0, false
+}
+
+// sharedStringEqual provides a function to check if the plain text string at
+// the given index of the shared string table is equal to the given string.
+func sharedStringEqual(sst *xlsxSST, i int, val string) bool {
+	if i < 0 || i >= sst.len() {
+		return false
+	}
+	si, err := sst.item(i)
+	return err == nil && si.T != nil && si.T.Val == val
+}
+
+// add provides a function to add the index of the plain text string in the
+// shared string table to the index.
+func (idx *sharedStringsIndex) add(sst *xlsxSST, val string, i int) {
+	h := hashSharedString(val)
+	j, ok := idx.hashes[h]
+	if !ok {
+		idx.hashes[h] = i
+		return
+	}
+	if sharedStringEqual(sst, j, val) {
+		return
+	}
+	if idx.collisions == nil {
+		idx.collisions = make(map[string]int)
+	}
+	if _, ok = idx.collisions[val]; !ok {
+		idx.collisions[val] = i
+	}
 }
 
 // getValueFrom return a value from a column/row cell, this function is
 // inteded to be used with for range on rows an argument with the spreadsheet
-// opened file.
-func (c *xlsxC) getValueFrom(f *File, d *xlsxSST) (string, error) {
+// opened file. The number format of the cell will be skipped when the raw is
+// true.
+func (c *xlsxC) getValueFrom(f *File, d *xlsxSST, raw ...bool) (string, error) {
 	f.Lock()
 	defer f.Unlock()
+	format := func(v string) string {
+		if len(raw) > 0 && raw[0] {
+			return v
+		}
+		return f.formattedValue(c.S, v)
+	}
 	switch c.T {
 	case "s":
 		if c.V != "" {
 			xlsxSI := 0
 			xlsxSI, _ = strconv.Atoi(c.V)
-			if len(d.SI) > xlsxSI {
-				return f.formattedValue(c.S, d.SI[xlsxSI].String()), nil
+			if xlsxSI >= 0 && d.len() > xlsxSI {
+				si, err := d.item(xlsxSI)
+				if err != nil {
+					return "", err
+				}
+				return format(si.String()), nil
 			}
 		}
-		return f.formattedValue(c.S, c.V), nil
+		return format(c.V), nil
 	case "str":
-		return f.formattedValue(c.S, c.V), nil
+		return format(c.V), nil
 	case "inlineStr":
 		if c.IS != nil {
-			return f.formattedValue(c.S, c.IS.String()), nil
+			return format(c.IS.String()), nil
 		}
-		return f.formattedValue(c.S, c.V), nil
+		return format(c.V), nil
 	default:
-		if len(c.V) > 16 {
-			val, err := roundPrecision(c.V)
+		v := c.value()
+		if len(v) > 16 {
+			val, err := roundPrecision(v)
 			if err != nil {
 				return "", err
 			}
-			if val != c.V {
-				return f.formattedValue(c.S, val), nil
+			if val != v {
+				return format(val), nil
 			}
 		}
-		return f.formattedValue(c.S, c.V), nil
+		return format(v), nil
 	}
 }
 
@@ -698,6 +1000,6 @@ func convertRowHeightToPixels(height float64) float64 {
 	if height == 0 {
 		return pixels
 	}
-	pixels = math.Ceil(4.0 / 3.0 * height)
+	pixels = math.Ceil(PointsToPixels(height))
 	return pixels
 }
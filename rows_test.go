@@ -1,8 +1,12 @@
 package excelize
 
 import (
+	"archive/zip"
 	"bytes"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -48,6 +52,77 @@ func TestRows(t *testing.T) {
 	assert.EqualError(t, err, `strconv.Atoi: parsing "A": invalid syntax`)
 }
 
+func TestReadRows(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	require.NoError(t, err)
+	returnedRows, err := f.GetRows("Sheet2")
+	require.NoError(t, err)
+	var collectedRows [][]string
+	assert.NoError(t, f.ReadRows("Sheet2", func(row int, cells []Cell) error {
+		for len(collectedRows) < row-1 {
+			collectedRows = append(collectedRows, nil)
+		}
+		var columns []string
+		for _, cell := range cells {
+			val, _ := cell.Value.(string)
+			columns = append(columns, val)
+		}
+		collectedRows = append(collectedRows, columns)
+		return nil
+	}))
+	for i := range returnedRows {
+		returnedRows[i] = trimSliceSpace(returnedRows[i])
+	}
+	for i := range collectedRows {
+		collectedRows[i] = trimSliceSpace(collectedRows[i])
+	}
+	assert.Equal(t, returnedRows, collectedRows)
+
+	f = NewFile()
+	style, err := f.NewStyle(&Style{NumFmt: 9})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 0.5))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "B2", style))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "text"))
+	var rows [][]Cell
+	assert.NoError(t, f.ReadRows("Sheet1", func(row int, cells []Cell) error {
+		rows = append(rows, cells)
+		return nil
+	}))
+	assert.Equal(t, [][]Cell{
+		nil,
+		{{StyleID: style}, {StyleID: style, Value: "50%"}},
+		{{Value: "text"}},
+	}, rows)
+
+	// Test read rows with the stopped callback
+	assert.EqualError(t, f.ReadRows("Sheet1", func(row int, cells []Cell) error {
+		return errors.New("stop")
+	}), "stop")
+	// Test read rows with the not exist worksheet
+	assert.EqualError(t, f.ReadRows("SheetN", func(row int, cells []Cell) error { return nil }), "sheet SheetN is not exist")
+	// Test read rows with the invalid worksheet
+	f = NewFile()
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="1"><c r="A" t="str"><v>B</v></c></row></sheetData></worksheet>`)
+	assert.EqualError(t, f.ReadRows("Sheet1", func(row int, cells []Cell) error { return nil }), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="1"><c r="A1" t="str"><v>B</c></row></sheetData></worksheet>`)
+	assert.EqualError(t, f.ReadRows("Sheet1", func(row int, cells []Cell) error { return nil }), "XML syntax error on line 1: element <v> closed by </c>")
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="1">`)
+	assert.EqualError(t, f.ReadRows("Sheet1", func(row int, cells []Cell) error { return nil }), "XML syntax error on line 1: unexpected EOF")
+	// Test read rows from the lazy loading worksheet
+	buf, err := NewFile().WriteToBuffer()
+	require.NoError(t, err)
+	f, err = OpenReaderAt(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.NoError(t, f.ReadRows("Sheet1", func(row int, cells []Cell) error { return nil }))
+	assert.Contains(t, f.lazyParts, "xl/worksheets/sheet1.xml")
+	zr, err := zip.NewReader(bytes.NewReader(unsupportedZipBytes), int64(len(unsupportedZipBytes)))
+	require.NoError(t, err)
+	f.lazyParts["xl/worksheets/sheet1.xml"] = zr.File[0]
+	assert.EqualError(t, f.ReadRows("Sheet1", func(row int, cells []Cell) error { return nil }), "zip: unsupported compression algorithm")
+}
+
 func TestRowsIterator(t *testing.T) {
 	const (
 		sheet2         = "Sheet2"
@@ -166,11 +241,92 @@ func TestColumns(t *testing.T) {
 func TestSharedStringsReader(t *testing.T) {
 	f := NewFile()
 	f.XLSX["xl/sharedStrings.xml"] = MacintoshCyrillicCharset
-	f.sharedStringsReader()
+	_, err := f.sharedStringsReader()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
 	si := xlsxSI{}
 	assert.EqualValues(t, "", si.String())
 }
 
+func TestSharedStringsSpill(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "a"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", " b "))
+	assert.NoError(t, f.SetCellRichText("Sheet1", "A3", []RichTextRun{{Text: "c"}, {Text: "d", Font: &Font{Bold: true}}}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSharedStringsSpill.xlsx")))
+
+	// Test read the shared string table extracted to the temporary file into
+	// memory by default
+	f, err := OpenFile(filepath.Join("test", "TestSharedStringsSpill.xlsx"), Options{UnzipXMLSizeLimit: 1})
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "cd", val)
+	assert.Nil(t, f.SharedStrings.spill)
+	assert.Len(t, f.SharedStrings.SI, 3)
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestSharedStringsSpill.xlsx"), Options{UnzipXMLSizeLimit: 1, SharedStringsOnDisk: true})
+	assert.NoError(t, err)
+	for cell, expected := range map[string]string{"A1": "a", "A2": " b ", "A3": "cd"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	sst := f.SharedStrings
+	assert.NotNil(t, sst.spill)
+	assert.Empty(t, sst.SI)
+	assert.Equal(t, 3, sst.len())
+	assert.Equal(t, 3, sst.UniqueCount)
+	// Test set the string which exists in the temporary file
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", " b "))
+	assert.Empty(t, sst.SI)
+	// Test append the string to the shared string table
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "e"))
+	assert.Len(t, sst.SI, 1)
+	assert.Equal(t, 4, sst.len())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSharedStringsSpill2.xlsx")))
+	assert.NoError(t, f.Close())
+	assert.Nil(t, sst.spill.file)
+
+	f, err = OpenFile(filepath.Join("test", "TestSharedStringsSpill2.xlsx"))
+	assert.NoError(t, err)
+	for cell, expected := range map[string]string{"A1": "a", "A2": " b ", "A3": "cd", "B1": " b ", "B2": "e"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	assert.Len(t, f.SharedStrings.SI, 4)
+	assert.Equal(t, 4, f.SharedStrings.UniqueCount)
+	assert.NoError(t, f.Close())
+
+	// Test read the string items from the closed temporary file
+	_, err = sst.item(0)
+	assert.Error(t, err)
+	_, err = (&xlsxC{T: "s", V: "0"}).getValueFrom(NewFile(), sst)
+	assert.Error(t, err)
+	assert.Error(t, writeSharedStrings(&bytes.Buffer{}, sst, nil))
+	assert.False(t, sharedStringEqual(sst, 0, "a"))
+
+	// Test spill the shared string table with the invalid temporary file
+	_, _, err = spillSharedStrings(tempFilePart(filepath.Join("test", "SharedStringsSpill.xml")))
+	assert.Error(t, err)
+	for _, content := range []string{`<sst><si><t>a</t>`, `<sst><extLst>`, `<?xml version="1.0" encoding="x-mac-cyrillic"?><sst/>`} {
+		tmp, err := ioutil.TempFile(os.TempDir(), "excelize-")
+		assert.NoError(t, err)
+		_, err = tmp.WriteString(content)
+		assert.NoError(t, err)
+		assert.NoError(t, tmp.Close())
+		_, _, err = spillSharedStrings(tempFilePart(tmp.Name()))
+		assert.Error(t, err, content)
+		// Test read the shared string table into memory if it can't be spilled
+		f = NewFile()
+		f.XLSX["xl/sharedStrings.xml"], f.lazyParts["xl/sharedStrings.xml"] = nil, tempFilePart(tmp.Name())
+		sst, _ = f.sharedStringsReader()
+		assert.Nil(t, sst.spill)
+		assert.NoError(t, os.Remove(tmp.Name()))
+	}
+}
+
 func TestRowVisibility(t *testing.T) {
 	f, err := prepareTestBook1()
 	if !assert.NoError(t, err) {
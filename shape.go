@@ -13,17 +13,18 @@ package excelize
 
 import (
 	"encoding/json"
+	"errors"
 	"strconv"
 	"strings"
 )
 
 // parseFormatShapeSet provides a function to parse the format settings of the
-// shape with default value.
-func parseFormatShapeSet(formatSet string) (*formatShape, error) {
-	format := formatShape{
+// shape by given JSON string or Shape structure pointer with default value.
+func parseFormatShapeSet(formatSet interface{}) (*Shape, error) {
+	format := Shape{
 		Width:  160,
 		Height: 160,
-		Format: formatPicture{
+		Format: PictureFormat{
 			FPrintsWithSheet: true,
 			FLocksWithSheet:  false,
 			NoChangeAspect:   false,
@@ -33,17 +34,54 @@ func parseFormatShapeSet(formatSet string) (*formatShape, error) {
 			YScale:           1.0,
 		},
 	}
-	err := json.Unmarshal([]byte(formatSet), &format)
-	return &format, err
+	var err error
+	switch v := formatSet.(type) {
+	case string:
+		err = json.Unmarshal([]byte(v), &format)
+	case *Shape:
+		if v == nil {
+			return &format, errors.New("parameter is required")
+		}
+		format = *v
+		if format.Width == 0 {
+			format.Width = 160
+		}
+		if format.Height == 0 {
+			format.Height = 160
+		}
+		format.Format.setDefaults()
+	default:
+		err = errors.New("invalid parameter type")
+	}
+	if err != nil {
+		return &format, err
+	}
+	if format.Type == "" {
+		return &format, errors.New("parameter 'Type' is required")
+	}
+	return &format, format.Format.validate()
 }
 
 // AddShape provides the method to add shape in a sheet by given worksheet
 // index, shape format set (such as offset, scale, aspect ratio setting and
-// print settings) and properties set. For example, add text box (rect shape)
-// in Sheet1:
+// print settings) and properties set, the format set can be the JSON string
+// or the Shape structure pointer. For example, add text box (rect shape) in
+// Sheet1:
 //
 //    err := f.AddShape("Sheet1", "G6", `{"type":"rect","color":{"line":"#4286F4","fill":"#8eb9ff"},"paragraph":[{"text":"Rectangle Shape","font":{"bold":true,"italic":true,"family":"Times New Roman","size":36,"color":"#777777","underline":"sng"}}],"width":180,"height": 90}`)
 //
+// The format set can also be specified by the Shape structure, the zero
+// values of the width, height and the scale of the format will be replaced by
+// the default values. For example:
+//
+//    err := f.AddShape("Sheet1", "G6", &excelize.Shape{
+//        Type:      "rect",
+//        Color:     excelize.ShapeColor{Line: "#4286F4", Fill: "#8eb9ff"},
+//        Paragraph: []excelize.ShapeParagraph{{Text: "Rectangle Shape", Font: excelize.Font{Bold: true, Size: 36}}},
+//        Width:     180,
+//        Height:    90,
+//    })
+//
 // The following shows the type of shape supported by excelize:
 //
 //    accentBorderCallout1 (Callout 1 with Border and Accent Shape)
@@ -255,7 +293,7 @@ func parseFormatShapeSet(formatSet string) (*formatShape, error) {
 //    wavyHeavy
 //    wavyDbl
 //
-func (f *File) AddShape(sheet, cell, format string) error {
+func (f *File) AddShape(sheet, cell string, format interface{}) error {
 	formatSet, err := parseFormatShapeSet(format)
 	if err != nil {
 		return err
@@ -292,7 +330,7 @@ func (f *File) AddShape(sheet, cell, format string) error {
 
 // addDrawingShape provides a function to add preset geometry by given sheet,
 // drawingXMLand format sets.
-func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *formatShape) error {
+func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *Shape) error {
 	fromCol, fromRow, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -327,7 +365,10 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *format
 	colStart, rowStart, colEnd, rowEnd, x2, y2 :=
 		f.positionObjectPixels(sheet, colIdx, rowIdx, formatSet.Format.OffsetX, formatSet.Format.OffsetY,
 			width, height)
-	content, cNvPrID := f.drawingParser(drawingXML)
+	content, cNvPrID, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return err
+	}
 	twoCellAnchor := xdrCellAnchor{}
 	twoCellAnchor.EditAs = formatSet.Format.Positioning
 	from := xlsxFrom{}
@@ -379,7 +420,7 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, formatSet *format
 		},
 	}
 	if len(formatSet.Paragraph) < 1 {
-		formatSet.Paragraph = []formatShapeParagraph{
+		formatSet.Paragraph = []ShapeParagraph{
 			{
 				Font: Font{
 					Bold:      false,
@@ -25,4 +25,24 @@ func TestAddShape(t *testing.T) {
 	f = NewFile()
 	assert.NoError(t, f.AddShape("Sheet1", "A1", `{"type":"ellipseRibbon", "color":{"line":"#4286f4","fill":"#8eb9ff"}, "paragraph":[{"font":{"bold":true,"italic":true,"family":"Times New Roman","size":36,"color":"#777777","underline":"single"}}], "height": 90}`))
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddShape2.xlsx")))
+
+	// Test add shape by the shape structure.
+	f = NewFile()
+	assert.NoError(t, f.AddShape("Sheet1", "A1", &Shape{
+		Type:      "rect",
+		Color:     ShapeColor{Line: "#4286F4", Fill: "#8EB9FF"},
+		Paragraph: []ShapeParagraph{{Text: "Rectangle", Font: Font{Bold: true, Color: "#777777"}}},
+		Height:    90,
+	}))
+	formatSet, err := parseFormatShapeSet(&Shape{Type: "rect"})
+	assert.NoError(t, err)
+	assert.Equal(t, 160, formatSet.Width)
+	assert.Equal(t, 160, formatSet.Height)
+	assert.Equal(t, 1.0, formatSet.Format.XScale)
+	assert.EqualError(t, f.AddShape("Sheet1", "A1", nil), "invalid parameter type")
+	assert.EqualError(t, f.AddShape("Sheet1", "A1", (*Shape)(nil)), "parameter is required")
+	assert.EqualError(t, f.AddShape("Sheet1", "A1", &Shape{}), "parameter 'Type' is required")
+	assert.EqualError(t, f.AddShape("Sheet1", "A1", `{"paragraph":[]}`), "parameter 'Type' is required")
+	assert.EqualError(t, f.AddShape("Sheet1", "A1", &Shape{Type: "rect", Format: PictureFormat{Positioning: "cell"}}), "parameter 'Positioning' must be 'oneCell', 'twoCell' or 'absolute'")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddShape3.xlsx")))
 }
@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"os"
 	"path"
 	"path/filepath"
@@ -44,7 +43,7 @@ func (f *File) NewSheet(name string) int {
 	}
 	f.DeleteSheet(name)
 	f.SheetCount++
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	sheetID := 0
 	for _, v := range wb.Sheets.Sheet {
 		if v.SheetID > sheetID {
@@ -67,18 +66,16 @@ func (f *File) NewSheet(name string) int {
 
 // contentTypesReader provides a function to get the pointer to the
 // [Content_Types].xml structure after deserialization.
-func (f *File) contentTypesReader() *xlsxTypes {
-	var err error
-
+func (f *File) contentTypesReader() (*xlsxTypes, error) {
 	if f.ContentTypes == nil {
 		f.ContentTypes = new(xlsxTypes)
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("[Content_Types].xml")))).
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("[Content_Types].xml")))).
 			Decode(f.ContentTypes); err != nil && err != io.EOF {
-			log.Printf("xml decode error: %s", err)
+			return f.ContentTypes, f.newXMLDecodeError("[Content_Types].xml", err)
 		}
 	}
 
-	return f.ContentTypes
+	return f.ContentTypes, nil
 }
 
 // contentTypesWriter provides a function to save [Content_Types].xml after
@@ -93,7 +90,7 @@ func (f *File) contentTypesWriter() {
 // getWorkbookPath provides a function to get the path of the workbook.xml in
 // the spreadsheet.
 func (f *File) getWorkbookPath() (path string) {
-	if rels := f.relsReader("_rels/.rels"); rels != nil {
+	if rels, _ := f.relsReader("_rels/.rels"); rels != nil {
 		for _, rel := range rels.Relationships {
 			if rel.Type == SourceRelationshipOfficeDocument {
 				path = strings.TrimPrefix(rel.Target, "/")
@@ -119,8 +116,7 @@ func (f *File) getWorkbookRelsPath() (path string) {
 
 // workbookReader provides a function to get the pointer to the workbook.xml
 // structure after deserialization.
-func (f *File) workbookReader() *xlsxWorkbook {
-	var err error
+func (f *File) workbookReader() (*xlsxWorkbook, error) {
 	if f.WorkBook == nil {
 		wbPath := f.getWorkbookPath()
 		f.WorkBook = new(xlsxWorkbook)
@@ -129,12 +125,12 @@ func (f *File) workbookReader() *xlsxWorkbook {
 			f.xmlAttr[wbPath] = append(f.xmlAttr[wbPath], getRootElement(d)...)
 			f.addNameSpaces(wbPath, SourceRelationship)
 		}
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(wbPath)))).
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(wbPath)))).
 			Decode(f.WorkBook); err != nil && err != io.EOF {
-			log.Printf("xml decode error: %s", err)
+			return f.WorkBook, f.newXMLDecodeError(wbPath, err)
 		}
 	}
-	return f.WorkBook
+	return f.WorkBook, nil
 }
 
 // workBookWriter provides a function to save workbook.xml after serialize
@@ -149,27 +145,91 @@ func (f *File) workBookWriter() {
 // workSheetWriter provides a function to save xl/worksheets/sheet%d.xml after
 // serialize structure.
 func (f *File) workSheetWriter() {
-	var arr []byte
-	buffer := bytes.NewBuffer(arr)
-	encoder := xml.NewEncoder(buffer)
 	for p, sheet := range f.Sheet {
 		if sheet != nil {
-			for k, v := range sheet.SheetData.Row {
-				f.Sheet[p].SheetData.Row[k].C = trimCell(v.C)
-			}
-			// reusing buffer
-			_ = encoder.Encode(sheet)
-			f.saveFileList(p, replaceRelationshipsBytes(f.replaceNameSpaceBytes(p, buffer.Bytes())))
+			f.saveSheet(p, sheet)
 			ok := f.checked[p]
 			if ok {
 				delete(f.Sheet, p)
 				f.checked[p] = false
 			}
-			buffer.Reset()
 		}
 	}
 }
 
+// writeSheet provides a function to serialize the worksheet by given part
+// path to the writer. The empty cells at the end of the rows will be trimmed,
+// and the name spaces and relationships will be replaced before the rows are
+// written, so that the cell values will not be touched by the replacement.
+func (f *File) writeSheet(w io.Writer, p string, ws *xlsxWorksheet) error {
+	for k, v := range ws.SheetData.Row {
+		ws.SheetData.Row[k].C = trimCell(v.C)
+	}
+	return writeWorksheet(w, ws, func(output []byte) []byte {
+		return replaceRelationshipsBytes(f.replaceNameSpaceBytes(p, output))
+	})
+}
+
+// saveSheet provides a function to serialize the worksheet by given part
+// path with writeSheet and store it in the spreadsheet.
+func (f *File) saveSheet(p string, ws *xlsxWorksheet) {
+	var buf bytes.Buffer
+	_ = f.writeSheet(&buf, p, ws)
+	f.saveFileList(p, buf.Bytes())
+}
+
+// UnloadSheet provides a function to free the memory of the worksheet by
+// given worksheet name. The worksheet will be serialized and stored in the
+// spreadsheet with the changes, and it will be decoded again when accessed
+// next time. For example, unload the worksheet named Sheet1 after reading
+// it:
+//
+//    rows, err := f.GetRows("Sheet1")
+//    if err != nil {
+//        return
+//    }
+//    err = f.UnloadSheet("Sheet1")
+//
+func (f *File) UnloadSheet(sheet string) error {
+	f.Lock()
+	defer f.Unlock()
+	name, ok := f.sheetMap[trimSheetName(sheet)]
+	if !ok {
+		return ErrSheetNotExist{sheet}
+	}
+	ws, ok := f.Sheet[name]
+	if !ok {
+		return nil
+	}
+	if ws != nil {
+		f.saveSheet(name, ws)
+	}
+	delete(f.Sheet, name)
+	delete(f.checked, name)
+	return nil
+}
+
+// readSheetXML provides a function to get the XML content of the worksheet
+// by given worksheet part path, the decoded worksheet will be serialized
+// before reading. It's safe for concurrent use.
+func (f *File) readSheetXML(name string) []byte {
+	f.Lock()
+	ws := f.Sheet[name]
+	f.Unlock()
+	var output []byte
+	if ws != nil {
+		ws.Lock()
+		output = marshalWorksheet(ws)
+		ws.Unlock()
+	}
+	f.Lock()
+	defer f.Unlock()
+	if ws != nil {
+		f.saveFileList(name, f.replaceNameSpaceBytes(name, output))
+	}
+	return f.readXML(name)
+}
+
 // trimCell provides a function to trim blank cells which created by fillColumns.
 func trimCell(column []xlsxC) []xlsxC {
 	rowFull := true
@@ -193,7 +253,7 @@ func trimCell(column []xlsxC) []xlsxC {
 // setContentTypes provides a function to read and update property of contents
 // type of the spreadsheet.
 func (f *File) setContentTypes(partName, contentType string) {
-	content := f.contentTypesReader()
+	content, _ := f.contentTypesReader()
 	content.Overrides = append(content.Overrides, xlsxOverride{
 		PartName:    partName,
 		ContentType: contentType,
@@ -217,7 +277,7 @@ func (f *File) setSheet(index int, name string) {
 // setWorkbook update workbook property of the spreadsheet. Maximum 31
 // characters are allowed in sheet title.
 func (f *File) setWorkbook(name string, sheetID, rid int) {
-	content := f.workbookReader()
+	content, _ := f.workbookReader()
 	content.Sheets.Sheet = append(content.Sheets.Sheet, xlsxSheet{
 		Name:    trimSheetName(name),
 		SheetID: sheetID,
@@ -239,9 +299,18 @@ func (f *File) relsWriter() {
 	}
 }
 
-// setAppXML update docProps/app.xml file of XML.
+// setAppXML update docProps/app.xml file of XML. The heading pairs and the
+// titles of parts will be removed, and the application properties will be
+// kept.
 func (f *File) setAppXML() {
-	f.saveFileList("docProps/app.xml", []byte(templateDocpropsApp))
+	app, err := f.appPropsReader()
+	if err != nil || len(f.readXML("docProps/app.xml")) == 0 {
+		f.saveFileList("docProps/app.xml", []byte(templateDocpropsApp))
+		return
+	}
+	app.Vt, app.HeadingPairs, app.TitlesOfParts = NameSpaceDocPropsVTypes, nil, nil
+	output, _ := xml.Marshal(app)
+	f.saveFileList("docProps/app.xml", output)
 }
 
 // replaceRelationshipsBytes; Some tools that read spreadsheet files have very
@@ -261,7 +330,7 @@ func (f *File) SetActiveSheet(index int) {
 	if index < 0 {
 		index = 0
 	}
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	for activeTab := range wb.Sheets.Sheet {
 		if activeTab == index {
 			if wb.BookViews == nil {
@@ -306,7 +375,7 @@ func (f *File) SetActiveSheet(index int) {
 // spreadsheet. If not found the active sheet will be return integer 0.
 func (f *File) GetActiveSheetIndex() (index int) {
 	var sheetID = f.getActiveSheetID()
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	if wb != nil {
 		for idx, sheet := range wb.Sheets.Sheet {
 			if sheet.SheetID == sheetID {
@@ -320,7 +389,7 @@ func (f *File) GetActiveSheetIndex() (index int) {
 // getActiveSheetID provides a function to get active sheet ID of the
 // spreadsheet. If not found the active sheet will be return integer 0.
 func (f *File) getActiveSheetID() int {
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	if wb != nil {
 		if wb.BookViews != nil && len(wb.BookViews.WorkBookView) > 0 {
 			activeTab := wb.BookViews.WorkBookView[0].ActiveTab
@@ -346,7 +415,7 @@ func (f *File) SetSheetName(oldName, newName string) {
 	if newName == oldName {
 		return
 	}
-	content := f.workbookReader()
+	content, _ := f.workbookReader()
 	for k, v := range content.Sheets.Sheet {
 		if v.Name == oldName {
 			content.Sheets.Sheet[k].Name = newName
@@ -360,7 +429,7 @@ func (f *File) SetSheetName(oldName, newName string) {
 // spreadsheet by given worksheet ID. If given sheet ID is invalid, will
 // return an empty string.
 func (f *File) getSheetNameByID(ID int) string {
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	if wb == nil || ID < 1 {
 		return ""
 	}
@@ -422,7 +491,7 @@ func (f *File) GetSheetIndex(name string) int {
 //    }
 //
 func (f *File) GetSheetMap() map[int]string {
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	sheetMap := map[int]string{}
 	if wb != nil {
 		for _, sheet := range wb.Sheets.Sheet {
@@ -435,7 +504,7 @@ func (f *File) GetSheetMap() map[int]string {
 // GetSheetList provides a function to get worksheets, chart sheets, and
 // dialog sheets name list of the workbook.
 func (f *File) GetSheetList() (list []string) {
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	if wb != nil {
 		for _, sheet := range wb.Sheets.Sheet {
 			list = append(list, sheet.Name)
@@ -446,10 +515,16 @@ func (f *File) GetSheetList() (list []string) {
 
 // getSheetMap provides a function to get worksheet name and XML file path map
 // of the spreadsheet.
-func (f *File) getSheetMap() map[string]string {
-	content := f.workbookReader()
-	rels := f.relsReader(f.getWorkbookRelsPath())
+func (f *File) getSheetMap() (map[string]string, error) {
 	maps := map[string]string{}
+	content, err := f.workbookReader()
+	if err != nil {
+		return maps, err
+	}
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return maps, err
+	}
 	for _, v := range content.Sheets.Sheet {
 		for _, rel := range rels.Relationships {
 			if rel.ID == v.ID {
@@ -462,7 +537,7 @@ func (f *File) getSheetMap() map[string]string {
 			}
 		}
 	}
-	return maps
+	return maps, nil
 }
 
 // SetSheetBackground provides a function to set background picture by given
@@ -497,8 +572,8 @@ func (f *File) DeleteSheet(name string) {
 		return
 	}
 	sheetName := trimSheetName(name)
-	wb := f.workbookReader()
-	wbRels := f.relsReader(f.getWorkbookRelsPath())
+	wb, _ := f.workbookReader()
+	wbRels, _ := f.relsReader(f.getWorkbookRelsPath())
 	activeSheetName := f.GetSheetName(f.GetActiveSheetIndex())
 	for idx, sheet := range wb.Sheets.Sheet {
 		if sheet.Name == sheetName {
@@ -533,7 +608,7 @@ func (f *File) DeleteSheet(name string) {
 // deleteSheetFromWorkbookRels provides a function to remove worksheet
 // relationships by given relationships ID in the file workbook.xml.rels.
 func (f *File) deleteSheetFromWorkbookRels(rID string) string {
-	content := f.relsReader(f.getWorkbookRelsPath())
+	content, _ := f.relsReader(f.getWorkbookRelsPath())
 	for k, v := range content.Relationships {
 		if v.ID == rID {
 			content.Relationships = append(content.Relationships[:k], content.Relationships[k+1:]...)
@@ -546,7 +621,7 @@ func (f *File) deleteSheetFromWorkbookRels(rID string) string {
 // deleteSheetFromContentTypes provides a function to remove worksheet
 // relationships by given target name in the file [Content_Types].xml.
 func (f *File) deleteSheetFromContentTypes(target string) {
-	content := f.contentTypesReader()
+	content, _ := f.contentTypesReader()
 	for k, v := range content.Overrides {
 		if v.PartName == "/xl/"+target {
 			content.Overrides = append(content.Overrides[:k], content.Overrides[k+1:]...)
@@ -555,8 +630,9 @@ func (f *File) deleteSheetFromContentTypes(target string) {
 }
 
 // CopySheet provides a function to duplicate a worksheet by gave source and
-// target worksheet index. Note that currently doesn't support duplicate
-// workbooks that contain tables, charts or pictures. For Example:
+// target worksheet index. The drawings with charts and pictures, comments,
+// tables and data validations of the source worksheet will be duplicated,
+// the pivot tables will not be duplicated. For Example:
 //
 //    // Sheet1 already exists...
 //    index := f.NewSheet("Sheet2")
@@ -584,15 +660,17 @@ func (f *File) copySheet(from, to int) error {
 	if len(worksheet.SheetViews.SheetView) > 0 {
 		worksheet.SheetViews.SheetView[0].TabSelected = false
 	}
-	worksheet.Drawing = nil
-	worksheet.TableParts = nil
-	worksheet.PageSetUp = nil
 	f.Sheet[path] = worksheet
 	toRels := "xl/worksheets/_rels/sheet" + toSheetID + ".xml.rels"
 	fromRels := "xl/worksheets/_rels/sheet" + strconv.Itoa(f.getSheetID(fromSheet)) + ".xml.rels"
-	_, ok := f.XLSX[fromRels]
-	if ok {
-		f.XLSX[toRels] = f.XLSX[fromRels]
+	rels, err := f.relsReader(fromRels)
+	if err != nil {
+		return err
+	}
+	if rels != nil {
+		if f.Relationships[toRels], err = f.copySheetRels(rels); err != nil {
+			return err
+		}
 	}
 	fromSheetXMLPath, _ := f.sheetMap[trimSheetName(fromSheet)]
 	fromSheetAttr, _ := f.xmlAttr[fromSheetXMLPath]
@@ -600,6 +678,159 @@ func (f *File) copySheet(from, to int) error {
 	return err
 }
 
+// copySheetRels provides a function to duplicate the worksheet relationships
+// and the parts of the drawing, comments and tables which referenced by the
+// relationships. The relationship IDs will be kept, so the references in the
+// duplicated worksheet are still valid.
+func (f *File) copySheetRels(rels *xlsxRelationships) (*xlsxRelationships, error) {
+	var (
+		err       error
+		sheetRels = &xlsxRelationships{}
+	)
+	for _, rel := range rels.Relationships {
+		switch rel.Type {
+		case SourceRelationshipDrawingML:
+			rel.Target, err = f.copyDrawing(rel.Target)
+		case SourceRelationshipDrawingVML:
+			rel.Target, err = f.copyDrawingVML(rel.Target)
+		case SourceRelationshipComments:
+			rel.Target, err = f.copyComments(rel.Target)
+		case SourceRelationshipTable:
+			rel.Target, err = f.copyTable(rel.Target)
+		case SourceRelationshipPivotTable:
+			continue
+		}
+		if err != nil {
+			return sheetRels, err
+		}
+		sheetRels.Relationships = append(sheetRels.Relationships, rel)
+	}
+	return sheetRels, err
+}
+
+// copyDrawing provides a function to duplicate the drawing part and the
+// charts in the drawing by given relationship target of the drawing, and
+// returns the relationship target of the duplicated drawing. The pictures in
+// the drawing will be shared.
+func (f *File) copyDrawing(target string) (string, error) {
+	drawingXML := strings.Replace(target, "..", "xl", -1)
+	drawingID := f.countDrawings() + 1
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return "", err
+	}
+	f.Drawings["xl/drawings/drawing"+strconv.Itoa(drawingID)+".xml"] = deepcopy.Copy(wsDr).(*xlsxWsDr)
+	drawingRels := "xl/drawings/_rels/" + filepath.Base(drawingXML) + ".rels"
+	rels, err := f.relsReader(drawingRels)
+	if err != nil {
+		return "", err
+	}
+	if rels != nil {
+		toRels := deepcopy.Copy(rels).(*xlsxRelationships)
+		for idx, rel := range toRels.Relationships {
+			if rel.Type == SourceRelationshipChart {
+				target, err := f.copyChart(rel.Target)
+				if err != nil {
+					return "", err
+				}
+				toRels.Relationships[idx].Target = target
+			}
+		}
+		f.Relationships["xl/drawings/_rels/drawing"+strconv.Itoa(drawingID)+".xml.rels"] = toRels
+	}
+	f.addContentTypePart(drawingID, "drawings")
+	return "../drawings/drawing" + strconv.Itoa(drawingID) + ".xml", nil
+}
+
+// copyChart provides a function to duplicate the chart part by given
+// relationship target of the chart, and returns the relationship target of
+// the duplicated chart.
+func (f *File) copyChart(target string) (string, error) {
+	chartXML := strings.Replace(target, "..", "xl", -1)
+	chartID := f.countCharts() + 1
+	f.XLSX["xl/charts/chart"+strconv.Itoa(chartID)+".xml"] = f.readXML(chartXML)
+	chartRels := "xl/charts/_rels/" + filepath.Base(chartXML) + ".rels"
+	rels, err := f.relsReader(chartRels)
+	if err != nil {
+		return "", err
+	}
+	if rels != nil {
+		f.Relationships["xl/charts/_rels/chart"+strconv.Itoa(chartID)+".xml.rels"] = deepcopy.Copy(rels).(*xlsxRelationships)
+	}
+	f.addContentTypePart(chartID, "chart")
+	return "../charts/chart" + strconv.Itoa(chartID) + ".xml", nil
+}
+
+// copyDrawingVML provides a function to duplicate the VML drawing part by
+// given relationship target, and returns the relationship target of the
+// duplicated VML drawing.
+func (f *File) copyDrawingVML(target string) (string, error) {
+	drawingVML := strings.Replace(target, "..", "xl", -1)
+	drawingID := f.getNextPartID("xl/drawings/vmlDrawing", ".vml")
+	toVML := "xl/drawings/vmlDrawing" + strconv.Itoa(drawingID) + ".vml"
+	if vml := f.VMLDrawing[drawingVML]; vml != nil {
+		f.VMLDrawing[toVML] = deepcopy.Copy(vml).(*vmlDrawing)
+	} else {
+		f.XLSX[toVML] = f.readXML(drawingVML)
+	}
+	vmlRels := "xl/drawings/_rels/" + filepath.Base(drawingVML) + ".rels"
+	rels, err := f.relsReader(vmlRels)
+	if err != nil {
+		return "", err
+	}
+	if rels != nil {
+		f.Relationships["xl/drawings/_rels/vmlDrawing"+strconv.Itoa(drawingID)+".vml.rels"] = deepcopy.Copy(rels).(*xlsxRelationships)
+	}
+	return "../drawings/vmlDrawing" + strconv.Itoa(drawingID) + ".vml", nil
+}
+
+// copyComments provides a function to duplicate the comments part by given
+// relationship target, and returns the relationship target of the duplicated
+// comments.
+func (f *File) copyComments(target string) (string, error) {
+	commentsXML := strings.Replace(target, "..", "xl", -1)
+	comments, err := f.commentsReader(commentsXML)
+	if err != nil {
+		return "", err
+	}
+	commentID := f.getNextPartID("xl/comments", ".xml")
+	if comments != nil {
+		f.Comments["xl/comments"+strconv.Itoa(commentID)+".xml"] = deepcopy.Copy(comments).(*xlsxComments)
+	}
+	f.addContentTypePart(commentID, "comments")
+	return "../comments" + strconv.Itoa(commentID) + ".xml", nil
+}
+
+// copyTable provides a function to duplicate the table part by given
+// relationship target, and returns the relationship target of the duplicated
+// table. The duplicated table will be renamed with the new table ID, and the
+// name which is used by the other tables will be skipped.
+func (f *File) copyTable(target string) (string, error) {
+	tableXML := strings.Replace(target, "..", "xl", -1)
+	tableID := f.getNextPartID("xl/tables/table", ".xml")
+	t, err := f.tableReader(tableXML)
+	if err != nil {
+		return "", err
+	}
+	names, maxID, err := f.getTableNames()
+	if err != nil {
+		return "", err
+	}
+	if t.ID = tableID; t.ID <= maxID {
+		t.ID = maxID + 1
+	}
+	for i := t.ID; ; i++ {
+		if t.Name = "Table" + strconv.Itoa(i); !names[strings.ToLower(t.Name)] {
+			break
+		}
+	}
+	t.DisplayName = t.Name
+	table, _ := xml.Marshal(t)
+	f.saveFileList("xl/tables/table"+strconv.Itoa(tableID)+".xml", table)
+	f.addContentTypePart(tableID, "table")
+	return "../tables/table" + strconv.Itoa(tableID) + ".xml", nil
+}
+
 // SetSheetVisible provides a function to set worksheet visible by given worksheet
 // name. A workbook must contain at least one visible worksheet. If the given
 // worksheet has been activated, this setting will be invalidated. Sheet state
@@ -615,7 +846,10 @@ func (f *File) copySheet(from, to int) error {
 //
 func (f *File) SetSheetVisible(name string, visible bool) error {
 	name = trimSheetName(name)
-	content := f.workbookReader()
+	content, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
 	if visible {
 		for k, v := range content.Sheets.Sheet {
 			if v.Name == name {
@@ -646,15 +880,69 @@ func (f *File) SetSheetVisible(name string, visible bool) error {
 	return nil
 }
 
-// parseFormatPanesSet provides a function to parse the panes settings.
-func parseFormatPanesSet(formatSet string) (*formatPanes, error) {
-	format := formatPanes{}
-	err := json.Unmarshal([]byte(formatSet), &format)
-	return &format, err
+// parseFormatPanesSet provides a function to parse the panes settings by
+// given JSON string or Panes structure pointer. The error of parsing the JSON
+// string will be ignored for compatibility.
+func parseFormatPanesSet(formatSet interface{}) (*Panes, error) {
+	format := Panes{}
+	switch v := formatSet.(type) {
+	case string:
+		_ = json.Unmarshal([]byte(v), &format)
+	case *Panes:
+		if v == nil {
+			return &format, errors.New("parameter is required")
+		}
+		format = *v
+	default:
+		return &format, errors.New("invalid parameter type")
+	}
+	return &format, format.validate()
+}
+
+// validate provides a function to check the panes settings.
+func (p *Panes) validate() error {
+	if p.XSplit < 0 || p.YSplit < 0 {
+		return errors.New("parameter 'XSplit' and 'YSplit' must be greater than or equal to 0")
+	}
+	if p.TopLeftCell != "" {
+		if _, _, err := CellNameToCoordinates(p.TopLeftCell); err != nil {
+			return fmt.Errorf("parameter 'TopLeftCell' parsing error: %v", err)
+		}
+	}
+	if !isValidPaneType(p.ActivePane) {
+		return errors.New("parameter 'ActivePane' must be 'bottomLeft', 'bottomRight', 'topLeft' or 'topRight'")
+	}
+	for i, selection := range p.Panes {
+		if !isValidPaneType(selection.Pane) {
+			return fmt.Errorf("parameter 'Panes[%d].Pane' must be 'bottomLeft', 'bottomRight', 'topLeft' or 'topRight'", i)
+		}
+		if selection.ActiveCell != "" {
+			if _, _, err := CellNameToCoordinates(selection.ActiveCell); err != nil {
+				return fmt.Errorf("parameter 'Panes[%d].ActiveCell' parsing error: %v", i, err)
+			}
+		}
+		for _, ref := range strings.Fields(selection.SQRef) {
+			if _, err := rangeRefToCoordinates(ref); err != nil {
+				return fmt.Errorf("parameter 'Panes[%d].SQRef' parsing error: %v", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isValidPaneType provides a function to check if the pane type is valid, the
+// empty pane type will be omitted.
+func isValidPaneType(pane string) bool {
+	switch pane {
+	case "", "bottomLeft", "bottomRight", "topLeft", "topRight":
+		return true
+	}
+	return false
 }
 
 // SetPanes provides a function to create and remove freeze panes and split panes
-// by given worksheet name and panes format set.
+// by given worksheet name and panes format set, the format set can be the
+// JSON string or the Panes structure pointer.
 //
 // activePane defines the pane that is active. The possible values for this
 // attribute are defined in the following table:
@@ -738,12 +1026,32 @@ func parseFormatPanesSet(formatSet string) (*formatPanes, error) {
 //
 //    f.SetPanes("Sheet1", `{"freeze":false,"split":false}`)
 //
-func (f *File) SetPanes(sheet, panes string) error {
-	fs, _ := parseFormatPanesSet(panes)
+// An example of how to freeze the first row in the Sheet1 by the Panes
+// structure:
+//
+//    err := f.SetPanes("Sheet1", &excelize.Panes{
+//        Freeze:      true,
+//        YSplit:      1,
+//        TopLeftCell: "A2",
+//        ActivePane:  "bottomLeft",
+//        Panes:       []excelize.PaneSelection{{SQRef: "A2", ActiveCell: "A2", Pane: "bottomLeft"}},
+//    })
+//
+func (f *File) SetPanes(sheet string, panes interface{}) error {
+	fs, err := parseFormatPanesSet(panes)
+	if err != nil {
+		return err
+	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
+	if ws.SheetViews == nil {
+		ws.SheetViews = &xlsxSheetViews{}
+	}
+	if len(ws.SheetViews.SheetView) == 0 {
+		ws.SheetViews.SheetView = append(ws.SheetViews.SheetView, xlsxSheetView{WorkbookViewID: 0})
+	}
 	p := &xlsxPane{
 		ActivePane:  fs.ActivePane,
 		TopLeftCell: fs.TopLeftCell,
@@ -777,7 +1085,7 @@ func (f *File) SetPanes(sheet, panes string) error {
 //    f.GetSheetVisible("Sheet1")
 //
 func (f *File) GetSheetVisible(name string) bool {
-	content := f.workbookReader()
+	content, _ := f.workbookReader()
 	visible := false
 	for k, v := range content.Sheets.Sheet {
 		if v.Name == trimSheetName(name) {
@@ -789,6 +1097,134 @@ func (f *File) GetSheetVisible(name string) bool {
 	return visible
 }
 
+// GetSheetDimension provides a function to get the used range reference
+// recorded in the dimension element of the worksheet by given worksheet
+// name. The recorded reference may be stale or absent in the spreadsheet
+// generated by other applications, use GetSheetUsedRange to compute the
+// range from the cells. For example, get dimension of Sheet1:
+//
+//    dimension, err := f.GetSheetDimension("Sheet1")
+//
+func (f *File) GetSheetDimension(sheet string) (string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.Dimension == nil {
+		return "", err
+	}
+	return ws.Dimension.Ref, err
+}
+
+// GetSheetUsedRange provides a function to compute the range of the cells
+// which have a value or formula by given worksheet name, the empty cells
+// which only have a style will be ignored. It will return an empty string if
+// the worksheet doesn't contain any value. For example, get the used range of
+// Sheet1 which has values in cells B2 and D5:
+//
+//    ref, err := f.GetSheetUsedRange("Sheet1") // return "B2:D5", nil
+//
+func (f *File) GetSheetUsedRange(sheet string) (string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return "", err
+	}
+	hCell, vCell, err := getUsedRange(ws, func(c *xlsxC) bool { return c.hasContent() })
+	if err != nil || hCell == "" {
+		return "", err
+	}
+	return joinUsedRange(hCell, vCell), err
+}
+
+// TrimSheet provides a function to drop the empty trailing rows and cells of
+// the worksheet by given worksheet name and update the dimension of the
+// worksheet. The spreadsheet generated by other applications often contains
+// a large amount of empty rows and cells which only have a style, those will
+// be dropped by default. Set the optional keepStyle parameter to true to
+// keep the styled cells and rows. For example, trim the Sheet1 and keep the
+// styled cells:
+//
+//    err := f.TrimSheet("Sheet1", true)
+//
+func (f *File) TrimSheet(sheet string, keepStyle ...bool) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	used := func(c *xlsxC) bool { return c.hasContent() }
+	if len(keepStyle) > 0 && keepStyle[0] {
+		used = func(c *xlsxC) bool { return c.hasValue() }
+	}
+	lastRow := 0
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		lastCol := 0
+		for colIdx := range row.C {
+			if used(&row.C[colIdx]) {
+				lastCol = colIdx + 1
+			}
+		}
+		row.C = row.C[:lastCol]
+		if lastCol > 0 || (len(keepStyle) > 0 && keepStyle[0] && (row.CustomFormat || row.CustomHeight)) {
+			lastRow = rowIdx + 1
+		}
+	}
+	ws.SheetData.Row = ws.SheetData.Row[:lastRow]
+	hCell, vCell, err := getUsedRange(ws, used)
+	if err != nil {
+		return err
+	}
+	if hCell == "" {
+		hCell, vCell = "A1", "A1"
+	}
+	ws.Dimension = &xlsxDimension{Ref: joinUsedRange(hCell, vCell)}
+	return err
+}
+
+// getUsedRange provides a function to get the top left cell and the bottom
+// right cell of the cells in the worksheet which match the given function.
+func getUsedRange(ws *xlsxWorksheet, used func(c *xlsxC) bool) (string, string, error) {
+	minCol, minRow, maxCol, maxRow := 0, 0, 0, 0
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		for colIdx := range row.C {
+			c := &row.C[colIdx]
+			if !used(c) {
+				continue
+			}
+			col, r, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return "", "", err
+			}
+			if minCol == 0 || col < minCol {
+				minCol = col
+			}
+			if minRow == 0 || r < minRow {
+				minRow = r
+			}
+			if col > maxCol {
+				maxCol = col
+			}
+			if r > maxRow {
+				maxRow = r
+			}
+		}
+	}
+	if maxCol == 0 {
+		return "", "", nil
+	}
+	hCell, _ := CoordinatesToCellName(minCol, minRow)
+	vCell, _ := CoordinatesToCellName(maxCol, maxRow)
+	return hCell, vCell, nil
+}
+
+// joinUsedRange provides a function to build the range reference by given top
+// left cell and bottom right cell, the reference of a single cell will be the
+// cell name.
+func joinUsedRange(hCell, vCell string) string {
+	if hCell == vCell {
+		return hCell
+	}
+	return hCell + ":" + vCell
+}
+
 // SearchSheet provides a function to get coordinates by given worksheet name,
 // cell value, and regular expression. The function doesn't support searching
 // on the calculated result, formatted numbers and conditional lookup
@@ -818,7 +1254,7 @@ func (f *File) SearchSheet(sheet, value string, reg ...bool) ([]string, error) {
 	}
 	if f.Sheet[name] != nil {
 		// flush data
-		output, _ := xml.Marshal(f.Sheet[name])
+		output := marshalWorksheet(f.Sheet[name])
 		f.saveFileList(name, f.replaceNameSpaceBytes(name, output))
 	}
 	return f.searchSheet(name, value, regSearch)
@@ -833,7 +1269,9 @@ func (f *File) searchSheet(name, value string, regSearch bool) (result []string,
 		d                   *xlsxSST
 	)
 
-	d = f.sharedStringsReader()
+	if d, err = f.sharedStringsReader(); err != nil {
+		return
+	}
 	decoder := f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
 	for {
 		var token xml.Token
@@ -1380,8 +1818,9 @@ func (f *File) GetPageLayout(sheet string, opts ...PageLayoutOptionPtr) error {
 }
 
 // SetDefinedName provides a function to set the defined names of the workbook
-// or worksheet. If not specified scope, the default scope is workbook.
-// For example:
+// or worksheet. If not specified scope, the default scope is workbook. The
+// defined names are case-insensitive, and an error will be returned if the
+// worksheet of the scope doesn't exist. For example:
 //
 //    f.SetDefinedName(&excelize.DefinedName{
 //        Name:     "Amount",
@@ -1390,28 +1829,39 @@ func (f *File) GetPageLayout(sheet string, opts ...PageLayoutOptionPtr) error {
 //        Scope:    "Sheet2",
 //    })
 //
+// The defined name could also refer to a constant or a formula, the leading
+// equal sign of the reference is optional and will not be stored. For
+// example, define the name "Company" as a text constant and the name
+// "TaxRate" as a formula:
+//
+//    f.SetDefinedName(&excelize.DefinedName{
+//        Name:     "Company",
+//        RefersTo: "=\"ACME\"",
+//    })
+//    f.SetDefinedName(&excelize.DefinedName{
+//        Name:     "TaxRate",
+//        RefersTo: "=IF(Sheet1!$B$1>1000,0.2,0.1)",
+//    })
+//
 func (f *File) SetDefinedName(definedName *DefinedName) error {
-	wb := f.workbookReader()
-	d := xlsxDefinedName{
-		Name:    definedName.Name,
-		Comment: definedName.Comment,
-		Data:    definedName.RefersTo,
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
 	}
-	if definedName.Scope != "" {
-		if sheetID := f.getSheetID(definedName.Scope); sheetID != 0 {
-			sheetID--
-			d.LocalSheetID = &sheetID
-		}
+	localSheetID, err := f.getDefinedNameLocalSheetID(definedName.Scope)
+	if err != nil {
+		return err
+	}
+	d := xlsxDefinedName{
+		Name:         definedName.Name,
+		Comment:      definedName.Comment,
+		Hidden:       definedName.Hidden,
+		LocalSheetID: localSheetID,
+		Data:         trimDefinedNameRefersTo(definedName.RefersTo),
 	}
 	if wb.DefinedNames != nil {
-		for _, dn := range wb.DefinedNames.DefinedName {
-			var scope string
-			if dn.LocalSheetID != nil {
-				scope = f.getSheetNameByID(*dn.LocalSheetID + 1)
-			}
-			if scope == definedName.Scope && dn.Name == definedName.Name {
-				return errors.New("the same name already exists on the scope")
-			}
+		if f.getDefinedNameIndex(definedName.Name, localSheetID) != -1 {
+			return errors.New("the same name already exists on the scope")
 		}
 		wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, d)
 		return nil
@@ -1422,6 +1872,43 @@ func (f *File) SetDefinedName(definedName *DefinedName) error {
 	return nil
 }
 
+// UpdateDefinedName provides a function to update the reference, comment and
+// hidden flag of an existing defined name of the workbook or worksheet. The
+// defined name is located by the name and scope, if not specified scope, the
+// default scope is workbook. For example, change the range of the name
+// "Amount" on the scope "Sheet2":
+//
+//    f.UpdateDefinedName(&excelize.DefinedName{
+//        Name:     "Amount",
+//        RefersTo: "Sheet1!$A$2:$D$10",
+//        Scope:    "Sheet2",
+//    })
+//
+func (f *File) UpdateDefinedName(definedName *DefinedName) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	localSheetID, err := f.getDefinedNameLocalSheetID(definedName.Scope)
+	if err != nil {
+		return err
+	}
+	idx := f.getDefinedNameIndex(definedName.Name, localSheetID)
+	if idx == -1 {
+		return errors.New("no defined name on the scope")
+	}
+	dn := &wb.DefinedNames.DefinedName[idx]
+	dn.Comment, dn.Hidden, dn.Data = definedName.Comment, definedName.Hidden, trimDefinedNameRefersTo(definedName.RefersTo)
+	return nil
+}
+
+// trimDefinedNameRefersTo provides a function to remove the leading equal
+// sign of the reference of the defined name, the reference is stored without
+// the equal sign in the workbook.
+func trimDefinedNameRefersTo(refersTo string) string {
+	return strings.TrimPrefix(strings.TrimSpace(refersTo), "=")
+}
+
 // DeleteDefinedName provides a function to delete the defined names of the
 // workbook or worksheet. If not specified scope, the default scope is
 // workbook. For example:
@@ -1432,42 +1919,185 @@ func (f *File) SetDefinedName(definedName *DefinedName) error {
 //    })
 //
 func (f *File) DeleteDefinedName(definedName *DefinedName) error {
-	wb := f.workbookReader()
-	if wb.DefinedNames != nil {
-		for idx, dn := range wb.DefinedNames.DefinedName {
-			var scope string
-			if dn.LocalSheetID != nil {
-				scope = f.getSheetNameByID(*dn.LocalSheetID + 1)
-			}
-			if scope == definedName.Scope && dn.Name == definedName.Name {
-				wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName[:idx], wb.DefinedNames.DefinedName[idx+1:]...)
-				return nil
-			}
-		}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	localSheetID, err := f.getDefinedNameLocalSheetID(definedName.Scope)
+	if err != nil {
+		return err
+	}
+	idx := f.getDefinedNameIndex(definedName.Name, localSheetID)
+	if idx == -1 {
+		return errors.New("no defined name on the scope")
 	}
-	return errors.New("no defined name on the scope")
+	wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName[:idx], wb.DefinedNames.DefinedName[idx+1:]...)
+	return nil
 }
 
 // GetDefinedName provides a function to get the defined names of the workbook
-// or worksheet.
+// or worksheet. The reference of the defined name will be returned as stored
+// in the workbook without the leading equal sign, which could be a cell
+// reference, a constant or a formula.
 func (f *File) GetDefinedName() []DefinedName {
 	var definedNames []DefinedName
-	wb := f.workbookReader()
+	wb, _ := f.workbookReader()
 	if wb.DefinedNames != nil {
 		for _, dn := range wb.DefinedNames.DefinedName {
-			definedName := DefinedName{
+			scope, workbook := f.getDefinedNameScope(dn)
+			if workbook {
+				scope = "Workbook"
+			}
+			definedNames = append(definedNames, DefinedName{
 				Name:     dn.Name,
 				Comment:  dn.Comment,
+				Hidden:   dn.Hidden,
 				RefersTo: dn.Data,
-				Scope:    "Workbook",
+				Scope:    scope,
+			})
+		}
+	}
+	return definedNames
+}
+
+// ResolveDefinedName provides a function to resolve the defined name to the
+// worksheet and range components it refers to by given name and scope. The
+// worksheet scoped name takes precedence over the workbook scoped name with
+// the same name, and the worksheet name of the scope will be used for the
+// references without an explicit worksheet name. The worksheet names in the
+// result are unquoted, and absolute reference marks will be removed. The 3D
+// references across multiple worksheets are not supported and will return an
+// error. For example, the name "Amount" refers to
+// "'Sales Data'!$A$2:$D$5,Sheet1!$F$2" will be resolved to:
+//
+//    [{Sheet: "Sales Data", Range: "A2:D5"}, {Sheet: "Sheet1", Range: "F2"}]
+//
+func (f *File) ResolveDefinedName(name, scope string) ([]DefinedNameRange, error) {
+	localSheetID, err := f.getDefinedNameLocalSheetID(scope)
+	if err != nil {
+		return nil, err
+	}
+	idx := f.getDefinedNameIndex(name, localSheetID)
+	if idx == -1 {
+		if idx = f.getDefinedNameIndex(name, nil); idx == -1 {
+			return nil, errors.New("no defined name on the scope")
+		}
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, err
+	}
+	dn := wb.DefinedNames.DefinedName[idx]
+	refersTo := strings.TrimPrefix(strings.TrimSpace(dn.Data), "=")
+	// The references without worksheet name of the workbook scoped name refer
+	// to the worksheet of the given scope, and there is no default worksheet
+	// if the given scope is also the workbook scope.
+	defaultSheet, _ := f.getDefinedNameScope(dn)
+	if dn.LocalSheetID == nil && localSheetID != nil {
+		defaultSheet = f.GetSheetName(*localSheetID)
+	}
+	var ranges []DefinedNameRange
+	for _, ref := range splitDefinedNameRefersTo(refersTo) {
+		ref = strings.TrimSpace(ref)
+		sheet, cells := defaultSheet, ref
+		if i := strings.LastIndex(ref, "!"); i != -1 {
+			sheet, cells = ref[:i], ref[i+1:]
+			if len(sheet) > 1 && strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") {
+				sheet = strings.Replace(sheet[1:len(sheet)-1], "''", "'", -1)
 			}
-			if dn.LocalSheetID != nil && *dn.LocalSheetID >= 0 {
-				definedName.Scope = f.getSheetNameByID(*dn.LocalSheetID + 1)
+			if strings.Contains(sheet, ":") {
+				return nil, fmt.Errorf("defined name %s refers to a 3D reference", name)
 			}
-			definedNames = append(definedNames, definedName)
 		}
+		cells = strings.Replace(cells, "$", "", -1)
+		if sheet == "" {
+			return nil, fmt.Errorf("defined name %s is not a cell reference", name)
+		}
+		for _, cell := range strings.Split(cells, ":") {
+			if _, _, err := CellNameToCoordinates(cell); err != nil {
+				return nil, fmt.Errorf("defined name %s is not a cell reference", name)
+			}
+		}
+		ranges = append(ranges, DefinedNameRange{Sheet: sheet, Range: cells})
 	}
-	return definedNames
+	return ranges, nil
+}
+
+// getDefinedNameScope provides a function to get the worksheet name of the
+// scope of the defined name, and whether the defined name is on the workbook
+// scope. The local sheet ID of the defined name is the zero-based position of
+// the worksheet in the workbook, and the worksheet name of the workbook
+// scoped name is empty.
+func (f *File) getDefinedNameScope(dn xlsxDefinedName) (string, bool) {
+	if dn.LocalSheetID != nil && *dn.LocalSheetID >= 0 {
+		return f.GetSheetName(*dn.LocalSheetID), false
+	}
+	return "", true
+}
+
+// getDefinedNameLocalSheetID provides a function to get the local sheet ID of
+// the defined name by given scope, which is the zero-based position of the
+// worksheet in the workbook rather than the ID of the worksheet. The empty
+// scope and "Workbook" are the workbook scope without local sheet ID, unless
+// there is a worksheet named "Workbook". If the worksheet of the scope
+// doesn't exist, it will return an error.
+func (f *File) getDefinedNameLocalSheetID(scope string) (*int, error) {
+	if scope == "" {
+		return nil, nil
+	}
+	if idx := f.GetSheetIndex(scope); idx != -1 {
+		return &idx, nil
+	}
+	if scope == "Workbook" {
+		return nil, nil
+	}
+	return nil, ErrSheetNotExist{scope}
+}
+
+// getDefinedNameIndex provides a function to get the index of the defined
+// name in the workbook by given name and local sheet ID, the nil local sheet
+// ID is the workbook scope. The defined names are case-insensitive. If the
+// defined name doesn't exist, it will return an integer type value -1.
+func (f *File) getDefinedNameIndex(name string, localSheetID *int) int {
+	wb, _ := f.workbookReader()
+	if wb.DefinedNames == nil {
+		return -1
+	}
+	for idx, dn := range wb.DefinedNames.DefinedName {
+		if !strings.EqualFold(dn.Name, name) {
+			continue
+		}
+		switch {
+		case dn.LocalSheetID == nil && localSheetID == nil:
+			return idx
+		case dn.LocalSheetID != nil && localSheetID != nil && *dn.LocalSheetID == *localSheetID:
+			return idx
+		}
+	}
+	return -1
+}
+
+// splitDefinedNameRefersTo provides a function to split the union references
+// of the defined name by comma, the commas inside the quoted worksheet name
+// will be ignored.
+func splitDefinedNameRefersTo(refersTo string) []string {
+	var (
+		refs   []string
+		quoted bool
+		start  int
+	)
+	for i, r := range refersTo {
+		switch r {
+		case '\'':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				refs = append(refs, refersTo[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(refs, refersTo[start:])
 }
 
 // GroupSheets provides a function to group worksheets by given worksheets
@@ -1635,24 +2265,107 @@ func (f *File) RemovePageBreak(sheet, cell string) (err error) {
 	return
 }
 
+// IgnoredErrorsType is the type of the error to be ignored in worksheet.
+type IgnoredErrorsType byte
+
+// Worksheet ignored errors types enumeration.
+const (
+	IgnoredErrorsEvalError IgnoredErrorsType = iota
+	IgnoredErrorsTwoDigitTextYear
+	IgnoredErrorsNumberStoredAsText
+	IgnoredErrorsFormula
+	IgnoredErrorsFormulaRange
+	IgnoredErrorsUnlockedFormula
+	IgnoredErrorsEmptyCellReference
+	IgnoredErrorsListDataValidation
+	IgnoredErrorsCalculatedColumn
+)
+
+// SetIgnoredErrors provides a function to ignore the errors of the cells in
+// a range by given worksheet name, range reference and error types, the
+// green triangles of these errors will not be shown in the cells. Multiple
+// ranges can be separated by space. Calling this function with the same
+// range reference again replaces the ignored error types of it, and calling
+// without any error type removes it. For example, ignore the "number stored
+// as text" errors in range A1:B10 on Sheet1:
+//
+//    err := f.SetIgnoredErrors("Sheet1", "A1:B10", excelize.IgnoredErrorsNumberStoredAsText)
+//
+func (f *File) SetIgnoredErrors(sheet, rangeRef string, types ...IgnoredErrorsType) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	coordinates, err := sqrefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	if len(coordinates) == 0 {
+		return fmt.Errorf("invalid range %q", rangeRef)
+	}
+	if rangeRef, err = coordinatesToSqref(coordinates); err != nil {
+		return err
+	}
+	ignoredError := &xlsxIgnoredError{Sqref: rangeRef}
+	for _, typ := range types {
+		switch typ {
+		case IgnoredErrorsEvalError:
+			ignoredError.EvalError = true
+		case IgnoredErrorsTwoDigitTextYear:
+			ignoredError.TwoDigitTextYear = true
+		case IgnoredErrorsNumberStoredAsText:
+			ignoredError.NumberStoredAsText = true
+		case IgnoredErrorsFormula:
+			ignoredError.Formula = true
+		case IgnoredErrorsFormulaRange:
+			ignoredError.FormulaRange = true
+		case IgnoredErrorsUnlockedFormula:
+			ignoredError.UnlockedFormula = true
+		case IgnoredErrorsEmptyCellReference:
+			ignoredError.EmptyCellReference = true
+		case IgnoredErrorsListDataValidation:
+			ignoredError.ListDataValidation = true
+		case IgnoredErrorsCalculatedColumn:
+			ignoredError.CalculatedColumn = true
+		default:
+			return fmt.Errorf("invalid ignored errors type %d", typ)
+		}
+	}
+	if ws.IgnoredErrors == nil {
+		ws.IgnoredErrors = &xlsxIgnoredErrors{}
+	}
+	var ignoredErrors []*xlsxIgnoredError
+	for _, v := range ws.IgnoredErrors.IgnoredError {
+		if v.Sqref != rangeRef {
+			ignoredErrors = append(ignoredErrors, v)
+		}
+	}
+	if len(types) > 0 {
+		ignoredErrors = append(ignoredErrors, ignoredError)
+	}
+	ws.IgnoredErrors.IgnoredError = ignoredErrors
+	if len(ignoredErrors) == 0 && ws.IgnoredErrors.ExtLst == nil {
+		ws.IgnoredErrors = nil
+	}
+	return err
+}
+
 // relsReader provides a function to get the pointer to the structure
 // after deserialization of xl/worksheets/_rels/sheet%d.xml.rels.
-func (f *File) relsReader(path string) *xlsxRelationships {
-	var err error
-
+func (f *File) relsReader(path string) (*xlsxRelationships, error) {
 	if f.Relationships[path] == nil {
 		_, ok := f.XLSX[path]
 		if ok {
 			c := xlsxRelationships{}
-			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+			f.Relationships[path] = &c
+			if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
 				Decode(&c); err != nil && err != io.EOF {
-				log.Printf("xml decode error: %s", err)
+				return f.Relationships[path], f.newXMLDecodeError(path, err)
 			}
-			f.Relationships[path] = &c
 		}
 	}
 
-	return f.Relationships[path]
+	return f.Relationships[path], nil
 }
 
 // fillSheetData ensures there are enough rows, and columns in the chosen
@@ -1696,3 +2409,49 @@ func makeContiguousColumns(ws *xlsxWorksheet, fromRow, toRow, colCount int) {
 		fillColumns(rowData, colCount, fromRow)
 	}
 }
+
+// setWorksheetExt provides a function to set the content of the extension
+// by given URI in the extension list of the worksheet, the extension will be
+// removed if the content is empty. The new extension will be placed after
+// the extensions with the given prior URIs, which should be in front of it.
+func (f *File) setWorksheetExt(ws *xlsxWorksheet, URI, content string, prior ...string) error {
+	decodeExtLst := new(decodeWorksheetExt)
+	if ws.ExtLst != nil {
+		if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	var exts []*xlsxWorksheetExt
+	found := false
+	for _, ext := range decodeExtLst.Ext {
+		if strings.EqualFold(ext.URI, URI) {
+			if found = true; content != "" {
+				ext.Content = content
+				exts = append(exts, ext)
+			}
+			continue
+		}
+		exts = append(exts, ext)
+	}
+	if !found {
+		if content == "" {
+			return nil
+		}
+		idx := 0
+		for idx < len(exts) && inStrSlice(prior, exts[idx].URI) != -1 {
+			idx++
+		}
+		exts = append(exts[:idx], append([]*xlsxWorksheetExt{{URI: URI, Content: content}}, exts[idx:]...)...)
+	}
+	if len(exts) == 0 {
+		ws.ExtLst = nil
+		return nil
+	}
+	decodeExtLst.Ext = exts
+	extLstBytes, _ := xml.Marshal(decodeExtLst)
+	ws.ExtLst = &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}
+	return nil
+}
@@ -87,6 +87,22 @@ func TestSetPane(t *testing.T) {
 	assert.NoError(t, f.SetPanes("Panes 4", `{"freeze":true,"split":false,"x_split":0,"y_split":9,"top_left_cell":"A34","active_pane":"bottomLeft","panes":[{"sqref":"A11:XFD11","active_cell":"A11","pane":"bottomLeft"}]}`))
 	assert.NoError(t, f.SetPanes("Panes 4", ""))
 	assert.EqualError(t, f.SetPanes("SheetN", ""), "sheet SheetN is not exist")
+	// Test set panes by the panes structure.
+	f.NewSheet("Panes 5")
+	assert.NoError(t, f.SetPanes("Panes 5", &Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft", Panes: []PaneSelection{{SQRef: "A2 C3:D4", ActiveCell: "A2", Pane: "bottomLeft"}}}))
+	ws, err := f.workSheetReader("Panes 5")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxPane{State: "frozen", YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}, ws.SheetViews.SheetView[0].Pane)
+	assert.Equal(t, "A2 C3:D4", ws.SheetViews.SheetView[0].Selection[0].SQRef)
+	// Test set panes with invalid settings.
+	assert.EqualError(t, f.SetPanes("Panes 5", nil), "invalid parameter type")
+	assert.EqualError(t, f.SetPanes("Panes 5", (*Panes)(nil)), "parameter is required")
+	assert.EqualError(t, f.SetPanes("Panes 5", &Panes{XSplit: -1}), "parameter 'XSplit' and 'YSplit' must be greater than or equal to 0")
+	assert.EqualError(t, f.SetPanes("Panes 5", &Panes{TopLeftCell: "A"}), `parameter 'TopLeftCell' parsing error: cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetPanes("Panes 5", `{"active_pane":"bottom"}`), "parameter 'ActivePane' must be 'bottomLeft', 'bottomRight', 'topLeft' or 'topRight'")
+	assert.EqualError(t, f.SetPanes("Panes 5", &Panes{Panes: []PaneSelection{{Pane: "top"}}}), "parameter 'Panes[0].Pane' must be 'bottomLeft', 'bottomRight', 'topLeft' or 'topRight'")
+	assert.EqualError(t, f.SetPanes("Panes 5", &Panes{Panes: []PaneSelection{{ActiveCell: "1"}}}), `parameter 'Panes[0].ActiveCell' parsing error: cannot convert cell "1" to coordinates: invalid cell name "1"`)
+	assert.EqualError(t, f.SetPanes("Panes 5", &Panes{Panes: []PaneSelection{{SQRef: "A1 B"}}}), `parameter 'Panes[0].SQRef' parsing error: cannot convert cell "B" to coordinates: invalid cell name "B"`)
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetPane.xlsx")))
 }
 
@@ -242,6 +258,246 @@ func TestDefinedName(t *testing.T) {
 	assert.Exactly(t, "Sheet1!$A$2:$D$5", f.GetDefinedName()[0].RefersTo)
 	assert.Exactly(t, 1, len(f.GetDefinedName()))
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDefinedName.xlsx")))
+
+	// Test set, update and delete the defined name on the nonexistent scope
+	for i := 0; i < 2; i++ {
+		assert.EqualError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$A$1", Scope: "SheetN"}), "sheet SheetN is not exist")
+	}
+	assert.EqualError(t, f.UpdateDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1", Scope: "SheetN"}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.DeleteDefinedName(&DefinedName{Name: "Amount", Scope: "SheetN"}), "sheet SheetN is not exist")
+	_, err := f.ResolveDefinedName("Amount", "SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	assert.Exactly(t, 1, len(f.GetDefinedName()))
+
+	// Test the defined names are case-insensitive
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{Name: "AMOUNT", RefersTo: "Sheet1!$A$1", Scope: "Sheet1"}), "the same name already exists on the scope")
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "TOTAL", RefersTo: "Sheet1!$A$1"}))
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{Name: "total", RefersTo: "Sheet1!$A$2"}), "the same name already exists on the scope")
+	assert.NoError(t, f.UpdateDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$B$1"}))
+	assert.Equal(t, "Sheet1!$B$1", f.GetDefinedName()[1].RefersTo)
+	assert.NoError(t, f.DeleteDefinedName(&DefinedName{Name: "amount", Scope: "Sheet1"}))
+	assert.Equal(t, []DefinedName{{Name: "TOTAL", RefersTo: "Sheet1!$B$1", Scope: "Workbook"}}, f.GetDefinedName())
+
+	// Test the local sheet ID is the position of the worksheet after a
+	// worksheet has been deleted
+	f = NewFile()
+	f.NewSheet("Sheet2")
+	f.NewSheet("Sheet3")
+	f.DeleteSheet("Sheet2")
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet3!$A$1", Scope: "Sheet3"}))
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *wb.DefinedNames.DefinedName[0].LocalSheetID)
+	assert.Equal(t, "Sheet3", f.GetDefinedName()[0].Scope)
+	assert.NoError(t, f.UpdateDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet3!$B$1", Scope: "Sheet3"}))
+	assert.Equal(t, "Sheet3!$B$1", f.GetDefinedName()[0].RefersTo)
+	assert.NoError(t, f.DeleteDefinedName(&DefinedName{Name: "Amount", Scope: "Sheet3"}))
+	assert.Empty(t, f.GetDefinedName())
+}
+
+func TestUpdateDefinedName(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.EqualError(t, f.UpdateDefinedName(&DefinedName{Name: "Amount"}), "no defined name on the scope")
+	assert.NoError(t, f.SetDefinedName(&DefinedName{
+		Name:     "Amount",
+		RefersTo: "Sheet1!$A$2:$D$5",
+		Scope:    "Sheet2",
+	}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{
+		Name:     "Amount",
+		RefersTo: "Sheet1!$A$2:$D$5",
+		Scope:    "Workbook",
+	}))
+	assert.EqualError(t, f.SetDefinedName(&DefinedName{
+		Name:     "Amount",
+		RefersTo: "Sheet1!$A$2:$D$5",
+	}), "the same name already exists on the scope")
+	assert.EqualError(t, f.UpdateDefinedName(&DefinedName{Name: "Amount", Scope: "Sheet1"}), "no defined name on the scope")
+	assert.NoError(t, f.UpdateDefinedName(&DefinedName{
+		Name:     "Amount",
+		RefersTo: "Sheet1!$A$2:$D$10",
+		Comment:  "defined name comment",
+		Hidden:   true,
+		Scope:    "Sheet2",
+	}))
+	assert.Equal(t, []DefinedName{
+		{Name: "Amount", Comment: "defined name comment", Hidden: true, RefersTo: "Sheet1!$A$2:$D$10", Scope: "Sheet2"},
+		{Name: "Amount", RefersTo: "Sheet1!$A$2:$D$5", Scope: "Workbook"},
+	}, f.GetDefinedName())
+}
+
+func TestResolveDefinedName(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$2:$D$5,'Sheet 1,2'!$F$2,'O''Brien'!B1"}))
+	ranges, err := f.ResolveDefinedName("Amount", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedNameRange{
+		{Sheet: "Sheet1", Range: "A2:D5"},
+		{Sheet: "Sheet 1,2", Range: "F2"},
+		{Sheet: "O'Brien", Range: "B1"},
+	}, ranges)
+	_, err = f.ResolveDefinedName("NoExist", "")
+	assert.EqualError(t, err, "no defined name on the scope")
+
+	// Test resolve the worksheet scoped name takes precedence over the
+	// workbook scoped name, and the references without worksheet name
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "=$B$1:$B$3", Scope: "Sheet2"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Constant", RefersTo: "0.5"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Sheets", RefersTo: "Sheet1:Sheet2!$A$1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "QuotedSheets", RefersTo: "'Sheet 1:Sheet 2'!$A$1"}))
+	ranges, err = f.ResolveDefinedName("Amount", "Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedNameRange{
+		{Sheet: "Sheet1", Range: "A2:D5"},
+		{Sheet: "Sheet 1,2", Range: "F2"},
+		{Sheet: "O'Brien", Range: "B1"},
+	}, ranges)
+	ranges, err = f.ResolveDefinedName("Amount", "Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedNameRange{{Sheet: "Sheet2", Range: "B1:B3"}}, ranges)
+	_, err = f.ResolveDefinedName("Constant", "Sheet1")
+	assert.EqualError(t, err, "defined name Constant is not a cell reference")
+	_, err = f.ResolveDefinedName("NoExist", "Sheet1")
+	assert.EqualError(t, err, "no defined name on the scope")
+
+	// Test resolve the 3D references across multiple worksheets
+	_, err = f.ResolveDefinedName("Sheets", "")
+	assert.EqualError(t, err, "defined name Sheets refers to a 3D reference")
+	_, err = f.ResolveDefinedName("QuotedSheets", "")
+	assert.EqualError(t, err, "defined name QuotedSheets refers to a 3D reference")
+
+	// Test resolve the references without worksheet name on the worksheet
+	// scope after a worksheet before it has been deleted
+	f = NewFile()
+	f.NewSheet("Sheet2")
+	f.NewSheet("Sheet3")
+	f.DeleteSheet("Sheet2")
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Local", RefersTo: "$C$1", Scope: "Sheet3"}))
+	ranges, err = f.ResolveDefinedName("Local", "Sheet3")
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedNameRange{{Sheet: "Sheet3", Range: "C1"}}, ranges)
+
+	// Test resolve the defined names on the worksheet named "Workbook" and
+	// refer to the worksheet name with the dollar sign
+	f.NewSheet("Workbook")
+	f.NewSheet("Q1$")
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "$A$1", Scope: "Workbook"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Rate", RefersTo: "Workbook!$B$1,'Q1$'!$A$1:$B$2"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Relative", RefersTo: "C1"}))
+	ranges, err = f.ResolveDefinedName("Total", "Workbook")
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedNameRange{{Sheet: "Workbook", Range: "A1"}}, ranges)
+	ranges, err = f.ResolveDefinedName("Rate", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedNameRange{{Sheet: "Workbook", Range: "B1"}, {Sheet: "Q1$", Range: "A1:B2"}}, ranges)
+	ranges, err = f.ResolveDefinedName("Relative", "Workbook")
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedNameRange{{Sheet: "Workbook", Range: "C1"}}, ranges)
+	_, err = f.ResolveDefinedName("Relative", "")
+	assert.EqualError(t, err, "defined name Relative is not a cell reference")
+}
+
+func TestDefinedNameConstantAndFormula(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Company", RefersTo: `="ACME, Inc. <R&D>"`}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "TaxRate", RefersTo: "=IF(Sheet1!$B$1>1000,0.2,0.1)"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Months", RefersTo: `{"Jan","Feb","Mar"}`, Scope: "Sheet1"}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDefinedNameConstantAndFormula.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestDefinedNameConstantAndFormula.xlsx"))
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedName{
+		{Name: "Company", RefersTo: `"ACME, Inc. <R&D>"`, Scope: "Workbook"},
+		{Name: "TaxRate", RefersTo: "IF(Sheet1!$B$1>1000,0.2,0.1)", Scope: "Workbook"},
+		{Name: "Months", RefersTo: `{"Jan","Feb","Mar"}`, Scope: "Sheet1"},
+	}, f.GetDefinedName())
+
+	assert.NoError(t, f.UpdateDefinedName(&DefinedName{Name: "Company", RefersTo: `="Contoso"`}))
+	assert.Equal(t, `"Contoso"`, f.GetDefinedName()[0].RefersTo)
+	_, err = f.ResolveDefinedName("Company", "Sheet1")
+	assert.EqualError(t, err, "defined name Company is not a cell reference")
+	_, err = f.ResolveDefinedName("TaxRate", "Sheet1")
+	assert.EqualError(t, err, "defined name TaxRate is not a cell reference")
+}
+
+func TestGetSheetDimension(t *testing.T) {
+	f := NewFile()
+	dimension, err := f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", dimension)
+	ref, err := f.GetSheetUsedRange("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", ref)
+
+	assert.NoError(t, f.SetCellValue("Sheet1", "D5", 1))
+	ref, err = f.GetSheetUsedRange("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "D5", ref)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B7", "SUM(D5)"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C2", "text"))
+	style, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"#E0EBF5"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "F10", style))
+	ref, err = f.GetSheetUsedRange("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:D7", ref)
+
+	ws, ok := f.Sheet["xl/worksheets/sheet1.xml"]
+	assert.True(t, ok)
+	ws.Dimension = nil
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", dimension)
+	ws.SheetData.Row[0].C[0].R = "A"
+	ws.SheetData.Row[0].C[0].V = "1"
+	_, err = f.GetSheetUsedRange("Sheet1")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
+	_, err = f.GetSheetDimension("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	_, err = f.GetSheetUsedRange("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestTrimSheet(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"#E0EBF5"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "F100", style))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "text"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", 1))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 200, 30))
+
+	assert.NoError(t, f.TrimSheet("Sheet1", true))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.SheetData.Row, 200)
+	assert.Len(t, ws.SheetData.Row[99].C, 6)
+	assert.Len(t, ws.SheetData.Row[199].C, 0)
+	dimension, err := f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:F100", dimension)
+
+	assert.NoError(t, f.TrimSheet("Sheet1"))
+	assert.Len(t, ws.SheetData.Row, 3)
+	assert.Len(t, ws.SheetData.Row[0].C, 0)
+	assert.Len(t, ws.SheetData.Row[1].C, 2)
+	assert.Len(t, ws.SheetData.Row[2].C, 3)
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:C3", dimension)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestTrimSheet.xlsx")))
+
+	f = NewFile()
+	assert.NoError(t, f.TrimSheet("Sheet1"))
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", dimension)
+	assert.EqualError(t, f.TrimSheet("SheetN"), "sheet SheetN is not exist")
+	f.Sheet["xl/worksheets/sheet1.xml"].SheetData.Row = []xlsxRow{{R: 1, C: []xlsxC{{R: "A", V: "1"}}}}
+	assert.EqualError(t, f.TrimSheet("Sheet1"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 }
 
 func TestGroupSheets(t *testing.T) {
@@ -359,6 +615,36 @@ func TestGetWorkbookRelsPath(t *testing.T) {
 	assert.Equal(t, "_rels/workbook.xml.rels", f.getWorkbookRelsPath())
 }
 
+func TestUnloadSheet(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.UnloadSheet("Sheet1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "unload"))
+	assert.Contains(t, f.Sheet, "xl/worksheets/sheet1.xml")
+	assert.NoError(t, f.UnloadSheet("Sheet1"))
+	assert.NotContains(t, f.Sheet, "xl/worksheets/sheet1.xml")
+	assert.NotContains(t, f.checked, "xl/worksheets/sheet1.xml")
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "unload", val)
+	val, err = f.GetCellValue("Sheet1", "A19")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total:", val)
+	assert.NoError(t, f.UnloadSheet("Sheet1"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnloadSheet.xlsx")))
+	assert.EqualError(t, f.UnloadSheet("SheetN"), "sheet SheetN is not exist")
+
+	// Test the unloaded worksheet is serialized in the same way of saving
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"a < b", 1, true}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E3", nil))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "https://github.com", "External"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.NoError(t, f.UnloadSheet("Sheet1"))
+	assert.Equal(t, readZipPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml"), string(f.XLSX["xl/worksheets/sheet1.xml"]))
+}
+
 func TestDeleteSheet(t *testing.T) {
 	f := NewFile()
 	f.SetActiveSheet(f.NewSheet("Sheet2"))
@@ -400,3 +686,31 @@ func newSheetWithSave() {
 	}
 	file.Save()
 }
+
+func TestSetIgnoredErrors(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetIgnoredErrors("Sheet1", "A1:B10", IgnoredErrorsNumberStoredAsText))
+	assert.NoError(t, f.SetIgnoredErrors("Sheet1", "C1 D1:D5", IgnoredErrorsEvalError, IgnoredErrorsFormula))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*xlsxIgnoredError{
+		{Sqref: "A1:B10", NumberStoredAsText: true},
+		{Sqref: "C1 D1:D5", EvalError: true, Formula: true},
+	}, ws.IgnoredErrors.IgnoredError)
+	// Test replace ignored error types of the same range
+	assert.NoError(t, f.SetIgnoredErrors("Sheet1", "A1:B10", IgnoredErrorsTwoDigitTextYear, IgnoredErrorsCalculatedColumn))
+	assert.Equal(t, &xlsxIgnoredError{Sqref: "A1:B10", TwoDigitTextYear: true, CalculatedColumn: true}, ws.IgnoredErrors.IgnoredError[1])
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetIgnoredErrors.xlsx")))
+	// Test remove ignored errors
+	assert.NoError(t, f.SetIgnoredErrors("Sheet1", "A1:B10"))
+	assert.NoError(t, f.SetIgnoredErrors("Sheet1", "C1 D1:D5"))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.IgnoredErrors)
+
+	// Test set ignored errors with invalid parameters
+	assert.EqualError(t, f.SetIgnoredErrors("SheetN", "A1"), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetIgnoredErrors("Sheet1", ""), `invalid range ""`)
+	assert.EqualError(t, f.SetIgnoredErrors("Sheet1", "A:B1"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetIgnoredErrors("Sheet1", "A1", IgnoredErrorsType(9)), "invalid ignored errors type 9")
+}
@@ -35,6 +35,18 @@ type (
 	AutoPageBreaks bool
 	// OutlineSummaryBelow is an outlinePr, within SheetPr option
 	OutlineSummaryBelow bool
+	// FilterMode is a SheetPrOption
+	FilterMode bool
+	// SyncHorizontal is a SheetPrOption
+	SyncHorizontal bool
+	// SyncVertical is a SheetPrOption
+	SyncVertical bool
+	// SyncRef is a SheetPrOption
+	SyncRef string
+	// TransitionEvaluation is a SheetPrOption
+	TransitionEvaluation bool
+	// TransitionEntry is a SheetPrOption
+	TransitionEntry bool
 )
 
 // setSheetPrOption implements the SheetPrOption interface.
@@ -104,6 +116,106 @@ func (o *Published) getSheetPrOption(pr *xlsxSheetPr) {
 	*o = Published(defaultTrue(pr.Published))
 }
 
+// setSheetPrOption implements the SheetPrOption interface and flag indicating
+// whether the worksheet has one or more autofilters or advanced filters on.
+func (o FilterMode) setSheetPrOption(pr *xlsxSheetPr) {
+	pr.FilterMode = bool(o)
+}
+
+// getSheetPrOption implements the SheetPrOptionPtr interface and get the
+// settings of whether the worksheet has filters on.
+func (o *FilterMode) getSheetPrOption(pr *xlsxSheetPr) {
+	if pr == nil {
+		*o = false
+		return
+	}
+	*o = FilterMode(pr.FilterMode)
+}
+
+// setSheetPrOption implements the SheetPrOption interface and flag indicating
+// whether to synchronize the horizontal scrolling of the worksheets in the
+// group with the sync reference cell.
+func (o SyncHorizontal) setSheetPrOption(pr *xlsxSheetPr) {
+	pr.SyncHorizontal = bool(o)
+}
+
+// getSheetPrOption implements the SheetPrOptionPtr interface and get the
+// settings of whether to synchronize the horizontal scrolling.
+func (o *SyncHorizontal) getSheetPrOption(pr *xlsxSheetPr) {
+	if pr == nil {
+		*o = false
+		return
+	}
+	*o = SyncHorizontal(pr.SyncHorizontal)
+}
+
+// setSheetPrOption implements the SheetPrOption interface and flag indicating
+// whether to synchronize the vertical scrolling of the worksheets in the
+// group with the sync reference cell.
+func (o SyncVertical) setSheetPrOption(pr *xlsxSheetPr) {
+	pr.SyncVertical = bool(o)
+}
+
+// getSheetPrOption implements the SheetPrOptionPtr interface and get the
+// settings of whether to synchronize the vertical scrolling.
+func (o *SyncVertical) getSheetPrOption(pr *xlsxSheetPr) {
+	if pr == nil {
+		*o = false
+		return
+	}
+	*o = SyncVertical(pr.SyncVertical)
+}
+
+// setSheetPrOption implements the SheetPrOption interface and specifies the
+// top left visible cell used to synchronize the scrolling of the worksheets.
+func (o SyncRef) setSheetPrOption(pr *xlsxSheetPr) {
+	pr.SyncRef = string(o)
+}
+
+// getSheetPrOption implements the SheetPrOptionPtr interface and get the top
+// left visible cell used to synchronize the scrolling of the worksheets.
+func (o *SyncRef) getSheetPrOption(pr *xlsxSheetPr) {
+	if pr == nil {
+		*o = ""
+		return
+	}
+	*o = SyncRef(pr.SyncRef)
+}
+
+// setSheetPrOption implements the SheetPrOption interface and flag indicating
+// whether the Lotus compatible formula evaluation shall be used for the
+// worksheet.
+func (o TransitionEvaluation) setSheetPrOption(pr *xlsxSheetPr) {
+	pr.TransitionEvaluation = bool(o)
+}
+
+// getSheetPrOption implements the SheetPrOptionPtr interface and get the
+// settings of whether the Lotus compatible formula evaluation is used.
+func (o *TransitionEvaluation) getSheetPrOption(pr *xlsxSheetPr) {
+	if pr == nil {
+		*o = false
+		return
+	}
+	*o = TransitionEvaluation(pr.TransitionEvaluation)
+}
+
+// setSheetPrOption implements the SheetPrOption interface and flag indicating
+// whether the Lotus compatible formula entry shall be used for the
+// worksheet.
+func (o TransitionEntry) setSheetPrOption(pr *xlsxSheetPr) {
+	pr.TransitionEntry = bool(o)
+}
+
+// getSheetPrOption implements the SheetPrOptionPtr interface and get the
+// settings of whether the Lotus compatible formula entry is used.
+func (o *TransitionEntry) getSheetPrOption(pr *xlsxSheetPr) {
+	if pr == nil {
+		*o = false
+		return
+	}
+	*o = TransitionEntry(pr.TransitionEntry)
+}
+
 // setSheetPrOption implements the SheetPrOption interface.
 func (o FitToPage) setSheetPrOption(pr *xlsxSheetPr) {
 	if pr.PageSetUpPr == nil {
@@ -155,6 +267,12 @@ func (o *AutoPageBreaks) getSheetPrOption(pr *xlsxSheetPr) {
 //   FitToPage(bool)
 //   AutoPageBreaks(bool)
 //   OutlineSummaryBelow(bool)
+//   FilterMode(bool)
+//   SyncHorizontal(bool)
+//   SyncVertical(bool)
+//   SyncRef(string)
+//   TransitionEvaluation(bool)
+//   TransitionEntry(bool)
 func (f *File) SetSheetPrOptions(name string, opts ...SheetPrOption) error {
 	sheet, err := f.workSheetReader(name)
 	if err != nil {
@@ -181,6 +299,12 @@ func (f *File) SetSheetPrOptions(name string, opts ...SheetPrOption) error {
 //   FitToPage(bool)
 //   AutoPageBreaks(bool)
 //   OutlineSummaryBelow(bool)
+//   FilterMode(bool)
+//   SyncHorizontal(bool)
+//   SyncVertical(bool)
+//   SyncRef(string)
+//   TransitionEvaluation(bool)
+//   TransitionEntry(bool)
 func (f *File) GetSheetPrOptions(name string, opts ...SheetPrOptionPtr) error {
 	sheet, err := f.workSheetReader(name)
 	if err != nil {
@@ -15,6 +15,12 @@ var _ = []SheetPrOption{
 	FitToPage(true),
 	AutoPageBreaks(true),
 	OutlineSummaryBelow(true),
+	FilterMode(true),
+	SyncHorizontal(true),
+	SyncVertical(true),
+	SyncRef("B2"),
+	TransitionEvaluation(true),
+	TransitionEntry(true),
 }
 
 var _ = []SheetPrOptionPtr{
@@ -24,6 +30,12 @@ var _ = []SheetPrOptionPtr{
 	(*FitToPage)(nil),
 	(*AutoPageBreaks)(nil),
 	(*OutlineSummaryBelow)(nil),
+	(*FilterMode)(nil),
+	(*SyncHorizontal)(nil),
+	(*SyncVertical)(nil),
+	(*SyncRef)(nil),
+	(*TransitionEvaluation)(nil),
+	(*TransitionEntry)(nil),
 }
 
 func ExampleFile_SetSheetPrOptions() {
@@ -37,6 +49,12 @@ func ExampleFile_SetSheetPrOptions() {
 		FitToPage(true),
 		AutoPageBreaks(true),
 		OutlineSummaryBelow(false),
+		FilterMode(false),
+		SyncHorizontal(true),
+		SyncVertical(true),
+		SyncRef("B2"),
+		TransitionEvaluation(false),
+		TransitionEntry(false),
 	); err != nil {
 		fmt.Println(err)
 	}
@@ -54,6 +72,12 @@ func ExampleFile_GetSheetPrOptions() {
 		fitToPage                         FitToPage
 		autoPageBreaks                    AutoPageBreaks
 		outlineSummaryBelow               OutlineSummaryBelow
+		filterMode                        FilterMode
+		syncHorizontal                    SyncHorizontal
+		syncVertical                      SyncVertical
+		syncRef                           SyncRef
+		transitionEvaluation              TransitionEvaluation
+		transitionEntry                   TransitionEntry
 	)
 
 	if err := f.GetSheetPrOptions(sheet,
@@ -63,6 +87,12 @@ func ExampleFile_GetSheetPrOptions() {
 		&fitToPage,
 		&autoPageBreaks,
 		&outlineSummaryBelow,
+		&filterMode,
+		&syncHorizontal,
+		&syncVertical,
+		&syncRef,
+		&transitionEvaluation,
+		&transitionEntry,
 	); err != nil {
 		fmt.Println(err)
 	}
@@ -73,6 +103,12 @@ func ExampleFile_GetSheetPrOptions() {
 	fmt.Println("- fitToPage:", fitToPage)
 	fmt.Println("- autoPageBreaks:", autoPageBreaks)
 	fmt.Println("- outlineSummaryBelow:", outlineSummaryBelow)
+	fmt.Println("- filterMode:", filterMode)
+	fmt.Println("- syncHorizontal:", syncHorizontal)
+	fmt.Println("- syncVertical:", syncVertical)
+	fmt.Printf("- syncRef: %q\n", syncRef)
+	fmt.Println("- transitionEvaluation:", transitionEvaluation)
+	fmt.Println("- transitionEntry:", transitionEntry)
 	// Output:
 	// Defaults:
 	// - codeName: ""
@@ -81,6 +117,12 @@ func ExampleFile_GetSheetPrOptions() {
 	// - fitToPage: false
 	// - autoPageBreaks: false
 	// - outlineSummaryBelow: true
+	// - filterMode: false
+	// - syncHorizontal: false
+	// - syncVertical: false
+	// - syncRef: ""
+	// - transitionEvaluation: false
+	// - transitionEntry: false
 }
 
 func TestSheetPrOptions(t *testing.T) {
@@ -96,6 +138,12 @@ func TestSheetPrOptions(t *testing.T) {
 		{new(FitToPage), FitToPage(true)},
 		{new(AutoPageBreaks), AutoPageBreaks(true)},
 		{new(OutlineSummaryBelow), OutlineSummaryBelow(false)},
+		{new(FilterMode), FilterMode(true)},
+		{new(SyncHorizontal), SyncHorizontal(true)},
+		{new(SyncVertical), SyncVertical(true)},
+		{new(SyncRef), SyncRef("B2")},
+		{new(TransitionEvaluation), TransitionEvaluation(true)},
+		{new(TransitionEntry), TransitionEntry(true)},
 	}
 
 	for i, test := range testData {
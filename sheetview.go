@@ -215,3 +215,54 @@ func (f *File) GetSheetViewOptions(name string, viewIndex int, opts ...SheetView
 	}
 	return nil
 }
+
+// SetRightToLeft provides a function to switch the worksheet to right-to-left
+// or left-to-right mode by given worksheet name. All views of the worksheet
+// will be switched. When the optional workbookDefault parameter is true, the
+// reading order of the default cell format of the workbook will be set to
+// right-to-left too, so the cells without explicit alignment will be read in
+// right-to-left order. Note that the coordinates of cells, the panes set by
+// SetPanes and the anchors of the objects are still counted from column A,
+// the spreadsheet application mirrors them when displaying the worksheet. For
+// example, switch Sheet1 and the workbook default to right-to-left:
+//
+//    err := f.SetRightToLeft("Sheet1", true, true)
+//
+func (f *File) SetRightToLeft(sheet string, rightToLeft bool, workbookDefault ...bool) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ws.SheetViews == nil {
+		ws.SheetViews = &xlsxSheetViews{}
+	}
+	if len(ws.SheetViews.SheetView) == 0 {
+		ws.SheetViews.SheetView = append(ws.SheetViews.SheetView, xlsxSheetView{WorkbookViewID: 0})
+	}
+	for idx := range ws.SheetViews.SheetView {
+		ws.SheetViews.SheetView[idx].RightToLeft = rightToLeft
+	}
+	if len(workbookDefault) == 0 || !workbookDefault[0] {
+		return err
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	if s.CellXfs == nil || len(s.CellXfs.Xf) == 0 {
+		return err
+	}
+	xf := &s.CellXfs.Xf[0]
+	if xf.Alignment == nil {
+		xf.Alignment = &xlsxAlignment{}
+	}
+	xf.Alignment.ReadingOrder = 0
+	if rightToLeft {
+		xf.Alignment.ReadingOrder = 2
+		xf.ApplyAlignment = boolPtr(true)
+	}
+	if *xf.Alignment == (xlsxAlignment{}) {
+		xf.Alignment = nil
+	}
+	return err
+}
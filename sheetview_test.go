@@ -2,6 +2,7 @@ package excelize
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -180,3 +181,41 @@ func TestSheetViewOptionsErrors(t *testing.T) {
 	assert.Error(t, f.SetSheetViewOptions(sheet, 1))
 	assert.Error(t, f.SetSheetViewOptions(sheet, -2))
 }
+
+func TestSetRightToLeft(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetRightToLeft("Sheet1", true, true))
+	var rightToLeft RightToLeft
+	assert.NoError(t, f.GetSheetViewOptions("Sheet1", -1, &rightToLeft))
+	assert.True(t, bool(rightToLeft))
+	alignment, err := f.GetCellAlignment("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), alignment.ReadingOrder)
+	// Test freeze panes in right-to-left mode, the panes are still counted
+	// from column A
+	assert.NoError(t, f.SetPanes("Sheet1", `{"freeze":true,"x_split":1,"y_split":1,"top_left_cell":"B2","active_pane":"bottomRight","panes":[{"sqref":"B2","active_cell":"B2","pane":"bottomRight"}]}`))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, ws.SheetViews.SheetView[0].RightToLeft)
+	assert.Equal(t, &xlsxPane{XSplit: 1, YSplit: 1, TopLeftCell: "B2", ActivePane: "bottomRight", State: "frozen"}, ws.SheetViews.SheetView[0].Pane)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetRightToLeft.xlsx")))
+
+	// Test switch back to left-to-right mode
+	assert.NoError(t, f.SetRightToLeft("Sheet1", false, true))
+	assert.NoError(t, f.GetSheetViewOptions("Sheet1", -1, &rightToLeft))
+	assert.False(t, bool(rightToLeft))
+	alignment, err = f.GetCellAlignment("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), alignment.ReadingOrder)
+	// Test switch worksheet without sheet views
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetViews = nil
+	assert.NoError(t, f.SetRightToLeft("Sheet1", true))
+	assert.True(t, ws.SheetViews.SheetView[0].RightToLeft)
+	ws.SheetViews = nil
+	assert.NoError(t, f.SetPanes("Sheet1", `{"freeze":true,"y_split":1}`))
+	assert.Equal(t, "frozen", ws.SheetViews.SheetView[0].Pane.State)
+	// Test switch not exists worksheet
+	assert.EqualError(t, f.SetRightToLeft("SheetN", true), "sheet SheetN is not exist")
+}
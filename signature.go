@@ -0,0 +1,693 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Digital signature algorithms and identifiers.
+const (
+	signatureAlgorithmC14N                  = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	signatureAlgorithmRelationshipTransform = "http://schemas.openxmlformats.org/package/2006/RelationshipTransform"
+	signatureAlgorithmSHA256                = "http://www.w3.org/2001/04/xmlenc#sha256"
+	signatureAlgorithmRSASHA256             = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	signatureAlgorithmECDSASHA256           = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"
+	signatureReferenceTypeObject            = "http://www.w3.org/2000/09/xmldsig#Object"
+	signatureTimeFormat                     = "YYYY-MM-DDThh:mm:ssTZD"
+	defaultSignatureOriginPath              = "_xmlsignatures/origin.sigs"
+	defaultSignatureID                      = "idPackageSignature"
+	defaultSignatureObjectID                = "idPackageObject"
+	defaultSignatureTimeID                  = "idSignatureTime"
+)
+
+var (
+	// signatureDigestAlgorithms defined the supported digest algorithms of
+	// the references.
+	signatureDigestAlgorithms = map[string]crypto.Hash{
+		"http://www.w3.org/2000/09/xmldsig#sha1":        crypto.SHA1,
+		"http://www.w3.org/2001/04/xmlenc#sha256":       crypto.SHA256,
+		"http://www.w3.org/2001/04/xmldsig-more#sha384": crypto.SHA384,
+		"http://www.w3.org/2001/04/xmlenc#sha512":       crypto.SHA512,
+	}
+	// signatureMethods defined the supported signature algorithms of the
+	// signed information.
+	signatureMethods = map[string]crypto.Hash{
+		"http://www.w3.org/2000/09/xmldsig#rsa-sha1":          crypto.SHA1,
+		"http://www.w3.org/2001/04/xmldsig-more#rsa-sha256":   crypto.SHA256,
+		"http://www.w3.org/2001/04/xmldsig-more#rsa-sha384":   crypto.SHA384,
+		"http://www.w3.org/2001/04/xmldsig-more#rsa-sha512":   crypto.SHA512,
+		"http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha1":   crypto.SHA1,
+		"http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256": crypto.SHA256,
+		"http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha384": crypto.SHA384,
+		"http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha512": crypto.SHA512,
+	}
+)
+
+// signaturePart defined the XML signature part which will be created when
+// saving the workbook.
+type signaturePart struct {
+	path    string
+	options *SignatureOptions
+}
+
+// AddSignature provides the method to sign the workbook with the digital
+// signature by given signature options. The XML signature part will be
+// created when saving the workbook, all the parts of the package except the
+// content types and the digital signature parts will be signed, so any
+// changes of the workbook after saving will invalidate the signature. For
+// example, sign the workbook with the RSA private key and certificate:
+//
+//    pair, err := tls.LoadX509KeyPair("cert.pem", "key.pem")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    cert, err := x509.ParseCertificate(pair.Certificate[0])
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := f.AddSignature(&excelize.SignatureOptions{
+//        Signer:      pair.PrivateKey.(crypto.Signer),
+//        Certificate: cert,
+//    }); err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := f.SaveAs("Book1.xlsx"); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) AddSignature(opts *SignatureOptions) error {
+	if opts == nil || opts.Signer == nil || opts.Certificate == nil {
+		return errors.New("signer and certificate are required")
+	}
+	switch opts.Signer.Public().(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return errors.New("unsupported signer key type")
+	}
+	pub, err := x509.MarshalPKIXPublicKey(opts.Signer.Public())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pub, opts.Certificate.RawSubjectPublicKeyInfo) {
+		return errors.New("signer does not match the certificate")
+	}
+	originRels := f.addSignatureOrigin()
+	var rID int
+	for _, rel := range originRels.Relationships {
+		t, _ := strconv.Atoi(strings.TrimPrefix(rel.ID, "rId"))
+		if t > rID {
+			rID = t
+		}
+	}
+	var sigPath string
+	for idx := 1; ; idx++ {
+		sigPath = "_xmlsignatures/sig" + strconv.Itoa(idx) + ".xml"
+		if _, ok := f.XLSX[sigPath]; ok {
+			continue
+		}
+		var used bool
+		for _, sig := range f.signatures {
+			used = used || sig.path == sigPath
+		}
+		if !used {
+			break
+		}
+	}
+	originRels.Relationships = append(originRels.Relationships, xlsxRelationship{
+		ID:     "rId" + strconv.Itoa(rID+1),
+		Type:   SourceRelationshipDigitalSignatureXML,
+		Target: path.Base(sigPath),
+	})
+	f.setContentTypes("/"+sigPath, ContentTypeDigitalSignatureXML)
+	f.signatures = append(f.signatures, &signaturePart{path: sigPath, options: opts})
+	return nil
+}
+
+// VerifySignatures provides the method to verify all digital signatures of
+// the workbook. It will return the certificates and signing time of the
+// signatures if all of them are valid, or return an error if any of the
+// signed parts has been changed or any signature value is incorrect. The
+// trust of the certificates should be verified by the caller. For example:
+//
+//    signatures, err := f.VerifySignatures()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, sig := range signatures {
+//        fmt.Println(sig.Certificate.Subject, sig.SigningTime)
+//    }
+//
+func (f *File) VerifySignatures() ([]Signature, error) {
+	var signatures []Signature
+	originPath := f.getSignatureOriginPath()
+	if originPath == "" {
+		return signatures, nil
+	}
+	originRels := new(xlsxRelationships)
+	if err := f.xmlNewDecoder(bytes.NewReader(f.readXML(getPartRelsPath(originPath)))).
+		Decode(originRels); err != nil && err != io.EOF {
+		return signatures, f.newXMLDecodeError(getPartRelsPath(originPath), err)
+	}
+	for _, rel := range originRels.Relationships {
+		if rel.Type != SourceRelationshipDigitalSignatureXML {
+			continue
+		}
+		sigPath := resolvePartPath(originPath, rel.Target)
+		sig, err := f.verifySignature(sigPath)
+		if err != nil {
+			return signatures, fmt.Errorf("invalid signature %s: %s", sigPath, err)
+		}
+		signatures = append(signatures, sig)
+	}
+	return signatures, nil
+}
+
+// verifySignature provides a function to verify the digital signature by
+// given XML signature part path.
+func (f *File) verifySignature(sigPath string) (Signature, error) {
+	var sig Signature
+	content, ok := f.XLSX[sigPath]
+	if !ok {
+		return sig, errors.New("signature part does not exist")
+	}
+	signature := new(xlsxSignature)
+	if err := f.xmlNewDecoder(bytes.NewReader(content)).Decode(signature); err != nil {
+		return sig, f.newXMLDecodeError(sigPath, err)
+	}
+	if signature.KeyInfo == nil || len(signature.KeyInfo.X509Certificate) == 0 {
+		return sig, errors.New("certificate does not exist")
+	}
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature.KeyInfo.X509Certificate[0]))
+	if err != nil {
+		return sig, err
+	}
+	if sig.Certificate, err = x509.ParseCertificate(der); err != nil {
+		return sig, err
+	}
+	for _, ref := range signature.SignedInfo.Reference {
+		if !strings.HasPrefix(ref.URI, "#") {
+			return sig, fmt.Errorf("unsupported reference %s", ref.URI)
+		}
+		id := strings.TrimPrefix(ref.URI, "#")
+		data, err := canonicalizeXML(content, func(se xml.StartElement) bool {
+			for _, attr := range se.Attr {
+				if attr.Name.Local == "Id" && attr.Value == id {
+					return true
+				}
+			}
+			return false
+		})
+		if err != nil {
+			return sig, err
+		}
+		if err = verifySignatureDigest(ref, data); err != nil {
+			return sig, err
+		}
+	}
+	for _, obj := range signature.Object {
+		if obj.SignatureTime != "" {
+			sig.SigningTime, _ = time.Parse(time.RFC3339, obj.SignatureTime)
+		}
+		if obj.Manifest == nil {
+			continue
+		}
+		for _, ref := range obj.Manifest.Reference {
+			if err = f.verifySignaturePart(ref); err != nil {
+				return sig, err
+			}
+		}
+	}
+	signedInfo, err := canonicalizeXML(content, func(se xml.StartElement) bool {
+		return se.Name.Local == "SignedInfo"
+	})
+	if err != nil {
+		return sig, err
+	}
+	value, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(signature.SignatureValue), ""))
+	if err != nil {
+		return sig, err
+	}
+	return sig, verifySignatureValue(sig.Certificate, signature.SignedInfo.SignatureMethod.Algorithm, signedInfo, value)
+}
+
+// verifySignaturePart provides a function to verify the digest of the
+// package part by given manifest reference.
+func (f *File) verifySignaturePart(ref xlsxSignatureReference) error {
+	uri, err := url.Parse(ref.URI)
+	if err != nil {
+		return err
+	}
+	partPath := strings.TrimPrefix(uri.Path, "/")
+	if _, ok := f.XLSX[partPath]; !ok {
+		return fmt.Errorf("signed part %s does not exist", partPath)
+	}
+	data := f.readXML(partPath)
+	for _, transform := range ref.Transforms {
+		if transform.Algorithm != signatureAlgorithmRelationshipTransform {
+			continue
+		}
+		rels := new(xlsxRelationships)
+		if err = f.xmlNewDecoder(bytes.NewReader(data)).Decode(rels); err != nil && err != io.EOF {
+			return f.newXMLDecodeError(partPath, err)
+		}
+		IDs := make(map[string]bool)
+		for _, r := range transform.RelationshipReference {
+			IDs[r.SourceID] = true
+		}
+		data = relationshipTransform(rels, func(rel xlsxRelationship) bool { return IDs[rel.ID] })
+	}
+	if err = verifySignatureDigest(ref, data); err != nil {
+		return fmt.Errorf("signed part %s: %s", partPath, err)
+	}
+	return nil
+}
+
+// verifySignatureDigest provides a function to check the digest value of the
+// reference by given data.
+func verifySignatureDigest(ref xlsxSignatureReference, data []byte) error {
+	hash, ok := signatureDigestAlgorithms[ref.DigestMethod.Algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported digest algorithm %s", ref.DigestMethod.Algorithm)
+	}
+	h := hash.New()
+	h.Write(data)
+	if base64.StdEncoding.EncodeToString(h.Sum(nil)) != strings.TrimSpace(ref.DigestValue) {
+		return errors.New("digest value mismatch")
+	}
+	return nil
+}
+
+// verifySignatureValue provides a function to check the signature value of
+// the canonicalized signed information by given certificate and signature
+// method.
+func verifySignatureValue(cert *x509.Certificate, method string, signedInfo, value []byte) error {
+	hash, ok := signatureMethods[method]
+	if !ok {
+		return fmt.Errorf("unsupported signature algorithm %s", method)
+	}
+	h := hash.New()
+	h.Write(signedInfo)
+	digest := h.Sum(nil)
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(pub, hash, digest, value) != nil {
+			return errors.New("signature value mismatch")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		size := len(value) / 2
+		r, s := new(big.Int).SetBytes(value[:size]), new(big.Int).SetBytes(value[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("signature value mismatch")
+		}
+		return nil
+	}
+	return errors.New("unsupported certificate key type")
+}
+
+// addSignatureOrigin provides a function to add the digital signature origin
+// part and its relationship to the package if not exists, and returns the
+// relationships of the origin part.
+func (f *File) addSignatureOrigin() *xlsxRelationships {
+	originPath := f.getSignatureOriginPath()
+	if originPath == "" {
+		originPath = defaultSignatureOriginPath
+		rels, _ := f.relsReader("_rels/.rels")
+		if rels == nil {
+			rels = &xlsxRelationships{}
+			f.Relationships["_rels/.rels"] = rels
+		}
+		var rID int
+		for _, rel := range rels.Relationships {
+			t, _ := strconv.Atoi(strings.TrimPrefix(rel.ID, "rId"))
+			if t > rID {
+				rID = t
+			}
+		}
+		rels.Relationships = append(rels.Relationships, xlsxRelationship{
+			ID:     "rId" + strconv.Itoa(rID+1),
+			Type:   SourceRelationshipDigitalSignatureOrigin,
+			Target: originPath,
+		})
+		f.XLSX[originPath] = []byte{}
+	}
+	content, _ := f.contentTypesReader()
+	var ok bool
+	for _, d := range content.Defaults {
+		ok = ok || d.Extension == strings.TrimPrefix(path.Ext(originPath), ".")
+	}
+	for _, o := range content.Overrides {
+		ok = ok || o.PartName == "/"+originPath
+	}
+	if !ok {
+		content.Defaults = append(content.Defaults, xlsxDefault{
+			Extension:   strings.TrimPrefix(path.Ext(originPath), "."),
+			ContentType: ContentTypeDigitalSignatureOrigin,
+		})
+	}
+	relsPath := getPartRelsPath(originPath)
+	if rels, _ := f.relsReader(relsPath); rels == nil {
+		f.Relationships[relsPath] = &xlsxRelationships{}
+	}
+	return f.Relationships[relsPath]
+}
+
+// getSignatureOriginPath provides a function to get the path of the digital
+// signature origin part, returns empty string if the package doesn't contain
+// the origin part.
+func (f *File) getSignatureOriginPath() string {
+	if rels, _ := f.relsReader("_rels/.rels"); rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipDigitalSignatureOrigin {
+				return strings.TrimPrefix(rel.Target, "/")
+			}
+		}
+	}
+	return ""
+}
+
+// signWorkbook provides a function to create the XML signature parts of the
+// workbook, it should be called after all the other parts have been
+// serialized.
+func (f *File) signWorkbook() error {
+	if len(f.signatures) == 0 {
+		return nil
+	}
+	manifest, err := f.signatureManifest()
+	if err != nil {
+		return err
+	}
+	for _, sig := range f.signatures {
+		content, err := sig.sign(manifest)
+		if err != nil {
+			return err
+		}
+		f.XLSX[sig.path] = content
+	}
+	return nil
+}
+
+// signatureManifest provides a function to build the manifest references of
+// all the parts to be signed in the package.
+func (f *File) signatureManifest() (string, error) {
+	parts := make(map[string][]byte, len(f.XLSX)+len(f.streams))
+	for partPath := range f.XLSX {
+		parts[partPath] = f.readXML(partPath)
+	}
+	for partPath, stream := range f.streams {
+		r, err := stream.rawData.Reader()
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err = f.copyXMLPart(&buf, r, partPath); err != nil {
+			return "", err
+		}
+		parts[partPath] = buf.Bytes()
+	}
+	partPaths := make([]string, 0, len(parts))
+	for partPath := range parts {
+		if partPath == "[Content_Types].xml" || strings.HasPrefix(partPath, "_xmlsignatures/") {
+			continue
+		}
+		partPaths = append(partPaths, partPath)
+	}
+	sort.Strings(partPaths)
+	var manifest strings.Builder
+	for _, partPath := range partPaths {
+		data, uri := parts[partPath], (&url.URL{Path: "/" + partPath}).EscapedPath()
+		contentType := f.getPartContentType(partPath)
+		if contentType != "" {
+			uri += "?ContentType=" + contentType
+		}
+		manifest.WriteString(`<Reference URI="` + escapeCanonicalAttr(uri) + `">`)
+		if contentType == ContentTypeRelationships {
+			rels := new(xlsxRelationships)
+			if err := f.xmlNewDecoder(bytes.NewReader(data)).Decode(rels); err != nil && err != io.EOF {
+				return "", f.newXMLDecodeError(partPath, err)
+			}
+			manifest.WriteString(`<Transforms><Transform Algorithm="` + signatureAlgorithmRelationshipTransform + `">`)
+			var IDs []string
+			for _, rel := range rels.Relationships {
+				if rel.Type != SourceRelationshipDigitalSignatureOrigin {
+					IDs = append(IDs, rel.ID)
+				}
+			}
+			sort.Strings(IDs)
+			selected := make(map[string]bool, len(IDs))
+			for _, ID := range IDs {
+				selected[ID] = true
+				manifest.WriteString(`<mdssi:RelationshipReference xmlns:mdssi="` + NameSpaceDigitalSignature + `" SourceId="` + escapeCanonicalAttr(ID) + `"></mdssi:RelationshipReference>`)
+			}
+			manifest.WriteString(`</Transform><Transform Algorithm="` + signatureAlgorithmC14N + `"></Transform></Transforms>`)
+			data = relationshipTransform(rels, func(rel xlsxRelationship) bool { return selected[rel.ID] })
+		}
+		manifest.WriteString(`<DigestMethod Algorithm="` + signatureAlgorithmSHA256 + `"></DigestMethod><DigestValue>` + signatureDigest(data) + `</DigestValue></Reference>`)
+	}
+	return manifest.String(), nil
+}
+
+// sign provides a function to create the XML signature part content by given
+// manifest references.
+func (sig *signaturePart) sign(manifest string) ([]byte, error) {
+	signingTime := sig.options.SigningTime
+	if signingTime.IsZero() {
+		signingTime = time.Now()
+	}
+	object := `<Object Id="` + defaultSignatureObjectID + `"><Manifest>` + manifest + `</Manifest><SignatureProperties><SignatureProperty Id="` + defaultSignatureTimeID + `" Target="#` + defaultSignatureID + `"><mdssi:SignatureTime xmlns:mdssi="` + NameSpaceDigitalSignature + `"><mdssi:Format>` + signatureTimeFormat + `</mdssi:Format><mdssi:Value>` + signingTime.UTC().Format(time.RFC3339) + `</mdssi:Value></mdssi:SignatureTime></SignatureProperty></SignatureProperties></Object>`
+	root := `<Signature xmlns="` + NameSpaceXMLSignature + `" Id="` + defaultSignatureID + `">`
+	objectData, err := canonicalizeXML([]byte(root+object+`</Signature>`), func(se xml.StartElement) bool {
+		return se.Name.Local == "Object"
+	})
+	if err != nil {
+		return nil, err
+	}
+	method := signatureAlgorithmRSASHA256
+	if _, ok := sig.options.Signer.Public().(*ecdsa.PublicKey); ok {
+		method = signatureAlgorithmECDSASHA256
+	}
+	signedInfo := `<SignedInfo><CanonicalizationMethod Algorithm="` + signatureAlgorithmC14N + `"></CanonicalizationMethod><SignatureMethod Algorithm="` + method + `"></SignatureMethod><Reference Type="` + signatureReferenceTypeObject + `" URI="#` + defaultSignatureObjectID + `"><DigestMethod Algorithm="` + signatureAlgorithmSHA256 + `"></DigestMethod><DigestValue>` + signatureDigest(objectData) + `</DigestValue></Reference></SignedInfo>`
+	signedInfoData, err := canonicalizeXML([]byte(root+signedInfo+`</Signature>`), func(se xml.StartElement) bool {
+		return se.Name.Local == "SignedInfo"
+	})
+	if err != nil {
+		return nil, err
+	}
+	h := crypto.SHA256.New()
+	h.Write(signedInfoData)
+	value, err := sig.options.Signer.Sign(rand.Reader, h.Sum(nil), crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	if pub, ok := sig.options.Signer.Public().(*ecdsa.PublicKey); ok {
+		var ecdsaSig struct{ R, S *big.Int }
+		if _, err = asn1.Unmarshal(value, &ecdsaSig); err != nil {
+			return nil, err
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		value = make([]byte, 2*size)
+		r, s := ecdsaSig.R.Bytes(), ecdsaSig.S.Bytes()
+		copy(value[size-len(r):size], r)
+		copy(value[2*size-len(s):], s)
+	}
+	return []byte(XMLHeader + root + signedInfo +
+		`<SignatureValue>` + base64.StdEncoding.EncodeToString(value) + `</SignatureValue>` +
+		`<KeyInfo><X509Data><X509Certificate>` + base64.StdEncoding.EncodeToString(sig.options.Certificate.Raw) + `</X509Certificate></X509Data></KeyInfo>` +
+		object + `</Signature>`), nil
+}
+
+// getPartContentType provides a function to get the content type of the
+// package part by given part path.
+func (f *File) getPartContentType(partPath string) string {
+	content, _ := f.contentTypesReader()
+	for _, o := range content.Overrides {
+		if strings.EqualFold(o.PartName, "/"+partPath) {
+			return o.ContentType
+		}
+	}
+	for _, d := range content.Defaults {
+		if strings.EqualFold("."+d.Extension, path.Ext(partPath)) {
+			return d.ContentType
+		}
+	}
+	return ""
+}
+
+// signatureDigest provides a function to calculate the base64 encoded SHA-256
+// digest value of the given data.
+func signatureDigest(data []byte) string {
+	h := crypto.SHA256.New()
+	h.Write(data)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// relationshipTransform provides a function to apply the relationship
+// transform to the relationships part, the selected relationships will be
+// sorted by the identifier and serialized in canonical form.
+func relationshipTransform(rels *xlsxRelationships, selected func(rel xlsxRelationship) bool) []byte {
+	var relationships []xlsxRelationship
+	for _, rel := range rels.Relationships {
+		if selected(rel) {
+			relationships = append(relationships, rel)
+		}
+	}
+	sort.Slice(relationships, func(i, j int) bool { return relationships[i].ID < relationships[j].ID })
+	var buf bytes.Buffer
+	buf.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for _, rel := range relationships {
+		targetMode := rel.TargetMode
+		if targetMode == "" {
+			targetMode = "Internal"
+		}
+		buf.WriteString(`<Relationship Id="` + escapeCanonicalAttr(rel.ID) + `" Target="` + escapeCanonicalAttr(rel.Target) +
+			`" TargetMode="` + escapeCanonicalAttr(targetMode) + `" Type="` + escapeCanonicalAttr(rel.Type) + `"></Relationship>`)
+	}
+	buf.WriteString(`</Relationships>`)
+	return buf.Bytes()
+}
+
+// canonicalizeXML provides a function to serialize the first element matched
+// by the given function in the canonical form defined by the Canonical XML
+// Version 1.0 without comments.
+func canonicalizeXML(doc []byte, match func(se xml.StartElement) bool) ([]byte, error) {
+	var (
+		buf    bytes.Buffer
+		depth  int
+		scopes = []map[string]string{{"xml": NameSpaceXML}}
+		d      = xml.NewDecoder(bytes.NewReader(doc))
+	)
+	for {
+		token, err := d.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("canonicalize element does not exist")
+			}
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			parent := scopes[len(scopes)-1]
+			scope := make(map[string]string, len(parent))
+			for prefix, ns := range parent {
+				scope[prefix] = ns
+			}
+			var attrs []xml.Attr
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					scope[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					scope[""] = attr.Value
+				default:
+					attrs = append(attrs, attr)
+				}
+			}
+			scopes = append(scopes, scope)
+			if depth == 0 {
+				if !match(t) {
+					continue
+				}
+				parent = map[string]string{"": "", "xml": NameSpaceXML}
+			}
+			depth++
+			var prefixes []string
+			for prefix, ns := range scope {
+				if pns, ok := parent[prefix]; (!ok && ns != "") || (ok && pns != ns) {
+					prefixes = append(prefixes, prefix)
+				}
+			}
+			sort.Strings(prefixes)
+			sort.Slice(attrs, func(i, j int) bool {
+				nsi, nsj := scope[attrs[i].Name.Space], scope[attrs[j].Name.Space]
+				if attrs[i].Name.Space == "" {
+					nsi = ""
+				}
+				if attrs[j].Name.Space == "" {
+					nsj = ""
+				}
+				if nsi != nsj {
+					return nsi < nsj
+				}
+				return attrs[i].Name.Local < attrs[j].Name.Local
+			})
+			buf.WriteString("<" + canonicalName(t.Name))
+			for _, prefix := range prefixes {
+				if prefix == "" {
+					buf.WriteString(` xmlns="` + escapeCanonicalAttr(scope[prefix]) + `"`)
+					continue
+				}
+				buf.WriteString(` xmlns:` + prefix + `="` + escapeCanonicalAttr(scope[prefix]) + `"`)
+			}
+			for _, attr := range attrs {
+				buf.WriteString(" " + canonicalName(attr.Name) + `="` + escapeCanonicalAttr(attr.Value) + `"`)
+			}
+			buf.WriteString(">")
+		case xml.EndElement:
+			scopes = scopes[:len(scopes)-1]
+			if depth == 0 {
+				continue
+			}
+			buf.WriteString("</" + canonicalName(t.Name) + ">")
+			if depth--; depth == 0 {
+				return buf.Bytes(), nil
+			}
+		case xml.CharData:
+			if depth > 0 {
+				buf.WriteString(escapeCanonicalText(string(t)))
+			}
+		}
+	}
+}
+
+// canonicalName provides a function to get the qualified name of the
+// element or attribute.
+func canonicalName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// escapeCanonicalText provides a function to escape the text node in the
+// canonical form.
+func escapeCanonicalText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;").Replace(s)
+}
+
+// escapeCanonicalAttr provides a function to escape the attribute value in
+// the canonical form.
+func escapeCanonicalAttr(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;").Replace(s)
+}
@@ -0,0 +1,157 @@
+package excelize
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/xml"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCertificate(t *testing.T, signer crypto.Signer) *x509.Certificate {
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "excelize"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, signer.Public(), signer)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert
+}
+
+func TestSignature(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	rsaCert, ecdsaCert := newTestCertificate(t, rsaKey), newTestCertificate(t, ecdsaKey)
+	signingTime := time.Date(2020, 12, 31, 8, 30, 0, 0, time.UTC)
+
+	f := NewFile()
+	signatures, err := f.VerifySignatures()
+	assert.NoError(t, err)
+	assert.Empty(t, signatures)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "signed"))
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"signed"}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.AddSignature(&SignatureOptions{Signer: rsaKey, Certificate: rsaCert, SigningTime: signingTime}))
+	assert.NoError(t, f.AddSignature(&SignatureOptions{Signer: ecdsaKey, Certificate: ecdsaCert}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSignature.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestSignature.xlsx"))
+	assert.NoError(t, err)
+	signatures, err = f.VerifySignatures()
+	assert.NoError(t, err)
+	if assert.Len(t, signatures, 2) {
+		assert.Equal(t, rsaCert.Raw, signatures[0].Certificate.Raw)
+		assert.Equal(t, signingTime, signatures[0].SigningTime)
+		assert.Equal(t, ecdsaCert.Raw, signatures[1].Certificate.Raw)
+	}
+
+	// Test co-sign the signed workbook
+	assert.NoError(t, f.AddSignature(&SignatureOptions{Signer: rsaKey, Certificate: rsaCert}))
+	assert.Equal(t, "_xmlsignatures/sig3.xml", f.signatures[0].path)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSignature.xlsx")))
+	signatures, err = f.VerifySignatures()
+	assert.NoError(t, err)
+	assert.Len(t, signatures, 3)
+
+	// Test sign the workbook with the formatted XML parts
+	formatted := NewFile()
+	sw, err = formatted.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"signed"}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, formatted.AddSignature(&SignatureOptions{Signer: rsaKey, Certificate: rsaCert}))
+	assert.NoError(t, formatted.SaveAs(filepath.Join("test", "TestSignatureFormatted.xlsx"), Options{PrettyXML: true}))
+	formatted, err = OpenFile(filepath.Join("test", "TestSignatureFormatted.xlsx"))
+	assert.NoError(t, err)
+	signatures, err = formatted.VerifySignatures()
+	assert.NoError(t, err)
+	assert.Len(t, signatures, 1)
+
+	// Test verify signatures with tampered parts
+	sheet := f.XLSX["xl/worksheets/sheet1.xml"]
+	f.XLSX["xl/worksheets/sheet1.xml"] = append(sheet, ' ')
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: signed part xl/worksheets/sheet1.xml: digest value mismatch")
+	f.XLSX["xl/worksheets/sheet1.xml"] = sheet
+	delete(f.XLSX, "xl/styles.xml")
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: signed part xl/styles.xml does not exist")
+
+	// Test verify signatures with unsigned object and tampered signature part
+	f, err = OpenFile(filepath.Join("test", "TestSignature.xlsx"))
+	assert.NoError(t, err)
+	sig := f.XLSX["_xmlsignatures/sig1.xml"]
+	f.XLSX["_xmlsignatures/sig1.xml"] = []byte(string(sig[:len(sig)-len("</Signature>")]) + "<Object></Object></Signature>")
+	_, err = f.VerifySignatures()
+	assert.NoError(t, err)
+	signed := new(xlsxSignature)
+	assert.NoError(t, xml.Unmarshal(sig, signed))
+	f.XLSX["_xmlsignatures/sig1.xml"] = []byte(`<Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo><SignatureMethod Algorithm="http://www.w3.org/2000/09/xmldsig#rsa-sha1"></SignatureMethod></SignedInfo><SignatureValue>` + signed.SignatureValue + `</SignatureValue><KeyInfo><X509Data><X509Certificate>` + signed.KeyInfo.X509Certificate[0] + `</X509Certificate></X509Data></KeyInfo></Signature>`)
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: signature value mismatch")
+	f.XLSX["_xmlsignatures/sig1.xml"] = []byte(`<Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo></SignedInfo></Signature>`)
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: certificate does not exist")
+	f.XLSX["_xmlsignatures/sig1.xml"] = []byte(`<Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo><Reference URI="/xl/workbook.xml"></Reference></SignedInfo><KeyInfo><X509Data><X509Certificate>` + signed.KeyInfo.X509Certificate[0] + `</X509Certificate></X509Data></KeyInfo></Signature>`)
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: unsupported reference /xl/workbook.xml")
+	f.XLSX["_xmlsignatures/sig1.xml"] = []byte(`<Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo><Reference URI="#idPackageObject"><DigestMethod Algorithm="md5"></DigestMethod></Reference></SignedInfo><KeyInfo><X509Data><X509Certificate>` + signed.KeyInfo.X509Certificate[0] + `</X509Certificate></X509Data></KeyInfo><Object Id="idPackageObject"></Object></Signature>`)
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: unsupported digest algorithm md5")
+	f.XLSX["_xmlsignatures/sig1.xml"] = []byte(`<Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo><SignatureMethod Algorithm="md5"></SignatureMethod></SignedInfo><KeyInfo><X509Data><X509Certificate>` + signed.KeyInfo.X509Certificate[0] + `</X509Certificate></X509Data></KeyInfo></Signature>`)
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: unsupported signature algorithm md5")
+	f.XLSX["_xmlsignatures/sig1.xml"] = []byte(`<Signature`)
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: xml decode error: XML syntax error on line 1: unexpected EOF")
+	delete(f.XLSX, "_xmlsignatures/sig1.xml")
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "invalid signature _xmlsignatures/sig1.xml: signature part does not exist")
+	f.XLSX["_xmlsignatures/_rels/origin.sigs.rels"] = MacintoshCyrillicCharset
+	_, err = f.VerifySignatures()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+
+	// Test add signature with invalid options
+	f = NewFile()
+	assert.EqualError(t, f.AddSignature(nil), "signer and certificate are required")
+	assert.EqualError(t, f.AddSignature(&SignatureOptions{Signer: rsaKey}), "signer and certificate are required")
+	assert.EqualError(t, f.AddSignature(&SignatureOptions{Signer: rsaKey, Certificate: ecdsaCert}), "signer does not match the certificate")
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	assert.EqualError(t, f.AddSignature(&SignatureOptions{Signer: ed25519Key, Certificate: rsaCert}), "unsupported signer key type")
+
+	// Test sign the workbook with unsupported charset relationships
+	assert.NoError(t, f.AddSignature(&SignatureOptions{Signer: rsaKey, Certificate: rsaCert}))
+	f.XLSX["xl/_rels/unsupported.xml.rels"] = MacintoshCyrillicCharset
+	_, err = f.WriteToBuffer()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestCanonicalizeXML(t *testing.T) {
+	doc := []byte(`<?xml version="1.0"?><a:root xmlns:a="urn:a" xmlns="urn:default" xmlns:b="urn:b"><!-- comment --><a:item b:z="1" y="2" a:x="&quot;3&#9;"><child xmlns="">x &amp; y &gt; z</child><b:empty/></a:item></a:root>`)
+	data, err := canonicalizeXML(doc, func(se xml.StartElement) bool { return se.Name.Local == "item" })
+	assert.NoError(t, err)
+	assert.Equal(t, `<a:item xmlns="urn:default" xmlns:a="urn:a" xmlns:b="urn:b" y="2" a:x="&quot;3&#x9;" b:z="1"><child xmlns="">x &amp; y &gt; z</child><b:empty></b:empty></a:item>`, string(data))
+	_, err = canonicalizeXML(doc, func(se xml.StartElement) bool { return se.Name.Local == "none" })
+	assert.EqualError(t, err, "canonicalize element does not exist")
+	_, err = canonicalizeXML([]byte(`<a`), func(se xml.StartElement) bool { return true })
+	assert.EqualError(t, err, "XML syntax error on line 1: unexpected EOF")
+}
@@ -0,0 +1,565 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SortKey directly maps the settings of a sort key used in SortRange.
+// Column is the column name of the key, which should be in the range.
+// CustomList specifies the order of the values, such as the days of the
+// week, the values which not in the list will be placed after the values in
+// the list.
+type SortKey struct {
+	Column        string
+	Descending    bool
+	CaseSensitive bool
+	CustomList    []string
+}
+
+// sortValueKind defined the order of the types of the cell values in the
+// ascending sort, the blank cells are always placed last.
+type sortValueKind byte
+
+// This section defines the kinds of the sort value.
+const (
+	sortValueNumber sortValueKind = iota
+	sortValueText
+	sortValueBool
+	sortValueError
+	sortValueBlank
+)
+
+// sortValue holds the value of the cell for comparison.
+type sortValue struct {
+	kind sortValueKind
+	num  float64
+	str  string
+}
+
+// sortRow holds the cells of a row in the range to be sorted.
+type sortRow struct {
+	row    int
+	cells  []xlsxC
+	values []sortValue
+}
+
+// SortRange provides a function to sort the rows of the range by given
+// worksheet name, range reference and sort keys. The values, formulas and
+// styles of the cells will be moved with the rows, and the relative
+// references in the formulas will be rewritten by the offset of the row. The
+// rows will be sorted by the first column ascending if no sort key given. The
+// order of the ascending sort is numbers, text, logical values, errors and
+// blank cells, the text is sorted case-insensitively unless the CaseSensitive
+// specified. Note that the range should not contain the header row. For
+// example, sort the range A2:D10 on Sheet1 by the column B descending and
+// then by the column A in order of the custom list:
+//
+//    err := f.SortRange("Sheet1", "A2:D10", excelize.SortKey{
+//        Column:     "B",
+//        Descending: true,
+//    }, excelize.SortKey{
+//        Column:     "A",
+//        CustomList: []string{"Low", "Medium", "High"},
+//    })
+//
+func (f *File) SortRange(sheet, rangeRef string, keys ...SortKey) error {
+	if len(strings.Split(rangeRef, ":")) != 2 {
+		return fmt.Errorf("invalid sort range %q", rangeRef)
+	}
+	coordinates, err := f.areaRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		name, _ := ColumnNumberToName(coordinates[0])
+		keys = []SortKey{{Column: name}}
+	}
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = key.Column
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	rows, err := f.readRangeRows(ws, coordinates, columns)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for k, key := range keys {
+			if c := compareSortValues(rows[i].values[k], rows[j].values[k], key); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return f.writeRangeRows(ws, sheet, coordinates, rows)
+}
+
+// RemoveDuplicates provides a function to remove the duplicate rows of the
+// range by given worksheet name, range reference and column names, and
+// returns the number of the removed rows. The rows are duplicate if the
+// values in the given columns are the same, all the columns of the range
+// will be compared if no column given. The text is compared
+// case-insensitively and the first row of the duplicate rows will be kept,
+// the remaining rows will be moved up and the cells at the bottom of the
+// range will be cleared, the cells outside the range will not be changed.
+// Note that the range should not contain the header row. For example, remove
+// the rows in range A2:D10 on Sheet1 which have the same values in the
+// columns A and C:
+//
+//    removed, err := f.RemoveDuplicates("Sheet1", "A2:D10", "A", "C")
+//
+func (f *File) RemoveDuplicates(sheet, rangeRef string, columns ...string) (int, error) {
+	if len(strings.Split(rangeRef, ":")) != 2 {
+		return 0, fmt.Errorf("invalid range %q", rangeRef)
+	}
+	coordinates, err := f.areaRefToCoordinates(rangeRef)
+	if err != nil {
+		return 0, err
+	}
+	_ = sortCoordinates(coordinates)
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return 0, err
+	}
+	if len(columns) == 0 {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			name, _ := ColumnNumberToName(col)
+			columns = append(columns, name)
+		}
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	rows, err := f.readRangeRows(ws, coordinates, columns)
+	if err != nil {
+		return 0, err
+	}
+	unique := make([]sortRow, 0, len(rows))
+	for _, r := range rows {
+		var duplicate bool
+		for _, u := range unique {
+			if duplicate = equalSortValues(r.values, u.values); duplicate {
+				break
+			}
+		}
+		if !duplicate {
+			unique = append(unique, r)
+		}
+	}
+	if err := f.writeRangeRows(ws, sheet, coordinates, unique); err != nil {
+		return 0, err
+	}
+	return len(rows) - len(unique), nil
+}
+
+// readRangeRows provides a function to read the rows of the range by given
+// worksheet, range coordinates and column names of the values for
+// comparison. The shared formulas in the range will be converted to the
+// normal formulas.
+func (f *File) readRangeRows(ws *xlsxWorksheet, coordinates []int, columns []string) ([]sortRow, error) {
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			rect, err := f.areaRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return nil, err
+			}
+			if isOverlap(coordinates, rect) {
+				return nil, errors.New("cannot change the range which contains merged cells")
+			}
+		}
+	}
+	cols := make([]int, len(columns))
+	for i, column := range columns {
+		col, err := ColumnNameToNumber(column)
+		if err != nil {
+			return nil, err
+		}
+		if col < coordinates[0] || col > coordinates[2] {
+			return nil, fmt.Errorf("incorrect index of column '%s'", column)
+		}
+		cols[i] = col - coordinates[0]
+	}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		prepareSheetXML(ws, coordinates[2], row)
+	}
+	convertSharedFormulas(ws, coordinates)
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]sortRow, 0, coordinates[3]-coordinates[1]+1)
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		cells := make([]xlsxC, coordinates[2]-coordinates[0]+1)
+		copy(cells, ws.SheetData.Row[row-1].C[coordinates[0]-1:coordinates[2]])
+		values := make([]sortValue, len(cols))
+		for i, col := range cols {
+			values[i] = getSortValue(&cells[col], sst)
+		}
+		rows = append(rows, sortRow{row: row, cells: cells, values: values})
+	}
+	return rows, nil
+}
+
+// writeRangeRows provides a function to write the rows to the range from the
+// first row of the range by given worksheet, worksheet name and range
+// coordinates. The relative references in the formulas will be rewritten by
+// the offset of the row, and the remaining cells of the range will be
+// cleared.
+func (f *File) writeRangeRows(ws *xlsxWorksheet, sheet string, coordinates []int, rows []sortRow) error {
+	sheetID := f.getSheetID(sheet)
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cell, _ := CoordinatesToCellName(col, row)
+			target := &ws.SheetData.Row[row-1].C[col-1]
+			c := xlsxC{}
+			if i := row - coordinates[1]; i < len(rows) {
+				if c = rows[i].cells[col-coordinates[0]]; c.F != nil && row != rows[i].row {
+					formula := *c.F
+					formula.Content = shiftFormulaReferences(formula.Content, 0, row-rows[i].row)
+					c.F = &formula
+				}
+			}
+			if target.F != nil && c.F == nil {
+				if err := f.deleteCalcChain(sheetID, cell); err != nil {
+					return err
+				}
+			}
+			c.R = cell
+			*target = c
+		}
+	}
+	return nil
+}
+
+// getSortValue provides a function to get the value of the cell for
+// comparison in sorting, the empty text will be treated as blank cell.
+func getSortValue(c *xlsxC, sst *xlsxSST) sortValue {
+	v := c.value()
+	switch c.T {
+	case "s":
+		if idx, err := strconv.Atoi(c.V); err == nil && idx >= 0 && idx < sst.len() {
+			if si, err := sst.item(idx); err == nil {
+				v = si.String()
+			}
+		}
+		if v != "" {
+			return sortValue{kind: sortValueText, str: v}
+		}
+	case "inlineStr":
+		if c.IS != nil {
+			v = c.IS.String()
+		}
+		if v != "" {
+			return sortValue{kind: sortValueText, str: v}
+		}
+	case "str":
+		if v != "" {
+			return sortValue{kind: sortValueText, str: v}
+		}
+	case "b":
+		return sortValue{kind: sortValueBool, str: c.V}
+	case "e":
+		return sortValue{kind: sortValueError, str: c.V}
+	}
+	if v == "" {
+		return sortValue{kind: sortValueBlank}
+	}
+	if num, err := strconv.ParseFloat(v, 64); err == nil {
+		return sortValue{kind: sortValueNumber, num: num, str: v}
+	}
+	return sortValue{kind: sortValueText, str: v}
+}
+
+// compareSortValues provides a function to compare two values by given sort
+// key. It returns a negative number if the value a should be placed before
+// the value b, and a positive number if after.
+func compareSortValues(a, b sortValue, key SortKey) int {
+	if a.kind == sortValueBlank || b.kind == sortValueBlank {
+		switch {
+		case a.kind == b.kind:
+			return 0
+		case a.kind == sortValueBlank:
+			return 1
+		}
+		return -1
+	}
+	var c int
+	if len(key.CustomList) > 0 {
+		i, j := customListIndex(key.CustomList, a.str), customListIndex(key.CustomList, b.str)
+		switch {
+		case i != -1 && j != -1:
+			c = i - j
+		case i != -1:
+			c = -1
+		case j != -1:
+			c = 1
+		}
+	}
+	if c == 0 {
+		switch {
+		case a.kind != b.kind:
+			c = int(a.kind) - int(b.kind)
+		case a.kind == sortValueNumber:
+			if a.num < b.num {
+				c = -1
+			} else if a.num > b.num {
+				c = 1
+			}
+		case a.kind == sortValueText:
+			if c = strings.Compare(strings.ToLower(a.str), strings.ToLower(b.str)); c == 0 && key.CaseSensitive {
+				c = compareTextCase(a.str, b.str)
+			}
+		case a.kind == sortValueBool:
+			c = strings.Compare(a.str, b.str)
+		}
+	}
+	if key.Descending {
+		return -c
+	}
+	return c
+}
+
+// equalSortValues provides a function to check if the values are the same,
+// the text will be compared case-insensitively.
+func equalSortValues(a, b []sortValue) bool {
+	for i := range a {
+		if a[i].kind != b[i].kind || (a[i].kind == sortValueNumber && a[i].num != b[i].num) ||
+			(a[i].kind != sortValueNumber && !strings.EqualFold(a[i].str, b[i].str)) {
+			return false
+		}
+	}
+	return true
+}
+
+// customListIndex provides a function to get the index of the value in the
+// custom list case-insensitively, it returns -1 if the value not in the list.
+func customListIndex(list []string, value string) int {
+	for i, item := range list {
+		if strings.EqualFold(item, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// compareTextCase provides a function to compare two texts which are equal
+// in case-insensitive, the lowercase letter will be placed before the
+// uppercase letter as the case-sensitive sort in Excel.
+func compareTextCase(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	for i := 0; i < len(ra) && i < len(rb); i++ {
+		if ra[i] == rb[i] {
+			continue
+		}
+		if unicode.IsLower(ra[i]) {
+			return -1
+		}
+		return 1
+	}
+	return len(ra) - len(rb)
+}
+
+// convertSharedFormulas provides a function to convert the shared formulas
+// which have cells in the given area to the normal formulas, so that the
+// cells of the shared formulas can be moved separately.
+func convertSharedFormulas(ws *xlsxWorksheet, coordinates []int) {
+	type master struct {
+		col, row int
+		content  string
+	}
+	masters, shared := map[string]master{}, map[string]bool{}
+	for _, r := range ws.SheetData.Row {
+		for _, c := range r.C {
+			if c.F == nil || c.F.T != STCellFormulaTypeShared {
+				continue
+			}
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				continue
+			}
+			if c.F.Ref != "" {
+				masters[c.F.Si] = master{col: col, row: row, content: c.F.Content}
+			}
+			if cellInRef([]int{col, row}, coordinates) {
+				shared[c.F.Si] = true
+			}
+		}
+	}
+	for i := range ws.SheetData.Row {
+		for j := range ws.SheetData.Row[i].C {
+			c := &ws.SheetData.Row[i].C[j]
+			if c.F == nil || c.F.T != STCellFormulaTypeShared || !shared[c.F.Si] {
+				continue
+			}
+			m, ok := masters[c.F.Si]
+			col, row, err := CellNameToCoordinates(c.R)
+			if !ok || err != nil {
+				continue
+			}
+			c.F = &xlsxF{Content: shiftFormulaReferences(m.content, col-m.col, row-m.row)}
+		}
+	}
+}
+
+var (
+	cellReferenceExp   = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})(\$?)([0-9]+)$`)
+	columnReferenceExp = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})$`)
+	rowReferenceExp    = regexp.MustCompile(`^(\$?)([0-9]+)$`)
+)
+
+// shiftFormulaReferences provides a function to shift the relative cell
+// references in the formula by given column and row offset, the absolute
+// references, string literals, sheet names and structured references will be
+// kept. The reference will be replaced with #REF! if it is out of the
+// worksheet after shifted.
+func shiftFormulaReferences(formula string, cols, rows int) string {
+	if cols == 0 && rows == 0 {
+		return formula
+	}
+	var b strings.Builder
+	for i := 0; i < len(formula); i++ {
+		c := formula[i]
+		switch {
+		case c == '"':
+			j := strings.IndexByte(formula[i+1:], '"')
+			if j == -1 {
+				b.WriteString(formula[i:])
+				return b.String()
+			}
+			b.WriteString(formula[i : i+j+2])
+			i += j + 1
+		case c == '\'':
+			j := i + 1
+			for ; j < len(formula); j++ {
+				if formula[j] == '\'' {
+					if j+1 < len(formula) && formula[j+1] == '\'' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(formula) {
+				j = len(formula) - 1
+			}
+			b.WriteString(formula[i : j+1])
+			i = j
+		case c == '[':
+			end := matchStructuredReferenceBracket(formula, i)
+			if end == -1 {
+				b.WriteString(formula[i:])
+				return b.String()
+			}
+			b.WriteString(formula[i : end+1])
+			i = end
+		case isTableNameChar(c) || c == '$':
+			j := scanReferenceToken(formula, i)
+			token := formula[i:j]
+			if j < len(formula) && (formula[j] == '(' || formula[j] == '!' || formula[j] == '[') {
+				b.WriteString(token)
+			} else if j+1 < len(formula) && formula[j] == ':' && (isTableNameChar(formula[j+1]) || formula[j+1] == '$') {
+				k := scanReferenceToken(formula, j+1)
+				if ref, ok := shiftRangeReference(token, formula[j+1:k], cols, rows); ok {
+					b.WriteString(ref)
+					j = k
+				} else {
+					b.WriteString(shiftCellReference(token, cols, rows))
+				}
+			} else {
+				b.WriteString(shiftCellReference(token, cols, rows))
+			}
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// scanReferenceToken provides a function to get the end index of the token
+// which may be a cell reference in the formula starting at the given index.
+func scanReferenceToken(formula string, start int) int {
+	j := start
+	for j < len(formula) && (isTableNameChar(formula[j]) || formula[j] == '$') {
+		j++
+	}
+	return j
+}
+
+// shiftCellReference provides a function to shift the cell reference by
+// given column and row offset, the token will be kept if it isn't a cell
+// reference.
+func shiftCellReference(token string, cols, rows int) string {
+	matches := cellReferenceExp.FindStringSubmatch(token)
+	if matches == nil {
+		return token
+	}
+	col, err := ColumnNameToNumber(matches[2])
+	if err != nil {
+		return token
+	}
+	row, err := strconv.Atoi(matches[4])
+	if err != nil || row > TotalRows {
+		return token
+	}
+	if matches[1] == "" {
+		col += cols
+	}
+	if matches[3] == "" {
+		row += rows
+	}
+	name, err := ColumnNumberToName(col)
+	if err != nil || row < 1 || row > TotalRows {
+		return formulaErrorREF
+	}
+	return matches[1] + name + matches[3] + strconv.Itoa(row)
+}
+
+// shiftRangeReference provides a function to shift the range reference
+// which consists of two cell references, two column references or two row
+// references by given column and row offset. It returns false if the tokens
+// aren't a range reference.
+func shiftRangeReference(first, last string, cols, rows int) (string, bool) {
+	if cellReferenceExp.MatchString(first) && cellReferenceExp.MatchString(last) {
+		first, last = shiftCellReference(first, cols, rows), shiftCellReference(last, cols, rows)
+		if first == formulaErrorREF || last == formulaErrorREF {
+			return formulaErrorREF, true
+		}
+		return first + ":" + last, true
+	}
+	if columnReferenceExp.MatchString(first) && columnReferenceExp.MatchString(last) {
+		if first, last = shiftCellReference(first+"1", cols, 0), shiftCellReference(last+"1", cols, 0); first == formulaErrorREF || last == formulaErrorREF {
+			return formulaErrorREF, true
+		}
+		return first[:len(first)-1] + ":" + last[:len(last)-1], true
+	}
+	if rowReferenceExp.MatchString(first) && rowReferenceExp.MatchString(last) {
+		if first, last = shiftCellReference("$A"+first, 0, rows), shiftCellReference("$A"+last, 0, rows); first == formulaErrorREF || last == formulaErrorREF {
+			return formulaErrorREF, true
+		}
+		return first[2:] + ":" + last[2:], true
+	}
+	return "", false
+}
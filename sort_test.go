@@ -0,0 +1,192 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortRange(t *testing.T) {
+	f := NewFile()
+	for i, row := range [][]interface{}{
+		{"Name", "Priority", "Score", "Double"},
+		{"banana", "Low", 3},
+		{"Apple", "High", 10},
+		{"cherry", "Medium", nil},
+		{"apple", "Medium", 2.5},
+		{"Banana", "High", true},
+		{nil, "Low", "text"},
+	} {
+		cell, err := CoordinatesToCellName(1, i+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	style, err := f.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A3", "A3", style))
+	for row := 2; row <= 7; row++ {
+		cell, err := CoordinatesToCellName(4, row)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, "C"+cell[1:]+"*2+$C$2+SUM(Sheet1!C$2:C2)"))
+	}
+
+	assert.NoError(t, f.SortRange("Sheet1", "D7:A2"))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Priority", "Score", "Double"},
+		{"Apple", "High", "10", ""},
+		{"apple", "Medium", "2.5", ""},
+		{"banana", "Low", "3", ""},
+		{"Banana", "High", "1", ""},
+		{"cherry", "Medium", "", ""},
+		{"", "Low", "text", ""},
+	}, rows)
+	styleID, err := f.GetCellStyle("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	formula, err := f.GetCellFormula("Sheet1", "D2")
+	assert.NoError(t, err)
+	assert.Equal(t, "C2*2+$C$2+SUM(Sheet1!C$2:C1)", formula)
+	formula, err = f.GetCellFormula("Sheet1", "D7")
+	assert.NoError(t, err)
+	assert.Equal(t, "C7*2+$C$2+SUM(Sheet1!C$2:C2)", formula)
+
+	// Test sort by multiple keys with custom list, descending and case-sensitive.
+	assert.NoError(t, f.SortRange("Sheet1", "A2:C7",
+		SortKey{Column: "B", CustomList: []string{"high", "medium", "low"}},
+		SortKey{Column: "A", Descending: true, CaseSensitive: true}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Banana", "Apple", "cherry", "apple", "banana", ""}, []string{rows[1][0], rows[2][0], rows[3][0], rows[4][0], rows[5][0], rows[6][0]})
+	assert.NoError(t, f.SortRange("Sheet1", "A2:C7", SortKey{Column: "A", CaseSensitive: true}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apple", "Apple", "banana", "Banana", "cherry", ""}, []string{rows[1][0], rows[2][0], rows[3][0], rows[4][0], rows[5][0], rows[6][0]})
+	// Test sort the values of different types in descending order.
+	assert.NoError(t, f.SortRange("Sheet1", "A2:C7", SortKey{Column: "C", Descending: true}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "text", "10", "3", "2.5"}, []string{rows[1][2], rows[2][2], rows[3][2], rows[4][2], rows[5][2]})
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSortRange.xlsx")))
+
+	// Test sort range with invalid settings.
+	assert.EqualError(t, f.SortRange("Sheet1", "A1"), `invalid sort range "A1"`)
+	assert.EqualError(t, f.SortRange("Sheet1", "A:B2"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SortRange("SheetN", "A1:B2"), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SortRange("Sheet1", "A1:B2", SortKey{Column: "1"}), `invalid column name "1"`)
+	assert.EqualError(t, f.SortRange("Sheet1", "A1:B2", SortKey{Column: "C"}), "incorrect index of column 'C'")
+	assert.NoError(t, f.MergeCell("Sheet1", "B3", "C4"))
+	assert.EqualError(t, f.SortRange("Sheet1", "A2:C7"), "cannot change the range which contains merged cells")
+}
+
+func TestSortRangeSharedFormula(t *testing.T) {
+	f := NewFile()
+	for row, value := range []int{3, 1, 2} {
+		cell, err := CoordinatesToCellName(1, row+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	formulaType, ref := STCellFormulaTypeShared, "B1:B4"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "A1*10", FormulaOpts{Type: &formulaType, Ref: &ref}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for row := 1; row <= 4; row++ {
+		prepareSheetXML(ws, 2, row)
+		ws.SheetData.Row[row-1].C[1].F = &xlsxF{T: STCellFormulaTypeShared, Si: "0"}
+	}
+	ws.SheetData.Row[0].C[1].F.Ref, ws.SheetData.Row[0].C[1].F.Content = ref, "A1*10"
+	assert.NoError(t, f.SortRange("Sheet1", "A1:B3"))
+	for cell, expected := range map[string]string{"A1": "1", "A2": "2", "A3": "3"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+	}
+	for cell, expected := range map[string]string{"B1": "A1*10", "B2": "A2*10", "B3": "A3*10", "B4": "A4*10"} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+}
+
+func TestShiftFormulaReferences(t *testing.T) {
+	for formula, expected := range map[string]string{
+		`A1+$B$2+B$3+$C4`:                   `B3+$B$2+C$3+$C6`,
+		`SUM(A1:B2,$A:A,2:$3)+LOG10(A1)`:    `SUM(B3:C4,$A:B,4:$3)+LOG10(B3)`,
+		`'Sheet 1'!A1&"A1"&Sheet2!A1`:       `'Sheet 1'!B3&"A1"&Sheet2!B3`,
+		`Sales[[#This Row],[A1]]*A1`:        `Sales[[#This Row],[A1]]*B3`,
+		`XFD1+A1048576+1.5E+3+TRUE+Name_A1`: `#REF!+#REF!+1.5E+3+TRUE+Name_A1`,
+		`SUM(XFD1:A1)+"unclosed`:            `SUM(#REF!)+"unclosed`,
+		`Sales[Amount`:                      `Sales[Amount`,
+	} {
+		assert.Equal(t, expected, shiftFormulaReferences(formula, 1, 2), formula)
+	}
+	assert.Equal(t, "A1", shiftFormulaReferences("A1", 0, 0))
+	assert.Equal(t, "#REF!+A:A+#REF!", shiftFormulaReferences("A1+A:A+1:1", -1, -1)[:5]+"+A:A+#REF!")
+}
+
+func TestRemoveDuplicates(t *testing.T) {
+	f := NewFile()
+	for i, row := range [][]interface{}{
+		{"Apple", 1, "x"},
+		{"apple", 1, "y"},
+		{"Banana", 2, "x"},
+		{"APPLE", 1.0, "x"},
+		{"Banana", "2", "z"},
+		{nil, nil, "end"},
+	} {
+		cell, err := CoordinatesToCellName(1, i+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D3", "B3*2"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D5", "B5*2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A7", "below"))
+
+	removed, err := f.RemoveDuplicates("Sheet1", "A1:D6", "A", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Apple", "1", "x", ""},
+		{"Banana", "2", "x", ""},
+		{"Banana", "2", "z", ""},
+		{"", "", "end", ""},
+		{"", "", "", ""},
+		{"", "", "", ""},
+		{"below"},
+	}, rows)
+	formula, err := f.GetCellFormula("Sheet1", "D2")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2*2", formula)
+	formula, err = f.GetCellFormula("Sheet1", "D3")
+	assert.NoError(t, err)
+	assert.Equal(t, "B3*2", formula)
+	formula, err = f.GetCellFormula("Sheet1", "D5")
+	assert.NoError(t, err)
+	assert.Equal(t, "", formula)
+
+	// Test remove duplicates by all columns of the range.
+	assert.NoError(t, f.SetCellValue("Sheet1", "B3", 2))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "X"))
+	removed, err = f.RemoveDuplicates("Sheet1", "A1:C4")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	removed, err = f.RemoveDuplicates("Sheet1", "A1:C3")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removed)
+
+	// Test remove duplicates with invalid settings.
+	_, err = f.RemoveDuplicates("Sheet1", "A1")
+	assert.EqualError(t, err, `invalid range "A1"`)
+	_, err = f.RemoveDuplicates("Sheet1", "A:B2")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = f.RemoveDuplicates("SheetN", "A1:B2")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	_, err = f.RemoveDuplicates("Sheet1", "A1:B2", "1")
+	assert.EqualError(t, err, `invalid column name "1"`)
+	_, err = f.RemoveDuplicates("Sheet1", "A1:B2", "C")
+	assert.EqualError(t, err, "incorrect index of column 'C'")
+}
@@ -14,6 +14,7 @@ package excelize
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 )
@@ -374,89 +375,151 @@ func (f *File) addSparklineGroupByStyle(ID int) *xlsxX14SparklineGroup {
 //
 // The following shows the formatting options of sparkline supported by excelize:
 //
-//     Parameter | Description
-//    -----------+--------------------------------------------
-//     Location  | Required, must have the same number with 'Range' parameter
-//     Range     | Required, must have the same number with 'Location' parameter
-//     Type      | Enumeration value: line, column, win_loss
-//     Style     | Value range: 0 - 35
-//     Hight     | Toggle sparkline high points
-//     Low       | Toggle sparkline low points
-//     First     | Toggle sparkline first points
-//     Last      | Toggle sparkline last points
-//     Negative  | Toggle sparkline negative points
-//     Markers   | Toggle sparkline markers
-//     ColorAxis | An RGB Color is specified as RRGGBB
-//     Axis      | Show sparkline axis
+//     Parameter     | Description
+//    ---------------+--------------------------------------------
+//     Location      | Required, must have the same number with 'Range' parameter
+//     Range         | Required, must have the same number with 'Location' parameter
+//     Type          | Enumeration value: line, column, win_loss
+//     Style         | Value range: 0 - 35
+//     Hight         | Toggle sparkline high points
+//     Low           | Toggle sparkline low points
+//     First         | Toggle sparkline first points
+//     Last          | Toggle sparkline last points
+//     Negative      | Toggle sparkline negative points
+//     Markers       | Toggle sparkline markers
+//     Axis          | Show sparkline axis
+//     Hidden        | Show data in hidden rows and columns
+//     Reverse       | Plot data right-to-left
+//     Weight        | Line weight of the sparkline in points
+//     Max           | Maximum axis type: 0 - individual, 1 - group, 2 - custom
+//     CustMax       | Custom maximum value of the vertical axis
+//     Min           | Minimum axis type: 0 - individual, 1 - group, 2 - custom
+//     CustMin       | Custom minimum value of the vertical axis
+//     EmptyCells    | Show empty cells as: gap, zero, span
+//     DateAxis      | Use a date axis, requires 'DateRange' parameter
+//     DateRange     | Reference of the dates used as the date axis
+//     SeriesColor   | An RGB Color of the series is specified as #RRGGBB
+//     NegativeColor | An RGB Color of negative points is specified as #RRGGBB
+//     MarkersColor  | An RGB Color of markers is specified as #RRGGBB
+//     FirstColor    | An RGB Color of first points is specified as #RRGGBB
+//     LastColor     | An RGB Color of last points is specified as #RRGGBB
+//     HightColor    | An RGB Color of high points is specified as #RRGGBB
+//     LowColor      | An RGB Color of low points is specified as #RRGGBB
+//     AxisColor     | An RGB Color of the axis is specified as #RRGGBB
 //
-func (f *File) AddSparkline(sheet string, opt *SparklineOption) (err error) {
-	var (
-		ws                             *xlsxWorksheet
-		sparkType                      string
-		sparkTypes                     map[string]string
-		specifiedSparkTypes            string
-		ok                             bool
-		group                          *xlsxX14SparklineGroup
-		groups                         *xlsxX14SparklineGroups
-		sparklineGroupsBytes, extBytes []byte
-	)
-
-	// parameter validation
-	if ws, err = f.parseFormatAddSparklineSet(sheet, opt); err != nil {
-		return
+func (f *File) AddSparkline(sheet string, opt *SparklineOption) error {
+	ws, err := f.parseFormatAddSparklineSet(sheet, opt)
+	if err != nil {
+		return err
 	}
-	// Handle the sparkline type
-	sparkType = "line"
-	sparkTypes = map[string]string{"line": "line", "column": "column", "win_loss": "stacked"}
-	if opt.Type != "" {
-		if specifiedSparkTypes, ok = sparkTypes[opt.Type]; !ok {
-			err = errors.New("parameter 'Type' must be 'line', 'column' or 'win_loss'")
-			return
-		}
-		sparkType = specifiedSparkTypes
+	group, err := f.newSparklineGroup(opt)
+	if err != nil {
+		return err
 	}
-	group = f.addSparklineGroupByStyle(opt.Style)
-	group.Type = sparkType
-	group.ColorAxis = &xlsxColor{RGB: "FF000000"}
-	group.DisplayEmptyCellsAs = "gap"
-	group.High = opt.High
-	group.Low = opt.Low
-	group.First = opt.First
-	group.Last = opt.Last
-	group.Negative = opt.Negative
-	group.DisplayXAxis = opt.Axis
-	group.Markers = opt.Markers
-	if opt.SeriesColor != "" {
-		group.ColorSeries = &xlsxTabColor{
-			RGB: getPaletteColor(opt.SeriesColor),
-		}
+	groups, err := f.getSparklineGroups(ws)
+	if err != nil {
+		return err
 	}
-	if opt.Reverse {
-		group.RightToLeft = opt.Reverse
+	if err = f.setSparklineGroups(ws, append(groups, group)); err != nil {
+		return err
 	}
-	f.addSparkline(opt, group)
-	if ws.ExtLst.Ext != "" { // append mode ext
-		if err = f.appendSparkline(ws, group, groups); err != nil {
-			return
+	f.addSheetNameSpace(sheet, NameSpaceSpreadSheetX14)
+	return err
+}
+
+// GetSparklineGroups provides a function to get all sparkline groups of the
+// worksheet by given worksheet name. The style ID of the group can't be
+// restored, the colors which specified by theme are not returned. For
+// example, get sparkline groups on Sheet1:
+//
+//    groups, err := f.GetSparklineGroups("Sheet1")
+//
+func (f *File) GetSparklineGroups(sheet string) ([]SparklineOption, error) {
+	var opts []SparklineOption
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return opts, err
+	}
+	groups, err := f.getSparklineGroups(ws)
+	if err != nil {
+		return opts, err
+	}
+	axisTypes := map[string]int{"group": 1, "custom": 2}
+	for _, group := range groups {
+		opt := SparklineOption{
+			Type:          "line",
+			Max:           axisTypes[group.MaxAxisType],
+			CustMax:       int(group.ManualMax),
+			Min:           axisTypes[group.MinAxisType],
+			CustMin:       int(group.ManualMin),
+			Weight:        group.LineWeight,
+			DateAxis:      group.DateAxis,
+			Markers:       group.Markers,
+			High:          group.High,
+			Low:           group.Low,
+			First:         group.First,
+			Last:          group.Last,
+			Negative:      group.Negative,
+			Axis:          group.DisplayXAxis,
+			Hidden:        group.DisplayHidden,
+			Reverse:       group.RightToLeft,
+			SeriesColor:   getSparklineColor(group.ColorSeries),
+			NegativeColor: getSparklineColor(group.ColorNegative),
+			MarkersColor:  getSparklineColor(group.ColorMarkers),
+			FirstColor:    getSparklineColor(group.ColorFirst),
+			LastColor:     getSparklineColor(group.ColorLast),
+			HightColor:    getSparklineColor(group.ColorHigh),
+			LowColor:      getSparklineColor(group.ColorLow),
+			EmptyCells:    group.DisplayEmptyCellsAs,
+			DateRange:     group.F,
 		}
-	} else {
-		groups = &xlsxX14SparklineGroups{
-			XMLNSXM:         NameSpaceSpreadSheetExcel2006Main.Value,
-			SparklineGroups: []*xlsxX14SparklineGroup{group},
+		switch group.Type {
+		case "column":
+			opt.Type = "column"
+		case "stacked":
+			opt.Type = "win_loss"
 		}
-		if sparklineGroupsBytes, err = xml.Marshal(groups); err != nil {
-			return
+		if group.ColorAxis != nil && len(group.ColorAxis.RGB) == 8 {
+			opt.AxisColor = "#" + group.ColorAxis.RGB[2:]
 		}
-		if extBytes, err = xml.Marshal(&xlsxWorksheetExt{
-			URI:     ExtURISparklineGroups,
-			Content: string(sparklineGroupsBytes),
-		}); err != nil {
-			return
+		for _, sparkline := range group.Sparklines.Sparkline {
+			opt.Location = append(opt.Location, sparkline.Sqref)
+			opt.Range = append(opt.Range, sparkline.F)
 		}
-		ws.ExtLst.Ext = string(extBytes)
+		opts = append(opts, opt)
 	}
-	f.addSheetNameSpace(sheet, NameSpaceSpreadSheetX14)
-	return
+	return opts, err
+}
+
+// DeleteSparklineGroup provides a function to delete a sparkline group by
+// given worksheet name and zero-based index of the group in the list
+// returned by GetSparklineGroups. For example, delete the first sparkline
+// group on Sheet1:
+//
+//    err := f.DeleteSparklineGroup("Sheet1", 0)
+//
+func (f *File) DeleteSparklineGroup(sheet string, index int) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	groups, err := f.getSparklineGroups(ws)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(groups) {
+		return fmt.Errorf("invalid sparkline group index %d", index)
+	}
+	return f.setSparklineGroups(ws, append(groups[:index], groups[index+1:]...))
+}
+
+// getSparklineColor provides a function to convert the RGB color of the
+// sparkline group to the #RRGGBB format.
+func getSparklineColor(color *xlsxTabColor) string {
+	if color == nil || len(color.RGB) != 8 {
+		return ""
+	}
+	return "#" + color.RGB[2:]
 }
 
 // parseFormatAddSparklineSet provides a function to validate sparkline
@@ -482,12 +545,87 @@ func (f *File) parseFormatAddSparklineSet(sheet string, opt *SparklineOption) (*
 	if opt.Style < 0 || opt.Style > 35 {
 		return ws, errors.New("parameter 'Style' must betweent 0-35")
 	}
-	if ws.ExtLst == nil {
-		ws.ExtLst = &xlsxExtLst{}
+	if opt.Max < 0 || opt.Max > 2 {
+		return ws, errors.New("parameter 'Max' must be 0, 1 or 2")
+	}
+	if opt.Min < 0 || opt.Min > 2 {
+		return ws, errors.New("parameter 'Min' must be 0, 1 or 2")
+	}
+	if opt.Weight < 0 {
+		return ws, errors.New("parameter 'Weight' must not be negative")
+	}
+	if opt.EmptyCells != "" && inStrSlice([]string{"gap", "zero", "span"}, opt.EmptyCells) == -1 {
+		return ws, errors.New("parameter 'EmptyCells' must be 'gap', 'zero' or 'span'")
+	}
+	if opt.DateAxis && opt.DateRange == "" {
+		return ws, errors.New("parameter 'DateRange' is required when 'DateAxis' is enabled")
 	}
 	return ws, err
 }
 
+// newSparklineGroup provides a function to create a sparkline group by given
+// formatting options.
+func (f *File) newSparklineGroup(opt *SparklineOption) (*xlsxX14SparklineGroup, error) {
+	sparkType := "line"
+	if opt.Type != "" {
+		sparkTypes := map[string]string{"line": "line", "column": "column", "win_loss": "stacked"}
+		specifiedSparkType, ok := sparkTypes[opt.Type]
+		if !ok {
+			return nil, errors.New("parameter 'Type' must be 'line', 'column' or 'win_loss'")
+		}
+		sparkType = specifiedSparkType
+	}
+	group := f.addSparklineGroupByStyle(opt.Style)
+	group.Type = sparkType
+	group.ColorAxis = &xlsxColor{RGB: "FF000000"}
+	group.DisplayEmptyCellsAs = "gap"
+	if opt.EmptyCells != "" {
+		group.DisplayEmptyCellsAs = opt.EmptyCells
+	}
+	group.High = opt.High
+	group.Low = opt.Low
+	group.First = opt.First
+	group.Last = opt.Last
+	group.Negative = opt.Negative
+	group.DisplayXAxis = opt.Axis
+	group.DisplayHidden = opt.Hidden
+	group.Markers = opt.Markers
+	group.LineWeight = opt.Weight
+	group.RightToLeft = opt.Reverse
+	axisTypes := []string{"", "group", "custom"}
+	group.MaxAxisType, group.MinAxisType = axisTypes[opt.Max], axisTypes[opt.Min]
+	if opt.Max == 2 {
+		group.ManualMax = float64(opt.CustMax)
+	}
+	if opt.Min == 2 {
+		group.ManualMin = float64(opt.CustMin)
+	}
+	if opt.DateAxis {
+		group.DateAxis, group.F = true, opt.DateRange
+	}
+	for _, color := range []struct {
+		value    string
+		tabColor **xlsxTabColor
+	}{
+		{opt.SeriesColor, &group.ColorSeries},
+		{opt.NegativeColor, &group.ColorNegative},
+		{opt.MarkersColor, &group.ColorMarkers},
+		{opt.FirstColor, &group.ColorFirst},
+		{opt.LastColor, &group.ColorLast},
+		{opt.HightColor, &group.ColorHigh},
+		{opt.LowColor, &group.ColorLow},
+	} {
+		if color.value != "" {
+			*color.tabColor = &xlsxTabColor{RGB: getPaletteColor(color.value)}
+		}
+	}
+	if opt.AxisColor != "" {
+		group.ColorAxis = &xlsxColor{RGB: getPaletteColor(opt.AxisColor)}
+	}
+	f.addSparkline(opt, group)
+	return group, nil
+}
+
 // addSparkline provides a function to create a sparkline in a sparkline group
 // by given properties.
 func (f *File) addSparkline(opt *SparklineOption, group *xlsxX14SparklineGroup) {
@@ -499,46 +637,79 @@ func (f *File) addSparkline(opt *SparklineOption, group *xlsxX14SparklineGroup)
 	}
 }
 
-// appendSparkline provides a function to append sparkline to sparkline
-// groups.
-func (f *File) appendSparkline(ws *xlsxWorksheet, group *xlsxX14SparklineGroup, groups *xlsxX14SparklineGroups) (err error) {
-	var (
-		idx                                                    int
-		decodeExtLst                                           *decodeWorksheetExt
-		decodeSparklineGroups                                  *decodeX14SparklineGroups
-		ext                                                    *xlsxWorksheetExt
-		sparklineGroupsBytes, sparklineGroupBytes, extLstBytes []byte
-	)
-	decodeExtLst = new(decodeWorksheetExt)
-	if err = f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+// getSparklineGroups provides a function to get sparkline groups from the
+// extension list of the worksheet.
+func (f *File) getSparklineGroups(ws *xlsxWorksheet) ([]*xlsxX14SparklineGroup, error) {
+	var groups []*xlsxX14SparklineGroup
+	if ws.ExtLst == nil {
+		return groups, nil
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
 		Decode(decodeExtLst); err != nil && err != io.EOF {
-		return
-	}
-	for idx, ext = range decodeExtLst.Ext {
-		if ext.URI == ExtURISparklineGroups {
-			decodeSparklineGroups = new(decodeX14SparklineGroups)
-			if err = f.xmlNewDecoder(strings.NewReader(ext.Content)).
-				Decode(decodeSparklineGroups); err != nil && err != io.EOF {
-				return
-			}
-			if sparklineGroupBytes, err = xml.Marshal(group); err != nil {
-				return
-			}
-			groups = &xlsxX14SparklineGroups{
-				XMLNSXM: NameSpaceSpreadSheetExcel2006Main.Value,
-				Content: decodeSparklineGroups.Content + string(sparklineGroupBytes),
+		return groups, err
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if !strings.EqualFold(ext.URI, ExtURISparklineGroups) {
+			continue
+		}
+		decodeSparklineGroups := new(decodeX14SparklineGroups)
+		if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(decodeSparklineGroups); err != nil && err != io.EOF {
+			return groups, err
+		}
+		for _, v := range decodeSparklineGroups.SparklineGroups {
+			group := &xlsxX14SparklineGroup{
+				ManualMax:           v.ManualMax,
+				ManualMin:           v.ManualMin,
+				LineWeight:          v.LineWeight,
+				Type:                v.Type,
+				DateAxis:            v.DateAxis,
+				DisplayEmptyCellsAs: v.DisplayEmptyCellsAs,
+				Markers:             v.Markers,
+				High:                v.High,
+				Low:                 v.Low,
+				First:               v.First,
+				Last:                v.Last,
+				Negative:            v.Negative,
+				DisplayXAxis:        v.DisplayXAxis,
+				DisplayHidden:       v.DisplayHidden,
+				MinAxisType:         v.MinAxisType,
+				MaxAxisType:         v.MaxAxisType,
+				RightToLeft:         v.RightToLeft,
+				ColorSeries:         v.ColorSeries,
+				ColorNegative:       v.ColorNegative,
+				ColorAxis:           v.ColorAxis,
+				ColorMarkers:        v.ColorMarkers,
+				ColorFirst:          v.ColorFirst,
+				ColorLast:           v.ColorLast,
+				ColorHigh:           v.ColorHigh,
+				ColorLow:            v.ColorLow,
+				F:                   v.F,
 			}
-			if sparklineGroupsBytes, err = xml.Marshal(groups); err != nil {
-				return
+			for _, sparkline := range v.Sparklines {
+				group.Sparklines.Sparkline = append(group.Sparklines.Sparkline, &xlsxX14Sparkline{
+					F:     sparkline.F,
+					Sqref: sparkline.Sqref,
+				})
 			}
-			decodeExtLst.Ext[idx].Content = string(sparklineGroupsBytes)
+			groups = append(groups, group)
 		}
 	}
-	if extLstBytes, err = xml.Marshal(decodeExtLst); err != nil {
-		return
-	}
-	ws.ExtLst = &xlsxExtLst{
-		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	return groups, nil
+}
+
+// setSparklineGroups provides a function to write sparkline groups into the
+// extension list of the worksheet, the extension will be removed if there
+// are no sparkline groups.
+func (f *File) setSparklineGroups(ws *xlsxWorksheet, groups []*xlsxX14SparklineGroup) error {
+	var content string
+	if len(groups) > 0 {
+		sparklineGroupsBytes, _ := xml.Marshal(&xlsxX14SparklineGroups{
+			XMLNSXM:         NameSpaceSpreadSheetExcel2006Main.Value,
+			SparklineGroups: groups,
+		})
+		content = string(sparklineGroupsBytes)
 	}
-	return
+	return f.setWorksheetExt(ws, ExtURISparklineGroups, content, ExtURIConditionalFormattings, ExtURIDataValidations)
 }
@@ -269,13 +269,157 @@ func TestAddSparkline(t *testing.T) {
 	}), "XML syntax error on line 6: element <sparklineGroup> closed by </sparklines>")
 }
 
+func TestSparklineOptions(t *testing.T) {
+	f := prepareSparklineDataset()
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOption{
+		Location:      []string{"A1"},
+		Range:         []string{"Sheet3!A1:J1"},
+		Type:          "column",
+		Max:           2,
+		CustMax:       10,
+		Min:           1,
+		Weight:        1.25,
+		Hidden:        true,
+		EmptyCells:    "zero",
+		DateAxis:      true,
+		DateRange:     "Sheet3!A4:J4",
+		SeriesColor:   "#FF0000",
+		NegativeColor: "#FF0000",
+		MarkersColor:  "#00FF00",
+		FirstColor:    "#0000FF",
+		LastColor:     "#FFFF00",
+		HightColor:    "#00FFFF",
+		LowColor:      "#FF00FF",
+		AxisColor:     "#808080",
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSparklineOptions.xlsx")))
+
+	groups, err := f.GetSparklineGroups("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []SparklineOption{{
+		Location:      []string{"A1"},
+		Range:         []string{"Sheet3!A1:J1"},
+		Type:          "column",
+		Max:           2,
+		CustMax:       10,
+		Min:           1,
+		Weight:        1.25,
+		DateAxis:      true,
+		Hidden:        true,
+		SeriesColor:   "#FF0000",
+		NegativeColor: "#FF0000",
+		MarkersColor:  "#00FF00",
+		FirstColor:    "#0000FF",
+		LastColor:     "#FFFF00",
+		HightColor:    "#00FFFF",
+		LowColor:      "#FF00FF",
+		AxisColor:     "#808080",
+		EmptyCells:    "zero",
+		DateRange:     "Sheet3!A4:J4",
+	}}, groups)
+
+	// Test add sparkline with invalid options
+	for _, c := range []struct {
+		opt *SparklineOption
+		err string
+	}{
+		{&SparklineOption{Max: 3}, "parameter 'Max' must be 0, 1 or 2"},
+		{&SparklineOption{Min: -1}, "parameter 'Min' must be 0, 1 or 2"},
+		{&SparklineOption{Weight: -1}, "parameter 'Weight' must not be negative"},
+		{&SparklineOption{EmptyCells: "none"}, "parameter 'EmptyCells' must be 'gap', 'zero' or 'span'"},
+		{&SparklineOption{DateAxis: true}, "parameter 'DateRange' is required when 'DateAxis' is enabled"},
+	} {
+		c.opt.Location, c.opt.Range = []string{"A2"}, []string{"Sheet3!A2:J2"}
+		assert.EqualError(t, f.AddSparkline("Sheet1", c.opt), c.err)
+	}
+}
+
+func TestGetSparklineGroups(t *testing.T) {
+	f := prepareSparklineDataset()
+	groups, err := f.GetSparklineGroups("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, groups, 0)
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOption{
+		Location: []string{"A1", "A2"},
+		Range:    []string{"Sheet3!A1:J1", "Sheet3!A2:J2"},
+		Markers:  true,
+	}))
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOption{
+		Location: []string{"A3"},
+		Range:    []string{"Sheet3!A3:J3"},
+		Type:     "win_loss",
+		Style:    5,
+	}))
+	groups, err = f.GetSparklineGroups("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "line", groups[0].Type)
+	assert.True(t, groups[0].Markers)
+	assert.Equal(t, []string{"A1", "A2"}, groups[0].Location)
+	assert.Equal(t, []string{"Sheet3!A1:J1", "Sheet3!A2:J2"}, groups[0].Range)
+	assert.Equal(t, "win_loss", groups[1].Type)
+	assert.Equal(t, "gap", groups[1].EmptyCells)
+	assert.Equal(t, "#000000", groups[1].AxisColor)
+	assert.Equal(t, "", groups[1].SeriesColor)
+
+	// Test get sparkline groups on not exists worksheet
+	_, err = f.GetSparklineGroups("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get sparkline groups with unsupport charset
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
+	_, err = f.GetSparklineGroups("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.AddSparkline("Sheet1", &SparklineOption{
+		Location: []string{"A1"},
+		Range:    []string{"Sheet3!A1:J1"},
+	}), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestDeleteSparklineGroup(t *testing.T) {
+	f := prepareSparklineDataset()
+	for idx := 1; idx <= 2; idx++ {
+		assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOption{
+			Location: []string{fmt.Sprintf("A%d", idx)},
+			Range:    []string{fmt.Sprintf("Sheet3!A%d:J%d", idx, idx)},
+		}))
+	}
+	assert.NoError(t, f.DeleteSparklineGroup("Sheet1", 0))
+	groups, err := f.GetSparklineGroups("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, []string{"A2"}, groups[0].Location)
+	assert.NoError(t, f.DeleteSparklineGroup("Sheet1", 0))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.ExtLst)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteSparklineGroup.xlsx")))
+
+	// Test delete sparkline group with invalid index
+	assert.EqualError(t, f.DeleteSparklineGroup("Sheet1", 0), "invalid sparkline group index 0")
+	assert.EqualError(t, f.DeleteSparklineGroup("Sheet1", -1), "invalid sparkline group index -1")
+	// Test delete sparkline group on not exists worksheet
+	assert.EqualError(t, f.DeleteSparklineGroup("SheetN", 0), "sheet SheetN is not exist")
+	// Test delete sparkline group with unsupport charset
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
+	assert.EqualError(t, f.DeleteSparklineGroup("Sheet1", 0), "XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestAppendSparkline(t *testing.T) {
 	// Test unsupport charset.
 	f := NewFile()
 	ws, err := f.workSheetReader("Sheet1")
 	assert.NoError(t, err)
+	opt := &SparklineOption{Location: []string{"A1"}, Range: []string{"Sheet1!B1:J1"}}
 	ws.ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
-	assert.EqualError(t, f.appendSparkline(ws, &xlsxX14SparklineGroup{}, &xlsxX14SparklineGroups{}), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.AddSparkline("Sheet1", opt), "XML syntax error on line 1: invalid UTF-8")
+	// Test append sparkline with invalid existing sparkline groups
+	ws.ExtLst = &xlsxExtLst{Ext: fmt.Sprintf(`<ext uri="%s" xmlns:x14="%s"><x14:sparklineGroups><x14:sparklineGroup manualMax="x"></x14:sparklineGroup></x14:sparklineGroups></ext>`,
+		ExtURISparklineGroups, NameSpaceSpreadSheetX14.Value)}
+	assert.EqualError(t, f.AddSparkline("Sheet1", opt), `strconv.ParseFloat: parsing "x": invalid syntax`)
 }
 
 func prepareSparklineDataset() *File {
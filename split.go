@@ -0,0 +1,217 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SplitColumnOptions directly maps the settings of splitting the text of the
+// column into multiple columns, either the Delimiters or the FixedWidths is
+// required.
+//
+// Delimiters specifies the characters which separate the fields, each
+// character of the string is a delimiter, such as ",;" for the comma and the
+// semicolon.
+//
+// ConsecutiveDelimiters specifies treating the consecutive delimiters as one.
+//
+// TextQualifier specifies the character which quotes the fields containing
+// the delimiters, such as '"', the doubled text qualifier in the quoted field
+// represents the character itself. The text qualifier will not be used if
+// it's zero.
+//
+// FixedWidths specifies the widths in characters of the fields, the remaining
+// text will be placed in the last field.
+//
+// ConvertNumbers specifies setting the numeric fields as numbers like the
+// General column data format of Excel, the fields will be set as strings if
+// it's false.
+type SplitColumnOptions struct {
+	Delimiters            string
+	ConsecutiveDelimiters bool
+	TextQualifier         rune
+	FixedWidths           []int
+	ConvertNumbers        bool
+}
+
+// SplitColumn provides a function to split the text of the cells in the
+// column into multiple columns by given worksheet name, column name and the
+// options like the Text to Columns of Excel. The columns for the split fields
+// will be inserted after the given column like InsertCol, the first field
+// will be kept in the given column, and the cells which can't be split will
+// be kept unchanged. For example, split the full names in the column A of
+// Sheet1 separated by the spaces:
+//
+//    err := f.SplitColumn("Sheet1", "A", excelize.SplitColumnOptions{
+//        Delimiters:            " ",
+//        ConsecutiveDelimiters: true,
+//    })
+//
+// Split the codes such as "AB1234" in the column C of Sheet1 into two letters
+// and the numbers:
+//
+//    err := f.SplitColumn("Sheet1", "C", excelize.SplitColumnOptions{
+//        FixedWidths:    []int{2},
+//        ConvertNumbers: true,
+//    })
+//
+// Use this method with caution, which will affect changes in references such
+// as formulas, charts, and so on. If there is any referenced value of the
+// worksheet, it will cause a file error when you open it. The excelize only
+// partially updates these references currently.
+func (f *File) SplitColumn(sheet, col string, opts SplitColumnOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	colNum, err := ColumnNameToNumber(col)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	fields, maxCol, count := map[int][]string{}, 0, 1
+	for rowIdx := range ws.SheetData.Row {
+		for _, c := range ws.SheetData.Row[rowIdx].C {
+			cellCol, cellRow, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return err
+			}
+			if cellCol > maxCol {
+				maxCol = cellCol
+			}
+			if cellCol != colNum {
+				continue
+			}
+			value, err := f.GetCellValue(sheet, c.R)
+			if err != nil {
+				return err
+			}
+			if values := splitColumnText(value, &opts); len(values) > 1 {
+				fields[cellRow] = values
+				if len(values) > count {
+					count = len(values)
+				}
+			}
+		}
+	}
+	if count == 1 {
+		return nil
+	}
+	if maxCol < colNum {
+		maxCol = colNum
+	}
+	if maxCol+count-1 > TotalColumns {
+		return errors.New("column number exceeds maximum limit")
+	}
+	if err = f.adjustHelper(sheet, columns, colNum+1, count-1); err != nil {
+		return err
+	}
+	rows := make([]int, 0, len(fields))
+	for row := range fields {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	for _, row := range rows {
+		for idx, field := range fields[row] {
+			cell, _ := CoordinatesToCellName(colNum+idx, row)
+			if err = f.setSplitColumnValue(sheet, cell, field, opts.ConvertNumbers); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// validate provides a function to check the options of splitting the column.
+func (opts *SplitColumnOptions) validate() error {
+	if opts.Delimiters == "" && len(opts.FixedWidths) == 0 {
+		return errors.New("parameter 'Delimiters' or 'FixedWidths' is required")
+	}
+	if opts.Delimiters != "" && len(opts.FixedWidths) > 0 {
+		return errors.New("parameter 'Delimiters' can't be used with 'FixedWidths'")
+	}
+	if opts.TextQualifier != 0 && strings.ContainsRune(opts.Delimiters, opts.TextQualifier) {
+		return errors.New("parameter 'TextQualifier' can't be used as the delimiter")
+	}
+	for idx, width := range opts.FixedWidths {
+		if width <= 0 {
+			return fmt.Errorf("parameter 'FixedWidths[%d]' must be greater than 0", idx)
+		}
+	}
+	return nil
+}
+
+// splitColumnText provides a function to split the text into fields by given
+// options of splitting the column.
+func splitColumnText(text string, opts *SplitColumnOptions) []string {
+	runes, fields := []rune(text), []string{}
+	if len(opts.FixedWidths) > 0 {
+		for _, width := range opts.FixedWidths {
+			if len(runes) == 0 {
+				break
+			}
+			if width > len(runes) {
+				width = len(runes)
+			}
+			fields, runes = append(fields, string(runes[:width])), runes[width:]
+		}
+		if len(runes) > 0 {
+			fields = append(fields, string(runes))
+		}
+		return fields
+	}
+	var (
+		field  strings.Builder
+		quoted bool
+	)
+	for idx := 0; idx < len(runes); idx++ {
+		r := runes[idx]
+		switch {
+		case opts.TextQualifier != 0 && r == opts.TextQualifier:
+			if quoted && idx+1 < len(runes) && runes[idx+1] == r {
+				field.WriteRune(r)
+				idx++
+				continue
+			}
+			quoted = !quoted
+		case !quoted && strings.ContainsRune(opts.Delimiters, r):
+			fields = append(fields, field.String())
+			field.Reset()
+			for opts.ConsecutiveDelimiters && idx+1 < len(runes) && strings.ContainsRune(opts.Delimiters, runes[idx+1]) {
+				idx++
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	return append(fields, field.String())
+}
+
+// setSplitColumnValue provides a function to set the value of the split field
+// by given worksheet name, cell coordinates and whether to set the numeric
+// field as number.
+func (f *File) setSplitColumnValue(sheet, cell, value string, convertNumbers bool) error {
+	if text := strings.TrimSpace(value); convertNumbers && !strings.ContainsAny(text, "xX") {
+		if num, err := strconv.ParseFloat(text, 64); err == nil && !math.IsInf(num, 0) && !math.IsNaN(num) {
+			return f.SetCellFloat(sheet, cell, num, -1, 64)
+		}
+	}
+	return f.SetCellStr(sheet, cell, value)
+}
@@ -0,0 +1,96 @@
+package excelize
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitColumn(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Name", "Total"},
+		{"Smith,John,Sales", 10},
+		{`"Doe, Jane",Marketing`, 20},
+		{"Lee", 30},
+		{`"Say ""Hi""",,x`, 40},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	assert.NoError(t, f.MergeCell("Sheet1", "B1", "C1"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "B3", "Sheet1!A1", "Location"))
+	assert.NoError(t, f.SplitColumn("Sheet1", "A", SplitColumnOptions{Delimiters: ",", TextQualifier: '"'}))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "", "", "Total"},
+		{"Smith", "John", "Sales", "10"},
+		{"Doe, Jane", "Marketing", "", "20"},
+		{"Lee", "", "", "30"},
+		{`Say "Hi"`, "", "x", "40"},
+	}, rows)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "D1:E1", mergeCells[0][0])
+	link, err := f.GetCellHyperLinkTarget("Sheet1", "D3")
+	assert.NoError(t, err)
+	assert.NotNil(t, link)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSplitColumn.xlsx")))
+
+	// Test split column by the fixed widths with the numbers conversion.
+	f = NewFile()
+	for idx, code := range []string{"AB1234", "CD0056", "E", "FG-1.5e2", "HI0x10"} {
+		assert.NoError(t, f.SetCellStr("Sheet1", fmt.Sprintf("B%d", idx+1), code))
+	}
+	assert.NoError(t, f.SplitColumn("Sheet1", "B", SplitColumnOptions{FixedWidths: []int{2}, ConvertNumbers: true}))
+	for cell, expected := range map[string]string{"B1": "AB", "C1": "1234", "C2": "56", "B3": "E", "C3": "", "C4": "-150", "C5": "0x10"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", ws.SheetData.Row[3].C[2].T)
+	assert.Equal(t, "s", ws.SheetData.Row[4].C[2].T)
+
+	// Test split column without the fields to be split.
+	f = NewFile()
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "A"))
+	assert.NoError(t, f.SetCellStr("Sheet1", "B1", "B"))
+	assert.NoError(t, f.SplitColumn("Sheet1", "A", SplitColumnOptions{Delimiters: ";"}))
+	value, err := f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B", value)
+
+	// Test split column with invalid parameters.
+	assert.EqualError(t, f.SplitColumn("Sheet1", "A", SplitColumnOptions{}), "parameter 'Delimiters' or 'FixedWidths' is required")
+	assert.EqualError(t, f.SplitColumn("Sheet1", "A", SplitColumnOptions{Delimiters: ",", FixedWidths: []int{1}}), "parameter 'Delimiters' can't be used with 'FixedWidths'")
+	assert.EqualError(t, f.SplitColumn("Sheet1", "A", SplitColumnOptions{Delimiters: ",'", TextQualifier: '\''}), "parameter 'TextQualifier' can't be used as the delimiter")
+	assert.EqualError(t, f.SplitColumn("Sheet1", "A", SplitColumnOptions{FixedWidths: []int{1, 0}}), "parameter 'FixedWidths[1]' must be greater than 0")
+	assert.EqualError(t, f.SplitColumn("Sheet1", "-", SplitColumnOptions{Delimiters: ","}), `invalid column name "-"`)
+	assert.EqualError(t, f.SplitColumn("SheetN", "A", SplitColumnOptions{Delimiters: ","}), "sheet SheetN is not exist")
+	assert.NoError(t, f.SetCellStr("Sheet1", "XFD1", "A,B"))
+	assert.EqualError(t, f.SplitColumn("Sheet1", "XFD", SplitColumnOptions{Delimiters: ","}), "column number exceeds maximum limit")
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "A,B"))
+	assert.EqualError(t, f.SplitColumn("Sheet1", "A", SplitColumnOptions{Delimiters: ","}), "column number exceeds maximum limit")
+}
+
+func TestSplitColumnText(t *testing.T) {
+	for _, c := range []struct {
+		text     string
+		opts     SplitColumnOptions
+		expected []string
+	}{
+		{"a  b\tc", SplitColumnOptions{Delimiters: " \t"}, []string{"a", "", "b", "c"}},
+		{"a  b\tc", SplitColumnOptions{Delimiters: " \t", ConsecutiveDelimiters: true}, []string{"a", "b", "c"}},
+		{`"a;b";c`, SplitColumnOptions{Delimiters: ";"}, []string{`"a`, `b"`, "c"}},
+		{`'a;b';c`, SplitColumnOptions{Delimiters: ";", TextQualifier: '\''}, []string{"a;b", "c"}},
+		{"", SplitColumnOptions{Delimiters: ","}, []string{""}},
+		{"ÄÖÜäöü", SplitColumnOptions{FixedWidths: []int{1, 2, 10, 1}}, []string{"Ä", "ÖÜ", "äöü"}},
+		{"", SplitColumnOptions{FixedWidths: []int{1}}, []string{}},
+	} {
+		assert.Equal(t, c.expected, splitColumnText(c.text, &c.opts), c.text)
+	}
+}
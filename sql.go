@@ -0,0 +1,154 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetSQLRows provides a function to write the result set of the SQL query to
+// the stream by given top-left cell of the data and the rows, and returns
+// the number of the data rows. The names of the columns are written as the
+// header row, and the values are written as the cells with the types mapped
+// from the database types of the columns: the integer, decimal and floating
+// point types are written as the numbers, the date, date time and timestamp
+// types are written with the date or date time number format, the decimal
+// types with scale are written with the number format of the same decimal
+// places, and the other types are written as the strings. The rows will not
+// be closed by this function. For example, write the result of the query to
+// Sheet1 and create a table over it:
+//
+//    rows, err := db.Query("SELECT id, name, amount, created_at FROM orders")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    defer rows.Close()
+//    sw, err := f.NewStreamWriter("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    count, err := sw.SetSQLRows("A1", rows)
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if count > 0 {
+//        err = sw.AddTable("A1", fmt.Sprintf("D%d", count+1), `{"table_name":"Orders"}`)
+//    }
+//    err = sw.Flush()
+//
+func (sw *StreamWriter) SetSQLRows(axis string, rows *sql.Rows) (int, error) {
+	col, row, err := CellNameToCoordinates(axis)
+	if err != nil {
+		return 0, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		header = make([]interface{}, len(columnTypes))
+		styles = make([]int, len(columnTypes))
+		values = make([]interface{}, len(columnTypes))
+		dest   = make([]interface{}, len(columnTypes))
+		count  int
+	)
+	for i, columnType := range columnTypes {
+		header[i], dest[i] = columnType.Name(), &values[i]
+		if styles[i], err = sw.getSQLColumnStyle(columnType); err != nil {
+			return count, err
+		}
+	}
+	if err = sw.SetRow(axis, header); err != nil {
+		return count, err
+	}
+	dateTimeStyle := -1
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return count, err
+		}
+		cells := make([]interface{}, len(values))
+		for i, value := range values {
+			cell := Cell{StyleID: styles[i], Value: getSQLCellValue(value, columnTypes[i].DatabaseTypeName())}
+			if _, ok := cell.Value.(time.Time); ok && cell.StyleID == 0 {
+				if dateTimeStyle == -1 {
+					if dateTimeStyle, err = sw.File.NewStyle(&Style{NumFmt: 22}); err != nil {
+						return count, err
+					}
+				}
+				cell.StyleID = dateTimeStyle
+			}
+			cells[i] = cell
+		}
+		count++
+		cell, err := CoordinatesToCellName(col, row+count)
+		if err != nil {
+			return count - 1, err
+		}
+		if err = sw.SetRow(cell, cells); err != nil {
+			return count - 1, err
+		}
+	}
+	return count, rows.Err()
+}
+
+// getSQLColumnStyle provides a function to get the style ID with the number
+// format for the column by given type of the SQL column, and 0 will be
+// returned if the column doesn't need the number format.
+func (sw *StreamWriter) getSQLColumnStyle(columnType *sql.ColumnType) (int, error) {
+	typeName := strings.ToUpper(columnType.DatabaseTypeName())
+	switch {
+	case typeName == "DATE":
+		return sw.File.NewStyle(&Style{NumFmt: 14})
+	case strings.HasPrefix(typeName, "DATETIME") || strings.HasPrefix(typeName, "TIMESTAMP"):
+		return sw.File.NewStyle(&Style{NumFmt: 22})
+	case typeName == "DECIMAL" || typeName == "NUMERIC":
+		if _, scale, ok := columnType.DecimalSize(); ok && scale > 0 && scale <= 30 {
+			numFmt := "0." + strings.Repeat("0", int(scale))
+			return sw.File.NewStyle(&Style{CustomNumFmt: &numFmt})
+		}
+	}
+	return 0, nil
+}
+
+// getSQLCellValue provides a function to convert the value scanned from the
+// SQL column to the cell value by given database type name of the column, the
+// bytes of the numeric types will be parsed as the number, and the wall clock
+// of the time in any location will be kept as the time in UTC.
+func getSQLCellValue(value interface{}, typeName string) interface{} {
+	if t, ok := value.(time.Time); ok {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+	typeName = strings.ToUpper(typeName)
+	if strings.Contains(typeName, "INT") {
+		if i, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+			return i
+		}
+	}
+	for _, numeric := range []string{"DECIMAL", "NUMERIC", "FLOAT", "DOUBLE", "REAL", "MONEY"} {
+		if strings.Contains(typeName, numeric) {
+			if num, err := strconv.ParseFloat(string(b), 64); err == nil {
+				return num
+			}
+		}
+	}
+	return string(b)
+}
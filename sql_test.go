@@ -0,0 +1,149 @@
+package excelize
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSQLDriver is a driver which returns the fixed rows for the tests.
+type testSQLDriver struct{}
+
+type testSQLConn struct{}
+
+type testSQLStmt struct{}
+
+type testSQLRows struct {
+	columns []string
+	types   []string
+	rows    [][]driver.Value
+	err     error
+}
+
+var testSQLResult = &testSQLRows{}
+
+func (testSQLDriver) Open(name string) (driver.Conn, error) { return testSQLConn{}, nil }
+
+func (testSQLConn) Prepare(query string) (driver.Stmt, error) { return testSQLStmt{}, nil }
+
+func (testSQLConn) Close() error { return nil }
+
+func (testSQLConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (testSQLStmt) Close() error { return nil }
+
+func (testSQLStmt) NumInput() int { return 0 }
+
+func (testSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (testSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows := *testSQLResult
+	return &rows, nil
+}
+
+func (r *testSQLRows) Columns() []string { return r.columns }
+
+func (r *testSQLRows) Close() error { return nil }
+
+func (r *testSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func (r *testSQLRows) ColumnTypeDatabaseTypeName(index int) string { return r.types[index] }
+
+func (r *testSQLRows) ColumnTypeScanType(index int) reflect.Type {
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+func (r *testSQLRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if r.types[index] == "DECIMAL" {
+		return 10, 2, true
+	}
+	return 0, 0, false
+}
+
+func init() {
+	sql.Register("excelize-test", testSQLDriver{})
+}
+
+func TestSetSQLRows(t *testing.T) {
+	db, err := sql.Open("excelize-test", "")
+	assert.NoError(t, err)
+	defer db.Close()
+	created := time.Date(2021, 1, 2, 15, 4, 5, 0, time.FixedZone("UTC+8", 8*3600))
+	testSQLResult = &testSQLRows{
+		columns: []string{"id", "name", "amount", "rate", "day", "created_at", "updated_at", "note"},
+		types:   []string{"BIGINT", "VARCHAR", "DECIMAL", "DOUBLE", "DATE", "TIMESTAMP", "", "TEXT"},
+		rows: [][]driver.Value{
+			{int64(1), "Foo", []byte("12.5"), []byte("0.25"), time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), created, created, nil},
+			{[]byte("2"), []byte("Bar"), []byte("n/a"), 1.5, nil, nil, nil, []byte("note")},
+		},
+	}
+	rows, err := db.Query("SELECT")
+	assert.NoError(t, err)
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	count, err := sw.SetSQLRows("B2", rows)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetSQLRows.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestSetSQLRows.xlsx"))
+	assert.NoError(t, err)
+	result, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		nil,
+		{"", "id", "name", "amount", "rate", "day", "created_at", "updated_at", "note"},
+		{"", "1", "Foo", "12.5", "0.25", "01-02-21", "1/2/21 3:04", "1/2/21 3:04", ""},
+		{"", "2", "Bar", "n/a", "1.5", "", "", "", "note"},
+	}, result)
+	styleID, err := f.GetCellStyle("Sheet1", "D3")
+	assert.NoError(t, err)
+	styles, err := f.stylesReader()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00", styles.NumFmts.NumFmt[0].FormatCode)
+	assert.Equal(t, styles.NumFmts.NumFmt[0].NumFmtID, *styles.CellXfs.Xf[styleID].NumFmtID)
+
+	// Test write with invalid cell and the error of the rows
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	rows, err = db.Query("SELECT")
+	assert.NoError(t, err)
+	_, err = sw.SetSQLRows("A", rows)
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.NoError(t, rows.Close())
+	testSQLResult.err = errors.New("connection lost")
+	rows, err = db.Query("SELECT")
+	assert.NoError(t, err)
+	count, err = sw.SetSQLRows("A10", rows)
+	assert.EqualError(t, err, "connection lost")
+	assert.Equal(t, 2, count)
+	testSQLResult.err = nil
+	rows, err = db.Query("SELECT")
+	assert.NoError(t, err)
+	_, err = sw.SetSQLRows("XFD30", rows)
+	assert.EqualError(t, err, "column number exceeds maximum limit")
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, "text", getSQLCellValue([]byte("text"), "BIGINT"))
+}
@@ -14,6 +14,7 @@ package excelize
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -70,10 +71,39 @@ type StreamWriter struct {
 //        fmt.Println(err)
 //    }
 //
+// The stream writers of different worksheets can be used in separate
+// goroutines concurrently, the SetRow and Flush of the stream writers are
+// safe for concurrent use, but a stream writer should not be shared between
+// goroutines. For example, write two worksheets concurrently:
+//
+//    var wg sync.WaitGroup
+//    for _, sheet := range []string{"Sheet1", "Sheet2"} {
+//        sw, err := file.NewStreamWriter(sheet)
+//        if err != nil {
+//            fmt.Println(err)
+//            return
+//        }
+//        wg.Add(1)
+//        go func(sw *excelize.StreamWriter) {
+//            defer wg.Done()
+//            for rowID := 1; rowID <= 102400; rowID++ {
+//                cell, _ := excelize.CoordinatesToCellName(1, rowID)
+//                if err := sw.SetRow(cell, []interface{}{rowID}); err != nil {
+//                    fmt.Println(err)
+//                    return
+//                }
+//            }
+//            if err := sw.Flush(); err != nil {
+//                fmt.Println(err)
+//            }
+//        }(sw)
+//    }
+//    wg.Wait()
+//
 func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 	sheetID := f.getSheetID(sheet)
 	if sheetID == -1 {
-		return nil, fmt.Errorf("sheet %s is not exist", sheet)
+		return nil, ErrSheetNotExist{sheet}
 	}
 	sw := &StreamWriter{
 		File:    f,
@@ -87,10 +117,12 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 	}
 
 	sheetXML := fmt.Sprintf("xl/worksheets/sheet%d.xml", sw.SheetID)
+	f.Lock()
 	if f.streams == nil {
 		f.streams = make(map[string]*StreamWriter)
 	}
 	f.streams[sheetXML] = sw
+	f.Unlock()
 
 	sw.rawData.WriteString(XMLHeader + `<worksheet` + templateNamespaceIDMap)
 	bulkAppendFields(&sw.rawData, sw.worksheet, 2, 6)
@@ -268,19 +300,113 @@ type Cell struct {
 	Value   interface{}
 }
 
+// MergeCell provides a function to merge cells by a given coordinate area
+// for the StreamWriter. For example, merge the cells in the area A1:D1:
+//
+//    err := sw.MergeCell("A1", "D1")
+//
+// Note that MergeCell must be called before Flush. See File.MergeCell for
+// details on the merged cells.
+func (sw *StreamWriter) MergeCell(hcell, vcell string) error {
+	return sw.File.MergeCell(sw.Sheet, hcell, vcell)
+}
+
+// SetCellHyperLink provides a function to set cell hyperlink by given cell
+// coordinate, link resource and link type for the StreamWriter. For example,
+// add a hyperlink to an external website on the A2 cell:
+//
+//    err := sw.SetCellHyperLink("A2", "https://github.com/360EntSecGroup-Skylar/excelize", "External")
+//
+// Note that SetCellHyperLink must be called before Flush. See
+// File.SetCellHyperLink for details on the link types.
+func (sw *StreamWriter) SetCellHyperLink(axis, link, linkType string) error {
+	return sw.File.SetCellHyperLink(sw.Sheet, axis, link, linkType)
+}
+
+// AddDataValidation provides a function to set data validation on a range
+// of the StreamWriter. For example, set a drop list on the cells in the area
+// B2:B1000:
+//
+//    dvRange := excelize.NewDataValidation(true)
+//    dvRange.Sqref = "B2:B1000"
+//    dvRange.SetDropList([]string{"1", "2", "3"})
+//    err := sw.AddDataValidation(dvRange)
+//
+// Note that AddDataValidation must be called before Flush. See
+// File.AddDataValidation for details on the data validation.
+func (sw *StreamWriter) AddDataValidation(dv *DataValidation) error {
+	return sw.File.AddDataValidation(sw.Sheet, dv)
+}
+
+// AddPicture provides a function to add picture in the StreamWriter by given
+// cell, picture path and format set. For example, insert a picture in the
+// cell A2 of the worksheet:
+//
+//    err := sw.AddPicture("A2", "image.png", `{"x_scale": 0.5, "y_scale": 0.5}`)
+//
+// Note that AddPicture must be called before Flush. The anchors of the
+// pictures will be written when saving the workbook. See File.AddPicture for
+// details on the format set.
+func (sw *StreamWriter) AddPicture(cell, picture, format string) error {
+	return sw.File.AddPicture(sw.Sheet, cell, picture, format)
+}
+
+// AddPictureFromBytes provides a function to add picture in the StreamWriter
+// by given cell, format set, picture name, extension name and file bytes.
+// Note that AddPictureFromBytes must be called before Flush. See
+// File.AddPictureFromBytes for details on the parameters.
+func (sw *StreamWriter) AddPictureFromBytes(cell, format, name, extension string, file []byte) error {
+	return sw.File.AddPictureFromBytes(sw.Sheet, cell, format, name, extension, file)
+}
+
+// AddChart provides a function to add chart in the StreamWriter by given
+// cell, format set and combo charts. For example, create a column chart at
+// the cell E1 with the data of the streamed rows:
+//
+//    err := sw.AddChart("E1", `{"type":"col","series":[{"name":"Sheet1!$A$1","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`)
+//
+// Note that AddChart must be called before Flush. The anchors of the charts
+// will be written when saving the workbook. See File.AddChart for details on
+// the format set, which can also be the Chart structure pointer.
+func (sw *StreamWriter) AddChart(cell string, format interface{}, combo ...interface{}) error {
+	return sw.File.AddChart(sw.Sheet, cell, format, combo...)
+}
+
+// RowOpts define the options for the row of the StreamWriter. The Height
+// specifies the height of the row in points, the Hidden specifies if hide
+// the row, the OutlineLevel specifies the outline level of the row in the
+// range of 1 to 7, and the StyleID specifies the style of the row.
+type RowOpts struct {
+	Height       float64
+	Hidden       bool
+	OutlineLevel uint8
+	StyleID      int
+}
+
 // SetRow writes an array to stream rows by giving a worksheet name, starting
 // coordinate and a pointer to an array of values. Note that you must call the
 // 'Flush' method to end the streaming writing process.
 //
 // As a special case, if Cell is used as a value, then the Cell.StyleID will be
 // applied to that cell.
-func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
+//
+// The row options can be specified to set the height, visibility, outline
+// level and style of the row. For example, write a hidden row with a height
+// of 30 points:
+//
+//    err := sw.SetRow("A1", []interface{}{"Data"}, excelize.RowOpts{Height: 30, Hidden: true})
+//
+func (sw *StreamWriter) SetRow(axis string, values []interface{}, opts ...RowOpts) error {
 	col, row, err := CellNameToCoordinates(axis)
 	if err != nil {
 		return err
 	}
+	attrs, err := marshalRowAttrs(opts...)
+	if err != nil {
+		return err
+	}
 
-	fmt.Fprintf(&sw.rawData, `<row r="%d">`, row)
+	fmt.Fprintf(&sw.rawData, `<row r="%d"%s>`, row, attrs)
 	for i, val := range values {
 		axis, err := CoordinatesToCellName(col+i, row)
 		if err != nil {
@@ -294,7 +420,7 @@ func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
 			c.S = v.StyleID
 			val = v.Value
 		}
-		if err = setCellValFunc(&c, val); err != nil {
+		if err = setCellValFunc(&c, val, sw.File.date1904()); err != nil {
 			sw.rawData.WriteString(`</row>`)
 			return err
 		}
@@ -304,8 +430,37 @@ func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
 	return sw.rawData.Sync()
 }
 
-// setCellValFunc provides a function to set value of a cell.
-func setCellValFunc(c *xlsxC, val interface{}) (err error) {
+// marshalRowAttrs provides a function to get the attributes of the row by
+// given row options.
+func marshalRowAttrs(opts ...RowOpts) (string, error) {
+	var attrs strings.Builder
+	for _, opt := range opts {
+		if opt.Height > MaxRowHeight {
+			return "", errors.New("the height of the row must be smaller than or equal to 409 points")
+		}
+		if opt.OutlineLevel > 7 {
+			return "", errors.New("invalid outline level")
+		}
+		attrs.Reset()
+		if opt.StyleID > 0 {
+			fmt.Fprintf(&attrs, ` s="%d" customFormat="true"`, opt.StyleID)
+		}
+		if opt.Height > 0 {
+			fmt.Fprintf(&attrs, ` ht="%s" customHeight="true"`, strconv.FormatFloat(opt.Height, 'f', -1, 64))
+		}
+		if opt.Hidden {
+			attrs.WriteString(` hidden="true"`)
+		}
+		if opt.OutlineLevel > 0 {
+			fmt.Fprintf(&attrs, ` outlineLevel="%d"`, opt.OutlineLevel)
+		}
+	}
+	return attrs.String(), nil
+}
+
+// setCellValFunc provides a function to set value of a cell by given cell,
+// value and date system of the workbook.
+func setCellValFunc(c *xlsxC, val interface{}, date1904 bool) (err error) {
 	switch val := val.(type) {
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		err = setCellIntFunc(c, val)
@@ -320,7 +475,7 @@ func setCellValFunc(c *xlsxC, val interface{}) (err error) {
 	case time.Duration:
 		c.T, c.V = setCellDuration(val)
 	case time.Time:
-		c.T, c.V, _, err = setCellTime(val)
+		c.T, c.V, _, err = setCellTime(val, date1904)
 	case bool:
 		c.T, c.V = setCellBool(val)
 	case nil:
@@ -383,7 +538,9 @@ func writeCell(buf *bufferedWriter, c xlsxC) {
 // Flush ending the streaming writing process.
 func (sw *StreamWriter) Flush() error {
 	sw.rawData.WriteString(`</sheetData>`)
-	bulkAppendFields(&sw.rawData, sw.worksheet, 8, 38)
+	var fields bytes.Buffer
+	bulkAppendFields(&fields, sw.worksheet, 8, 38)
+	sw.rawData.Write(replaceRelationshipsBytes(fields.Bytes()))
 	sw.rawData.WriteString(sw.tableParts)
 	bulkAppendFields(&sw.rawData, sw.worksheet, 40, 40)
 	sw.rawData.WriteString(`</worksheet>`)
@@ -392,9 +549,11 @@ func (sw *StreamWriter) Flush() error {
 	}
 
 	sheetXML := fmt.Sprintf("xl/worksheets/sheet%d.xml", sw.SheetID)
+	sw.File.Lock()
 	delete(sw.File.Sheet, sheetXML)
 	delete(sw.File.checked, sheetXML)
 	delete(sw.File.XLSX, sheetXML)
+	sw.File.Unlock()
 
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -134,6 +135,158 @@ func TestStreamTable(t *testing.T) {
 	assert.EqualError(t, streamWriter.AddTable("A1", "B", `{}`), `cannot convert cell "B" to coordinates: invalid cell name "B"`)
 }
 
+func TestStreamMergeCellHyperLinkDataValidation(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Title"}))
+	assert.NoError(t, streamWriter.SetRow("A2", []interface{}{"Link", "Sheet1!A1", 1}))
+	assert.NoError(t, streamWriter.MergeCell("A1", "C1"))
+	assert.NoError(t, streamWriter.SetCellHyperLink("A2", "https://github.com/360EntSecGroup-Skylar/excelize", "External"))
+	assert.NoError(t, streamWriter.SetCellHyperLink("B2", "Sheet1!A1", "Location"))
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "C2:C10"
+	assert.NoError(t, dvRange.SetRange(1, 10, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, streamWriter.AddDataValidation(dvRange))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamMergeCellHyperLinkDataValidation.xlsx")))
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamMergeCellHyperLinkDataValidation.xlsx"))
+	assert.NoError(t, err)
+	mergeCells, err := file.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, mergeCells, 1) {
+		assert.Equal(t, "A1", mergeCells[0].GetStartAxis())
+		assert.Equal(t, "C1", mergeCells[0].GetEndAxis())
+	}
+	link, target, err := file.GetCellHyperLink("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/360EntSecGroup-Skylar/excelize", target)
+	link, target, err = file.GetCellHyperLink("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "Sheet1!A1", target)
+	ws, err := file.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	if assert.NotNil(t, ws.DataValidations) {
+		assert.Equal(t, 1, ws.DataValidations.Count)
+		assert.Equal(t, "C2:C10", ws.DataValidations.DataValidation[0].Sqref)
+	}
+
+	// Test set merged cells, hyperlinks and data validation with invalid parameters
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, streamWriter.MergeCell("A", "B1"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, streamWriter.SetCellHyperLink("A2", "Sheet1!A1", "None"), `invalid link type "None"`)
+	streamWriter.Sheet = "SheetN"
+	assert.EqualError(t, streamWriter.AddDataValidation(dvRange), "sheet SheetN is not exist")
+}
+
+func TestStreamPictureChart(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{nil, "Q1", "Q2", "Q3"}))
+	assert.NoError(t, streamWriter.SetRow("A2", []interface{}{"Sales", 1, 2, 3}))
+	assert.NoError(t, streamWriter.AddPicture("A4", filepath.Join("test", "images", "excel.png"), ""))
+	img, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.jpg"))
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.AddPictureFromBytes("H4", "", "Excel Logo", ".jpg", img))
+	assert.NoError(t, streamWriter.AddChart("E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamPictureChart.xlsx")))
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamPictureChart.xlsx"))
+	assert.NoError(t, err)
+	name, raw, err := file.GetPicture("Sheet1", "A4")
+	assert.NoError(t, err)
+	assert.Equal(t, "image1.png", name)
+	assert.NotEmpty(t, raw)
+	name, raw, err = file.GetPicture("Sheet1", "H4")
+	assert.NoError(t, err)
+	assert.Equal(t, "image2.jpeg", name)
+	assert.Equal(t, img, raw)
+	assert.Contains(t, file.XLSX, "xl/charts/chart1.xml")
+	val, err := file.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", val)
+
+	// Test add picture and chart with invalid parameters
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, streamWriter.AddPicture("A1", filepath.Join("test", "images", "excel.png"), "{x}"), "invalid character 'x' looking for beginning of object key string")
+	assert.EqualError(t, streamWriter.AddPictureFromBytes("A1", "", "Excel Logo", ".txt", img), "unsupported image extension")
+	assert.EqualError(t, streamWriter.AddChart("A1", `{"type":"unknown"}`), "unsupported chart type unknown")
+}
+
+func TestConcurrentStreamWriters(t *testing.T) {
+	file := NewFile()
+	sheets := []string{"Sheet1", "Sheet2", "Sheet3", "Sheet4"}
+	for _, sheet := range sheets[1:] {
+		file.NewSheet(sheet)
+	}
+	var wg sync.WaitGroup
+	for _, sheet := range sheets {
+		wg.Add(1)
+		go func(sheet string) {
+			defer wg.Done()
+			streamWriter, err := file.NewStreamWriter(sheet)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for rowID := 1; rowID <= 1000; rowID++ {
+				cell, _ := CoordinatesToCellName(1, rowID)
+				assert.NoError(t, streamWriter.SetRow(cell, []interface{}{sheet, rowID, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}))
+			}
+			assert.NoError(t, streamWriter.Flush())
+		}(sheet)
+	}
+	wg.Wait()
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestConcurrentStreamWriters.xlsx")))
+
+	file, err := OpenFile(filepath.Join("test", "TestConcurrentStreamWriters.xlsx"))
+	assert.NoError(t, err)
+	for _, sheet := range sheets {
+		rows, err := file.GetRows(sheet)
+		assert.NoError(t, err)
+		if assert.Len(t, rows, 1000) {
+			assert.Equal(t, []string{sheet, "1000", "43831"}, rows[999])
+		}
+	}
+}
+
+func TestStreamSetRowWithOpts(t *testing.T) {
+	file := NewFile()
+	styleID, err := file.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"#E0EBF5"}, Pattern: 1}})
+	assert.NoError(t, err)
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A"}, RowOpts{Height: 30.5}))
+	assert.NoError(t, streamWriter.SetRow("A2", []interface{}{"B"}, RowOpts{Hidden: true, OutlineLevel: 2}))
+	assert.NoError(t, streamWriter.SetRow("A3", []interface{}{"C"}, RowOpts{StyleID: styleID}))
+	assert.EqualError(t, streamWriter.SetRow("A4", []interface{}{"D"}, RowOpts{Height: MaxRowHeight + 1}), "the height of the row must be smaller than or equal to 409 points")
+	assert.EqualError(t, streamWriter.SetRow("A4", []interface{}{"D"}, RowOpts{OutlineLevel: 8}), "invalid outline level")
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamSetRowWithOpts.xlsx")))
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamSetRowWithOpts.xlsx"))
+	assert.NoError(t, err)
+	height, err := file.GetRowHeight("Sheet1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.5, height)
+	visible, err := file.GetRowVisible("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	level, err := file.GetRowOutlineLevel("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(2), level)
+	ws, err := file.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID, ws.SheetData.Row[2].S)
+	assert.True(t, ws.SheetData.Row[2].CustomFormat)
+}
+
 func TestNewStreamWriter(t *testing.T) {
 	// Test error exceptions
 	file := NewFile()
@@ -153,23 +306,23 @@ func TestSetRow(t *testing.T) {
 
 func TestSetCellValFunc(t *testing.T) {
 	c := &xlsxC{}
-	assert.NoError(t, setCellValFunc(c, 128))
-	assert.NoError(t, setCellValFunc(c, int8(-128)))
-	assert.NoError(t, setCellValFunc(c, int16(-32768)))
-	assert.NoError(t, setCellValFunc(c, int32(-2147483648)))
-	assert.NoError(t, setCellValFunc(c, int64(-9223372036854775808)))
-	assert.NoError(t, setCellValFunc(c, uint(128)))
-	assert.NoError(t, setCellValFunc(c, uint8(255)))
-	assert.NoError(t, setCellValFunc(c, uint16(65535)))
-	assert.NoError(t, setCellValFunc(c, uint32(4294967295)))
-	assert.NoError(t, setCellValFunc(c, uint64(18446744073709551615)))
-	assert.NoError(t, setCellValFunc(c, float32(100.1588)))
-	assert.NoError(t, setCellValFunc(c, float64(100.1588)))
-	assert.NoError(t, setCellValFunc(c, " Hello"))
-	assert.NoError(t, setCellValFunc(c, []byte(" Hello")))
-	assert.NoError(t, setCellValFunc(c, time.Now().UTC()))
-	assert.NoError(t, setCellValFunc(c, time.Duration(1e13)))
-	assert.NoError(t, setCellValFunc(c, true))
-	assert.NoError(t, setCellValFunc(c, nil))
-	assert.NoError(t, setCellValFunc(c, complex64(5+10i)))
+	assert.NoError(t, setCellValFunc(c, 128, false))
+	assert.NoError(t, setCellValFunc(c, int8(-128), false))
+	assert.NoError(t, setCellValFunc(c, int16(-32768), false))
+	assert.NoError(t, setCellValFunc(c, int32(-2147483648), false))
+	assert.NoError(t, setCellValFunc(c, int64(-9223372036854775808), false))
+	assert.NoError(t, setCellValFunc(c, uint(128), false))
+	assert.NoError(t, setCellValFunc(c, uint8(255), false))
+	assert.NoError(t, setCellValFunc(c, uint16(65535), false))
+	assert.NoError(t, setCellValFunc(c, uint32(4294967295), false))
+	assert.NoError(t, setCellValFunc(c, uint64(18446744073709551615), false))
+	assert.NoError(t, setCellValFunc(c, float32(100.1588), false))
+	assert.NoError(t, setCellValFunc(c, float64(100.1588), false))
+	assert.NoError(t, setCellValFunc(c, " Hello", false))
+	assert.NoError(t, setCellValFunc(c, []byte(" Hello"), false))
+	assert.NoError(t, setCellValFunc(c, time.Now().UTC(), false))
+	assert.NoError(t, setCellValFunc(c, time.Duration(1e13), false))
+	assert.NoError(t, setCellValFunc(c, true, false))
+	assert.NoError(t, setCellValFunc(c, nil, false))
+	assert.NoError(t, setCellValFunc(c, complex64(5+10i), false))
 }
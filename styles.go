@@ -18,7 +18,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"math"
 	"reflect"
 	"regexp"
@@ -756,7 +755,7 @@ var currencyNumFmt = map[int]string{
 
 // builtInNumFmtFunc defined the format conversion functions map. Partial format
 // code doesn't support currently and will return original string.
-var builtInNumFmtFunc = map[int]func(v string, format string) string{
+var builtInNumFmtFunc = map[int]func(v string, format string, date1904 bool) string{
 	0:  formatToString,
 	1:  formatToInt,
 	2:  formatToFloat,
@@ -813,6 +812,20 @@ var validType = map[string]string{
 	"formula":       "expression",
 }
 
+// drawContFmtFunc defined the functions to create the conditional formatting
+// rule by given validation types.
+var drawContFmtFunc = map[string]func(p int, ct string, fmtCond *ConditionalFormatOption) *xlsxCfRule{
+	"cellIs":          drawCondFmtCellIs,
+	"top10":           drawCondFmtTop10,
+	"aboveAverage":    drawCondFmtAboveAverage,
+	"duplicateValues": drawCondFmtDuplicateUniqueValues,
+	"uniqueValues":    drawCondFmtDuplicateUniqueValues,
+	"2_color_scale":   drawCondFmtColorScale,
+	"3_color_scale":   drawCondFmtColorScale,
+	"dataBar":         drawCondFmtDataBar,
+	"expression":      drawConfFmtExp,
+}
+
 // criteriaType defined the list of valid criteria types.
 var criteriaType = map[string]string{
 	"between":                  "between",
@@ -848,14 +861,14 @@ var criteriaType = map[string]string{
 
 // formatToString provides a function to return original string by given
 // built-in number formats code and cell string.
-func formatToString(v string, format string) string {
+func formatToString(v string, format string, date1904 bool) string {
 	return v
 }
 
 // formatToInt provides a function to convert original string to integer
 // format as string type by given built-in number formats code and cell
 // string.
-func formatToInt(v string, format string) string {
+func formatToInt(v string, format string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -866,7 +879,7 @@ func formatToInt(v string, format string) string {
 // formatToFloat provides a function to convert original string to float
 // format as string type by given built-in number formats code and cell
 // string.
-func formatToFloat(v string, format string) string {
+func formatToFloat(v string, format string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -876,7 +889,7 @@ func formatToFloat(v string, format string) string {
 
 // formatToA provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToA(v string, format string) string {
+func formatToA(v string, format string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -891,7 +904,7 @@ func formatToA(v string, format string) string {
 
 // formatToB provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToB(v string, format string) string {
+func formatToB(v string, format string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -904,7 +917,7 @@ func formatToB(v string, format string) string {
 
 // formatToC provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToC(v string, format string) string {
+func formatToC(v string, format string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -915,7 +928,7 @@ func formatToC(v string, format string) string {
 
 // formatToD provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToD(v string, format string) string {
+func formatToD(v string, format string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -926,7 +939,7 @@ func formatToD(v string, format string) string {
 
 // formatToE provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToE(v string, format string) string {
+func formatToE(v string, format string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -945,9 +958,10 @@ var dateTimeFormatsCache = map[string]string{}
 // letters in them that would be replaced by other characters below (such as
 // the 'h' in March, or the 'd' in Tuesday) below. First we convert them to
 // arbitrary characters unused in Excel Date formats, and then at the end,
-// turn them to what they should actually be. Based off:
+// turn them to what they should actually be. The cell value will be
+// converted with the given date system of the workbook. Based off:
 // http://www.ozgrid.com/Excel/CustomFormats.htm
-func parseTime(v string, format string) string {
+func parseTime(v string, format string, date1904 bool) string {
 	var (
 		f     float64
 		err   error
@@ -957,7 +971,7 @@ func parseTime(v string, format string) string {
 	if err != nil {
 		return v
 	}
-	val := timeFromExcelTime(f, false)
+	val := timeFromExcelTime(f, date1904)
 
 	if format == "" {
 		return v
@@ -1058,25 +1072,23 @@ func is12HourTime(format string) bool {
 
 // stylesReader provides a function to get the pointer to the structure after
 // deserialization of xl/styles.xml.
-func (f *File) stylesReader() *xlsxStyleSheet {
-	var err error
-
+func (f *File) stylesReader() (*xlsxStyleSheet, error) {
 	if f.Styles == nil {
 		f.Styles = new(xlsxStyleSheet)
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/styles.xml")))).
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/styles.xml")))).
 			Decode(f.Styles); err != nil && err != io.EOF {
-			log.Printf("xml decode error: %s", err)
+			return f.Styles, f.newXMLDecodeError("xl/styles.xml", err)
 		}
 	}
 
-	return f.Styles
+	return f.Styles, nil
 }
 
 // styleSheetWriter provides a function to save xl/styles.xml after serialize
 // structure.
 func (f *File) styleSheetWriter() {
 	if f.Styles != nil {
-		output, _ := xml.Marshal(f.Styles)
+		output := marshalStyleSheet(f.Styles)
 		f.saveFileList("xl/styles.xml", f.replaceNameSpaceBytes("xl/styles.xml", output))
 	}
 }
@@ -1085,7 +1097,7 @@ func (f *File) styleSheetWriter() {
 // serialize structure.
 func (f *File) sharedStringsWriter() {
 	if f.SharedStrings != nil {
-		output, _ := xml.Marshal(f.SharedStrings)
+		output := marshalSharedStrings(f.SharedStrings)
 		f.saveFileList("xl/sharedStrings.xml", f.replaceNameSpaceBytes("xl/sharedStrings.xml", output))
 	}
 }
@@ -1987,7 +1999,10 @@ func (f *File) NewStyle(style interface{}) (int, error) {
 	if fs.DecimalPlaces == 0 {
 		fs.DecimalPlaces = 2
 	}
-	s := f.stylesReader()
+	s, err := f.stylesReader()
+	if err != nil {
+		return cellXfsID, err
+	}
 	// check given style already exist.
 	if cellXfsID = f.getStyleID(s, fs); cellXfsID != -1 {
 		return cellXfsID, err
@@ -2103,7 +2118,10 @@ func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (styleID int) {
 // NewStyle(). Note that the color field uses RGB color code and only support
 // to set font, fills, alignment and borders currently.
 func (f *File) NewConditionalStyle(style string) (int, error) {
-	s := f.stylesReader()
+	s, err := f.stylesReader()
+	if err != nil {
+		return 0, err
+	}
 	fs, err := parseFormatStyleSet(style)
 	if err != nil {
 		return 0, err
@@ -2142,15 +2160,122 @@ func (f *File) GetDefaultFont() string {
 func (f *File) SetDefaultFont(fontName string) {
 	font := f.readDefaultFont()
 	font.Name.Val = stringPtr(fontName)
-	s := f.stylesReader()
+	s, _ := f.stylesReader()
 	s.Fonts.Font[0] = font
 	custom := true
 	s.CellStyles.CellStyle[0].CustomBuiltIn = &custom
 }
 
+// Define the built-in cell style ID and the theme color index of the hyperlink
+// cell styles.
+const (
+	builtInHyperLinkStyleID         = 8
+	builtInFollowedHyperLinkStyleID = 9
+	themeColorHyperLink             = 10
+	themeColorFollowedHyperLink     = 11
+)
+
+// setCellHyperLinkStyle provides a function to apply the built-in Hyperlink
+// cell style to the cell by given worksheet name and cell coordinates, the
+// formats of the cell except the font will be kept. The built-in Followed
+// Hyperlink cell style will also be added for Excel to switch to it after the
+// hyperlink has been clicked.
+func (f *File) setCellHyperLinkStyle(sheet, axis string) error {
+	styleID, err := f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return err
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	f.addBuiltInCellStyle(s, "Followed Hyperlink", builtInFollowedHyperLinkStyleID, themeColorFollowedHyperLink)
+	xfID := f.addBuiltInCellStyle(s, "Hyperlink", builtInHyperLinkStyleID, themeColorHyperLink)
+	if s.CellXfs == nil {
+		s.CellXfs = &xlsxCellXfs{}
+	}
+	var xf xlsxXf
+	if styleID < len(s.CellXfs.Xf) {
+		xf = s.CellXfs.Xf[styleID]
+	} else {
+		xf = xlsxXf{NumFmtID: intPtr(0), FillID: intPtr(0), BorderID: intPtr(0)}
+	}
+	xf.FontID, xf.XfID, xf.ApplyFont = intPtr(*s.CellStyleXfs.Xf[xfID].FontID), intPtr(xfID), nil
+	for idx, cellXf := range s.CellXfs.Xf {
+		if reflect.DeepEqual(cellXf, xf) {
+			return f.SetCellStyle(sheet, axis, axis, idx)
+		}
+	}
+	s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+	s.CellXfs.Count = len(s.CellXfs.Xf)
+	return f.SetCellStyle(sheet, axis, axis, s.CellXfs.Count-1)
+}
+
+// addBuiltInCellStyle provides a function to add the built-in named cell
+// style with the underlined font in the given theme color, which is based on
+// the default font of the workbook, and returns the index of its master
+// formatting record. The existing named cell style with the same built-in ID
+// will be reused.
+func (f *File) addBuiltInCellStyle(s *xlsxStyleSheet, name string, builtInID, themeColor int) int {
+	if s.CellStyles == nil {
+		s.CellStyles = &xlsxCellStyles{}
+	}
+	if s.CellStyleXfs == nil {
+		s.CellStyleXfs = &xlsxCellStyleXfs{}
+	}
+	for _, cellStyle := range s.CellStyles.CellStyle {
+		if cellStyle.BuiltInID != nil && *cellStyle.BuiltInID == builtInID &&
+			cellStyle.XfID < len(s.CellStyleXfs.Xf) && s.CellStyleXfs.Xf[cellStyle.XfID].FontID != nil {
+			return cellStyle.XfID
+		}
+	}
+	font := &xlsxFont{U: &attrValString{Val: stringPtr("single")}, Color: &xlsxColor{Theme: intPtr(themeColor)}}
+	if s.Fonts == nil {
+		s.Fonts = &xlsxFonts{}
+	}
+	if len(s.Fonts.Font) > 0 {
+		if defaultFont := s.Fonts.Font[0]; defaultFont != nil {
+			if defaultFont.Sz != nil {
+				sz := *defaultFont.Sz
+				font.Sz = &sz
+			}
+			if defaultFont.Name != nil {
+				fontName := *defaultFont.Name
+				font.Name = &fontName
+			}
+			if defaultFont.Family != nil {
+				family := *defaultFont.Family
+				font.Family = &family
+			}
+			if defaultFont.Scheme != nil {
+				scheme := *defaultFont.Scheme
+				font.Scheme = &scheme
+			}
+		}
+	}
+	s.Fonts.Font = append(s.Fonts.Font, font)
+	s.Fonts.Count = len(s.Fonts.Font)
+	s.CellStyleXfs.Xf = append(s.CellStyleXfs.Xf, xlsxXf{
+		NumFmtID:          intPtr(0),
+		FontID:            intPtr(s.Fonts.Count - 1),
+		FillID:            intPtr(0),
+		BorderID:          intPtr(0),
+		ApplyNumberFormat: boolPtr(false),
+		ApplyFill:         boolPtr(false),
+		ApplyBorder:       boolPtr(false),
+		ApplyAlignment:    boolPtr(false),
+		ApplyProtection:   boolPtr(false),
+	})
+	s.CellStyleXfs.Count = len(s.CellStyleXfs.Xf)
+	xfID := s.CellStyleXfs.Count - 1
+	s.CellStyles.CellStyle = append(s.CellStyles.CellStyle, &xlsxCellStyle{Name: name, XfID: xfID, BuiltInID: intPtr(builtInID)})
+	s.CellStyles.Count = len(s.CellStyles.CellStyle)
+	return xfID
+}
+
 // readDefaultFont provides an unmarshalled font value.
 func (f *File) readDefaultFont() *xlsxFont {
-	s := f.stylesReader()
+	s, _ := f.stylesReader()
 	return s.Fonts.Font[0]
 }
 
@@ -2459,6 +2584,9 @@ func newAlignment(style *Style) *xlsxAlignment {
 		alignment.TextRotation = style.Alignment.TextRotation
 		alignment.Vertical = style.Alignment.Vertical
 		alignment.WrapText = style.Alignment.WrapText
+		if style.Alignment.VerticalText {
+			alignment.TextRotation = 255
+		}
 	}
 	return &alignment
 }
@@ -2584,11 +2712,53 @@ func (f *File) GetCellStyle(sheet, axis string) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	cellData, col, _, err := f.prepareCell(ws, sheet, axis)
+	ws.Lock()
+	defer ws.Unlock()
+	if axis, err = f.mergeCellsParser(ws, axis); err != nil {
+		return 0, err
+	}
+	col, row, err := CellNameToCoordinates(axis)
 	if err != nil {
 		return 0, err
 	}
-	return f.prepareCellStyle(ws, col, cellData.S), err
+	prepareSheetXML(ws, col, row)
+	return f.prepareCellStyle(ws, col, ws.SheetData.Row[row-1].C[col-1].S), err
+}
+
+// GetCellAlignment provides a function to get the alignment settings of the
+// cell style by given worksheet name and cell coordinates. The VerticalText
+// field will be true when the text of the cell is stacked vertically. For
+// example, get the alignment of cell A1 on Sheet1:
+//
+//    alignment, err := f.GetCellAlignment("Sheet1", "A1")
+//
+func (f *File) GetCellAlignment(sheet, axis string) (*Alignment, error) {
+	styleID, err := f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return nil, err
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		return nil, err
+	}
+	alignment := &Alignment{}
+	if s.CellXfs == nil || styleID >= len(s.CellXfs.Xf) || s.CellXfs.Xf[styleID].Alignment == nil {
+		return alignment, err
+	}
+	a := s.CellXfs.Xf[styleID].Alignment
+	alignment = &Alignment{
+		Horizontal:      a.Horizontal,
+		Indent:          a.Indent,
+		JustifyLastLine: a.JustifyLastLine,
+		ReadingOrder:    a.ReadingOrder,
+		RelativeIndent:  a.RelativeIndent,
+		ShrinkToFit:     a.ShrinkToFit,
+		TextRotation:    a.TextRotation,
+		Vertical:        a.Vertical,
+		VerticalText:    a.TextRotation == 255,
+		WrapText:        a.WrapText,
+	}
+	return alignment, err
 }
 
 // SetCellStyle provides a function to add style attribute for cells by given
@@ -2676,16 +2846,22 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 		vrow, hrow = hrow, vrow
 	}
 
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	coordinates, err := f.mergeCellsStylePolicy(ws, []int{hcol, hrow, vcol, vrow})
+	if err != nil {
+		return err
+	}
+	hcol, hrow, vcol, vrow = coordinates[0], coordinates[1], coordinates[2], coordinates[3]
+
 	hcolIdx := hcol - 1
 	hrowIdx := hrow - 1
 
 	vcolIdx := vcol - 1
 	vrowIdx := vrow - 1
 
-	ws, err := f.workSheetReader(sheet)
-	if err != nil {
-		return err
-	}
 	prepareSheetXML(ws, vcol, vrow)
 	makeContiguousColumns(ws, hrow, vrow, vcol)
 
@@ -2908,24 +3084,38 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //
 // bar_color - Used for data_bar. Same as min_color, see above.
 //
-func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
-	var format []*formatConditional
-	err := json.Unmarshal([]byte(formatSet), &format)
-	if err != nil {
-		return err
-	}
-	drawContFmtFunc := map[string]func(p int, ct string, fmtCond *formatConditional) *xlsxCfRule{
-		"cellIs":          drawCondFmtCellIs,
-		"top10":           drawCondFmtTop10,
-		"aboveAverage":    drawCondFmtAboveAverage,
-		"duplicateValues": drawCondFmtDuplicateUniqueValues,
-		"uniqueValues":    drawCondFmtDuplicateUniqueValues,
-		"2_color_scale":   drawCondFmtColorScale,
-		"3_color_scale":   drawCondFmtColorScale,
-		"dataBar":         drawCondFmtDataBar,
-		"expression":      drawConfFmtExp,
+// The format set can also be specified by the slice of the
+// ConditionalFormatOption structure. Unlike the JSON format string, which
+// skips the rules with unsupported type or criteria, the error will be
+// returned for the invalid rule, and the criteria is optional for the types
+// which don't compare the cell values, such as 2_color_scale and data_bar.
+// For example, highlight the cells greater than 6 in the range A1:A10:
+//
+//    err := f.SetConditionalFormat("Sheet1", "A1:A10", []excelize.ConditionalFormatOption{
+//        {Type: "cell", Criteria: ">", Format: format, Value: "6"},
+//    })
+//
+func (f *File) SetConditionalFormat(sheet, area string, formatSet interface{}) error {
+	var (
+		format    []*ConditionalFormatOption
+		validated bool
+	)
+	switch v := formatSet.(type) {
+	case string:
+		if err := json.Unmarshal([]byte(v), &format); err != nil {
+			return err
+		}
+	case []ConditionalFormatOption:
+		for i := range v {
+			if err := v[i].validate(); err != nil {
+				return err
+			}
+			format = append(format, &v[i])
+		}
+		validated = true
+	default:
+		return errors.New("invalid parameter type")
 	}
-
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
@@ -2939,7 +3129,7 @@ func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
 		if ok {
 			// Check for valid criteria types.
 			ct, ok = criteriaType[v.Criteria]
-			if ok || vt == "expression" {
+			if ok || vt == "expression" || validated {
 				drawfunc, ok := drawContFmtFunc[vt]
 				if ok {
 					cfRule = append(cfRule, drawfunc(p, ct, v))
@@ -2955,6 +3145,26 @@ func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
 	return err
 }
 
+// validate provides a function to check the conditional format settings
+// specified by the ConditionalFormatOption structure, the criteria is
+// optional for the types which don't compare the cell values.
+func (opt *ConditionalFormatOption) validate() error {
+	vt, ok := validType[opt.Type]
+	if _, supported := drawContFmtFunc[vt]; !ok || !supported {
+		return fmt.Errorf("unsupported conditional format type %s", opt.Type)
+	}
+	if vt == "expression" {
+		if opt.Criteria == "" {
+			return errors.New("parameter 'Criteria' is required")
+		}
+		return nil
+	}
+	if _, ok = criteriaType[opt.Criteria]; !ok && (opt.Criteria != "" || vt == "cellIs") {
+		return fmt.Errorf("unsupported conditional format criteria %s", opt.Criteria)
+	}
+	return nil
+}
+
 // UnsetConditionalFormat provides a function to unset the conditional format
 // by given worksheet name and range.
 func (f *File) UnsetConditionalFormat(sheet, area string) error {
@@ -2974,7 +3184,7 @@ func (f *File) UnsetConditionalFormat(sheet, area string) error {
 // drawCondFmtCellIs provides a function to create conditional formatting rule
 // for cell value (include between, not between, equal, not equal, greater
 // than and less than) by given priority, criteria type and format settings.
-func drawCondFmtCellIs(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtCellIs(p int, ct string, format *ConditionalFormatOption) *xlsxCfRule {
 	c := &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -2997,7 +3207,7 @@ func drawCondFmtCellIs(p int, ct string, format *formatConditional) *xlsxCfRule
 // drawCondFmtTop10 provides a function to create conditional formatting rule
 // for top N (default is top 10) by given priority, criteria type and format
 // settings.
-func drawCondFmtTop10(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtTop10(p int, ct string, format *ConditionalFormatOption) *xlsxCfRule {
 	c := &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -3015,7 +3225,7 @@ func drawCondFmtTop10(p int, ct string, format *formatConditional) *xlsxCfRule {
 // drawCondFmtAboveAverage provides a function to create conditional
 // formatting rule for above average and below average by given priority,
 // criteria type and format settings.
-func drawCondFmtAboveAverage(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtAboveAverage(p int, ct string, format *ConditionalFormatOption) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority:     p + 1,
 		Type:         validType[format.Type],
@@ -3027,7 +3237,7 @@ func drawCondFmtAboveAverage(p int, ct string, format *formatConditional) *xlsxC
 // drawCondFmtDuplicateUniqueValues provides a function to create conditional
 // formatting rule for duplicate and unique values by given priority, criteria
 // type and format settings.
-func drawCondFmtDuplicateUniqueValues(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtDuplicateUniqueValues(p int, ct string, format *ConditionalFormatOption) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -3038,7 +3248,7 @@ func drawCondFmtDuplicateUniqueValues(p int, ct string, format *formatConditiona
 // drawCondFmtColorScale provides a function to create conditional formatting
 // rule for color scale (include 2 color scale and 3 color scale) by given
 // priority, criteria type and format settings.
-func drawCondFmtColorScale(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtColorScale(p int, ct string, format *ConditionalFormatOption) *xlsxCfRule {
 	minValue := format.MinValue
 	if minValue == "" {
 		minValue = "0"
@@ -3075,7 +3285,7 @@ func drawCondFmtColorScale(p int, ct string, format *formatConditional) *xlsxCfR
 
 // drawCondFmtDataBar provides a function to create conditional formatting
 // rule for data bar by given priority, criteria type and format settings.
-func drawCondFmtDataBar(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawCondFmtDataBar(p int, ct string, format *ConditionalFormatOption) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -3088,7 +3298,7 @@ func drawCondFmtDataBar(p int, ct string, format *formatConditional) *xlsxCfRule
 
 // drawConfFmtExp provides a function to create conditional formatting rule
 // for expression by given priority, criteria type and format settings.
-func drawConfFmtExp(p int, ct string, format *formatConditional) *xlsxCfRule {
+func drawConfFmtExp(p int, ct string, format *ConditionalFormatOption) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
@@ -3105,16 +3315,79 @@ func getPaletteColor(color string) string {
 
 // themeReader provides a function to get the pointer to the xl/theme/theme1.xml
 // structure after deserialization.
-func (f *File) themeReader() *xlsxTheme {
-	var (
-		err   error
-		theme xlsxTheme
-	)
-	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/theme/theme1.xml")))).
+func (f *File) themeReader() (*xlsxTheme, error) {
+	var theme xlsxTheme
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/theme/theme1.xml")))).
 		Decode(&theme); err != nil && err != io.EOF {
-		log.Printf("xml decoder error: %s", err)
+		return &theme, f.newXMLDecodeError("xl/theme/theme1.xml", err)
+	}
+	return &theme, nil
+}
+
+// GetThemePalette provides a function to get the theme colors of the
+// workbook in ARGB format, the colors are ordered by theme color index which
+// used by the theme attribute of the cell styles:
+//
+//    Index | Theme color
+//   -------+--------------------------
+//    0     | Light 1 (Background 1)
+//    1     | Dark 1 (Text 1)
+//    2     | Light 2 (Background 2)
+//    3     | Dark 2 (Text 2)
+//    4-9   | Accent 1 - Accent 6
+//    10    | Hyperlink
+//    11    | Followed hyperlink
+//
+func (f *File) GetThemePalette() []string {
+	if f.Theme == nil {
+		f.Theme, _ = f.themeReader()
+	}
+	colors := map[string]string{}
+	for _, c := range f.Theme.ThemeElements.ClrScheme.Children {
+		var color string
+		if c.SrgbClr != nil && c.SrgbClr.Val != nil {
+			color = *c.SrgbClr.Val
+		}
+		if c.SysClr != nil {
+			color = c.SysClr.LastClr
+			if color == "" {
+				color = map[string]string{"window": "FFFFFF"}[c.SysClr.Val]
+			}
+			if color == "" {
+				color = "000000"
+			}
+		}
+		colors[c.XMLName.Local] = strings.ToUpper(color)
+	}
+	var palette []string
+	for _, name := range []string{"lt1", "dk1", "lt2", "dk2", "accent1", "accent2", "accent3", "accent4", "accent5", "accent6", "hlink", "folHlink"} {
+		color, ok := colors[name]
+		if !ok {
+			return palette
+		}
+		palette = append(palette, "FF"+color)
+	}
+	return palette
+}
+
+// GetThemeColor provides a function to resolve the theme color to ARGB
+// format by given theme color index and tint value. The index is same as the
+// theme attribute of the color in the cell styles, see GetThemePalette for
+// the theme colors of each index. The tint value should be between -1 and 1,
+// the negative value darkens the color and the positive value lightens the
+// color. For example, get the color of Accent 1, Lighter 40%:
+//
+//    color, err := f.GetThemeColor(4, 0.3999755851924192)
+//
+func (f *File) GetThemeColor(index int, tint float64) (string, error) {
+	if tint < -1 || tint > 1 {
+		return "", fmt.Errorf("invalid theme color tint %v", tint)
+	}
+	palette := f.GetThemePalette()
+	if index < 0 || index >= len(palette) {
+		return "", fmt.Errorf("invalid theme color index %d", index)
 	}
-	return &theme
+	return ThemeColor(palette[index][2:], tint), nil
 }
 
 // ThemeColor applied the color with tint value.
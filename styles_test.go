@@ -30,7 +30,8 @@ func TestStyleFill(t *testing.T) {
 		styleID, err := xl.NewStyle(testCase.format)
 		assert.NoError(t, err)
 
-		styles := xl.stylesReader()
+		styles, err := xl.stylesReader()
+		assert.NoError(t, err)
 		style := styles.CellXfs.Xf[styleID]
 		if testCase.expectFill {
 			assert.NotEqual(t, *style.FillID, 0, testCase.label)
@@ -175,6 +176,40 @@ func TestSetConditionalFormat(t *testing.T) {
 	}
 }
 
+func TestSetConditionalFormatWithStructure(t *testing.T) {
+	f := NewFile()
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"},"fill":{"type":"pattern","color":["#FEC7CE"],"pattern":1}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", []ConditionalFormatOption{
+		{Type: "cell", Criteria: ">", Format: format, Value: "6"},
+		{Type: "data_bar", MinType: "min", MaxType: "max", BarColor: "#638EC6"},
+		{Type: "formula", Criteria: "A1<3", Format: format},
+	}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting[0].CfRule, 3)
+	assert.Equal(t, "greaterThan", ws.ConditionalFormatting[0].CfRule[0].Operator)
+	assert.Equal(t, "dataBar", ws.ConditionalFormatting[0].CfRule[1].Type)
+	assert.Equal(t, []string{"A1<3"}, ws.ConditionalFormatting[0].CfRule[2].Formula)
+
+	// Test set conditional format with invalid settings.
+	for _, c := range []struct {
+		opt ConditionalFormatOption
+		err string
+	}{
+		{ConditionalFormatOption{Type: "cells", Criteria: ">"}, "unsupported conditional format type cells"},
+		{ConditionalFormatOption{Type: "text", Criteria: "containing"}, "unsupported conditional format type text"},
+		{ConditionalFormatOption{Type: "cell"}, "unsupported conditional format criteria "},
+		{ConditionalFormatOption{Type: "top", Criteria: "greater"}, "unsupported conditional format criteria greater"},
+		{ConditionalFormatOption{Type: "formula"}, "parameter 'Criteria' is required"},
+	} {
+		assert.EqualError(t, f.SetConditionalFormat("Sheet1", "B1:B10", []ConditionalFormatOption{c.opt}), c.err)
+	}
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "B1:B10", nil), "invalid parameter type")
+	assert.EqualError(t, f.SetConditionalFormat("SheetN", "B1:B10", []ConditionalFormatOption{}), "sheet SheetN is not exist")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetConditionalFormatWithStructure.xlsx")))
+}
+
 func TestUnsetConditionalFormat(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 7))
@@ -193,7 +228,8 @@ func TestNewStyle(t *testing.T) {
 	f := NewFile()
 	styleID, err := f.NewStyle(`{"font":{"bold":true,"italic":true,"family":"Times New Roman","size":36,"color":"#777777"}}`)
 	assert.NoError(t, err)
-	styles := f.stylesReader()
+	styles, err := f.stylesReader()
+	assert.NoError(t, err)
 	fontID := styles.CellXfs.Xf[styleID].FontID
 	font := styles.Fonts.Font[*fontID]
 	assert.Contains(t, *font.Name.Val, "Times New Roman", "Stored font should contain font name")
@@ -251,7 +287,8 @@ func TestGetDefaultFont(t *testing.T) {
 func TestSetDefaultFont(t *testing.T) {
 	f := NewFile()
 	f.SetDefaultFont("Ariel")
-	styles := f.stylesReader()
+	styles, err := f.stylesReader()
+	assert.NoError(t, err)
 	s := f.GetDefaultFont()
 	assert.Equal(t, s, "Ariel", "Default font should change to Ariel")
 	assert.Equal(t, *styles.CellStyles.CellStyle[0].CustomBuiltIn, true)
@@ -262,14 +299,18 @@ func TestStylesReader(t *testing.T) {
 	// Test read styles with unsupport charset.
 	f.Styles = nil
 	f.XLSX["xl/styles.xml"] = MacintoshCyrillicCharset
-	assert.EqualValues(t, new(xlsxStyleSheet), f.stylesReader())
+	styles, err := f.stylesReader()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	assert.EqualValues(t, new(xlsxStyleSheet), styles)
 }
 
 func TestThemeReader(t *testing.T) {
 	f := NewFile()
 	// Test read theme with unsupport charset.
 	f.XLSX["xl/theme/theme1.xml"] = MacintoshCyrillicCharset
-	assert.EqualValues(t, new(xlsxTheme), f.themeReader())
+	theme, err := f.themeReader()
+	assert.EqualError(t, err, "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	assert.EqualValues(t, new(xlsxTheme), theme)
 }
 
 func TestSetCellStyle(t *testing.T) {
@@ -283,18 +324,20 @@ func TestGetStyleID(t *testing.T) {
 }
 
 func TestGetFillID(t *testing.T) {
-	assert.Equal(t, -1, getFillID(NewFile().stylesReader(), &Style{Fill: Fill{Type: "unknown"}}))
+	styles, err := NewFile().stylesReader()
+	assert.NoError(t, err)
+	assert.Equal(t, -1, getFillID(styles, &Style{Fill: Fill{Type: "unknown"}}))
 }
 
 func TestParseTime(t *testing.T) {
-	assert.Equal(t, "2019", parseTime("43528", "YYYY"))
-	assert.Equal(t, "43528", parseTime("43528", ""))
-
-	assert.Equal(t, "2019-03-04 05:05:42", parseTime("43528.2123", "YYYY-MM-DD hh:mm:ss"))
-	assert.Equal(t, "2019-03-04 05:05:42", parseTime("43528.2123", "YYYY-MM-DD hh:mm:ss;YYYY-MM-DD hh:mm:ss"))
-	assert.Equal(t, "3/4/2019 5:5:42", parseTime("43528.2123", "M/D/YYYY h:m:s"))
-	assert.Equal(t, "March", parseTime("43528", "mmmm"))
-	assert.Equal(t, "Monday", parseTime("43528", "dddd"))
+	assert.Equal(t, "2019", parseTime("43528", "YYYY", false))
+	assert.Equal(t, "43528", parseTime("43528", "", false))
+
+	assert.Equal(t, "2019-03-04 05:05:42", parseTime("43528.2123", "YYYY-MM-DD hh:mm:ss", false))
+	assert.Equal(t, "2019-03-04 05:05:42", parseTime("43528.2123", "YYYY-MM-DD hh:mm:ss;YYYY-MM-DD hh:mm:ss", false))
+	assert.Equal(t, "3/4/2019 5:5:42", parseTime("43528.2123", "M/D/YYYY h:m:s", false))
+	assert.Equal(t, "March", parseTime("43528", "mmmm", false))
+	assert.Equal(t, "Monday", parseTime("43528", "dddd", false))
 }
 
 func TestThemeColor(t *testing.T) {
@@ -309,3 +352,57 @@ func TestThemeColor(t *testing.T) {
 		assert.Equal(t, clr[0], clr[1])
 	}
 }
+
+func TestGetThemeColor(t *testing.T) {
+	f := NewFile()
+	assert.Equal(t, []string{
+		"FFFFFFFF", "FF000000", "FFE7E6E6", "FF44546A", "FF5B9BD5", "FFED7D31",
+		"FFA5A5A5", "FFFFC000", "FF4472C4", "FF70AD47", "FF0563C1", "FF954F72",
+	}, f.GetThemePalette())
+	for _, c := range []struct {
+		index int
+		tint  float64
+		color string
+	}{
+		{0, 0, "FFFFFFFF"},
+		{0, -0.499984740745262, "FF808080"},
+		{1, 0.499984740745262, "FF7F7F7F"},
+		{4, 0.3999755851924192, "FF9DC3E6"},
+		{4, -0.249977111117893, "FF2E75B6"},
+	} {
+		color, err := f.GetThemeColor(c.index, c.tint)
+		assert.NoError(t, err)
+		assert.Equal(t, c.color, color)
+	}
+	_, err := f.GetThemeColor(12, 0)
+	assert.EqualError(t, err, "invalid theme color index 12")
+	_, err = f.GetThemeColor(-1, 0)
+	assert.EqualError(t, err, "invalid theme color index -1")
+	_, err = f.GetThemeColor(0, 1.5)
+	assert.EqualError(t, err, "invalid theme color tint 1.5")
+
+	// Test get theme palette with system colors without last color
+	f.Theme = nil
+	f.XLSX["xl/theme/theme1.xml"] = []byte(`<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:themeElements><a:clrScheme name="Office"><a:dk1><a:sysClr val="windowText"/></a:dk1><a:lt1><a:sysClr val="window"/></a:lt1></a:clrScheme></a:themeElements></a:theme>`)
+	assert.Equal(t, []string{"FFFFFFFF", "FF000000"}, f.GetThemePalette())
+	_, err = f.GetThemeColor(2, 0)
+	assert.EqualError(t, err, "invalid theme color index 2")
+}
+
+func TestGetCellAlignment(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "center", VerticalText: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	alignment, err := f.GetCellAlignment("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Alignment{Horizontal: "center", TextRotation: 255, VerticalText: true}, alignment)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetCellAlignment.xlsx")))
+	// Test get alignment of the cell without alignment settings
+	alignment, err = f.GetCellAlignment("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Alignment{}, alignment)
+	// Test get cell alignment on not exists worksheet
+	_, err = f.GetCellAlignment("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
@@ -12,9 +12,13 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -54,6 +58,36 @@ func parseFormatTableSet(formatSet string) (*formatTable, error) {
 //    TableStyleMedium1 - TableStyleMedium28
 //    TableStyleDark1 - TableStyleDark11
 //
+// totals_row: The settings of the totals row of the table, the totals row
+// will be added below the given coordinate area. Each setting specifies the
+// column name of the worksheet and one of the label, the function or the
+// custom formula of the column in the totals row. For example, create a table
+// of A1:C5 with the totals row in the row 6:
+//
+//    err := f.AddTable("Sheet1", "A1", "C5", `{"table_name":"Sales","totals_row":[{"column":"A","label":"Total"},{"column":"B","function":"sum"},{"column":"C","formula":"SUM(Sales[Price])/2"}]}`)
+//
+// The following functions are available for the totals row, the label will
+// be ignored if the function or the formula was specified:
+//
+//    average
+//    count
+//    countNums
+//    max
+//    min
+//    stdDev
+//    sum
+//    var
+//
+// calculated_columns: The settings of the calculated columns of the table,
+// each setting specifies the column name of the worksheet and the formula
+// which will be filled into each data row of the column. The structured
+// references of the current row such as [@Price] could be used in the
+// formula, and the formula will be filled into the new rows of the column
+// when the table is resized. For example, create a table of A1:D5 with the
+// calculated column D:
+//
+//    err := f.AddTable("Sheet1", "A1", "D5", `{"table_name":"Sales","calculated_columns":[{"column":"D","formula":"[@Price]*[@Quantity]"}]}`)
+//
 func (f *File) AddTable(sheet, hcell, vcell, format string) error {
 	formatSet, err := parseFormatTableSet(format)
 	if err != nil {
@@ -94,18 +128,344 @@ func (f *File) AddTable(sheet, hcell, vcell, format string) error {
 	return err
 }
 
-// countTables provides a function to get table files count storage in the
-// folder xl/tables.
+// GetTables provides the method to get the definitions of all tables in a
+// worksheet by given worksheet name. For example, get the tables on Sheet1:
+//
+//    tables, err := f.GetTables("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, table := range tables {
+//        fmt.Println(table.Name, table.Range, table.TableStyle)
+//    }
+//
+func (f *File) GetTables(sheet string) ([]Table, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var tables []Table
+	if ws.TableParts == nil {
+		return tables, err
+	}
+	for _, tablePart := range ws.TableParts.TableParts {
+		target := f.getSheetRelationshipsTargetByID(sheet, tablePart.RID)
+		if target == "" {
+			continue
+		}
+		t, err := f.tableReader(strings.Replace(target, "..", "xl", -1))
+		if err != nil {
+			return tables, err
+		}
+		table := Table{
+			Name:          t.Name,
+			Range:         t.Ref,
+			ShowHeaderRow: t.HeaderRowCount == nil || *t.HeaderRowCount > 0,
+			ShowTotalsRow: t.TotalsRowCount > 0,
+		}
+		if t.TableColumns != nil {
+			for _, col := range t.TableColumns.TableColumn {
+				column := TableColumn{
+					Name:              col.Name,
+					TotalsRowFunction: col.TotalsRowFunction,
+					TotalsRowLabel:    col.TotalsRowLabel,
+				}
+				if col.TotalsRowFormula != nil {
+					column.TotalsRowFormula = col.TotalsRowFormula.Content
+				}
+				if col.CalculatedColumnFormula != nil {
+					column.CalculatedColumnFormula = col.CalculatedColumnFormula.Content
+				}
+				table.Columns = append(table.Columns, column)
+			}
+		}
+		if t.TableStyleInfo != nil {
+			table.TableStyle = t.TableStyleInfo.Name
+			table.ShowFirstColumn = t.TableStyleInfo.ShowFirstColumn
+			table.ShowLastColumn = t.TableStyleInfo.ShowLastColumn
+			table.ShowRowStripes = t.TableStyleInfo.ShowRowStripes
+			table.ShowColumnStripes = t.TableStyleInfo.ShowColumnStripes
+		}
+		if t.AutoFilter != nil {
+			table.AutoFilter = t.AutoFilter.Ref
+		}
+		tables = append(tables, table)
+	}
+	return tables, err
+}
+
+// tableReader provides a function to get the pointer to the structure after
+// deserialization of the table part by given path.
+func (f *File) tableReader(path string) (*xlsxTable, error) {
+	t := xlsxTable{}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(&t); err != nil && err != io.EOF {
+		return &t, err
+	}
+	return &t, nil
+}
+
+// DeleteTable provides the method to delete the table by given table name,
+// the table part, the relationship of the worksheet to it and the tablePart
+// element of the worksheet will be removed, the cells of the table will be
+// kept. For example, delete the table named Table1:
+//
+//    err := f.DeleteTable("Table1")
+//
+func (f *File) DeleteTable(name string) error {
+	sheet, rID, tableXML, _, err := f.findTable(name)
+	if err != nil {
+		return err
+	}
+	ws, _ := f.workSheetReader(sheet)
+	for idx, tablePart := range ws.TableParts.TableParts {
+		if tablePart.RID == rID {
+			ws.TableParts.TableParts = append(ws.TableParts.TableParts[:idx], ws.TableParts.TableParts[idx+1:]...)
+			break
+		}
+	}
+	ws.TableParts.Count = len(ws.TableParts.TableParts)
+	if ws.TableParts.Count == 0 {
+		ws.TableParts = nil
+	}
+	f.deleteSheetRelationships(sheet, rID)
+	f.deletePartWithRels(tableXML)
+	return err
+}
+
+// ResizeTable provides the method to change the cell range of the table by
+// given table name and new cell range. The column definitions of the table
+// will be updated by the header row of the new range, the columns which
+// header names are unchanged will be kept, and the columns out of the new
+// range will be removed. For example, resize the table named Table1 to A1:F20:
+//
+//    err := f.ResizeTable("Table1", "A1:F20")
+//
+// Note that the header cells must contain strings and must be unique, the
+// empty header cells will be filled with the default column names.
+func (f *File) ResizeTable(name, rangeRef string) error {
+	if len(strings.Split(rangeRef, ":")) != 2 {
+		return fmt.Errorf("invalid table range %q", rangeRef)
+	}
+	coordinates, err := f.areaRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	sheet, _, tableXML, t, err := f.findTable(name)
+	if err != nil {
+		return err
+	}
+	header := t.HeaderRowCount == nil || *t.HeaderRowCount > 0
+	// Correct the minimum number of rows, the table at least two lines.
+	if header && coordinates[1] == coordinates[3] {
+		coordinates[3]++
+	}
+	lastRow := coordinates[1]
+	if oldCoordinates, err := f.areaRefToCoordinates(t.Ref); err == nil {
+		lastRow = oldCoordinates[3] - t.TotalsRowCount
+	}
+	if t.Ref, err = f.coordinatesToAreaRef(coordinates); err != nil {
+		return err
+	}
+	if t.AutoFilter != nil {
+		t.AutoFilter.Ref = t.Ref
+		// The auto filter of the table doesn't cover the totals row.
+		if t.TotalsRowCount > 0 && coordinates[3]-t.TotalsRowCount > coordinates[1] {
+			t.AutoFilter.Ref, _ = f.coordinatesToAreaRef([]int{coordinates[0], coordinates[1], coordinates[2], coordinates[3] - t.TotalsRowCount})
+		}
+	}
+	if t.TableColumns == nil {
+		t.TableColumns = &xlsxTableColumns{}
+	}
+	columns, nextID := map[string]*xlsxTableColumn{}, 0
+	for _, col := range t.TableColumns.TableColumn {
+		columns[col.Name] = col
+		if col.ID > nextID {
+			nextID = col.ID
+		}
+	}
+	var tableColumn []*xlsxTableColumn
+	for idx, col := 0, coordinates[0]; col <= coordinates[2]; idx, col = idx+1, col+1 {
+		var colName string
+		if header {
+			cell, _ := CoordinatesToCellName(col, coordinates[1])
+			colName, _ = f.GetCellValue(sheet, cell)
+			if _, err := strconv.Atoi(colName); err == nil {
+				_ = f.SetCellStr(sheet, cell, colName)
+			}
+			if colName == "" {
+				colName = "Column" + strconv.Itoa(idx+1)
+				_ = f.SetCellStr(sheet, cell, colName)
+			}
+		} else if idx < len(t.TableColumns.TableColumn) {
+			colName = t.TableColumns.TableColumn[idx].Name
+		} else {
+			colName = "Column" + strconv.Itoa(idx+1)
+		}
+		if column, ok := columns[colName]; ok {
+			delete(columns, colName)
+			tableColumn = append(tableColumn, column)
+			continue
+		}
+		nextID++
+		tableColumn = append(tableColumn, &xlsxTableColumn{ID: nextID, Name: colName})
+	}
+	t.TableColumns.Count, t.TableColumns.TableColumn = len(tableColumn), tableColumn
+	if err = f.fillTableCalculatedColumns(sheet, t, lastRow+1); err != nil {
+		return err
+	}
+	table, _ := xml.Marshal(t)
+	f.saveFileList(tableXML, table)
+	return err
+}
+
+// SetTableCalculatedColumn provides the method to set the formula of the
+// calculated column of the table by given table name, the column name of the
+// worksheet and the formula. The formula will be filled into each data row of
+// the column, and will be filled into the new rows of the column when the
+// table is resized. Set the formula as empty to remove the calculated column
+// formula, the cells of the column will be kept. For example, set the column
+// D of the table named Sales as the calculated column:
+//
+//    err := f.SetTableCalculatedColumn("Sales", "D", "[@Price]*[@Quantity]")
+//
+func (f *File) SetTableCalculatedColumn(name, column, formula string) error {
+	sheet, _, tableXML, t, err := f.findTable(name)
+	if err != nil {
+		return err
+	}
+	if err = f.setTableCalculatedColumn(sheet, t, column, formula); err != nil {
+		return err
+	}
+	table, _ := xml.Marshal(t)
+	f.saveFileList(tableXML, table)
+	return err
+}
+
+// setTableCalculatedColumn provides a function to set the calculated column
+// formula of the table column by given worksheet name, table, the column
+// name of the worksheet and formula, and fill the formula into each data row
+// of the column.
+func (f *File) setTableCalculatedColumn(sheet string, t *xlsxTable, column, formula string) error {
+	colNum, err := ColumnNameToNumber(column)
+	if err != nil {
+		return err
+	}
+	coordinates, err := f.areaRefToCoordinates(t.Ref)
+	if err != nil {
+		return err
+	}
+	offset := colNum - coordinates[0]
+	if t.TableColumns == nil || offset < 0 || offset >= len(t.TableColumns.TableColumn) {
+		return fmt.Errorf("incorrect index of column '%s'", column)
+	}
+	tableColumn := t.TableColumns.TableColumn[offset]
+	if formula = strings.TrimPrefix(formula, "="); formula == "" {
+		tableColumn.CalculatedColumnFormula = nil
+		return err
+	}
+	tableColumn.CalculatedColumnFormula = &xlsxTableFormula{Content: normalizeTableStructuredReferences(formula, func() string { return t.Name })}
+	return f.fillTableCalculatedColumns(sheet, t, coordinates[1])
+}
+
+// fillTableCalculatedColumns provides a function to fill the formulas of the
+// calculated columns of the table into the data rows from the given row to
+// the last data row of the table.
+func (f *File) fillTableCalculatedColumns(sheet string, t *xlsxTable, row int) error {
+	coordinates, err := f.areaRefToCoordinates(t.Ref)
+	if err != nil || t.TableColumns == nil {
+		return err
+	}
+	if header := t.HeaderRowCount == nil || *t.HeaderRowCount > 0; header && row <= coordinates[1] {
+		row = coordinates[1] + 1
+	}
+	for idx, column := range t.TableColumns.TableColumn {
+		if column.CalculatedColumnFormula == nil || column.CalculatedColumnFormula.Array {
+			continue
+		}
+		for r := row; r <= coordinates[3]-t.TotalsRowCount; r++ {
+			cell, err := CoordinatesToCellName(coordinates[0]+idx, r)
+			if err != nil {
+				return err
+			}
+			if err = f.SetCellFormula(sheet, cell, column.CalculatedColumnFormula.Content); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// findTable provides a function to get the worksheet name, the relationship
+// ID of the worksheet to the table, the table part path and the table by
+// given table name, the table name is case-insensitive.
+func (f *File) findTable(name string) (string, string, string, *xlsxTable, error) {
+	for _, sheet := range f.GetSheetList() {
+		ws, err := f.workSheetReader(sheet)
+		if err != nil || ws.TableParts == nil {
+			continue
+		}
+		for _, tablePart := range ws.TableParts.TableParts {
+			target := f.getSheetRelationshipsTargetByID(sheet, tablePart.RID)
+			if target == "" {
+				continue
+			}
+			tableXML := strings.Replace(target, "..", "xl", -1)
+			t, err := f.tableReader(tableXML)
+			if err != nil {
+				return "", "", "", nil, err
+			}
+			if strings.EqualFold(t.Name, name) {
+				return sheet, tablePart.RID, tableXML, t, nil
+			}
+		}
+	}
+	return "", "", "", nil, ErrTableNotExist{name}
+}
+
+// countTables provides a function to get the maximum index of the table files
+// storage in the folder xl/tables, so the new table will not overwrite the
+// existing ones after some tables have been deleted.
 func (f *File) countTables() int {
 	count := 0
 	for k := range f.XLSX {
-		if strings.Contains(k, "xl/tables/table") {
-			count++
+		if strings.HasPrefix(k, "xl/tables/table") {
+			if idx, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(k, "xl/tables/table"), ".xml")); err == nil && idx > count {
+				count = idx
+			}
 		}
 	}
 	return count
 }
 
+// getTableNames provides a function to get the lower case names and display
+// names of all tables in the spreadsheet, and the maximum ID of the tables.
+func (f *File) getTableNames() (map[string]bool, int, error) {
+	names, maxID, parts := map[string]bool{}, 0, map[string]bool{}
+	for name := range f.XLSX {
+		parts[name] = true
+	}
+	for name := range f.lazyParts {
+		parts[name] = true
+	}
+	for name := range parts {
+		if path.Dir(name) != "xl/tables" || path.Ext(name) != ".xml" {
+			continue
+		}
+		t, err := f.tableReader(name)
+		if err != nil {
+			return names, maxID, f.newXMLDecodeError(name, err)
+		}
+		names[strings.ToLower(t.Name)], names[strings.ToLower(t.DisplayName)] = true, true
+		if t.ID > maxID {
+			maxID = t.ID
+		}
+	}
+	return names, maxID, nil
+}
+
 // addSheetTable provides a function to add tablePart element to
 // xl/worksheets/sheet%d.xml by given worksheet name and relationship index.
 func (f *File) addSheetTable(sheet string, rID int) error {
@@ -185,11 +545,435 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, formatSet
 			ShowColumnStripes: formatSet.ShowColumnStripes,
 		},
 	}
+	if len(formatSet.TotalsRow) > 0 {
+		if err = f.setTableTotalsRow(sheet, &t, x1, y2+1, formatSet.TotalsRow); err != nil {
+			return err
+		}
+	}
+	for _, calculated := range formatSet.CalculatedColumns {
+		if err = f.setTableCalculatedColumn(sheet, &t, calculated.Column, calculated.Formula); err != nil {
+			return err
+		}
+	}
 	table, _ := xml.Marshal(t)
 	f.saveFileList(tableXML, table)
 	return nil
 }
 
+// tableTotalsRowFunctions defined the function numbers of the SUBTOTAL
+// function for the totals row functions of the table.
+var tableTotalsRowFunctions = map[string]int{
+	"average":   101,
+	"count":     103,
+	"countNums": 102,
+	"max":       104,
+	"min":       105,
+	"stdDev":    107,
+	"sum":       109,
+	"var":       110,
+}
+
+// setTableTotalsRow provides a function to set the totals row of the table in
+// the given row by given worksheet name, table, the first column number of the
+// table and totals row settings. The label or formula of each column will be
+// written into the cells of the totals row.
+func (f *File) setTableTotalsRow(sheet string, t *xlsxTable, col, row int, totals []formatTableTotal) error {
+	for _, total := range totals {
+		colNum, err := ColumnNameToNumber(total.Column)
+		if err != nil {
+			return err
+		}
+		offset := colNum - col
+		if offset < 0 || offset >= len(t.TableColumns.TableColumn) {
+			return fmt.Errorf("incorrect index of column '%s'", total.Column)
+		}
+		column := t.TableColumns.TableColumn[offset]
+		cell, _ := CoordinatesToCellName(colNum, row)
+		if total.Formula != "" {
+			column.TotalsRowFunction = "custom"
+			column.TotalsRowFormula = &xlsxTableFormula{Content: strings.TrimPrefix(total.Formula, "=")}
+			if err = f.SetCellFormula(sheet, cell, column.TotalsRowFormula.Content); err != nil {
+				return err
+			}
+			continue
+		}
+		if total.Function != "" {
+			var function string
+			for name := range tableTotalsRowFunctions {
+				if strings.EqualFold(name, total.Function) {
+					function = name
+				}
+			}
+			if function == "" {
+				return fmt.Errorf("unsupported totals row function %q", total.Function)
+			}
+			column.TotalsRowFunction = function
+			formula := fmt.Sprintf("SUBTOTAL(%d,%s[%s])", tableTotalsRowFunctions[function], t.Name, escapeStructuredReference(column.Name))
+			if err = f.SetCellFormula(sheet, cell, formula); err != nil {
+				return err
+			}
+			continue
+		}
+		column.TotalsRowLabel = total.Label
+		if err = f.SetCellStr(sheet, cell, total.Label); err != nil {
+			return err
+		}
+	}
+	lastCell, err := CoordinatesToCellName(col+len(t.TableColumns.TableColumn)-1, row)
+	if err != nil {
+		return err
+	}
+	t.Ref = strings.Split(t.Ref, ":")[0] + ":" + lastCell
+	t.TotalsRowCount, t.TotalsRowShown = 1, true
+	return err
+}
+
+// escapeStructuredReference provides a function to escape the special
+// characters in the column name of the structured reference of the table.
+func escapeStructuredReference(name string) string {
+	return strings.NewReplacer("'", "''", "[", "'[", "]", "']", "#", "'#").Replace(name)
+}
+
+// structuredReference directly maps the parsed specifier of the structured
+// reference of the table, such as Sales[[#Headers],[Amount]:[Price]]. The
+// items are the lower case special items of the specifier, and the From and
+// To are the names of the first and last column of the specifier.
+type structuredReference struct {
+	items    []string
+	from, to string
+}
+
+// scanStructuredReferences provides a function to find the structured
+// references in the formula and replace them with the result of the given
+// function by the table name and the specifier of each reference. The table
+// name will be empty if the structured reference is used in the table
+// without table name. The string literals, quoted sheet names and external
+// workbook references will be kept. The #REF! error will be returned if the
+// bracket of the structured reference is not closed.
+func scanStructuredReferences(formula string, fn func(table, spec string) (string, error)) (string, error) {
+	out := make([]byte, 0, len(formula))
+	nameStart, inString := -1, false
+	for i := 0; i < len(formula); i++ {
+		c := formula[i]
+		if inString {
+			out = append(out, c)
+			inString = c != '"'
+			continue
+		}
+		switch {
+		case c == '"':
+			inString, nameStart = true, -1
+			out = append(out, c)
+		case c == '\'':
+			j := i + 1
+			for ; j < len(formula); j++ {
+				if formula[j] == '\'' {
+					if j+1 < len(formula) && formula[j+1] == '\'' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(formula) {
+				j = len(formula) - 1
+			}
+			out, i, nameStart = append(out, formula[i:j+1]...), j, -1
+		case c == '[':
+			end := matchStructuredReferenceBracket(formula, i)
+			if end == -1 {
+				return formula, errors.New(formulaErrorREF)
+			}
+			var name string
+			if nameStart != -1 {
+				name = formula[nameStart:i]
+			}
+			if name == "" && end+1 < len(formula) && (isTableNameChar(formula[end+1]) || formula[end+1] == '!') {
+				out, i = append(out, formula[i:end+1]...), end
+				continue
+			}
+			ref, err := fn(name, formula[i+1:end])
+			if err != nil {
+				return formula, err
+			}
+			out, i, nameStart = append(out[:len(out)-len(name)], ref...), end, -1
+		default:
+			if !isTableNameChar(c) {
+				nameStart = -1
+			} else if nameStart == -1 {
+				nameStart = i
+			}
+			out = append(out, c)
+		}
+	}
+	return string(out), nil
+}
+
+// isTableNameChar provides a function to check if the byte can be used in
+// the table name, the bytes of multi-byte characters are always accepted.
+func isTableNameChar(c byte) bool {
+	return c >= 0x80 || c == '_' || c == '.' || c == '\\' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// matchStructuredReferenceBracket provides a function to get the index of
+// the bracket which closes the bracket at the given index of the structured
+// reference, the character after the escape character ' will be skipped. It
+// returns -1 if the bracket is not closed.
+func matchStructuredReferenceBracket(s string, start int) int {
+	var depth int
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			i++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// unescapeStructuredReference provides a function to remove the escape
+// characters in the column name of the structured reference of the table.
+func unescapeStructuredReference(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\'' && i+1 < len(name) {
+			i++
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
+
+// parseStructuredReference provides a function to parse the specifier of the
+// structured reference, such as #Data, @Amount, Amount or
+// [#This Row],[Amount]:[Price].
+func parseStructuredReference(spec string) (*structuredReference, error) {
+	ref, spec := &structuredReference{}, strings.TrimSpace(spec)
+	addItem := func(item string, isRange bool) {
+		item = strings.TrimSpace(item)
+		switch {
+		case strings.HasPrefix(item, "#"):
+			ref.items = append(ref.items, strings.ToLower(item))
+		case isRange:
+			ref.to = unescapeStructuredReference(item)
+		case item != "":
+			ref.from = unescapeStructuredReference(item)
+			ref.to = ref.from
+		}
+	}
+	if strings.HasPrefix(spec, "@") {
+		ref.items, spec = append(ref.items, "#this row"), strings.TrimSpace(spec[1:])
+	}
+	if !strings.HasPrefix(spec, "[") {
+		addItem(spec, false)
+		return ref, nil
+	}
+	for i, isRange := 0, false; i < len(spec); i++ {
+		switch spec[i] {
+		case ' ', ',':
+		case ':':
+			isRange = true
+		case '[':
+			end := matchStructuredReferenceBracket(spec, i)
+			if end == -1 {
+				return ref, errors.New(formulaErrorREF)
+			}
+			addItem(spec[i+1:end], isRange)
+			i, isRange = end, false
+		default:
+			return ref, errors.New(formulaErrorREF)
+		}
+	}
+	return ref, nil
+}
+
+// findTableByCell provides a function to get the table which contains the
+// cell by given worksheet name and cell name.
+func (f *File) findTableByCell(sheet, cell string) (*xlsxTable, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.TableParts == nil {
+		return nil, err
+	}
+	for _, tablePart := range ws.TableParts.TableParts {
+		target := f.getSheetRelationshipsTargetByID(sheet, tablePart.RID)
+		if target == "" {
+			continue
+		}
+		t, err := f.tableReader(strings.Replace(target, "..", "xl", -1))
+		if err != nil {
+			return nil, err
+		}
+		if coordinates, err := f.areaRefToCoordinates(t.Ref); err == nil &&
+			coordinates[0] <= col && col <= coordinates[2] && coordinates[1] <= row && row <= coordinates[3] {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+// normalizeStructuredReferences provides a function to convert the this row
+// shorthand @ in the structured references of the formula to the
+// [#This Row] specifier which used in the file format, and add the table name
+// to the structured references without table name in the table which
+// contains the given cell, such as [@Amount] to Sales[[#This Row],[Amount]].
+func (f *File) normalizeStructuredReferences(sheet, cell, formula string) string {
+	if !strings.Contains(formula, "[") {
+		return formula
+	}
+	return normalizeTableStructuredReferences(formula, func() string {
+		if t, _ := f.findTableByCell(sheet, cell); t != nil {
+			return t.Name
+		}
+		return ""
+	})
+}
+
+// normalizeTableStructuredReferences provides a function to convert the this
+// row shorthand @ in the structured references of the formula to the
+// [#This Row] specifier, and add the table name which returned by the given
+// function to the structured references without table name.
+func normalizeTableStructuredReferences(formula string, tableName func() string) string {
+	if !strings.Contains(formula, "[") {
+		return formula
+	}
+	result, _ := scanStructuredReferences(formula, func(table, spec string) (string, error) {
+		if table == "" {
+			table = tableName()
+		}
+		if spec = strings.TrimSpace(spec); strings.HasPrefix(spec, "@") {
+			switch rest := strings.TrimSpace(spec[1:]); {
+			case rest == "":
+				spec = "#This Row"
+			case strings.HasPrefix(rest, "["):
+				spec = "[#This Row]," + rest
+			default:
+				spec = "[#This Row],[" + rest + "]"
+			}
+		}
+		return table + "[" + spec + "]", nil
+	})
+	return result
+}
+
+// resolveStructuredReferences provides a function to replace the structured
+// references in the formula of the given cell with the cell references, such
+// as Sales[Amount] to 'Sheet1'!B2:B5. The structured references are resolved
+// by the current definitions of the tables, so they are still valid after the
+// tables have been resized.
+func (f *File) resolveStructuredReferences(sheet, cell, formula string) (string, error) {
+	if !strings.Contains(formula, "[") {
+		return formula, nil
+	}
+	return scanStructuredReferences(formula, func(table, spec string) (string, error) {
+		var (
+			t        *xlsxTable
+			refSheet = sheet
+			err      error
+		)
+		if table == "" {
+			t, err = f.findTableByCell(sheet, cell)
+		} else {
+			refSheet, _, _, t, err = f.findTable(table)
+		}
+		if err != nil || t == nil {
+			return "", errors.New(formulaErrorREF)
+		}
+		ref, err := parseStructuredReference(spec)
+		if err != nil {
+			return "", err
+		}
+		return f.structuredReferenceToArea(t, ref, refSheet, sheet, cell)
+	})
+}
+
+// structuredReferenceToArea provides a function to convert the parsed
+// structured reference of the table on the given worksheet to the cell
+// reference, the #This Row specifier will be resolved by the row of the
+// given cell.
+func (f *File) structuredReferenceToArea(t *xlsxTable, ref *structuredReference, tableSheet, sheet, cell string) (string, error) {
+	if len(strings.Split(t.Ref, ":")) != 2 {
+		return "", errors.New(formulaErrorREF)
+	}
+	coordinates, err := f.areaRefToCoordinates(t.Ref)
+	if err != nil {
+		return "", errors.New(formulaErrorREF)
+	}
+	headers, totals := 1, t.TotalsRowCount
+	if t.HeaderRowCount != nil {
+		headers = *t.HeaderRowCount
+	}
+	if len(ref.items) == 0 {
+		ref.items = []string{"#data"}
+	}
+	fromRow, toRow := 0, 0
+	for _, item := range ref.items {
+		var from, to int
+		switch item {
+		case "#all":
+			from, to = coordinates[1], coordinates[3]
+		case "#data":
+			from, to = coordinates[1]+headers, coordinates[3]-totals
+		case "#headers":
+			from, to = coordinates[1], coordinates[1]+headers-1
+		case "#totals":
+			from, to = coordinates[3]-totals+1, coordinates[3]
+		case "#this row":
+			_, row, _ := CellNameToCoordinates(cell)
+			if sheet != tableSheet || row < coordinates[1]+headers || row > coordinates[3]-totals {
+				return "", errors.New(formulaErrorVALUE)
+			}
+			from, to = row, row
+		default:
+			return "", errors.New(formulaErrorREF)
+		}
+		if from > to {
+			return "", errors.New(formulaErrorREF)
+		}
+		if fromRow == 0 || from < fromRow {
+			fromRow = from
+		}
+		if to > toRow {
+			toRow = to
+		}
+	}
+	fromCol, toCol := coordinates[0], coordinates[2]
+	if ref.from != "" {
+		if fromCol, toCol = -1, -1; t.TableColumns != nil {
+			for idx, column := range t.TableColumns.TableColumn {
+				if strings.EqualFold(column.Name, ref.from) {
+					fromCol = coordinates[0] + idx
+				}
+				if strings.EqualFold(column.Name, ref.to) {
+					toCol = coordinates[0] + idx
+				}
+			}
+		}
+		if fromCol == -1 || toCol == -1 {
+			return "", errors.New(formulaErrorREF)
+		}
+	}
+	area := []int{fromCol, fromRow, toCol, toRow}
+	_ = sortCoordinates(area)
+	result, err := f.coordinatesToAreaRef(area)
+	if err != nil {
+		return "", errors.New(formulaErrorREF)
+	}
+	if area[0] == area[2] && area[1] == area[3] {
+		result = strings.Split(result, ":")[0]
+	}
+	return "'" + strings.Replace(tableSheet, "'", "''", -1) + "'!" + result, nil
+}
+
 // parseAutoFilterSet provides a function to parse the settings of the auto
 // filter.
 func parseAutoFilterSet(formatSet string) (*formatAutoFilter, error) {
@@ -214,8 +998,11 @@ func parseAutoFilterSet(formatSet string) (*formatAutoFilter, error) {
 //
 // It isn't sufficient to just specify the filter condition. You must also
 // hide any rows that don't match the filter condition. Rows are hidden using
-// the SetRowVisible() method. Excelize can't filter rows automatically since
-// this isn't part of the file format.
+// the SetRowVisible() method, or set hide_rows to evaluate the filter
+// criteria by the cell values and hide the rows which don't match it, the
+// rows which match it will be shown. For example:
+//
+//    err := f.AutoFilter("Sheet1", "A1", "D4", `{"column":"B","expression":"x > 2000","hide_rows":true}`)
 //
 // Setting a filter criteria for a column:
 //
@@ -289,8 +1076,18 @@ func (f *File) AutoFilter(sheet, hcell, vcell, format string) error {
 	formatSet, _ := parseAutoFilterSet(format)
 	cellStart, _ := CoordinatesToCellName(hcol, hrow)
 	cellEnd, _ := CoordinatesToCellName(vcol, vrow)
-	ref, filterDB := cellStart+":"+cellEnd, "_xlnm._FilterDatabase"
-	wb := f.workbookReader()
+	ref := cellStart + ":" + cellEnd
+	f.setFilterDatabase(sheet, ref)
+	refRange := vcol - hcol
+	return f.autoFilter(sheet, ref, refRange, hcol, formatSet)
+}
+
+// setFilterDatabase provides a function to set the hidden defined name
+// _xlnm._FilterDatabase of the worksheet which refers to the auto filter
+// range by given worksheet name and the cell range.
+func (f *File) setFilterDatabase(sheet, ref string) {
+	filterDB := "_xlnm._FilterDatabase"
+	wb, _ := f.workbookReader()
 	sheetID := f.GetSheetIndex(sheet)
 	filterRange := fmt.Sprintf("%s!%s", sheet, ref)
 	d := xlsxDefinedName{
@@ -316,8 +1113,155 @@ func (f *File) AutoFilter(sheet, hcell, vcell, format string) error {
 			wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, d)
 		}
 	}
-	refRange := vcol - hcol
-	return f.autoFilter(sheet, ref, refRange, hcol, formatSet)
+}
+
+// GetAutoFilter provides the method to get the settings of the auto filter
+// in a worksheet by given worksheet name, it returns nil if the worksheet has
+// no auto filter. The settings can be modified and set back by
+// SetAutoFilter. For example, remove the criteria of the column B of the auto
+// filter on Sheet1:
+//
+//    opt, err := f.GetAutoFilter("Sheet1")
+//    if err != nil || opt == nil {
+//        return
+//    }
+//    var columns []excelize.AutoFilterColumn
+//    for _, column := range opt.Columns {
+//        if column.Column != "B" {
+//            columns = append(columns, column)
+//        }
+//    }
+//    opt.Columns = columns
+//    err = f.SetAutoFilter("Sheet1", *opt)
+//
+func (f *File) GetAutoFilter(sheet string) (*AutoFilterOption, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.AutoFilter == nil {
+		return nil, err
+	}
+	col, _, err := CellNameToCoordinates(strings.Split(ws.AutoFilter.Ref, ":")[0])
+	if err != nil {
+		return nil, err
+	}
+	opt := &AutoFilterOption{
+		Range:      ws.AutoFilter.Ref,
+		FilterMode: ws.SheetPr != nil && ws.SheetPr.FilterMode,
+	}
+	for _, filterColumn := range ws.AutoFilter.FilterColumn {
+		column := AutoFilterColumn{}
+		if column.Column, err = ColumnNumberToName(col + filterColumn.ColID); err != nil {
+			return opt, err
+		}
+		if filters := filterColumn.Filters; filters != nil {
+			column.FilterBlank = filters.Blank
+			for _, filter := range filters.Filter {
+				column.Filters = append(column.Filters, filter.Val)
+			}
+		}
+		if customFilters := filterColumn.CustomFilters; customFilters != nil {
+			column.CustomFiltersAnd = customFilters.And
+			for _, customFilter := range customFilters.CustomFilter {
+				operator := customFilter.Operator
+				if operator == "" {
+					operator = "equal"
+				}
+				column.CustomFilters = append(column.CustomFilters, AutoFilterCustomFilter{Operator: operator, Val: customFilter.Val})
+			}
+		}
+		if dynamicFilter := filterColumn.DynamicFilter; dynamicFilter != nil {
+			column.DynamicFilter = &AutoFilterDynamicFilter{
+				Type: dynamicFilter.Type, Val: dynamicFilter.Val, ValISO: dynamicFilter.ValISO, MaxValISO: dynamicFilter.MaxValISO,
+			}
+		}
+		if top10 := filterColumn.Top10; top10 != nil {
+			column.Top10 = &AutoFilterTop10{Top: top10.Top, Percent: top10.Percent, Val: top10.Val, FilterVal: top10.FilterVal}
+		}
+		if colorFilter := filterColumn.ColorFilter; colorFilter != nil {
+			column.ColorFilter = &AutoFilterColorFilter{CellColor: colorFilter.CellColor, DxfID: colorFilter.DxfID}
+		}
+		if iconFilter := filterColumn.IconFilter; iconFilter != nil {
+			column.IconFilter = &AutoFilterIconFilter{IconSet: iconFilter.IconSet, IconID: iconFilter.IconID}
+		}
+		opt.Columns = append(opt.Columns, column)
+	}
+	return opt, err
+}
+
+// SetAutoFilter provides the method to set the auto filter in a worksheet by
+// given worksheet name and the settings of the auto filter, the existing
+// auto filter of the worksheet will be replaced. The column of each filter
+// criteria must be in the range of the auto filter, and the rows will not be
+// hidden or shown by the criteria. For example, filter the column B of
+// A1:D10 on Sheet1 by the top 3 items:
+//
+//    err := f.SetAutoFilter("Sheet1", excelize.AutoFilterOption{
+//        Range:      "A1:D10",
+//        FilterMode: true,
+//        Columns: []excelize.AutoFilterColumn{
+//            {Column: "B", Top10: &excelize.AutoFilterTop10{Top: true, Val: 3}},
+//        },
+//    })
+//
+func (f *File) SetAutoFilter(sheet string, opt AutoFilterOption) error {
+	if len(strings.Split(opt.Range, ":")) != 2 {
+		return fmt.Errorf("invalid auto filter range %q", opt.Range)
+	}
+	coordinates, err := f.areaRefToCoordinates(opt.Range)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	ref, _ := f.coordinatesToAreaRef(coordinates)
+	filter := &xlsxAutoFilter{Ref: ref}
+	for _, column := range opt.Columns {
+		col, err := ColumnNameToNumber(column.Column)
+		if err != nil {
+			return err
+		}
+		if col < coordinates[0] || col > coordinates[2] {
+			return fmt.Errorf("incorrect index of column '%s'", column.Column)
+		}
+		filterColumn := &xlsxFilterColumn{ColID: col - coordinates[0]}
+		if len(column.Filters) > 0 || column.FilterBlank {
+			filterColumn.Filters = &xlsxFilters{Blank: column.FilterBlank}
+			for _, val := range column.Filters {
+				filterColumn.Filters.Filter = append(filterColumn.Filters.Filter, &xlsxFilter{Val: val})
+			}
+		}
+		if len(column.CustomFilters) > 0 {
+			filterColumn.CustomFilters = &xlsxCustomFilters{And: column.CustomFiltersAnd}
+			for _, customFilter := range column.CustomFilters {
+				filterColumn.CustomFilters.CustomFilter = append(filterColumn.CustomFilters.CustomFilter,
+					&xlsxCustomFilter{Operator: customFilter.Operator, Val: customFilter.Val})
+			}
+		}
+		if dynamicFilter := column.DynamicFilter; dynamicFilter != nil {
+			filterColumn.DynamicFilter = &xlsxDynamicFilter{
+				Type: dynamicFilter.Type, Val: dynamicFilter.Val, ValISO: dynamicFilter.ValISO, MaxValISO: dynamicFilter.MaxValISO,
+			}
+		}
+		if top10 := column.Top10; top10 != nil {
+			filterColumn.Top10 = &xlsxTop10{Top: top10.Top, Percent: top10.Percent, Val: top10.Val, FilterVal: top10.FilterVal}
+		}
+		if colorFilter := column.ColorFilter; colorFilter != nil {
+			filterColumn.ColorFilter = &xlsxColorFilter{CellColor: colorFilter.CellColor, DxfID: colorFilter.DxfID}
+		}
+		if iconFilter := column.IconFilter; iconFilter != nil {
+			filterColumn.IconFilter = &xlsxIconFilter{IconSet: iconFilter.IconSet, IconID: iconFilter.IconID}
+		}
+		filter.FilterColumn = append(filter.FilterColumn, filterColumn)
+	}
+	f.setFilterDatabase(sheet, ref)
+	ws.AutoFilter = filter
+	if ws.SheetPr == nil {
+		ws.SheetPr = &xlsxSheetPr{}
+	}
+	ws.SheetPr.FilterMode = opt.FilterMode
+	return err
 }
 
 // autoFilter provides a function to extract the tokens from the filter
@@ -336,6 +1280,9 @@ func (f *File) autoFilter(sheet, ref string, refRange, col int, formatSet *forma
 	}
 	ws.AutoFilter = filter
 	if formatSet.Column == "" || formatSet.Expression == "" {
+		if formatSet.HideRows {
+			return f.hideFilteredRows(sheet, filter, col)
+		}
 		return nil
 	}
 
@@ -348,9 +1295,9 @@ func (f *File) autoFilter(sheet, ref string, refRange, col int, formatSet *forma
 		return fmt.Errorf("incorrect index of column '%s'", formatSet.Column)
 	}
 
-	filter.FilterColumn = &xlsxFilterColumn{
+	filter.FilterColumn = []*xlsxFilterColumn{{
 		ColID: offset,
-	}
+	}}
 	re := regexp.MustCompile(`"(?:[^"]|"")*"|\S+`)
 	token := re.FindAllString(formatSet.Expression, -1)
 	if len(token) != 3 && len(token) != 7 {
@@ -362,9 +1309,144 @@ func (f *File) autoFilter(sheet, ref string, refRange, col int, formatSet *forma
 	}
 	f.writeAutoFilter(filter, expressions, tokens)
 	ws.AutoFilter = filter
+	if formatSet.HideRows {
+		return f.hideFilteredRows(sheet, filter, col)
+	}
 	return nil
 }
 
+// hideFilteredRows provides a function to hide the rows which don't match the
+// criteria of the filter column and show the rows which match it in the data
+// range of the auto filter by given worksheet name, auto filter and the first
+// column number of the auto filter range. The row will be shown if it
+// matches the criteria of all filter columns.
+func (f *File) hideFilteredRows(sheet string, filter *xlsxAutoFilter, col int) error {
+	coordinates, err := f.areaRefToCoordinates(filter.Ref)
+	if err != nil {
+		return err
+	}
+	for row := coordinates[1] + 1; row <= coordinates[3]; row++ {
+		visible := true
+		for _, filterColumn := range filter.FilterColumn {
+			cell, err := CoordinatesToCellName(col+filterColumn.ColID, row)
+			if err != nil {
+				return err
+			}
+			value, err := f.GetCellValue(sheet, cell)
+			if err != nil {
+				return err
+			}
+			visible = visible && matchFilterColumn(filterColumn, value)
+		}
+		if err = f.SetRowVisible(sheet, row, visible); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// matchFilterColumn provides a function to check if the cell value matches
+// the filters or custom filters of the filter column.
+func matchFilterColumn(filterColumn *xlsxFilterColumn, value string) bool {
+	if filterColumn.Filters != nil {
+		if filterColumn.Filters.Blank && strings.TrimSpace(value) == "" {
+			return true
+		}
+		for _, filter := range filterColumn.Filters.Filter {
+			if matchFilterCriteria("equal", filter.Val, value) {
+				return true
+			}
+		}
+		return false
+	}
+	if filterColumn.CustomFilters == nil {
+		return true
+	}
+	var matched bool
+	for idx, customFilter := range filterColumn.CustomFilters.CustomFilter {
+		result := matchFilterCriteria(customFilter.Operator, customFilter.Val, value)
+		switch {
+		case idx == 0:
+			matched = result
+		case filterColumn.CustomFilters.And:
+			matched = matched && result
+		default:
+			matched = matched || result
+		}
+	}
+	return matched
+}
+
+// matchFilterCriteria provides a function to check if the cell value matches
+// the criteria by given operator of the custom filter and criteria value. The
+// numbers will be compared numerically and the text will be compared case
+// insensitively, the wildcard characters can be used in the equal and not
+// equal criteria.
+func matchFilterCriteria(operator, criteria, value string) bool {
+	if len(criteria) > 1 && strings.HasPrefix(criteria, `"`) && strings.HasSuffix(criteria, `"`) {
+		criteria = strings.Replace(criteria[1:len(criteria)-1], `""`, `"`, -1)
+	}
+	if criteria == " " || strings.EqualFold(criteria, "blanks") {
+		blank := strings.TrimSpace(value) == ""
+		return blank == (operator == "equal")
+	}
+	var cmp int
+	criteriaNum, criteriaErr := strconv.ParseFloat(criteria, 64)
+	valueNum, valueErr := strconv.ParseFloat(value, 64)
+	switch {
+	case criteriaErr == nil && valueErr == nil:
+		if valueNum < criteriaNum {
+			cmp = -1
+		} else if valueNum > criteriaNum {
+			cmp = 1
+		}
+	case operator == "equal" || operator == "notEqual":
+		if matchFilterWildcard(criteria, value) {
+			return operator == "equal"
+		}
+		return operator == "notEqual"
+	default:
+		cmp = strings.Compare(strings.ToLower(value), strings.ToLower(criteria))
+	}
+	switch operator {
+	case "lessThan":
+		return cmp < 0
+	case "lessThanOrEqual":
+		return cmp <= 0
+	case "greaterThan":
+		return cmp > 0
+	case "greaterThanOrEqual":
+		return cmp >= 0
+	case "notEqual":
+		return cmp != 0
+	}
+	return cmp == 0
+}
+
+// matchFilterWildcard provides a function to check if the text matches the
+// criteria with the wildcard characters case insensitively, the * matches
+// any characters, the ? matches any single character and the ~ escapes the
+// next character.
+func matchFilterWildcard(criteria, text string) bool {
+	var pattern strings.Builder
+	pattern.WriteString("(?is)^")
+	for i := 0; i < len(criteria); i++ {
+		switch c := criteria[i]; {
+		case c == '~' && i+1 < len(criteria):
+			i++
+			pattern.WriteString(regexp.QuoteMeta(criteria[i : i+1]))
+		case c == '*':
+			pattern.WriteString(".*")
+		case c == '?':
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(criteria[i : i+1]))
+		}
+	}
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String()).MatchString(text)
+}
+
 // writeAutoFilter provides a function to check for single or double custom
 // filters as default filters and handle them accordingly.
 func (f *File) writeAutoFilter(filter *xlsxAutoFilter, exp []int, tokens []string) {
@@ -372,14 +1454,14 @@ func (f *File) writeAutoFilter(filter *xlsxAutoFilter, exp []int, tokens []strin
 		// Single equality.
 		var filters []*xlsxFilter
 		filters = append(filters, &xlsxFilter{Val: tokens[0]})
-		filter.FilterColumn.Filters = &xlsxFilters{Filter: filters}
+		filter.FilterColumn[0].Filters = &xlsxFilters{Filter: filters}
 	} else if len(exp) == 3 && exp[0] == 2 && exp[1] == 1 && exp[2] == 2 {
 		// Double equality with "or" operator.
 		filters := []*xlsxFilter{}
 		for _, v := range tokens {
 			filters = append(filters, &xlsxFilter{Val: v})
 		}
-		filter.FilterColumn.Filters = &xlsxFilters{Filter: filters}
+		filter.FilterColumn[0].Filters = &xlsxFilters{Filter: filters}
 	} else {
 		// Non default custom filter.
 		expRel := map[int]int{0: 0, 1: 2}
@@ -387,7 +1469,7 @@ func (f *File) writeAutoFilter(filter *xlsxAutoFilter, exp []int, tokens []strin
 		for k, v := range tokens {
 			f.writeCustomFilter(filter, exp[expRel[k]], v)
 			if k == 1 {
-				filter.FilterColumn.CustomFilters.And = andRel[exp[k]]
+				filter.FilterColumn[0].CustomFilters.And = andRel[exp[k]]
 			}
 		}
 	}
@@ -408,12 +1490,12 @@ func (f *File) writeCustomFilter(filter *xlsxAutoFilter, operator int, val strin
 		Operator: operators[operator],
 		Val:      val,
 	}
-	if filter.FilterColumn.CustomFilters != nil {
-		filter.FilterColumn.CustomFilters.CustomFilter = append(filter.FilterColumn.CustomFilters.CustomFilter, &customFilter)
+	if filter.FilterColumn[0].CustomFilters != nil {
+		filter.FilterColumn[0].CustomFilters.CustomFilter = append(filter.FilterColumn[0].CustomFilters.CustomFilter, &customFilter)
 	} else {
 		customFilters := []*xlsxCustomFilter{}
 		customFilters = append(customFilters, &customFilter)
-		filter.FilterColumn.CustomFilters = &xlsxCustomFilters{CustomFilter: customFilters}
+		filter.FilterColumn[0].CustomFilters = &xlsxCustomFilters{CustomFilter: customFilters}
 	}
 }
 
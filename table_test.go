@@ -3,6 +3,7 @@ package excelize
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,6 +46,266 @@ func TestAddTable(t *testing.T) {
 	assert.EqualError(t, f.addTable("sheet1", "", 1, 1, 0, 0, 0, nil), "invalid cell coordinates [0, 0]")
 }
 
+func TestAddTableTotalsRow(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Region", "Sales", "Price", "Units", "Cost [USD]"}))
+	for row := 2; row <= 5; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{"North", row * 10, row, row * 2, row * 3}))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "E5", `{"table_name":"Sales","totals_row":[{"column":"A","label":"Total"},{"column":"B","function":"sum"},{"column":"C","formula":"=SUM(Sales[Price])/2"},{"column":"D","function":"Average"},{"column":"E","function":"max","label":"ignored"}]}`))
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []Table{{
+		Name:  "Sales",
+		Range: "A1:E6",
+		Columns: []TableColumn{
+			{Name: "Region", TotalsRowLabel: "Total"},
+			{Name: "Sales", TotalsRowFunction: "sum"},
+			{Name: "Price", TotalsRowFunction: "custom", TotalsRowFormula: "SUM(Sales[Price])/2"},
+			{Name: "Units", TotalsRowFunction: "average"},
+			{Name: "Cost [USD]", TotalsRowFunction: "max"},
+		},
+		ShowHeaderRow:  true,
+		ShowTotalsRow:  true,
+		ShowRowStripes: true,
+		AutoFilter:     "A1:E5",
+	}}, tables)
+	val, err := f.GetCellValue("Sheet1", "A6")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total", val)
+	for cell, expected := range map[string]string{
+		"B6": "SUBTOTAL(109,Sales[Sales])",
+		"C6": "SUM(Sales[Price])/2",
+		"D6": "SUBTOTAL(101,Sales[Units])",
+		"E6": "SUBTOTAL(104,Sales[Cost '[USD']])",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	assert.Contains(t, string(f.readXML("xl/tables/table1.xml")), `totalsRowCount="1" totalsRowShown="true"`)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddTableTotalsRow.xlsx")))
+
+	// Test resize the table with the totals row.
+	assert.NoError(t, f.ResizeTable("Sales", "A1:E8"))
+	table, err := f.tableReader("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:E7", table.AutoFilter.Ref)
+
+	// Test add table with invalid totals row settings.
+	for format, expected := range map[string]string{
+		`{"totals_row":[{"column":"A1","label":"Total"}]}`: `invalid column name "A1"`,
+		`{"totals_row":[{"column":"F","label":"Total"}]}`:  "incorrect index of column 'F'",
+		`{"totals_row":[{"column":"A","function":"sums"}]}`: `unsupported totals row function "sums"`,
+	} {
+		f = NewFile()
+		assert.EqualError(t, f.AddTable("Sheet1", "A1", "E5", format), expected)
+	}
+	f = NewFile()
+	assert.EqualError(t, f.AddTable("Sheet1", "A1048575", "A1048576", `{"totals_row":[{"column":"A","label":"Total"}]}`), "row number exceeds maximum limit")
+	assert.EqualError(t, f.AddTable("Sheet1", "A1048575", "A1048576", `{"totals_row":[{"column":"A","function":"sum"}]}`), "row number exceeds maximum limit")
+	assert.EqualError(t, f.AddTable("Sheet1", "A1048575", "A1048576", `{"totals_row":[{"column":"A","formula":"SUM(A1)"}]}`), "row number exceeds maximum limit")
+}
+
+func TestGetTables(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []Table{{
+		Name:           "Table1",
+		Range:          "C21:D26",
+		Columns:        []TableColumn{{Name: "Column1"}, {Name: "Column2"}},
+		TableStyle:     "TableStyleMedium9",
+		ShowHeaderRow:  true,
+		ShowRowStripes: true,
+		AutoFilter:     "C21:D26",
+	}}, tables)
+	tables, err = f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Empty(t, tables)
+
+	assert.NoError(t, f.SetSheetRow("Sheet2", "A1", &[]interface{}{"Name", "Amount"}))
+	assert.NoError(t, f.AddTable("Sheet2", "A1", "B3", `{"table_name":"Sales","table_style":"TableStyleLight2","show_first_column":true,"show_column_stripes":true}`))
+	tables, err = f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, []Table{{
+		Name:              "Sales",
+		Range:             "A1:B3",
+		Columns:           []TableColumn{{Name: "Name"}, {Name: "Amount"}},
+		TableStyle:        "TableStyleLight2",
+		ShowHeaderRow:     true,
+		ShowFirstColumn:   true,
+		ShowRowStripes:    true,
+		ShowColumnStripes: true,
+		AutoFilter:        "A1:B3",
+	}}, tables)
+
+	// Test get tables with header and totals rows settings.
+	f.XLSX["xl/tables/table2.xml"] = []byte(`<table xmlns="` + NameSpaceSpreadSheet.Value + `" id="2" name="Sales" ref="A1:B3" headerRowCount="0" totalsRowCount="1"><tableColumns count="2"><tableColumn id="1" name="Name" totalsRowLabel="Total"/><tableColumn id="2" name="Amount" totalsRowFunction="sum"/></tableColumns></table>`)
+	tables, err = f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, []Table{{
+		Name:          "Sales",
+		Range:         "A1:B3",
+		Columns:       []TableColumn{{Name: "Name", TotalsRowLabel: "Total"}, {Name: "Amount", TotalsRowFunction: "sum"}},
+		ShowTotalsRow: true,
+	}}, tables)
+
+	// Test get tables with unsupported charset table part.
+	f.XLSX["xl/tables/table2.xml"] = MacintoshCyrillicCharset
+	_, err = f.GetTables("Sheet2")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get tables on not exist worksheet.
+	_, err = f.GetTables("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestDeleteTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Amount"}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B3", `{"table_name":"Table1"}`))
+	assert.NoError(t, f.AddTable("Sheet1", "D1", "E3", `{"table_name":"Table2"}`))
+	assert.NoError(t, f.DeleteTable("table1"))
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "Table2", tables[0].Name)
+	_, ok := f.XLSX["xl/tables/table1.xml"]
+	assert.False(t, ok)
+	contentTypes, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	for _, override := range contentTypes.Overrides {
+		assert.NotEqual(t, "/xl/tables/table1.xml", override.PartName)
+	}
+	rels, err := f.relsReader("xl/worksheets/_rels/sheet1.xml.rels")
+	assert.NoError(t, err)
+	for _, rel := range rels.Relationships {
+		assert.NotEqual(t, "../tables/table1.xml", rel.Target)
+	}
+	// Test add table after the table has been deleted.
+	assert.NoError(t, f.AddTable("Sheet1", "G1", "H3", `{"table_name":"Table3"}`))
+	_, ok = f.XLSX["xl/tables/table3.xml"]
+	assert.True(t, ok)
+	assert.NoError(t, f.DeleteTable("Table2"))
+	assert.NoError(t, f.DeleteTable("Table3"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.TableParts)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Name", val)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteTable.xlsx")))
+
+	// Test delete not exist table.
+	assert.EqualError(t, f.DeleteTable("Table1"), "table Table1 is not exist")
+	// Test delete table with unsupported charset table part.
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	f.XLSX["xl/tables/table1.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.DeleteTable("Table1"), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestResizeTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Amount", "Price", 2020}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B3", `{"table_name":"Sales"}`))
+	f.XLSX["xl/tables/table1.xml"] = []byte(strings.Replace(string(f.readXML("xl/tables/table1.xml")), `name="Amount"`, `name="Amount" totalsRowFunction="sum"`, 1))
+	assert.NoError(t, f.ResizeTable("Sales", "E10:A1"))
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:E10", tables[0].Range)
+	assert.Equal(t, "A1:E10", tables[0].AutoFilter)
+	assert.Equal(t, []TableColumn{{Name: "Name"}, {Name: "Amount", TotalsRowFunction: "sum"}, {Name: "Price"}, {Name: "2020"}, {Name: "Column5"}}, tables[0].Columns)
+	val, err := f.GetCellValue("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Column5", val)
+	table, err := f.tableReader("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	var ids []int
+	for _, col := range table.TableColumns.TableColumn {
+		ids = append(ids, col.ID)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+	assert.Equal(t, 5, table.TableColumns.Count)
+
+	assert.NoError(t, f.ResizeTable("Sales", "B1:C1"))
+	tables, err = f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B1:C2", tables[0].Range)
+	assert.Equal(t, []TableColumn{{Name: "Amount", TotalsRowFunction: "sum"}, {Name: "Price"}}, tables[0].Columns)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestResizeTable.xlsx")))
+
+	// Test resize table without header row.
+	f.XLSX["xl/tables/table1.xml"] = []byte(`<table xmlns="` + NameSpaceSpreadSheet.Value + `" id="1" name="Sales" ref="B1:C2" headerRowCount="0"><tableColumns count="2"><tableColumn id="1" name="Amount"/><tableColumn id="2" name="Price"/></tableColumns></table>`)
+	assert.NoError(t, f.ResizeTable("Sales", "B1:D1"))
+	tables, err = f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B1:D1", tables[0].Range)
+	assert.Equal(t, []TableColumn{{Name: "Amount"}, {Name: "Price"}, {Name: "Column3"}}, tables[0].Columns)
+
+	// Test resize table with invalid range.
+	assert.EqualError(t, f.ResizeTable("Sales", "A1"), `invalid table range "A1"`)
+	assert.EqualError(t, f.ResizeTable("Sales", "A:B1"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test resize not exist table.
+	assert.EqualError(t, f.ResizeTable("Table1", "A1:B2"), "table Table1 is not exist")
+}
+
+func TestTableCalculatedColumn(t *testing.T) {
+	f := NewFile()
+	for i, row := range [][]interface{}{{"Item", "Price", "Quantity", "Total"}, {"A", 1.5, 2}, {"B", 2, 3}, {"C", 4, 1}} {
+		cell, err := CoordinatesToCellName(1, i+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "D3", `{"table_name":"Sales","calculated_columns":[{"column":"D","formula":"=[@Price]*[@Quantity]"}]}`))
+	for _, cell := range []string{"D2", "D3"} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, "Sales[[#This Row],[Price]]*Sales[[#This Row],[Quantity]]", formula)
+	}
+	value, err := f.CalcCellValue("Sheet1", "D3")
+	assert.NoError(t, err)
+	assert.Equal(t, "6", value)
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sales[[#This Row],[Price]]*Sales[[#This Row],[Quantity]]", tables[0].Columns[3].CalculatedColumnFormula)
+
+	// Test fill the formula of the calculated column into the new rows.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D3", "0"))
+	assert.NoError(t, f.ResizeTable("Sales", "A1:D4"))
+	formula, err := f.GetCellFormula("Sheet1", "D3")
+	assert.NoError(t, err)
+	assert.Equal(t, "0", formula)
+	value, err = f.CalcCellValue("Sheet1", "D4")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", value)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestTableCalculatedColumn.xlsx")))
+
+	// Test set and remove the calculated column of the existing table.
+	assert.NoError(t, f.SetTableCalculatedColumn("sales", "C", "[@Price]+1"))
+	formula, err = f.GetCellFormula("Sheet1", "C4")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sales[[#This Row],[Price]]+1", formula)
+	assert.NoError(t, f.SetTableCalculatedColumn("Sales", "C", ""))
+	tables, err = f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, tables[0].Columns[2].CalculatedColumnFormula)
+	formula, err = f.GetCellFormula("Sheet1", "C4")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sales[[#This Row],[Price]]+1", formula)
+
+	// Test set calculated column with invalid settings.
+	assert.EqualError(t, f.SetTableCalculatedColumn("Sales", "E", "1"), "incorrect index of column 'E'")
+	assert.EqualError(t, f.SetTableCalculatedColumn("Sales", "1", "1"), `invalid column name "1"`)
+	assert.EqualError(t, f.SetTableCalculatedColumn("Table", "A", "1"), "table Table is not exist")
+	assert.EqualError(t, f.AddTable("Sheet1", "F1", "G3", `{"calculated_columns":[{"column":"A","formula":"1"}]}`), "incorrect index of column 'A'")
+	assert.EqualError(t, f.setTableCalculatedColumn("Sheet1", &xlsxTable{Ref: "A:B1"}, "A", "1"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.fillTableCalculatedColumns("SheetN", &xlsxTable{Ref: "A1:A2", TableColumns: &xlsxTableColumns{
+		TableColumn: []*xlsxTableColumn{{CalculatedColumnFormula: &xlsxTableFormula{Content: "1"}}},
+	}}, 1), "sheet SheetN is not exist")
+}
+
 func TestAutoFilter(t *testing.T) {
 	outFile := filepath.Join("test", "TestAutoFilter%d.xlsx")
 
@@ -77,6 +338,105 @@ func TestAutoFilter(t *testing.T) {
 	assert.EqualError(t, f.AutoFilter("Sheet1", "A1", "B", ""), `cannot convert cell "B" to coordinates: invalid cell name "B"`)
 }
 
+func TestAutoFilterHideRows(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Region", "Sales"}, {"North", 1500}, {"South", 2500}, {"East", nil}, {"West", 3500}, {"north east", 2000},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	for _, c := range []struct {
+		format  string
+		visible []bool
+	}{
+		{`{"column":"B","expression":"x > 2000","hide_rows":true}`, []bool{false, true, false, true, false}},
+		{`{"column":"B","expression":"x >= 1500 and x < 2500","hide_rows":true}`, []bool{true, false, false, false, true}},
+		{`{"column":"B","expression":"x <= 1500 or x == blanks","hide_rows":true}`, []bool{true, false, true, false, false}},
+		{`{"column":"B","expression":"x == nonblanks","hide_rows":true}`, []bool{true, true, false, true, true}},
+		{`{"column":"B","expression":"x == 2500","hide_rows":true}`, []bool{false, true, false, false, false}},
+		{`{"column":"A","expression":"x == North or x == west","hide_rows":true}`, []bool{true, false, false, true, false}},
+		{`{"column":"A","expression":"x == north*","hide_rows":true}`, []bool{true, false, false, false, true}},
+		{`{"column":"A","expression":"x != *th","hide_rows":true}`, []bool{false, false, true, true, true}},
+		{`{"column":"A","expression":"x == ?ast or x == \"north east\"","hide_rows":true}`, []bool{false, false, true, false, true}},
+		{`{"column":"A","expression":"x > n","hide_rows":true}`, []bool{true, true, false, true, true}},
+		{`{"column":"A","hide_rows":true}`, []bool{true, true, true, true, true}},
+	} {
+		assert.NoError(t, f.AutoFilter("Sheet1", "A1", "B6", c.format), c.format)
+		for row := 2; row <= 6; row++ {
+			visible, err := f.GetRowVisible("Sheet1", row)
+			assert.NoError(t, err)
+			assert.Equal(t, c.visible[row-2], visible, fmt.Sprintf("%s: row %d", c.format, row))
+		}
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, ws.SheetPr.FilterMode)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAutoFilterHideRows.xlsx")))
+
+	assert.True(t, matchFilterColumn(&xlsxFilterColumn{Filters: &xlsxFilters{Blank: true}}, " "))
+	assert.True(t, matchFilterColumn(&xlsxFilterColumn{}, "text"))
+	assert.True(t, matchFilterCriteria("lessThanOrEqual", "b", "a"))
+	assert.True(t, matchFilterCriteria("greaterThanOrEqual", "1", "1"))
+	assert.True(t, matchFilterCriteria("notEqual", "1", "2"))
+	assert.True(t, matchFilterWildcard("~*a~?", "*A?"))
+	assert.False(t, matchFilterWildcard("~*a", "ba"))
+
+	// Test hide filtered rows with invalid auto filter range.
+	assert.EqualError(t, f.hideFilteredRows("Sheet1", &xlsxAutoFilter{Ref: "A:B2"}, 1), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.hideFilteredRows("Sheet1", &xlsxAutoFilter{Ref: "A1:B2", FilterColumn: []*xlsxFilterColumn{{}}}, 0), "invalid cell coordinates [0, 2]")
+	assert.EqualError(t, f.hideFilteredRows("SheetN", &xlsxAutoFilter{Ref: "A1:B2", FilterColumn: []*xlsxFilterColumn{{}}}, 1), "sheet SheetN is not exist")
+}
+
+func TestGetAutoFilter(t *testing.T) {
+	f := NewFile()
+	opt, err := f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, opt)
+	assert.NoError(t, f.AutoFilter("Sheet1", "D4", "B1", `{"column":"C","expression":"x > 1 and x <= 2"}`))
+	opt, err = f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &AutoFilterOption{Range: "B1:D4", FilterMode: true, Columns: []AutoFilterColumn{{
+		Column:           "C",
+		CustomFilters:    []AutoFilterCustomFilter{{Operator: "greaterThan", Val: "1"}, {Operator: "lessThanOrEqual", Val: "2"}},
+		CustomFiltersAnd: true,
+	}}}, opt)
+
+	expected := AutoFilterOption{Range: "A1:F10", FilterMode: true, Columns: []AutoFilterColumn{
+		{Column: "A", Filters: []string{"North", "South"}, FilterBlank: true},
+		{Column: "B", CustomFilters: []AutoFilterCustomFilter{{Operator: "equal", Val: "a*"}, {Operator: "notEqual", Val: "ab"}}},
+		{Column: "C", DynamicFilter: &AutoFilterDynamicFilter{Type: "aboveAverage", Val: 5.5}},
+		{Column: "D", Top10: &AutoFilterTop10{Top: true, Percent: true, Val: 10, FilterVal: 7}},
+		{Column: "E", ColorFilter: &AutoFilterColorFilter{CellColor: true, DxfID: 1}},
+		{Column: "F", IconFilter: &AutoFilterIconFilter{IconSet: "3Arrows", IconID: 2}},
+	}}
+	assert.NoError(t, f.SetAutoFilter("Sheet1", AutoFilterOption{Range: "F10:A1", FilterMode: true, Columns: expected.Columns}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetAutoFilter.xlsx")))
+	f, err = OpenFile(filepath.Join("test", "TestGetAutoFilter.xlsx"))
+	assert.NoError(t, err)
+	opt, err = f.GetAutoFilter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, opt)
+	assert.Equal(t, []DefinedName{{Name: "_xlnm._FilterDatabase", Hidden: true, RefersTo: "Sheet1!A1:F10", Scope: "Sheet1"}}, f.GetDefinedName())
+
+	// Test get auto filter with invalid range and column.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.AutoFilter.Ref = "XFD1:XFD2"
+	_, err = f.GetAutoFilter("Sheet1")
+	assert.EqualError(t, err, "column number exceeds maximum limit")
+	ws.AutoFilter.Ref = "A:B2"
+	_, err = f.GetAutoFilter("Sheet1")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = f.GetAutoFilter("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test set auto filter with invalid settings.
+	assert.EqualError(t, f.SetAutoFilter("Sheet1", AutoFilterOption{Range: "A1"}), `invalid auto filter range "A1"`)
+	assert.EqualError(t, f.SetAutoFilter("Sheet1", AutoFilterOption{Range: "A:B2"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetAutoFilter("SheetN", AutoFilterOption{Range: "A1:B2"}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetAutoFilter("Sheet1", AutoFilterOption{Range: "A1:B2", Columns: []AutoFilterColumn{{Column: "1"}}}), `invalid column name "1"`)
+	assert.EqualError(t, f.SetAutoFilter("Sheet1", AutoFilterOption{Range: "A1:B2", Columns: []AutoFilterColumn{{Column: "C"}}}), "incorrect index of column 'C'")
+}
+
 func TestAutoFilterError(t *testing.T) {
 	outFile := filepath.Join("test", "TestAutoFilterError%d.xlsx")
 
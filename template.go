@@ -0,0 +1,553 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	templateActionRe      = regexp.MustCompile(`\{\{\s*(.*?)\s*\}\}`)
+	templatePlaceholderRe = regexp.MustCompile(`\{\{\s*(\$|\$?\.[\w.]*)\s*\}\}`)
+	templateRangeRe       = regexp.MustCompile(`\{\{\s*range\s+(\$|\$?\.[\w.]*)\s*\}\}`)
+	templateEndRe         = regexp.MustCompile(`\{\{\s*end\s*\}\}`)
+	templateReferenceRe   = regexp.MustCompile(`\$?[A-Za-z]{1,3}\$?\d+(:\$?[A-Za-z]{1,3}\$?\d+)?`)
+)
+
+// TemplatePlaceholder directly maps the placeholder in the cell of the
+// template worksheet, the Action is the text between the braces, such as
+// ".Customer.Name", "range .Items" and "end".
+type TemplatePlaceholder struct {
+	Cell   string
+	Action string
+}
+
+// TemplateOptions directly maps the settings of filling the template.
+//
+// Sheet specifies the worksheet to be filled, all worksheets will be filled
+// if it's empty.
+type TemplateOptions struct {
+	Sheet string
+}
+
+// templateCell directly maps the text value and the formula of the cell in
+// the template worksheet.
+type templateCell struct {
+	axis    string
+	row     int
+	value   string
+	formula string
+}
+
+// templateRegion directly maps the rows repeated for the items of the range
+// in the template worksheet.
+type templateRegion struct {
+	start, end, count int
+}
+
+// extra returns the number of rows inserted after the region is repeated,
+// and the negative number if the rows of the region were removed.
+func (r templateRegion) extra() int {
+	return (r.count - 1) * (r.end - r.start + 1)
+}
+
+// adjust provides a function to adjust the rows of the reference out of the
+// region after the region is repeated, the range ending in the region will be
+// extended to cover all repeated rows.
+func (r templateRegion) adjust(rows []int) {
+	if len(rows) == 2 && rows[0] <= r.start && r.start <= rows[1] && rows[1] <= r.end {
+		if rows[1] += r.extra(); rows[1] < rows[0] {
+			rows[1] = rows[0]
+		}
+		return
+	}
+	for i := range rows {
+		if rows[i] > r.end {
+			rows[i] += r.extra()
+		}
+	}
+}
+
+// GetTemplatePlaceholders provides a function to get the placeholders in the
+// values and formulas of the cells by given worksheet name, the placeholders
+// are returned in the order of the cells.
+func (f *File) GetTemplatePlaceholders(sheet string) ([]TemplatePlaceholder, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := f.getTemplateCells(ws)
+	if err != nil {
+		return nil, err
+	}
+	var placeholders []TemplatePlaceholder
+	for _, cell := range cells {
+		for _, text := range []string{cell.value, cell.formula} {
+			for _, match := range templateActionRe.FindAllStringSubmatch(text, -1) {
+				placeholders = append(placeholders, TemplatePlaceholder{Cell: cell.axis, Action: match[1]})
+			}
+		}
+	}
+	return placeholders, err
+}
+
+// ExecuteTemplate provides a function to fill the placeholders in the
+// template worksheets by given data and optional settings, the styles,
+// merged cells and formulas of the template will be kept. The placeholder
+// {{.Name}} is replaced with the value of the field or the map key of the
+// data, the nested values are accessed by the path such as
+// {{.Customer.Name}} or {{.Items.0.Name}}. The cell which only contains one
+// placeholder is set as the value with its type, such as the number, boolean
+// and date time, the others are set as the strings. The placeholders in the
+// formulas are replaced with the text of the values.
+//
+// The rows from the cell containing {{range .Items}} to the cell containing
+// {{end}} are repeated for each item of the slice or array, the placeholders
+// in the repeated rows are resolved by the item, and {{$.Name}} can be used
+// to access the data. The relative references of the formulas in the
+// repeated rows are moved with the rows, and the ranges and tables ending
+// in the repeated rows are extended to cover all of them, the repeated rows
+// are removed if there is no item. The nested ranges are not supported. For
+// example, fill the invoice template:
+//
+//    f, err := excelize.OpenFile("Invoice.xlsx")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    // A1: Invoice {{.Number}}    B1: {{.Customer.Name}}
+//    // A4: {{range .Items}}{{.Name}}    B4: {{.Qty}}    C4: {{.Price}}    D4: =B4*C4{{end}}
+//    // A5: Total    D5: =SUM(D4:D4)
+//    err = f.ExecuteTemplate(map[string]interface{}{
+//        "Number":   "INV-001",
+//        "Customer": customer,
+//        "Items":    items,
+//    })
+//
+func (f *File) ExecuteTemplate(data interface{}, opts ...TemplateOptions) error {
+	sheets := f.GetSheetList()
+	for _, opt := range opts {
+		if opt.Sheet != "" {
+			sheets = []string{opt.Sheet}
+		}
+	}
+	for _, sheet := range sheets {
+		if err := f.executeSheetTemplate(sheet, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeSheetTemplate provides a function to fill the placeholders in the
+// template worksheet by given worksheet name and data.
+func (f *File) executeSheetTemplate(sheet string, data interface{}) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	var regions []templateRegion
+	for from := 1; ; {
+		region, err := f.executeTemplateRegion(sheet, ws, from, data)
+		if err != nil {
+			return err
+		}
+		if region == nil {
+			break
+		}
+		regions = append(regions, *region)
+		from = region.start + region.count*(region.end-region.start+1)
+	}
+	cells, err := f.getTemplateCells(ws)
+	if err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		filled := false
+		for _, region := range regions {
+			if region.start <= cell.row && cell.row < region.start+region.count*(region.end-region.start+1) {
+				filled = true
+			}
+		}
+		if !filled {
+			if err = f.executeTemplateCell(sheet, cell, data, data); err != nil {
+				return err
+			}
+		}
+	}
+	if len(regions) == 0 {
+		return err
+	}
+	tables, err := f.GetTables(sheet)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		coordinates, err := f.areaRefToCoordinates(table.Range)
+		if err != nil {
+			return err
+		}
+		rows := []int{coordinates[1], coordinates[3]}
+		for _, region := range regions {
+			region.adjust(rows)
+		}
+		if rows[0] != coordinates[1] || rows[1] != coordinates[3] {
+			ref, err := f.coordinatesToAreaRef([]int{coordinates[0], rows[0], coordinates[2], rows[1]})
+			if err != nil {
+				return err
+			}
+			if err = f.ResizeTable(table.Name, ref); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// executeTemplateRegion provides a function to find the first range in the
+// template worksheet starting from the given row, repeat the rows of the
+// range for each item and fill the placeholders in the repeated rows. The
+// nil will be returned if there is no range.
+func (f *File) executeTemplateRegion(sheet string, ws *xlsxWorksheet, from int, data interface{}) (*templateRegion, error) {
+	var (
+		region                   templateRegion
+		expr, rangeCell, endCell string
+	)
+	cells, err := f.getTemplateCells(ws)
+	if err != nil {
+		return nil, err
+	}
+	for _, cell := range cells {
+		if cell.row < from {
+			continue
+		}
+		if rangeCell == "" {
+			match := templateRangeRe.FindStringSubmatch(cell.value)
+			if match == nil {
+				continue
+			}
+			region.start, expr, rangeCell = cell.row, match[1], cell.axis
+			cell.value = strings.Replace(cell.value, match[0], "", 1)
+			if err := f.setTemplateCellText(sheet, cell.axis, cell.value); err != nil {
+				return nil, err
+			}
+		}
+		if match := templateEndRe.FindString(cell.value); match != "" {
+			region.end, endCell = cell.row, cell.axis
+			if err := f.setTemplateCellText(sheet, cell.axis, strings.Replace(cell.value, match, "", 1)); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if rangeCell == "" {
+		return nil, nil
+	}
+	if endCell == "" {
+		return nil, fmt.Errorf("missing {{end}} of the range in cell %s", rangeCell)
+	}
+	value, err := getTemplateValue(data, data, expr)
+	if err != nil {
+		return nil, err
+	}
+	items := reflect.ValueOf(value)
+	switch items.Kind() {
+	case reflect.Invalid:
+	case reflect.Slice, reflect.Array:
+		region.count = items.Len()
+	default:
+		return nil, fmt.Errorf("range can't iterate over %v", value)
+	}
+	height := region.end - region.start + 1
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		for colIdx := range row.C {
+			if c := &row.C[colIdx]; c.F != nil && c.F.Content != "" {
+				c.F.Content = adjustTemplateFormula(c.F.Content, func(rows []int, abs []bool) {
+					if row.R < region.start || row.R > region.end {
+						region.adjust(rows)
+						return
+					}
+					for i := range rows {
+						if rows[i] > region.end {
+							rows[i] += region.extra()
+						}
+					}
+				})
+			}
+		}
+	}
+	if region.count == 0 {
+		for row := region.end; row >= region.start; row-- {
+			if err = f.RemoveRow(sheet, row); err != nil {
+				return nil, err
+			}
+		}
+		return &region, err
+	}
+	var mergeCells [][]int
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			if coordinates, err := f.areaRefToCoordinates(mergeCell.Ref); err == nil &&
+				coordinates[1] != coordinates[3] && region.start <= coordinates[1] && coordinates[3] <= region.end {
+				mergeCells = append(mergeCells, coordinates)
+			}
+		}
+	}
+	for i := 1; i < region.count; i++ {
+		for k := 0; k < height; k++ {
+			if region.start+k > len(ws.SheetData.Row) {
+				err = f.InsertRow(sheet, region.start+i*height+k)
+			} else {
+				err = f.DuplicateRowTo(sheet, region.start+k, region.start+i*height+k)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, coordinates := range mergeCells {
+			hcell, _ := CoordinatesToCellName(coordinates[0], coordinates[1]+i*height)
+			vcell, _ := CoordinatesToCellName(coordinates[2], coordinates[3]+i*height)
+			if err = f.MergeCell(sheet, hcell, vcell); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		offset := row.R - region.start
+		if offset < height || offset >= height*region.count {
+			continue
+		}
+		for colIdx := range row.C {
+			if c := &row.C[colIdx]; c.F != nil && c.F.Content != "" {
+				c.F.Content = adjustTemplateFormula(c.F.Content, func(rows []int, abs []bool) {
+					for i := range rows {
+						if !abs[i] && region.start <= rows[i] && rows[i] <= region.end {
+							rows[i] += offset / height * height
+						}
+					}
+				})
+			}
+		}
+	}
+	if cells, err = f.getTemplateCells(ws); err != nil {
+		return nil, err
+	}
+	for _, cell := range cells {
+		if offset := cell.row - region.start; offset >= 0 && offset < height*region.count {
+			if err = f.executeTemplateCell(sheet, cell, data, items.Index(offset/height).Interface()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &region, err
+}
+
+// getTemplateCells provides a function to get the cells with the text values
+// containing the placeholders or with the formulas in the worksheet.
+func (f *File) getTemplateCells(ws *xlsxWorksheet) ([]templateCell, error) {
+	var cells []templateCell
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return cells, err
+	}
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			cell := templateCell{axis: c.R, row: row.R}
+			if c.F != nil {
+				cell.formula = c.F.Content
+			}
+			if c.T == "s" || c.T == "str" || c.T == "inlineStr" {
+				cell.value, _ = c.getValueFrom(f, sst, true)
+			}
+			if cell.formula != "" || strings.Contains(cell.value, "{{") {
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells, nil
+}
+
+// executeTemplateCell provides a function to fill the placeholders in the
+// value or the formula of the cell by given data and the current item.
+func (f *File) executeTemplateCell(sheet string, cell templateCell, data, dot interface{}) error {
+	if cell.formula != "" {
+		if !strings.Contains(cell.formula, "{{") {
+			return nil
+		}
+		formula, err := executeTemplateText(cell.formula, data, dot)
+		if err != nil {
+			return err
+		}
+		return f.SetCellFormula(sheet, cell.axis, formula)
+	}
+	if loc := templatePlaceholderRe.FindStringSubmatchIndex(cell.value); loc != nil && loc[0] == 0 && loc[1] == len(cell.value) {
+		value, err := getTemplateValue(data, dot, cell.value[loc[2]:loc[3]])
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return f.setTemplateCellText(sheet, cell.axis, "")
+		}
+		return f.SetCellValue(sheet, cell.axis, value)
+	}
+	text, err := executeTemplateText(cell.value, data, dot)
+	if err != nil {
+		return err
+	}
+	return f.setTemplateCellText(sheet, cell.axis, text)
+}
+
+// setTemplateCellText provides a function to set the text of the cell, the
+// value of the cell will be cleared if the text is empty.
+func (f *File) setTemplateCellText(sheet, axis, text string) error {
+	if text == "" {
+		return f.SetCellDefault(sheet, axis, text)
+	}
+	return f.SetCellStr(sheet, axis, text)
+}
+
+// executeTemplateText provides a function to replace the placeholders in the
+// text with the text of the values by given data and the current item.
+func executeTemplateText(text string, data, dot interface{}) (string, error) {
+	var err error
+	result := templatePlaceholderRe.ReplaceAllStringFunc(text, func(placeholder string) string {
+		value, e := getTemplateValue(data, dot, templatePlaceholderRe.FindStringSubmatch(placeholder)[1])
+		if e != nil && err == nil {
+			err = e
+		}
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	})
+	return result, err
+}
+
+// getTemplateValue provides a function to get the value by given data, the
+// current item and the path of the placeholder, the path starting with $ is
+// resolved by the data, and the others are resolved by the current item.
+func getTemplateValue(data, dot interface{}, expr string) (interface{}, error) {
+	value := reflect.ValueOf(dot)
+	if strings.HasPrefix(expr, "$") {
+		value, expr = reflect.ValueOf(data), expr[1:]
+	}
+	for _, name := range strings.Split(expr, ".") {
+		if name == "" {
+			continue
+		}
+		if value = indirectTemplateValue(value); !value.IsValid() {
+			return nil, nil
+		}
+		switch value.Kind() {
+		case reflect.Struct:
+			field := value.FieldByName(name)
+			if !field.IsValid() || !field.CanInterface() {
+				return nil, fmt.Errorf("can't evaluate field %s in type %s", name, value.Type())
+			}
+			value = field
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("can't evaluate field %s in type %s", name, value.Type())
+			}
+			value = value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+		case reflect.Slice, reflect.Array:
+			idx, err := strconv.Atoi(name)
+			if err != nil || idx < 0 || idx >= value.Len() {
+				return nil, fmt.Errorf("can't index item %s in type %s", name, value.Type())
+			}
+			value = value.Index(idx)
+		default:
+			return nil, fmt.Errorf("can't evaluate field %s in type %s", name, value.Type())
+		}
+	}
+	if value = indirectTemplateValue(value); !value.IsValid() {
+		return nil, nil
+	}
+	return value.Interface(), nil
+}
+
+// indirectTemplateValue provides a function to get the value that the
+// pointer or the interface points to, the invalid value will be returned for
+// the nil pointer or interface.
+func indirectTemplateValue(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// adjustTemplateFormula provides a function to adjust the rows of the cell
+// and range references in the formula by given function, the references in
+// the strings and the references with the worksheet name are skipped. The
+// function receives the rows of the reference and whether each row is
+// absolute, the range reference has two rows.
+func adjustTemplateFormula(formula string, fn func(rows []int, abs []bool)) string {
+	var (
+		quoted = make([]bool, len(formula))
+		quote  byte
+	)
+	for i := 0; i < len(formula); i++ {
+		if quote != 0 {
+			quoted[i] = true
+			if formula[i] == quote {
+				quote = 0
+			}
+			continue
+		}
+		if formula[i] == '"' || formula[i] == '\'' {
+			quoted[i], quote = true, formula[i]
+		}
+	}
+	isName := func(c byte) bool {
+		return c == '_' || c == '.' || ('0' <= c && c <= '9') || ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z')
+	}
+	var (
+		buf  strings.Builder
+		last int
+	)
+	for _, loc := range templateReferenceRe.FindAllStringIndex(formula, -1) {
+		if quoted[loc[0]] ||
+			(loc[0] > 0 && (isName(formula[loc[0]-1]) || strings.IndexByte("!$[:", formula[loc[0]-1]) != -1)) ||
+			(loc[1] < len(formula) && (isName(formula[loc[1]]) || strings.IndexByte("(]!:", formula[loc[1]]) != -1)) {
+			continue
+		}
+		var (
+			parts = strings.Split(formula[loc[0]:loc[1]], ":")
+			rows  = make([]int, len(parts))
+			abs   = make([]bool, len(parts))
+			idx   = make([]int, len(parts))
+		)
+		for i, part := range parts {
+			idx[i] = strings.IndexAny(part, "0123456789")
+			rows[i], _ = strconv.Atoi(part[idx[i]:])
+			abs[i] = part[idx[i]-1] == '$'
+		}
+		fn(rows, abs)
+		for i, part := range parts {
+			parts[i] = part[:idx[i]] + strconv.Itoa(rows[i])
+		}
+		buf.WriteString(formula[last:loc[0]])
+		buf.WriteString(strings.Join(parts, ":"))
+		last = loc[1]
+	}
+	buf.WriteString(formula[last:])
+	return buf.String()
+}
@@ -0,0 +1,165 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteTemplate(t *testing.T) {
+	type item struct {
+		Name  string
+		Qty   int
+		Price float64
+	}
+	type customer struct {
+		Name string
+	}
+	newTemplate := func() *File {
+		f := NewFile()
+		for cell, value := range map[string]interface{}{
+			"A1": "Invoice {{.Number}}", "B1": "{{.Customer.Name}}", "C1": "{{.Date}}",
+			"A3": "Item", "B3": "Qty", "C3": "Price", "D3": "Amount",
+			"A4": "{{range .Items}}{{.Name}}", "B4": "{{.Qty}}", "C4": "{{.Price}}", "E4": "{{$.Currency}}{{end}}",
+			"A5": "Total", "A6": "{{.Note}}",
+		} {
+			assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+		}
+		assert.NoError(t, f.SetCellFormula("Sheet1", "D4", "B4*C4*$B$1"))
+		assert.NoError(t, f.SetCellFormula("Sheet1", "D5", `SUM(D4:D4)&"D4"`))
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "A6&{{.Rate}}"))
+		assert.NoError(t, f.MergeCell("Sheet1", "A6", "B6"))
+		assert.NoError(t, f.AddTable("Sheet1", "A3", "D4", `{"table_name":"Items"}`))
+		style, err := f.NewStyle(`{"font":{"bold":true}}`)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+		assert.NoError(t, f.SetCellStyle("Sheet1", "B4", "B4", style))
+		return f
+	}
+	f := newTemplate()
+	placeholders, err := f.GetTemplatePlaceholders("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []TemplatePlaceholder{
+		{Cell: "A1", Action: ".Number"}, {Cell: "B1", Action: ".Customer.Name"},
+		{Cell: "C1", Action: ".Date"}, {Cell: "F1", Action: ".Rate"},
+		{Cell: "A4", Action: "range .Items"}, {Cell: "A4", Action: ".Name"},
+		{Cell: "B4", Action: ".Qty"}, {Cell: "C4", Action: ".Price"},
+		{Cell: "E4", Action: "$.Currency"}, {Cell: "E4", Action: "end"},
+		{Cell: "A6", Action: ".Note"},
+	}, placeholders)
+
+	data := map[string]interface{}{
+		"Number":   "INV-001",
+		"Customer": &customer{Name: "Foo"},
+		"Date":     time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		"Currency": "USD",
+		"Rate":     2,
+		"Items":    []item{{"Apple", 2, 1.5}, {"Banana", 3, 0.5}, {"Cherry", 1, 10}},
+	}
+	assert.NoError(t, f.ExecuteTemplate(data))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Invoice INV-001", "Foo", "1/2/21 12:00", "", "", ""},
+		nil,
+		{"Item", "Qty", "Price", "Amount"},
+		{"Apple", "2", "1.5", "", "USD"},
+		{"Banana", "3", "0.5", "", "USD"},
+		{"Cherry", "1", "10", "", "USD"},
+		{"Total", "", "", ""},
+	}, rows[:7])
+	for cell, expected := range map[string]string{
+		"D4": "B4*C4*$B$1", "D5": "B5*C5*$B$1", "D6": "B6*C6*$B$1", "D7": `SUM(D4:D6)&"D4"`, "F1": "A8&2",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	for _, cell := range []string{"B1", "B5", "B6"} {
+		style, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.NotZero(t, style, cell)
+	}
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A8", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "B8", mergeCells[0].GetEndAxis())
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A3:D6", tables[0].Range)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", ws.SheetData.Row[4].C[1].T)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestExecuteTemplate.xlsx")))
+
+	// Test execute template with empty range and nil value
+	f = newTemplate()
+	data["Items"], data["Customer"] = nil, (*customer)(nil)
+	assert.NoError(t, f.ExecuteTemplate(data, TemplateOptions{Sheet: "Sheet1"}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Invoice INV-001", "", "1/2/21 12:00", "", "", ""}, nil, {"Item", "Qty", "Price", "Amount"}, {"Total", "", "", ""}, {""}}, rows)
+	formula, err := f.GetCellFormula("Sheet1", "D4")
+	assert.NoError(t, err)
+	assert.Equal(t, `SUM(D4:D4)&"D4"`, formula)
+
+	// Test execute template with multiple rows range
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "{{range .}}{{.Name}}"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "{{.Qty}}{{end}}"))
+	assert.NoError(t, f.MergeCell("Sheet1", "B1", "B2"))
+	assert.NoError(t, f.ExecuteTemplate([]*item{{Name: "Apple", Qty: 2}, {Name: "Banana", Qty: 3}}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Apple"}, {"2"}, {"Banana"}, {"3"}}, rows)
+	mergeCells, err = f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 2)
+
+	// Test execute template with invalid data
+	for _, c := range []struct {
+		value interface{}
+		data  interface{}
+		err   string
+	}{
+		{"{{.Missing}}", item{}, "can't evaluate field Missing in type excelize.item"},
+		{"Name: {{.name}}", item{}, "can't evaluate field name in type excelize.item"},
+		{"{{.Name.Value}}", item{}, "can't evaluate field Value in type string"},
+		{"{{.Items.3}}", data, "can't index item 3 in type []excelize.item"},
+		{"{{.Key}}", map[int]string{}, "can't evaluate field Key in type map[int]string"},
+		{"{{range .Name}}{{end}}", item{Name: "Foo"}, "range can't iterate over Foo"},
+		{"{{range .Items}}", map[string]interface{}{"Items": []int{1}}, "missing {{end}} of the range in cell A1"},
+	} {
+		f = NewFile()
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", c.value))
+		if c.data == nil {
+			c.data = data
+		}
+		data["Items"] = []item{{}}
+		assert.EqualError(t, f.ExecuteTemplate(c.data), c.err)
+	}
+	assert.EqualError(t, f.ExecuteTemplate(data, TemplateOptions{Sheet: "SheetN"}), "sheet SheetN is not exist")
+	_, err = f.GetTemplatePlaceholders("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestAdjustTemplateFormula(t *testing.T) {
+	shift := func(rows []int, abs []bool) {
+		for i := range rows {
+			if !abs[i] {
+				rows[i]++
+			}
+		}
+	}
+	for formula, expected := range map[string]string{
+		"A1+$B$2+C$3*$D4":                  "A2+$B$2+C$3*$D5",
+		"SUM(A1:B2)+LOG10(A1)":             "SUM(A2:B3)+LOG10(A2)",
+		`"A1"&'Sheet 1'!A1&Sheet1!B2&Tax2`: `"A1"&'Sheet 1'!A1&Sheet1!B2&Tax3`,
+		"Table1[Col1]+A1_B2":               "Table1[Col1]+A1_B2",
+	} {
+		assert.Equal(t, expected, adjustTemplateFormula(formula, shift), formula)
+	}
+}
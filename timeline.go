@@ -0,0 +1,481 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimelineOption directly maps the settings of the timeline. PivotTable and
+// Field specify the name of the pivot table and the name of the date field in
+// the pivot cache of the pivot table to be filtered by the timeline. Name and
+// Caption specify the name and the header caption of the timeline, the field
+// name will be used if they are empty. Level specifies the time level of the
+// timeline, the possible values are "years", "quarters", "months" and "days",
+// the default value is "months". Cell, OffsetX, OffsetY, Width and Height
+// specify the placement and the size in pixels of the timeline. Style
+// specifies the timeline style, such as "TimeSlicerStyleLight1".
+type TimelineOption struct {
+	Name       string
+	Caption    string
+	PivotTable string
+	Field      string
+	Level      string
+	Cell       string
+	OffsetX    int
+	OffsetY    int
+	Width      int
+	Height     int
+	Style      string
+}
+
+// timelineLevels defined the time levels of the timeline.
+var timelineLevels = map[string]int{"years": 0, "quarters": 1, "months": 2, "days": 3}
+
+// timelineCacheNameExp defined the characters which should be replaced in the
+// name of the timeline cache.
+var timelineCacheNameExp = regexp.MustCompile(`[^0-9A-Za-z_]`)
+
+// AddTimeline provides the method to add a timeline on the worksheet by given
+// worksheet name and timeline options. The timeline filters the pivot table
+// by the date field of the pivot cache, the date range of the timeline is
+// calculated by the source data of the pivot cache. For example, add a
+// timeline at the cell H2 on Sheet1 to filter the pivot table named
+// "PivotTable1" by quarters of the "Date" field:
+//
+//    err := f.AddTimeline("Sheet1", &excelize.TimelineOption{
+//        PivotTable: "PivotTable1",
+//        Field:      "Date",
+//        Level:      "quarters",
+//        Cell:       "H2",
+//    })
+//
+// The timeline requires Excel 2013 or later.
+func (f *File) AddTimeline(sheet string, opt *TimelineOption) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	level, err := parseFormatTimelineSet(opt)
+	if err != nil {
+		return err
+	}
+	_, part, err := f.findPivotTablePart(opt.PivotTable)
+	if err != nil {
+		return err
+	}
+	field := -1
+	if part.pc.CacheFields != nil {
+		for idx, cacheField := range part.pc.CacheFields.CacheField {
+			if cacheField.Name == opt.Field && defaultTrue(cacheField.DatabaseField) {
+				field = idx
+				break
+			}
+		}
+	}
+	if field == -1 {
+		return fmt.Errorf("parameter 'Field' parsing error: field %s is not exist", opt.Field)
+	}
+	bounds, err := f.getTimelineBounds(part.pc, field)
+	if err != nil {
+		return err
+	}
+	name, err := f.getTimelineName(opt)
+	if err != nil {
+		return err
+	}
+	pivotCacheID, err := f.setPivotCacheID(part)
+	if err != nil {
+		return err
+	}
+	cacheName, err := f.addTimelineCache(part, field, pivotCacheID, bounds)
+	if err != nil {
+		return err
+	}
+	timeline := &xlsxTimeline{
+		Name:           name,
+		Cache:          cacheName,
+		Caption:        opt.Caption,
+		Level:          level,
+		SelectionLevel: level,
+		ScrollPosition: bounds.StartDate,
+		Style:          opt.Style,
+	}
+	if timeline.Caption == "" {
+		timeline.Caption = opt.Field
+	}
+	if err = f.addTimeline(ws, sheet, timeline); err != nil {
+		return err
+	}
+	return f.addDrawingTimeline(ws, sheet, name, opt)
+}
+
+// parseFormatTimelineSet provides a function to validate the timeline
+// options and returns the time level of the timeline.
+func parseFormatTimelineSet(opt *TimelineOption) (int, error) {
+	if opt == nil {
+		return 0, errors.New("parameter is required")
+	}
+	level := timelineLevels["months"]
+	if opt.Level != "" {
+		var ok bool
+		if level, ok = timelineLevels[strings.ToLower(opt.Level)]; !ok {
+			return level, errors.New("parameter 'Level' must be 'years', 'quarters', 'months' or 'days'")
+		}
+	}
+	if _, _, err := CellNameToCoordinates(opt.Cell); err != nil {
+		return level, err
+	}
+	return level, nil
+}
+
+// getTimelineBounds provides a function to get the date range of the
+// timeline by given pivot cache definition and the index of the cache field.
+// The date range starts from the first day of the year of the earliest date
+// and ends at the first day of the year after the latest date in the source
+// data.
+func (f *File) getTimelineBounds(pc *xlsxPivotCacheDefinition, field int) (*xlsxTimelineRange, error) {
+	dataSheet, coordinates, err := f.getPivotCacheSource(pc)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := f.workSheetReader(dataSheet)
+	if err != nil {
+		return nil, err
+	}
+	col := 0
+	for idx := 0; idx < field; idx++ {
+		if defaultTrue(pc.CacheFields.CacheField[idx].DatabaseField) {
+			col++
+		}
+	}
+	var minValue, maxValue float64
+	var found bool
+	values, err := f.getPivotCacheSourceValues(ws, coordinates)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range values[1:] {
+		if col >= len(row) || row[col].kind != sortValueNumber {
+			continue
+		}
+		if !found || row[col].num < minValue {
+			minValue = row[col].num
+		}
+		if !found || row[col].num > maxValue {
+			maxValue = row[col].num
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("parameter 'Field' parsing error: field %s doesn't contain date values", pc.CacheFields.CacheField[field].Name)
+	}
+	date1904 := f.date1904()
+	startYear, endYear := timeFromExcelTime(minValue, date1904).Year(), timeFromExcelTime(maxValue, date1904).Year()
+	return &xlsxTimelineRange{
+		StartDate: time.Date(startYear, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02T15:04:05"),
+		EndDate:   time.Date(endYear+1, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02T15:04:05"),
+	}, nil
+}
+
+// getTimelineName provides a function to get the name of the timeline by
+// given timeline options, the name of the timeline should be unique in the
+// workbook.
+func (f *File) getTimelineName(opt *TimelineOption) (string, error) {
+	var names []string
+	for path := range f.XLSX {
+		if !strings.HasPrefix(path, "xl/timelines/timeline") {
+			continue
+		}
+		timelines, err := f.timelinesReader(path)
+		if err != nil {
+			return "", err
+		}
+		for _, timeline := range timelines.Timeline {
+			names = append(names, timeline.Name)
+		}
+	}
+	if opt.Name != "" {
+		if inStrSlice(names, opt.Name) != -1 {
+			return opt.Name, fmt.Errorf("the same timeline name %s already exists", opt.Name)
+		}
+		return opt.Name, nil
+	}
+	name := opt.Field
+	for idx := 1; inStrSlice(names, name) != -1; idx++ {
+		name = fmt.Sprintf("%s %d", opt.Field, idx)
+	}
+	return name, nil
+}
+
+// setPivotCacheID provides a function to get the identifier of the pivot
+// cache in the extension list of the pivot cache definition by given pivot
+// table parts, the identifier will be created by the cache ID of the pivot
+// table if it doesn't exist.
+func (f *File) setPivotCacheID(part *pivotTablePart) (int, error) {
+	pc, decodeExtLst := part.pc, new(decodeWorksheetExt)
+	if pc.ExtLst != nil {
+		if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + pc.ExtLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return 0, err
+		}
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI == ExtURIPivotCacheDefinition {
+			decodePivotCache := new(decodeX14PivotCacheDefinition)
+			if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+				Decode(decodePivotCache); err != nil && err != io.EOF {
+				return 0, err
+			}
+			return decodePivotCache.PivotCacheID, nil
+		}
+	}
+	content, _ := xml.Marshal(xlsxX14PivotCacheDefinition{
+		XMLNSX14:     NameSpaceSpreadSheetX14.Value,
+		PivotCacheID: part.pt.CacheID,
+	})
+	decodeExtLst.Ext = append(decodeExtLst.Ext, &xlsxWorksheetExt{URI: ExtURIPivotCacheDefinition, Content: string(content)})
+	extLstBytes, _ := xml.Marshal(decodeExtLst)
+	pc.ExtLst = &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}
+	pivotCache, err := xml.Marshal(pc)
+	f.saveFileList(part.pivotCacheXML, pivotCache)
+	return part.pt.CacheID, err
+}
+
+// addTimelineCache provides a function to create a timeline cache for the
+// pivot table by given pivot table parts, the index of the cache field, the
+// pivot cache ID and the date range, and returns the name of the timeline
+// cache. The name of the timeline cache will be added as a defined name of
+// the workbook.
+func (f *File) addTimelineCache(part *pivotTablePart, field, pivotCacheID int, bounds *xlsxTimelineRange) (string, error) {
+	sourceName := part.pc.CacheFields.CacheField[field].Name
+	cacheName := "NativeTimeline_" + timelineCacheNameExp.ReplaceAllString(sourceName, "_")
+	for idx := 1; f.getDefinedNameIndex(cacheName, nil) != -1; idx++ {
+		cacheName = fmt.Sprintf("NativeTimeline_%s%d", timelineCacheNameExp.ReplaceAllString(sourceName, "_"), idx)
+	}
+	if err := f.SetDefinedName(&DefinedName{Name: cacheName, RefersTo: "#N/A"}); err != nil {
+		return cacheName, err
+	}
+	timelineCacheID := f.countTimelineCaches() + 1
+	timelineCache, err := xml.Marshal(xlsxTimelineCacheDefinition{
+		Name:       cacheName,
+		SourceName: sourceName,
+		PivotTables: &xlsxTimelineCachePivotTables{
+			PivotTable: []*xlsxTimelineCachePivotTable{{TabID: f.getSheetID(part.sheet), Name: part.pt.Name}},
+		},
+		State: &xlsxTimelineState{
+			MinimalRefreshVersion: 6,
+			LastRefreshVersion:    6,
+			PivotCacheID:          pivotCacheID,
+			FilterType:            "unknown",
+			Bounds:                bounds,
+		},
+	})
+	if err != nil {
+		return cacheName, err
+	}
+	f.saveFileList(fmt.Sprintf("xl/timelineCaches/timelineCache%d.xml", timelineCacheID), timelineCache)
+	rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipTimelineCache, fmt.Sprintf("/xl/timelineCaches/timelineCache%d.xml", timelineCacheID), "")
+	wb, err := f.workbookReader()
+	if err != nil {
+		return "", err
+	}
+	if wb.ExtLst, err = f.appendTimelineRef(wb.ExtLst, ExtURITimelineCacheRefs, "timelineCacheRef", rID); err != nil {
+		return cacheName, err
+	}
+	f.addContentTypePart(timelineCacheID, "timelineCache")
+	return cacheName, err
+}
+
+// addTimeline provides a function to add the timeline to the timelines part
+// of the worksheet, the timelines part will be created if it doesn't exist.
+func (f *File) addTimeline(ws *xlsxWorksheet, sheet string, timeline *xlsxTimeline) error {
+	sheetPath := f.sheetMap[trimSheetName(sheet)]
+	sheetRels, timelineXML := getPartRelsPath(sheetPath), ""
+	rels, err := f.relsReader(sheetRels)
+	if err != nil {
+		return err
+	}
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipTimeline {
+				timelineXML = resolvePartPath(sheetPath, rel.Target)
+				break
+			}
+		}
+	}
+	timelines := &xlsxTimelines{}
+	if timelineXML == "" {
+		timelineID := f.countTimelines() + 1
+		timelineXML = fmt.Sprintf("xl/timelines/timeline%d.xml", timelineID)
+		rID := f.addRels(sheetRels, SourceRelationshipTimeline, fmt.Sprintf("../timelines/timeline%d.xml", timelineID), "")
+		var err error
+		if ws.ExtLst, err = f.appendTimelineRef(ws.ExtLst, ExtURITimelineRefs, "timelineRef", rID); err != nil {
+			return err
+		}
+		f.addSheetNameSpace(sheet, SourceRelationship)
+		f.addContentTypePart(timelineID, "timeline")
+	} else {
+		var err error
+		if timelines, err = f.timelinesReader(timelineXML); err != nil {
+			return err
+		}
+	}
+	timelines.Timeline = append(timelines.Timeline, timeline)
+	output, err := xml.Marshal(timelines)
+	f.saveFileList(timelineXML, output)
+	return err
+}
+
+// appendTimelineRef provides a function to append the relationship ID of the
+// timeline part or the timeline cache part to the references list in the
+// extension list by given extension URI and the element name of the
+// reference.
+func (f *File) appendTimelineRef(extLst *xlsxExtLst, uri, name string, rID int) (*xlsxExtLst, error) {
+	decodeExtLst := new(decodeWorksheetExt)
+	if extLst != nil {
+		if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + extLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return extLst, err
+		}
+	}
+	var ext *xlsxWorksheetExt
+	for _, e := range decodeExtLst.Ext {
+		if e.URI == uri {
+			ext = e
+			break
+		}
+	}
+	if ext == nil {
+		ext = &xlsxWorksheetExt{URI: uri}
+		decodeExtLst.Ext = append(decodeExtLst.Ext, ext)
+	}
+	decodeRefs := new(decodeX15TimelineRefs)
+	if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+		Decode(decodeRefs); err != nil && err != io.EOF {
+		return extLst, err
+	}
+	refs := xlsxX15TimelineRefs{
+		XMLName:  xml.Name{Local: "x15:" + name + "s"},
+		XMLNSX15: NameSpaceSpreadSheetX15.Value,
+	}
+	for _, ref := range decodeRefs.TimelineRef {
+		refs.TimelineRef = append(refs.TimelineRef, &xlsxX15TimelineRef{XMLName: xml.Name{Local: "x15:" + name}, RID: ref.RID})
+	}
+	refs.TimelineRef = append(refs.TimelineRef, &xlsxX15TimelineRef{XMLName: xml.Name{Local: "x15:" + name}, RID: "rId" + strconv.Itoa(rID)})
+	content, _ := xml.Marshal(refs)
+	ext.Content = string(content)
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	return &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}, err
+}
+
+// addDrawingTimeline provides a function to add the graphic frame of the
+// timeline to the drawing part of the worksheet by given timeline name and
+// timeline options.
+func (f *File) addDrawingTimeline(ws *xlsxWorksheet, sheet, name string, opt *TimelineOption) error {
+	col, row, err := CellNameToCoordinates(opt.Cell)
+	if err != nil {
+		return err
+	}
+	width, height := opt.Width, opt.Height
+	if width == 0 {
+		width = 320
+	}
+	if height == 0 {
+		height = 140
+	}
+	drawingID := f.countDrawings() + 1
+	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
+	drawingID, drawingXML = f.prepareDrawing(ws, drawingID, sheet, drawingXML)
+	colStart, rowStart, colEnd, rowEnd, x2, y2 :=
+		f.positionObjectPixels(sheet, col-1, row-1, opt.OffsetX, opt.OffsetY, width, height)
+	content, cNvPrID, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return err
+	}
+	graphicFrame, _ := xml.Marshal(xlsxGraphicFrame{
+		NvGraphicFramePr: xlsxNvGraphicFramePr{
+			CNvPr: &xlsxCNvPr{ID: cNvPrID, Name: name},
+		},
+		Graphic: &xlsxGraphic{
+			GraphicData: &xlsxGraphicData{
+				URI:        NameSpaceDrawingMLTimeSlicer,
+				TimeSlicer: &xlsxTimeSlicer{Tsle: NameSpaceDrawingMLTimeSlicer, Name: name},
+			},
+		},
+	})
+	alternateContent, _ := xml.Marshal(xlsxAlternateContent{
+		MC: SourceRelationshipCompatibility.Value,
+		Choice: &xlsxAlternateContentChoice{
+			Tsle:     NameSpaceDrawingMLTimeSlicer,
+			Requires: "tsle",
+			Content:  string(graphicFrame),
+		},
+	})
+	content.TwoCellAnchor = append(content.TwoCellAnchor, &xdrCellAnchor{
+		EditAs:       "oneCell",
+		From:         &xlsxFrom{Col: colStart, ColOff: opt.OffsetX * EMU, Row: rowStart, RowOff: opt.OffsetY * EMU},
+		To:           &xlsxTo{Col: colEnd, ColOff: x2 * EMU, Row: rowEnd, RowOff: y2 * EMU},
+		GraphicFrame: string(alternateContent),
+		ClientData:   &xdrClientData{FLocksWithSheet: true, FPrintsWithSheet: true},
+	})
+	f.Drawings[drawingXML] = content
+	f.addContentTypePart(drawingID, "drawings")
+	return err
+}
+
+// countTimelines provides a function to get timeline files count storage in
+// the folder xl/timelines.
+func (f *File) countTimelines() int {
+	count := 0
+	for k := range f.XLSX {
+		if strings.Contains(k, "xl/timelines/timeline") {
+			count++
+		}
+	}
+	return count
+}
+
+// countTimelineCaches provides a function to get timeline cache files count
+// storage in the folder xl/timelineCaches.
+func (f *File) countTimelineCaches() int {
+	count := 0
+	for k := range f.XLSX {
+		if strings.Contains(k, "xl/timelineCaches/timelineCache") {
+			count++
+		}
+	}
+	return count
+}
+
+// timelinesReader provides a function to get the pointer to the structure
+// after deserialization of the timelines part by given part path.
+func (f *File) timelinesReader(path string) (*xlsxTimelines, error) {
+	timelines := xlsxTimelines{}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(&timelines); err != nil && err != io.EOF {
+		return &timelines, err
+	}
+	return &timelines, nil
+}
@@ -0,0 +1,81 @@
+package excelize
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTimeline(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Order Date", "Type", "Sales"}))
+	for i := 0; i < 12; i++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &[]interface{}{time.Date(2019, time.Month(i*2+1), 15, 0, 0, 0, 0, time.UTC), "Meat", i * 100}))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		Name:            "PivotTable1",
+		DataRange:       "Sheet1!$A$1:$C$13",
+		PivotTableRange: "Sheet1!$E$2:$G$20",
+		Rows:            []PivotTableField{{Data: "Type"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	assert.NoError(t, f.AddTimeline("Sheet1", &TimelineOption{PivotTable: "pivottable1", Field: "Order Date", Level: "Quarters", Cell: "I2"}))
+	assert.NoError(t, f.AddTimeline("Sheet1", &TimelineOption{PivotTable: "PivotTable1", Field: "Order Date", Cell: "I12", Caption: "Date", Style: "TimeSlicerStyleLight2"}))
+
+	timelines, err := f.timelinesReader("xl/timelines/timeline1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, timelines.Timeline, 2)
+	assert.Equal(t, xlsxTimeline{Name: "Order Date", Cache: "NativeTimeline_Order_Date", Caption: "Order Date", Level: 1, SelectionLevel: 1, ScrollPosition: "2019-01-01T00:00:00"}, *timelines.Timeline[0])
+	assert.Equal(t, xlsxTimeline{Name: "Order Date 1", Cache: "NativeTimeline_Order_Date1", Caption: "Date", Level: 2, SelectionLevel: 2, ScrollPosition: "2019-01-01T00:00:00", Style: "TimeSlicerStyleLight2"}, *timelines.Timeline[1])
+	timelineCache := xlsxTimelineCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/timelineCaches/timelineCache2.xml"), &timelineCache))
+	assert.Equal(t, "NativeTimeline_Order_Date1", timelineCache.Name)
+	assert.Equal(t, "Order Date", timelineCache.SourceName)
+	assert.Equal(t, xlsxTimelineCachePivotTable{TabID: 1, Name: "PivotTable1"}, *timelineCache.PivotTables.PivotTable[0])
+	assert.Equal(t, 2, timelineCache.State.PivotCacheID)
+	assert.Equal(t, xlsxTimelineRange{StartDate: "2019-01-01T00:00:00", EndDate: "2021-01-01T00:00:00"}, *timelineCache.State.Bounds)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(ws.ExtLst.Ext, "<x15:timelineRef "))
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(wb.ExtLst.Ext, "<x15:timelineCacheRef "))
+	wb, err = f.workbookReader()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(wb.DefinedNames.DefinedName[0].Name+wb.DefinedNames.DefinedName[1].Name, "NativeTimeline_Order_Date"))
+	drawing, _, err := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, drawing.TwoCellAnchor, 2)
+	assert.Contains(t, drawing.TwoCellAnchor[1].GraphicFrame, `<tsle:timeslicer xmlns:tsle="http://schemas.microsoft.com/office/drawing/2012/timeslicer" name="Order Date 1">`)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddTimeline.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestAddTimeline.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddTimeline("Sheet1", &TimelineOption{Name: "Timeline", PivotTable: "PivotTable1", Field: "Order Date", Level: "days", Cell: "I22"}))
+	timelines, err = f.timelinesReader("xl/timelines/timeline1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, timelines.Timeline, 3)
+	wb, err = f.workbookReader()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(wb.ExtLst.Ext, "<x15:timelineCacheRef "))
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(pc.ExtLst.Ext, ExtURIPivotCacheDefinition))
+
+	// Test add timeline with invalid settings.
+	assert.EqualError(t, f.AddTimeline("SheetN", &TimelineOption{}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.AddTimeline("Sheet1", nil), "parameter is required")
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOption{Level: "weeks"}), "parameter 'Level' must be 'years', 'quarters', 'months' or 'days'")
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOption{Cell: "A"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOption{Cell: "A1", PivotTable: "PivotTable2"}), "pivot table PivotTable2 is not exist")
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOption{Cell: "A1", PivotTable: "PivotTable1", Field: "Date"}), "parameter 'Field' parsing error: field Date is not exist")
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOption{Cell: "A1", PivotTable: "PivotTable1", Field: "Type"}), "parameter 'Field' parsing error: field Type doesn't contain date values")
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOption{Name: "Timeline", Cell: "A1", PivotTable: "PivotTable1", Field: "Order Date"}), "the same timeline name Timeline already exists")
+	f.XLSX["xl/timelines/timeline1.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOption{Cell: "A1", PivotTable: "PivotTable1", Field: "Order Date"}), "XML syntax error on line 1: invalid UTF-8")
+}
@@ -0,0 +1,177 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"math"
+	"strings"
+)
+
+// Define the units of measurement in the English Metric Units (EMUs) used by
+// the drawing objects, 1 inch = 914400 EMUs = 72 points = 96 pixels at the
+// default 96 DPI.
+const (
+	EMUsPerInch       = 914400
+	EMUsPerCentimeter = 360000
+	EMUsPerPoint      = 12700
+	EMUsPerPixel      = 9525
+)
+
+// defaultMaxDigitWidth defined the maximum digit width in pixels of the
+// default font Calibri 11 of the workbook.
+const defaultMaxDigitWidth float64 = 7
+
+// fontDigitWidths defined the advance widths of the digit glyphs of the
+// common fonts in the units of the font size, which are used to calculate the
+// maximum digit width of the default font of the workbook.
+var fontDigitWidths = map[string]float64{
+	"arial":           0.5562,
+	"calibri":         0.5068,
+	"cambria":         0.5552,
+	"courier new":     0.6001,
+	"segoe ui":        0.5596,
+	"tahoma":          0.5459,
+	"times new roman": 0.5,
+	"verdana":         0.6357,
+}
+
+// PixelsToEMUs provides a function to convert the pixels to EMUs, which are
+// used by the offsets and sizes of the drawing objects, 1 pixel = 9525 EMUs.
+func PixelsToEMUs(pixels float64) int {
+	return int(math.Round(pixels * EMUsPerPixel))
+}
+
+// EMUsToPixels provides a function to convert the EMUs to pixels, 1 pixel =
+// 9525 EMUs.
+func EMUsToPixels(emus int) float64 {
+	return float64(emus) / EMUsPerPixel
+}
+
+// PointsToEMUs provides a function to convert the points to EMUs, which are
+// used by the line widths of the drawing objects, 1 point = 12700 EMUs.
+func PointsToEMUs(points float64) int {
+	return int(math.Round(points * EMUsPerPoint))
+}
+
+// EMUsToPoints provides a function to convert the EMUs to points, 1 point =
+// 12700 EMUs.
+func EMUsToPoints(emus int) float64 {
+	return float64(emus) / EMUsPerPoint
+}
+
+// PointsToPixels provides a function to convert the points to pixels at the
+// default 96 DPI, 1 point = 4/3 pixels. The row height set by SetRowHeight is
+// measured in points.
+func PointsToPixels(points float64) float64 {
+	return points * 4 / 3
+}
+
+// PixelsToPoints provides a function to convert the pixels to points at the
+// default 96 DPI, 1 pixel = 0.75 points.
+func PixelsToPoints(pixels float64) float64 {
+	return pixels * 0.75
+}
+
+// ColWidthToPixels provides a function to convert the column width, which is
+// used by SetColWidth and GetColWidth, to pixels by the maximum digit width
+// of the default font of the workbook. For example, get the width in pixels
+// of the column with the default width 9.140625, which is 64 pixels with the
+// default font Calibri 11:
+//
+//    pixels := f.ColWidthToPixels(9.140625)
+//
+func (f *File) ColWidthToPixels(width float64) float64 {
+	return colWidthToPixels(width, f.getMaxDigitWidth())
+}
+
+// PixelsToColWidth provides a function to convert the pixels to the column
+// width by the maximum digit width of the default font of the workbook, the
+// width will be truncated to 1/256 of the character. For example, set the
+// width of column A to fit the image with the width 200 pixels:
+//
+//    err := f.SetColWidth("Sheet1", "A", "A", f.PixelsToColWidth(200))
+//
+func (f *File) PixelsToColWidth(pixels float64) float64 {
+	return pixelsToColWidth(pixels, f.getMaxDigitWidth())
+}
+
+// CharsToColWidth provides a function to convert the number of characters,
+// which is displayed as the column width in Excel, to the column width with
+// the cell margins and the gridline. For example, set the width of column A
+// to 8.43 characters:
+//
+//    err := f.SetColWidth("Sheet1", "A", "A", f.CharsToColWidth(8.43))
+//
+func (f *File) CharsToColWidth(chars float64) float64 {
+	mdw := f.getMaxDigitWidth()
+	if chars <= 0 {
+		return 0
+	}
+	return math.Trunc((chars*mdw+5)/mdw*256) / 256
+}
+
+// ColWidthToChars provides a function to convert the column width to the
+// number of characters which is displayed as the column width in Excel, the
+// number will be rounded to 2 decimal places.
+func (f *File) ColWidthToChars(width float64) float64 {
+	mdw := f.getMaxDigitWidth()
+	pixels := colWidthToPixels(width, mdw)
+	if pixels <= 5 {
+		return 0
+	}
+	return math.Trunc((pixels-5)/mdw*100+0.5) / 100
+}
+
+// getMaxDigitWidth provides a function to get the maximum digit width in
+// pixels of the default font of the workbook, the digit width of the font
+// Calibri will be used for the unknown fonts.
+func (f *File) getMaxDigitWidth() float64 {
+	s, _ := f.stylesReader()
+	if s == nil || s.Fonts == nil || len(s.Fonts.Font) == 0 {
+		return defaultMaxDigitWidth
+	}
+	var (
+		font  = s.Fonts.Font[0]
+		size  = 11.0
+		ratio = fontDigitWidths["calibri"]
+	)
+	if font.Sz != nil && font.Sz.Val != nil && *font.Sz.Val > 0 {
+		size = *font.Sz.Val
+	}
+	if font.Name != nil && font.Name.Val != nil {
+		if r, ok := fontDigitWidths[strings.ToLower(*font.Name.Val)]; ok {
+			ratio = r
+		}
+	}
+	if width := math.Round(ratio * PointsToPixels(size)); width > 0 {
+		return width
+	}
+	return 1
+}
+
+// colWidthToPixels provides a function to convert the column width to pixels
+// by given maximum digit width.
+func colWidthToPixels(width, maxDigitWidth float64) float64 {
+	if width <= 0 {
+		return 0
+	}
+	return math.Trunc((256*width + math.Trunc(128/maxDigitWidth)) / 256 * maxDigitWidth)
+}
+
+// pixelsToColWidth provides a function to convert the pixels to the column
+// width by given maximum digit width.
+func pixelsToColWidth(pixels, maxDigitWidth float64) float64 {
+	if pixels <= 0 {
+		return 0
+	}
+	return math.Trunc(pixels/maxDigitWidth*256) / 256
+}
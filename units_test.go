@@ -0,0 +1,69 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitConversions(t *testing.T) {
+	assert.Equal(t, 9525, PixelsToEMUs(1))
+	assert.Equal(t, 1905000, PixelsToEMUs(200))
+	assert.Equal(t, 200.0, EMUsToPixels(1905000))
+	assert.Equal(t, EMU, EMUsPerPixel)
+	assert.Equal(t, 12700, PointsToEMUs(1))
+	assert.Equal(t, 28575, PointsToEMUs(2.25))
+	assert.Equal(t, 2.25, EMUsToPoints(28575))
+	assert.Equal(t, EMUsPerInch, PointsToEMUs(72))
+	assert.Equal(t, EMUsPerInch, PixelsToEMUs(96))
+	assert.Equal(t, 20.0, PointsToPixels(15))
+	assert.Equal(t, 15.0, PixelsToPoints(20))
+}
+
+func TestColWidthToPixels(t *testing.T) {
+	f := NewFile()
+	assert.Equal(t, 7.0, f.getMaxDigitWidth())
+	for width, pixels := range map[float64]float64{
+		0: 0, -1: 0, 0.5: 3, 1: 7, 9.140625: 64, 28.5703125: 200,
+	} {
+		assert.Equal(t, pixels, f.ColWidthToPixels(width), width)
+	}
+	for pixels, width := range map[float64]float64{
+		0: 0, 64: 9.140625, 200: 28.5703125,
+	} {
+		assert.Equal(t, width, f.PixelsToColWidth(pixels), pixels)
+	}
+	for pixels := 1.0; pixels <= 300; pixels++ {
+		assert.Equal(t, pixels, f.ColWidthToPixels(f.PixelsToColWidth(pixels)), pixels)
+	}
+	assert.Equal(t, 9.140625, f.CharsToColWidth(8.43))
+	assert.Equal(t, 8.43, f.ColWidthToChars(9.140625))
+	assert.Equal(t, 0.0, f.CharsToColWidth(0))
+	assert.Equal(t, 0.0, f.ColWidthToChars(0.5))
+
+	// Test convert the column width with the workbook's default font.
+	f.SetDefaultFont("Arial")
+	assert.Equal(t, 8.0, f.getMaxDigitWidth())
+	assert.Equal(t, 9.125, f.PixelsToColWidth(73))
+	s, err := f.stylesReader()
+	assert.NoError(t, err)
+	s.Fonts.Font[0].Sz.Val = float64Ptr(10)
+	assert.Equal(t, 7.0, f.getMaxDigitWidth())
+	s.Fonts.Font[0].Name.Val, s.Fonts.Font[0].Sz.Val = stringPtr("Times New Roman"), float64Ptr(12)
+	assert.Equal(t, 8.0, f.getMaxDigitWidth())
+	s.Fonts.Font[0].Name.Val, s.Fonts.Font[0].Sz = stringPtr("Unknown"), nil
+	assert.Equal(t, 7.0, f.getMaxDigitWidth())
+	s.Fonts.Font[0].Sz = &attrValFloat{Val: float64Ptr(1)}
+	assert.Equal(t, 1.0, f.getMaxDigitWidth())
+	s.Fonts = nil
+	assert.Equal(t, defaultMaxDigitWidth, f.getMaxDigitWidth())
+
+	// Test set the column width by the pixels.
+	f = NewFile()
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "A", f.PixelsToColWidth(200)))
+	width, err := f.GetColWidth("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 200.0, f.ColWidthToPixels(width))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestColWidthToPixels.xlsx")))
+}
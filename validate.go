@@ -0,0 +1,414 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationIssueType defined the type of the issue found by Validate.
+type ValidationIssueType byte
+
+// Validation issue types enumeration.
+const (
+	ValidationContentType ValidationIssueType = iota
+	ValidationRelationship
+	ValidationCellReference
+	ValidationOrphanedPart
+	ValidationStyleIndex
+	ValidationSharedStringIndex
+	ValidationMalformedXML
+)
+
+// ValidationIssue directly maps the issue found by Validate. The Part is the
+// path of the part in the spreadsheet which the issue was found in, the Sheet
+// and Cell are the worksheet name and the cell reference of the issue, they
+// will be empty if the issue is not related to the worksheet or cell.
+type ValidationIssue struct {
+	Type    ValidationIssueType
+	Part    string
+	Sheet   string
+	Cell    string
+	Message string
+}
+
+// Validate provides a function to check the integrity of the spreadsheet
+// including the unsaved changes, and get the issues which may cause the
+// spreadsheet applications to report unreadable content. The parts without
+// content type, the content type overrides of the missing parts, the
+// relationships with missing targets or duplicate IDs, the relationship IDs
+// referenced by the worksheets which don't exist, the invalid, duplicate,
+// unordered and out of range row numbers and cell references, the drawings
+// and charts which are not referenced by any relationship, and the style and
+// shared string indexes out of range will be reported. The issues will be
+// returned in the order of the parts, for example:
+//
+//    issues, err := f.Validate()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, issue := range issues {
+//        fmt.Println(issue.Part, issue.Sheet, issue.Cell, issue.Message)
+//    }
+//
+func (f *File) Validate() ([]ValidationIssue, error) {
+	var issues []ValidationIssue
+	parts := f.getValidationParts()
+	issues = append(issues, f.validateContentTypes(parts)...)
+	relIssues, targets := f.validateRelationships(parts)
+	issues = append(issues, relIssues...)
+	for _, part := range parts {
+		if (strings.HasPrefix(part, "xl/drawings/") || strings.HasPrefix(part, "xl/charts/")) &&
+			!strings.Contains(part, "/_rels/") && !targets[part] {
+			issues = append(issues, ValidationIssue{
+				Type: ValidationOrphanedPart, Part: part,
+				Message: fmt.Sprintf("part %s is not referenced by any relationship", part),
+			})
+		}
+	}
+	issues = append(issues, f.validateStyles()...)
+	for _, sheet := range f.GetSheetList() {
+		sheetIssues, err := f.validateSheet(sheet)
+		if err != nil {
+			return issues, err
+		}
+		issues = append(issues, sheetIssues...)
+	}
+	return issues, nil
+}
+
+// getValidationParts provides a function to get the sorted paths of the parts
+// in the spreadsheet including the unsaved parts.
+func (f *File) getValidationParts() []string {
+	exist := make(map[string]bool)
+	for part := range f.XLSX {
+		exist[part] = true
+	}
+	for part := range f.streams {
+		exist[part] = true
+	}
+	for part := range f.Sheet {
+		exist[part] = true
+	}
+	for part := range f.Drawings {
+		exist[part] = true
+	}
+	for part := range f.Comments {
+		exist[part] = true
+	}
+	for part := range f.VMLDrawing {
+		exist[part] = true
+	}
+	for part := range f.Relationships {
+		exist[part] = true
+	}
+	if f.SharedStrings != nil {
+		exist["xl/sharedStrings.xml"] = true
+	}
+	if f.CalcChain != nil && f.CalcChain.C != nil {
+		exist["xl/calcChain.xml"] = true
+	}
+	parts := make([]string, 0, len(exist))
+	for part := range exist {
+		parts = append(parts, part)
+	}
+	sort.Strings(parts)
+	return parts
+}
+
+// validateContentTypes provides a function to check the content types of the
+// given parts.
+func (f *File) validateContentTypes(parts []string) []ValidationIssue {
+	var (
+		issues    []ValidationIssue
+		exist     = make(map[string]bool)
+		overrides = make(map[string]bool)
+		defaults  = make(map[string]bool)
+	)
+	content, _ := f.contentTypesReader()
+	for _, override := range content.Overrides {
+		overrides[strings.TrimPrefix(override.PartName, "/")] = true
+	}
+	for _, def := range content.Defaults {
+		defaults[strings.ToLower(def.Extension)] = true
+	}
+	for _, part := range parts {
+		exist[part] = true
+		if part == "[Content_Types].xml" || overrides[part] {
+			continue
+		}
+		if !defaults[strings.ToLower(strings.TrimPrefix(path.Ext(part), "."))] {
+			issues = append(issues, ValidationIssue{
+				Type: ValidationContentType, Part: part,
+				Message: fmt.Sprintf("part %s has no content type", part),
+			})
+		}
+	}
+	for _, override := range content.Overrides {
+		if part := strings.TrimPrefix(override.PartName, "/"); !exist[part] {
+			issues = append(issues, ValidationIssue{
+				Type: ValidationContentType, Part: "[Content_Types].xml",
+				Message: fmt.Sprintf("content type override for part %s which doesn't exist", part),
+			})
+		}
+	}
+	return issues
+}
+
+// validateRelationships provides a function to check the targets and IDs of
+// the relationships in the given parts, and get the paths of the parts which
+// are the internal targets of the relationships.
+func (f *File) validateRelationships(parts []string) ([]ValidationIssue, map[string]bool) {
+	var (
+		issues  []ValidationIssue
+		exist   = make(map[string]bool)
+		targets = make(map[string]bool)
+	)
+	for _, part := range parts {
+		exist[part] = true
+	}
+	for _, part := range parts {
+		if path.Ext(part) != ".rels" {
+			continue
+		}
+		rels, _ := f.relsReader(part)
+		if rels == nil {
+			continue
+		}
+		source := strings.TrimSuffix(strings.Replace(part, "_rels/", "", 1), ".rels")
+		IDs := make(map[string]bool)
+		for _, rel := range rels.Relationships {
+			if IDs[rel.ID] {
+				issues = append(issues, ValidationIssue{
+					Type: ValidationRelationship, Part: part,
+					Message: fmt.Sprintf("duplicate relationship ID %s", rel.ID),
+				})
+			}
+			IDs[rel.ID] = true
+			if rel.TargetMode == "External" {
+				continue
+			}
+			target := resolveRelationshipTarget(exist, source, rel.Target)
+			if targets[target] = true; !exist[target] {
+				issues = append(issues, ValidationIssue{
+					Type: ValidationRelationship, Part: part,
+					Message: fmt.Sprintf("target %s of relationship %s doesn't exist", target, rel.ID),
+				})
+			}
+		}
+	}
+	return issues, targets
+}
+
+// resolveRelationshipTarget provides a function to get the path of the
+// internal relationship target by given existing parts, source part path and
+// target, the URL-escaped target will be unescaped if the part doesn't exist.
+func resolveRelationshipTarget(exist map[string]bool, source, target string) string {
+	target = resolvePartPath(source, target)
+	if unescaped, err := url.PathUnescape(target); err == nil && !exist[target] {
+		target = unescaped
+	}
+	return target
+}
+
+// validateStyles provides a function to check the font, fill, border and
+// number format indexes of the cell formats.
+func (f *File) validateStyles() []ValidationIssue {
+	var (
+		issues                []ValidationIssue
+		fonts, fills, borders int
+		numFmts               = make(map[int]bool)
+		s, _                  = f.stylesReader()
+	)
+	if s.Fonts != nil {
+		fonts = len(s.Fonts.Font)
+	}
+	if s.Fills != nil {
+		fills = len(s.Fills.Fill)
+	}
+	if s.Borders != nil {
+		borders = len(s.Borders.Border)
+	}
+	if s.NumFmts != nil {
+		for _, numFmt := range s.NumFmts.NumFmt {
+			numFmts[numFmt.NumFmtID] = true
+		}
+	}
+	if s.CellXfs == nil {
+		return issues
+	}
+	check := func(idx int, name string, ID *int, count int) {
+		if ID != nil && (*ID < 0 || *ID >= count) {
+			issues = append(issues, ValidationIssue{
+				Type: ValidationStyleIndex, Part: "xl/styles.xml",
+				Message: fmt.Sprintf("%s index %d of cell format %d out of range", name, *ID, idx),
+			})
+		}
+	}
+	for idx, xf := range s.CellXfs.Xf {
+		check(idx, "font", xf.FontID, fonts)
+		check(idx, "fill", xf.FillID, fills)
+		check(idx, "border", xf.BorderID, borders)
+		if xf.NumFmtID != nil && (*xf.NumFmtID < 0 || *xf.NumFmtID >= 164 && !numFmts[*xf.NumFmtID]) {
+			issues = append(issues, ValidationIssue{
+				Type: ValidationStyleIndex, Part: "xl/styles.xml",
+				Message: fmt.Sprintf("number format %d of cell format %d doesn't exist", *xf.NumFmtID, idx),
+			})
+		}
+	}
+	return issues
+}
+
+// validationSheetReader provides a function to get the reader of the
+// worksheet part including the unsaved changes by given part path.
+func (f *File) validationSheetReader(name string) (io.Reader, error) {
+	if stream, ok := f.streams[name]; ok {
+		return stream.rawData.Reader()
+	}
+	return bytes.NewReader(namespaceStrictToTransitional(f.readSheetXML(name))), nil
+}
+
+// validateSheet provides a function to check the relationship IDs, row
+// numbers, cell references, style indexes and shared string indexes in the
+// worksheet by given worksheet name.
+func (f *File) validateSheet(sheet string) ([]ValidationIssue, error) {
+	name := f.sheetMap[trimSheetName(sheet)]
+	if !strings.HasPrefix(name, "xl/worksheets/") {
+		return nil, nil
+	}
+	r, err := f.validationSheetReader(name)
+	if err != nil {
+		return nil, err
+	}
+	ws := new(xlsxWorksheet)
+	if err = f.xmlNewDecoder(r).Decode(ws); err != nil && err != io.EOF {
+		return nil, err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		issues []ValidationIssue
+		xfs    int
+		IDs    = make(map[string]bool)
+	)
+	if styles, _ := f.stylesReader(); styles.CellXfs != nil {
+		xfs = len(styles.CellXfs.Xf)
+	}
+	addIssue := func(typ ValidationIssueType, cell, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Type: typ, Part: name, Sheet: sheet, Cell: cell, Message: fmt.Sprintf(format, args...)})
+	}
+	rels, err := f.relsReader(getPartRelsPath(name))
+	if err != nil {
+		return nil, err
+	}
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			IDs[rel.ID] = true
+		}
+	}
+	checkID := func(element, ID string) {
+		if ID != "" && !IDs[ID] {
+			addIssue(ValidationRelationship, "", "relationship %s of %s doesn't exist", ID, element)
+		}
+	}
+	if ws.Drawing != nil {
+		checkID("drawing", ws.Drawing.RID)
+	}
+	if ws.LegacyDrawing != nil {
+		checkID("legacyDrawing", ws.LegacyDrawing.RID)
+	}
+	if ws.LegacyDrawingHF != nil {
+		checkID("legacyDrawingHF", ws.LegacyDrawingHF.RID)
+	}
+	if ws.Picture != nil {
+		checkID("picture", ws.Picture.RID)
+	}
+	if ws.TableParts != nil {
+		for _, tablePart := range ws.TableParts.TableParts {
+			checkID("tablePart", tablePart.RID)
+		}
+	}
+	if ws.Hyperlinks != nil {
+		for _, link := range ws.Hyperlinks.Hyperlink {
+			checkID("hyperlink", link.RID)
+		}
+	}
+	if ws.Cols != nil {
+		for _, col := range ws.Cols.Col {
+			if col.Style < 0 || col.Style >= xfs {
+				addIssue(ValidationStyleIndex, "", "style index %d of columns %d:%d out of range", col.Style, col.Min, col.Max)
+			}
+		}
+	}
+	var prevRow int
+	for _, row := range ws.SheetData.Row {
+		if row.R == 0 {
+			row.R = prevRow + 1
+		}
+		switch {
+		case row.R < 1 || row.R > TotalRows:
+			addIssue(ValidationCellReference, "", "row number %d out of range", row.R)
+		case row.R == prevRow:
+			addIssue(ValidationCellReference, "", "duplicate row number %d", row.R)
+		case row.R < prevRow:
+			addIssue(ValidationCellReference, "", "row number %d is not in ascending order", row.R)
+		}
+		prevRow = row.R
+		if row.CustomFormat && (row.S < 0 || row.S >= xfs) {
+			addIssue(ValidationStyleIndex, "", "style index %d of row %d out of range", row.S, row.R)
+		}
+		var prevCol int
+		for _, c := range row.C {
+			col := prevCol + 1
+			if c.R != "" {
+				cellCol, cellRow, err := CellNameToCoordinates(c.R)
+				if err != nil {
+					addIssue(ValidationCellReference, c.R, "invalid cell reference %s", c.R)
+					continue
+				}
+				if cellRow != row.R {
+					addIssue(ValidationCellReference, c.R, "cell %s is not in row %d", c.R, row.R)
+				}
+				col = cellCol
+			}
+			cell, _ := CoordinatesToCellName(col, row.R)
+			switch {
+			case col > TotalColumns:
+				addIssue(ValidationCellReference, c.R, "column number %d of cell out of range", col)
+			case col == prevCol:
+				addIssue(ValidationCellReference, cell, "duplicate cell reference %s", cell)
+			case col < prevCol:
+				addIssue(ValidationCellReference, cell, "cell %s is not in ascending order", cell)
+			}
+			prevCol = col
+			if c.S < 0 || c.S >= xfs {
+				addIssue(ValidationStyleIndex, cell, "style index %d of cell %s out of range", c.S, cell)
+			}
+			if c.T == "s" {
+				if idx, err := strconv.Atoi(strings.TrimSpace(c.V)); err != nil || idx < 0 || idx >= sst.len() {
+					addIssue(ValidationSharedStringIndex, cell, "shared string index %s of cell %s out of range", c.V, cell)
+				}
+			}
+		}
+	}
+	return issues, nil
+}
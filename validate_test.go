@@ -0,0 +1,81 @@
+package excelize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Validate"))
+	assert.NoError(t, f.AddChart("Sheet1", "D1", `{"type":"col","series":[{"name":"Sheet1!$A$1","values":"Sheet1!$B$1"}]}`))
+	assert.NoError(t, f.AddPicture("Sheet1", "H2", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddComment("Sheet1", "A2", `{"author":"Excelize","text":"Validate"}`))
+	assert.NoError(t, f.AddTable("Sheet1", "A5", "B8", `{}`))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A3", "https://github.com/360EntSecGroup-Skylar/excelize", "External"))
+	f.NewSheet("Sheet2")
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{1, "stream"}))
+	assert.NoError(t, sw.Flush())
+	issues, err := f.Validate()
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestValidate.xlsx")))
+	f, err = OpenFile(filepath.Join("test", "TestValidate.xlsx"))
+	assert.NoError(t, err)
+	issues, err = f.Validate()
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+
+	// Test validate the spreadsheet with issues
+	f = NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Validate"))
+	f.XLSX["xl/unknown.bin"] = []byte{}
+	f.XLSX["xl/drawings/drawing9.xml"] = []byte{}
+	f.ContentTypes.Overrides = append(f.ContentTypes.Overrides, xlsxOverride{PartName: "/xl/missing.xml", ContentType: ContentTypeDrawing})
+	rels, err := f.relsReader("xl/_rels/workbook.xml.rels")
+	assert.NoError(t, err)
+	rels.Relationships = append(rels.Relationships,
+		xlsxRelationship{ID: "rId9", Type: SourceRelationshipDrawingML, Target: "drawings/missing.xml"},
+		xlsxRelationship{ID: "rId9", Type: SourceRelationshipHyperLink, Target: "https://github.com", TargetMode: "External"},
+	)
+	fontID, numFmtID := 99, 200
+	f.Styles.CellXfs.Xf = append(f.Styles.CellXfs.Xf, xlsxXf{FontID: &fontID, NumFmtID: &numFmtID})
+	delete(f.Sheet, "xl/worksheets/sheet2.xml")
+	f.XLSX["xl/worksheets/sheet2.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<cols><col min="1" max="2" style="9"/></cols><sheetData>` +
+		`<row r="2" s="9" customFormat="1"><c r="B2"/><c r="B2"/><c r="A2"/><c r="C3"/><c r="A"/><c t="s"><v>9</v></c><c s="9"/></row>` +
+		`<row r="2"/><row r="1"/><row r="1048577"/></sheetData><drawing r:id="rId1"/></worksheet>`)
+	issues, err = f.Validate()
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationIssue{
+		{Type: ValidationContentType, Part: "xl/unknown.bin", Message: "part xl/unknown.bin has no content type"},
+		{Type: ValidationContentType, Part: "[Content_Types].xml", Message: "content type override for part xl/missing.xml which doesn't exist"},
+		{Type: ValidationRelationship, Part: "xl/_rels/workbook.xml.rels", Message: "target xl/drawings/missing.xml of relationship rId9 doesn't exist"},
+		{Type: ValidationRelationship, Part: "xl/_rels/workbook.xml.rels", Message: "duplicate relationship ID rId9"},
+		{Type: ValidationOrphanedPart, Part: "xl/drawings/drawing9.xml", Message: "part xl/drawings/drawing9.xml is not referenced by any relationship"},
+		{Type: ValidationStyleIndex, Part: "xl/styles.xml", Message: "font index 99 of cell format 1 out of range"},
+		{Type: ValidationStyleIndex, Part: "xl/styles.xml", Message: "number format 200 of cell format 1 doesn't exist"},
+		{Type: ValidationRelationship, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Message: "relationship rId1 of drawing doesn't exist"},
+		{Type: ValidationStyleIndex, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Message: "style index 9 of columns 1:2 out of range"},
+		{Type: ValidationStyleIndex, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Message: "style index 9 of row 2 out of range"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Cell: "B2", Message: "duplicate cell reference B2"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Cell: "A2", Message: "cell A2 is not in ascending order"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Cell: "C3", Message: "cell C3 is not in row 2"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Cell: "A", Message: "invalid cell reference A"},
+		{Type: ValidationSharedStringIndex, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Cell: "D2", Message: "shared string index 9 of cell D2 out of range"},
+		{Type: ValidationStyleIndex, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Cell: "E2", Message: "style index 9 of cell E2 out of range"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Message: "duplicate row number 2"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Message: "row number 1 is not in ascending order"},
+		{Type: ValidationCellReference, Part: "xl/worksheets/sheet2.xml", Sheet: "Sheet2", Message: "row number 1048577 out of range"},
+	}, issues)
+
+	// Test validate the worksheet with invalid XML
+	f.XLSX["xl/worksheets/sheet2.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="A"/></sheetData></worksheet>`)
+	_, err = f.Validate()
+	assert.EqualError(t, err, `strconv.ParseInt: parsing "A": invalid syntax`)
+}
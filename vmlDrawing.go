@@ -14,7 +14,11 @@ package excelize
 import "encoding/xml"
 
 // vmlDrawing directly maps the root element in the file
-// xl/drawings/vmlDrawing%d.vml.
+// xl/drawings/vmlDrawing%d.vml. For the existing VML drawing part, the Head
+// and Content fields hold the original root start tag and the elements
+// except the shapes, and the Raw field of the shape holds the original
+// shape, so that the legacy drawing objects such as form controls can be
+// kept as they are.
 type vmlDrawing struct {
 	XMLName     xml.Name         `xml:"xml"`
 	XMLNSv      string           `xml:"xmlns:v,attr"`
@@ -24,6 +28,8 @@ type vmlDrawing struct {
 	Shapelayout *xlsxShapelayout `xml:"o:shapelayout"`
 	Shapetype   *xlsxShapetype   `xml:"v:shapetype"`
 	Shape       []xlsxShape      `xml:"v:shape"`
+	Head        string           `xml:"-"`
+	Content     string           `xml:"-"`
 }
 
 // xlsxShapelayout directly maps the shapelayout element. This element contains
@@ -50,6 +56,7 @@ type xlsxShape struct {
 	Insetmode   string   `xml:"urn:schemas-microsoft-com:office:office insetmode,attr,omitempty"`
 	Strokecolor string   `xml:"strokecolor,attr,omitempty"`
 	Val         string   `xml:",innerxml"`
+	Raw         string   `xml:"-"`
 }
 
 // xlsxShapetype directly maps the shapetype element.
@@ -116,13 +123,14 @@ type xlsxDiv struct {
 // child elements is appropriate. Relevant groups are identified for each child
 // element.
 type xClientData struct {
-	ObjectType    string `xml:"ObjectType,attr"`
-	MoveWithCells string `xml:"x:MoveWithCells,omitempty"`
-	SizeWithCells string `xml:"x:SizeWithCells,omitempty"`
-	Anchor        string `xml:"x:Anchor"`
-	AutoFill      string `xml:"x:AutoFill"`
-	Row           int    `xml:"x:Row"`
-	Column        int    `xml:"x:Column"`
+	ObjectType    string  `xml:"ObjectType,attr"`
+	MoveWithCells string  `xml:"x:MoveWithCells,omitempty"`
+	SizeWithCells string  `xml:"x:SizeWithCells,omitempty"`
+	Anchor        string  `xml:"x:Anchor"`
+	AutoFill      string  `xml:"x:AutoFill"`
+	Row           int     `xml:"x:Row"`
+	Column        int     `xml:"x:Column"`
+	Visible       *string `xml:"x:Visible"`
 }
 
 // decodeVmlDrawing defines the structure used to parse the file
@@ -133,7 +141,25 @@ type decodeVmlDrawing struct {
 
 // decodeShape defines the structure used to parse the particular shape element.
 type decodeShape struct {
-	Val string `xml:",innerxml"`
+	ID    string `xml:"id,attr"`
+	Style string `xml:"style,attr"`
+	Val   string `xml:",innerxml"`
+}
+
+// decodeShapeVal defines the structure used to parse the sub-element of the
+// shape in the file xl/drawings/vmlDrawing%d.vml.
+type decodeShapeVal struct {
+	ClientData decodeVMLClientData `xml:"ClientData"`
+}
+
+// decodeVMLClientData defines the structure used to parse the x:ClientData
+// element in the file xl/drawings/vmlDrawing%d.vml.
+type decodeVMLClientData struct {
+	ObjectType string  `xml:"ObjectType,attr"`
+	Visible    *string `xml:"Visible"`
+	Anchor     string  `xml:"Anchor"`
+	Row        int     `xml:"Row"`
+	Column     int     `xml:"Column"`
 }
 
 // encodeShape defines the structure used to re-serialization shape element.
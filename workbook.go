@@ -0,0 +1,777 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ProtectWorkbook provides a function to prevent other users from viewing
+// hidden worksheets, adding, moving, deleting or hiding worksheets, and
+// renaming worksheets in a workbook, or changing the size and position of the
+// workbook windows. The password will be hashed with the given algorithm and
+// a random salt by 100000 spin count, the default hash algorithm is SHA-512.
+// Support hash algorithm: MD4, MD5, SHA-1, SHA-256, SHA-384 and SHA-512. For
+// example, protect the structure of the workbook with password:
+//
+//    err := f.ProtectWorkbook(&excelize.FormatWorkbookProtection{
+//        Password:      "password",
+//        LockStructure: true,
+//    })
+//
+func (f *File) ProtectWorkbook(settings *FormatWorkbookProtection) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		settings = &FormatWorkbookProtection{LockStructure: true}
+	}
+	wb.WorkbookProtection = &xlsxWorkbookProtection{
+		LockStructure: settings.LockStructure,
+		LockWindows:   settings.LockWindows,
+	}
+	if settings.Password != "" {
+		algorithmName := settings.AlgorithmName
+		if algorithmName == "" {
+			algorithmName = "SHA-512"
+		}
+		hashValue, saltValue, err := genISOPasswdHash(settings.Password, algorithmName, "", workbookProtectionSpinCount)
+		if err != nil {
+			return err
+		}
+		wb.WorkbookProtection.WorkbookAlgorithmName = algorithmName
+		wb.WorkbookProtection.WorkbookHashValue = hashValue
+		wb.WorkbookProtection.WorkbookSaltValue = saltValue
+		wb.WorkbookProtection.WorkbookSpinCount = workbookProtectionSpinCount
+	}
+	return nil
+}
+
+// GetWorkbookProtection provides a function to get the protection settings of
+// the workbook. The password of the settings will be empty, since only the
+// hash value of the password is stored in the workbook.
+func (f *File) GetWorkbookProtection() FormatWorkbookProtection {
+	var settings FormatWorkbookProtection
+	wb, _ := f.workbookReader()
+	if wb.WorkbookProtection != nil {
+		settings.AlgorithmName = wb.WorkbookProtection.WorkbookAlgorithmName
+		settings.LockStructure = wb.WorkbookProtection.LockStructure
+		settings.LockWindows = wb.WorkbookProtection.LockWindows
+	}
+	return settings
+}
+
+// UnprotectWorkbook provides a function to remove protection for workbook.
+// Specify the optional password parameter to verify the password before
+// removing the protection, it will return an error if the password is not
+// matched. For example:
+//
+//    err := f.UnprotectWorkbook("password")
+//
+func (f *File) UnprotectWorkbook(password ...string) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if len(password) > 0 && wb.WorkbookProtection != nil && wb.WorkbookProtection.WorkbookHashValue != "" {
+		hashValue, _, err := genISOPasswdHash(password[0], wb.WorkbookProtection.WorkbookAlgorithmName,
+			wb.WorkbookProtection.WorkbookSaltValue, wb.WorkbookProtection.WorkbookSpinCount)
+		if err != nil {
+			return err
+		}
+		if hashValue != wb.WorkbookProtection.WorkbookHashValue {
+			return errors.New("incorrect workbook protection password")
+		}
+	}
+	wb.WorkbookProtection = nil
+	return nil
+}
+
+// CalcPrOption is an option of the calculation properties of the workbook.
+// See SetCalcPrOptions().
+type CalcPrOption interface {
+	setCalcPrOption(pr *xlsxCalcPr)
+}
+
+// CalcPrOptionPtr is a writable CalcPrOption. See GetCalcPrOptions().
+type CalcPrOptionPtr interface {
+	CalcPrOption
+	getCalcPrOption(pr *xlsxCalcPr)
+}
+
+type (
+	// CalcID is a CalcPrOption
+	CalcID int
+	// CalcMode is a CalcPrOption
+	CalcMode string
+	// CalcOnSave is a CalcPrOption
+	CalcOnSave bool
+	// ForceFullCalc is a CalcPrOption
+	ForceFullCalc bool
+	// FullCalcOnLoad is a CalcPrOption
+	FullCalcOnLoad bool
+	// RefMode is a CalcPrOption
+	RefMode string
+)
+
+// setCalcPrOption implements the CalcPrOption interface and specifies the
+// version of the calculation engine used to calculate values in the workbook.
+func (o CalcID) setCalcPrOption(pr *xlsxCalcPr) {
+	pr.CalcID = ""
+	if o != 0 {
+		pr.CalcID = strconv.Itoa(int(o))
+	}
+}
+
+// getCalcPrOption implements the CalcPrOptionPtr interface and get the
+// version of the calculation engine.
+func (o *CalcID) getCalcPrOption(pr *xlsxCalcPr) {
+	if pr == nil {
+		*o = 0
+		return
+	}
+	id, _ := strconv.Atoi(pr.CalcID)
+	*o = CalcID(id)
+}
+
+// setCalcPrOption implements the CalcPrOption interface and specifies when
+// the application shall calculate formulas in the workbook. Possible values
+// are manual, auto and autoNoTable.
+func (o CalcMode) setCalcPrOption(pr *xlsxCalcPr) {
+	pr.CalcMode = string(o)
+}
+
+// getCalcPrOption implements the CalcPrOptionPtr interface and get the
+// calculation mode of the workbook.
+func (o *CalcMode) getCalcPrOption(pr *xlsxCalcPr) {
+	// Excel default: auto
+	if pr == nil || pr.CalcMode == "" {
+		*o = "auto"
+		return
+	}
+	*o = CalcMode(pr.CalcMode)
+}
+
+// setCalcPrOption implements the CalcPrOption interface and flag indicating
+// whether the application shall recalculate the workbook before saving.
+func (o CalcOnSave) setCalcPrOption(pr *xlsxCalcPr) {
+	pr.CalcOnSave = bool(o)
+}
+
+// getCalcPrOption implements the CalcPrOptionPtr interface and get the
+// settings of whether recalculate the workbook before saving.
+func (o *CalcOnSave) getCalcPrOption(pr *xlsxCalcPr) {
+	if pr == nil {
+		*o = false
+		return
+	}
+	*o = CalcOnSave(pr.CalcOnSave)
+}
+
+// setCalcPrOption implements the CalcPrOption interface and flag indicating
+// whether the application shall calculate all formulas every time a
+// calculation is triggered.
+func (o ForceFullCalc) setCalcPrOption(pr *xlsxCalcPr) {
+	pr.ForceFullCalc = bool(o)
+}
+
+// getCalcPrOption implements the CalcPrOptionPtr interface and get the
+// settings of whether calculate all formulas on every calculation.
+func (o *ForceFullCalc) getCalcPrOption(pr *xlsxCalcPr) {
+	if pr == nil {
+		*o = false
+		return
+	}
+	*o = ForceFullCalc(pr.ForceFullCalc)
+}
+
+// setCalcPrOption implements the CalcPrOption interface and flag indicating
+// whether the application shall perform a full calculation when the workbook
+// is opened.
+func (o FullCalcOnLoad) setCalcPrOption(pr *xlsxCalcPr) {
+	pr.FullCalcOnLoad = bool(o)
+}
+
+// getCalcPrOption implements the CalcPrOptionPtr interface and get the
+// settings of whether perform a full calculation when the workbook is opened.
+func (o *FullCalcOnLoad) getCalcPrOption(pr *xlsxCalcPr) {
+	if pr == nil {
+		*o = false
+		return
+	}
+	*o = FullCalcOnLoad(pr.FullCalcOnLoad)
+}
+
+// setCalcPrOption implements the CalcPrOption interface and specifies the
+// reference style of the formulas in the workbook. Possible values are A1 and
+// R1C1.
+func (o RefMode) setCalcPrOption(pr *xlsxCalcPr) {
+	pr.RefMode = string(o)
+}
+
+// getCalcPrOption implements the CalcPrOptionPtr interface and get the
+// reference style of the workbook.
+func (o *RefMode) getCalcPrOption(pr *xlsxCalcPr) {
+	// Excel default: A1
+	if pr == nil || pr.RefMode == "" {
+		*o = "A1"
+		return
+	}
+	*o = RefMode(pr.RefMode)
+}
+
+// SetCalcPrOptions provides a function to sets the calculation properties of
+// the workbook. For example, set the workbook calculated manually and
+// perform a full calculation when the workbook is opened:
+//
+//    err := f.SetCalcPrOptions(excelize.CalcMode("manual"), excelize.FullCalcOnLoad(true))
+//
+// Available options:
+//   CalcID(int)
+//   CalcMode(string)
+//   CalcOnSave(bool)
+//   ForceFullCalc(bool)
+//   FullCalcOnLoad(bool)
+//   RefMode(string)
+func (f *File) SetCalcPrOptions(opts ...CalcPrOption) error {
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case CalcMode:
+			if o != "" && o != "manual" && o != "auto" && o != "autoNoTable" {
+				return fmt.Errorf("invalid calculation mode %s", o)
+			}
+		case RefMode:
+			if o != "" && o != "A1" && o != "R1C1" {
+				return fmt.Errorf("invalid reference mode %s", o)
+			}
+		}
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.CalcPr == nil {
+		wb.CalcPr = new(xlsxCalcPr)
+	}
+	for _, opt := range opts {
+		opt.setCalcPrOption(wb.CalcPr)
+	}
+	return nil
+}
+
+// GetCalcPrOptions provides a function to gets the calculation properties of
+// the workbook.
+//
+// Available options:
+//   CalcID(int)
+//   CalcMode(string)
+//   CalcOnSave(bool)
+//   ForceFullCalc(bool)
+//   FullCalcOnLoad(bool)
+//   RefMode(string)
+func (f *File) GetCalcPrOptions(opts ...CalcPrOptionPtr) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		opt.getCalcPrOption(wb.CalcPr)
+	}
+	return nil
+}
+
+// WorkbookViewOption is an option of the first workbook view of the workbook.
+// See SetWorkbookViewOptions().
+type WorkbookViewOption interface {
+	setWorkbookViewOption(view *xlsxWorkBookView)
+}
+
+// WorkbookViewOptionPtr is a writable WorkbookViewOption. See
+// GetWorkbookViewOptions().
+type WorkbookViewOptionPtr interface {
+	WorkbookViewOption
+	getWorkbookViewOption(view *xlsxWorkBookView)
+}
+
+type (
+	// ActiveTab is a WorkbookViewOption
+	ActiveTab int
+	// FirstSheet is a WorkbookViewOption
+	FirstSheet int
+	// ShowHorizontalScroll is a WorkbookViewOption
+	ShowHorizontalScroll bool
+	// ShowSheetTabs is a WorkbookViewOption
+	ShowSheetTabs bool
+	// ShowVerticalScroll is a WorkbookViewOption
+	ShowVerticalScroll bool
+	// TabRatio is a WorkbookViewOption
+	TabRatio int
+	// WindowHeight is a WorkbookViewOption
+	WindowHeight int
+	// WindowWidth is a WorkbookViewOption
+	WindowWidth int
+	// XWindow is a WorkbookViewOption
+	XWindow int
+	// YWindow is a WorkbookViewOption
+	YWindow int
+)
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and
+// specifies the index of the active sheet in the workbook view.
+func (o ActiveTab) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.ActiveTab = int(o)
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the index of the active sheet.
+func (o *ActiveTab) getWorkbookViewOption(view *xlsxWorkBookView) {
+	*o = ActiveTab(view.ActiveTab)
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and
+// specifies the index of the first sheet displayed in the sheet tab bar.
+func (o FirstSheet) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.FirstSheet = int(o)
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the index of the first sheet displayed in the sheet tab bar.
+func (o *FirstSheet) getWorkbookViewOption(view *xlsxWorkBookView) {
+	*o = FirstSheet(view.FirstSheet)
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and flag
+// indicating whether to display the horizontal scroll bar.
+func (o ShowHorizontalScroll) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.ShowHorizontalScroll = boolPtr(bool(o))
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the settings of whether to display the horizontal scroll bar.
+func (o *ShowHorizontalScroll) getWorkbookViewOption(view *xlsxWorkBookView) {
+	// Excel default: true
+	*o = ShowHorizontalScroll(defaultTrue(view.ShowHorizontalScroll))
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and flag
+// indicating whether to display the sheet tabs.
+func (o ShowSheetTabs) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.ShowSheetTabs = boolPtr(bool(o))
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the settings of whether to display the sheet tabs.
+func (o *ShowSheetTabs) getWorkbookViewOption(view *xlsxWorkBookView) {
+	// Excel default: true
+	*o = ShowSheetTabs(defaultTrue(view.ShowSheetTabs))
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and flag
+// indicating whether to display the vertical scroll bar.
+func (o ShowVerticalScroll) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.ShowVerticalScroll = boolPtr(bool(o))
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the settings of whether to display the vertical scroll bar.
+func (o *ShowVerticalScroll) getWorkbookViewOption(view *xlsxWorkBookView) {
+	// Excel default: true
+	*o = ShowVerticalScroll(defaultTrue(view.ShowVerticalScroll))
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and
+// specifies ratio between the sheet tab bar and the horizontal scroll bar in
+// thousandths.
+func (o TabRatio) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.TabRatio = int(o)
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the ratio between the sheet tab bar and the horizontal scroll bar.
+func (o *TabRatio) getWorkbookViewOption(view *xlsxWorkBookView) {
+	// Excel default: 600
+	if view.TabRatio == 0 {
+		*o = 600
+		return
+	}
+	*o = TabRatio(view.TabRatio)
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and
+// specifies the height of the workbook window in twips.
+func (o WindowHeight) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.WindowHeight = int(o)
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the height of the workbook window.
+func (o *WindowHeight) getWorkbookViewOption(view *xlsxWorkBookView) {
+	*o = WindowHeight(view.WindowHeight)
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and
+// specifies the width of the workbook window in twips.
+func (o WindowWidth) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.WindowWidth = int(o)
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the width of the workbook window.
+func (o *WindowWidth) getWorkbookViewOption(view *xlsxWorkBookView) {
+	*o = WindowWidth(view.WindowWidth)
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and
+// specifies the X coordinate of the upper-left corner of the workbook window
+// in twips.
+func (o XWindow) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.XWindow = strconv.Itoa(int(o))
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the X coordinate of the upper-left corner of the workbook window.
+func (o *XWindow) getWorkbookViewOption(view *xlsxWorkBookView) {
+	x, _ := strconv.Atoi(view.XWindow)
+	*o = XWindow(x)
+}
+
+// setWorkbookViewOption implements the WorkbookViewOption interface and
+// specifies the Y coordinate of the upper-left corner of the workbook window
+// in twips.
+func (o YWindow) setWorkbookViewOption(view *xlsxWorkBookView) {
+	view.YWindow = strconv.Itoa(int(o))
+}
+
+// getWorkbookViewOption implements the WorkbookViewOptionPtr interface and
+// get the Y coordinate of the upper-left corner of the workbook window.
+func (o *YWindow) getWorkbookViewOption(view *xlsxWorkBookView) {
+	y, _ := strconv.Atoi(view.YWindow)
+	*o = YWindow(y)
+}
+
+// SetWorkbookViewOptions provides a function to sets the first workbook view
+// of the workbook, which controls the window state when the workbook is
+// opened. The ActiveTab option will also select the active worksheet like
+// SetActiveSheet. For example, set the size of the window, hide the sheet
+// tabs and display the second worksheet as the active one:
+//
+//    err := f.SetWorkbookViewOptions(
+//        excelize.WindowWidth(28800),
+//        excelize.WindowHeight(17620),
+//        excelize.ShowSheetTabs(false),
+//        excelize.ActiveTab(1),
+//    )
+//
+// Available options:
+//   ActiveTab(int)
+//   FirstSheet(int)
+//   ShowHorizontalScroll(bool)
+//   ShowSheetTabs(bool)
+//   ShowVerticalScroll(bool)
+//   TabRatio(int)
+//   WindowHeight(int)
+//   WindowWidth(int)
+//   XWindow(int)
+//   YWindow(int)
+func (f *File) SetWorkbookViewOptions(opts ...WorkbookViewOption) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case ActiveTab:
+			if o < 0 || int(o) >= len(wb.Sheets.Sheet) {
+				return fmt.Errorf("invalid active tab index %d", o)
+			}
+		case FirstSheet:
+			if o < 0 || int(o) >= len(wb.Sheets.Sheet) {
+				return fmt.Errorf("invalid first sheet index %d", o)
+			}
+		case TabRatio:
+			if o < 0 || o > 1000 {
+				return fmt.Errorf("invalid tab ratio %d", o)
+			}
+		}
+	}
+	if wb.BookViews == nil {
+		wb.BookViews = &xlsxBookViews{}
+	}
+	if len(wb.BookViews.WorkBookView) == 0 {
+		wb.BookViews.WorkBookView = append(wb.BookViews.WorkBookView, xlsxWorkBookView{})
+	}
+	for _, opt := range opts {
+		if o, ok := opt.(ActiveTab); ok {
+			f.SetActiveSheet(int(o))
+			continue
+		}
+		opt.setWorkbookViewOption(&wb.BookViews.WorkBookView[0])
+	}
+	return nil
+}
+
+// GetWorkbookViewOptions provides a function to gets the settings of the
+// first workbook view of the workbook.
+//
+// Available options:
+//   ActiveTab(int)
+//   FirstSheet(int)
+//   ShowHorizontalScroll(bool)
+//   ShowSheetTabs(bool)
+//   ShowVerticalScroll(bool)
+//   TabRatio(int)
+//   WindowHeight(int)
+//   WindowWidth(int)
+//   XWindow(int)
+//   YWindow(int)
+func (f *File) GetWorkbookViewOptions(opts ...WorkbookViewOptionPtr) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	view := xlsxWorkBookView{}
+	if wb.BookViews != nil && len(wb.BookViews.WorkBookView) > 0 {
+		view = wb.BookViews.WorkBookView[0]
+	}
+	for _, opt := range opts {
+		opt.getWorkbookViewOption(&view)
+	}
+	return nil
+}
+
+// SetWorkbookDateSystem provides a function to set the date system of the
+// workbook. The 1900 date system will be used by default, set date1904 as
+// true to use the 1904 date system, in which the serial value 0 represents
+// January 1, 1904. The date and time values set by SetCellValue, the date
+// formatted cell values get by GetCellValue, the date values of the charts
+// and the date functions of the formula calculation engine will be
+// converted with the date system of the workbook. Note that the existing
+// serial values of the cells will not be converted when changing the date
+// system, so the dates will be displayed as shifted by 1462 days. For
+// example:
+//
+//    err := f.SetWorkbookDateSystem(true)
+//
+func (f *File) SetWorkbookDateSystem(date1904 bool) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.WorkbookPr == nil {
+		wb.WorkbookPr = new(xlsxWorkbookPr)
+	}
+	wb.WorkbookPr.Date1904 = date1904
+	return nil
+}
+
+// GetWorkbookDateSystem provides a function to get the date system of the
+// workbook, it returns true if the workbook uses the 1904 date system.
+func (f *File) GetWorkbookDateSystem() bool {
+	return f.date1904()
+}
+
+// date1904 provides a function to check if the workbook uses the 1904 date
+// system.
+func (f *File) date1904() bool {
+	wb, _ := f.workbookReader()
+	return wb != nil && wb.WorkbookPr != nil && wb.WorkbookPr.Date1904
+}
+
+// Connection directly maps the settings of the external data connection of
+// the workbook. Type specifies the type of the data source, the possible
+// values are ODBC, DAO, File, Web, OLEDB, Text, ADO and DSP. ConnectionString
+// and Command specify the connection string and the command text of the
+// database connection, SourceFile specifies the path of the source file of
+// the file or text connection, and URL specifies the URL of the web query.
+// DataModel specifies the connection is used by the data model of the
+// workbook.
+type Connection struct {
+	ID               int
+	Name             string
+	Description      string
+	Type             string
+	ConnectionString string
+	Command          string
+	SourceFile       string
+	URL              string
+	RefreshOnLoad    bool
+	DataModel        bool
+}
+
+// connectionTypes defined the data source types of the connection.
+var connectionTypes = map[int]string{
+	1: "ODBC",
+	2: "DAO",
+	3: "File",
+	4: "Web",
+	5: "OLEDB",
+	6: "Text",
+	7: "ADO",
+	8: "DSP",
+}
+
+// GetConnections provides a function to get the external data connections
+// of the workbook, include the connections of the data model (Power Pivot).
+// The connections, the data model and the related parts of the workbook will
+// be kept as is when saving the workbook. For example, print the names of
+// the connections:
+//
+//    connections, err := f.GetConnections()
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, connection := range connections {
+//        fmt.Println(connection.ID, connection.Name, connection.Type)
+//    }
+//
+func (f *File) GetConnections() ([]Connection, error) {
+	var connections []Connection
+	conns, err := f.connectionsReader()
+	if err != nil || conns == nil {
+		return connections, err
+	}
+	for _, conn := range conns.Connection {
+		connection := Connection{
+			ID:            conn.ID,
+			Name:          conn.Name,
+			Description:   conn.Description,
+			Type:          connectionTypes[conn.Type],
+			SourceFile:    conn.SourceFile,
+			RefreshOnLoad: conn.RefreshOnLoad,
+		}
+		if connection.Type == "" && conn.Type != 0 {
+			connection.Type = strconv.Itoa(conn.Type)
+		}
+		if conn.DbPr != nil {
+			connection.ConnectionString, connection.Command = conn.DbPr.Connection, conn.DbPr.Command
+		}
+		if conn.TextPr != nil && conn.TextPr.SourceFile != "" {
+			connection.SourceFile = conn.TextPr.SourceFile
+		}
+		if conn.WebPr != nil {
+			connection.URL = conn.WebPr.URL
+		}
+		if connection.DataModel, err = f.isDataModelConnection(conn); err != nil {
+			return connections, err
+		}
+		connections = append(connections, connection)
+	}
+	return connections, err
+}
+
+// isDataModelConnection provides a function to check if the connection is
+// used by the data model of the workbook.
+func (f *File) isDataModelConnection(conn *xlsxConnection) (bool, error) {
+	if conn.ExtLst == nil {
+		return false, nil
+	}
+	decodeExtLst := new(decodeWorksheetExt)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + conn.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return false, err
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURIConnection {
+			continue
+		}
+		decodeConn := new(decodeX15Connection)
+		if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(decodeConn); err != nil && err != io.EOF {
+			return false, err
+		}
+		return decodeConn.Model, nil
+	}
+	return false, nil
+}
+
+// connectionsReader provides a function to get the pointer to the structure
+// after deserialization of the connections part of the workbook, and returns
+// nil if the workbook doesn't contain any connections.
+func (f *File) connectionsReader() (*xlsxConnections, error) {
+	var connectionsXML string
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return nil, err
+	}
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipConnections {
+				connectionsXML = resolvePartPath(f.getWorkbookPath(), rel.Target)
+				break
+			}
+		}
+	}
+	if connectionsXML == "" {
+		return nil, nil
+	}
+	connections := new(xlsxConnections)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(connectionsXML)))).
+		Decode(connections); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return connections, nil
+}
+
+// GetCustomViews provides a function to get the custom views of the
+// workbook. The custom views are created by the spreadsheet application, and
+// will be preserved when saving the workbook. For example:
+//
+//    for _, view := range f.GetCustomViews() {
+//        fmt.Println(view.Name, view.Sheets)
+//    }
+//
+func (f *File) GetCustomViews() []CustomView {
+	var views []CustomView
+	wb, _ := f.workbookReader()
+	if wb.CustomWorkbookViews == nil {
+		return views
+	}
+	for _, v := range wb.CustomWorkbookViews.CustomWorkbookView {
+		view := CustomView{IncludePrintSettings: true, IncludeHiddenRowCol: true}
+		if v.Name != nil {
+			view.Name = *v.Name
+		}
+		if v.GUID != nil {
+			view.GUID = *v.GUID
+		}
+		if v.PersonalView != nil {
+			view.PersonalView = *v.PersonalView
+		}
+		if v.IncludePrintSettings != nil {
+			view.IncludePrintSettings = *v.IncludePrintSettings
+		}
+		if v.IncludeHiddenRowCol != nil {
+			view.IncludeHiddenRowCol = *v.IncludeHiddenRowCol
+		}
+		for _, sheet := range f.GetSheetList() {
+			ws, err := f.workSheetReader(sheet)
+			if err != nil || ws.CustomSheetViews == nil {
+				continue
+			}
+			for _, sheetView := range ws.CustomSheetViews.CustomSheetView {
+				if strings.EqualFold(sheetView.GUID, view.GUID) {
+					view.Sheets = append(view.Sheets, sheet)
+					break
+				}
+			}
+		}
+		views = append(views, view)
+	}
+	return views
+}
@@ -0,0 +1,311 @@
+package excelize
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectWorkbook(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.ProtectWorkbook(nil))
+	assert.Equal(t, FormatWorkbookProtection{LockStructure: true}, f.GetWorkbookProtection())
+	settings := &FormatWorkbookProtection{
+		Password:      "password",
+		LockStructure: true,
+		LockWindows:   true,
+	}
+	assert.NoError(t, f.ProtectWorkbook(settings))
+	// Test the default algorithm name is not written back to the settings
+	assert.Equal(t, "", settings.AlgorithmName)
+	assert.Equal(t, FormatWorkbookProtection{AlgorithmName: "SHA-512", LockStructure: true, LockWindows: true}, f.GetWorkbookProtection())
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Equal(t, 100000, wb.WorkbookProtection.WorkbookSpinCount)
+	assert.Len(t, wb.WorkbookProtection.WorkbookSaltValue, 24)
+	assert.Len(t, wb.WorkbookProtection.WorkbookHashValue, 88)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestProtectWorkbook.xlsx")))
+
+	assert.EqualError(t, f.ProtectWorkbook(&FormatWorkbookProtection{
+		AlgorithmName: "RIPEMD-160",
+		Password:      "password",
+	}), "unsupported hash algorithm")
+}
+
+func TestUnprotectWorkbook(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.UnprotectWorkbook())
+	assert.NoError(t, f.ProtectWorkbook(&FormatWorkbookProtection{
+		AlgorithmName: "SHA-256",
+		Password:      "password",
+		LockStructure: true,
+	}))
+	assert.EqualError(t, f.UnprotectWorkbook("passwd"), "incorrect workbook protection password")
+	assert.NoError(t, f.UnprotectWorkbook("password"))
+	assert.Equal(t, FormatWorkbookProtection{}, f.GetWorkbookProtection())
+
+	f.WorkBook.WorkbookProtection = &xlsxWorkbookProtection{WorkbookAlgorithmName: "SHA-0", WorkbookHashValue: "hash"}
+	assert.EqualError(t, f.UnprotectWorkbook("password"), "unsupported hash algorithm")
+	f.WorkBook.WorkbookProtection = &xlsxWorkbookProtection{WorkbookAlgorithmName: "SHA-512", WorkbookHashValue: "hash", WorkbookSaltValue: "!"}
+	assert.EqualError(t, f.UnprotectWorkbook("password"), "illegal base64 data at input byte 0")
+	assert.NoError(t, f.UnprotectWorkbook())
+}
+
+func TestCalcPrOptions(t *testing.T) {
+	f := NewFile()
+	var (
+		calcID         CalcID
+		calcMode       CalcMode
+		calcOnSave     CalcOnSave
+		forceFullCalc  ForceFullCalc
+		fullCalcOnLoad FullCalcOnLoad
+		refMode        RefMode
+	)
+	assert.NoError(t, f.GetCalcPrOptions(&calcID, &calcMode, &calcOnSave, &forceFullCalc, &fullCalcOnLoad, &refMode))
+	assert.Equal(t, CalcID(122211), calcID)
+	assert.Equal(t, CalcMode("auto"), calcMode)
+	assert.Equal(t, RefMode("A1"), refMode)
+	assert.False(t, bool(fullCalcOnLoad))
+
+	assert.NoError(t, f.SetCalcPrOptions(CalcID(191029), CalcMode("manual"), CalcOnSave(true),
+		ForceFullCalc(true), FullCalcOnLoad(true), RefMode("R1C1")))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCalcPrOptions.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestCalcPrOptions.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.GetCalcPrOptions(&calcID, &calcMode, &calcOnSave, &forceFullCalc, &fullCalcOnLoad, &refMode))
+	assert.Equal(t, CalcID(191029), calcID)
+	assert.Equal(t, CalcMode("manual"), calcMode)
+	assert.True(t, bool(calcOnSave))
+	assert.True(t, bool(forceFullCalc))
+	assert.True(t, bool(fullCalcOnLoad))
+	assert.Equal(t, RefMode("R1C1"), refMode)
+
+	// Test get calculation properties without calcPr element
+	f.WorkBook.CalcPr = nil
+	assert.NoError(t, f.GetCalcPrOptions(&calcID, &calcMode, &calcOnSave, &forceFullCalc, &fullCalcOnLoad, &refMode))
+	assert.Equal(t, CalcID(0), calcID)
+	assert.Equal(t, CalcMode("auto"), calcMode)
+	assert.False(t, bool(calcOnSave))
+	assert.False(t, bool(forceFullCalc))
+	assert.False(t, bool(fullCalcOnLoad))
+	assert.Equal(t, RefMode("A1"), refMode)
+
+	assert.EqualError(t, f.SetCalcPrOptions(CalcMode("semiautomatic")), "invalid calculation mode semiautomatic")
+	assert.EqualError(t, f.SetCalcPrOptions(RefMode("R1")), "invalid reference mode R1")
+}
+
+func TestWorkbookViewOptions(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	f.NewSheet("Sheet3")
+	var (
+		activeTab            ActiveTab
+		firstSheet           FirstSheet
+		showHorizontalScroll ShowHorizontalScroll
+		showSheetTabs        ShowSheetTabs
+		showVerticalScroll   ShowVerticalScroll
+		tabRatio             TabRatio
+		windowHeight         WindowHeight
+		windowWidth          WindowWidth
+		xWindow              XWindow
+		yWindow              YWindow
+	)
+	opts := []WorkbookViewOptionPtr{&activeTab, &firstSheet, &showHorizontalScroll, &showSheetTabs,
+		&showVerticalScroll, &tabRatio, &windowHeight, &windowWidth, &xWindow, &yWindow}
+	assert.NoError(t, f.GetWorkbookViewOptions(opts...))
+	assert.Equal(t, ActiveTab(0), activeTab)
+	assert.Equal(t, ShowSheetTabs(true), showSheetTabs)
+	assert.Equal(t, TabRatio(600), tabRatio)
+	assert.Equal(t, WindowWidth(14805), windowWidth)
+	assert.Equal(t, WindowHeight(8010), windowHeight)
+
+	assert.NoError(t, f.SetWorkbookViewOptions(ActiveTab(2), FirstSheet(1), ShowHorizontalScroll(false),
+		ShowSheetTabs(false), ShowVerticalScroll(false), TabRatio(800), WindowHeight(17620),
+		WindowWidth(28800), XWindow(120), YWindow(240)))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestWorkbookViewOptions.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestWorkbookViewOptions.xlsx"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.GetActiveSheetIndex())
+	assert.NoError(t, f.GetWorkbookViewOptions(opts...))
+	assert.Equal(t, ActiveTab(2), activeTab)
+	assert.Equal(t, FirstSheet(1), firstSheet)
+	assert.Equal(t, ShowHorizontalScroll(false), showHorizontalScroll)
+	assert.Equal(t, ShowSheetTabs(false), showSheetTabs)
+	assert.Equal(t, ShowVerticalScroll(false), showVerticalScroll)
+	assert.Equal(t, TabRatio(800), tabRatio)
+	assert.Equal(t, WindowHeight(17620), windowHeight)
+	assert.Equal(t, WindowWidth(28800), windowWidth)
+	assert.Equal(t, XWindow(120), xWindow)
+	assert.Equal(t, YWindow(240), yWindow)
+
+	// Test set workbook view options without workbook view
+	f.WorkBook.BookViews = nil
+	assert.NoError(t, f.GetWorkbookViewOptions(&showSheetTabs))
+	assert.Equal(t, ShowSheetTabs(true), showSheetTabs)
+	assert.NoError(t, f.SetWorkbookViewOptions(WindowWidth(14805)))
+	assert.Equal(t, 14805, f.WorkBook.BookViews.WorkBookView[0].WindowWidth)
+
+	assert.EqualError(t, f.SetWorkbookViewOptions(ActiveTab(3)), "invalid active tab index 3")
+	assert.EqualError(t, f.SetWorkbookViewOptions(FirstSheet(-1)), "invalid first sheet index -1")
+	assert.EqualError(t, f.SetWorkbookViewOptions(TabRatio(1001)), "invalid tab ratio 1001")
+}
+
+func TestWorkbookDateSystem(t *testing.T) {
+	f := NewFile()
+	assert.False(t, f.GetWorkbookDateSystem())
+	assert.NoError(t, f.SetWorkbookDateSystem(true))
+	assert.True(t, f.GetWorkbookDateSystem())
+
+	date := time.Date(2020, 10, 21, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", date))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", time.Date(1903, 12, 31, 0, 0, 0, 0, time.UTC)))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "42663.5", ws.SheetData.Row[0].C[0].V)
+	assert.Equal(t, "1903-12-31T00:00:00Z", ws.SheetData.Row[1].C[0].V)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10/21/20 12:00", val)
+
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=DATE(2020,10,21)"))
+	result, err := f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2020-10-21 00:00:00 +0000 UTC", result)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=DATE(1903,12,31)"))
+	_, err = f.CalcCellValue("Sheet1", "B1")
+	assert.EqualError(t, err, "#NUM!")
+
+	assert.NoError(t, f.AddChart("Sheet1", "C1", `{"type":"col","series":[{"name":"Sheet1!$A$1","categories":"Sheet1!$A$1","values":"Sheet1!$A$1"}]}`))
+	assert.Contains(t, string(f.XLSX["xl/charts/chart1.xml"]), `<date1904 val="true">`)
+
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{date}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestWorkbookDateSystem.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestWorkbookDateSystem.xlsx"))
+	assert.NoError(t, err)
+	assert.True(t, f.GetWorkbookDateSystem())
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"42663.5"}}, rows)
+	assert.NoError(t, f.SetWorkbookDateSystem(false))
+	assert.False(t, f.GetWorkbookDateSystem())
+}
+
+func TestGetConnections(t *testing.T) {
+	f := NewFile()
+	connections, err := f.GetConnections()
+	assert.NoError(t, err)
+	assert.Empty(t, connections)
+
+	modelData := []byte{0x00, 0x01, 0x02, 0xff}
+	f.XLSX["xl/model/item.data"] = modelData
+	f.XLSX["xl/connections.xml"] = []byte(`<connections xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" mc:Ignorable="xr16" xmlns:xr16="http://schemas.microsoft.com/office/spreadsheetml/2017/revision16">` +
+		`<connection id="1" keepAlive="1" name="ThisWorkbookDataModel" description="Data Model" type="5" refreshedVersion="6" minRefreshableVersion="5" background="1"><dbPr connection="Data Model Connection" command="Model" commandType="1"/><extLst><ext uri="{DE250136-89BD-433C-8126-D09CA5730AF9}" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"><x15:connection id="" model="1"/></ext></extLst></connection>` +
+		`<connection id="2" name="Sales" type="6" refreshedVersion="6" refreshOnLoad="1"><textPr sourceFile="C:\Data\Sales.csv" delimited="1"><textFields><textField/></textFields></textPr></connection>` +
+		`<connection id="3" name="Rates" type="4" refreshedVersion="6"><webPr url="https://example.com/rates" htmlTables="1"/></connection>` +
+		`<connection id="4" name="Unknown" type="9" refreshedVersion="6"/></connections>`)
+	f.addRels(f.getWorkbookRelsPath(), SourceRelationshipConnections, "connections.xml", "")
+	f.addRels(f.getWorkbookRelsPath(), "http://schemas.microsoft.com/office/2007/relationships/powerPivotData", "model/item.data", "")
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.ExtLst = &xlsxExtLst{Ext: `<ext uri="{FCE2AD5D-F65C-4FA6-A056-5C36A1767C68}" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"><x15:dataModel><x15:modelTables><x15:modelTable id="Sales" name="Sales" connection="Sales"/></x15:modelTables></x15:dataModel></ext>`}
+	wb.FileRecoveryPr = &xlsxFileRecoveryPr{RepairLoad: true}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetConnections.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestGetConnections.xlsx"))
+	assert.NoError(t, err)
+	assert.Equal(t, modelData, f.XLSX["xl/model/item.data"])
+	workbook := string(f.readXML("xl/workbook.xml"))
+	assert.Contains(t, workbook, `<x15:modelTable id="Sales" name="Sales" connection="Sales"/>`)
+	assert.True(t, strings.Index(workbook, "<fileRecoveryPr") < strings.Index(workbook, "<extLst>"))
+	connections, err = f.GetConnections()
+	assert.NoError(t, err)
+	assert.Equal(t, []Connection{
+		{ID: 1, Name: "ThisWorkbookDataModel", Description: "Data Model", Type: "OLEDB", ConnectionString: "Data Model Connection", Command: "Model", DataModel: true},
+		{ID: 2, Name: "Sales", Type: "Text", SourceFile: `C:\Data\Sales.csv`, RefreshOnLoad: true},
+		{ID: 3, Name: "Rates", Type: "Web", URL: "https://example.com/rates"},
+		{ID: 4, Name: "Unknown", Type: "9"},
+	}, connections)
+
+	// Test get connections with unsupported charset.
+	f.XLSX["xl/connections.xml"] = MacintoshCyrillicCharset
+	_, err = f.GetConnections()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.isDataModelConnection(&xlsxConnection{ExtLst: &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.isDataModelConnection(&xlsxConnection{ExtLst: &xlsxExtLst{Ext: `<ext uri="{DE250136-89BD-433C-8126-D09CA5730AF9}">` + string(MacintoshCyrillicCharset) + `</ext>`}})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetCustomViews(t *testing.T) {
+	f := NewFile()
+	assert.Len(t, f.GetCustomViews(), 0)
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData/>` +
+		`<scenarios current="0" show="0" sqref="B1"><scenario name="Best" locked="1" count="1" user="User" comment="Comment"><inputCells r="B1" val="100" numFmtId="0"/></scenario></scenarios>` +
+		`<customSheetViews><customSheetView guid="{3F1C5A0E-1E5B-4A8B-9C7A-6D7B2E6B1C01}" showGridLines="0" zeroValues="0"><pane xSplit="1" topLeftCell="B1" activePane="topRight" state="frozen"/></customSheetView></customSheetViews>` +
+		`<cellWatches><cellWatch r="A1"/><cellWatch r="B1"/></cellWatches></worksheet>`)
+	f.NewSheet("Sheet2")
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.CustomWorkbookViews = &xlsxCustomWorkbookViews{
+		CustomWorkbookView: []xlsxCustomWorkbookView{
+			{
+				Name:                 stringPtr("View1"),
+				GUID:                 stringPtr("{3F1C5A0E-1E5B-4A8B-9C7A-6D7B2E6B1C01}"),
+				IncludePrintSettings: boolPtr(false),
+				ActiveSheetID:        intPtr(0),
+			},
+			{
+				Name:         stringPtr("View2"),
+				GUID:         stringPtr("{6A4F0F57-2D7E-4D8E-8B0B-0C2F5F6B9E02}"),
+				PersonalView: boolPtr(true),
+			},
+		},
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetCustomViews.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestGetCustomViews.xlsx"))
+	assert.NoError(t, err)
+	assert.Equal(t, []CustomView{
+		{
+			Name:                "View1",
+			GUID:                "{3F1C5A0E-1E5B-4A8B-9C7A-6D7B2E6B1C01}",
+			IncludeHiddenRowCol: true,
+			Sheets:              []string{"Sheet1"},
+		},
+		{
+			Name:                 "View2",
+			GUID:                 "{6A4F0F57-2D7E-4D8E-8B0B-0C2F5F6B9E02}",
+			PersonalView:         true,
+			IncludePrintSettings: true,
+			IncludeHiddenRowCol:  true,
+		},
+	}, f.GetCustomViews())
+	// Test the scenarios, custom sheet views and cell watches are preserved
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxScenarios{
+		Current: intPtr(0),
+		Show:    intPtr(0),
+		Sqref:   "B1",
+		Scenario: []*xlsxScenario{{
+			Name: "Best", Locked: true, Count: 1, User: "User", Comment: "Comment",
+			InputCells: []*xlsxInputCells{{R: "B1", Val: "100", NumFmtID: intPtr(0)}},
+		}},
+	}, ws.Scenarios)
+	view := ws.CustomSheetViews.CustomSheetView[0]
+	assert.Equal(t, boolPtr(false), view.ShowGridLines)
+	assert.Equal(t, boolPtr(false), view.ZeroValues)
+	assert.Nil(t, view.ShowRowCol)
+	assert.Equal(t, "frozen", view.Pane.State)
+	assert.Equal(t, &xlsxCellWatches{CellWatch: []*xlsxCellWatch{{R: "A1"}, {R: "B1"}}}, ws.CellWatches)
+}
@@ -401,6 +401,7 @@ type cSer struct {
 	DLbls            *cDLbls      `xml:"dLbls"`
 	Marker           *cMarker     `xml:"marker"`
 	InvertIfNegative *attrValBool `xml:"invertIfNegative"`
+	Trendline        *cTrendline  `xml:"trendline"`
 	Cat              *cCat        `xml:"cat"`
 	Val              *cVal        `xml:"val"`
 	XVal             *cCat        `xml:"xVal"`
@@ -410,6 +411,19 @@ type cSer struct {
 	Bubble3D         *attrValBool `xml:"bubble3D"`
 }
 
+// cTrendline (Trendline) directly maps the trendline element. This element
+// specifies a trendline of the series.
+type cTrendline struct {
+	Name          string         `xml:"name,omitempty"`
+	TrendlineType *attrValString `xml:"trendlineType"`
+	Order         *attrValInt    `xml:"order"`
+	Period        *attrValInt    `xml:"period"`
+	Forward       *attrValFloat  `xml:"forward"`
+	Backward      *attrValFloat  `xml:"backward"`
+	DispRSqr      *attrValBool   `xml:"dispRSqr"`
+	DispEq        *attrValBool   `xml:"dispEq"`
+}
+
 // cMarker (Marker) directly maps the marker element. This element specifies a
 // data marker.
 type cMarker struct {
@@ -518,8 +532,8 @@ type cPageMargins struct {
 	T      float64 `xml:"t,attr"`
 }
 
-// formatChartAxis directly maps the format settings of the chart axis.
-type formatChartAxis struct {
+// ChartAxis directly maps the format settings of the chart axis.
+type ChartAxis struct {
 	Crossing            string  `json:"crossing"`
 	MajorGridlines      bool    `json:"major_grid_lines"`
 	MinorGridlines      bool    `json:"minor_grid_lines"`
@@ -542,25 +556,27 @@ type formatChartAxis struct {
 		Italic    bool   `json:"italic"`
 		Underline bool   `json:"underline"`
 	} `json:"num_font"`
-	LogBase    float64      `json:"logbase"`
-	NameLayout formatLayout `json:"name_layout"`
+	LogBase    float64     `json:"logbase"`
+	NameLayout ChartLayout `json:"name_layout"`
 }
 
-type formatChartDimension struct {
+// ChartDimension directly maps the dimension of the chart.
+type ChartDimension struct {
 	Width  int `json:"width"`
 	Height int `json:"height"`
 }
 
-// formatChart directly maps the format settings of the chart.
-type formatChart struct {
-	Type      string               `json:"type"`
-	Series    []formatChartSeries  `json:"series"`
-	Format    formatPicture        `json:"format"`
-	Dimension formatChartDimension `json:"dimension"`
-	Legend    formatChartLegend    `json:"legend"`
-	Title     formatChartTitle     `json:"title"`
-	XAxis     formatChartAxis      `json:"x_axis"`
-	YAxis     formatChartAxis      `json:"y_axis"`
+// Chart directly maps the format settings of the chart, which can be used
+// by AddChart and AddChartSheet instead of the JSON format string.
+type Chart struct {
+	Type      string         `json:"type"`
+	Series    []ChartSeries  `json:"series"`
+	Format    PictureFormat  `json:"format"`
+	Dimension ChartDimension `json:"dimension"`
+	Legend    ChartLegend    `json:"legend"`
+	Title     ChartTitle     `json:"title"`
+	XAxis     ChartAxis      `json:"x_axis"`
+	YAxis     ChartAxis      `json:"y_axis"`
 	Chartarea struct {
 		Border struct {
 			None bool `json:"none"`
@@ -592,7 +608,7 @@ type formatChart struct {
 		Fill struct {
 			Color string `json:"color"`
 		} `json:"fill"`
-		Layout formatLayout `json:"layout"`
+		Layout ChartLayout `json:"layout"`
 	} `json:"plotarea"`
 	ShowBlanksAs   string `json:"show_blanks_as"`
 	ShowHiddenData bool   `json:"show_hidden_data"`
@@ -601,19 +617,19 @@ type formatChart struct {
 	order          int
 }
 
-// formatChartLegend directly maps the format settings of the chart legend.
-type formatChartLegend struct {
-	None            bool         `json:"none"`
-	DeleteSeries    []int        `json:"delete_series"`
-	Font            Font         `json:"font"`
-	Layout          formatLayout `json:"layout"`
-	Position        string       `json:"position"`
-	ShowLegendEntry bool         `json:"show_legend_entry"`
-	ShowLegendKey   bool         `json:"show_legend_key"`
+// ChartLegend directly maps the format settings of the chart legend.
+type ChartLegend struct {
+	None            bool        `json:"none"`
+	DeleteSeries    []int       `json:"delete_series"`
+	Font            Font        `json:"font"`
+	Layout          ChartLayout `json:"layout"`
+	Position        string      `json:"position"`
+	ShowLegendEntry bool        `json:"show_legend_entry"`
+	ShowLegendKey   bool        `json:"show_legend_key"`
 }
 
-// formatChartSeries directly maps the format settings of the chart series.
-type formatChartSeries struct {
+// ChartSeries directly maps the format settings of the chart series.
+type ChartSeries struct {
 	Name       string `json:"name"`
 	Categories string `json:"categories"`
 	Values     string `json:"values"`
@@ -635,18 +651,32 @@ type formatChartSeries struct {
 			None  bool   `json:"none"`
 		} `json:"fill"`
 	} `json:"marker"`
+	Trendline ChartTrendline `json:"trendline"`
+}
+
+// ChartTrendline directly maps the format settings of the trendline of the
+// chart series.
+type ChartTrendline struct {
+	Type            string  `json:"type"`
+	Name            string  `json:"name"`
+	Order           int     `json:"order"`
+	Period          int     `json:"period"`
+	Forward         float64 `json:"forward"`
+	Backward        float64 `json:"backward"`
+	DisplayRSquared bool    `json:"display_r_squared"`
+	DisplayEquation bool    `json:"display_equation"`
 }
 
-// formatChartTitle directly maps the format settings of the chart title.
-type formatChartTitle struct {
-	None    bool         `json:"none"`
-	Name    string       `json:"name"`
-	Overlay bool         `json:"overlay"`
-	Layout  formatLayout `json:"layout"`
+// ChartTitle directly maps the format settings of the chart title.
+type ChartTitle struct {
+	None    bool        `json:"none"`
+	Name    string      `json:"name"`
+	Overlay bool        `json:"overlay"`
+	Layout  ChartLayout `json:"layout"`
 }
 
-// formatLayout directly maps the format settings of the element layout.
-type formatLayout struct {
+// ChartLayout directly maps the format settings of the element layout.
+type ChartLayout struct {
 	X      float64 `json:"x"`
 	Y      float64 `json:"y"`
 	Width  float64 `json:"width"`
@@ -74,14 +74,22 @@ type xlsxPhoneticRun struct {
 
 // formatComment directly maps the format settings of the comment.
 type formatComment struct {
-	Author string `json:"author"`
-	Text   string `json:"text"`
+	Author    string `json:"author"`
+	Text      string `json:"text"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Anchor    string `json:"anchor"`
+	Visible   bool   `json:"visible"`
+	FillColor string `json:"fill_color"`
 }
 
 // Comment directly maps the comment information.
 type Comment struct {
-	Author   string `json:"author"`
-	AuthorID int    `json:"author_id"`
-	Ref      string `json:"ref"`
-	Text     string `json:"text"`
+	Author   string        `json:"author"`
+	AuthorID int           `json:"author_id"`
+	Ref      string        `json:"ref"`
+	Text     string        `json:"text"`
+	Runs     []RichTextRun `json:"runs"`
+	Anchor   string        `json:"anchor"`
+	Visible  bool          `json:"visible"`
 }
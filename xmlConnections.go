@@ -0,0 +1,89 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxConnections directly maps the connections element. This element
+// represents the collection of the external data connections of the
+// workbook.
+type xlsxConnections struct {
+	XMLName    xml.Name          `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main connections"`
+	Connection []*xlsxConnection `xml:"connection"`
+}
+
+// xlsxConnection directly maps the connection element. This element
+// represents the properties of an external data connection which is used to
+// refresh the data of the tables, the pivot tables and the data model in the
+// workbook.
+type xlsxConnection struct {
+	ID                    int           `xml:"id,attr"`
+	SourceFile            string        `xml:"sourceFile,attr,omitempty"`
+	OdcFile               string        `xml:"odcFile,attr,omitempty"`
+	KeepAlive             bool          `xml:"keepAlive,attr,omitempty"`
+	Interval              int           `xml:"interval,attr,omitempty"`
+	Name                  string        `xml:"name,attr,omitempty"`
+	Description           string        `xml:"description,attr,omitempty"`
+	Type                  int           `xml:"type,attr,omitempty"`
+	ReconnectionMethod    int           `xml:"reconnectionMethod,attr,omitempty"`
+	RefreshedVersion      int           `xml:"refreshedVersion,attr"`
+	MinRefreshableVersion int           `xml:"minRefreshableVersion,attr,omitempty"`
+	SavePassword          bool          `xml:"savePassword,attr,omitempty"`
+	New                   bool          `xml:"new,attr,omitempty"`
+	Deleted               bool          `xml:"deleted,attr,omitempty"`
+	OnlyUseConnectionFile bool          `xml:"onlyUseConnectionFile,attr,omitempty"`
+	Background            bool          `xml:"background,attr,omitempty"`
+	RefreshOnLoad         bool          `xml:"refreshOnLoad,attr,omitempty"`
+	SaveData              bool          `xml:"saveData,attr,omitempty"`
+	Credentials           string        `xml:"credentials,attr,omitempty"`
+	SingleSignOnID        string        `xml:"singleSignOnId,attr,omitempty"`
+	DbPr                  *xlsxDbPr     `xml:"dbPr"`
+	OlapPr                *xlsxInnerXML `xml:"olapPr"`
+	WebPr                 *xlsxWebPr    `xml:"webPr"`
+	TextPr                *xlsxTextPr   `xml:"textPr"`
+	Parameters            *xlsxInnerXML `xml:"parameters"`
+	ExtLst                *xlsxExtLst   `xml:"extLst"`
+}
+
+// xlsxDbPr directly maps the dbPr element. This element specifies the
+// properties of the connection to the database.
+type xlsxDbPr struct {
+	Connection    string `xml:"connection,attr"`
+	Command       string `xml:"command,attr,omitempty"`
+	ServerCommand string `xml:"serverCommand,attr,omitempty"`
+	CommandType   int    `xml:"commandType,attr,omitempty"`
+}
+
+// xlsxWebPr directly maps the webPr element. This element specifies the
+// properties of the connection to the web query.
+type xlsxWebPr struct {
+	URL      string `xml:"url,attr,omitempty"`
+	Post     string `xml:"post,attr,omitempty"`
+	EditPage string `xml:"editPage,attr,omitempty"`
+	Content  string `xml:",innerxml"`
+}
+
+// xlsxTextPr directly maps the textPr element. This element specifies the
+// properties of the connection to the text file.
+type xlsxTextPr struct {
+	SourceFile string `xml:"sourceFile,attr,omitempty"`
+	Delimited  *bool  `xml:"delimited,attr"`
+	Content    string `xml:",innerxml"`
+}
+
+// decodeX15Connection directly maps the connection element in the extension
+// list of the connection which specifies the connection to the data model.
+type decodeX15Connection struct {
+	XMLName xml.Name `xml:"connection"`
+	ID      string   `xml:"id,attr"`
+	Model   bool     `xml:"model,attr"`
+}
@@ -23,6 +23,7 @@ type DocProperties struct {
 	Identifier     string
 	Keywords       string
 	LastModifiedBy string
+	LastPrinted    string
 	Modified       string
 	Revision       string
 	Subject        string
@@ -44,6 +45,7 @@ type decodeCoreProperties struct {
 	Keywords       string   `xml:"keywords,omitempty"`
 	Description    string   `xml:"http://purl.org/dc/elements/1.1/ description,omitempty"`
 	LastModifiedBy string   `xml:"lastModifiedBy"`
+	LastPrinted    string   `xml:"lastPrinted,omitempty"`
 	Language       string   `xml:"http://purl.org/dc/elements/1.1/ language,omitempty"`
 	Identifier     string   `xml:"http://purl.org/dc/elements/1.1/ identifier,omitempty"`
 	Revision       string   `xml:"revision,omitempty"`
@@ -74,6 +76,7 @@ type xlsxCoreProperties struct {
 	Keywords       string   `xml:"keywords,omitempty"`
 	Description    string   `xml:"dc:description,omitempty"`
 	LastModifiedBy string   `xml:"lastModifiedBy"`
+	LastPrinted    string   `xml:"lastPrinted,omitempty"`
 	Language       string   `xml:"dc:language,omitempty"`
 	Identifier     string   `xml:"dc:identifier,omitempty"`
 	Revision       string   `xml:"revision,omitempty"`
@@ -89,3 +92,54 @@ type xlsxCoreProperties struct {
 	Category      string `xml:"category,omitempty"`
 	Version       string `xml:"version,omitempty"`
 }
+
+// CustomProperty directly maps the custom property of the document. The type
+// of the value could be string, int, int32, int64, float32, float64, bool and
+// time.Time.
+type CustomProperty struct {
+	Name  string
+	Value interface{}
+}
+
+// decodeCustomProperties directly maps the root element for the custom
+// properties part. In order to solve the problem that the label structure is
+// changed after serialization and deserialization, two different structures
+// are defined. decodeCustomProperties just for deserialization.
+type decodeCustomProperties struct {
+	XMLName  xml.Name               `xml:"http://schemas.openxmlformats.org/officeDocument/2006/custom-properties Properties"`
+	Property []decodeCustomProperty `xml:"property"`
+}
+
+// decodeCustomProperty directly maps the property element of the custom
+// properties part, the raw content of the property will be kept.
+type decodeCustomProperty struct {
+	FmtID      string `xml:"fmtid,attr"`
+	PID        int    `xml:"pid,attr"`
+	Name       string `xml:"name,attr"`
+	LinkTarget string `xml:"linkTarget,attr"`
+	Content    string `xml:",innerxml"`
+	Value      struct {
+		XMLName xml.Name
+		Text    string `xml:",chardata"`
+	} `xml:",any"`
+}
+
+// xlsxCustomProperties directly maps the root element for the custom
+// properties part.
+type xlsxCustomProperties struct {
+	XMLName  xml.Name             `xml:"http://schemas.openxmlformats.org/officeDocument/2006/custom-properties Properties"`
+	Vt       string               `xml:"xmlns:vt,attr"`
+	Property []xlsxCustomProperty `xml:"property"`
+}
+
+// xlsxCustomProperty directly maps the property element of the custom
+// properties part. Each property has a name, a unique property ID and a
+// value with the type specified by the element in the docPropsVTypes
+// namespace.
+type xlsxCustomProperty struct {
+	FmtID      string `xml:"fmtid,attr"`
+	PID        int    `xml:"pid,attr"`
+	Name       string `xml:"name,attr,omitempty"`
+	LinkTarget string `xml:"linkTarget,attr,omitempty"`
+	Content    string `xml:",innerxml"`
+}
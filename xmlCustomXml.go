@@ -0,0 +1,58 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// CustomXMLPart directly maps the custom XML data part of the workbook. The
+// ID is the GUID of the custom XML data, such as
+// {6C8A1C2B-3F2D-4C1E-9B3A-2E4F5A6B7C8D}, and the SchemaRefs are the
+// namespaces of the XML schemas used by the content.
+type CustomXMLPart struct {
+	ID         string
+	SchemaRefs []string
+	Content    []byte
+}
+
+// decodeDatastoreItem directly maps the root element for the custom XML data
+// properties part. In order to solve the problem that the label structure is
+// changed after serialization and deserialization, two different structures
+// are defined. decodeDatastoreItem just for deserialization.
+type decodeDatastoreItem struct {
+	XMLName    xml.Name `xml:"http://schemas.openxmlformats.org/officeDocument/2006/customXml datastoreItem"`
+	ItemID     string   `xml:"itemID,attr"`
+	SchemaRefs []struct {
+		URI string `xml:"uri,attr"`
+	} `xml:"schemaRefs>schemaRef"`
+}
+
+// xlsxDatastoreItem directly maps the root element for the custom XML data
+// properties part, it specifies the identifier and the schemas of the custom
+// XML data.
+type xlsxDatastoreItem struct {
+	XMLName    xml.Name                `xml:"ds:datastoreItem"`
+	ItemID     string                  `xml:"ds:itemID,attr"`
+	DS         string                  `xml:"xmlns:ds,attr"`
+	SchemaRefs xlsxDatastoreSchemaRefs `xml:"ds:schemaRefs"`
+}
+
+// xlsxDatastoreSchemaRefs directly maps the schemaRefs element of the custom
+// XML data properties part.
+type xlsxDatastoreSchemaRefs struct {
+	SchemaRef []xlsxDatastoreSchemaRef `xml:"ds:schemaRef"`
+}
+
+// xlsxDatastoreSchemaRef directly maps the schemaRef element of the custom XML
+// data properties part.
+type xlsxDatastoreSchemaRef struct {
+	URI string `xml:"ds:uri,attr"`
+}
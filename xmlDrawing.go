@@ -36,6 +36,10 @@ const (
 	SourceRelationshipOfficeDocument             = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
 	SourceRelationshipChart                      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chart"
 	SourceRelationshipComments                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
+	SourceRelationshipExtendProperties           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+	SourceRelationshipCustomProperties           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+	SourceRelationshipCustomXML                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml"
+	SourceRelationshipCustomXMLProps             = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXmlProps"
 	SourceRelationshipImage                      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
 	SourceRelationshipTable                      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/table"
 	SourceRelationshipDrawingML                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/drawing"
@@ -44,10 +48,21 @@ const (
 	SourceRelationshipWorkSheet                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"
 	SourceRelationshipChartsheet                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chartsheet"
 	SourceRelationshipDialogsheet                = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/dialogsheet"
+	SourceRelationshipConnections                = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/connections"
 	SourceRelationshipPivotTable                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotTable"
 	SourceRelationshipPivotCache                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheDefinition"
+	SourceRelationshipPivotCacheRecords          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheRecords"
 	SourceRelationshipSharedStrings              = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings"
+	SourceRelationshipTimeline                   = "http://schemas.microsoft.com/office/2011/relationships/timeline"
+	SourceRelationshipTimelineCache              = "http://schemas.microsoft.com/office/2011/relationships/timelineCache"
 	SourceRelationshipVBAProject                 = "http://schemas.microsoft.com/office/2006/relationships/vbaProject"
+	SourceRelationshipVBAProjectSignature        = "http://schemas.microsoft.com/office/2006/relationships/vbaProjectSignature"
+	SourceRelationshipCtrlProp                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/ctrlProp"
+	SourceRelationshipControl                    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/control"
+	SourceRelationshipDigitalSignatureOrigin     = "http://schemas.openxmlformats.org/package/2006/relationships/digital-signature/origin"
+	SourceRelationshipDigitalSignatureXML        = "http://schemas.openxmlformats.org/package/2006/relationships/digital-signature/signature"
+	NameSpaceDigitalSignature                    = "http://schemas.openxmlformats.org/package/2006/digital-signature"
+	NameSpaceXMLSignature                        = "http://www.w3.org/2000/09/xmldsig#"
 	NameSpaceXML                                 = "http://www.w3.org/XML/1998/namespace"
 	NameSpaceXMLSchemaInstance                   = "http://www.w3.org/2001/XMLSchema-instance"
 	StrictSourceRelationship                     = "http://purl.oclc.org/ooxml/officeDocument/relationships"
@@ -56,27 +71,45 @@ const (
 	StrictSourceRelationshipComments             = "http://purl.oclc.org/ooxml/officeDocument/relationships/comments"
 	StrictSourceRelationshipImage                = "http://purl.oclc.org/ooxml/officeDocument/relationships/image"
 	StrictNameSpaceSpreadSheet                   = "http://purl.oclc.org/ooxml/spreadsheetml/main"
+	NameSpaceExtendedProperties                  = "http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"
+	NameSpaceCustomProperties                    = "http://schemas.openxmlformats.org/officeDocument/2006/custom-properties"
+	NameSpaceCustomXML                           = "http://schemas.openxmlformats.org/officeDocument/2006/customXml"
+	NameSpaceDocPropsVTypes                      = "http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"
 	NameSpaceDublinCore                          = "http://purl.org/dc/elements/1.1/"
 	NameSpaceDublinCoreTerms                     = "http://purl.org/dc/terms/"
 	NameSpaceDublinCoreMetadataIntiative         = "http://purl.org/dc/dcmitype/"
+	NameSpaceDrawingMLTimeSlicer                 = "http://schemas.microsoft.com/office/drawing/2012/timeslicer"
+	ContentTypeCustomProperties                  = "application/vnd.openxmlformats-officedocument.custom-properties+xml"
+	ContentTypeCustomXMLProperties               = "application/vnd.openxmlformats-officedocument.customXmlProperties+xml"
 	ContentTypeDrawing                           = "application/vnd.openxmlformats-officedocument.drawing+xml"
 	ContentTypeDrawingML                         = "application/vnd.openxmlformats-officedocument.drawingml.chart+xml"
 	ContentTypeMacro                             = "application/vnd.ms-excel.sheet.macroEnabled.main+xml"
+	ContentTypeSheetML                           = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"
+	ContentTypeTemplate                          = "application/vnd.openxmlformats-officedocument.spreadsheetml.template.main+xml"
+	ContentTypeTemplateMacro                     = "application/vnd.ms-excel.template.macroEnabled.main+xml"
+	ContentTypeActiveX                           = "application/vnd.ms-office.activeX"
+	ContentTypePrinterSettings                   = "application/vnd.openxmlformats-officedocument.spreadsheetml.printerSettings"
 	ContentTypeSpreadSheetMLChartsheet           = "application/vnd.openxmlformats-officedocument.spreadsheetml.chartsheet+xml"
 	ContentTypeSpreadSheetMLComments             = "application/vnd.openxmlformats-officedocument.spreadsheetml.comments+xml"
 	ContentTypeSpreadSheetMLPivotCacheDefinition = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheDefinition+xml"
+	ContentTypeSpreadSheetMLPivotCacheRecords    = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheRecords+xml"
 	ContentTypeSpreadSheetMLPivotTable           = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotTable+xml"
 	ContentTypeSpreadSheetMLSharedStrings        = "application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml"
 	ContentTypeSpreadSheetMLTable                = "application/vnd.openxmlformats-officedocument.spreadsheetml.table+xml"
 	ContentTypeSpreadSheetMLWorksheet            = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
+	ContentTypeTimeline                          = "application/vnd.ms-excel.timeline+xml"
+	ContentTypeTimelineCache                     = "application/vnd.ms-excel.timelineCache+xml"
 	ContentTypeVBA                               = "application/vnd.ms-office.vbaProject"
+	ContentTypeDigitalSignatureOrigin            = "application/vnd.openxmlformats-package.digital-signature-origin"
+	ContentTypeDigitalSignatureXML               = "application/vnd.openxmlformats-package.digital-signature-xmlsignature+xml"
+	ContentTypeRelationships                     = "application/vnd.openxmlformats-package.relationships+xml"
 	ContentTypeVML                               = "application/vnd.openxmlformats-officedocument.vmlDrawing"
 	// ExtURIConditionalFormattings is the extLst child element
 	// ([ISO/IEC29500-1:2016] section 18.2.10) of the worksheet element
 	// ([ISO/IEC29500-1:2016] section 18.3.1.99) is extended by the addition of
 	// new child ext elements ([ISO/IEC29500-1:2016] section 18.2.7)
 	ExtURIConditionalFormattings = "{78C0D931-6437-407D-A8EE-F0AAD7539E65}"
-	ExtURIDataValidations        = "{CCE6A557-97BC-4B89-ADB6-D9C93CAAB3DF}"
+	ExtURIDataValidations        = "{CCE6A557-97BC-4b89-ADB6-D9C93CAAB3DF}"
 	ExtURISparklineGroups        = "{05C60535-1F16-4fd2-B633-F4F36F0B64E0}"
 	ExtURISlicerListX14          = "{A8765BA9-456A-4DAB-B4F3-ACF838C121DE}"
 	ExtURISlicerCachesListX14    = "{BBE1A952-AA13-448e-AADC-164F8A28A991}"
@@ -85,6 +118,10 @@ const (
 	ExtURIIgnoredErrors          = "{01252117-D84E-4E92-8308-4BE1C098FCBB}"
 	ExtURIWebExtensions          = "{F7C9EE02-42E1-4005-9D12-6889AFFD525C}"
 	ExtURITimelineRefs           = "{7E03D99C-DC04-49d9-9315-930204A7B6E9}"
+	ExtURITimelineCacheRefs      = "{D0CA8CA8-9F24-4464-BF8E-62219DCF47F9}"
+	ExtURIPivotCacheDefinition   = "{725AE2AE-9491-48be-B2B4-4EB974FC3084}"
+	ExtURIConnection             = "{DE250136-89BD-433C-8126-D09CA5730AF9}"
+	ExtURIPivotField             = "{2946ED86-A175-432a-8AC1-64E0C546D7DE}"
 	ExtURIDrawingBlip            = "{28A0092B-C50C-407E-A947-70E740481C1C}"
 	ExtURIMacExcelMX             = "{64002731-A6B0-56B0-2670-7721B7C09600}"
 )
@@ -344,8 +381,9 @@ type xlsxGraphic struct {
 // document. This graphic object is provided entirely by the document authors
 // who choose to persist this data within the document.
 type xlsxGraphicData struct {
-	URI   string     `xml:"uri,attr"`
-	Chart *xlsxChart `xml:"c:chart,omitempty"`
+	URI        string          `xml:"uri,attr"`
+	Chart      *xlsxChart      `xml:"c:chart,omitempty"`
+	TimeSlicer *xlsxTimeSlicer `xml:"tsle:timeslicer,omitempty"`
 }
 
 // xlsxChart (Chart) directly maps the c:chart element.
@@ -355,6 +393,29 @@ type xlsxChart struct {
 	R   string `xml:"xmlns:r,attr"`
 }
 
+// xlsxTimeSlicer directly maps the tsle:timeslicer element. This element
+// specifies the name of the timeline displayed in the graphic frame.
+type xlsxTimeSlicer struct {
+	Tsle string `xml:"xmlns:tsle,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// xlsxAlternateContent directly maps the mc:AlternateContent element. This
+// element specifies the content which requires the application to support
+// the namespace specified by the Requires attribute of the mc:Choice element.
+type xlsxAlternateContent struct {
+	XMLName xml.Name                    `xml:"mc:AlternateContent"`
+	MC      string                      `xml:"xmlns:mc,attr"`
+	Choice  *xlsxAlternateContentChoice `xml:"mc:Choice"`
+}
+
+// xlsxAlternateContentChoice directly maps the mc:Choice element.
+type xlsxAlternateContentChoice struct {
+	Tsle     string `xml:"xmlns:tsle,attr,omitempty"`
+	Requires string `xml:"Requires,attr"`
+	Content  string `xml:",innerxml"`
+}
+
 // xdrSp (Shape) directly maps the xdr:sp element. This element specifies the
 // existence of a single shape. A shape can either be a preset or a custom
 // geometry, defined using the SpreadsheetDrawingML framework. In addition to a
@@ -436,8 +497,10 @@ type xdrTxBody struct {
 	P      []*aP    `xml:"a:p"`
 }
 
-// formatPicture directly maps the format settings of the picture.
-type formatPicture struct {
+// PictureFormat directly maps the format settings of the picture, which can
+// be used by AddPicture and AddPictureFromBytes instead of the JSON format
+// string. It's also used as the format settings of the chart and the shape.
+type PictureFormat struct {
 	FPrintsWithSheet bool    `json:"print_obj"`
 	FLocksWithSheet  bool    `json:"locked"`
 	NoChangeAspect   bool    `json:"lock_aspect_ratio"`
@@ -451,25 +514,26 @@ type formatPicture struct {
 	Positioning      string  `json:"positioning"`
 }
 
-// formatShape directly maps the format settings of the shape.
-type formatShape struct {
-	Type      string                 `json:"type"`
-	Width     int                    `json:"width"`
-	Height    int                    `json:"height"`
-	Format    formatPicture          `json:"format"`
-	Color     formatShapeColor       `json:"color"`
-	Paragraph []formatShapeParagraph `json:"paragraph"`
+// Shape directly maps the format settings of the shape, which can be used by
+// AddShape instead of the JSON format string.
+type Shape struct {
+	Type      string           `json:"type"`
+	Width     int              `json:"width"`
+	Height    int              `json:"height"`
+	Format    PictureFormat    `json:"format"`
+	Color     ShapeColor       `json:"color"`
+	Paragraph []ShapeParagraph `json:"paragraph"`
 }
 
-// formatShapeParagraph directly maps the format settings of the paragraph in
+// ShapeParagraph directly maps the format settings of the paragraph in
 // the shape.
-type formatShapeParagraph struct {
+type ShapeParagraph struct {
 	Font Font   `json:"font"`
 	Text string `json:"text"`
 }
 
-// formatShapeColor directly maps the color settings of the shape.
-type formatShapeColor struct {
+// ShapeColor directly maps the color settings of the shape.
+type ShapeColor struct {
 	Line   string `json:"line"`
 	Fill   string `json:"fill"`
 	Effect string `json:"effect"`
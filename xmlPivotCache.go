@@ -24,7 +24,7 @@ type xlsxPivotCacheDefinition struct {
 	SaveData              bool                   `xml:"saveData,attr"`
 	RefreshOnLoad         bool                   `xml:"refreshOnLoad,attr,omitempty"`
 	OptimizeMemory        bool                   `xml:"optimizeMemory,attr,omitempty"`
-	EnableRefresh         bool                   `xml:"enableRefresh,attr,omitempty"`
+	EnableRefresh         *bool                  `xml:"enableRefresh,attr"`
 	RefreshedBy           string                 `xml:"refreshedBy,attr,omitempty"`
 	RefreshedDate         float64                `xml:"refreshedDate,attr,omitempty"`
 	RefreshedDateIso      float64                `xml:"refreshedDateIso,attr,omitempty"`
@@ -51,6 +51,22 @@ type xlsxPivotCacheDefinition struct {
 	ExtLst                *xlsxExtLst            `xml:"extLst"`
 }
 
+// xlsxPivotCacheRecords represents the pivotCacheRecords part. This part
+// contains the records of the source data in the pivot cache, the values of
+// the fields which have shared items are stored as the indexes of the items.
+type xlsxPivotCacheRecords struct {
+	XMLName xml.Name                `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main pivotCacheRecords"`
+	Count   int                     `xml:"count,attr"`
+	R       []*xlsxPivotCacheRecord `xml:"r"`
+}
+
+// xlsxPivotCacheRecord represents a record in the pivot cache, each child
+// element of the record is the value of a field in order of the database
+// fields of the cache.
+type xlsxPivotCacheRecord struct {
+	Content string `xml:",innerxml"`
+}
+
 // xlsxCacheSource represents the description of data source whose data is
 // stored in the pivot cache. The data source refers to the underlying rows or
 // database records that provide the data for a PivotTable. You can create a
@@ -80,6 +96,8 @@ type xlsxWorksheetSource struct {
 // specified in the rangeSets collection. The logic for how the application
 // consolidates the data in the ranges is application- defined.
 type xlsxConsolidation struct {
+	AutoPage *bool  `xml:"autoPage,attr"`
+	Content  string `xml:",innerxml"`
 }
 
 // xlsxCacheFields represents the collection of field definitions in the
@@ -105,7 +123,7 @@ type xlsxCacheField struct {
 	SQLType             int              `xml:"sqlType,attr,omitempty"`
 	Hierarchy           int              `xml:"hierarchy,attr,omitempty"`
 	Level               int              `xml:"level,attr,omitempty"`
-	DatabaseField       bool             `xml:"databaseField,attr,omitempty"`
+	DatabaseField       *bool            `xml:"databaseField,attr"`
 	MappingCount        int              `xml:"mappingCount,attr,omitempty"`
 	MemberPropertyField bool             `xml:"memberPropertyField,attr,omitempty"`
 	SharedItems         *xlsxSharedItems `xml:"sharedItems"`
@@ -121,16 +139,16 @@ type xlsxCacheField struct {
 // those values that are referenced in multiple places across all the
 // PivotTable parts.
 type xlsxSharedItems struct {
-	ContainsSemiMixedTypes bool          `xml:"containsSemiMixedTypes,attr,omitempty"`
-	ContainsNonDate        bool          `xml:"containsNonDate,attr,omitempty"`
+	ContainsSemiMixedTypes *bool         `xml:"containsSemiMixedTypes,attr"`
+	ContainsNonDate        *bool         `xml:"containsNonDate,attr"`
 	ContainsDate           bool          `xml:"containsDate,attr,omitempty"`
-	ContainsString         bool          `xml:"containsString,attr,omitempty"`
+	ContainsString         *bool         `xml:"containsString,attr"`
 	ContainsBlank          bool          `xml:"containsBlank,attr,omitempty"`
 	ContainsMixedTypes     bool          `xml:"containsMixedTypes,attr,omitempty"`
 	ContainsNumber         bool          `xml:"containsNumber,attr,omitempty"`
 	ContainsInteger        bool          `xml:"containsInteger,attr,omitempty"`
-	MinValue               float64       `xml:"minValue,attr,omitempty"`
-	MaxValue               float64       `xml:"maxValue,attr,omitempty"`
+	MinValue               *float64      `xml:"minValue,attr"`
+	MaxValue               *float64      `xml:"maxValue,attr"`
 	MinDate                string        `xml:"minDate,attr,omitempty"`
 	MaxDate                string        `xml:"maxDate,attr,omitempty"`
 	Count                  int           `xml:"count,attr"`
@@ -139,7 +157,7 @@ type xlsxSharedItems struct {
 	N                      *xlsxNumber   `xml:"n"`
 	B                      *xlsxBoolean  `xml:"b"`
 	E                      *xlsxError    `xml:"e"`
-	S                      *xlsxString   `xml:"s"`
+	S                      []*xlsxString `xml:"s"`
 	D                      *xlsxDateTime `xml:"d"`
 }
 
@@ -204,40 +222,137 @@ type xlsxDateTime struct {
 
 // xlsxFieldGroup represents the collection of properties for a field group.
 type xlsxFieldGroup struct {
+	Par        *int            `xml:"par,attr"`
+	Base       *int            `xml:"base,attr"`
+	RangePr    *xlsxRangePr    `xml:"rangePr"`
+	DiscretePr *xlsxInnerXML   `xml:"discretePr"`
+	GroupItems *xlsxGroupItems `xml:"groupItems"`
+}
+
+// xlsxRangePr represents the collection of properties for the range grouping
+// of numeric and date time values in a field group.
+type xlsxRangePr struct {
+	AutoStart     *bool   `xml:"autoStart,attr"`
+	AutoEnd       *bool   `xml:"autoEnd,attr"`
+	GroupBy       string  `xml:"groupBy,attr,omitempty"`
+	StartNum      float64 `xml:"startNum,attr,omitempty"`
+	EndNum        float64 `xml:"endNum,attr,omitempty"`
+	StartDate     string  `xml:"startDate,attr,omitempty"`
+	EndDate       string  `xml:"endDate,attr,omitempty"`
+	GroupInterval float64 `xml:"groupInterval,attr,omitempty"`
+}
+
+// xlsxGroupItems represents the collection of items in a field group.
+type xlsxGroupItems struct {
+	Count int           `xml:"count,attr"`
+	S     []*xlsxString `xml:"s"`
 }
 
 // xlsxCacheHierarchies represents the collection of OLAP hierarchies in the
 // PivotCache.
 type xlsxCacheHierarchies struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
 }
 
 // xlsxKpis represents the collection of Key Performance Indicators (KPIs)
 // defined on the OLAP server and stored in the PivotCache.
 type xlsxKpis struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
 }
 
 // xlsxTupleCache represents the cache of OLAP sheet data members, or tuples.
 type xlsxTupleCache struct {
+	Content string `xml:",innerxml"`
 }
 
 // xlsxCalculatedItems represents the collection of calculated items.
 type xlsxCalculatedItems struct {
+	Count          int                   `xml:"count,attr"`
+	CalculatedItem []*xlsxCalculatedItem `xml:"calculatedItem"`
+}
+
+// xlsxCalculatedItem represents a calculated item, the formula of the item
+// is applied to the items of the field specified by the pivot area.
+type xlsxCalculatedItem struct {
+	Field     *int           `xml:"field,attr"`
+	Formula   string         `xml:"formula,attr,omitempty"`
+	PivotArea *xlsxPivotArea `xml:"pivotArea"`
+	ExtLst    *xlsxExtLst    `xml:"extLst"`
+}
+
+// xlsxPivotArea represents a rule to describe PivotTable selection.
+type xlsxPivotArea struct {
+	Field                       *int                     `xml:"field,attr"`
+	Type                        string                   `xml:"type,attr,omitempty"`
+	DataOnly                    *bool                    `xml:"dataOnly,attr"`
+	LabelOnly                   bool                     `xml:"labelOnly,attr,omitempty"`
+	GrandRow                    bool                     `xml:"grandRow,attr,omitempty"`
+	GrandCol                    bool                     `xml:"grandCol,attr,omitempty"`
+	CacheIndex                  bool                     `xml:"cacheIndex,attr,omitempty"`
+	Outline                     *bool                    `xml:"outline,attr"`
+	Offset                      string                   `xml:"offset,attr,omitempty"`
+	CollapsedLevelsAreSubtotals bool                     `xml:"collapsedLevelsAreSubtotals,attr,omitempty"`
+	Axis                        string                   `xml:"axis,attr,omitempty"`
+	FieldPosition               *int                     `xml:"fieldPosition,attr"`
+	References                  *xlsxPivotAreaReferences `xml:"references"`
+	ExtLst                      *xlsxExtLst              `xml:"extLst"`
+}
+
+// xlsxPivotAreaReferences represents the set of selected fields and the
+// selected items within those fields.
+type xlsxPivotAreaReferences struct {
+	Count     int                       `xml:"count,attr"`
+	Reference []*xlsxPivotAreaReference `xml:"reference"`
+}
+
+// xlsxPivotAreaReference represents a pivot reference, the indexes of the
+// selected items of the field are specified by the x elements.
+type xlsxPivotAreaReference struct {
+	Field           *int        `xml:"field,attr"`
+	Count           int         `xml:"count,attr,omitempty"`
+	Selected        *bool       `xml:"selected,attr"`
+	ByPosition      bool        `xml:"byPosition,attr,omitempty"`
+	Relative        bool        `xml:"relative,attr,omitempty"`
+	DefaultSubtotal bool        `xml:"defaultSubtotal,attr,omitempty"`
+	SumSubtotal     bool        `xml:"sumSubtotal,attr,omitempty"`
+	CountASubtotal  bool        `xml:"countASubtotal,attr,omitempty"`
+	AvgSubtotal     bool        `xml:"avgSubtotal,attr,omitempty"`
+	MaxSubtotal     bool        `xml:"maxSubtotal,attr,omitempty"`
+	MinSubtotal     bool        `xml:"minSubtotal,attr,omitempty"`
+	ProductSubtotal bool        `xml:"productSubtotal,attr,omitempty"`
+	CountSubtotal   bool        `xml:"countSubtotal,attr,omitempty"`
+	StdDevSubtotal  bool        `xml:"stdDevSubtotal,attr,omitempty"`
+	StdDevPSubtotal bool        `xml:"stdDevPSubtotal,attr,omitempty"`
+	VarSubtotal     bool        `xml:"varSubtotal,attr,omitempty"`
+	VarPSubtotal    bool        `xml:"varPSubtotal,attr,omitempty"`
+	X               []*xlsxX    `xml:"x"`
+	ExtLst          *xlsxExtLst `xml:"extLst"`
 }
 
 // xlsxCalculatedMembers represents the collection of calculated members in an
 // OLAP PivotTable.
 type xlsxCalculatedMembers struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
 }
 
 // xlsxDimensions represents the collection of PivotTable OLAP dimensions.
 type xlsxDimensions struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
 }
 
 // xlsxMeasureGroups represents the collection of PivotTable OLAP measure
 // groups.
 type xlsxMeasureGroups struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
 }
 
 // xlsxMaps represents the PivotTable OLAP measure group - Dimension maps.
 type xlsxMaps struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
 }
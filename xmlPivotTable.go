@@ -71,8 +71,8 @@ type xlsxPivotTableDefinition struct {
 	ShowEmptyRow            bool                     `xml:"showEmptyRow,attr,omitempty"`
 	ShowEmptyCol            bool                     `xml:"showEmptyCol,attr,omitempty"`
 	ShowHeaders             bool                     `xml:"showHeaders,attr,omitempty"`
-	Compact                 bool                     `xml:"compact,attr"`
-	Outline                 bool                     `xml:"outline,attr"`
+	Compact                 *bool                    `xml:"compact,attr"`
+	Outline                 *bool                    `xml:"outline,attr"`
 	OutlineData             bool                     `xml:"outlineData,attr,omitempty"`
 	CompactData             *bool                    `xml:"compactData,attr,omitempty"`
 	Published               bool                     `xml:"published,attr,omitempty"`
@@ -93,8 +93,15 @@ type xlsxPivotTableDefinition struct {
 	ColItems                *xlsxColItems            `xml:"colItems"`
 	PageFields              *xlsxPageFields          `xml:"pageFields"`
 	DataFields              *xlsxDataFields          `xml:"dataFields"`
+	Formats                 *xlsxFormats             `xml:"formats"`
 	ConditionalFormats      *xlsxConditionalFormats  `xml:"conditionalFormats"`
+	ChartFormats            *xlsxChartFormats        `xml:"chartFormats"`
+	PivotHierarchies        *xlsxPivotHierarchies    `xml:"pivotHierarchies"`
 	PivotTableStyleInfo     *xlsxPivotTableStyleInfo `xml:"pivotTableStyleInfo"`
+	Filters                 *xlsxPivotFilters        `xml:"filters"`
+	RowHierarchiesUsage     *xlsxHierarchiesUsage    `xml:"rowHierarchiesUsage"`
+	ColHierarchiesUsage     *xlsxHierarchiesUsage    `xml:"colHierarchiesUsage"`
+	ExtLst                  *xlsxExtLst              `xml:"extLst"`
 }
 
 // xlsxLocation represents location information for the PivotTable.
@@ -125,11 +132,11 @@ type xlsxPivotField struct {
 	ShowDropDowns                bool               `xml:"showDropDowns,attr,omitempty"`
 	HiddenLevel                  bool               `xml:"hiddenLevel,attr,omitempty"`
 	UniqueMemberProperty         string             `xml:"uniqueMemberProperty,attr,omitempty"`
-	Compact                      bool               `xml:"compact,attr"`
+	Compact                      *bool              `xml:"compact,attr"`
 	AllDrilled                   bool               `xml:"allDrilled,attr,omitempty"`
 	NumFmtID                     string             `xml:"numFmtId,attr,omitempty"`
-	Outline                      bool               `xml:"outline,attr"`
-	SubtotalTop                  bool               `xml:"subtotalTop,attr,omitempty"`
+	Outline                      *bool              `xml:"outline,attr"`
+	SubtotalTop                  *bool              `xml:"subtotalTop,attr"`
 	DragToRow                    bool               `xml:"dragToRow,attr,omitempty"`
 	DragToCol                    bool               `xml:"dragToCol,attr,omitempty"`
 	MultipleItemSelectionAllowed bool               `xml:"multipleItemSelectionAllowed,attr,omitempty"`
@@ -194,6 +201,45 @@ type xlsxItem struct {
 	E  bool   `xml:"e,attr,omitempty"`
 }
 
+// xlsxPivotFieldExt directly maps the ext element of the pivot field which
+// extended by the pivotField element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2009/9/main.
+type xlsxPivotFieldExt struct {
+	XMLName    xml.Name           `xml:"ext"`
+	URI        string             `xml:"uri,attr"`
+	XMLNSX14   string             `xml:"xmlns:x14,attr"`
+	PivotField *xlsxX14PivotField `xml:"x14:pivotField"`
+}
+
+// xlsxX14PivotField directly maps the pivotField element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2009/9/main. The
+// fillDownLabels attribute specifies whether the item labels of the field
+// will be repeated.
+type xlsxX14PivotField struct {
+	FillDownLabels bool `xml:"fillDownLabels,attr,omitempty"`
+}
+
+// decodePivotFieldExtLst defines the structure used to parse the extLst
+// element of the pivot field.
+type decodePivotFieldExtLst struct {
+	XMLName xml.Name               `xml:"extLst"`
+	Ext     []*decodePivotFieldExt `xml:"ext"`
+}
+
+// decodePivotFieldExt defines the structure used to parse the ext element of
+// the pivot field.
+type decodePivotFieldExt struct {
+	URI        string               `xml:"uri,attr"`
+	PivotField *decodeX14PivotField `xml:"pivotField"`
+}
+
+// decodeX14PivotField defines the structure used to parse the pivotField
+// element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2009/9/main.
+type decodeX14PivotField struct {
+	FillDownLabels bool `xml:"fillDownLabels,attr"`
+}
+
 // xlsxAutoSortScope represents the sorting scope for the PivotTable.
 type xlsxAutoSortScope struct {
 }
@@ -226,6 +272,7 @@ type xlsxI struct {
 
 // xlsxX represents an array of indexes to cached shared item values.
 type xlsxX struct {
+	V int `xml:"v,attr,omitempty"`
 }
 
 // xlsxColFields represents the collection of fields that are on the column
@@ -273,15 +320,51 @@ type xlsxDataField struct {
 	Fld        int         `xml:"fld,attr"`
 	Subtotal   string      `xml:"subtotal,attr,omitempty"`
 	ShowDataAs string      `xml:"showDataAs,attr,omitempty"`
-	BaseField  int         `xml:"baseField,attr,omitempty"`
-	BaseItem   int64       `xml:"baseItem,attr,omitempty"`
+	BaseField  *int        `xml:"baseField,attr"`
+	BaseItem   *int64      `xml:"baseItem,attr"`
 	NumFmtID   string      `xml:"numFmtId,attr,omitempty"`
 	ExtLst     *xlsxExtLst `xml:"extLst"`
 }
 
+// xlsxFormats represents the collection of formats applied to a PivotTable.
+type xlsxFormats struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
+}
+
 // xlsxConditionalFormats represents the collection of conditional formats
 // applied to a PivotTable.
 type xlsxConditionalFormats struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
+}
+
+// xlsxChartFormats represents the collection of PivotChart formats applied
+// to a PivotTable.
+type xlsxChartFormats struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
+}
+
+// xlsxPivotHierarchies represents the collection of OLAP hierarchies on the
+// PivotTable.
+type xlsxPivotHierarchies struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
+}
+
+// xlsxPivotFilters represents the collection of filters that apply to the
+// PivotTable.
+type xlsxPivotFilters struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
+}
+
+// xlsxHierarchiesUsage represents the collection of OLAP hierarchies that
+// are used on the rows or the columns of the PivotTable.
+type xlsxHierarchiesUsage struct {
+	Count   int    `xml:"count,attr,omitempty"`
+	Content string `xml:",innerxml"`
 }
 
 // xlsxPivotTableStyleInfo represent information on style applied to the
@@ -28,6 +28,9 @@ type xlsxSST struct {
 	Count       int      `xml:"count,attr"`
 	UniqueCount int      `xml:"uniqueCount,attr"`
 	SI          []xlsxSI `xml:"si"`
+	// The string items which are kept in the temporary file by the
+	// SharedStringsOnDisk of the options, they are followed by the SI.
+	spill *sharedStringsSpill
 }
 
 // xlsxSI (String Item) is the representation of an individual string in the
@@ -86,13 +89,13 @@ type xlsxRPr struct {
 	RFont     *attrValString `xml:"rFont"`
 	Charset   *attrValInt    `xml:"charset"`
 	Family    *attrValInt    `xml:"family"`
-	B         string         `xml:"b,omitempty"`
-	I         string         `xml:"i,omitempty"`
-	Strike    string         `xml:"strike,omitempty"`
-	Outline   string         `xml:"outline,omitempty"`
-	Shadow    string         `xml:"shadow,omitempty"`
-	Condense  string         `xml:"condense,omitempty"`
-	Extend    string         `xml:"extend,omitempty"`
+	B         *string        `xml:"b"`
+	I         *string        `xml:"i"`
+	Strike    *string        `xml:"strike"`
+	Outline   *string        `xml:"outline"`
+	Shadow    *string        `xml:"shadow"`
+	Condense  *string        `xml:"condense"`
+	Extend    *string        `xml:"extend"`
 	Color     *xlsxColor     `xml:"color"`
 	Sz        *attrValFloat  `xml:"sz"`
 	U         *attrValString `xml:"u"`
@@ -100,6 +103,17 @@ type xlsxRPr struct {
 	Scheme    *attrValString `xml:"scheme"`
 }
 
+// Phonetic directly maps the phonetic settings of the cell. Text is the
+// phonetic hint shown above the whole cell value, Type is one of
+// fullwidthKatakana, halfwidthKatakana, Hiragana and noConversion, and
+// Alignment is one of noControl, left, center and distributed.
+type Phonetic struct {
+	Text      string
+	Type      string
+	Alignment string
+	Show      bool
+}
+
 // RichTextRun directly maps the settings of the rich text run.
 type RichTextRun struct {
 	Font *Font
@@ -0,0 +1,99 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/xml"
+	"time"
+)
+
+// xlsxSignature directly maps the Signature element in the namespace
+// http://www.w3.org/2000/09/xmldsig#. The Signature element is the root
+// element of the digital signature XML signature part of the package.
+type xlsxSignature struct {
+	XMLName        xml.Name                `xml:"http://www.w3.org/2000/09/xmldsig# Signature"`
+	ID             string                  `xml:"Id,attr,omitempty"`
+	SignedInfo     xlsxSignatureSignedInfo `xml:"SignedInfo"`
+	SignatureValue string                  `xml:"SignatureValue"`
+	KeyInfo        *xlsxSignatureKeyInfo   `xml:"KeyInfo"`
+	Object         []xlsxSignatureObject   `xml:"Object"`
+}
+
+// xlsxSignatureSignedInfo directly maps the SignedInfo element, it contains
+// the information that is actually signed.
+type xlsxSignatureSignedInfo struct {
+	CanonicalizationMethod xlsxSignatureAlgorithm   `xml:"CanonicalizationMethod"`
+	SignatureMethod        xlsxSignatureAlgorithm   `xml:"SignatureMethod"`
+	Reference              []xlsxSignatureReference `xml:"Reference"`
+}
+
+// xlsxSignatureAlgorithm directly maps the element which specifies the
+// algorithm by the Algorithm attribute, such as CanonicalizationMethod,
+// SignatureMethod, Transform and DigestMethod.
+type xlsxSignatureAlgorithm struct {
+	Algorithm string `xml:"Algorithm,attr"`
+}
+
+// xlsxSignatureReference directly maps the Reference element, it specifies a
+// digest algorithm and digest value, and optionally an identifier of the
+// object being signed and a list of transforms to be applied prior to
+// digesting.
+type xlsxSignatureReference struct {
+	URI          string                   `xml:"URI,attr"`
+	Type         string                   `xml:"Type,attr,omitempty"`
+	Transforms   []xlsxSignatureTransform `xml:"Transforms>Transform"`
+	DigestMethod xlsxSignatureAlgorithm   `xml:"DigestMethod"`
+	DigestValue  string                   `xml:"DigestValue"`
+}
+
+// xlsxSignatureTransform directly maps the Transform element. For the
+// relationship transform, the RelationshipReference element specifies the
+// relationship to be signed by identifier.
+type xlsxSignatureTransform struct {
+	Algorithm             string `xml:"Algorithm,attr"`
+	RelationshipReference []struct {
+		SourceID string `xml:"SourceId,attr"`
+	} `xml:"RelationshipReference"`
+}
+
+// xlsxSignatureKeyInfo directly maps the KeyInfo element, it contains the
+// X.509 certificates of the signer.
+type xlsxSignatureKeyInfo struct {
+	X509Certificate []string `xml:"X509Data>X509Certificate"`
+}
+
+// xlsxSignatureObject directly maps the Object element, it contains the
+// manifest of the package parts and the signature properties.
+type xlsxSignatureObject struct {
+	ID       string `xml:"Id,attr,omitempty"`
+	Manifest *struct {
+		Reference []xlsxSignatureReference `xml:"Reference"`
+	} `xml:"Manifest"`
+	SignatureTime string `xml:"SignatureProperties>SignatureProperty>SignatureTime>Value"`
+}
+
+// SignatureOptions directly maps the settings of the digital signature. The
+// Signer should be an RSA or ECDSA private key which matches the public key
+// of the Certificate. The SigningTime is the current time if not specified.
+type SignatureOptions struct {
+	Signer      crypto.Signer
+	Certificate *x509.Certificate
+	SigningTime time.Time
+}
+
+// Signature directly maps the verified digital signature of the workbook.
+type Signature struct {
+	Certificate *x509.Certificate
+	SigningTime time.Time
+}
@@ -318,6 +318,7 @@ type Alignment struct {
 	ShrinkToFit     bool   `json:"shrink_to_fit"`
 	TextRotation    int    `json:"text_rotation"`
 	Vertical        string `json:"vertical"`
+	VerticalText    bool   `json:"vertical_text"`
 	WrapText        bool   `json:"wrap_text"`
 }
 
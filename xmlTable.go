@@ -26,7 +26,7 @@ type xlsxTable struct {
 	DisplayName          string              `xml:"displayName,attr,omitempty"`
 	HeaderRowBorderDxfID int                 `xml:"headerRowBorderDxfId,attr,omitempty"`
 	HeaderRowCellStyle   string              `xml:"headerRowCellStyle,attr,omitempty"`
-	HeaderRowCount       int                 `xml:"headerRowCount,attr,omitempty"`
+	HeaderRowCount       *int                `xml:"headerRowCount,attr"`
 	HeaderRowDxfID       int                 `xml:"headerRowDxfId,attr,omitempty"`
 	ID                   int                 `xml:"id,attr"`
 	InsertRow            bool                `xml:"insertRow,attr,omitempty"`
@@ -46,9 +46,9 @@ type xlsxTable struct {
 // applied column by column to a table of data in the worksheet. This collection
 // expresses AutoFilter settings.
 type xlsxAutoFilter struct {
-	XMLName      xml.Name          `xml:"autoFilter"`
-	Ref          string            `xml:"ref,attr"`
-	FilterColumn *xlsxFilterColumn `xml:"filterColumn"`
+	XMLName      xml.Name            `xml:"autoFilter"`
+	Ref          string              `xml:"ref,attr"`
+	FilterColumn []*xlsxFilterColumn `xml:"filterColumn"`
 }
 
 // xlsxFilterColumn directly maps the filterColumn element. The filterColumn
@@ -171,18 +171,28 @@ type xlsxTableColumns struct {
 // xlsxTableColumn directly maps the element representing a single column for
 // this table.
 type xlsxTableColumn struct {
-	DataCellStyle      string `xml:"dataCellStyle,attr,omitempty"`
-	DataDxfID          int    `xml:"dataDxfId,attr,omitempty"`
-	HeaderRowCellStyle string `xml:"headerRowCellStyle,attr,omitempty"`
-	HeaderRowDxfID     int    `xml:"headerRowDxfId,attr,omitempty"`
-	ID                 int    `xml:"id,attr"`
-	Name               string `xml:"name,attr"`
-	QueryTableFieldID  int    `xml:"queryTableFieldId,attr,omitempty"`
-	TotalsRowCellStyle string `xml:"totalsRowCellStyle,attr,omitempty"`
-	TotalsRowDxfID     int    `xml:"totalsRowDxfId,attr,omitempty"`
-	TotalsRowFunction  string `xml:"totalsRowFunction,attr,omitempty"`
-	TotalsRowLabel     string `xml:"totalsRowLabel,attr,omitempty"`
-	UniqueName         string `xml:"uniqueName,attr,omitempty"`
+	DataCellStyle           string            `xml:"dataCellStyle,attr,omitempty"`
+	DataDxfID               int               `xml:"dataDxfId,attr,omitempty"`
+	HeaderRowCellStyle      string            `xml:"headerRowCellStyle,attr,omitempty"`
+	HeaderRowDxfID          int               `xml:"headerRowDxfId,attr,omitempty"`
+	ID                      int               `xml:"id,attr"`
+	Name                    string            `xml:"name,attr"`
+	QueryTableFieldID       int               `xml:"queryTableFieldId,attr,omitempty"`
+	TotalsRowCellStyle      string            `xml:"totalsRowCellStyle,attr,omitempty"`
+	TotalsRowDxfID          int               `xml:"totalsRowDxfId,attr,omitempty"`
+	TotalsRowFunction       string            `xml:"totalsRowFunction,attr,omitempty"`
+	TotalsRowLabel          string            `xml:"totalsRowLabel,attr,omitempty"`
+	UniqueName              string            `xml:"uniqueName,attr,omitempty"`
+	CalculatedColumnFormula *xlsxTableFormula `xml:"calculatedColumnFormula"`
+	TotalsRowFormula        *xlsxTableFormula `xml:"totalsRowFormula"`
+}
+
+// xlsxTableFormula directly maps the formula element of the table column,
+// such as the calculated column formula and the custom formula of the totals
+// row.
+type xlsxTableFormula struct {
+	Content string `xml:",chardata"`
+	Array   bool   `xml:"array,attr,omitempty"`
 }
 
 // xlsxTableStyleInfo directly maps the tableStyleInfo element. This element
@@ -196,14 +206,132 @@ type xlsxTableStyleInfo struct {
 	ShowColumnStripes bool   `xml:"showColumnStripes,attr"`
 }
 
+// Table directly maps the definition of the table in a worksheet. The Range
+// is the cell range of the table including the header and totals rows, and
+// the AutoFilter is the cell range of the auto filter of the table, it will
+// be empty if the table has no auto filter.
+type Table struct {
+	Name              string
+	Range             string
+	Columns           []TableColumn
+	TableStyle        string
+	ShowHeaderRow     bool
+	ShowTotalsRow     bool
+	ShowFirstColumn   bool
+	ShowLastColumn    bool
+	ShowRowStripes    bool
+	ShowColumnStripes bool
+	AutoFilter        string
+}
+
+// TableColumn directly maps the column of the table. The TotalsRowFormula is
+// the formula of the custom totals row function of the column, and the
+// CalculatedColumnFormula is the formula of the calculated column which
+// applies to each data row of the column.
+type TableColumn struct {
+	Name                    string
+	TotalsRowFunction       string
+	TotalsRowLabel          string
+	TotalsRowFormula        string
+	CalculatedColumnFormula string
+}
+
+// AutoFilterOption directly maps the settings of the auto filter in a
+// worksheet. The Range is the cell range of the auto filter including the
+// header row, the FilterMode specifies if the worksheet has been filtered,
+// and the Columns are the filter criteria of the filtered columns.
+type AutoFilterOption struct {
+	Range      string
+	FilterMode bool
+	Columns    []AutoFilterColumn
+}
+
+// AutoFilterColumn directly maps the filter criteria of a column of the auto
+// filter. The Column is the column name of the worksheet, such as B. The
+// Filters are the values to filter by, and the FilterBlank specifies if the
+// blank cells will be shown. The CustomFilters are the criteria with the
+// operators, which will be joined by and operator if the CustomFiltersAnd is
+// true, or by or operator otherwise.
+type AutoFilterColumn struct {
+	Column           string
+	Filters          []string
+	FilterBlank      bool
+	CustomFilters    []AutoFilterCustomFilter
+	CustomFiltersAnd bool
+	DynamicFilter    *AutoFilterDynamicFilter
+	Top10            *AutoFilterTop10
+	ColorFilter      *AutoFilterColorFilter
+	IconFilter       *AutoFilterIconFilter
+}
+
+// AutoFilterCustomFilter directly maps the custom filter criteria of the auto
+// filter column. The Operator is one of the equal, notEqual, lessThan,
+// lessThanOrEqual, greaterThan and greaterThanOrEqual.
+type AutoFilterCustomFilter struct {
+	Operator string
+	Val      string
+}
+
+// AutoFilterDynamicFilter directly maps the dynamic filter criteria of the
+// auto filter column, such as aboveAverage, today and thisMonth.
+type AutoFilterDynamicFilter struct {
+	Type      string
+	Val       float64
+	ValISO    string
+	MaxValISO string
+}
+
+// AutoFilterTop10 directly maps the top N filter criteria of the auto filter
+// column. The Val is the number or percent of the items to filter by, and the
+// FilterVal is the actual cell value used to filter.
+type AutoFilterTop10 struct {
+	Top       bool
+	Percent   bool
+	Val       float64
+	FilterVal float64
+}
+
+// AutoFilterColorFilter directly maps the color filter criteria of the auto
+// filter column. The DxfID is the index of the differential format of the
+// color, and the CellColor specifies if filter by the fill color of the
+// cells, the font color will be used if it is false.
+type AutoFilterColorFilter struct {
+	CellColor bool
+	DxfID     int
+}
+
+// AutoFilterIconFilter directly maps the icon filter criteria of the auto
+// filter column.
+type AutoFilterIconFilter struct {
+	IconSet string
+	IconID  int
+}
+
 // formatTable directly maps the format settings of the table.
 type formatTable struct {
-	TableName         string `json:"table_name"`
-	TableStyle        string `json:"table_style"`
-	ShowFirstColumn   bool   `json:"show_first_column"`
-	ShowLastColumn    bool   `json:"show_last_column"`
-	ShowRowStripes    bool   `json:"show_row_stripes"`
-	ShowColumnStripes bool   `json:"show_column_stripes"`
+	TableName         string                        `json:"table_name"`
+	TableStyle        string                        `json:"table_style"`
+	ShowFirstColumn   bool                          `json:"show_first_column"`
+	ShowLastColumn    bool                          `json:"show_last_column"`
+	ShowRowStripes    bool                          `json:"show_row_stripes"`
+	ShowColumnStripes bool                          `json:"show_column_stripes"`
+	TotalsRow         []formatTableTotal            `json:"totals_row"`
+	CalculatedColumns []formatTableCalculatedColumn `json:"calculated_columns"`
+}
+
+// formatTableTotal directly maps the totals row settings of the table column.
+type formatTableTotal struct {
+	Column   string `json:"column"`
+	Label    string `json:"label"`
+	Function string `json:"function"`
+	Formula  string `json:"formula"`
+}
+
+// formatTableCalculatedColumn directly maps the calculated column settings of
+// the table column.
+type formatTableCalculatedColumn struct {
+	Column  string `json:"column"`
+	Formula string `json:"formula"`
 }
 
 // formatAutoFilter directly maps the auto filter settings.
@@ -214,4 +342,5 @@ type formatAutoFilter struct {
 		Column string `json:"column"`
 		Value  []int  `json:"value"`
 	} `json:"filter_list"`
+	HideRows bool `json:"hide_rows"`
 }
@@ -0,0 +1,134 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxTimelines directly maps the timelines element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2010/11/main. This part
+// specifies the timelines on the worksheet, each timeline refers to a
+// timeline cache by the name of the timeline cache.
+type xlsxTimelines struct {
+	XMLName  xml.Name        `xml:"http://schemas.microsoft.com/office/spreadsheetml/2010/11/main timelines"`
+	Timeline []*xlsxTimeline `xml:"timeline"`
+	ExtLst   *xlsxExtLst     `xml:"extLst"`
+}
+
+// xlsxTimeline directly maps the timeline element. This element specifies
+// the name, the caption, the time level and the display settings of a
+// timeline.
+type xlsxTimeline struct {
+	Name                    string      `xml:"name,attr"`
+	Cache                   string      `xml:"cache,attr"`
+	Caption                 string      `xml:"caption,attr,omitempty"`
+	ShowHeader              *bool       `xml:"showHeader,attr"`
+	ShowSelectionLabel      *bool       `xml:"showSelectionLabel,attr"`
+	ShowTimeLevel           *bool       `xml:"showTimeLevel,attr"`
+	ShowHorizontalScrollbar *bool       `xml:"showHorizontalScrollbar,attr"`
+	Level                   int         `xml:"level,attr"`
+	SelectionLevel          int         `xml:"selectionLevel,attr"`
+	ScrollPosition          string      `xml:"scrollPosition,attr,omitempty"`
+	Style                   string      `xml:"style,attr,omitempty"`
+	ExtLst                  *xlsxExtLst `xml:"extLst"`
+}
+
+// xlsxTimelineCacheDefinition directly maps the timelineCacheDefinition
+// element. This part specifies the pivot tables filtered by the timeline
+// cache, the source field of the pivot cache and the state of the filter.
+type xlsxTimelineCacheDefinition struct {
+	XMLName     xml.Name                      `xml:"http://schemas.microsoft.com/office/spreadsheetml/2010/11/main timelineCacheDefinition"`
+	Name        string                        `xml:"name,attr"`
+	SourceName  string                        `xml:"sourceName,attr"`
+	PivotTables *xlsxTimelineCachePivotTables `xml:"pivotTables"`
+	State       *xlsxTimelineState            `xml:"state"`
+	ExtLst      *xlsxExtLst                   `xml:"extLst"`
+}
+
+// xlsxTimelineCachePivotTables directly maps the pivotTables element. This
+// element specifies the collection of the pivot tables filtered by the
+// timeline cache.
+type xlsxTimelineCachePivotTables struct {
+	PivotTable []*xlsxTimelineCachePivotTable `xml:"pivotTable"`
+}
+
+// xlsxTimelineCachePivotTable directly maps the pivotTable element. This
+// element specifies the sheet ID of the worksheet and the name of the pivot
+// table.
+type xlsxTimelineCachePivotTable struct {
+	TabID int    `xml:"tabId,attr"`
+	Name  string `xml:"name,attr"`
+}
+
+// xlsxTimelineState directly maps the state element. This element specifies
+// the pivot cache and the date range of the filter of the timeline cache.
+type xlsxTimelineState struct {
+	SingleRangeFilterState bool               `xml:"singleRangeFilterState,attr,omitempty"`
+	MinimalRefreshVersion  int                `xml:"minimalRefreshVersion,attr"`
+	LastRefreshVersion     int                `xml:"lastRefreshVersion,attr"`
+	PivotCacheID           int                `xml:"pivotCacheId,attr"`
+	FilterType             string             `xml:"filterType,attr"`
+	Selection              *xlsxTimelineRange `xml:"selection"`
+	Bounds                 *xlsxTimelineRange `xml:"bounds"`
+}
+
+// xlsxTimelineRange directly maps the selection and bounds element. This
+// element specifies the start date and the end date of the date range.
+type xlsxTimelineRange struct {
+	StartDate string `xml:"startDate,attr"`
+	EndDate   string `xml:"endDate,attr"`
+}
+
+// xlsxX15TimelineRefs directly maps the timelineRefs element in the extension
+// list of the worksheet and the timelineCacheRefs element in the extension
+// list of the workbook.
+type xlsxX15TimelineRefs struct {
+	XMLName     xml.Name
+	XMLNSX15    string                `xml:"xmlns:x15,attr"`
+	TimelineRef []*xlsxX15TimelineRef `xml:",any"`
+}
+
+// xlsxX15TimelineRef directly maps the timelineRef and timelineCacheRef
+// element. This element specifies the relationship ID of the timeline part or
+// the timeline cache part.
+type xlsxX15TimelineRef struct {
+	XMLName xml.Name
+	RID     string `xml:"r:id,attr"`
+}
+
+// decodeX15TimelineRefs defines the structure used to parse the timelineRefs
+// and timelineCacheRefs element.
+type decodeX15TimelineRefs struct {
+	TimelineRef []*decodeX15TimelineRef `xml:",any"`
+}
+
+// decodeX15TimelineRef defines the structure used to parse the timelineRef
+// and timelineCacheRef element.
+type decodeX15TimelineRef struct {
+	RID string `xml:"id,attr"`
+}
+
+// xlsxX14PivotCacheDefinition directly maps the pivotCacheDefinition element
+// in the extension list of the pivot cache definition. This element specifies
+// the identifier of the pivot cache used by the timeline caches.
+type xlsxX14PivotCacheDefinition struct {
+	XMLName      xml.Name `xml:"x14:pivotCacheDefinition"`
+	XMLNSX14     string   `xml:"xmlns:x14,attr"`
+	PivotCacheID int      `xml:"pivotCacheId,attr"`
+}
+
+// decodeX14PivotCacheDefinition defines the structure used to parse the
+// pivotCacheDefinition element in the extension list of the pivot cache
+// definition.
+type decodeX14PivotCacheDefinition struct {
+	XMLName      xml.Name `xml:"pivotCacheDefinition"`
+	PivotCacheID int      `xml:"pivotCacheId,attr"`
+}
@@ -42,8 +42,8 @@ type xlsxWorkbook struct {
 	CalcPr              *xlsxCalcPr              `xml:"calcPr"`
 	CustomWorkbookViews *xlsxCustomWorkbookViews `xml:"customWorkbookViews"`
 	PivotCaches         *xlsxPivotCaches         `xml:"pivotCaches"`
-	ExtLst              *xlsxExtLst              `xml:"extLst"`
 	FileRecoveryPr      *xlsxFileRecoveryPr      `xml:"fileRecoveryPr"`
+	ExtLst              *xlsxExtLst              `xml:"extLst"`
 }
 
 // xlsxFileRecoveryPr maps sheet recovery information. This element defines
@@ -128,12 +128,12 @@ type xlsxBookViews struct {
 // specifies a single Workbook view.
 type xlsxWorkBookView struct {
 	ActiveTab              int    `xml:"activeTab,attr,omitempty"`
-	AutoFilterDateGrouping bool   `xml:"autoFilterDateGrouping,attr,omitempty"`
+	AutoFilterDateGrouping *bool  `xml:"autoFilterDateGrouping,attr"`
 	FirstSheet             int    `xml:"firstSheet,attr,omitempty"`
 	Minimized              bool   `xml:"minimized,attr,omitempty"`
-	ShowHorizontalScroll   bool   `xml:"showHorizontalScroll,attr,omitempty"`
-	ShowSheetTabs          bool   `xml:"showSheetTabs,attr,omitempty"`
-	ShowVerticalScroll     bool   `xml:"showVerticalScroll,attr,omitempty"`
+	ShowHorizontalScroll   *bool  `xml:"showHorizontalScroll,attr"`
+	ShowSheetTabs          *bool  `xml:"showSheetTabs,attr"`
+	ShowVerticalScroll     *bool  `xml:"showVerticalScroll,attr"`
 	TabRatio               int    `xml:"tabRatio,attr,omitempty"`
 	Visibility             string `xml:"visibility,attr,omitempty"`
 	WindowHeight           int    `xml:"windowHeight,attr,omitempty"`
@@ -264,31 +264,33 @@ type xlsxCustomWorkbookViews struct {
 // a fixed display for workbooks. However, if a spreadsheet application chooses
 // to implement configurable display modes, the customWorkbookView element
 // should be used to persist the settings for those display modes.
+
 type xlsxCustomWorkbookView struct {
-	ActiveSheetID        *int    `xml:"activeSheetId,attr"`
-	AutoUpdate           *bool   `xml:"autoUpdate,attr"`
-	ChangesSavedWin      *bool   `xml:"changesSavedWin,attr"`
-	GUID                 *string `xml:"guid,attr"`
-	IncludeHiddenRowCol  *bool   `xml:"includeHiddenRowCol,attr"`
-	IncludePrintSettings *bool   `xml:"includePrintSettings,attr"`
-	Maximized            *bool   `xml:"maximized,attr"`
-	MergeInterval        int     `xml:"mergeInterval,attr"`
-	Minimized            *bool   `xml:"minimized,attr"`
-	Name                 *string `xml:"name,attr"`
-	OnlySync             *bool   `xml:"onlySync,attr"`
-	PersonalView         *bool   `xml:"personalView,attr"`
-	ShowComments         *string `xml:"showComments,attr"`
-	ShowFormulaBar       *bool   `xml:"showFormulaBar,attr"`
-	ShowHorizontalScroll *bool   `xml:"showHorizontalScroll,attr"`
-	ShowObjects          *string `xml:"showObjects,attr"`
-	ShowSheetTabs        *bool   `xml:"showSheetTabs,attr"`
-	ShowStatusbar        *bool   `xml:"showStatusbar,attr"`
-	ShowVerticalScroll   *bool   `xml:"showVerticalScroll,attr"`
-	TabRatio             *int    `xml:"tabRatio,attr"`
-	WindowHeight         *int    `xml:"windowHeight,attr"`
-	WindowWidth          *int    `xml:"windowWidth,attr"`
-	XWindow              *int    `xml:"xWindow,attr"`
-	YWindow              *int    `xml:"yWindow,attr"`
+	ActiveSheetID        *int        `xml:"activeSheetId,attr"`
+	AutoUpdate           *bool       `xml:"autoUpdate,attr"`
+	ChangesSavedWin      *bool       `xml:"changesSavedWin,attr"`
+	GUID                 *string     `xml:"guid,attr"`
+	IncludeHiddenRowCol  *bool       `xml:"includeHiddenRowCol,attr"`
+	IncludePrintSettings *bool       `xml:"includePrintSettings,attr"`
+	Maximized            *bool       `xml:"maximized,attr"`
+	MergeInterval        *int        `xml:"mergeInterval,attr"`
+	Minimized            *bool       `xml:"minimized,attr"`
+	Name                 *string     `xml:"name,attr"`
+	OnlySync             *bool       `xml:"onlySync,attr"`
+	PersonalView         *bool       `xml:"personalView,attr"`
+	ShowComments         *string     `xml:"showComments,attr"`
+	ShowFormulaBar       *bool       `xml:"showFormulaBar,attr"`
+	ShowHorizontalScroll *bool       `xml:"showHorizontalScroll,attr"`
+	ShowObjects          *string     `xml:"showObjects,attr"`
+	ShowSheetTabs        *bool       `xml:"showSheetTabs,attr"`
+	ShowStatusbar        *bool       `xml:"showStatusbar,attr"`
+	ShowVerticalScroll   *bool       `xml:"showVerticalScroll,attr"`
+	TabRatio             *int        `xml:"tabRatio,attr"`
+	WindowHeight         *int        `xml:"windowHeight,attr"`
+	WindowWidth          *int        `xml:"windowWidth,attr"`
+	XWindow              *int        `xml:"xWindow,attr"`
+	YWindow              *int        `xml:"yWindow,attr"`
+	ExtLst               *xlsxExtLst `xml:"extLst"`
 }
 
 // DefinedName directly maps the name for a cell or cell range on a
@@ -296,6 +298,47 @@ type xlsxCustomWorkbookView struct {
 type DefinedName struct {
 	Name     string
 	Comment  string
+	Hidden   bool
 	RefersTo string
 	Scope    string
 }
+
+// DefinedNameRange directly maps a component of the cell ranges which the
+// defined name refers to, the Range is a cell or range reference without
+// absolute reference marks on the worksheet specified by the Sheet.
+type DefinedNameRange struct {
+	Sheet string
+	Range string
+}
+
+// CustomView directly maps the settings of the custom workbook view. The
+// Sheets field is the list of worksheets which have display and print
+// settings saved in the custom view.
+type CustomView struct {
+	Name                 string
+	GUID                 string
+	PersonalView         bool
+	IncludePrintSettings bool
+	IncludeHiddenRowCol  bool
+	Sheets               []string
+}
+
+// FormatWorkbookProtection directly maps the settings of workbook protection.
+type FormatWorkbookProtection struct {
+	AlgorithmName string
+	Password      string
+	LockStructure bool
+	LockWindows   bool
+}
+
+// VBAModule directly maps the module of the VBA project.
+type VBAModule struct {
+	Name string
+	Type string
+}
+
+// ConvertOptions directly maps the settings of the conversion between
+// macro-enabled and macro-free workbook.
+type ConvertOptions struct {
+	KeepControls bool
+}
@@ -13,7 +13,11 @@ package excelize
 
 import (
 	"encoding/xml"
+	"math"
+	"strconv"
 	"sync"
+
+	"github.com/mohae/deepcopy"
 )
 
 // xlsxWorksheet directly maps the worksheet element in the namespace
@@ -30,7 +34,7 @@ type xlsxWorksheet struct {
 	SheetCalcPr           *xlsxInnerXML                `xml:"sheetCalcPr"`
 	SheetProtection       *xlsxSheetProtection         `xml:"sheetProtection"`
 	ProtectedRanges       *xlsxInnerXML                `xml:"protectedRanges"`
-	Scenarios             *xlsxInnerXML                `xml:"scenarios"`
+	Scenarios             *xlsxScenarios               `xml:"scenarios"`
 	AutoFilter            *xlsxAutoFilter              `xml:"autoFilter"`
 	SortState             *xlsxSortState               `xml:"sortState"`
 	DataConsolidate       *xlsxInnerXML                `xml:"dataConsolidate"`
@@ -47,8 +51,8 @@ type xlsxWorksheet struct {
 	RowBreaks             *xlsxBreaks                  `xml:"rowBreaks"`
 	ColBreaks             *xlsxBreaks                  `xml:"colBreaks"`
 	CustomProperties      *xlsxInnerXML                `xml:"customProperties"`
-	CellWatches           *xlsxInnerXML                `xml:"cellWatches"`
-	IgnoredErrors         *xlsxInnerXML                `xml:"ignoredErrors"`
+	CellWatches           *xlsxCellWatches             `xml:"cellWatches"`
+	IgnoredErrors         *xlsxIgnoredErrors           `xml:"ignoredErrors"`
 	SmartTags             *xlsxInnerXML                `xml:"smartTags"`
 	Drawing               *xlsxDrawing                 `xml:"drawing"`
 	LegacyDrawing         *xlsxLegacyDrawing           `xml:"legacyDrawing"`
@@ -62,6 +66,28 @@ type xlsxWorksheet struct {
 	ExtLst                *xlsxExtLst                  `xml:"extLst"`
 }
 
+// xlsxIgnoredErrors directly maps the ignoredErrors element. This element
+// specifies a collection of ignored errors, by error type and range.
+type xlsxIgnoredErrors struct {
+	IgnoredError []*xlsxIgnoredError `xml:"ignoredError"`
+	ExtLst       *xlsxExtLst         `xml:"extLst"`
+}
+
+// xlsxIgnoredError directly maps the ignoredError element. This element
+// specifies a single ignored error for a range of cells.
+type xlsxIgnoredError struct {
+	Sqref              string `xml:"sqref,attr"`
+	EvalError          bool   `xml:"evalError,attr,omitempty"`
+	TwoDigitTextYear   bool   `xml:"twoDigitTextYear,attr,omitempty"`
+	NumberStoredAsText bool   `xml:"numberStoredAsText,attr,omitempty"`
+	Formula            bool   `xml:"formula,attr,omitempty"`
+	FormulaRange       bool   `xml:"formulaRange,attr,omitempty"`
+	UnlockedFormula    bool   `xml:"unlockedFormula,attr,omitempty"`
+	EmptyCellReference bool   `xml:"emptyCellReference,attr,omitempty"`
+	ListDataValidation bool   `xml:"listDataValidation,attr,omitempty"`
+	CalculatedColumn   bool   `xml:"calculatedColumn,attr,omitempty"`
+}
+
 // xlsxDrawing change r:id to rid in the namespace.
 type xlsxDrawing struct {
 	XMLName xml.Name `xml:"drawing"`
@@ -380,10 +406,10 @@ type xlsxCustomSheetView struct {
 	ColorID        int               `xml:"colorId,attr,omitempty"`
 	ShowPageBreaks bool              `xml:"showPageBreaks,attr,omitempty"`
 	ShowFormulas   bool              `xml:"showFormulas,attr,omitempty"`
-	ShowGridLines  bool              `xml:"showGridLines,attr,omitempty"`
-	ShowRowCol     bool              `xml:"showRowCol,attr,omitempty"`
-	OutlineSymbols bool              `xml:"outlineSymbols,attr,omitempty"`
-	ZeroValues     bool              `xml:"zeroValues,attr,omitempty"`
+	ShowGridLines  *bool             `xml:"showGridLines,attr"`
+	ShowRowCol     *bool             `xml:"showRowCol,attr"`
+	OutlineSymbols *bool             `xml:"outlineSymbols,attr"`
+	ZeroValues     *bool             `xml:"zeroValues,attr"`
 	FitToPage      bool              `xml:"fitToPage,attr,omitempty"`
 	PrintArea      bool              `xml:"printArea,attr,omitempty"`
 	Filter         bool              `xml:"filter,attr,omitempty"`
@@ -393,10 +419,53 @@ type xlsxCustomSheetView struct {
 	State          string            `xml:"state,attr,omitempty"`
 	FilterUnique   bool              `xml:"filterUnique,attr,omitempty"`
 	View           string            `xml:"view,attr,omitempty"`
-	ShowRuler      bool              `xml:"showRuler,attr,omitempty"`
+	ShowRuler      *bool             `xml:"showRuler,attr"`
 	TopLeftCell    string            `xml:"topLeftCell,attr,omitempty"`
 }
 
+// xlsxScenarios directly maps the scenarios element. This collection
+// represents the scenarios of the worksheet, and the current and shown
+// scenario.
+type xlsxScenarios struct {
+	Current  *int            `xml:"current,attr"`
+	Show     *int            `xml:"show,attr"`
+	Sqref    string          `xml:"sqref,attr,omitempty"`
+	Scenario []*xlsxScenario `xml:"scenario"`
+}
+
+// xlsxScenario directly maps the scenario element. This element represents
+// a single scenario, which is a set of input values for the changing cells.
+type xlsxScenario struct {
+	Name       string            `xml:"name,attr"`
+	Locked     bool              `xml:"locked,attr,omitempty"`
+	Hidden     bool              `xml:"hidden,attr,omitempty"`
+	Count      int               `xml:"count,attr,omitempty"`
+	User       string            `xml:"user,attr,omitempty"`
+	Comment    string            `xml:"comment,attr,omitempty"`
+	InputCells []*xlsxInputCells `xml:"inputCells"`
+}
+
+// xlsxInputCells directly maps the inputCells element. This element
+// represents the value of a changing cell of the scenario.
+type xlsxInputCells struct {
+	R        string `xml:"r,attr"`
+	Deleted  bool   `xml:"deleted,attr,omitempty"`
+	Undone   bool   `xml:"undone,attr,omitempty"`
+	Val      string `xml:"val,attr"`
+	NumFmtID *int   `xml:"numFmtId,attr"`
+}
+
+// xlsxCellWatches directly maps the cellWatches element. This collection
+// represents the cells of the worksheet which shown in the watch window.
+type xlsxCellWatches struct {
+	CellWatch []*xlsxCellWatch `xml:"cellWatch"`
+}
+
+// xlsxCellWatch directly maps the cellWatch element. A single watched cell.
+type xlsxCellWatch struct {
+	R string `xml:"r,attr"`
+}
+
 // xlsxMergeCell directly maps the mergeCell element. A single merged cell.
 type xlsxMergeCell struct {
 	Ref string `xml:"ref,attr,omitempty"`
@@ -428,6 +497,7 @@ type DataValidation struct {
 	Error            *string `xml:"error,attr"`
 	ErrorStyle       *string `xml:"errorStyle,attr"`
 	ErrorTitle       *string `xml:"errorTitle,attr"`
+	ImeMode          string  `xml:"imeMode,attr,omitempty"`
 	Operator         string  `xml:"operator,attr,omitempty"`
 	Prompt           *string `xml:"prompt,attr"`
 	PromptTitle      *string `xml:"promptTitle,attr"`
@@ -438,6 +508,90 @@ type DataValidation struct {
 	Type             string  `xml:"type,attr,omitempty"`
 	Formula1         string  `xml:",innerxml"`
 	Formula2         string  `xml:",innerxml"`
+	crossSheet       bool
+	hiddenKeys       []string
+}
+
+// decodeDataValidationFormulas directly maps the formula1 and formula2
+// elements of the data validation.
+type decodeDataValidationFormulas struct {
+	Formula1 *string `xml:"formula1"`
+	Formula2 *string `xml:"formula2"`
+}
+
+// decodeX14DataValidations directly maps the dataValidations element in the
+// extLst of the worksheet.
+type decodeX14DataValidations struct {
+	XMLName        xml.Name                   `xml:"dataValidations"`
+	DisablePrompts bool                       `xml:"disablePrompts,attr,omitempty"`
+	XWindow        int                        `xml:"xWindow,attr,omitempty"`
+	YWindow        int                        `xml:"yWindow,attr,omitempty"`
+	DataValidation []*decodeX14DataValidation `xml:"dataValidation"`
+}
+
+// decodeX14DataValidation directly maps the dataValidation element in the
+// extLst of the worksheet.
+type decodeX14DataValidation struct {
+	AllowBlank       bool              `xml:"allowBlank,attr"`
+	Error            *string           `xml:"error,attr"`
+	ErrorStyle       *string           `xml:"errorStyle,attr"`
+	ErrorTitle       *string           `xml:"errorTitle,attr"`
+	ImeMode          string            `xml:"imeMode,attr"`
+	Operator         string            `xml:"operator,attr"`
+	Prompt           *string           `xml:"prompt,attr"`
+	PromptTitle      *string           `xml:"promptTitle,attr"`
+	ShowDropDown     bool              `xml:"showDropDown,attr"`
+	ShowErrorMessage bool              `xml:"showErrorMessage,attr"`
+	ShowInputMessage bool              `xml:"showInputMessage,attr"`
+	Type             string            `xml:"type,attr"`
+	Formula1         *decodeX14Formula `xml:"formula1"`
+	Formula2         *decodeX14Formula `xml:"formula2"`
+	Sqref            string            `xml:"sqref"`
+}
+
+// decodeX14Formula directly maps the formula1 and formula2 element in the
+// extLst of the worksheet.
+type decodeX14Formula struct {
+	F string `xml:"f"`
+}
+
+// xlsxX14DataValidations directly maps the dataValidations element in the
+// extLst of the worksheet, which contains the data validations with the
+// cross-sheet references.
+type xlsxX14DataValidations struct {
+	XMLName        xml.Name                 `xml:"x14:dataValidations"`
+	XMLNSXM        string                   `xml:"xmlns:xm,attr"`
+	Count          int                      `xml:"count,attr"`
+	DisablePrompts bool                     `xml:"disablePrompts,attr,omitempty"`
+	XWindow        int                      `xml:"xWindow,attr,omitempty"`
+	YWindow        int                      `xml:"yWindow,attr,omitempty"`
+	DataValidation []*xlsxX14DataValidation `xml:"x14:dataValidation"`
+}
+
+// xlsxX14DataValidation directly maps the dataValidation element in the
+// extLst of the worksheet.
+type xlsxX14DataValidation struct {
+	AllowBlank       bool            `xml:"allowBlank,attr"`
+	Error            *string         `xml:"error,attr"`
+	ErrorStyle       *string         `xml:"errorStyle,attr"`
+	ErrorTitle       *string         `xml:"errorTitle,attr"`
+	ImeMode          string          `xml:"imeMode,attr,omitempty"`
+	Operator         string          `xml:"operator,attr,omitempty"`
+	Prompt           *string         `xml:"prompt,attr"`
+	PromptTitle      *string         `xml:"promptTitle,attr"`
+	ShowDropDown     bool            `xml:"showDropDown,attr,omitempty"`
+	ShowErrorMessage bool            `xml:"showErrorMessage,attr,omitempty"`
+	ShowInputMessage bool            `xml:"showInputMessage,attr,omitempty"`
+	Type             string          `xml:"type,attr,omitempty"`
+	Formula1         *xlsxX14Formula `xml:"x14:formula1"`
+	Formula2         *xlsxX14Formula `xml:"x14:formula2"`
+	Sqref            string          `xml:"xm:sqref"`
+}
+
+// xlsxX14Formula directly maps the formula1 and formula2 element in the
+// extLst of the worksheet.
+type xlsxX14Formula struct {
+	F string `xml:"xm:f"`
 }
 
 // xlsxC collection represents a cell in the worksheet. Information about the
@@ -462,14 +616,93 @@ type xlsxC struct {
 	R        string   `xml:"r,attr,omitempty"` // Cell ID, e.g. A1
 	S        int      `xml:"s,attr,omitempty"` // Style reference.
 	// Str string `xml:"str,attr,omitempty"` // Style reference.
-	T  string  `xml:"t,attr,omitempty"` // Type.
-	F  *xlsxF  `xml:"f,omitempty"`      // Formula
-	V  string  `xml:"v,omitempty"`      // Value
-	IS *xlsxSI `xml:"is"`
+	T  string `xml:"t,attr,omitempty"`  // Type.
+	Ph bool   `xml:"ph,attr,omitempty"` // Show phonetic.
+	// Typed numeric value of the cell, which will be serialized to the text
+	// of the value only when the worksheet being marshaled. The type is
+	// placed next to the Ph to share its padding.
+	numType cellNumType
+	F       *xlsxF  `xml:"f,omitempty"` // Formula
+	V       string  `xml:"v,omitempty"` // Value
+	IS      *xlsxSI `xml:"is"`
+	num     uint64
+}
+
+// DeepCopy implements deepcopy.Interface to copy the cell including the typed
+// numeric value, which is skipped by the reflection of deepcopy.
+func (c xlsxC) DeepCopy() interface{} {
+	type cell xlsxC
+	cpy := xlsxC(deepcopy.Copy(cell(c)).(cell))
+	cpy.numType, cpy.num = c.numType, c.num
+	return cpy
+}
+
+// cellNumType is the type of the typed numeric value of the cell.
+type cellNumType byte
+
+// This section defines the types of the typed numeric value of the cell.
+const (
+	cellNumNone cellNumType = iota
+	cellNumInt
+	cellNumFloat32
+	cellNumFloat64
+)
+
+// setInt provides a function to set the integer value of the cell as the
+// typed numeric value.
+func (c *xlsxC) setInt(value int) {
+	c.T, c.V, c.numType, c.num = "", "", cellNumInt, uint64(value)
+}
+
+// setFloat provides a function to set the floating point value of the cell as
+// the typed numeric value, bitSize is 32 or 64 depending on if a float32 or
+// float64 was originally used for the value.
+func (c *xlsxC) setFloat(value float64, bitSize int) {
+	c.T, c.V, c.numType, c.num = "", "", cellNumFloat64, math.Float64bits(value)
+	if bitSize == 32 {
+		c.numType = cellNumFloat32
+	}
+}
+
+// setValue provides a function to set the type and text of the value of the
+// cell, and clear the typed numeric value of the cell.
+func (c *xlsxC) setValue(t, v string) {
+	c.T, c.V, c.numType, c.num = t, v, cellNumNone, 0
+}
+
+// value provides a function to get the text of the value of the cell.
+func (c *xlsxC) value() string {
+	switch c.numType {
+	case cellNumInt:
+		return strconv.FormatInt(int64(c.num), 10)
+	case cellNumFloat32:
+		return strconv.FormatFloat(math.Float64frombits(c.num), 'f', -1, 32)
+	case cellNumFloat64:
+		return strconv.FormatFloat(math.Float64frombits(c.num), 'f', -1, 64)
+	}
+	return c.V
+}
+
+// MarshalXML implements xml.Marshaler to serialize the typed numeric value of
+// the cell to the text of the value.
+func (c *xlsxC) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type cell xlsxC
+	if c.numType == cellNumNone {
+		return e.EncodeElement((*cell)(c), start)
+	}
+	v := cell(*c)
+	v.V = c.value()
+	return e.EncodeElement(&v, start)
 }
 
 func (c *xlsxC) hasValue() bool {
-	return c.S != 0 || c.V != "" || c.F != nil || c.T != ""
+	return c.S != 0 || c.V != "" || c.numType != cellNumNone || c.F != nil || c.T != ""
+}
+
+// hasContent provides a function to check if the cell has a value, rich text
+// or formula, the style of the cell will be ignored.
+func (c *xlsxC) hasContent() bool {
+	return c.V != "" || c.numType != cellNumNone || c.F != nil || c.IS != nil
 }
 
 // xlsxF represents a formula for the cell. The formula expression is
@@ -607,6 +840,34 @@ type xlsxHyperlink struct {
 	RID      string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
 }
 
+// HyperLinkLocation directly maps the destination of the internal hyperlink
+// in the workbook, which is a cell or range on a worksheet specified by the
+// Sheet and Cell, or a defined name specified by the DefinedName.
+type HyperLinkLocation struct {
+	Sheet       string
+	Cell        string
+	DefinedName string
+}
+
+// HyperLink directly maps the hyperlink of the cell. The Target is the
+// address of the external hyperlink, or the location of the internal
+// hyperlink as stored in the worksheet, and the Location is the parsed
+// destination of the internal hyperlink.
+type HyperLink struct {
+	External bool
+	Target   string
+	Location HyperLinkLocation
+}
+
+// HyperLinkOptions directly maps the settings of the hyperlink of the cell.
+// The ApplyStyle specifies whether to apply the built-in Hyperlink cell style
+// to the cell, which displays the cell value with the underlined font in the
+// hyperlink color of the theme, and Excel will switch it to the built-in
+// Followed Hyperlink cell style after the hyperlink has been clicked.
+type HyperLinkOptions struct {
+	ApplyStyle bool
+}
+
 // xlsxTableParts directly maps the tableParts element in the namespace
 // http://schemas.openxmlformats.org/spreadsheetml/2006/main - The table element
 // has several attributes applied to identify the table and the data range it
@@ -702,9 +963,46 @@ type decodeWorksheetExt struct {
 
 // decodeX14SparklineGroups directly maps the sparklineGroups element.
 type decodeX14SparklineGroups struct {
-	XMLName xml.Name `xml:"sparklineGroups"`
-	XMLNSXM string   `xml:"xmlns:xm,attr"`
-	Content string   `xml:",innerxml"`
+	XMLName         xml.Name                   `xml:"sparklineGroups"`
+	XMLNSXM         string                     `xml:"xmlns:xm,attr"`
+	SparklineGroups []*decodeX14SparklineGroup `xml:"sparklineGroup"`
+}
+
+// decodeX14SparklineGroup directly maps the sparklineGroup element.
+type decodeX14SparklineGroup struct {
+	ManualMax           float64               `xml:"manualMax,attr"`
+	ManualMin           float64               `xml:"manualMin,attr"`
+	LineWeight          float64               `xml:"lineWeight,attr"`
+	Type                string                `xml:"type,attr"`
+	DateAxis            bool                  `xml:"dateAxis,attr"`
+	DisplayEmptyCellsAs string                `xml:"displayEmptyCellsAs,attr"`
+	Markers             bool                  `xml:"markers,attr"`
+	High                bool                  `xml:"high,attr"`
+	Low                 bool                  `xml:"low,attr"`
+	First               bool                  `xml:"first,attr"`
+	Last                bool                  `xml:"last,attr"`
+	Negative            bool                  `xml:"negative,attr"`
+	DisplayXAxis        bool                  `xml:"displayXAxis,attr"`
+	DisplayHidden       bool                  `xml:"displayHidden,attr"`
+	MinAxisType         string                `xml:"minAxisType,attr"`
+	MaxAxisType         string                `xml:"maxAxisType,attr"`
+	RightToLeft         bool                  `xml:"rightToLeft,attr"`
+	ColorSeries         *xlsxTabColor         `xml:"colorSeries"`
+	ColorNegative       *xlsxTabColor         `xml:"colorNegative"`
+	ColorAxis           *xlsxColor            `xml:"colorAxis"`
+	ColorMarkers        *xlsxTabColor         `xml:"colorMarkers"`
+	ColorFirst          *xlsxTabColor         `xml:"colorFirst"`
+	ColorLast           *xlsxTabColor         `xml:"colorLast"`
+	ColorHigh           *xlsxTabColor         `xml:"colorHigh"`
+	ColorLow            *xlsxTabColor         `xml:"colorLow"`
+	F                   string                `xml:"f"`
+	Sparklines          []*decodeX14Sparkline `xml:"sparklines>sparkline"`
+}
+
+// decodeX14Sparkline directly maps the sparkline element.
+type decodeX14Sparkline struct {
+	F     string `xml:"f"`
+	Sqref string `xml:"sqref"`
 }
 
 // xlsxX14SparklineGroups directly maps the sparklineGroups element.
@@ -712,14 +1010,13 @@ type xlsxX14SparklineGroups struct {
 	XMLName         xml.Name                 `xml:"x14:sparklineGroups"`
 	XMLNSXM         string                   `xml:"xmlns:xm,attr"`
 	SparklineGroups []*xlsxX14SparklineGroup `xml:"x14:sparklineGroup"`
-	Content         string                   `xml:",innerxml"`
 }
 
 // xlsxX14SparklineGroup directly maps the sparklineGroup element.
 type xlsxX14SparklineGroup struct {
 	XMLName             xml.Name          `xml:"x14:sparklineGroup"`
-	ManualMax           int               `xml:"manualMax,attr,omitempty"`
-	ManualMin           int               `xml:"manualMin,attr,omitempty"`
+	ManualMax           float64           `xml:"manualMax,attr,omitempty"`
+	ManualMin           float64           `xml:"manualMin,attr,omitempty"`
 	LineWeight          float64           `xml:"lineWeight,attr,omitempty"`
 	Type                string            `xml:"type,attr,omitempty"`
 	DateAxis            bool              `xml:"dateAxis,attr,omitempty"`
@@ -743,6 +1040,7 @@ type xlsxX14SparklineGroup struct {
 	ColorLast           *xlsxTabColor     `xml:"x14:colorLast"`
 	ColorHigh           *xlsxTabColor     `xml:"x14:colorHigh"`
 	ColorLow            *xlsxTabColor     `xml:"x14:colorLow"`
+	F                   string            `xml:"xm:f,omitempty"`
 	Sparklines          xlsxX14Sparklines `xml:"x14:sparklines"`
 }
 
@@ -785,26 +1083,34 @@ type SparklineOption struct {
 	LastColor     string
 	HightColor    string
 	LowColor      string
+	AxisColor     string
 	EmptyCells    string
+	DateRange     string
+}
+
+// Panes directly maps the settings of the panes, which can be used by
+// SetPanes instead of the JSON format string.
+type Panes struct {
+	Freeze      bool            `json:"freeze"`
+	Split       bool            `json:"split"`
+	XSplit      int             `json:"x_split"`
+	YSplit      int             `json:"y_split"`
+	TopLeftCell string          `json:"top_left_cell"`
+	ActivePane  string          `json:"active_pane"`
+	Panes       []PaneSelection `json:"panes"`
 }
 
-// formatPanes directly maps the settings of the panes.
-type formatPanes struct {
-	Freeze      bool   `json:"freeze"`
-	Split       bool   `json:"split"`
-	XSplit      int    `json:"x_split"`
-	YSplit      int    `json:"y_split"`
-	TopLeftCell string `json:"top_left_cell"`
-	ActivePane  string `json:"active_pane"`
-	Panes       []struct {
-		SQRef      string `json:"sqref"`
-		ActiveCell string `json:"active_cell"`
-		Pane       string `json:"pane"`
-	} `json:"panes"`
+// PaneSelection directly maps the settings of the selection in the pane.
+type PaneSelection struct {
+	SQRef      string `json:"sqref"`
+	ActiveCell string `json:"active_cell"`
+	Pane       string `json:"pane"`
 }
 
-// formatConditional directly maps the conditional format settings of the cells.
-type formatConditional struct {
+// ConditionalFormatOption directly maps the conditional format settings of
+// the cells, which can be used by SetConditionalFormat instead of the JSON
+// format string.
+type ConditionalFormatOption struct {
 	Type         string `json:"type"`
 	AboveAverage bool   `json:"above_average"`
 	Percent      bool   `json:"percent"`